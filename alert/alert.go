@@ -0,0 +1,243 @@
+// Package alert turns detections into named alerts with configurable rules
+package alert
+
+import (
+	"bytes"
+	"image"
+
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/alert/alertconfig"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
+	"github.com/snowzach/doods/sink"
+	"github.com/snowzach/doods/zone"
+)
+
+// Engine checks detections against the alert rules and sends the alerts to the sinks
+type Engine struct {
+	rules      []*rule
+	deliveries []*delivery
+	falls      []*fall
+	fires      []*fire
+	zones      *zone.Store
+	sinks      *sink.Manager
+	keys       *server.AuthKeys
+	logger     *zap.SugaredLogger
+}
+
+// New creates the configured alert rules
+func New(zones *zone.Store, sinks *sink.Manager) *Engine {
+
+	e := &Engine{
+		zones:  zones,
+		sinks:  sinks,
+		keys:   server.Keys(),
+		logger: zap.S().With("package", "alert"),
+	}
+
+	// Get the alerts config
+	var ruleConfig []*alertconfig.RuleConfig
+	config.UnmarshalKey("doods.alerts", &ruleConfig)
+
+	for _, c := range ruleConfig {
+		r, err := newRule(c)
+		if err != nil {
+			e.logger.Errorf("Could not configure alert %s: %v", c.Name, err)
+			continue
+		}
+		e.rules = append(e.rules, r)
+		e.logger.Infow("Configured Alert", "name", c.Name, "sources", c.Sources, "zone", c.Zone, "sinks", c.Sinks)
+	}
+
+	// Get the package delivery config
+	var deliveryConfig []*alertconfig.DeliveryConfig
+	config.UnmarshalKey("doods.deliveries", &deliveryConfig)
+
+	for _, c := range deliveryConfig {
+		e.deliveries = append(e.deliveries, newDelivery(c))
+		e.logger.Infow("Configured Delivery", "name", c.Name, "sources", c.Sources, "zone", c.Zone, "sinks", c.Sinks)
+	}
+
+	// Get the fall detection config
+	var fallConfig []*alertconfig.FallConfig
+	config.UnmarshalKey("doods.falls", &fallConfig)
+
+	for _, c := range fallConfig {
+		e.falls = append(e.falls, newFall(c))
+		e.logger.Infow("Configured Fall", "name", c.Name, "sources", c.Sources, "zone", c.Zone, "sinks", c.Sinks)
+	}
+
+	// Get the fire and smoke config
+	var fireConfig []*alertconfig.FireConfig
+	config.UnmarshalKey("doods.fires", &fireConfig)
+
+	for _, c := range fireConfig {
+		e.fires = append(e.fires, newFire(c))
+		e.logger.Infow("Configured Fire", "name", c.Name, "sources", c.Sources, "zone", c.Zone, "sinks", c.Sinks)
+	}
+
+	return e
+
+}
+
+// Process checks the result of a detection against the rules. It should be called for every detection, including
+// ones without results, so the rules know when objects are gone.
+func (e *Engine) Process(event *sink.Event) {
+	if e == nil || (len(e.rules) == 0 && len(e.deliveries) == 0 && len(e.falls) == 0 && len(e.fires) == 0) {
+		return
+	}
+
+	// The zones for the stream or detector
+	zones := e.zones.Get(zone.Target(zone.TargetStreams, event.Source))
+	if zones == nil {
+		zones = e.zones.Get(zone.Target(zone.TargetDetectors, event.Detector))
+	}
+
+	for _, r := range e.rules {
+		if r.sources != nil {
+			if _, ok := r.sources[event.Source]; !ok {
+				continue
+			}
+		}
+
+		matched := r.match(event.Response.Detections, zones)
+		if !r.update(event.Source, event.Time, len(matched) > 0) {
+			continue
+		}
+
+		e.logger.Infow("Alert", "alert", r.config.Name, "id", event.ID, "source", event.Source, "detections", len(matched))
+
+		sinks := r.config.Sinks
+		if len(sinks) == 0 {
+			sinks = event.Sinks
+		}
+		e.sinks.Send(&sink.Event{
+			Time:     event.Time,
+			ID:       event.ID,
+			Source:   event.Source,
+			Detector: event.Detector,
+			Image:    event.Image,
+			Response: &odrpc.DetectResponse{
+				Id:         event.Response.Id,
+				Detections: matched,
+			},
+			Alert: r.config.Name,
+			Sinks: sinks,
+		})
+	}
+	for _, d := range e.deliveries {
+		if d.sources != nil {
+			if _, ok := d.sources[event.Source]; !ok {
+				continue
+			}
+		}
+
+		for _, de := range d.update(event.Source, event.Time, event.Response.Detections, zones) {
+			name := d.config.Name + "-" + de.typ
+			e.logger.Infow("Alert", "alert", name, "id", event.ID, "source", event.Source, "label", de.detection.Label)
+
+			sinks := d.config.Sinks
+			if len(sinks) == 0 {
+				sinks = event.Sinks
+			}
+			e.sinks.Send(&sink.Event{
+				Time:     event.Time,
+				ID:       event.ID,
+				Source:   event.Source,
+				Detector: event.Detector,
+				Image:    event.Image,
+				Response: &odrpc.DetectResponse{
+					Id:         event.Response.Id,
+					Detections: []*odrpc.Detection{de.detection},
+				},
+				Alert: name,
+				Sinks: sinks,
+			})
+		}
+	}
+	// The boxes are compared in pixels
+	var aspect float32
+	for _, f := range e.falls {
+		if f.sources != nil {
+			if _, ok := f.sources[event.Source]; !ok {
+				continue
+			}
+		}
+		if aspect == 0 {
+			aspect = 1
+			if c, _, err := image.DecodeConfig(bytes.NewReader(event.Image)); err == nil && c.Height > 0 {
+				aspect = float32(c.Width) / float32(c.Height)
+			}
+		}
+
+		for _, d := range f.update(event.Source, event.Time, event.Response.Detections, zones, aspect) {
+			e.logger.Warnw("Alert", "alert", f.config.Name, "id", event.ID, "source", event.Source, "label", d.Label)
+
+			sinks := f.config.Sinks
+			if len(sinks) == 0 {
+				sinks = event.Sinks
+			}
+			e.sinks.Send(&sink.Event{
+				Time:     event.Time,
+				ID:       event.ID,
+				Source:   event.Source,
+				Detector: event.Detector,
+				Image:    event.Image,
+				Response: &odrpc.DetectResponse{
+					Id:         event.Response.Id,
+					Detections: []*odrpc.Detection{d},
+				},
+				Alert:    f.config.Name,
+				Priority: f.priority,
+				Sinks:    sinks,
+			})
+		}
+	}
+	// The image is decoded once for the brightness checks
+	var img image.Image
+	var decoded bool
+	decode := func() image.Image {
+		if !decoded {
+			decoded = true
+			var err error
+			if img, _, err = image.Decode(bytes.NewReader(event.Image)); err != nil {
+				e.logger.Warnw("Could not decode image for fire checks", "id", event.ID, "error", err)
+			}
+		}
+		return img
+	}
+	for _, f := range e.fires {
+		if f.sources != nil {
+			if _, ok := f.sources[event.Source]; !ok {
+				continue
+			}
+		}
+
+		detections := f.update(event.Source, event.Time, event.Response.Detections, zones, decode)
+		if len(detections) == 0 {
+			continue
+		}
+		e.logger.Warnw("Alert", "alert", f.config.Name, "id", event.ID, "source", event.Source, "detections", len(detections))
+
+		sinks := f.config.Sinks
+		if len(sinks) == 0 {
+			sinks = event.Sinks
+		}
+		e.sinks.Send(&sink.Event{
+			Time:     event.Time,
+			ID:       event.ID,
+			Source:   event.Source,
+			Detector: event.Detector,
+			Image:    event.Image,
+			Response: &odrpc.DetectResponse{
+				Id:         event.Response.Id,
+				Detections: detections,
+			},
+			Alert:    f.config.Name,
+			Priority: sink.PriorityCritical,
+			Sinks:    sinks,
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package alertconfig
+
+import (
+	"time"
+)
+
+// RuleConfig is used for parsing alert rule configuration from the config file
+type RuleConfig struct {
+	Name string `json:"name"`
+	// Only match detections from these sources (detector or stream names), all if empty
+	Sources []string `json:"sources"`
+	// The labels and minimum scores to match, * matches any label
+	Detect map[string]float32 `json:"detect"`
+	// Only match detections with one of the values of each attribute, like the pet attribute
+	Attributes map[string][]string `json:"attributes"`
+	// Only match detections centered in this named region from the zones
+	Zone string `json:"zone"`
+	// The rule has to match continuously for this long before the alert fires
+	MinDuration time.Duration `json:"min_duration"`
+	// The minimum time before the alert can fire again for a source
+	Cooldown time.Duration `json:"cooldown"`
+	// Only fire during this schedule
+	Schedule *ScheduleConfig `json:"schedule"`
+	// Send the alert to these sinks, all if empty
+	Sinks []string `json:"sinks"`
+}
+
+// ScheduleConfig is a daily time window in HH:MM on the given days, it can wrap past midnight
+type ScheduleConfig struct {
+	// Days of the week (sun, mon, tue...), every day if empty
+	Days  []string `json:"days"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// DeliveryConfig reports packages left in a zone by a person and when they are taken
+type DeliveryConfig struct {
+	// The alerts are <name>-delivered and <name>-taken, default package
+	Name string `json:"name"`
+	// Only track these sources (detector or stream names), all if empty
+	Sources []string `json:"sources"`
+	// Only track objects centered in this named region from the zones, the whole image if empty
+	Zone string `json:"zone"`
+	// The package labels and minimum scores, default package: 50
+	Packages map[string]float32 `json:"packages"`
+	// The labels and minimum scores of who delivers them, default person: 50
+	Carriers map[string]float32 `json:"carriers"`
+	// How long a new package has to stay before it's delivered, default 10s
+	Dwell time.Duration `json:"dwell"`
+	// A carrier has to be seen this long before the package appears or during the dwell time, default 2m
+	Window time.Duration `json:"window"`
+	// How long a delivered package has to be gone before it's taken, default 1m
+	Taken time.Duration `json:"taken"`
+	// The minimum overlap for a detection to be the same package, default 0.3
+	IOU float32 `json:"iou"`
+	// Send the alerts to these sinks, all if empty
+	Sinks []string `json:"sinks"`
+}
+
+// FallConfig reports people that fall and don't move, from the orientation of their boxes
+type FallConfig struct {
+	// The alert name, default fall
+	Name string `json:"name"`
+	// Only track these sources (detector or stream names), all if empty
+	Sources []string `json:"sources"`
+	// Only track people centered in this named region from the zones, the whole image if empty
+	Zone string `json:"zone"`
+	// The labels and minimum scores of people, default person: 50
+	Detect map[string]float32 `json:"detect"`
+	// The width / height of the box of a person lying down, default 1.2
+	Ratio float32 `json:"ratio"`
+	// How fast the person has to go from upright to lying down to be a fall, default 3s
+	Transition time.Duration `json:"transition"`
+	// How long the person has to lie without moving after the fall, default 20s
+	Still time.Duration `json:"still"`
+	// The maximum movement of the center of the box while still as a fraction of the image, default 0.05
+	Movement float32 `json:"movement"`
+	// The minimum time before the alert can fire again for a source, default 5m
+	Cooldown time.Duration `json:"cooldown"`
+	// The alert priority, high (default) or normal
+	Priority string `json:"priority"`
+	// Send the alert to these sinks, all if empty
+	Sinks []string `json:"sinks"`
+}
+
+// FireConfig reports fire and smoke seen in enough recent frames that pass the brightness checks
+type FireConfig struct {
+	// The alert name, default fire
+	Name string `json:"name"`
+	// Only check these sources (detector or stream names), all if empty
+	Sources []string `json:"sources"`
+	// Only check detections centered in this named region from the zones, the whole image if empty
+	Zone string `json:"zone"`
+	// The labels and minimum scores, default fire: 50 and smoke: 50
+	Detect map[string]float32 `json:"detect"`
+	// A label has to be seen in min_frames of the last frames, default 3 of 5
+	MinFrames int32 `json:"min_frames"`
+	Frames    int32 `json:"frames"`
+	// The labels that give off light, default fire and flame
+	Bright []string `json:"bright"`
+	// The bright labels have to be this much brighter than the image around them, default 1.2, negative to disable
+	Contrast float32 `json:"contrast"`
+	// The location for the sunrise and sunset
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Ignore the bright labels entirely in the sky this long before and after sunrise and sunset, default 1h
+	Golden time.Duration `json:"golden"`
+	// The top of the image that is sky as a fraction of its height, default 0.4
+	Sky float32 `json:"sky"`
+	// The minimum time before the alert can fire again for a source, default 10m
+	Cooldown time.Duration `json:"cooldown"`
+	// Send the alert to these sinks, all if empty
+	Sinks []string `json:"sinks"`
+}
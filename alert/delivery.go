@@ -0,0 +1,235 @@
+package alert
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/alert/alertconfig"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/zone"
+)
+
+// Delivery event types
+const (
+	Delivered = "delivered"
+	Taken     = "taken"
+)
+
+// delivery tracks the packages in a zone. A package that appears while a carrier is around and stays for the dwell
+// time is delivered, a delivered package that is gone for the taken time was taken.
+type delivery struct {
+	config  *alertconfig.DeliveryConfig
+	sources map[string]struct{}
+
+	// source -> state
+	states map[string]*deliveryState
+	lock   sync.Mutex
+}
+
+// deliveryState is the packages for one source
+type deliveryState struct {
+	lastCarrier time.Time
+	packages    []*trackedPackage
+	nextID      int
+}
+
+// trackedPackage is a package in the zone
+type trackedPackage struct {
+	ID        int              `json:"id"`
+	Detection *odrpc.Detection `json:"detection"`
+	FirstSeen time.Time        `json:"first_seen"`
+	LastSeen  time.Time        `json:"last_seen"`
+	// A carrier was seen when it appeared
+	Carried   bool       `json:"carried"`
+	Delivered *time.Time `json:"delivered,omitempty"`
+	// It was there when tracking started
+	existing bool
+}
+
+// deliveryEvent is a package that was delivered or taken
+type deliveryEvent struct {
+	typ       string
+	detection *odrpc.Detection
+}
+
+func newDelivery(c *alertconfig.DeliveryConfig) *delivery {
+
+	if c.Name == "" {
+		c.Name = "package"
+	}
+	if len(c.Packages) == 0 {
+		c.Packages = map[string]float32{"package": 50}
+	}
+	if len(c.Carriers) == 0 {
+		c.Carriers = map[string]float32{"person": 50}
+	}
+	if c.Dwell <= 0 {
+		c.Dwell = 10 * time.Second
+	}
+	if c.Window <= 0 {
+		c.Window = 2 * time.Minute
+	}
+	if c.Taken <= 0 {
+		c.Taken = time.Minute
+	}
+	if c.IOU <= 0 {
+		c.IOU = 0.3
+	}
+
+	d := &delivery{
+		config: c,
+		states: make(map[string]*deliveryState),
+	}
+	if len(c.Sources) > 0 {
+		d.sources = make(map[string]struct{})
+		for _, source := range c.Sources {
+			d.sources[source] = struct{}{}
+		}
+	}
+	return d
+
+}
+
+// update records the detections for the source and returns the packages that were delivered or taken
+func (d *delivery) update(source string, now time.Time, detections []*odrpc.Detection, zones *zone.Zones) []*deliveryEvent {
+
+	var region *odrpc.DetectRegion
+	if d.config.Zone != "" {
+		if zones != nil {
+			region = zones.Regions[d.config.Zone]
+		}
+		// The zone doesn't exist for this source
+		if region == nil {
+			return nil
+		}
+	}
+
+	var packages []*odrpc.Detection
+	carrier := false
+	for _, det := range detections {
+		if region != nil {
+			x, y := (det.Left+det.Right)/2, (det.Top+det.Bottom)/2
+			if x < region.Left || x > region.Right || y < region.Top || y > region.Bottom {
+				continue
+			}
+		}
+		if score, ok := d.config.Packages[det.Label]; ok && det.Confidence >= score {
+			packages = append(packages, det)
+		}
+		if score, ok := d.config.Carriers[det.Label]; ok && det.Confidence >= score {
+			carrier = true
+		}
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	s, ok := d.states[source]
+	if !ok {
+		s = new(deliveryState)
+		d.states[source] = s
+	}
+	// The packages already there when tracking starts were delivered before
+	existing := !ok
+	if carrier {
+		s.lastCarrier = now
+	}
+
+	// Match the detections with the tracked packages, the best overlaps first
+	type pair struct {
+		p   *trackedPackage
+		det int
+		iou float32
+	}
+	var pairs []pair
+	for _, p := range s.packages {
+		for i, det := range packages {
+			if overlap := odrpc.IoU(p.Detection, det); overlap >= d.config.IOU {
+				pairs = append(pairs, pair{p, i, overlap})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].iou > pairs[j].iou })
+	seen := make(map[*trackedPackage]struct{})
+	used := make(map[int]struct{})
+	for _, pr := range pairs {
+		if _, ok := seen[pr.p]; ok {
+			continue
+		}
+		if _, ok := used[pr.det]; ok {
+			continue
+		}
+		seen[pr.p] = struct{}{}
+		used[pr.det] = struct{}{}
+		dc := *packages[pr.det]
+		pr.p.Detection = &dc
+		pr.p.LastSeen = now
+		// The carrier can show up on camera after the package, like stepping back from the door
+		if carrier && !pr.p.existing && now.Sub(pr.p.FirstSeen) <= d.config.Dwell {
+			pr.p.Carried = true
+		}
+	}
+
+	// New packages
+	for i, det := range packages {
+		if _, ok := used[i]; ok {
+			continue
+		}
+		dc := *det
+		s.nextID++
+		s.packages = append(s.packages, &trackedPackage{
+			ID:        s.nextID,
+			Detection: &dc,
+			FirstSeen: now,
+			LastSeen:  now,
+			Carried:   !existing && !s.lastCarrier.IsZero() && now.Sub(s.lastCarrier) <= d.config.Window,
+			existing:  existing,
+		})
+	}
+
+	var events []*deliveryEvent
+	kept := s.packages[:0]
+	for _, p := range s.packages {
+		// Gone long enough, a package that wasn't delivered was probably a false detection
+		if now.Sub(p.LastSeen) >= d.config.Taken {
+			if p.Delivered != nil {
+				events = append(events, &deliveryEvent{typ: Taken, detection: p.Detection})
+			}
+			continue
+		}
+		kept = append(kept, p)
+		// Boxes that were there without a carrier, like a planter, are never delivered
+		if p.Delivered == nil && p.Carried && p.LastSeen.Equal(now) && now.Sub(p.FirstSeen) >= d.config.Dwell {
+			delivered := now
+			p.Delivered = &delivered
+			events = append(events, &deliveryEvent{typ: Delivered, detection: p.Detection})
+		}
+	}
+	for i := len(kept); i < len(s.packages); i++ {
+		s.packages[i] = nil
+	}
+	s.packages = kept
+
+	return events
+
+}
+
+// packages returns copies of the tracked packages for each source
+func (d *delivery) packages() map[string][]*trackedPackage {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	ret := make(map[string][]*trackedPackage)
+	for source, s := range d.states {
+		if len(s.packages) == 0 {
+			continue
+		}
+		packages := make([]*trackedPackage, 0, len(s.packages))
+		for _, p := range s.packages {
+			pc := *p
+			packages = append(packages, &pc)
+		}
+		ret[source] = packages
+	}
+	return ret
+}
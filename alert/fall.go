@@ -0,0 +1,231 @@
+package alert
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/alert/alertconfig"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/sink"
+	"github.com/snowzach/doods/zone"
+)
+
+// The furthest a person can move between frames as a fraction of the image, a fall moves the center a lot
+const fallMaxDistance = 0.3
+
+// fall reports people that go from upright to lying down quickly and then don't move. A person is lying down when
+// their box is wider than it is tall.
+type fall struct {
+	config   *alertconfig.FallConfig
+	sources  map[string]struct{}
+	priority string
+	// How long a person can be missed before they are forgotten
+	lost time.Duration
+
+	// source -> state
+	states map[string]*fallState
+	lock   sync.Mutex
+}
+
+// fallState is the people for one source
+type fallState struct {
+	people    []*fallPerson
+	lastFired time.Time
+}
+
+// fallPerson is a person followed between frames
+type fallPerson struct {
+	x, y        float32
+	lastSeen    time.Time
+	lastUpright time.Time
+	// When they were first seen lying down and if they got there fast enough to be a fall
+	lying time.Time
+	fell  bool
+	// Where they have been lying still since
+	stillX, stillY float32
+	stillSince     time.Time
+	fired          bool
+}
+
+func newFall(c *alertconfig.FallConfig) *fall {
+
+	if c.Name == "" {
+		c.Name = "fall"
+	}
+	if len(c.Detect) == 0 {
+		c.Detect = map[string]float32{"person": 50}
+	}
+	if c.Ratio <= 0 {
+		c.Ratio = 1.2
+	}
+	if c.Transition <= 0 {
+		c.Transition = 3 * time.Second
+	}
+	if c.Still <= 0 {
+		c.Still = 20 * time.Second
+	}
+	if c.Movement <= 0 {
+		c.Movement = 0.05
+	}
+	if c.Cooldown <= 0 {
+		c.Cooldown = 5 * time.Minute
+	}
+
+	f := &fall{
+		config:   c,
+		priority: sink.PriorityHigh,
+		// A person on the floor is often missed for a few frames
+		lost:   c.Still + time.Minute,
+		states: make(map[string]*fallState),
+	}
+	if c.Priority == "normal" {
+		f.priority = ""
+	}
+	if len(c.Sources) > 0 {
+		f.sources = make(map[string]struct{})
+		for _, source := range c.Sources {
+			f.sources[source] = struct{}{}
+		}
+	}
+	return f
+
+}
+
+// update records the people in the frame and returns the detections of the people that fell. The aspect is the image
+// width / height so the boxes can be compared in pixels.
+func (f *fall) update(source string, now time.Time, detections []*odrpc.Detection, zones *zone.Zones, aspect float32) []*odrpc.Detection {
+
+	var region *odrpc.DetectRegion
+	if f.config.Zone != "" {
+		if zones != nil {
+			region = zones.Regions[f.config.Zone]
+		}
+		// The zone doesn't exist for this source
+		if region == nil {
+			return nil
+		}
+	}
+
+	var people []*odrpc.Detection
+	for _, d := range detections {
+		score, ok := f.config.Detect[d.Label]
+		if !ok || d.Confidence < score {
+			continue
+		}
+		if region != nil {
+			x, y := (d.Left+d.Right)/2, (d.Top+d.Bottom)/2
+			if x < region.Left || x > region.Right || y < region.Top || y > region.Bottom {
+				continue
+			}
+		}
+		people = append(people, d)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	s, ok := f.states[source]
+	if !ok {
+		s = new(fallState)
+		f.states[source] = s
+	}
+
+	// Match the people with the closest ones from the last frames
+	type pair struct {
+		p        *fallPerson
+		det      int
+		distance float32
+	}
+	var pairs []pair
+	for _, p := range s.people {
+		for i, d := range people {
+			if distance := dist(p.x, p.y, (d.Left+d.Right)/2, (d.Top+d.Bottom)/2); distance <= fallMaxDistance {
+				pairs = append(pairs, pair{p, i, distance})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].distance < pairs[j].distance })
+	matched := make(map[int]*fallPerson)
+	seen := make(map[*fallPerson]struct{})
+	for _, pr := range pairs {
+		if _, ok := seen[pr.p]; ok {
+			continue
+		}
+		if _, ok := matched[pr.det]; ok {
+			continue
+		}
+		seen[pr.p] = struct{}{}
+		matched[pr.det] = pr.p
+	}
+
+	var fell []*odrpc.Detection
+	for i, d := range people {
+		p, ok := matched[i]
+		if !ok {
+			// Someone already lying down when they are first seen didn't fall
+			p = new(fallPerson)
+			s.people = append(s.people, p)
+		}
+		p.x, p.y = (d.Left+d.Right)/2, (d.Top+d.Bottom)/2
+		p.lastSeen = now
+
+		width, height := (d.Right-d.Left)*aspect, d.Bottom-d.Top
+		if height <= 0 || width/height < f.config.Ratio {
+			p.lastUpright = now
+			p.lying = time.Time{}
+			p.fell = false
+			p.fired = false
+			continue
+		}
+
+		if p.lying.IsZero() {
+			p.lying = now
+			p.fell = !p.lastUpright.IsZero() && now.Sub(p.lastUpright) <= f.config.Transition
+			p.stillX, p.stillY, p.stillSince = p.x, p.y, now
+		} else if dist(p.x, p.y, p.stillX, p.stillY) > f.config.Movement {
+			p.stillX, p.stillY, p.stillSince = p.x, p.y, now
+		}
+
+		if p.fell && !p.fired && now.Sub(p.stillSince) >= f.config.Still {
+			p.fired = true
+			if s.lastFired.IsZero() || now.Sub(s.lastFired) >= f.config.Cooldown {
+				s.lastFired = now
+				dc := *d
+				fell = append(fell, &dc)
+			}
+		}
+	}
+
+	kept := s.people[:0]
+	for _, p := range s.people {
+		if now.Sub(p.lastSeen) < f.lost {
+			kept = append(kept, p)
+		}
+	}
+	for i := len(kept); i < len(s.people); i++ {
+		s.people[i] = nil
+	}
+	s.people = kept
+
+	return fell
+
+}
+
+// lastFired returns when the alert last fired for each source
+func (f *fall) lastFired() map[string]time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	ret := make(map[string]time.Time)
+	for source, s := range f.states {
+		if !s.lastFired.IsZero() {
+			ret[source] = s.lastFired
+		}
+	}
+	return ret
+}
+
+func dist(x1, y1, x2, y2 float32) float32 {
+	return float32(math.Hypot(float64(x1-x2), float64(y1-y2)))
+}
@@ -0,0 +1,215 @@
+package alert
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/alert/alertconfig"
+	"github.com/snowzach/doods/detector/confirm"
+	"github.com/snowzach/doods/detector/sun"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/zone"
+)
+
+// The number of pixels sampled in each direction for the brightness
+const fireSamples = 64
+
+// fire reports fire and smoke seen in enough of the recent frames. The bright labels (fire) also have to be brighter
+// than the rest of the image and not only in the sky around sunset, the usual false positives.
+type fire struct {
+	config  *alertconfig.FireConfig
+	sources map[string]struct{}
+	bright  map[string]struct{}
+	confirm *confirm.Confirmer
+	opts    *odrpc.Confirm
+
+	// source -> when it last fired
+	fired map[string]time.Time
+	lock  sync.Mutex
+}
+
+func newFire(c *alertconfig.FireConfig) *fire {
+
+	if c.Name == "" {
+		c.Name = "fire"
+	}
+	if len(c.Detect) == 0 {
+		c.Detect = map[string]float32{"fire": 50, "smoke": 50}
+	}
+	if c.MinFrames <= 0 {
+		c.MinFrames = 3
+	}
+	if c.Frames < c.MinFrames {
+		c.Frames = c.MinFrames + 2
+	}
+	if len(c.Bright) == 0 {
+		c.Bright = []string{"fire", "flame"}
+	}
+	if c.Contrast < 0 {
+		c.Contrast = 0
+	} else if c.Contrast == 0 {
+		c.Contrast = 1.2
+	}
+	if c.Golden <= 0 {
+		c.Golden = time.Hour
+	}
+	if c.Sky <= 0 {
+		c.Sky = 0.4
+	}
+	if c.Cooldown <= 0 {
+		c.Cooldown = 10 * time.Minute
+	}
+
+	f := &fire{
+		config:  c,
+		bright:  make(map[string]struct{}),
+		confirm: confirm.New(),
+		opts:    &odrpc.Confirm{MinFrames: c.MinFrames, Frames: c.Frames},
+		fired:   make(map[string]time.Time),
+	}
+	for _, label := range c.Bright {
+		f.bright[label] = struct{}{}
+	}
+	if len(c.Sources) > 0 {
+		f.sources = make(map[string]struct{})
+		for _, source := range c.Sources {
+			f.sources[source] = struct{}{}
+		}
+	}
+	return f
+
+}
+
+// update records the frame and returns the confirmed fire and smoke detections if the alert should fire. The image is
+// only decoded if there are bright detections to check.
+func (f *fire) update(source string, now time.Time, detections []*odrpc.Detection, zones *zone.Zones, decode func() image.Image) []*odrpc.Detection {
+
+	var region *odrpc.DetectRegion
+	if f.config.Zone != "" {
+		if zones != nil {
+			region = zones.Regions[f.config.Zone]
+		}
+		// The zone doesn't exist for this source
+		if region == nil {
+			return nil
+		}
+	}
+
+	golden := f.golden(now)
+	var img image.Image
+	var decoded bool
+
+	var candidates []*odrpc.Detection
+	for _, d := range detections {
+		score, ok := f.config.Detect[d.Label]
+		if !ok || d.Confidence < score {
+			continue
+		}
+		if region != nil {
+			x, y := (d.Left+d.Right)/2, (d.Top+d.Bottom)/2
+			if x < region.Left || x > region.Right || y < region.Top || y > region.Bottom {
+				continue
+			}
+		}
+		if _, ok := f.bright[d.Label]; ok {
+			// The setting sun and the glowing sky, a fire that reaches below the sky band is kept
+			if golden && d.Bottom < f.config.Sky {
+				continue
+			}
+			if f.config.Contrast > 0 && !decoded {
+				decoded = true
+				// Without the image the detection is kept, a missed fire is worse
+				img = decode()
+			}
+			if f.config.Contrast > 0 && img != nil {
+				b := img.Bounds()
+				area := image.Rect(
+					b.Min.X+int(d.Left*float32(b.Dx())), b.Min.Y+int(d.Top*float32(b.Dy())),
+					b.Min.X+int(d.Right*float32(b.Dx())), b.Min.Y+int(d.Bottom*float32(b.Dy())),
+				).Intersect(b)
+				// Orange light over the whole scene isn't a fire. A fire that fills the frame has nothing to compare with.
+				if area.Empty() {
+					continue
+				}
+				if around, ok := meanLuma(img, b, area); ok {
+					if inside, _ := meanLuma(img, area, image.Rectangle{}); inside < around*float64(f.config.Contrast) {
+						continue
+					}
+				}
+			}
+		}
+		dc := *d
+		candidates = append(candidates, &dc)
+	}
+
+	// Every frame is recorded so the labels expire
+	confirmed := f.confirm.Apply(source, f.opts, candidates)
+	if len(confirmed) == 0 {
+		return nil
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if last, ok := f.fired[source]; ok && now.Sub(last) < f.config.Cooldown {
+		return nil
+	}
+	f.fired[source] = now
+	return confirmed
+
+}
+
+// golden returns true if it's around sunrise or sunset at the location
+func (f *fire) golden(now time.Time) bool {
+	if f.config.Latitude == 0 && f.config.Longitude == 0 {
+		return false
+	}
+	rise, set, _ := sun.Times(now, f.config.Latitude, f.config.Longitude)
+	if rise.IsZero() {
+		return false
+	}
+	near := func(t time.Time) bool {
+		d := now.Sub(t)
+		return d > -f.config.Golden && d < f.config.Golden
+	}
+	return near(rise) || near(set)
+}
+
+// lastFired returns when the alert last fired for each source
+func (f *fire) lastFired() map[string]time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	ret := make(map[string]time.Time, len(f.fired))
+	for source, t := range f.fired {
+		ret[source] = t
+	}
+	return ret
+}
+
+// meanLuma returns the average brightness (0-255) of a grid of pixels in the area that aren't in the excluded area. It
+// returns false if there are no pixels to sample.
+func meanLuma(img image.Image, area image.Rectangle, exclude image.Rectangle) (float64, bool) {
+	stepX, stepY := area.Dx()/fireSamples, area.Dy()/fireSamples
+	if stepX < 1 {
+		stepX = 1
+	}
+	if stepY < 1 {
+		stepY = 1
+	}
+	var sum float64
+	var count int
+	for y := area.Min.Y; y < area.Max.Y; y += stepY {
+		for x := area.Min.X; x < area.Max.X; x += stepX {
+			if (image.Point{X: x, Y: y}).In(exclude) {
+				continue
+			}
+			r, g, b, _ := img.At(x, y).RGBA()
+			sum += (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/snowzach/doods/alert/alertconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// fireImage is a dark image with a bright area
+func fireImage(bright image.Rectangle) image.Image {
+	img := image.NewGray(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			v := uint8(40)
+			if (image.Point{X: x, Y: y}).In(bright) {
+				v = 220
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func TestFireContrast(t *testing.T) {
+
+	for _, test := range []struct {
+		name   string
+		img    image.Image
+		box    *odrpc.Detection
+		expect bool
+	}{
+		{"small fire", fireImage(image.Rect(40, 40, 60, 60)), &odrpc.Detection{Top: 0.4, Left: 0.4, Bottom: 0.6, Right: 0.6}, true},
+		{"fire filling most of the frame", fireImage(image.Rect(5, 5, 95, 95)), &odrpc.Detection{Top: 0.05, Left: 0.05, Bottom: 0.95, Right: 0.95}, true},
+		{"fire filling the frame", fireImage(image.Rect(0, 0, 100, 100)), &odrpc.Detection{Bottom: 1, Right: 1}, true},
+		{"orange scene", fireImage(image.Rect(0, 0, 100, 100)), &odrpc.Detection{Top: 0.4, Left: 0.4, Bottom: 0.6, Right: 0.6}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFire(&alertconfig.FireConfig{MinFrames: 1, Frames: 1})
+			test.box.Label, test.box.Confidence = "fire", 90
+			fired := f.update("cam", time.Now(), []*odrpc.Detection{test.box}, nil, func() image.Image { return test.img })
+			if (len(fired) > 0) != test.expect {
+				t.Errorf("fired %v, expected %v", len(fired) > 0, test.expect)
+			}
+		})
+	}
+
+}
+
+func TestFireSky(t *testing.T) {
+
+	// Denver at sunset
+	now := time.Date(2020, 6, 21, 20, 30, 0, 0, time.FixedZone("MDT", -6*60*60))
+	img := fireImage(image.Rect(0, 0, 100, 100))
+	decode := func() image.Image { return img }
+
+	for _, test := range []struct {
+		name   string
+		box    *odrpc.Detection
+		expect bool
+	}{
+		{"sunset", &odrpc.Detection{Top: 0.05, Left: 0.1, Bottom: 0.3, Right: 0.3}, false},
+		{"house on fire", &odrpc.Detection{Top: 0.2, Left: 0.1, Bottom: 0.7, Right: 0.5}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFire(&alertconfig.FireConfig{MinFrames: 1, Frames: 1, Contrast: -1, Latitude: 39.74, Longitude: -104.99})
+			if !f.golden(now) {
+				t.Fatal("expected golden hour")
+			}
+			test.box.Label, test.box.Confidence = "fire", 90
+			fired := f.update("cam", now, []*odrpc.Detection{test.box}, nil, decode)
+			if (len(fired) > 0) != test.expect {
+				t.Errorf("fired %v, expected %v", len(fired) > 0, test.expect)
+			}
+		})
+	}
+
+}
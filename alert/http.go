@@ -0,0 +1,86 @@
+package alert
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/server"
+)
+
+// RegisterHTTP registers the alert endpoints on the router
+func (e *Engine) RegisterHTTP(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(e.keys))
+		r.Get("/alerts", e.handleAlerts)
+		r.Get("/deliveries", e.handleDeliveries)
+	})
+}
+
+type alertInfo struct {
+	Name      string               `json:"name"`
+	Sources   []string             `json:"sources,omitempty"`
+	Zone      string               `json:"zone,omitempty"`
+	Detect    map[string]float32   `json:"detect"`
+	Sinks     []string             `json:"sinks,omitempty"`
+	LastFired map[string]time.Time `json:"last_fired"`
+}
+
+// handleAlerts returns the alert rules and when they last fired for each source
+func (e *Engine) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts := make([]*alertInfo, 0, len(e.rules))
+	for _, rule := range e.rules {
+		alerts = append(alerts, &alertInfo{
+			Name:      rule.config.Name,
+			Sources:   rule.config.Sources,
+			Zone:      rule.config.Zone,
+			Detect:    rule.config.Detect,
+			Sinks:     rule.config.Sinks,
+			LastFired: rule.lastFired(),
+		})
+	}
+	for _, f := range e.falls {
+		alerts = append(alerts, &alertInfo{
+			Name:      f.config.Name,
+			Sources:   f.config.Sources,
+			Zone:      f.config.Zone,
+			Detect:    f.config.Detect,
+			Sinks:     f.config.Sinks,
+			LastFired: f.lastFired(),
+		})
+	}
+	for _, f := range e.fires {
+		alerts = append(alerts, &alertInfo{
+			Name:      f.config.Name,
+			Sources:   f.config.Sources,
+			Zone:      f.config.Zone,
+			Detect:    f.config.Detect,
+			Sinks:     f.config.Sinks,
+			LastFired: f.lastFired(),
+		})
+	}
+	render.JSON(w, r, map[string]interface{}{"alerts": alerts})
+}
+
+type deliveryInfo struct {
+	Name     string                       `json:"name"`
+	Sources  []string                     `json:"sources,omitempty"`
+	Zone     string                       `json:"zone,omitempty"`
+	Packages map[string][]*trackedPackage `json:"packages"`
+}
+
+// handleDeliveries returns the packages tracked for each source
+func (e *Engine) handleDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries := make([]*deliveryInfo, 0, len(e.deliveries))
+	for _, d := range e.deliveries {
+		deliveries = append(deliveries, &deliveryInfo{
+			Name:     d.config.Name,
+			Sources:  d.config.Sources,
+			Zone:     d.config.Zone,
+			Packages: d.packages(),
+		})
+	}
+	render.JSON(w, r, map[string]interface{}{"deliveries": deliveries})
+}
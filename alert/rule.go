@@ -0,0 +1,242 @@
+package alert
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/alert/alertconfig"
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/zone"
+)
+
+// rule turns matching detections into a named alert
+type rule struct {
+	config   *alertconfig.RuleConfig
+	sources  map[string]struct{}
+	schedule *schedule
+
+	// source -> state
+	states map[string]*ruleState
+	lock   sync.Mutex
+}
+
+// ruleState tracks a rule for one source
+type ruleState struct {
+	since     time.Time // when the current match started
+	fired     bool      // fired for the current match
+	lastFired time.Time
+}
+
+func newRule(c *alertconfig.RuleConfig) (*rule, error) {
+
+	if c.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if len(c.Detect) == 0 {
+		return nil, fmt.Errorf("detect is required")
+	}
+
+	r := &rule{
+		config: c,
+		states: make(map[string]*ruleState),
+	}
+
+	if len(c.Sources) > 0 {
+		r.sources = make(map[string]struct{})
+		for _, source := range c.Sources {
+			r.sources[source] = struct{}{}
+		}
+	}
+
+	if c.Schedule != nil {
+		var err error
+		if r.schedule, err = newSchedule(c.Schedule); err != nil {
+			return nil, fmt.Errorf("invalid schedule: %v", err)
+		}
+	}
+
+	return r, nil
+
+}
+
+// match returns the detections matching the rule
+func (r *rule) match(detections []*odrpc.Detection, zones *zone.Zones) []*odrpc.Detection {
+
+	var region *odrpc.DetectRegion
+	if r.config.Zone != "" {
+		if zones != nil {
+			region = zones.Regions[r.config.Zone]
+		}
+		// The zone doesn't exist for this source
+		if region == nil {
+			return nil
+		}
+	}
+
+	var matched []*odrpc.Detection
+	for _, d := range detections {
+		score, ok := r.config.Detect[d.Label]
+		if !ok {
+			score, ok = r.config.Detect["*"]
+		}
+		if !ok || d.Confidence < score {
+			continue
+		}
+		if !r.matchAttributes(d) {
+			continue
+		}
+		if region != nil {
+			x, y := (d.Left+d.Right)/2, (d.Top+d.Bottom)/2
+			if x < region.Left || x > region.Right || y < region.Top || y > region.Bottom {
+				continue
+			}
+		}
+		// Copy it so later changes to the response (translation) don't affect the alert
+		dc := *d
+		matched = append(matched, &dc)
+	}
+	return matched
+
+}
+
+// matchAttributes returns true if the detection has one of the values of each attribute of the rule
+func (r *rule) matchAttributes(d *odrpc.Detection) bool {
+	for name, values := range r.config.Attributes {
+		found := false
+		for _, a := range d.Attributes {
+			if a.Name != name {
+				continue
+			}
+			for _, value := range values {
+				if value == "*" || a.Value == value {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// update records if the rule matched for the source and returns true if the alert should fire
+func (r *rule) update(source string, now time.Time, matched bool) bool {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s, ok := r.states[source]
+	if !ok {
+		s = new(ruleState)
+		r.states[source] = s
+	}
+
+	if !matched {
+		s.since = time.Time{}
+		s.fired = false
+		return false
+	}
+
+	if s.since.IsZero() {
+		s.since = now
+	}
+
+	if s.fired || now.Sub(s.since) < r.config.MinDuration {
+		return false
+	}
+	if !s.lastFired.IsZero() && now.Sub(s.lastFired) < r.config.Cooldown {
+		return false
+	}
+	if r.schedule != nil && !r.schedule.active(now) {
+		return false
+	}
+
+	s.fired = true
+	s.lastFired = now
+	return true
+
+}
+
+// lastFired returns when the alert last fired for each source
+func (r *rule) lastFired() map[string]time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ret := make(map[string]time.Time)
+	for source, s := range r.states {
+		if !s.lastFired.IsZero() {
+			ret[source] = s.lastFired
+		}
+	}
+	return ret
+}
+
+// schedule is a daily time window on some days of the week
+type schedule struct {
+	days  map[time.Weekday]struct{}
+	start time.Duration // Since midnight
+	end   time.Duration
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func newSchedule(c *alertconfig.ScheduleConfig) (*schedule, error) {
+
+	s := new(schedule)
+
+	if len(c.Days) > 0 {
+		s.days = make(map[time.Weekday]struct{})
+		for _, day := range c.Days {
+			day = strings.ToLower(day)
+			if len(day) > 3 {
+				day = day[:3]
+			}
+			d, ok := weekdays[day]
+			if !ok {
+				return nil, fmt.Errorf("unknown day: %s", day)
+			}
+			s.days[d] = struct{}{}
+		}
+	}
+
+	if c.Start != "" || c.End != "" {
+		var err error
+		if s.start, err = conf.ParseTimeOfDay(c.Start); err != nil {
+			return nil, fmt.Errorf("invalid start: %v", err)
+		}
+		if s.end, err = conf.ParseTimeOfDay(c.End); err != nil {
+			return nil, fmt.Errorf("invalid end: %v", err)
+		}
+	}
+
+	return s, nil
+
+}
+
+// active returns true if t is in the schedule
+func (s *schedule) active(t time.Time) bool {
+	if s.days != nil {
+		if _, ok := s.days[t.Weekday()]; !ok {
+			return false
+		}
+	}
+	if s.start == s.end {
+		return true
+	}
+	tod := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if s.start < s.end {
+		return tod >= s.start && tod < s.end
+	}
+	return tod >= s.start || tod < s.end
+}
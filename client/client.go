@@ -0,0 +1,208 @@
+// Package client is a Go client for the doods gRPC API with helpers for sending images, retries and streaming.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	emptypb "github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// Options are the client options
+type Options struct {
+	// The auth key if the server requires one
+	AuthKey string
+	// Connect with TLS, InsecureSkipVerify for self signed certificates
+	TLS                bool
+	InsecureSkipVerify bool
+	// Retry requests that failed because the server was unavailable or overloaded
+	Retries int
+	// The wait before the first retry, doubled for each retry, default 200ms
+	Backoff time.Duration
+	// The timeout of each request, default none
+	Timeout time.Duration
+	// Extra dial options
+	DialOptions []grpc.DialOption
+}
+
+// Client is a doods client
+type Client struct {
+	conn    *grpc.ClientConn
+	rpc     odrpc.OdrpcClient
+	options Options
+}
+
+// authKey passes the auth key with every request
+type authKey string
+
+func (a authKey) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{odrpc.DoodsAuthKeyHeader: string(a)}, nil
+}
+
+func (a authKey) RequireTransportSecurity() bool {
+	return false
+}
+
+// New connects to the doods server at address (host:port)
+func New(ctx context.Context, address string, options *Options) (*Client, error) {
+
+	c := &Client{}
+	if options != nil {
+		c.options = *options
+	}
+	if c.options.Backoff <= 0 {
+		c.options.Backoff = 200 * time.Millisecond
+	}
+
+	dialOptions := append([]grpc.DialOption{}, c.options.DialOptions...)
+	if c.options.TLS {
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: c.options.InsecureSkipVerify})))
+	} else {
+		dialOptions = append(dialOptions, grpc.WithInsecure())
+	}
+	if c.options.AuthKey != "" {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(authKey(c.options.AuthKey)))
+	}
+
+	var err error
+	c.conn, err = grpc.DialContext(ctx, address, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to %s: %v", address, err)
+	}
+	c.rpc = odrpc.NewOdrpcClient(c.conn)
+
+	return c, nil
+
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// RPC returns the generated client for anything the helpers don't cover
+func (c *Client) RPC() odrpc.OdrpcClient {
+	return c.rpc
+}
+
+// Detectors returns the detectors configured on the server
+func (c *Client) Detectors(ctx context.Context) ([]*odrpc.Detector, error) {
+	var response *odrpc.GetDetectorsResponse
+	err := c.retry(ctx, func(ctx context.Context) error {
+		var err error
+		response, err = c.rpc.GetDetectors(ctx, &emptypb.Empty{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.Detectors, nil
+}
+
+// Detect runs the detection request, retrying if the server is unavailable or overloaded
+func (c *Client) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+	var response *odrpc.DetectResponse
+	err := c.retry(ctx, func(ctx context.Context) error {
+		var err error
+		response, err = c.rpc.Detect(ctx, request)
+		return err
+	})
+	return response, err
+}
+
+// DetectImage detects objects in the image data (jpg, png, bmp or ppm) with at least the confidence for each
+// label (* for any), all the detections if detect is empty
+func (c *Client) DetectImage(ctx context.Context, detector string, data []byte, detect map[string]float32) (*odrpc.DetectResponse, error) {
+	return c.Detect(ctx, &odrpc.DetectRequest{
+		DetectorName: detector,
+		Data:         data,
+		Detect:       detect,
+	})
+}
+
+// DetectFile detects objects in a local image file, it's sent to the server
+func (c *Client) DetectFile(ctx context.Context, detector string, filename string, detect map[string]float32) (*odrpc.DetectResponse, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", filename, err)
+	}
+	return c.DetectImage(ctx, detector, data, detect)
+}
+
+// DetectURL has the server fetch the image, the host must be allowed by doods.fetch.allowed_hosts
+func (c *Client) DetectURL(ctx context.Context, detector string, url string, detect map[string]float32) (*odrpc.DetectResponse, error) {
+	return c.Detect(ctx, &odrpc.DetectRequest{
+		DetectorName: detector,
+		ImageUrl:     url,
+		Detect:       detect,
+	})
+}
+
+// DetectCascade detects and calls fn with each result as the cascade stages finish.
+// It returns when the last result is received or fn returns an error.
+func (c *Client) DetectCascade(ctx context.Context, request *odrpc.DetectRequest, fn func(*odrpc.CascadeResult) error) error {
+	stream, err := c.rpc.DetectCascade(ctx, request)
+	if err != nil {
+		return err
+	}
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err = fn(result); err != nil {
+			return err
+		}
+		if result.Done {
+			return nil
+		}
+	}
+}
+
+// retry calls fn until it succeeds, the error isn't temporary or the retries are used up
+func (c *Client) retry(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	backoff := c.options.Backoff
+	for attempt := 0; ; attempt++ {
+		callCtx, cancel := ctx, context.CancelFunc(func() {})
+		if c.options.Timeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, c.options.Timeout)
+		}
+		err := fn(callCtx)
+		cancel()
+		if err == nil || attempt >= c.options.Retries || !Temporary(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+
+}
+
+// Temporary returns true if the request failed because the server was unavailable or overloaded and can be retried
+func Temporary(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
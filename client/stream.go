@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// Stream sends detection requests on one stream and receives the responses as they finish.
+// The responses may be out of order, match them with the request id.
+type Stream struct {
+	stream odrpc.Odrpc_DetectStreamClient
+	send   sync.Mutex
+	cancel context.CancelFunc
+}
+
+// Stream opens a detection stream
+func (c *Client) Stream(ctx context.Context) (*Stream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := c.rpc.DetectStream(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &Stream{
+		stream: stream,
+		cancel: cancel,
+	}, nil
+}
+
+// Send sends a request, it's safe to call from multiple goroutines
+func (s *Stream) Send(request *odrpc.DetectRequest) error {
+	s.send.Lock()
+	defer s.send.Unlock()
+	return s.stream.Send(request)
+}
+
+// Recv waits for the next response. A response with an error isn't fatal to the stream, if it has RetryAfterMs the
+// detector is overloaded and requests should be slowed down.
+func (s *Stream) Recv() (*odrpc.DetectResponse, error) {
+	return s.stream.Recv()
+}
+
+// RetryAfter returns how long to wait before sending more requests after the response, 0 if not overloaded
+func RetryAfter(response *odrpc.DetectResponse) time.Duration {
+	return time.Duration(response.RetryAfterMs) * time.Millisecond
+}
+
+// Close stops sending and closes the stream
+func (s *Stream) Close() error {
+	s.send.Lock()
+	err := s.stream.CloseSend()
+	s.send.Unlock()
+	s.cancel()
+	if err != nil {
+		return fmt.Errorf("could not close stream: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,372 @@
+// Package cluster lets doods nodes find each other with gossip and forward detections for detectors that aren't on
+// the node. There is no leader, each node regularly swaps what it knows about the members with a few random members.
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/client"
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
+)
+
+// ForwardedHeader marks a request forwarded by another node so it isn't forwarded again
+const ForwardedHeader = "doods-forwarded"
+
+// Member is a node and its detectors as it's gossiped
+type Member struct {
+	// The host:port of the node's api
+	Address string `json:"address"`
+	// When the node started (unix nanoseconds), a restarted node is newer even though its heartbeat starts over
+	Incarnation int64 `json:"incarnation"`
+	// Increased by the node each round, a member is dead when it stops going up
+	Heartbeat uint64            `json:"heartbeat"`
+	Detectors []*odrpc.Detector `json:"detectors"`
+}
+
+type member struct {
+	Member
+	// When the heartbeat last went up
+	updated time.Time
+	client  *client.Client
+}
+
+// Cluster is the membership of this node
+type Cluster struct {
+	self      string
+	seeds     []string
+	local     func() []*odrpc.Detector
+	interval  time.Duration
+	deadAfter time.Duration
+	fanout    int
+	authKey   string
+	tls       bool
+	insecure  bool
+
+	incarnation int64
+	heartbeat   uint64
+	members     map[string]*member
+	lock        sync.RWMutex
+
+	http   *http.Client
+	keys   *server.AuthKeys
+	lc     *conf.Lifecycle
+	logger *zap.SugaredLogger
+}
+
+// New starts gossiping with the seeds if doods.cluster.enabled is set, local returns the detectors on this node
+func New(lc *conf.Lifecycle, local func() []*odrpc.Detector) *Cluster {
+
+	if !config.GetBool("doods.cluster.enabled") {
+		return nil
+	}
+
+	c := &Cluster{
+		self:        config.GetString("doods.cluster.advertise"),
+		seeds:       config.GetStringSlice("doods.cluster.seeds"),
+		local:       local,
+		interval:    config.GetDuration("doods.cluster.interval"),
+		deadAfter:   config.GetDuration("doods.cluster.dead_after"),
+		fanout:      config.GetInt("doods.cluster.fanout"),
+		authKey:     config.GetString("doods.cluster.auth_key"),
+		tls:         config.GetBool("doods.cluster.tls"),
+		insecure:    config.GetBool("doods.cluster.insecure_skip_verify"),
+		members:     make(map[string]*member),
+		incarnation: time.Now().UnixNano(),
+		keys:        server.Keys(),
+		lc:          lc,
+		logger:      zap.S().With("package", "cluster"),
+	}
+	if c.self == "" {
+		c.logger.Fatalw("doods.cluster.advertise is required, it's the host:port other nodes reach this node on")
+	}
+	if c.interval <= 0 {
+		c.interval = time.Second
+	}
+	if c.deadAfter <= c.interval {
+		c.deadAfter = 10 * c.interval
+	}
+	if c.fanout <= 0 {
+		c.fanout = 1
+	}
+	c.http = &http.Client{
+		Timeout:   c.interval * 2,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: c.insecure}},
+	}
+
+	lc.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				c.lock.Lock()
+				for _, m := range c.members {
+					if m.client != nil {
+						m.client.Close()
+					}
+				}
+				c.lock.Unlock()
+				return
+			case <-ticker.C:
+				c.round(ctx)
+			}
+		}
+	})
+
+	c.logger.Infow("Cluster started", "advertise", c.self, "seeds", c.seeds)
+
+	return c
+
+}
+
+// round sends what this node knows to a few random live members, or the seeds when it knows none
+func (c *Cluster) round(ctx context.Context) {
+
+	c.lock.Lock()
+	c.heartbeat++
+	now := time.Now()
+	var targets []string
+	for address, m := range c.members {
+		switch {
+		case now.Sub(m.updated) < c.deadAfter:
+			targets = append(targets, address)
+		case now.Sub(m.updated) > 3*c.deadAfter:
+			// Forget it, it's gossiped again if it comes back
+			if m.client != nil {
+				m.client.Close()
+			}
+			delete(c.members, address)
+			c.logger.Infow("Member left", "address", address)
+		}
+	}
+	c.lock.Unlock()
+
+	if len(targets) == 0 {
+		targets = append(targets, c.seeds...)
+	}
+	rand.Shuffle(len(targets), func(i, j int) { targets[i], targets[j] = targets[j], targets[i] })
+	if len(targets) > c.fanout {
+		targets = targets[:c.fanout]
+	}
+
+	state := c.state()
+	for _, address := range targets {
+		if address == c.self {
+			continue
+		}
+		members, err := c.gossip(ctx, address, state)
+		if err != nil {
+			c.logger.Debugw("Could not gossip", "address", address, "error", err)
+			continue
+		}
+		c.merge(members)
+	}
+
+}
+
+// gossip sends the state to a member and returns its state
+func (c *Cluster) gossip(ctx context.Context, address string, state []*Member) ([]*Member, error) {
+
+	body, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	scheme := "http"
+	if c.tls {
+		scheme = "https"
+	}
+	req, err := http.NewRequest(http.MethodPost, scheme+"://"+address+"/cluster/gossip", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if c.authKey != "" {
+		req.Header.Set(odrpc.DoodsAuthKeyHeader, c.authKey)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+	var members []*Member
+	if err := json.NewDecoder(resp.Body).Decode(&members); err != nil {
+		return nil, err
+	}
+	return members, nil
+
+}
+
+// state returns this node and the live members
+func (c *Cluster) state() []*Member {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	ret := []*Member{{Address: c.self, Incarnation: c.incarnation, Heartbeat: c.heartbeat, Detectors: c.local()}}
+	now := time.Now()
+	for _, m := range c.members {
+		if now.Sub(m.updated) < c.deadAfter {
+			member := m.Member
+			ret = append(ret, &member)
+		}
+	}
+	return ret
+}
+
+// merge keeps the newest state of each member
+func (c *Cluster) merge(members []*Member) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	for _, m := range members {
+		if m == nil || m.Address == "" || m.Address == c.self {
+			continue
+		}
+		existing, ok := c.members[m.Address]
+		if !ok {
+			existing = &member{}
+			c.members[m.Address] = existing
+			c.logger.Infow("Member joined", "address", m.Address, "detectors", len(m.Detectors))
+		} else if !m.newer(&existing.Member) {
+			continue
+		} else if m.Incarnation > existing.Incarnation {
+			c.logger.Infow("Member restarted", "address", m.Address, "detectors", len(m.Detectors))
+		} else if now.Sub(existing.updated) >= c.deadAfter {
+			c.logger.Infow("Member is back", "address", m.Address)
+		}
+		existing.Member = *m
+		existing.updated = now
+	}
+}
+
+// newer returns true if the member state is newer than the other state of the same node
+func (m *Member) newer(other *Member) bool {
+	if m.Incarnation != other.Incarnation {
+		return m.Incarnation > other.Incarnation
+	}
+	return m.Heartbeat > other.Heartbeat
+}
+
+// Members returns the live members sorted by address
+func (c *Cluster) Members() []*Member {
+	members := c.state()[1:]
+	sort.Slice(members, func(i, j int) bool { return members[i].Address < members[j].Address })
+	return members
+}
+
+// Detectors returns the detectors on the other members that aren't on this node
+func (c *Cluster) Detectors() []*odrpc.Detector {
+	local := make(map[string]struct{})
+	for _, d := range c.local() {
+		local[d.Name] = struct{}{}
+	}
+	var ret []*odrpc.Detector
+	for _, m := range c.Members() {
+		for _, d := range m.Detectors {
+			if _, ok := local[d.Name]; !ok {
+				local[d.Name] = struct{}{}
+				ret = append(ret, d)
+			}
+		}
+	}
+	return ret
+}
+
+// Forwarded returns true if the request was forwarded by another node
+func Forwarded(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(md.Get(ForwardedHeader)) > 0
+}
+
+// Detect forwards the request to a member with the detector. Each detector goes to the same member while it's live
+// (highest random weight hashing) and the others are tried in order when it's unavailable.
+func (c *Cluster) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+
+	ctx = metadata.AppendToOutgoingContext(ctx, ForwardedHeader, c.self)
+
+	var err error
+	for _, m := range c.membersWith(request.DetectorName) {
+		var cl *client.Client
+		if cl, err = c.client(m); err != nil {
+			continue
+		}
+		var response *odrpc.DetectResponse
+		if response, err = cl.Detect(ctx, request); err == nil {
+			return response, nil
+		}
+		if !client.Temporary(err) {
+			return nil, err
+		}
+		c.logger.Warnw("Member unavailable", "id", request.Id, "address", m.Address, "error", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "no member available: %v", err)
+	}
+	return nil, status.Errorf(codes.NotFound, "not found")
+
+}
+
+// membersWith returns the live members with the detector in the order they are tried
+func (c *Cluster) membersWith(name string) []*member {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	now := time.Now()
+	var ret []*member
+	for _, m := range c.members {
+		if now.Sub(m.updated) >= c.deadAfter {
+			continue
+		}
+		for _, d := range m.Detectors {
+			if d.Name == name {
+				ret = append(ret, m)
+				break
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return weight(name, ret[i].Address) > weight(name, ret[j].Address) })
+	return ret
+}
+
+func weight(name string, address string) uint64 {
+	sum := sha256.Sum256([]byte(name + "/" + address))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// client returns the connection to the member
+func (c *Cluster) client(m *member) (*client.Client, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if m.client == nil {
+		var err error
+		m.client, err = client.New(c.lc.Context(), m.Address, &client.Options{
+			AuthKey:            c.authKey,
+			TLS:                c.tls,
+			InsecureSkipVerify: c.insecure,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m.client, nil
+}
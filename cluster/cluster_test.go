@@ -0,0 +1,48 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+func TestMerge(t *testing.T) {
+
+	c := &Cluster{
+		self:      "a:8080",
+		deadAfter: time.Second,
+		members:   make(map[string]*member),
+		logger:    zap.NewNop().Sugar(),
+	}
+
+	c.merge([]*Member{
+		{Address: "a:8080", Heartbeat: 100},
+		{Address: "b:8080", Incarnation: 1, Heartbeat: 10, Detectors: []*odrpc.Detector{{Name: "old"}}},
+	})
+	if _, ok := c.members["a:8080"]; ok {
+		t.Fatal("merged itself as a member")
+	}
+
+	// Old gossip is ignored
+	c.merge([]*Member{{Address: "b:8080", Incarnation: 1, Heartbeat: 9}})
+	if b := c.members["b:8080"]; b.Heartbeat != 10 {
+		t.Fatalf("heartbeat %d, expected 10", b.Heartbeat)
+	}
+
+	// A restarted node starts its heartbeat over
+	c.merge([]*Member{{Address: "b:8080", Incarnation: 2, Heartbeat: 1, Detectors: []*odrpc.Detector{{Name: "new"}}}})
+	b := c.members["b:8080"]
+	if b.Incarnation != 2 || b.Heartbeat != 1 || b.Detectors[0].Name != "new" {
+		t.Fatalf("restarted member not merged: %+v", b.Member)
+	}
+
+	// Gossip from before the restart is ignored even with a higher heartbeat
+	c.merge([]*Member{{Address: "b:8080", Incarnation: 1, Heartbeat: 11}})
+	if b := c.members["b:8080"]; b.Incarnation != 2 {
+		t.Fatalf("stale incarnation replaced the member: %+v", b.Member)
+	}
+
+}
@@ -0,0 +1,34 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/server"
+)
+
+// RegisterHTTP registers the gossip and members endpoints on the router
+func (c *Cluster) RegisterHTTP(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(c.keys))
+		r.Post("/cluster/gossip", c.handleGossip)
+		r.Get("/cluster/members", c.handleMembers)
+	})
+}
+
+func (c *Cluster) handleGossip(w http.ResponseWriter, r *http.Request) {
+	var members []*Member
+	if err := json.NewDecoder(r.Body).Decode(&members); err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	c.merge(members)
+	render.JSON(w, r, c.state())
+}
+
+func (c *Cluster) handleMembers(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, c.Members())
+}
@@ -2,12 +2,23 @@ package cmd
 
 import (
 	cli "github.com/spf13/cobra"
+	config "github.com/spf13/viper"
 	"go.uber.org/zap"
 
+	"github.com/snowzach/doods/alert"
+	"github.com/snowzach/doods/cluster"
+	"github.com/snowzach/doods/compat"
 	"github.com/snowzach/doods/conf"
 	"github.com/snowzach/doods/detector"
+	"github.com/snowzach/doods/feedback"
+	"github.com/snowzach/doods/history"
+	"github.com/snowzach/doods/job"
 	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/review"
 	"github.com/snowzach/doods/server"
+	"github.com/snowzach/doods/sink"
+	"github.com/snowzach/doods/stream"
+	"github.com/snowzach/doods/zone"
 )
 
 func init() {
@@ -21,8 +32,29 @@ var (
 		Long:  `Start API`,
 		Run: func(cmd *cli.Command, args []string) { // Initialize the databse
 
+			// Stops everything on interrupt
+			lc := conf.NewLifecycle()
+			lc.StopOnInterrupt()
+
+			// Report to the service manager when running as a Windows service
+			serviceStopped := startService(lc)
+
+			// Zones for detectors and streams
+			zones, err := zone.NewStore(config.GetString("doods.zones_file"))
+			if err != nil {
+				logger.Fatalw("Could not load zones",
+					"error", err,
+				)
+			}
+
 			// Create the detector mux server
-			d := detector.New()
+			sinks := sink.New(lc)
+			alerts := alert.New(zones, sinks)
+			reviews := review.New(sinks)
+			fb := feedback.New(zones, sinks)
+			hist := history.New(lc)
+			d := detector.New(lc, zones, sinks, alerts, reviews, fb, hist)
+			sinks.SetHealth(d.Health)
 
 			// Create the server
 			s, err := server.New()
@@ -36,6 +68,41 @@ var (
 			odrpc.RegisterOdrpcServer(s.GRPCServer(), d)
 			s.GWReg(odrpc.RegisterOdrpcHandlerFromEndpoint)
 
+			// Register the HTTP only endpoints
+			d.RegisterHTTP(s.Router())
+
+			// Start any streams
+			st := stream.New(lc, d, zones, sinks)
+			st.RegisterHTTP(s.Router())
+
+			// Alert rules
+			alerts.RegisterHTTP(s.Router())
+
+			// Low confidence detections for labeling
+			reviews.RegisterHTTP(s.Router())
+
+			// False positive feedback
+			fb.RegisterHTTP(s.Router())
+
+			// Detection history
+			hist.RegisterHTTP(s.Router())
+
+			// Start any scheduled jobs
+			jobs := job.New(lc, d)
+			jobs.RegisterHTTP(s.Router())
+
+			// Compatible APIs for other projects
+			compat.RegisterHTTP(s.Router(), d)
+
+			// Zone management
+			zones.RegisterHTTP(s.Router(), server.Keys())
+
+			// Find the other doods nodes and forward requests for their detectors
+			if cl := cluster.New(lc, d.LocalDetectors); cl != nil {
+				d.SetCluster(cl)
+				cl.RegisterHTTP(s.Router())
+			}
+
 			err = s.ListenAndServe()
 			if err != nil {
 				logger.Fatalw("Could not start server",
@@ -43,9 +110,19 @@ var (
 				)
 			}
 
-			<-conf.Stop.Chan() // Wait until StopChan
-			conf.Stop.Wait()   // Wait until everyone cleans up
-			zap.L().Sync()     // Flush the logger
+			// Apply changes to the config file, e.g. from a Kubernetes ConfigMap
+			if config.GetBool("watch_config") {
+				if config.ConfigFileUsed() == "" {
+					logger.Warnw("There is no config file to watch")
+				} else {
+					conf.WatchConfig()
+				}
+			}
+
+			<-lc.Done()      // Wait until stopped
+			lc.Wait()        // Wait until everyone cleans up
+			serviceStopped() // Tell the service manager
+			zap.L().Sync()   // Flush the logger
 
 		},
 	}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+
+	cli "github.com/spf13/cobra"
+	config "github.com/spf13/viper"
+
+	"github.com/snowzach/doods/detector/sealed"
+)
+
+func init() {
+
+	modelCmd := &cli.Command{
+		Use:   "model",
+		Short: "Encrypt and sign model files",
+		Long:  `Generate keys and encrypt and sign model files for doods instances configured with doods.models keys.`,
+	}
+
+	modelCmd.AddCommand(&cli.Command{
+		Use:   "keygen",
+		Short: "Generate an encryption key",
+		Args:  cli.NoArgs,
+		Run: func(cmd *cli.Command, args []string) {
+			key, err := sealed.GenerateKey()
+			if err != nil {
+				logger.Fatalw("Could not generate key", "error", err)
+			}
+			fmt.Println(sealed.EncodeKey(key))
+		},
+	})
+
+	modelCmd.AddCommand(&cli.Command{
+		Use:   "keypair",
+		Short: "Generate a signing key pair",
+		Args:  cli.NoArgs,
+		Run: func(cmd *cli.Command, args []string) {
+			public, private, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				logger.Fatalw("Could not generate key pair", "error", err)
+			}
+			fmt.Printf("public_key: %s\nprivate_key: %s\n", sealed.EncodeKey(public), sealed.EncodeKey(private))
+		},
+	})
+
+	modelCmd.AddCommand(&cli.Command{
+		Use:   "encrypt <model> [output]",
+		Short: "Encrypt a model with doods.models.key, the output is the model with .enc by default",
+		Args:  cli.RangeArgs(1, 2),
+		Run: func(cmd *cli.Command, args []string) {
+			key, err := sealed.ParseKey(config.GetString("doods.models.key"))
+			if err != nil {
+				logger.Fatalw("Could not get doods.models.key", "error", err)
+			}
+			data, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				logger.Fatalw("Could not read model", "file", args[0], "error", err)
+			}
+			if sealed.IsEncrypted(data) {
+				logger.Fatalw("Model is already encrypted", "file", args[0])
+			}
+			out := args[0] + ".enc"
+			if len(args) > 1 {
+				out = args[1]
+			}
+			encrypted, err := sealed.Encrypt(data, key)
+			if err != nil {
+				logger.Fatalw("Could not encrypt model", "error", err)
+			}
+			if err = ioutil.WriteFile(out, encrypted, 0644); err != nil {
+				logger.Fatalw("Could not write model", "file", out, "error", err)
+			}
+			fmt.Printf("Encrypted %s to %s\n", args[0], out)
+		},
+	})
+
+	modelCmd.AddCommand(&cli.Command{
+		Use:   "sign <model>",
+		Short: "Sign a model with doods.models.private_key, the signature is written to the model with .sig",
+		Args:  cli.ExactArgs(1),
+		Run: func(cmd *cli.Command, args []string) {
+			key, err := sealed.ParsePrivateKey(config.GetString("doods.models.private_key"))
+			if err != nil {
+				logger.Fatalw("Could not get doods.models.private_key", "error", err)
+			}
+			data, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				logger.Fatalw("Could not read model", "file", args[0], "error", err)
+			}
+			if err = ioutil.WriteFile(args[0]+".sig", sealed.Sign(data, key), 0644); err != nil {
+				logger.Fatalw("Could not write signature", "file", args[0]+".sig", "error", err)
+			}
+			fmt.Printf("Signed %s\n", args[0])
+		},
+	})
+
+	rootCmd.AddCommand(modelCmd)
+
+}
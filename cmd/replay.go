@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	cli "github.com/spf13/cobra"
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/client"
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector"
+	"github.com/snowzach/doods/verify"
+)
+
+func init() {
+
+	opts := new(verify.Options)
+	var address string
+	clientOpts := new(client.Options)
+
+	replayCmd := &cli.Command{
+		Use:   "replay <path>",
+		Short: "Replay saved requests and compare the detections",
+		Long: `Replay requests saved to doods.debug.dir, or the images in a manifest.json, and compare the detections with the
+expected results. The path is a saved request, the debug directory, a directory with a manifest.json or a manifest.`,
+		Args: cli.ExactArgs(1),
+		Run: func(cmd *cli.Command, args []string) {
+
+			cases, err := verify.LoadCases(args[0])
+			if err != nil {
+				logger.Fatalw("Could not load requests", "path", args[0], "error", err)
+			}
+
+			lc := conf.NewLifecycle()
+			lc.StopOnInterrupt()
+
+			// Replay on a doods server or the detectors in the config
+			var d verify.Detector
+			var shutdown func()
+			if address != "" {
+				c, err := client.New(lc.Context(), address, clientOpts)
+				if err != nil {
+					logger.Fatalw("Could not connect", "address", address, "error", err)
+				}
+				d, shutdown = c, func() { c.Close() }
+			} else {
+				// The replayed requests aren't saved again
+				config.Set("doods.debug.dir", "")
+				m := detector.New(lc, nil, nil, nil, nil, nil, nil)
+				d, shutdown = m, m.Shutdown
+			}
+
+			results, err := verify.Replay(lc.Context(), d, opts, cases)
+			if err != nil && err != context.Canceled {
+				logger.Errorw("Could not replay", "error", err)
+			}
+
+			failed := printResults(results)
+
+			lc.Stop()
+			shutdown()
+			zap.L().Sync() // Flush the logger
+
+			if failed > 0 || err != nil {
+				os.Exit(1)
+			}
+
+		},
+	}
+
+	replayCmd.Flags().StringVar(&opts.Detector, "detector", "", "Replay on this detector instead of the detector of each request")
+	replayCmd.Flags().Float32Var(&opts.MinConfidence, "min-confidence", 50, "Only compare detections with at least this confidence")
+	replayCmd.Flags().Float32Var(&opts.IOU, "iou", 0.5, "The minimum overlap for a detection to match")
+	replayCmd.Flags().Float32Var(&opts.Tolerance, "tolerance", 5, "The maximum confidence difference")
+	replayCmd.Flags().BoolVar(&opts.Update, "update", false, "Save the detections as the new expected results")
+	replayCmd.Flags().StringVar(&address, "address", "", "Replay on the doods server at host:port instead of the configured detectors")
+	replayCmd.Flags().StringVar(&clientOpts.AuthKey, "auth-key", "", "The auth key for the doods server")
+	replayCmd.Flags().BoolVar(&clientOpts.TLS, "tls", false, "Connect to the doods server with TLS")
+
+	rootCmd.AddCommand(replayCmd)
+
+}
@@ -21,6 +21,7 @@ var (
 	configFile string
 	pidFile    string
 	logger     *zap.SugaredLogger
+	envKeys    []string
 
 	// The Root Cli Handler
 	rootCmd = &cli.Command{
@@ -79,11 +80,21 @@ func initConfig() {
 		}
 
 	}
+
+	// Environment variables override anything in the config file, including list entries
+	envKeys = conf.ApplyEnv(os.Environ())
 }
 
 func initLogger() {
+	// The console isn't visible to a service, log to the event log
+	if inService() && config.GetString("logger.eventlog") == "" {
+		config.Set("logger.eventlog", config.GetString("service.name"))
+	}
 	conf.InitLogger()
 	logger = zap.S().With("package", "cmd")
+	if len(envKeys) > 0 {
+		logger.Debugw("Config set by environment", "keys", envKeys)
+	}
 }
 
 // Profiler can explicitly listen on address/port
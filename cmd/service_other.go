@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package cmd
+
+import (
+	"github.com/snowzach/doods/conf"
+)
+
+// inService is only true for a Windows service
+func inService() bool {
+	return false
+}
+
+// startService does nothing, services are only supported on Windows
+func startService(lc *conf.Lifecycle) func() {
+	return func() {}
+}
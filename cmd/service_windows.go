@@ -0,0 +1,246 @@
+//go:build windows
+// +build windows
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	cli "github.com/spf13/cobra"
+	config "github.com/spf13/viper"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+
+	"github.com/snowzach/doods/conf"
+)
+
+func init() {
+
+	serviceCmd := &cli.Command{
+		Use:   "service",
+		Short: "Manage the Windows service",
+		Long:  `Install, remove, start and stop doods as a Windows service that runs the api. Needs an administrator prompt.`,
+	}
+
+	for _, c := range []struct {
+		use   string
+		short string
+		run   func(name string) error
+	}{
+		{"install", "Install the service, it uses the config file given with -c", installService},
+		{"uninstall", "Remove the service", uninstallService},
+		{"start", "Start the service", startWindowsService},
+		{"stop", "Stop the service and wait for it to stop", stopWindowsService},
+	} {
+		c := c
+		serviceCmd.AddCommand(&cli.Command{
+			Use:   c.use,
+			Short: c.short,
+			Run: func(cmd *cli.Command, args []string) {
+				name := config.GetString("service.name")
+				if err := c.run(name); err != nil {
+					fmt.Fprintf(os.Stderr, "Could not %s service %s: %v\n", c.use, name, err)
+					os.Exit(1)
+				}
+				fmt.Printf("Service %s: %s complete\n", name, c.use)
+			},
+		})
+	}
+
+	rootCmd.AddCommand(serviceCmd)
+
+}
+
+// inService returns true when started by the service manager
+func inService() bool {
+	isService, err := svc.IsWindowsService()
+	return err == nil && isService
+}
+
+// startService reports to the service manager when running as a service and stops the lifecycle when the service
+// is stopped. The returned func is called once everything has stopped so the service manager sees a clean stop.
+func startService(lc *conf.Lifecycle) func() {
+
+	if !inService() {
+		return func() {}
+	}
+
+	// Services start in the system directory, relative paths in the config are relative to the config file
+	dir := filepath.Dir(configFile)
+	if configFile == "" {
+		if exe, err := os.Executable(); err == nil {
+			dir = filepath.Dir(exe)
+		}
+	}
+	if err := os.Chdir(dir); err != nil {
+		logger.Warnw("Could not change directory", "dir", dir, "error", err)
+	}
+
+	h := &serviceHandler{
+		lc:      lc,
+		stopped: make(chan struct{}),
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := svc.Run(config.GetString("service.name"), h); err != nil {
+			logger.Errorw("Service failed", "error", err)
+			lc.Stop()
+		}
+	}()
+
+	return func() {
+		close(h.stopped)
+		<-done
+	}
+
+}
+
+type serviceHandler struct {
+	lc      *conf.Lifecycle
+	stopped chan struct{}
+}
+
+// Execute handles the service manager requests until stopped
+func (h *serviceHandler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+
+	status <- svc.Status{State: svc.StartPending}
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	logger.Info("Service running")
+
+	for {
+		select {
+		case r := <-requests:
+			switch r.Cmd {
+			case svc.Interrogate:
+				status <- r.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				logger.Info("Service stop requested...")
+				h.lc.Stop()
+			}
+		case <-h.lc.Done():
+			// Wait for the detections in progress, the service manager is told how long it could take
+			status <- svc.Status{State: svc.StopPending, WaitHint: 30000}
+			<-h.stopped
+			return false, 0
+		}
+	}
+
+}
+
+func installService(name string) error {
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{"api"}
+	if configFile != "" {
+		abs, err := filepath.Abs(configFile)
+		if err != nil {
+			return err
+		}
+		args = append(args, "-c", abs)
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(name); err == nil {
+		s.Close()
+		return fmt.Errorf("service already exists")
+	}
+	s, err := m.CreateService(name, exe, mgr.Config{
+		DisplayName: "DOODS",
+		Description: "Dedicated Open Object Detection Service",
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	// Register the event log source, the log messages are used as is
+	if err := eventlog.InstallAsEventCreate(name, eventlog.Error|eventlog.Warning|eventlog.Info); err != nil {
+		s.Delete()
+		return fmt.Errorf("could not register the event log source: %v", err)
+	}
+	return nil
+
+}
+
+func uninstallService(name string) error {
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	if err := s.Delete(); err != nil {
+		return err
+	}
+	return eventlog.Remove(name)
+
+}
+
+func startWindowsService(name string) error {
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	return s.Start()
+
+}
+
+func stopWindowsService(name string) error {
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	status, err := s.Control(svc.Stop)
+	if err != nil {
+		return err
+	}
+	timeout := time.Now().Add(time.Minute)
+	for status.State != svc.Stopped {
+		if time.Now().After(timeout) {
+			return fmt.Errorf("timed out waiting for the service to stop")
+		}
+		time.Sleep(500 * time.Millisecond)
+		if status, err = s.Query(); err != nil {
+			return err
+		}
+	}
+	return nil
+
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	cli "github.com/spf13/cobra"
+	config "github.com/spf13/viper"
+
+	"github.com/snowzach/doods/detector/sealed"
+	"github.com/snowzach/doods/detector/signing"
+	"github.com/snowzach/doods/odrpc"
+)
+
+func init() {
+
+	signingCmd := &cli.Command{
+		Use:   "signing",
+		Short: "Generate signing keys and check signed detections",
+		Long:  `Generate keys for doods.signing and check the signature of a detect response or sink event.`,
+	}
+
+	signingCmd.AddCommand(&cli.Command{
+		Use:   "keygen <hmac-sha256|ed25519>",
+		Short: "Generate a signing key",
+		Args:  cli.ExactArgs(1),
+		Run: func(cmd *cli.Command, args []string) {
+			switch args[0] {
+			case signing.HMACSHA256:
+				key, err := sealed.GenerateKey()
+				if err != nil {
+					logger.Fatalw("Could not generate key", "error", err)
+				}
+				fmt.Printf("key: %s\n", sealed.EncodeKey(key))
+			case signing.Ed25519:
+				public, private, err := ed25519.GenerateKey(rand.Reader)
+				if err != nil {
+					logger.Fatalw("Could not generate key pair", "error", err)
+				}
+				fmt.Printf("key: %s\npublic_key: %s\n", sealed.EncodeKey(private), sealed.EncodeKey(public))
+			default:
+				logger.Fatalw("Unknown signing algorithm", "algorithm", args[0])
+			}
+		},
+	})
+
+	verifyCmd := &cli.Command{
+		Use:   "verify <json> [image]",
+		Short: "Check the signature of a detect response, sink event or history event, and that it's for the image if it's given",
+		Args:  cli.RangeArgs(1, 2),
+		Run: func(cmd *cli.Command, args []string) {
+
+			var signed struct {
+				ID         string             `json:"id"`
+				Detections []*odrpc.Detection `json:"detections"`
+				Signature  *odrpc.Signature   `json:"signature"`
+				// History events have their own id
+				RequestID string `json:"request_id"`
+			}
+			data, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				logger.Fatalw("Could not read file", "file", args[0], "error", err)
+			}
+			if err = json.Unmarshal(data, &signed); err != nil {
+				logger.Fatalw("Could not parse file", "file", args[0], "error", err)
+			}
+			if signed.RequestID != "" {
+				signed.ID = signed.RequestID
+			}
+
+			var image []byte
+			if len(args) > 1 {
+				if image, err = ioutil.ReadFile(args[1]); err != nil {
+					logger.Fatalw("Could not read image", "file", args[1], "error", err)
+				}
+			}
+
+			key, _ := cmd.Flags().GetString("key")
+			if key == "" {
+				key = verifyKey(signed.Signature)
+			}
+
+			if err = signing.Verify(signed.ID, signed.Detections, signed.Signature, image, key); err != nil {
+				fmt.Printf("INVALID %s: %v\n", args[0], err)
+				os.Exit(1)
+			}
+			fmt.Printf("OK %s signed with %s key %q at %s\n", args[0], signed.Signature.Algorithm, signed.Signature.KeyId,
+				time.Unix(0, signed.Signature.SignedAt*int64(time.Millisecond)).Format(time.RFC3339))
+
+		},
+	}
+	verifyCmd.Flags().String("key", "", "The hmac-sha256 key or ed25519 public key, the public key of doods.signing.key by default")
+	signingCmd.AddCommand(verifyCmd)
+
+	rootCmd.AddCommand(signingCmd)
+
+}
+
+// verifyKey returns the key to check the signature with from doods.signing.key
+func verifyKey(sig *odrpc.Signature) string {
+	key := config.GetString("doods.signing.key")
+	if sig != nil && sig.Algorithm == signing.Ed25519 {
+		if private, err := sealed.ParsePrivateKey(key); err == nil {
+			return sealed.EncodeKey(private.Public().(ed25519.PublicKey))
+		}
+	}
+	return key
+}
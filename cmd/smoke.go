@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"time"
+
+	cli "github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector"
+	"github.com/snowzach/doods/odrpc"
+)
+
+func init() {
+
+	var (
+		detectorName  string
+		imageFile     string
+		minConfidence float32
+	)
+
+	smokeCmd := &cli.Command{
+		Use:   "smoke",
+		Short: "Run one detection to check the detector works",
+		Long:  `Load the detectors and run an image (a generated one by default) through a detector. It needs no model files with a binary built with the embedded model.`,
+		Run: func(cmd *cli.Command, args []string) {
+
+			data := testImage()
+			if imageFile != "" {
+				var err error
+				if data, err = ioutil.ReadFile(imageFile); err != nil {
+					logger.Fatalw("Could not read image", "file", imageFile, "error", err)
+				}
+			}
+
+			lc := conf.NewLifecycle()
+			lc.StopOnInterrupt()
+
+			// Only the detectors, no sinks, alerts or zones
+			d := detector.New(lc, nil, nil, nil, nil, nil, nil)
+
+			start := time.Now()
+			response, err := d.Detect(lc.Context(), &odrpc.DetectRequest{
+				Id:           "smoke",
+				DetectorName: detectorName,
+				Data:         data,
+				Detect:       map[string]float32{"*": minConfidence},
+			})
+			if err == nil && response.Error != "" {
+				err = fmt.Errorf("%s", response.Error)
+			}
+			if err == nil {
+				fmt.Printf("OK %s: %d detections in %v\n", detectorName, len(response.Detections), time.Since(start))
+				for _, detection := range response.Detections {
+					fmt.Printf("    %s\n", formatDetection(detection))
+				}
+			} else {
+				fmt.Printf("FAIL %s: %v\n", detectorName, err)
+			}
+
+			lc.Stop()
+			d.Shutdown()
+			zap.L().Sync() // Flush the logger
+
+			if err != nil {
+				os.Exit(1)
+			}
+
+		},
+	}
+
+	smokeCmd.Flags().StringVar(&detectorName, "detector", "default", "The detector to run")
+	smokeCmd.Flags().StringVarP(&imageFile, "image", "i", "", "The image to detect, a generated image if blank")
+	smokeCmd.Flags().Float32Var(&minConfidence, "min-confidence", 50, "Only return detections with at least this confidence")
+
+	rootCmd.AddCommand(smokeCmd)
+
+}
+
+// testImage returns a generated PNG with a few shapes for when there's no image
+func testImage() []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			c := color.RGBA{uint8(x * 255 / 640), uint8(y * 255 / 480), 128, 255}
+			switch {
+			case x > 100 && x < 250 && y > 150 && y < 400:
+				c = color.RGBA{200, 40, 40, 255}
+			case (x-450)*(x-450)+(y-240)*(y-240) < 80*80:
+				c = color.RGBA{40, 40, 200, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
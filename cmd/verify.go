@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	cli "github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/verify"
+)
+
+func init() {
+
+	opts := new(verify.Options)
+
+	verifyCmd := &cli.Command{
+		Use:   "verify",
+		Short: "Verify detections against golden results",
+		Long:  `Run the reference images through a detector and compare the detections with the stored golden results`,
+		Run: func(cmd *cli.Command, args []string) {
+
+			lc := conf.NewLifecycle()
+			lc.StopOnInterrupt()
+
+			// Only the detectors, no sinks, alerts or zones
+			d := detector.New(lc, nil, nil, nil, nil, nil, nil)
+
+			results, err := verify.Run(lc.Context(), d, opts)
+			if err != nil && results == nil {
+				logger.Fatalw("Could not verify", "error", err)
+			}
+
+			failed := printResults(results)
+
+			lc.Stop()
+			d.Shutdown()
+			zap.L().Sync() // Flush the logger
+
+			if failed > 0 || err != nil {
+				os.Exit(1)
+			}
+
+		},
+	}
+
+	verifyCmd.Flags().StringVarP(&opts.Dir, "dir", "d", "verify", "The directory with the reference images and golden results")
+	verifyCmd.Flags().StringVar(&opts.Detector, "detector", "default", "The detector to verify")
+	verifyCmd.Flags().Float32Var(&opts.MinConfidence, "min-confidence", 50, "Only compare detections with at least this confidence")
+	verifyCmd.Flags().Float32Var(&opts.IOU, "iou", 0.5, "The minimum overlap for a detection to match")
+	verifyCmd.Flags().Float32Var(&opts.Tolerance, "tolerance", 5, "The maximum confidence difference")
+	verifyCmd.Flags().BoolVar(&opts.Update, "update", false, "Save the detections as the new golden results")
+
+	rootCmd.AddCommand(verifyCmd)
+
+}
+
+// printResults prints the verify or replay results and returns how many failed
+func printResults(results []*verify.Result) int {
+	var failed int
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			fmt.Printf("FAIL %s: %s\n", r.Image, r.Error)
+		case r.Updated:
+			fmt.Printf("SAVE %s: %d detections\n", r.Image, r.Matched)
+		case r.Passed():
+			fmt.Printf("PASS %s: %d detections\n", r.Image, r.Matched)
+		default:
+			fmt.Printf("FAIL %s: %d matched\n", r.Image, r.Matched)
+			for _, m := range r.Missing {
+				fmt.Printf("    missing %s\n", formatDetection(m))
+			}
+			for _, e := range r.Extra {
+				fmt.Printf("    extra   %s\n", formatDetection(e))
+			}
+			for _, c := range r.Changed {
+				fmt.Printf("    changed %s -> %.1f\n", formatDetection(c.Golden), c.Got.Confidence)
+			}
+		}
+		if !r.Passed() {
+			failed++
+		}
+	}
+	fmt.Printf("%d images, %d failed\n", len(results), failed)
+	return failed
+}
+
+// formatDetection formats a detection for the verify output
+func formatDetection(d *odrpc.Detection) string {
+	return fmt.Sprintf("%s %.1f [%.3f, %.3f, %.3f, %.3f]", d.Label, d.Confidence, d.Top, d.Left, d.Bottom, d.Right)
+}
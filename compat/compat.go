@@ -0,0 +1,130 @@
+// Package compat implements APIs compatible with other object detection servers
+package compat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	// We will support these formats
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/bmp"
+
+	"github.com/go-chi/chi"
+	config "github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
+)
+
+// The max size of an uploaded image
+const maxUploadSize = 32 << 20
+
+// errUnauthorized is returned for a missing or wrong auth key
+var errUnauthorized = errors.New("unauthorized")
+
+// Detector runs detections
+type Detector interface {
+	Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error)
+}
+
+// API handles the compatible endpoints
+type API struct {
+	detector Detector
+	keys     *server.AuthKeys
+	// The detector used for the DeepStack detection endpoint
+	deepstackDetector string
+}
+
+// RegisterHTTP registers the compatible endpoints on the router
+func RegisterHTTP(r chi.Router, detector Detector) {
+	api := &API{
+		detector:          detector,
+		keys:              server.Keys(),
+		deepstackDetector: config.GetString("doods.deepstack_detector"),
+	}
+	r.Post("/frigate/{detector}/v1/vision/detection", api.handleFrigate)
+	r.Post("/v1/vision/detection", api.handleDeepstack)
+	r.Post("/v1/vision/custom/{model}", api.handleDeepstack)
+}
+
+// prediction is a detection in pixel coordinates (DeepStack format)
+type prediction struct {
+	Label      string  `json:"label"`
+	Confidence float32 `json:"confidence"`
+	XMin       int     `json:"x_min"`
+	YMin       int     `json:"y_min"`
+	XMax       int     `json:"x_max"`
+	YMax       int     `json:"y_max"`
+}
+
+// readImage checks the auth key and reads the image from the multipart form. The auth key header is checked before the
+// body is read, the api_key form field once the form is read up to the max size. Detecting needs a read key.
+func (api *API) readImage(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+
+	key := r.Header.Get(odrpc.DoodsAuthKeyHeader)
+	if key != "" && !api.keys.Allowed(r.Context(), key, server.RoleRead) {
+		return nil, errUnauthorized
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		return nil, fmt.Errorf("invalid form: %v", err)
+	}
+	if key == "" && !api.keys.Allowed(r.Context(), r.FormValue("api_key"), server.RoleRead) {
+		return nil, errUnauthorized
+	}
+
+	f, _, err := r.FormFile("image")
+	if err != nil {
+		return nil, fmt.Errorf("missing image: %v", err)
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+
+}
+
+// detect runs the detection and converts the results to pixel coordinates sorted by confidence
+func (api *API) detect(ctx context.Context, detector string, data []byte, minConfidence float32) ([]*prediction, error) {
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not decode image: %v", err)
+	}
+
+	response, err := api.detector.Detect(ctx, &odrpc.DetectRequest{
+		DetectorName: detector,
+		Data:         data,
+		Detect:       map[string]float32{"*": minConfidence * 100},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	predictions := make([]*prediction, 0, len(response.Detections))
+	for _, d := range response.Detections {
+		predictions = append(predictions, &prediction{
+			Label:      d.Label,
+			Confidence: d.Confidence / 100,
+			XMin:       int(d.Left * float32(cfg.Width)),
+			YMin:       int(d.Top * float32(cfg.Height)),
+			XMax:       int(d.Right * float32(cfg.Width)),
+			YMax:       int(d.Bottom * float32(cfg.Height)),
+		})
+	}
+	sort.SliceStable(predictions, func(i, j int) bool { return predictions[i].Confidence > predictions[j].Confidence })
+
+	return predictions, nil
+
+}
@@ -0,0 +1,119 @@
+package compat
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	config "github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
+)
+
+type testDetector struct {
+	detected bool
+}
+
+func (d *testDetector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+	d.detected = true
+	if request.DetectorName != "default" {
+		return nil, status.Errorf(codes.NotFound, "detector %s not found", request.DetectorName)
+	}
+	return &odrpc.DetectResponse{Detections: []*odrpc.Detection{{Label: "person", Confidence: 90, Right: 1, Bottom: 1}}}, nil
+}
+
+// unread fails the test if the body is read
+type unread struct {
+	t *testing.T
+}
+
+func (u *unread) Read(p []byte) (int, error) {
+	u.t.Fatal("body read before the auth key was checked")
+	return 0, nil
+}
+
+func form(t *testing.T, apiKey string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if apiKey != "" {
+		mw.WriteField("api_key", apiKey)
+	}
+	fw, _ := mw.CreateFormFile("image", "image.png")
+	if err := png.Encode(fw, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	mw.Close()
+	return &body, mw.FormDataContentType()
+}
+
+func TestCompat(t *testing.T) {
+
+	defer config.Reset()
+	config.Set("doods.auth_key", "secret")
+	config.Set("doods.deepstack_detector", "default")
+	if !server.Keys().Enabled() {
+		t.Fatal("auth keys not enabled")
+	}
+
+	d := &testDetector{}
+	r := chi.NewRouter()
+	RegisterHTTP(r, d)
+
+	// A wrong header key is rejected before the upload is read
+	req := httptest.NewRequest("POST", "/frigate/default/v1/vision/detection", &unread{t: t})
+	req.Header.Set(odrpc.DoodsAuthKeyHeader, "wrong")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden || d.detected {
+		t.Fatalf("wrong header key got status %d", w.Code)
+	}
+
+	for _, test := range []struct {
+		url    string
+		apiKey string
+		status int
+	}{
+		{"/v1/vision/detection", "", http.StatusUnauthorized},
+		{"/v1/vision/detection", "wrong", http.StatusUnauthorized},
+		{"/v1/vision/detection", "secret", http.StatusOK},
+		{"/v1/vision/custom/missing", "secret", http.StatusNotFound},
+		{"/frigate/default/v1/vision/detection", "", http.StatusForbidden},
+		{"/frigate/default/v1/vision/detection", "secret", http.StatusOK},
+		{"/frigate/missing/v1/vision/detection", "secret", http.StatusNotFound},
+	} {
+		body, contentType := form(t, test.apiKey)
+		req := httptest.NewRequest("POST", test.url, body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != test.status {
+			t.Errorf("%s with key %q got status %d, expected %d: %s", test.url, test.apiKey, w.Code, test.status, w.Body)
+		}
+	}
+
+	// The upload is limited
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("image", "image.png")
+	fw.Write(make([]byte, maxUploadSize))
+	mw.Close()
+	req = httptest.NewRequest("POST", "/v1/vision/detection", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set(odrpc.DoodsAuthKeyHeader, "secret")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "too large") {
+		t.Errorf("large upload got status %d: %s", w.Code, w.Body)
+	}
+
+}
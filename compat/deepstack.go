@@ -0,0 +1,76 @@
+package compat
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/server"
+)
+
+// The DeepStack default minimum confidence
+const deepstackMinConfidence = 0.45
+
+type deepstackResponse struct {
+	Success     bool          `json:"success"`
+	Predictions []*prediction `json:"predictions"`
+	Error       string        `json:"error,omitempty"`
+	Duration    int           `json:"duration"`
+}
+
+// handleDeepstack implements the DeepStack object detection API. The detector is the
+// custom model name or the configured default.
+func (api *API) handleDeepstack(w http.ResponseWriter, r *http.Request) {
+
+	start := time.Now()
+
+	data, err := api.readImage(w, r)
+	if err == errUnauthorized {
+		api.deepstackError(w, r, http.StatusUnauthorized, "Incorrect api key")
+		return
+	} else if err != nil {
+		api.deepstackError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	minConfidence := float32(deepstackMinConfidence)
+	if mc := r.FormValue("min_confidence"); mc != "" {
+		v, err := strconv.ParseFloat(mc, 32)
+		if err != nil {
+			api.deepstackError(w, r, http.StatusBadRequest, "invalid min_confidence")
+			return
+		}
+		minConfidence = float32(v)
+	}
+
+	detector := chi.URLParam(r, "model")
+	if detector == "" {
+		detector = api.deepstackDetector
+	}
+
+	predictions, err := api.detect(r.Context(), detector, data, minConfidence)
+	if err != nil {
+		api.deepstackError(w, r, server.StatusCode(err), status.Convert(err).Message())
+		return
+	}
+
+	render.JSON(w, r, &deepstackResponse{
+		Success:     true,
+		Predictions: predictions,
+		Duration:    int(time.Since(start) / time.Millisecond),
+	})
+
+}
+
+func (api *API) deepstackError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	render.Status(r, status)
+	render.JSON(w, r, &deepstackResponse{
+		Success:     false,
+		Predictions: []*prediction{},
+		Error:       message,
+	})
+}
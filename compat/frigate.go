@@ -0,0 +1,42 @@
+package compat
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/server"
+)
+
+// Frigate only reads the top 20 detections
+const frigateMaxDetections = 20
+
+// handleFrigate implements the API used by the Frigate deepstack detector plugin. Frigate sends the
+// frame at the model size and reads up to 20 detections sorted by confidence.
+func (api *API) handleFrigate(w http.ResponseWriter, r *http.Request) {
+
+	data, err := api.readImage(w, r)
+	if err == errUnauthorized {
+		render.Render(w, r, server.ErrPermissionDenied)
+		return
+	} else if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+
+	predictions, err := api.detect(r.Context(), chi.URLParam(r, "detector"), data, 0)
+	if err != nil {
+		render.Render(w, r, server.ErrStatus(err))
+		return
+	}
+	if len(predictions) > frigateMaxDetections {
+		predictions = predictions[:frigateMaxDetections]
+	}
+
+	render.JSON(w, r, map[string]interface{}{
+		"success":     true,
+		"predictions": predictions,
+	})
+
+}
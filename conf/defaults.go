@@ -6,50 +6,146 @@ import (
 
 	config "github.com/spf13/viper"
 
+	"github.com/snowzach/doods/alert/alertconfig"
 	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/job/jobconfig"
+	"github.com/snowzach/doods/sink/sinkconfig"
+	"github.com/snowzach/doods/stream/sconfig"
 )
 
 func init() {
+	setDefaults(config.GetViper())
+}
+
+// setDefaults sets up the environment and the defaults, a reloaded config file starts over with a new config
+func setDefaults(v *config.Viper) {
+
 	// Sets up the config file, environment etc
-	config.SetTypeByDefaultValue(true)                      // If a default value is []string{"a"} an environment variable of "a b" will end up []string{"a","b"}
-	config.AutomaticEnv()                                   // Automatically use environment variables where available
-	config.SetEnvKeyReplacer(strings.NewReplacer(".", "_")) // Environement variables use underscores instead of periods
+	v.SetTypeByDefaultValue(true)                      // If a default value is []string{"a"} an environment variable of "a b" will end up []string{"a","b"}
+	v.AutomaticEnv()                                   // Automatically use environment variables where available
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_")) // Environement variables use underscores instead of periods
 
 	// Logger Defaults
-	config.SetDefault("logger.level", "info")
-	config.SetDefault("logger.encoding", "console")
-	config.SetDefault("logger.color", true)
-	config.SetDefault("logger.dev_mode", true)
-	config.SetDefault("logger.disable_caller", false)
-	config.SetDefault("logger.disable_stacktrace", true)
+	v.SetDefault("logger.level", "info")
+	v.SetDefault("logger.encoding", "console")
+	v.SetDefault("logger.color", true)
+	v.SetDefault("logger.dev_mode", true)
+	v.SetDefault("logger.disable_caller", false)
+	v.SetDefault("logger.disable_stacktrace", true)
+	v.SetDefault("logger.eventlog", "")
+	v.SetDefault("logger.eventlog_level", "warn")
+
+	// Windows service
+	v.SetDefault("service.name", "doods")
 
 	// Pidfile
-	config.SetDefault("pidfile", "")
+	v.SetDefault("pidfile", "")
+
+	// Reload the config file when it changes
+	v.SetDefault("watch_config", false)
 
 	// Profiler config
-	config.SetDefault("profiler.enabled", false)
-	config.SetDefault("profiler.host", "")
-	config.SetDefault("profiler.port", "6060")
+	v.SetDefault("profiler.enabled", false)
+	v.SetDefault("profiler.host", "")
+	v.SetDefault("profiler.port", "6060")
 
 	// Server Configuration
-	config.SetDefault("server.host", "")
-	config.SetDefault("server.port", "8080")
-	config.SetDefault("server.tls", false)
-	config.SetDefault("server.devcert", false)
-	config.SetDefault("server.certfile", "server.crt")
-	config.SetDefault("server.keyfile", "server.key")
-	config.SetDefault("server.max_msg_size", 64000000)
-	config.SetDefault("server.log_requests", true)
-	config.SetDefault("server.profiler_enabled", false)
-	config.SetDefault("server.profiler_path", "/debug")
-	config.SetDefault("server.allowed_origins", []string{"*"})
-	config.SetDefault("server.allowed_methods", []string{http.MethodHead, http.MethodOptions, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch})
-	config.SetDefault("server.allowed_headers", []string{"*"})
-	config.SetDefault("server.allowed_credentials", false)
-	config.SetDefault("server.max_age", 300)
+	v.SetDefault("server.host", "")
+	v.SetDefault("server.port", "8080")
+	v.SetDefault("server.tls", false)
+	v.SetDefault("server.devcert", false)
+	v.SetDefault("server.certfile", "server.crt")
+	v.SetDefault("server.keyfile", "server.key")
+	v.SetDefault("server.max_msg_size", 64000000)
+	v.SetDefault("server.log_requests", true)
+	v.SetDefault("server.profiler_enabled", false)
+	v.SetDefault("server.profiler_path", "/debug")
+	v.SetDefault("server.cors.enabled", true)
+	v.SetDefault("server.cors.allowed_origins", []string{"*"})
+	v.SetDefault("server.cors.allowed_methods", []string{http.MethodHead, http.MethodOptions, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodPatch})
+	v.SetDefault("server.cors.allowed_headers", []string{"*"})
+	v.SetDefault("server.cors.exposed_headers", []string{"ETag", "Last-Modified"})
+	v.SetDefault("server.cors.allowed_credentials", false)
+	v.SetDefault("server.cors.max_age", 300)
+	v.SetDefault("server.ui_enabled", true)
+	v.SetDefault("server.reflection", false)
+	v.SetDefault("server.compression.enabled", true)
+	v.SetDefault("server.compression.level", 5)
+	v.SetDefault("server.compression.types", []string{"application/json", "application/x-protobuf", "application/protobuf", "application/msgpack", "application/x-msgpack", "text/html", "text/plain"})
+	v.SetDefault("server.compression.exclude_paths", []string{})
 
 	// Main settings
-	config.SetDefault("doods.auth_key", "")
-	config.SetDefault("doods.detectors", []*dconfig.DetectorConfig{})
+	v.SetDefault("doods.auth_key", "")
+	v.SetDefault("doods.detectors", []*dconfig.DetectorConfig{})
+	v.SetDefault("doods.streams", []*sconfig.StreamConfig{})
+	v.SetDefault("doods.zones_file", "zones.json")
+	v.SetDefault("doods.sinks", []*sinkconfig.SinkConfig{})
+	v.SetDefault("doods.jobs", []*jobconfig.JobConfig{})
+	v.SetDefault("doods.alerts", []*alertconfig.RuleConfig{})
+	v.SetDefault("doods.deepstack_detector", "default")
+	v.SetDefault("doods.fetch.allowed_hosts", []string{})
+	v.SetDefault("doods.fetch.max_size", 20000000)
+	v.SetDefault("doods.fetch.timeout", "10s")
+	v.SetDefault("doods.max_restarts", 3)
+	v.SetDefault("doods.image.max_width", 16384)
+	v.SetDefault("doods.image.max_height", 16384)
+	v.SetDefault("doods.image.max_pixels", 50000000)
+	v.SetDefault("doods.boxes.max", 32)
+	v.SetDefault("doods.state.debounce", "2s")
+	v.SetDefault("doods.state.leave", "30s")
+	v.SetDefault("doods.scheduler.capacity", 0)
+	v.SetDefault("doods.simd", true)
+	v.SetDefault("doods.embedded.enabled", false)
+	v.SetDefault("doods.embedded.dir", "")
+	v.SetDefault("doods.models.key", "")
+	v.SetDefault("doods.models.key_command", []string{})
+	v.SetDefault("doods.models.public_keys", []string{})
+	v.SetDefault("doods.router.backends", []*dconfig.BackendConfig{})
+	v.SetDefault("doods.router.discover", true)
+	v.SetDefault("doods.router.timeout", "10s")
+	v.SetDefault("doods.signing.algorithm", "")
+	v.SetDefault("doods.signing.key", "")
+	v.SetDefault("doods.signing.key_id", "")
+	v.SetDefault("doods.cluster.enabled", false)
+	v.SetDefault("doods.cluster.advertise", "")
+	v.SetDefault("doods.cluster.seeds", []string{})
+	v.SetDefault("doods.cluster.interval", "1s")
+	v.SetDefault("doods.cluster.dead_after", "30s")
+	v.SetDefault("doods.cluster.fanout", 2)
+	v.SetDefault("doods.cluster.auth_key", "")
+	v.SetDefault("doods.cluster.tls", false)
+	v.SetDefault("doods.cluster.insecure_skip_verify", false)
+	v.SetDefault("doods.dedupe.window", "0s")
+	v.SetDefault("doods.dedupe.iou", 0.5)
+	v.SetDefault("doods.script", "")
+	v.SetDefault("doods.review.dir", "")
+	v.SetDefault("doods.review.min", 30)
+	v.SetDefault("doods.review.max", 55)
+	v.SetDefault("doods.review.labels", []string{})
+	v.SetDefault("doods.review.sources", []string{})
+	v.SetDefault("doods.review.max_items", 1000)
+	v.SetDefault("doods.review.rate_limit", "1m")
+	v.SetDefault("doods.review.sink", "")
+	v.SetDefault("doods.feedback.dir", "")
+	v.SetDefault("doods.feedback.recent", 100)
+	v.SetDefault("doods.feedback.sink", "")
+	v.SetDefault("doods.debug.dir", "")
+	v.SetDefault("doods.debug.errors", true)
+	v.SetDefault("doods.debug.empty", false)
+	v.SetDefault("doods.debug.max_requests", 100)
+	v.SetDefault("doods.history.dir", "")
+	v.SetDefault("doods.history.retention", "168h")
+	v.SetDefault("doods.history.labels", map[string]string{})
+	v.SetDefault("doods.history.sources", []string{})
+	v.SetDefault("doods.history.images", true)
+	v.SetDefault("doods.privacy.face_labels", []string{"face"})
+	v.SetDefault("doods.privacy.persist_faces", false)
+	v.SetDefault("doods.pets.dir", "")
+	v.SetDefault("doods.pets.detector", "")
+	v.SetDefault("doods.pets.labels", []string{"cat", "dog"})
+	v.SetDefault("doods.pets.min_confidence", 50)
+	v.SetDefault("doods.pets.padding", 0.1)
+	v.SetDefault("doods.pets.threshold", 0.8)
+	v.SetDefault("doods.pets.max_embeddings", 20)
 
 }
@@ -0,0 +1,240 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	config "github.com/spf13/viper"
+)
+
+// ApplyEnv overrides the config with environment variables like DOODS_DETECTORS_DEFAULT_NUMTHREADS=4. The name is the
+// path of the value with underscores, list entries are picked by index or name and values starting with [ or { are
+// JSON. Viper only does this for plain keys, this also handles lists like the detectors. It returns the keys it set.
+func ApplyEnv(environ []string) []string {
+	return applyEnv(config.GetViper(), environ)
+}
+
+// applyEnv overrides the config v with the environment variables
+func applyEnv(v *config.Viper, environ []string) []string {
+
+	environ = append([]string{}, environ...)
+	sort.Strings(environ) // So a list is set before its entries and entries are added in order
+
+	var keys []string
+	settings := v.AllSettings()
+	for _, env := range environ {
+		i := strings.Index(env, "=")
+		if i <= 0 {
+			continue
+		}
+		o := &envOverride{value: env[i+1:]}
+		path, value, ok := o.override(settings, strings.Split(strings.ToUpper(env[:i]), "_"))
+		if !ok || len(path) < 2 {
+			continue
+		}
+		key := strings.Join(path, ".")
+		v.Set(key, value)
+		if len(keys) == 0 || keys[len(keys)-1] != key {
+			keys = append(keys, key)
+		}
+		settings = v.AllSettings()
+	}
+	return keys
+
+}
+
+// envOverride sets a value in the config tree from the segments of an environment variable name
+type envOverride struct {
+	value string
+}
+
+// override finds the key for the segments in the maps of the config, lists are replaced as a whole
+func (o *envOverride) override(node interface{}, segs []string) ([]string, interface{}, bool) {
+
+	m, ok := toStringMap(node)
+	if !ok {
+		return nil, nil, false
+	}
+	for _, k := range sortedKeys(m) {
+		for n := 1; n <= len(segs); n++ {
+			if envKey(k) != strings.Join(segs[:n], "") {
+				continue
+			}
+			child, rest := m[k], segs[n:]
+			if isList(child) {
+				if value, ok := o.replace(child, rest); ok {
+					return []string{k}, value, true
+				}
+			} else if _, isMap := toStringMap(child); isMap && len(rest) > 0 {
+				if path, value, ok := o.override(child, rest); ok {
+					return append([]string{k}, path...), value, true
+				}
+			} else if len(rest) == 0 {
+				if value, ok := o.parse(child); ok {
+					return []string{k}, value, true
+				}
+			}
+		}
+	}
+	return nil, nil, false
+
+}
+
+// replace returns a copy of the node with the value set. Keys that don't exist in list entries are added with the
+// segments joined, config structs match them to fields ignoring case.
+func (o *envOverride) replace(node interface{}, segs []string) (interface{}, bool) {
+
+	if len(segs) == 0 {
+		return o.parse(node)
+	}
+
+	if m, ok := toStringMap(node); ok {
+		for _, k := range sortedKeys(m) {
+			for n := 1; n <= len(segs); n++ {
+				if envKey(k) != strings.Join(segs[:n], "") {
+					continue
+				}
+				if value, ok := o.replace(m[k], segs[n:]); ok {
+					m[k] = value
+					return m, true
+				}
+			}
+		}
+		value, _ := o.parse(nil)
+		m[strings.ToLower(strings.Join(segs, ""))] = value
+		return m, true
+	}
+
+	list, ok := toList(node)
+	if !ok {
+		return nil, false
+	}
+
+	// By index, one past the end adds an entry
+	if index, err := strconv.Atoi(segs[0]); err == nil {
+		switch {
+		case index >= 0 && index < len(list):
+			if value, ok := o.replace(list[index], segs[1:]); ok {
+				list[index] = value
+				return list, true
+			}
+		case index == len(list):
+			if value, ok := o.replace(map[string]interface{}{}, segs[1:]); ok {
+				return append(list, value), true
+			}
+		}
+		return nil, false
+	}
+
+	// By name
+	for i, entry := range list {
+		m, ok := toStringMap(entry)
+		if !ok {
+			continue
+		}
+		name := strings.Split(envName(fmt.Sprint(m["name"])), "_")
+		if m["name"] == nil || len(name) > len(segs) || strings.Join(name, "_") != strings.Join(segs[:len(name)], "_") {
+			continue
+		}
+		if value, ok := o.replace(entry, segs[len(name):]); ok {
+			list[i] = value
+			return list, true
+		}
+	}
+	return nil, false
+
+}
+
+// parse returns the value, JSON if it looks like it. Lists of values can also be split on spaces, other lists are
+// only JSON.
+func (o *envOverride) parse(current interface{}) (interface{}, bool) {
+	value := strings.TrimSpace(o.value)
+	if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+		var ret interface{}
+		if err := json.Unmarshal([]byte(value), &ret); err == nil {
+			return ret, true
+		}
+	}
+	if list, ok := toList(current); ok {
+		if t := reflect.TypeOf(current).Elem().Kind(); t != reflect.String && t != reflect.Interface {
+			return nil, false
+		}
+		for _, v := range list {
+			if isList(v) || v == nil {
+				return nil, false
+			}
+			if _, isMap := toStringMap(v); isMap {
+				return nil, false
+			}
+		}
+		return strings.Fields(value), true
+	}
+	return o.value, true
+}
+
+// envKey is a config key as it's matched in a variable name, upper case without separators
+func envKey(key string) string {
+	return strings.Replace(envName(key), "_", "", -1)
+}
+
+// envName is a list entry name as it's matched in a variable name, upper case with underscores for other characters
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// toStringMap returns a copy of a config map
+func toStringMap(node interface{}) (map[string]interface{}, bool) {
+	switch m := node.(type) {
+	case map[string]interface{}:
+		ret := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			ret[k] = v
+		}
+		return ret, true
+	case map[interface{}]interface{}:
+		ret := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			ret[fmt.Sprint(k)] = v
+		}
+		return ret, true
+	}
+	return nil, false
+}
+
+// toList returns a copy of a config list, the defaults are typed slices
+func toList(node interface{}) ([]interface{}, bool) {
+	if !isList(node) {
+		return nil, false
+	}
+	v := reflect.ValueOf(node)
+	ret := make([]interface{}, v.Len())
+	for i := range ret {
+		ret[i] = v.Index(i).Interface()
+	}
+	return ret, true
+}
+
+func isList(node interface{}) bool {
+	return node != nil && reflect.TypeOf(node).Kind() == reflect.Slice
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package conf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	config "github.com/spf13/viper"
+)
+
+const envTestConfig = `
+doods:
+  detectors:
+    - name: default
+      type: tflite
+      numThreads: 1
+    - name: front-door
+      type: tensorflow
+  sinks: []
+  fetch:
+    allowed_hosts: [camera]
+server:
+  port: 8080
+  listeners:
+    - address: ":9000"
+`
+
+func TestApplyEnv(t *testing.T) {
+
+	v := config.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(envTestConfig)); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := applyEnv(v, []string{
+		"DOODS_DETECTORS_DEFAULT_NUMTHREADS=4",
+		"DOODS_DETECTORS_FRONT_DOOR_HWACCEL=true",
+		"DOODS_DETECTORS_2_NAME=extra",
+		"DOODS_DETECTORS_2_TYPE=tflite",
+		"DOODS_DETECTORS_5_NAME=skipped", // Not the next index
+		"DOODS_FETCH_ALLOWED_HOSTS=camera nvr",
+		`DOODS_SINKS=[{"name": "hook", "type": "webhook"}]`,
+		"SERVER_PORT=9090",
+		"SERVER_LISTENERS_0_AUTHKEY=secret",
+		"DOODS_UNKNOWN=1",
+		"PATH=/usr/bin",
+		"NOVALUE",
+		"=empty",
+	})
+
+	expectedKeys := []string{"doods.detectors", "doods.fetch.allowed_hosts", "doods.sinks", "server.listeners", "server.port"}
+	if !reflect.DeepEqual(keys, expectedKeys) {
+		t.Errorf("keys %v, expected %v", keys, expectedKeys)
+	}
+
+	detectors := v.Get("doods.detectors").([]interface{})
+	if len(detectors) != 3 {
+		t.Fatalf("detectors %v", detectors)
+	}
+	for i, expected := range []map[string]interface{}{
+		{"name": "default", "type": "tflite", "numThreads": "4"},
+		{"name": "front-door", "type": "tensorflow", "hwaccel": "true"},
+		{"name": "extra", "type": "tflite"},
+	} {
+		detector, _ := toStringMap(detectors[i])
+		if !reflect.DeepEqual(detector, expected) {
+			t.Errorf("detector %d is %v, expected %v", i, detector, expected)
+		}
+	}
+
+	if hosts := v.GetStringSlice("doods.fetch.allowed_hosts"); !reflect.DeepEqual(hosts, []string{"camera", "nvr"}) {
+		t.Errorf("allowed hosts %v", hosts)
+	}
+	if port := v.GetString("server.port"); port != "9090" {
+		t.Errorf("port %v", port)
+	}
+	sinks, _ := toList(v.Get("doods.sinks"))
+	if len(sinks) != 1 || !reflect.DeepEqual(sinks[0], map[string]interface{}{"name": "hook", "type": "webhook"}) {
+		t.Errorf("sinks %v", sinks)
+	}
+	listeners, _ := toList(v.Get("server.listeners"))
+	if len(listeners) != 1 {
+		t.Fatalf("listeners %v", listeners)
+	}
+	if listener, _ := toStringMap(listeners[0]); !reflect.DeepEqual(listener, map[string]interface{}{"address": ":9000", "authkey": "secret"}) {
+		t.Errorf("listener %v", listener)
+	}
+
+}
+
+func TestEnvName(t *testing.T) {
+
+	for name, expected := range map[string]string{
+		"default":    "DEFAULT",
+		"front-door": "FRONT_DOOR",
+		"Cam 2.back": "CAM_2_BACK",
+	} {
+		if got := envName(name); got != expected {
+			t.Errorf("envName(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+	if got := envKey("allowed_hosts"); got != "ALLOWEDHOSTS" {
+		t.Errorf("envKey(allowed_hosts) = %q", got)
+	}
+
+}
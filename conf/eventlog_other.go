@@ -0,0 +1,15 @@
+//go:build !windows
+// +build !windows
+
+package conf
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// eventLogCore is only supported on Windows
+func eventLogCore(source string, level zapcore.Level, encoderConfig zapcore.EncoderConfig) (zapcore.Core, error) {
+	return nil, fmt.Errorf("the event log is only supported on windows")
+}
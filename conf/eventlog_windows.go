@@ -0,0 +1,70 @@
+//go:build windows
+// +build windows
+
+package conf
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventLog writes log entries to the Windows event log
+type eventLog struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	log     *eventlog.Log
+}
+
+// eventLogCore returns a core that writes the entries at or above the level to the event log source
+func eventLogCore(source string, level zapcore.Level, encoderConfig zapcore.EncoderConfig) (zapcore.Core, error) {
+	l, err := eventlog.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	// The event log has its own time and level, no colors
+	encoderConfig.TimeKey = ""
+	encoderConfig.LevelKey = ""
+	return &eventLog{
+		LevelEnabler: level,
+		encoder:      zapcore.NewConsoleEncoder(encoderConfig),
+		log:          l,
+	}, nil
+}
+
+func (c *eventLog) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.encoder = c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(clone.encoder)
+	}
+	return &clone
+}
+
+func (c *eventLog) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *eventLog) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSpace(buf.String())
+	buf.Free()
+	switch {
+	case entry.Level >= zapcore.ErrorLevel:
+		return c.log.Error(1, msg)
+	case entry.Level == zapcore.WarnLevel:
+		return c.log.Warning(1, msg)
+	}
+	return c.log.Info(1, msg)
+}
+
+func (c *eventLog) Sync() error {
+	return nil
+}
@@ -0,0 +1,79 @@
+package conf
+
+import (
+	"context"
+	"sync"
+)
+
+// Lifecycle cancels running work when it is stopped and waits for the work to finish. Components get a
+// child so they can be stopped and restarted without stopping the process.
+type Lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// wg counts the work running in this lifecycle and its children
+	wg     sync.WaitGroup
+	parent *Lifecycle
+}
+
+// NewLifecycle creates a root lifecycle
+func NewLifecycle() *Lifecycle {
+	l := new(Lifecycle)
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	return l
+}
+
+// Child creates a lifecycle that is stopped with this one. Work in the child is also waited for by this one.
+func (l *Lifecycle) Child() *Lifecycle {
+	c := &Lifecycle{parent: l}
+	c.ctx, c.cancel = context.WithCancel(l.ctx)
+	return c
+}
+
+// Context returns a context that is canceled when stopping
+func (l *Lifecycle) Context() context.Context {
+	return l.ctx
+}
+
+// Done returns a channel that is closed when stopping
+func (l *Lifecycle) Done() <-chan struct{} {
+	return l.ctx.Done()
+}
+
+// Stopping returns true once stopped
+func (l *Lifecycle) Stopping() bool {
+	return l.ctx.Err() != nil
+}
+
+// Stop cancels the lifecycle and its children
+func (l *Lifecycle) Stop() {
+	l.cancel()
+}
+
+// Track counts work that should finish before exiting. Call the returned func when it's done.
+func (l *Lifecycle) Track() func() {
+	for p := l; p != nil; p = p.parent {
+		p.wg.Add(1)
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for p := l; p != nil; p = p.parent {
+				p.wg.Done()
+			}
+		})
+	}
+}
+
+// Go runs the func in a goroutine that is waited for before exiting
+func (l *Lifecycle) Go(f func(ctx context.Context)) {
+	done := l.Track()
+	go func() {
+		defer done()
+		f(l.ctx)
+	}()
+}
+
+// Wait waits for the tracked work to finish
+func (l *Lifecycle) Wait() {
+	l.wg.Wait()
+}
@@ -7,9 +7,13 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// The level of the logger, it changes with logger.level when the config is watched
+var atomicLevel zap.AtomicLevel
+
 func InitLogger() {
 
 	logConfig := zap.NewProductionConfig()
+	atomicLevel = logConfig.Level
 
 	// Log Level
 	var logLevel zapcore.Level
@@ -48,6 +52,33 @@ func InitLogger() {
 
 	// Build the logger
 	globalLogger, _ := logConfig.Build()
+
+	// Also log to the Windows event log
+	if source := config.GetString("logger.eventlog"); source != "" {
+		var eventLevel zapcore.Level
+		if err := eventLevel.Set(config.GetString("logger.eventlog_level")); err != nil {
+			zap.S().Fatalw("Could not determine logger.eventlog_level", "error", err)
+		}
+		core, err := eventLogCore(source, eventLevel, logConfig.EncoderConfig)
+		if err != nil {
+			globalLogger.Sugar().Warnw("Could not open the event log", "source", source, "error", err)
+		} else {
+			globalLogger = globalLogger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+				return zapcore.NewTee(c, core)
+			}))
+		}
+	}
 	zap.ReplaceGlobals(globalLogger)
 
 }
+
+func init() {
+	OnConfigChange(func() {
+		var level zapcore.Level
+		if err := level.Set(Live().GetString("logger.level")); err != nil {
+			zap.S().Errorw("Could not determine logger.level", "error", err)
+			return
+		}
+		atomicLevel.SetLevel(level)
+	}, "logger.level")
+}
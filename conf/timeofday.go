@@ -0,0 +1,29 @@
+package conf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTimeOfDay parses HH:MM (or H:MM) into the duration since midnight for the time windows in the config. 24:00 is
+// the end of the day.
+func ParseTimeOfDay(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 || len(parts[0]) < 1 || len(parts[0]) > 2 || len(parts[1]) != 2 {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	hour, err := strconv.ParseUint(parts[0], 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	minute, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	if hour > 24 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time of day %q, it must be from 00:00 to 24:00", s)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
@@ -0,0 +1,39 @@
+package conf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeOfDay(t *testing.T) {
+
+	for _, test := range []struct {
+		value    string
+		expected time.Duration
+		valid    bool
+	}{
+		{"00:00", 0, true},
+		{"06:30", 6*time.Hour + 30*time.Minute, true},
+		{"7:05", 7*time.Hour + 5*time.Minute, true},
+		{"23:59", 23*time.Hour + 59*time.Minute, true},
+		{"24:00", 24 * time.Hour, true},
+		{"24:01", 0, false},
+		{"25:00", 0, false},
+		{"12:60", 0, false},
+		{"0700", 0, false},
+		{"07:0", 0, false},
+		{"07:00:00", 0, false},
+		{"-1:00", 0, false},
+		{"+1:00", 0, false},
+		{"aa:bb", 0, false},
+		{"", 0, false},
+	} {
+		d, err := ParseTimeOfDay(test.value)
+		if test.valid && (err != nil || d != test.expected) {
+			t.Errorf("%q parsed as %v, %v, expected %v", test.value, d, err, test.expected)
+		} else if !test.valid && err == nil {
+			t.Errorf("%q parsed as %v, expected an error", test.value, d)
+		}
+	}
+
+}
@@ -0,0 +1,187 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// The config file is often written in several steps, changes are applied once it settles
+const watchSettle = time.Second
+
+type changeHandler struct {
+	prefixes []string
+	apply    func()
+}
+
+var (
+	changeHandlers []*changeHandler
+	changeLock     sync.Mutex
+)
+
+// OnConfigChange calls apply when a watched config file changes any key starting with one of the prefixes, apply reads
+// the new values from Live. Changes to keys without a handler are logged as needing a restart.
+func OnConfigChange(apply func(), prefixes ...string) {
+	changeLock.Lock()
+	changeHandlers = append(changeHandlers, &changeHandler{prefixes: prefixes, apply: apply})
+	changeLock.Unlock()
+}
+
+// live is the config from the latest change to the file
+var live atomic.Value
+
+// Live returns the config with the latest changes to the config file. Only the keys with an OnConfigChange handler
+// should be read from it, the global config is not changed after startup so the other keys keep the startup values
+// until a restart.
+func Live() *config.Viper {
+	if v, ok := live.Load().(*config.Viper); ok {
+		return v
+	}
+	return config.GetViper()
+}
+
+// WatchConfig reloads the config file when it changes. It watches the directory of the file so it also sees a
+// Kubernetes ConfigMap update, which swaps a symlink instead of writing the file.
+func WatchConfig() {
+
+	logger := zap.S().With("package", "conf")
+	filename := filepath.Clean(config.ConfigFileUsed())
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Errorw("Could not watch config", "file", filename, "error", err)
+		return
+	}
+	if err = watcher.Add(filepath.Dir(filename)); err != nil {
+		logger.Errorw("Could not watch config", "file", filename, "error", err)
+		watcher.Close()
+		return
+	}
+
+	go func() {
+		current := flatSettings(config.GetViper())
+		realFile, _ := filepath.EvalSymlinks(filename)
+		var settle <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				newRealFile, _ := filepath.EvalSymlinks(filename)
+				written := filepath.Clean(event.Name) == filename && event.Op&(fsnotify.Write|fsnotify.Create) != 0
+				if written || (newRealFile != "" && newRealFile != realFile) {
+					realFile = newRealFile
+					settle = time.After(watchSettle)
+				}
+			case <-settle:
+				settle = nil
+				current = reloadConfig(logger, filename, current)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logger.Warnw("Could not watch config", "file", filename, "error", err)
+			}
+		}
+	}()
+
+	logger.Infow("Watching config", "file", filename)
+
+}
+
+// reloadConfig reads the file into a new config, stores it for Live and calls the handlers of the changed keys. It
+// returns the new settings.
+func reloadConfig(logger *zap.SugaredLogger, filename string, current map[string]interface{}) map[string]interface{} {
+
+	v := config.New()
+	setDefaults(v)
+	v.SetConfigFile(filename)
+	if err := v.ReadInConfig(); err != nil {
+		logger.Errorw("Could not read config, keeping the current config", "file", filename, "error", err)
+		return current
+	}
+	// The environment still overrides the new file
+	applyEnv(v, os.Environ())
+
+	settings := flatSettings(v)
+	changed := changedKeys(current, settings)
+	if len(changed) == 0 {
+		return settings
+	}
+	live.Store(v)
+	logger.Infow("Config changed", "file", filename, "keys", changed)
+
+	changeLock.Lock()
+	handlers := changeHandlers
+	changeLock.Unlock()
+
+	var restart []string
+	for _, key := range changed {
+		handled := false
+		for _, h := range handlers {
+			handled = handled || h.handles(key)
+		}
+		if !handled {
+			restart = append(restart, key)
+		}
+	}
+	for _, h := range handlers {
+		for _, key := range changed {
+			if h.handles(key) {
+				h.apply()
+				break
+			}
+		}
+	}
+	if len(restart) > 0 {
+		logger.Warnw("Restart to apply config changes", "keys", restart)
+	}
+
+	return settings
+
+}
+
+func (h *changeHandler) handles(key string) bool {
+	for _, prefix := range h.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// flatSettings returns the config values by key, lists are values
+func flatSettings(v *config.Viper) map[string]interface{} {
+	ret := make(map[string]interface{})
+	for _, key := range v.AllKeys() {
+		ret[key] = v.Get(key)
+	}
+	return ret
+}
+
+// changedKeys returns the sorted keys with different values
+func changedKeys(before, after map[string]interface{}) []string {
+	var ret []string
+	for key, value := range after {
+		if old, ok := before[key]; !ok || !reflect.DeepEqual(old, value) {
+			ret = append(ret, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			ret = append(ret, key)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
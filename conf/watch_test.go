@@ -0,0 +1,83 @@
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func TestReloadConfig(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "config.yaml")
+
+	applied := 0
+	OnConfigChange(func() { applied++ }, "watchtest.")
+
+	// Read the live config while reloading
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				Live().GetString("watchtest.value")
+			}
+		}
+	}()
+
+	logger := zap.S()
+	current := flatSettings(config.GetViper())
+	for _, test := range []struct {
+		file    string
+		value   string
+		applied int
+	}{
+		{"watchtest:\n  value: one\n", "one", 1},
+		{"watchtest:\n  value: one\n", "one", 1}, // Unchanged
+		{"watchtest: [\n", "one", 1},             // Invalid, keeps the current config
+		{"watchtest:\n  value: two\n", "two", 2},
+		{"logger:\n  level: info\n", "", 3}, // Removed
+	} {
+		if err = ioutil.WriteFile(filename, []byte(test.file), 0644); err != nil {
+			t.Fatal(err)
+		}
+		current = reloadConfig(logger, filename, current)
+		if value := Live().GetString("watchtest.value"); value != test.value || applied != test.applied {
+			t.Errorf("%q: value %q applied %d, expected %q applied %d", test.file, value, applied, test.value, test.applied)
+		}
+	}
+	close(done)
+	<-stopped
+
+	// The global config doesn't change
+	if value := config.GetString("watchtest.value"); value != "" {
+		t.Errorf("global config changed to %q", value)
+	}
+
+}
+
+func TestChangedKeys(t *testing.T) {
+
+	before := map[string]interface{}{"a": 1, "b": []string{"x"}, "c": "same"}
+	after := map[string]interface{}{"b": []string{"x", "y"}, "c": "same", "d": true}
+	if changed := changedKeys(before, after); !reflect.DeepEqual(changed, []string{"a", "b", "d"}) {
+		t.Errorf("changed %v", changed)
+	}
+	if changed := changedKeys(after, after); changed != nil {
+		t.Errorf("changed %v", changed)
+	}
+
+}
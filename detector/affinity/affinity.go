@@ -0,0 +1,145 @@
+// Package affinity places the threads running detections on specific CPUs, such as the performance cores of
+// big.LITTLE processors or the CPUs of a NUMA node.
+package affinity
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const sysfsPath = "/sys/devices/system"
+
+// Parse returns the CPUs for the spec:
+//
+//	performance (or big) - the cores with the highest capacity
+//	efficiency (or little) - the other cores
+//	node:N - the CPUs of NUMA node N
+//	a cpu list like 4-7 or 0,2,4
+func Parse(spec string) ([]int, error) {
+
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	switch {
+	case spec == "":
+		return nil, nil
+	case spec == "performance" || spec == "big":
+		return cores(true)
+	case spec == "efficiency" || spec == "little":
+		return cores(false)
+	case strings.HasPrefix(spec, "node:"):
+		node, err := strconv.Atoi(strings.TrimPrefix(spec, "node:"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid node: %s", spec)
+		}
+		data, err := ioutil.ReadFile(filepath.Join(sysfsPath, "node", "node"+strconv.Itoa(node), "cpulist"))
+		if err != nil {
+			return nil, fmt.Errorf("could not read node %d: %v", node, err)
+		}
+		return parseList(string(data))
+	}
+	return parseList(spec)
+
+}
+
+// cores returns the performance or efficiency cores by their capacity, or max frequency if capacity isn't available
+func cores(performance bool) ([]int, error) {
+
+	capacity := make(map[int]int)
+	for cpu := 0; cpu < runtime.NumCPU(); cpu++ {
+		dir := filepath.Join(sysfsPath, "cpu", "cpu"+strconv.Itoa(cpu))
+		value, err := readInt(filepath.Join(dir, "cpu_capacity"))
+		if err != nil {
+			value, err = readInt(filepath.Join(dir, "cpufreq", "cpuinfo_max_freq"))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not determine the capacity of cpu %d: %v", cpu, err)
+		}
+		capacity[cpu] = value
+	}
+
+	var max int
+	for _, value := range capacity {
+		if value > max {
+			max = value
+		}
+	}
+
+	var cpus []int
+	for cpu, value := range capacity {
+		if (value == max) == performance {
+			cpus = append(cpus, cpu)
+		}
+	}
+	if len(cpus) == 0 {
+		return nil, fmt.Errorf("no efficiency cores found")
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+
+}
+
+// parseList parses a cpu list like 0-3,6
+func parseList(list string) ([]int, error) {
+	var cpus []int
+	for _, part := range strings.Split(strings.TrimSpace(list), ",") {
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu list: %s", list)
+		}
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.Atoi(bounds[1]); err != nil || end < start {
+				return nil, fmt.Errorf("invalid cpu list: %s", list)
+			}
+		}
+		for cpu := start; cpu <= end; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	if len(cpus) == 0 {
+		return nil, fmt.Errorf("empty cpu list")
+	}
+	return cpus, nil
+}
+
+func readInt(filename string) (int, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+// Run runs f on an OS thread bound to the cpus. Threads created by f (like the tflite thread pool) inherit the placement.
+// If cpus is empty, f is run normally.
+func Run(cpus []int, f func()) error {
+
+	if len(cpus) == 0 {
+		f()
+		return nil
+	}
+
+	runtime.LockOSThread()
+	previous, err := set(cpus)
+	if err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+
+	f()
+
+	// If the placement can't be restored, leave the thread locked so it exits with the goroutine
+	if _, err := set(previous); err == nil {
+		runtime.UnlockOSThread()
+	}
+	return nil
+
+}
@@ -0,0 +1,33 @@
+package affinity
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// set binds the current thread to the cpus and returns the previous cpus
+func set(cpus []int) ([]int, error) {
+
+	var current unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &current); err != nil {
+		return nil, fmt.Errorf("could not get cpu affinity: %v", err)
+	}
+	var previous []int
+	for cpu := 0; cpu < len(current)*64; cpu++ {
+		if current.IsSet(cpu) {
+			previous = append(previous, cpu)
+		}
+	}
+
+	var set unix.CPUSet
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+	if err := unix.SchedSetaffinity(0, &set); err != nil {
+		return nil, fmt.Errorf("could not set cpu affinity: %v", err)
+	}
+
+	return previous, nil
+
+}
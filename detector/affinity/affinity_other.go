@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package affinity
+
+import (
+	"fmt"
+)
+
+// set is only supported on linux
+func set(cpus []int) ([]int, error) {
+	return nil, fmt.Errorf("cpu affinity is not supported on this platform")
+}
@@ -0,0 +1,67 @@
+// Package annotate draws detections on images
+package annotate
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"gocv.io/x/gocv"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+var (
+	boxColor  = color.RGBA{R: 0, G: 255, B: 0, A: 0}
+	textColor = color.RGBA{R: 0, G: 0, B: 0, A: 0}
+)
+
+// Draw draws the detection boxes and labels on the image
+func Draw(img *gocv.Mat, detections []*odrpc.Detection) {
+
+	width, height := float32(img.Cols()), float32(img.Rows())
+	thickness := int(width/400) + 1
+	scale := float64(width) / 1000
+	if scale < 0.4 {
+		scale = 0.4
+	}
+
+	for _, d := range detections {
+		rect := image.Rect(int(d.Left*width), int(d.Top*height), int(d.Right*width), int(d.Bottom*height))
+		gocv.Rectangle(img, rect, boxColor, thickness)
+
+		// Label with a background
+		text := fmt.Sprintf("%s %.0f%%", d.Label, d.Confidence)
+		size := gocv.GetTextSize(text, gocv.FontHersheySimplex, scale, thickness)
+		top := rect.Min.Y
+		if top < size.Y+2*thickness {
+			top = size.Y + 2*thickness
+		}
+		gocv.Rectangle(img, image.Rect(rect.Min.X, top-size.Y-2*thickness, rect.Min.X+size.X+2*thickness, top), boxColor, -1)
+		gocv.PutText(img, text, image.Pt(rect.Min.X+thickness, top-thickness), gocv.FontHersheySimplex, scale, textColor, thickness)
+	}
+
+}
+
+// Image decodes the image data, draws the detections and returns it as a JPEG.
+// If width is > 0 the image will be scaled to that width.
+func Image(data []byte, detections []*odrpc.Detection, width int) ([]byte, error) {
+
+	img, err := gocv.IMDecode(data, gocv.IMReadColor)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %v", err)
+	}
+	defer img.Close()
+	if img.Empty() {
+		return nil, fmt.Errorf("could not decode image")
+	}
+
+	if width > 0 && width < img.Cols() {
+		gocv.Resize(img, &img, image.Point{X: width, Y: img.Rows() * width / img.Cols()}, 0, 0, gocv.InterpolationArea)
+	}
+
+	Draw(&img, detections)
+
+	return gocv.IMEncode(gocv.JPEGFileExt, img)
+
+}
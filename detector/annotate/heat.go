@@ -0,0 +1,81 @@
+package annotate
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+
+	xdraw "golang.org/x/image/draw"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The opacity of the density map over the image
+const densityOpacity = 0.6
+
+// Density decodes the image data, draws the density map over it and returns it as a JPEG. The colors are relative to
+// the densest cell. If width is > 0 the image will be scaled to that width.
+func Density(data []byte, density *odrpc.Density, width int) ([]byte, error) {
+
+	base, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %v", err)
+	}
+	b := base.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if width > 0 && width < w {
+		w, h = width, h*width/w
+	}
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.ApproxBiLinear.Scale(out, out.Bounds(), base, b, xdraw.Src, nil)
+
+	dw, dh := int(density.Width), int(density.Height)
+	if dw > 0 && dh > 0 && len(density.Values) == dw*dh {
+		var peak float32
+		for _, v := range density.Values {
+			if v > peak {
+				peak = v
+			}
+		}
+		if peak > 0 {
+			heat := image.NewRGBA(image.Rect(0, 0, dw, dh))
+			for y := 0; y < dh; y++ {
+				for x := 0; x < dw; x++ {
+					heat.SetRGBA(x, y, HeatColor(density.Values[y*dw+x]/peak, densityOpacity))
+				}
+			}
+			// Smooth the cells, the maps are much smaller than the image
+			xdraw.BiLinear.Scale(out, out.Bounds(), heat, heat.Bounds(), xdraw.Over, nil)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, out, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+
+}
+
+// HeatColor maps 0-1 from transparent blue through green and yellow to red (premultiplied alpha)
+func HeatColor(v float32, opacity float32) color.RGBA {
+	if v <= 0 {
+		return color.RGBA{}
+	}
+	var r, g, b float32
+	switch {
+	case v < 0.25:
+		b, g = 1, v/0.25
+	case v < 0.5:
+		g, b = 1, 1-(v-0.25)/0.25
+	case v < 0.75:
+		g, r = 1, (v-0.5)/0.25
+	default:
+		r, g = 1, 1-(v-0.75)/0.25
+	}
+	a := opacity * (0.3 + 0.7*v)
+	return color.RGBA{R: uint8(r * a * 255), G: uint8(g * a * 255), B: uint8(b * a * 255), A: uint8(a * 255)}
+}
@@ -0,0 +1,131 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sort"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// attributeClassifier runs a classifier on the area of the detections and adds the labels it returns as attributes
+type attributeClassifier struct {
+	name          string
+	detector      string
+	labels        map[string]struct{}
+	minConfidence float32
+	padding       float32
+	threshold     float32
+	multiple      bool
+}
+
+func newAttributeClassifier(c *dconfig.AttributeConfig) *attributeClassifier {
+	a := &attributeClassifier{
+		name:          c.Name,
+		detector:      c.Detector,
+		minConfidence: c.MinConfidence,
+		padding:       c.Padding,
+		threshold:     c.Threshold,
+		multiple:      c.Multiple,
+	}
+	if a.name == "" {
+		a.name = a.detector
+	}
+	if a.padding <= 0 {
+		a.padding = 0.1
+	}
+	if a.threshold <= 0 {
+		a.threshold = 50
+	}
+	if len(c.Labels) > 0 {
+		a.labels = make(map[string]struct{}, len(c.Labels))
+		for _, label := range c.Labels {
+			a.labels[label] = struct{}{}
+		}
+	}
+	return a
+}
+
+// matches returns true if the detection should be classified
+func (a *attributeClassifier) matches(d *odrpc.Detection) bool {
+	if d.Confidence < a.minConfidence {
+		return false
+	}
+	if a.labels == nil {
+		return true
+	}
+	_, ok := a.labels[d.Label]
+	return ok
+}
+
+// classifyAttributes runs the attribute classifiers of the detector on the matching detections. A classifier that
+// fails is logged and leaves the detections without its attribute.
+func (m *Mux) classifyAttributes(ctx context.Context, request *odrpc.DetectRequest, detector *muxDetector, img image.Image, detections []*odrpc.Detection) {
+
+	b := img.Bounds()
+	for _, a := range detector.attributes {
+		classifier, ok := m.detectors[a.detector]
+		if !ok {
+			m.logger.Warnw("Attribute detector not found", "detector", request.DetectorName, "attribute", a.name, "attribute_detector", a.detector)
+			continue
+		}
+		for _, d := range detections {
+			if ctx.Err() != nil {
+				return
+			}
+			if !a.matches(d) {
+				continue
+			}
+			area := cropArea(b, &odrpc.Box{Top: d.Top, Left: d.Left, Bottom: d.Bottom, Right: d.Right}, a.padding)
+			if area.Empty() {
+				continue
+			}
+			data, err := encodeCrop(img, area)
+			if err != nil {
+				m.logger.Warnw("Could not encode attribute crop", "id", request.Id, "attribute", a.name, "error", err)
+				continue
+			}
+			attributeRequest := &odrpc.DetectRequest{
+				Id:           request.Id,
+				DetectorName: a.detector,
+				Data:         data,
+			}
+			response, _, err := m.detectWithFallback(ctx, classifier, attributeRequest)
+			if err == nil && response.Error != "" {
+				err = fmt.Errorf("%s", response.Error)
+			}
+			if err != nil {
+				m.logger.Warnw("Could not classify attribute", "id", request.Id, "attribute", a.name, "error", err)
+				continue
+			}
+			classifier.IgnoreResponse(attributeRequest, response)
+			d.Attributes = append(d.Attributes, a.values(response.Detections)...)
+		}
+	}
+
+}
+
+// values returns the classifier labels over the threshold as attributes, the best one unless it's multiple
+func (a *attributeClassifier) values(detections []*odrpc.Detection) []*odrpc.Attribute {
+	var ret []*odrpc.Attribute
+	seen := make(map[string]struct{})
+	sorted := append([]*odrpc.Detection(nil), detections...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Confidence > sorted[j].Confidence })
+	for _, d := range sorted {
+		if d.Confidence < a.threshold {
+			break
+		}
+		// A classifier can return the same label for more than one box
+		if _, ok := seen[d.Label]; ok {
+			continue
+		}
+		seen[d.Label] = struct{}{}
+		ret = append(ret, &odrpc.Attribute{Name: a.name, Value: d.Label, Confidence: d.Confidence})
+		if !a.multiple {
+			break
+		}
+	}
+	return ret
+}
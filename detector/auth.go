@@ -9,13 +9,14 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
 )
 
 // AuthFuncOverride will handle authentication
 func (m *Mux) AuthFuncOverride(ctx context.Context, fullMethodName string) (context.Context, error) {
 
 	// Auth disabled
-	if m.authKey == "" {
+	if !m.keys.Enabled() {
 		return ctx, nil
 	}
 
@@ -25,8 +26,8 @@ func (m *Mux) AuthFuncOverride(ctx context.Context, fullMethodName string) (cont
 		return ctx, status.Errorf(codes.PermissionDenied, "Permission Denied")
 	}
 
-	// Get the user pubKeyString
-	if mdfirst(md, odrpc.DoodsAuthKeyHeader) != m.authKey {
+	// All of the detector methods need a read key
+	if !m.keys.Allowed(ctx, mdfirst(md, odrpc.DoodsAuthKeyHeader), server.RoleRead) {
 		return ctx, status.Errorf(codes.PermissionDenied, "Invalid Login")
 	}
 
@@ -0,0 +1,183 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/draw"
+	"io/ioutil"
+	"sort"
+
+	config "github.com/spf13/viper"
+	"golang.org/x/image/bmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// DetectBoxes runs the detector on each of the boxes in the image. The detections are in the coordinates of the
+// whole image so a classifier returns the box it was given.
+func (m *Mux) DetectBoxes(ctx context.Context, request *odrpc.DetectBoxesRequest) (*odrpc.DetectBoxesResponse, error) {
+
+	if request.DetectorName == "" {
+		request.DetectorName = "default"
+	}
+	detector, ok := m.detectors[request.DetectorName]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "not found")
+	}
+	if len(request.Boxes) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no boxes")
+	}
+	if max := config.GetInt("doods.boxes.max"); max > 0 && len(request.Boxes) > max {
+		return nil, status.Errorf(codes.InvalidArgument, "too many boxes %d, the max is %d", len(request.Boxes), max)
+	}
+
+	var err error
+	if len(request.File) != 0 {
+		request.Data, err = ioutil.ReadFile(request.File)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "could not open file %s", request.File)
+		}
+	}
+	if request.ImageUrl != "" {
+		request.Data, err = m.fetcher.fetch(ctx, request.ImageUrl)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+	if err = m.limits.check(request.Data); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(request.Data))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not decode image: %v", err)
+	}
+	b := img.Bounds()
+	if b.Empty() {
+		return nil, status.Errorf(codes.InvalidArgument, "empty image")
+	}
+
+	response := &odrpc.DetectBoxesResponse{
+		Id:      request.Id,
+		Results: make([]*odrpc.BoxResult, 0, len(request.Boxes)),
+	}
+	for i, box := range request.Boxes {
+		if ctx.Err() != nil {
+			return nil, status.Errorf(codes.Canceled, "detect canceled")
+		}
+		if box == nil {
+			box = &odrpc.Box{}
+		}
+		// Pixel boxes are converted to relative coordinates
+		if request.Pixels {
+			w, h := float32(b.Dx()), float32(b.Dy())
+			box = &odrpc.Box{Top: box.Top / h, Left: box.Left / w, Bottom: box.Bottom / h, Right: box.Right / w}
+		}
+		result := m.detectBox(ctx, detector, request, img, box)
+		result.Index = int32(i)
+		response.Results = append(response.Results, result)
+	}
+
+	m.logger.Infow("Detected boxes", "id", request.Id, "detector", request.DetectorName, "boxes", len(request.Boxes))
+
+	return response, nil
+
+}
+
+// detectBox runs the detector on the area of the box
+func (m *Mux) detectBox(ctx context.Context, detector *muxDetector, request *odrpc.DetectBoxesRequest, img image.Image, box *odrpc.Box) *odrpc.BoxResult {
+
+	result := &odrpc.BoxResult{
+		Detections: []*odrpc.Detection{},
+	}
+
+	area := cropArea(img.Bounds(), box, request.Padding)
+	result.Box = relativeBox(img.Bounds(), area)
+	if area.Empty() {
+		result.Error = "empty box"
+		return result
+	}
+	data, err := encodeCrop(img, area)
+	if err != nil {
+		result.Error = "could not encode crop: " + err.Error()
+		return result
+	}
+
+	boxRequest := &odrpc.DetectRequest{
+		Id:           request.Id,
+		DetectorName: request.DetectorName,
+		Data:         data,
+		Detect:       request.Detect,
+		Language:     request.Language,
+		Ignore:       request.Ignore,
+	}
+	response, _, err := m.detectWithFallback(ctx, detector, boxRequest)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if response.Error != "" {
+		result.Error = response.Error
+		return result
+	}
+	detector.IgnoreResponse(boxRequest, response)
+	m.FilterResponse(boxRequest, response)
+
+	if request.MaxDetections > 0 && len(response.Detections) > int(request.MaxDetections) {
+		sort.SliceStable(response.Detections, func(i, j int) bool {
+			return response.Detections[i].Confidence > response.Detections[j].Confidence
+		})
+		response.Detections = response.Detections[:request.MaxDetections]
+	}
+	uncrop(response.Detections, img.Bounds(), area)
+	result.Detections = response.Detections
+
+	return result
+
+}
+
+// cropArea returns the pixels of the box in relative coordinates grown by the padding fraction of its size
+func cropArea(b image.Rectangle, box *odrpc.Box, padding float32) image.Rectangle {
+	w, h := float32(b.Dx()), float32(b.Dy())
+	padX, padY := (box.Right-box.Left)*padding, (box.Bottom-box.Top)*padding
+	left, right := max32(box.Left-padX, 0), min32(box.Right+padX, 1)
+	top, bottom := max32(box.Top-padY, 0), min32(box.Bottom+padY, 1)
+	return image.Rect(b.Min.X+int(left*w), b.Min.Y+int(top*h), b.Min.X+int(right*w), b.Min.Y+int(bottom*h)).Intersect(b)
+}
+
+// relativeBox returns the area in relative coordinates
+func relativeBox(b image.Rectangle, area image.Rectangle) *odrpc.Box {
+	w, h := float32(b.Dx()), float32(b.Dy())
+	return &odrpc.Box{
+		Top:    float32(area.Min.Y-b.Min.Y) / h,
+		Left:   float32(area.Min.X-b.Min.X) / w,
+		Bottom: float32(area.Max.Y-b.Min.Y) / h,
+		Right:  float32(area.Max.X-b.Min.X) / w,
+	}
+}
+
+// encodeCrop returns the area of the image as a BMP, it's fast to encode and decode
+func encodeCrop(img image.Image, area image.Rectangle) ([]byte, error) {
+	crop := image.NewRGBA(image.Rect(0, 0, area.Dx(), area.Dy()))
+	draw.Draw(crop, crop.Bounds(), img, area.Min, draw.Src)
+	var buf bytes.Buffer
+	if err := bmp.Encode(&buf, crop); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// uncrop converts detections in the area to the coordinates of the whole image
+func uncrop(detections []*odrpc.Detection, b image.Rectangle, area image.Rectangle) {
+	box := relativeBox(b, area)
+	cropWidth, cropHeight := box.Right-box.Left, box.Bottom-box.Top
+	for _, d := range detections {
+		d.Left = box.Left + d.Left*cropWidth
+		d.Right = box.Left + d.Right*cropWidth
+		d.Top = box.Top + d.Top*cropHeight
+		d.Bottom = box.Top + d.Bottom*cropHeight
+	}
+}
@@ -0,0 +1,82 @@
+package detector
+
+import (
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/detector/dconfig"
+)
+
+// breaker is a circuit breaker. After consecutive failures it opens and requests fail fast for the cooldown.
+// After the cooldown one request at a time probes the detector until one succeeds and it closes.
+type breaker struct {
+	failures int
+	cooldown time.Duration
+	now      func() time.Time
+
+	consecutive int
+	open        bool
+	openUntil   time.Time
+	probing     bool
+	lock        sync.Mutex
+}
+
+func newBreaker(c *dconfig.BreakerConfig) *breaker {
+	b := &breaker{
+		failures: c.Failures,
+		cooldown: c.Cooldown,
+		now:      time.Now,
+	}
+	if b.cooldown <= 0 {
+		b.cooldown = 30 * time.Second
+	}
+	return b
+}
+
+// allow returns true if the request should be run on the detector
+func (b *breaker) allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.open {
+		return true
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// isOpen returns true if requests are failing fast
+func (b *breaker) isOpen() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.open
+}
+
+// skip ends a request allowed by allow that says nothing about the health of the detector, like a bad request, so
+// another request can probe
+func (b *breaker) skip() {
+	b.lock.Lock()
+	b.probing = false
+	b.lock.Unlock()
+}
+
+// record records the result of a request, it returns if the breaker opened or closed and if it's open
+func (b *breaker) record(failed bool) (bool, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	wasOpen := b.open
+	b.probing = false
+	if failed {
+		b.consecutive++
+		if b.open || b.consecutive >= b.failures {
+			b.open = true
+			b.openUntil = b.now().Add(b.cooldown)
+		}
+	} else {
+		b.consecutive = 0
+		b.open = false
+	}
+	return wasOpen != b.open, b.open
+}
@@ -0,0 +1,148 @@
+package detector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// testClock is a clock for the tests that only moves when it's told
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func testBreaker(failures int) (*breaker, *testClock) {
+	clock := &testClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := newBreaker(&dconfig.BreakerConfig{Failures: failures, Cooldown: 20 * time.Millisecond})
+	b.now = clock.Now
+	return b, clock
+}
+
+func TestBreaker(t *testing.T) {
+
+	b, clock := testBreaker(3)
+
+	// A success resets the count
+	b.record(true)
+	b.record(true)
+	b.record(false)
+	b.record(true)
+	if changed, open := b.record(true); changed || open {
+		t.Fatal("breaker opened before 3 consecutive failures")
+	}
+	if changed, open := b.record(true); !changed || !open {
+		t.Fatal("breaker didn't open after 3 consecutive failures")
+	}
+	if b.allow() {
+		t.Fatal("open breaker allowed a request")
+	}
+	clock.now = clock.now.Add(19 * time.Millisecond)
+	if b.allow() {
+		t.Fatal("breaker allowed a request before the cooldown")
+	}
+
+	// After the cooldown one request probes
+	clock.now = clock.now.Add(time.Millisecond)
+	if !b.allow() {
+		t.Fatal("breaker didn't allow a probe after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("breaker allowed a second probe")
+	}
+
+	// A failed probe opens it for another cooldown
+	if changed, open := b.record(true); changed || !open {
+		t.Fatal("failed probe should keep the breaker open")
+	}
+	if b.allow() {
+		t.Fatal("breaker allowed a request after a failed probe")
+	}
+
+	// A skipped probe lets another request probe
+	clock.now = clock.now.Add(20 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("breaker didn't allow a probe after the second cooldown")
+	}
+	b.skip()
+	if !b.isOpen() {
+		t.Fatal("skipped probe closed the breaker")
+	}
+	if !b.allow() {
+		t.Fatal("breaker didn't allow a probe after a skipped probe")
+	}
+
+	if changed, open := b.record(false); !changed || open {
+		t.Fatal("successful probe should close the breaker")
+	}
+	if !b.allow() || b.isOpen() {
+		t.Fatal("closed breaker should allow requests")
+	}
+
+}
+
+// errorDetector returns the error for every detection
+type errorDetector struct {
+	err error
+}
+
+func (d *errorDetector) Config() *odrpc.Detector {
+	return &odrpc.Detector{Name: "test"}
+}
+
+func (d *errorDetector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &odrpc.DetectResponse{}, nil
+}
+
+func (d *errorDetector) Shutdown() {}
+
+func TestDetectBreaker(t *testing.T) {
+
+	b, clock := testBreaker(1)
+	d := &errorDetector{err: status.Errorf(codes.Internal, "failed")}
+	m := &Mux{scheduler: newScheduler(1), logger: zap.S()}
+	md := &muxDetector{Detector: d, breaker: b, weight: 1}
+	ctx := context.Background()
+
+	if _, _, err := m.detectOnce(ctx, md, &odrpc.DetectRequest{}); status.Code(err) != codes.Internal || !b.isOpen() {
+		t.Fatalf("breaker didn't open after the failure: %v", err)
+	}
+
+	// An open breaker fails before it takes any capacity
+	if err := m.scheduler.acquire(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, _, err := m.detectOnce(canceled, md, &odrpc.DetectRequest{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected the breaker to fail fast, got %v", err)
+	}
+	m.scheduler.release(1)
+
+	// A bad request doesn't close the breaker
+	clock.now = clock.now.Add(b.cooldown)
+	d.err = status.Errorf(codes.InvalidArgument, "bad image")
+	if _, _, err := m.detectOnce(ctx, md, &odrpc.DetectRequest{}); status.Code(err) != codes.InvalidArgument || !b.isOpen() {
+		t.Fatalf("bad request changed the breaker: %v", err)
+	}
+
+	// The next request probes and closes it
+	d.err = nil
+	if _, _, err := m.detectOnce(ctx, md, &odrpc.DetectRequest{}); err != nil || b.isOpen() {
+		t.Fatalf("successful probe didn't close the breaker: %v", err)
+	}
+
+}
@@ -0,0 +1,231 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io/ioutil"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// cascadeStage runs a detector on the area of the detections of the previous stage
+type cascadeStage struct {
+	name          string
+	detector      string
+	parent        string
+	labels        map[string]struct{}
+	minConfidence float32
+	padding       float32
+	detect        map[string]float32
+}
+
+func newCascadeStage(c *dconfig.CascadeStageConfig) *cascadeStage {
+	s := &cascadeStage{
+		name:          c.Name,
+		detector:      c.Detector,
+		parent:        c.Parent,
+		minConfidence: c.MinConfidence,
+		padding:       c.Padding,
+		detect:        c.Detect,
+	}
+	if s.name == "" {
+		s.name = s.detector
+	}
+	if s.padding <= 0 {
+		s.padding = 0.1
+	}
+	if len(c.Labels) > 0 {
+		s.labels = make(map[string]struct{}, len(c.Labels))
+		for _, label := range c.Labels {
+			s.labels[label] = struct{}{}
+		}
+	}
+	return s
+}
+
+// matches returns true if the stage should refine the detection
+func (s *cascadeStage) matches(d *odrpc.Detection) bool {
+	if d.Confidence < s.minConfidence {
+		return false
+	}
+	if s.labels == nil {
+		return true
+	}
+	_, ok := s.labels[d.Label]
+	return ok
+}
+
+// DetectCascade detects like Detect and sends the detections right away. Then each cascade stage of the detector
+// runs on the area of the matching detections and the results are sent as they finish.
+func (m *Mux) DetectCascade(request *odrpc.DetectRequest, stream odrpc.Odrpc_DetectCascadeServer) error {
+
+	ctx := stream.Context()
+
+	// Load the image first, the stages need the original
+	var err error
+	if len(request.File) != 0 {
+		request.Data, err = ioutil.ReadFile(request.File)
+		if err != nil {
+			return status.Errorf(codes.NotFound, "could not open file %s", request.File)
+		}
+		request.File = ""
+	}
+	if request.ImageUrl != "" {
+		request.Data, err = m.fetcher.fetch(ctx, request.ImageUrl)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		request.ImageUrl = ""
+	}
+	data := request.Data
+
+	// The stages are streamed here
+	request.Cascade = false
+	response, err := m.Detect(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	detector := m.detectors[request.DetectorName]
+	if detector == nil || len(detector.cascade) == 0 || response.Error != "" || len(response.Detections) == 0 {
+		return stream.Send(&odrpc.CascadeResult{
+			Id:         request.Id,
+			Parent:     -1,
+			Detections: response.Detections,
+			Error:      response.Error,
+			Done:       true,
+			Objects:    response.Detections,
+		})
+	}
+
+	if err = stream.Send(&odrpc.CascadeResult{
+		Id:         request.Id,
+		Parent:     -1,
+		Detections: response.Detections,
+	}); err != nil {
+		return err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "could not decode image: %v", err)
+	}
+
+	objects, err := m.runCascade(ctx, request, detector, img, response.Detections, stream.Send)
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&odrpc.CascadeResult{
+		Id:      request.Id,
+		Parent:  -1,
+		Done:    true,
+		Objects: objects,
+	})
+
+}
+
+// runCascade runs the cascade stages of the detector and returns copies of the detections with the stage detections
+// nested as children, like person -> face -> identity. Each stage result is sent as it finishes if send is set.
+func (m *Mux) runCascade(ctx context.Context, request *odrpc.DetectRequest, detector *muxDetector, img image.Image, detections []*odrpc.Detection, send func(*odrpc.CascadeResult) error) ([]*odrpc.Detection, error) {
+
+	type node struct {
+		detection *odrpc.Detection
+		stage     string
+		path      []int32
+	}
+
+	// Copies, the detections are shared with the sinks
+	objects := make([]*odrpc.Detection, 0, len(detections))
+	nodes := make([]*node, 0, len(detections))
+	for i, d := range detections {
+		c := *d
+		objects = append(objects, &c)
+		nodes = append(nodes, &node{detection: &c, path: []int32{int32(i)}})
+	}
+
+	for _, stage := range detector.cascade {
+		// The nodes are from the earlier stages, a stage doesn't refine its own detections
+		for _, n := range nodes {
+			if n.stage != stage.parent || !stage.matches(n.detection) {
+				continue
+			}
+			result := m.detectStage(ctx, request, stage, img, n.detection)
+			result.Parent = n.path[0]
+			result.ParentStage = n.stage
+			result.Path = n.path
+			for _, d := range result.Detections {
+				d.Stage = stage.name
+				n.detection.Children = append(n.detection.Children, d)
+				path := append(append([]int32{}, n.path...), int32(len(n.detection.Children)-1))
+				nodes = append(nodes, &node{detection: d, stage: stage.name, path: path})
+			}
+			if send != nil {
+				if err := send(result); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return objects, nil
+
+}
+
+// detectStage runs the stage detector on the area of the detection
+func (m *Mux) detectStage(ctx context.Context, request *odrpc.DetectRequest, stage *cascadeStage, img image.Image, parent *odrpc.Detection) *odrpc.CascadeResult {
+
+	result := &odrpc.CascadeResult{
+		Id:    request.Id,
+		Stage: stage.name,
+	}
+
+	detector, ok := m.detectors[stage.detector]
+	if !ok {
+		result.Error = "stage detector " + stage.detector + " not found"
+		return result
+	}
+
+	// The detection with padding in pixels
+	b := img.Bounds()
+	area := cropArea(b, &odrpc.Box{Top: parent.Top, Left: parent.Left, Bottom: parent.Bottom, Right: parent.Right}, stage.padding)
+	if area.Empty() {
+		result.Detections = []*odrpc.Detection{}
+		return result
+	}
+	data, err := encodeCrop(img, area)
+	if err != nil {
+		result.Error = "could not encode crop: " + err.Error()
+		return result
+	}
+
+	stageRequest := &odrpc.DetectRequest{
+		Id:           request.Id,
+		DetectorName: stage.detector,
+		Data:         data,
+		Detect:       stage.detect,
+	}
+	response, _, err := m.detectWithFallback(ctx, detector, stageRequest)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if response.Error != "" {
+		result.Error = response.Error
+		return result
+	}
+	detector.IgnoreResponse(stageRequest, response)
+	m.FilterResponse(stageRequest, response)
+
+	// Convert to the whole image
+	uncrop(response.Detections, b, area)
+	result.Detections = response.Detections
+
+	return result
+
+}
@@ -0,0 +1,120 @@
+// Package confirm drops labels until they're seen in enough of the recent frames from a source,
+// so one frame false positives (headlights, insects) aren't reported
+package confirm
+
+import (
+	"sync"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// Confirmer keeps the recent frames of each source
+type Confirmer struct {
+	sources map[string]*history
+	lock    sync.Mutex
+}
+
+// history is if each label was seen in the recent frames of a source
+type history struct {
+	labels map[string]*frames
+	lock   sync.Mutex
+}
+
+// frames is a ring of the recent frames
+type frames struct {
+	seen  []bool
+	next  int
+	count int
+}
+
+// New creates a confirmer
+func New() *Confirmer {
+	return &Confirmer{
+		sources: make(map[string]*history),
+	}
+}
+
+// Apply records the labels in this frame and returns the detections with the labels seen in at least min_frames of
+// the last frames. Labels not in the options labels are always returned.
+func (c *Confirmer) Apply(source string, opts *odrpc.Confirm, detections []*odrpc.Detection) []*odrpc.Detection {
+
+	if c == nil || opts == nil || opts.MinFrames <= 1 {
+		return detections
+	}
+	minFrames, size := int(opts.MinFrames), int(opts.Frames)
+	if size < minFrames {
+		size = minFrames
+	}
+
+	c.lock.Lock()
+	h, ok := c.sources[source]
+	if !ok {
+		h = &history{labels: make(map[string]*frames)}
+		c.sources[source] = h
+	}
+	c.lock.Unlock()
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var only map[string]struct{}
+	if len(opts.Labels) > 0 {
+		only = make(map[string]struct{}, len(opts.Labels))
+		for _, label := range opts.Labels {
+			only[label] = struct{}{}
+		}
+	}
+	confirming := func(label string) bool {
+		if only == nil {
+			return true
+		}
+		_, ok := only[label]
+		return ok
+	}
+
+	// Record this frame for the labels seen now or before
+	present := make(map[string]bool)
+	for _, d := range detections {
+		if confirming(d.Label) {
+			present[d.Label] = true
+		}
+	}
+	for label := range present {
+		if _, ok := h.labels[label]; !ok {
+			h.labels[label] = &frames{}
+		}
+	}
+	confirmed := make(map[string]bool)
+	for label, f := range h.labels {
+		f.add(present[label], size)
+		if f.count == 0 {
+			delete(h.labels, label)
+			continue
+		}
+		confirmed[label] = f.count >= minFrames
+	}
+
+	ret := detections[:0]
+	for _, d := range detections {
+		if !confirming(d.Label) || confirmed[d.Label] {
+			ret = append(ret, d)
+		}
+	}
+	return ret
+
+}
+
+// add records a frame, the ring is resized if the number of frames changed
+func (f *frames) add(seen bool, size int) {
+	if len(f.seen) != size {
+		f.seen, f.next, f.count = make([]bool, size), 0, 0
+	}
+	if f.seen[f.next] {
+		f.count--
+	}
+	f.seen[f.next] = seen
+	if seen {
+		f.count++
+	}
+	f.next = (f.next + 1) % size
+}
@@ -0,0 +1,93 @@
+package confirm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+func frame(labels ...string) []*odrpc.Detection {
+	ret := make([]*odrpc.Detection, 0, len(labels))
+	for _, label := range labels {
+		ret = append(ret, &odrpc.Detection{Label: label})
+	}
+	return ret
+}
+
+func labels(detections []*odrpc.Detection) []string {
+	ret := make([]string, 0, len(detections))
+	for _, d := range detections {
+		ret = append(ret, d.Label)
+	}
+	return ret
+}
+
+func TestApply(t *testing.T) {
+
+	for _, test := range []struct {
+		name   string
+		opts   *odrpc.Confirm
+		frames [][]string
+		// The labels returned for each frame
+		expected [][]string
+	}{
+		{
+			name:     "2 of 3 frames for person",
+			opts:     &odrpc.Confirm{MinFrames: 2, Frames: 3, Labels: []string{"person"}},
+			frames:   [][]string{{"person", "car"}, {"person"}, {}, {"person"}, {}, {}, {}, {"person"}},
+			expected: [][]string{{"car"}, {"person"}, {}, {"person"}, {}, {}, {}, {}},
+		},
+		{
+			name:     "all labels",
+			opts:     &odrpc.Confirm{MinFrames: 2},
+			frames:   [][]string{{"person", "car"}, {"car"}, {"person", "car"}, {"person", "dog"}},
+			expected: [][]string{{}, {"car"}, {"car"}, {"person"}},
+		},
+		{
+			name:     "more frames than min frames",
+			opts:     &odrpc.Confirm{MinFrames: 3, Frames: 5},
+			frames:   [][]string{{"cat"}, {}, {"cat"}, {}, {"cat"}, {}, {}, {}, {"cat"}},
+			expected: [][]string{{}, {}, {}, {}, {"cat"}, {}, {}, {}, {}},
+		},
+		{
+			name:     "one frame is off",
+			opts:     &odrpc.Confirm{MinFrames: 1, Frames: 3},
+			frames:   [][]string{{"person"}, {"car"}},
+			expected: [][]string{{"person"}, {"car"}},
+		},
+		{
+			name:     "no options",
+			frames:   [][]string{{"person"}},
+			expected: [][]string{{"person"}},
+		},
+	} {
+		c := New()
+		for i, labelFrame := range test.frames {
+			got := labels(c.Apply("camera", test.opts, frame(labelFrame...)))
+			if !reflect.DeepEqual(got, test.expected[i]) {
+				t.Errorf("%s: frame %d returned %v, expected %v", test.name, i, got, test.expected[i])
+			}
+		}
+	}
+
+}
+
+func TestSources(t *testing.T) {
+
+	c := New()
+	opts := &odrpc.Confirm{MinFrames: 2}
+	c.Apply("yard", opts, frame("person"))
+	if got := labels(c.Apply("door", opts, frame("person"))); len(got) != 0 {
+		t.Errorf("door returned %v before it was confirmed", got)
+	}
+	if got := labels(c.Apply("yard", opts, frame("person"))); !reflect.DeepEqual(got, []string{"person"}) {
+		t.Errorf("yard returned %v, expected person", got)
+	}
+
+	// Changing the number of frames starts over
+	if got := labels(c.Apply("yard", &odrpc.Confirm{MinFrames: 2, Frames: 4}, frame("person"))); len(got) != 0 {
+		t.Errorf("yard returned %v after the frames changed", got)
+	}
+
+}
@@ -0,0 +1,141 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"sort"
+
+	"golang.org/x/image/bmp"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// crops splits ultra wide images into overlapping crops, detects each one and merges the results
+type crops struct {
+	count     int
+	overlap   float64
+	minAspect float64
+	mergeIOU  float32
+}
+
+func newCrops(c *dconfig.CropsConfig) (*crops, error) {
+	cr := &crops{
+		count:     c.Count,
+		overlap:   c.Overlap,
+		minAspect: c.MinAspect,
+		mergeIOU:  c.MergeIOU,
+	}
+	if cr.count < 0 || cr.count == 1 {
+		return nil, fmt.Errorf("invalid crops count %d", cr.count)
+	}
+	if cr.overlap <= 0 {
+		cr.overlap = 0.1
+	} else if cr.overlap >= 0.9 {
+		return nil, fmt.Errorf("invalid crops overlap %f", cr.overlap)
+	}
+	if cr.minAspect <= 0 {
+		cr.minAspect = 2
+	}
+	if cr.mergeIOU <= 0 {
+		cr.mergeIOU = 0.3
+	}
+	return cr, nil
+}
+
+// detect runs the detector on each crop if the image is wide enough, otherwise on the whole image
+func (c *crops) detect(ctx context.Context, detector *muxDetector, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+
+	img, _, err := image.Decode(bytes.NewReader(request.Data))
+	if err != nil {
+		return detector.Detect(ctx, request)
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if h == 0 || float64(w)/float64(h) < c.minAspect {
+		return detector.Detect(ctx, request)
+	}
+
+	// Square crops unless the count is set
+	count := c.count
+	var cropWidth int
+	if count == 0 {
+		cropWidth = h
+		count = int(math.Ceil((float64(w) - c.overlap*float64(h)) / ((1 - c.overlap) * float64(h))))
+		if count < 2 {
+			count = 2
+		}
+	} else {
+		cropWidth = int(math.Ceil(float64(w) / (float64(count) - float64(count-1)*c.overlap)))
+	}
+	if cropWidth > w {
+		cropWidth = w
+	}
+
+	response := &odrpc.DetectResponse{
+		Id:         request.Id,
+		Detections: make([]*odrpc.Detection, 0),
+	}
+	for i := 0; i < count; i++ {
+		x := i * (w - cropWidth) / (count - 1)
+
+		crop := image.NewRGBA(image.Rect(0, 0, cropWidth, h))
+		draw.Draw(crop, crop.Bounds(), img, image.Point{X: b.Min.X + x, Y: b.Min.Y}, draw.Src)
+		var buf bytes.Buffer
+		if err := bmp.Encode(&buf, crop); err != nil {
+			return nil, fmt.Errorf("could not encode crop: %v", err)
+		}
+
+		cropRequest := *request
+		cropRequest.Data = buf.Bytes()
+		cropResponse, err := detector.Detect(ctx, &cropRequest)
+		if err != nil {
+			return cropResponse, err
+		}
+		if cropResponse.Error != "" {
+			response.Error = cropResponse.Error
+			return response, nil
+		}
+
+		// Convert to the whole image, crops are the full height
+		for _, d := range cropResponse.Detections {
+			d.Left = (float32(x) + d.Left*float32(cropWidth)) / float32(w)
+			d.Right = (float32(x) + d.Right*float32(cropWidth)) / float32(w)
+			response.Detections = append(response.Detections, d)
+		}
+	}
+
+	response.Detections = c.merge(response.Detections)
+	return response, nil
+
+}
+
+// merge combines detections of the same object from overlapping crops into one box with the highest confidence
+func (c *crops) merge(detections []*odrpc.Detection) []*odrpc.Detection {
+
+	sort.Slice(detections, func(i, j int) bool { return detections[i].Confidence > detections[j].Confidence })
+
+	merged := make([]*odrpc.Detection, 0, len(detections))
+	for _, d := range detections {
+		var found bool
+		for _, m := range merged {
+			if m.Label != d.Label || odrpc.IoU(m, d) < c.mergeIOU {
+				continue
+			}
+			// An object cut by the edge of a crop is in both, keep the whole box
+			m.Left, m.Top = min32(m.Left, d.Left), min32(m.Top, d.Top)
+			m.Right, m.Bottom = max32(m.Right, d.Right), max32(m.Bottom, d.Bottom)
+			found = true
+			break
+		}
+		if !found {
+			merged = append(merged, d)
+		}
+	}
+	return merged
+
+}
@@ -1,17 +1,292 @@
 package dconfig
 
 import (
+	"fmt"
+	"io/ioutil"
 	"time"
 )
 
 // Detector config is used for parsing configuration data from the config file
 type DetectorConfig struct {
-	Name          string        `json:"name"`
-	Type          string        `json:"type"`
-	ModelFile     string        `json:"model_file"`
-	LabelFile     string        `json:"label_file"`
-	NumThreads    int           `json:"num_threads"`
-	NumConcurrent int           `json:"num_concurrent"`
-	HWAccel       bool          `json:"hw_accel"`
-	Timeout       time.Duration `json:"timeout"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	ModelFile string `json:"model_file"`
+	// The model after its signature was checked and it was decrypted, used instead of reading the model file again
+	ModelData     []byte            `json:"-"`
+	LabelFile     string            `json:"label_file"`
+	LabelFormat   string            `json:"label_format"`
+	LabelFiles    map[string]string `json:"label_files"`
+	Ignore        []string          `json:"ignore"`
+	Mask          string            `json:"mask"`
+	MaskInput     bool              `json:"mask_input"`
+	Profiles      []*ProfileConfig  `json:"profiles"`
+	Night         *NightConfig      `json:"night"`
+	NumThreads    int               `json:"num_threads"`
+	NumConcurrent int               `json:"num_concurrent"`
+	HWAccel       bool              `json:"hw_accel"`
+	Timeout       time.Duration     `json:"timeout"`
+	// The tflite hardware delegate with hw_accel: edgetpu (default) or one built in like coreml
+	Delegate        string            `json:"delegate"`
+	DelegateOptions map[string]string `json:"delegate_options"`
+	// Only use edgetpu devices of this type: usb or pcie, default any
+	DeviceType string `json:"device_type"`
+	// The edgetpu performance mode: low, medium, high or max
+	Performance string `json:"performance"`
+	// The onnx execution provider: cpu (default), cuda, rocm, migraphx or directml and the GPU to use
+	Provider string `json:"provider"`
+	DeviceID int    `json:"device_id"`
+	// The rknn NPU cores: auto, 0, 1, 2, 0_1, 0_1_2 (all) or each to run each concurrent detection on its own core
+	NPUCores string `json:"npu_cores"`
+	// The share of doods.scheduler.capacity used by each detection, default 1
+	Weight int64 `json:"weight"`
+	// Resize the model input to this size, required for models with a dynamic input shape
+	InputWidth  int `json:"input_width"`
+	InputHeight int `json:"input_height"`
+	// The name of the image input tensor for models with more than one input
+	ImageInput string `json:"image_input"`
+	// The other inputs for models with more than one input
+	Inputs []*InputConfig `json:"inputs"`
+	// Don't parse the model outputs, always return the raw output tensors
+	RawOutputs bool `json:"raw_outputs"`
+	// The single output of the model is the score of each label even if it isn't named scores, like image classifiers
+	Classifier bool `json:"classifier"`
+	// The model outputs a density map that sums to the number of objects, like CSRNet crowd counting models
+	Density *DensityConfig `json:"density"`
+	// Convert the model outputs to detections with a plugin
+	PostProcess *PostProcessConfig `json:"post_process"`
+	// Retry transient detector errors before failing
+	Retry *RetryConfig `json:"retry"`
+	// Fail fast after consecutive failures
+	Breaker *BreakerConfig `json:"breaker"`
+	// Reject requests that would take longer than the SLA
+	SLA *SLAConfig `json:"sla"`
+	// Retry on these detectors in order if the detector errors or times out
+	Fallback []string `json:"fallback"`
+	// Refine the detections with other detectors for DetectCascade
+	Cascade []*CascadeStageConfig `json:"cascade"`
+	// Classify the area of the detections with other detectors and add the results as attributes
+	Attributes []*AttributeConfig `json:"attributes"`
+	// A ready made pipeline like birds, the options it sets can still be changed
+	Preset *PresetConfig `json:"preset"`
+	// Also run some requests through another detector and compare the results
+	Shadow *ShadowConfig `json:"shadow"`
+	// Run the detector threads on these CPUs: performance, efficiency, node:N or a cpu list like 4-7
+	CPUs string `json:"cpus"`
+	// Check the image before detection
+	Quality *QualityConfig `json:"quality"`
+	// Split ultra wide images into overlapping crops
+	Crops *CropsConfig `json:"crops"`
+	// The canned detections for the mock detector type
+	Mock *MockConfig `json:"mock"`
+	// Versions of the model for different hardware, the first one the hardware supports is used
+	Variants []*VariantConfig `json:"variants"`
+}
+
+// ReadModel returns the checked model data or reads the model file
+func (c *DetectorConfig) ReadModel() ([]byte, error) {
+	if c.ModelData != nil {
+		return c.ModelData, nil
+	}
+	data, err := ioutil.ReadFile(c.ModelFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read model %s: %v", c.ModelFile, err)
+	}
+	return data, nil
+}
+
+// VariantConfig is a version of the model for some hardware. The fields that are set replace the detector config.
+type VariantConfig struct {
+	// The hardware required: edgetpu, gpu, avx2, avx512, neon or a comma separated list, blank for any
+	Requires    string `json:"requires"`
+	Type        string `json:"type"`
+	ModelFile   string `json:"model_file"`
+	LabelFile   string `json:"label_file"`
+	HWAccel     bool   `json:"hw_accel"`
+	Delegate    string `json:"delegate"`
+	NumThreads  int    `json:"num_threads"`
+	InputWidth  int    `json:"input_width"`
+	InputHeight int    `json:"input_height"`
+}
+
+// MockConfig configures the mock detector that returns canned detections without a model
+type MockConfig struct {
+	Detections []*MockDetection `json:"detections"`
+	// Extra labels the detector reports, the detection labels are always included
+	Labels []string `json:"labels"`
+	// How long each detection takes plus a random amount up to jitter
+	Latency time.Duration `json:"latency"`
+	Jitter  time.Duration `json:"jitter"`
+	// The percentage of requests that return a detector error
+	ErrorPercent float64 `json:"error_percent"`
+}
+
+// MockDetection is a canned detection
+type MockDetection struct {
+	Label      string  `json:"label"`
+	Confidence float32 `json:"confidence"`
+	Top        float32 `json:"top"`
+	Left       float32 `json:"left"`
+	Bottom     float32 `json:"bottom"`
+	Right      float32 `json:"right"`
+	// The percentage of requests it's returned for, default 100
+	Percent float64 `json:"percent"`
+}
+
+// InputConfig feeds a model input tensor from the request inputs
+type InputConfig struct {
+	// The input tensor name
+	Name string `json:"name"`
+	// The key in the request inputs, defaults to the name
+	Field string `json:"field"`
+	// The values or a file with the raw tensor data to use if the request doesn't have the input
+	Values []float32 `json:"values"`
+	File   string    `json:"file"`
+}
+
+// PostProcessConfig loads a post-processor plugin
+type PostProcessConfig struct {
+	// The Go plugin (.so)
+	Plugin string `json:"plugin"`
+	// Passed to the plugin
+	Options map[string]string `json:"options"`
+}
+
+// RetryConfig retries transient detector errors like an interpreter invoke error or an edgetpu USB glitch
+type RetryConfig struct {
+	// The number of retries
+	Count int `json:"count"`
+	// The wait before the first retry, doubled for each retry up to max_backoff
+	Backoff    time.Duration `json:"backoff"`
+	MaxBackoff time.Duration `json:"max_backoff"`
+}
+
+// BreakerConfig marks a detector unhealthy after consecutive failures or timeouts
+type BreakerConfig struct {
+	// The number of consecutive failures that open the breaker
+	Failures int `json:"failures"`
+	// How long requests fail fast before the next request probes the detector, default 30s
+	Cooldown time.Duration `json:"cooldown"`
+}
+
+// SLAConfig rejects new requests when the projected completion time is over the latency
+type SLAConfig struct {
+	Latency time.Duration `json:"latency"`
+	// The number of recent detections used for the p95 latency, default 100
+	Window int `json:"window"`
+}
+
+// CascadeStageConfig runs another detector on the area of each detection, like faces of people or plates of cars
+type CascadeStageConfig struct {
+	// The stage name in the results, defaults to the detector
+	Name     string `json:"name"`
+	Detector string `json:"detector"`
+	// Refine the detections of this stage instead of the first stage, like the text of the plates of cars
+	Parent string `json:"parent"`
+	// Only refine detections with these labels and confidence, blank for any
+	Labels        []string `json:"labels"`
+	MinConfidence float32  `json:"min_confidence"`
+	// Extra area around the detection as a fraction of its size, default 0.1
+	Padding float32 `json:"padding"`
+	// What to detect with the stage detector like the detect field of a request
+	Detect map[string]float32 `json:"detect"`
+}
+
+// PresetConfig sets up a ready made pipeline for the detector
+type PresetConfig struct {
+	Name string `json:"name"`
+	// The second stage detector, like the species classifier
+	Detector string `json:"detector"`
+	// Use these labels instead of the preset labels
+	Labels []string `json:"labels"`
+}
+
+// AttributeConfig runs a classifier on the area of each detection, like the color of cars or a helmet on people
+type AttributeConfig struct {
+	// The attribute name in the results, defaults to the detector
+	Name     string `json:"name"`
+	Detector string `json:"detector"`
+	// Only classify detections with these labels and confidence, blank for any
+	Labels        []string `json:"labels"`
+	MinConfidence float32  `json:"min_confidence"`
+	// Extra area around the detection as a fraction of its size, default 0.1
+	Padding float32 `json:"padding"`
+	// The minimum confidence of a value, default 50
+	Threshold float32 `json:"threshold"`
+	// Add every value over the threshold instead of the best one, for classifiers with independent labels like
+	// backpack and helmet
+	Multiple bool `json:"multiple"`
+}
+
+// DensityConfig counts objects with a density map model instead of boxes, for crowds too dense to detect each person
+type DensityConfig struct {
+	// What is counted, default person
+	Label string `json:"label"`
+	// The output tensor with the density map, default the first
+	Output string `json:"output"`
+	// Multiply the density map, for models trained on maps scaled up to make the values larger, default 1
+	Scale float32 `json:"scale"`
+}
+
+// ShadowConfig runs a percentage of the requests through another detector to compare it before switching
+type ShadowConfig struct {
+	Detector string `json:"detector"`
+	// The percentage of requests, default 100
+	Percent float64 `json:"percent"`
+	// The minimum overlap (intersection over union) for detections to match, default 0.5
+	IOU float32 `json:"iou"`
+}
+
+// QualityConfig checks the image before detection. Checks with a zero value are disabled.
+type QualityConfig struct {
+	// The average brightness (0-255)
+	MinBrightness float32 `json:"min_brightness"`
+	MaxBrightness float32 `json:"max_brightness"`
+	// The maximum percentage of almost white pixels
+	MaxOverexposed float32 `json:"max_overexposed"`
+	// The minimum variance of the laplacian, lower is blurrier
+	MinSharpness float32 `json:"min_sharpness"`
+	// skip (default) returns the report without detecting, flag detects and includes the report
+	Action string `json:"action"`
+}
+
+// CropsConfig splits ultra wide images into overlapping crops that are detected separately and merged
+type CropsConfig struct {
+	// The number of crops, default 0 for square crops
+	Count int `json:"count"`
+	// The overlap between crops (0-1), default 0.1
+	Overlap float64 `json:"overlap"`
+	// Only crop images with a width/height at least this, default 2
+	MinAspect float64 `json:"min_aspect"`
+	// The minimum overlap (intersection over union) of detections from different crops to merge them, default 0.3
+	MergeIOU float32 `json:"merge_iou"`
+}
+
+// NightConfig switches to another detector at night. If brightness is set
+// the average image brightness (0-255) is used, otherwise it uses sunset/sunrise at the latitude/longitude.
+type NightConfig struct {
+	Detector   string  `json:"detector"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Brightness float64 `json:"brightness"`
+}
+
+// ProfileConfig changes the detection options during a time window
+type ProfileConfig struct {
+	Name   string             `json:"name"`
+	Start  string             `json:"start"`
+	End    string             `json:"end"`
+	Days   []string           `json:"days"`
+	Detect map[string]float32 `json:"detect"`
+	Ignore []string           `json:"ignore"`
+	Mask   string             `json:"mask"`
+}
+
+// BackendConfig is another doods instance the router sends detections to
+type BackendConfig struct {
+	// host:port of the gRPC api
+	Address string `json:"address"`
+	AuthKey string `json:"auth_key"`
+	TLS     bool   `json:"tls"`
+	// Accept self signed certificates
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
 }
@@ -0,0 +1,192 @@
+package detector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+var unsafeDebugName = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// debugStore saves requests so results can be reproduced offline. Each request gets a directory with the original
+// image, the image the detector got, the resized detector input and the request and response.
+type debugStore struct {
+	dir    string
+	errors bool
+	empty  bool
+	max    int
+
+	saved []string
+	lock  sync.Mutex
+
+	logger *zap.SugaredLogger
+}
+
+// newDebugStore returns the debug store, nil if doods.debug.dir isn't set
+func newDebugStore(logger *zap.SugaredLogger) *debugStore {
+
+	dir := config.GetString("doods.debug.dir")
+	if dir == "" {
+		return nil
+	}
+
+	s := &debugStore{
+		dir:    dir,
+		errors: config.GetBool("doods.debug.errors"),
+		empty:  config.GetBool("doods.debug.empty"),
+		max:    config.GetInt("doods.debug.max_requests"),
+		logger: logger,
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		logger.Fatalf("Could not create debug dir: %v", err)
+	}
+
+	// The names start with the time so they sort oldest first
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		logger.Fatalf("Could not read debug dir: %v", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			s.saved = append(s.saved, e.Name())
+		}
+	}
+	sort.Strings(s.saved)
+
+	return s
+
+}
+
+// wanted returns true if the request should be saved, it was asked for, failed or found nothing
+func (s *debugStore) wanted(request *odrpc.DetectRequest, response *odrpc.DetectResponse, err error) bool {
+	switch {
+	case s == nil:
+		return false
+	case request.Debug:
+		return true
+	case err != nil || response == nil || response.Degraded:
+		return s.errors
+	case len(response.Detections) == 0:
+		return s.empty
+	}
+	return false
+}
+
+// save writes the request in the background and returns the name of its directory
+func (s *debugStore) save(detector *muxDetector, request *odrpc.DetectRequest, original []byte, response *odrpc.DetectResponse, detectErr error) string {
+
+	name := time.Now().Format("20060102_150405.000") + "_" + unsafeDebugName.ReplaceAllString(request.DetectorName, "_")
+	if request.Id != "" {
+		name += "_" + unsafeDebugName.ReplaceAllString(request.Id, "_")
+	}
+	dir := filepath.Join(s.dir, name)
+
+	// Encode them now, they can change after the request returns
+	files := make(map[string][]byte)
+	r := *request
+	r.Data = nil
+	files["request.json"], _ = json.MarshalIndent(struct {
+		Request  *odrpc.DetectRequest `json:"request"`
+		Detector *odrpc.Detector      `json:"detector"`
+	}{&r, detector.Config()}, "", "  ")
+	if detectErr != nil {
+		files["error.txt"] = []byte(detectErr.Error() + "\n")
+	}
+	if response != nil {
+		files["response.json"], _ = json.MarshalIndent(response, "", "  ")
+	}
+	files["original"+imageExt(original)] = original
+	if !bytes.Equal(original, request.Data) {
+		files["input"+imageExt(request.Data)] = request.Data
+	}
+	id, input, cfg := request.Id, request.Data, detector.Config()
+
+	go func() {
+
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			s.logger.Errorw("Could not save debug request", "dir", dir, "error", err)
+			return
+		}
+
+		// The image resized to the detector input and the tensor, like the preprocess endpoint
+		if cfg.Width > 0 && cfg.Height > 0 && (cfg.Channels == 0 || cfg.Channels == 3) {
+			if src, sw, sh, err := decodeRGB(input); err == nil {
+				w, h := int(cfg.Width), int(cfg.Height)
+				data, _ := resizeInput(src, sw, sh, w, h, false)
+				files["resized.ppm"] = append([]byte(fmt.Sprintf("P6\n%d %d\n255\n", w, h)), data...)
+				if cfg.Input != nil {
+					if tensor, err := inputTensor(data, w, h, cfg.Input); err == nil {
+						files["tensor.bin"] = tensor
+						shape := []int{1, h, w, 3}
+						if cfg.Input.Layout == "NCHW" {
+							shape = []int{1, 3, h, w}
+						}
+						files["tensor.json"], _ = json.MarshalIndent(struct {
+							Shape []int            `json:"shape"`
+							Input *odrpc.InputSpec `json:"input"`
+						}{shape, cfg.Input}, "", "  ")
+					}
+				}
+			}
+		}
+
+		for filename, data := range files {
+			if err := ioutil.WriteFile(filepath.Join(dir, filename), data, 0600); err != nil {
+				s.logger.Errorw("Could not save debug request", "dir", dir, "error", err)
+				return
+			}
+		}
+
+		// Keep the newest
+		s.lock.Lock()
+		s.saved = append(s.saved, name)
+		var remove []string
+		if s.max > 0 && len(s.saved) > s.max {
+			remove = s.saved[:len(s.saved)-s.max]
+			s.saved = append([]string(nil), s.saved[len(s.saved)-s.max:]...)
+		}
+		s.lock.Unlock()
+		for _, old := range remove {
+			os.RemoveAll(filepath.Join(s.dir, old))
+		}
+
+		s.logger.Debugw("Saved debug request", "id", id, "dir", dir)
+
+	}()
+
+	return name
+
+}
+
+// imageExt returns the file extension for the image
+func imageExt(data []byte) string {
+	if bytes.HasPrefix(data, []byte("P6")) {
+		return ".ppm"
+	}
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/bmp":
+		return ".bmp"
+	case "image/webp":
+		return ".webp"
+	}
+	return ".bin"
+}
@@ -0,0 +1,125 @@
+package detector
+
+import (
+	"fmt"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/mask"
+	"github.com/snowzach/doods/detector/orient"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/zone"
+)
+
+// densityCounter counts with the density map output of crowd counting models. Each cell of the map is the number of
+// objects in that area so the sum is the count, even when the objects are too packed to get a box each.
+type densityCounter struct {
+	label  string
+	output string
+	scale  float32
+}
+
+func newDensityCounter(c *dconfig.DensityConfig) *densityCounter {
+
+	dc := &densityCounter{
+		label:  c.Label,
+		output: c.Output,
+		scale:  c.Scale,
+	}
+	if dc.label == "" {
+		dc.label = "person"
+	}
+	if dc.scale <= 0 {
+		dc.scale = 1
+	}
+	return dc
+
+}
+
+// estimate sums the density map in the response outputs. The map is converted to the original orientation, the cells
+// in the masks are left out and the cells in each zone region are also counted for the zone.
+func (dc *densityCounter) estimate(response *odrpc.DetectResponse, rotate int32, flip string, msk *mask.Mask, zones *zone.Zones) (*odrpc.Density, error) {
+
+	var tensor *odrpc.OutputTensor
+	for _, output := range response.Outputs {
+		if dc.output == "" || output.Name == dc.output {
+			tensor = output
+			break
+		}
+	}
+	if tensor == nil {
+		return nil, fmt.Errorf("density output %s not found", dc.output)
+	}
+
+	width, height, err := densityShape(tensor.Shape)
+	if err != nil {
+		return nil, err
+	}
+	if len(tensor.Values) != width*height {
+		return nil, fmt.Errorf("density output has %d values, expected %d", len(tensor.Values), width*height)
+	}
+
+	values := make([]float32, len(tensor.Values))
+	for i, v := range tensor.Values {
+		// Models output a little negative noise where there's nothing
+		if v > 0 {
+			values[i] = v * dc.scale
+		}
+	}
+	values, width, height = orient.MapGrid(values, width, height, rotate, flip)
+
+	density := &odrpc.Density{
+		Label:  dc.label,
+		Width:  int32(width),
+		Height: int32(height),
+		Values: values,
+	}
+	var regions map[string]*odrpc.DetectRegion
+	if zones != nil && len(zones.Regions) > 0 {
+		regions = zones.Regions
+		density.Zones = make(map[string]float32, len(regions))
+		for name := range regions {
+			density.Zones[name] = 0
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		cy := (float32(y) + 0.5) / float32(height)
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			if values[i] == 0 {
+				continue
+			}
+			cx := (float32(x) + 0.5) / float32(width)
+			if (msk != nil && msk.Masked(cx, cy)) || zones.Masked(cx, cy) {
+				values[i] = 0
+				continue
+			}
+			density.Count += values[i]
+			for name, region := range regions {
+				if cx >= region.Left && cx <= region.Right && cy >= region.Top && cy <= region.Bottom {
+					density.Zones[name] += values[i]
+				}
+			}
+		}
+	}
+
+	return density, nil
+
+}
+
+// densityShape returns the width and height of a density map tensor, [1,H,W,1], [1,1,H,W], [1,H,W] or [H,W]
+func densityShape(shape []int32) (int, int, error) {
+
+	switch {
+	case len(shape) == 4 && shape[0] == 1 && shape[3] == 1:
+		return int(shape[2]), int(shape[1]), nil
+	case len(shape) == 4 && shape[0] == 1 && shape[1] == 1:
+		return int(shape[3]), int(shape[2]), nil
+	case len(shape) == 3 && shape[0] == 1:
+		return int(shape[2]), int(shape[1]), nil
+	case len(shape) == 2:
+		return int(shape[1]), int(shape[0]), nil
+	}
+	return 0, 0, fmt.Errorf("density output shape %v is not a single map", shape)
+
+}
@@ -1,9 +1,15 @@
 package detector
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"image"
 	"io/ioutil"
+	"sort"
 	"sync"
+	"time"
 
 	// We will support these formats
 	_ "image/gif"
@@ -19,11 +25,37 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/snowzach/doods/alert"
+	"github.com/snowzach/doods/cluster"
 	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/confirm"
 	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/embedded"
+	"github.com/snowzach/doods/detector/enhance"
+	"github.com/snowzach/doods/detector/gotflite"
+	"github.com/snowzach/doods/detector/labels"
+	"github.com/snowzach/doods/detector/mask"
+	"github.com/snowzach/doods/detector/mock"
+	"github.com/snowzach/doods/detector/onnx"
+	"github.com/snowzach/doods/detector/orient"
+	"github.com/snowzach/doods/detector/pixel"
+	"github.com/snowzach/doods/detector/remote"
+	"github.com/snowzach/doods/detector/rknn"
+	"github.com/snowzach/doods/detector/signing"
+	"github.com/snowzach/doods/detector/smooth"
 	"github.com/snowzach/doods/detector/tensorflow"
 	"github.com/snowzach/doods/detector/tflite"
+	"github.com/snowzach/doods/feedback"
+	"github.com/snowzach/doods/history"
 	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/pets"
+	"github.com/snowzach/doods/review"
+	"github.com/snowzach/doods/script"
+	"github.com/snowzach/doods/server"
+	"github.com/snowzach/doods/sink"
+	"github.com/snowzach/doods/state"
+	"github.com/snowzach/doods/stats"
+	"github.com/snowzach/doods/zone"
 )
 
 // Detector is the interface to object detectors
@@ -33,72 +65,401 @@ type Detector interface {
 	Shutdown()
 }
 
+// Labeler is implemented by detectors that can return their labels by class index
+type Labeler interface {
+	Labels() labels.Labels
+}
+
+// muxDetector holds a detector and the options the mux applies to it
+type muxDetector struct {
+	Detector
+	// language -> label -> translated label
+	translations map[string]map[string]string
+	// labels to never return
+	ignore map[string]struct{}
+	// detections centered in the masked area are dropped
+	mask      *mask.Mask
+	maskInput bool
+	// time of day profiles
+	profiles []*profile
+	// switch to another detector at night
+	night *night
+	// compare with another detector
+	shadow *shadow
+	// check the image before detecting
+	quality *quality
+	// detect ultra wide images in crops
+	crops *crops
+	// the share of the scheduler capacity for each detection
+	weight int64
+	// retry transient errors
+	retry *retry
+	// fail fast when the detector keeps failing
+	breaker *breaker
+	// reject requests that won't finish in time
+	sla *sla
+	// detectors to retry on if this one fails
+	fallback []string
+	// stages for DetectCascade
+	cascade []*cascadeStage
+	// classifiers for the attributes of the detections
+	attributes []*attributeClassifier
+	// counts with the density map of crowd counting models
+	density *densityCounter
+	// the last detection with results
+	last     *event
+	lastLock sync.RWMutex
+}
+
 // Mux handles and routes requests to the configured detectors
 type Mux struct {
-	detectors map[string]Detector
-	authKey   string
+	detectors map[string]*muxDetector
+	zones     *zone.Store
+	sinks     *sink.Manager
+	alerts    *alert.Engine
+	fetcher   *fetcher
+	limits    *imageLimits
+	state     *state.Tracker
+	smooth    *smooth.Smoother
+	confirm   *confirm.Confirmer
+	stats     *stats.Collector
+	scheduler *scheduler
+	script    *script.Hooks
+	review    *review.Queue
+	feedback  *feedback.Store
+	history   *history.Store
+	pets      *pets.Store
+	debug     *debugStore
+	signer    *signing.Signer
+	cluster   *cluster.Cluster
+	lc        *conf.Lifecycle
+	keys      *server.AuthKeys
 	logger    *zap.SugaredLogger
 }
 
 // Create a new mux
-func New() *Mux {
+func New(lc *conf.Lifecycle, zones *zone.Store, sinks *sink.Manager, alerts *alert.Engine, reviews *review.Queue, fb *feedback.Store, hist *history.Store) *Mux {
 
 	m := &Mux{
-		detectors: make(map[string]Detector),
-		authKey:   config.GetString("doods.auth_key"),
+		detectors: make(map[string]*muxDetector),
+		zones:     zones,
+		sinks:     sinks,
+		alerts:    alerts,
+		review:    reviews,
+		feedback:  fb,
+		history:   hist,
+		pets:      pets.New(),
+		lc:        lc,
+		fetcher:   newFetcher(),
+		limits:    newImageLimits(),
+		state:     state.NewTracker(config.GetDuration("doods.state.debounce"), config.GetDuration("doods.state.leave")),
+		smooth:    smooth.New(),
+		confirm:   confirm.New(),
+		stats:     stats.New(),
+		scheduler: newScheduler(config.GetInt64("doods.scheduler.capacity")),
+		keys:      server.Keys(),
 		logger:    zap.S().With("package", "detector"),
 	}
 
+	// The image conversion kernels
+	pixel.SetEnabled(config.GetBool("doods.simd"))
+	m.logger.Infow("Pixel kernels", "kernel", pixel.Kernel())
+
 	// Get the detectors config
 	var detectorConfig []*dconfig.DetectorConfig
 	config.UnmarshalKey("doods.detectors", &detectorConfig)
 
-	// Create the detectors
+	// Route detectors to other doods instances, the ones that aren't configured here are added
+	var router *remote.Router
+	var backendConfig []*dconfig.BackendConfig
+	config.UnmarshalKey("doods.router.backends", &backendConfig)
+	if len(backendConfig) > 0 {
+		router = remote.New(lc, backendConfig, config.GetDuration("doods.router.timeout"))
+		if config.GetBool("doods.router.discover") {
+			configured := make(map[string]struct{})
+			for _, c := range detectorConfig {
+				configured[c.Name] = struct{}{}
+			}
+			for _, name := range router.Detectors() {
+				if _, ok := configured[name]; !ok {
+					detectorConfig = append(detectorConfig, &dconfig.DetectorConfig{Name: name, Type: "remote"})
+				}
+			}
+		}
+	}
+
+	// Add the embedded model if it's enabled or nothing is configured
+	var embeddedConfig *dconfig.DetectorConfig
+	if config.GetBool("doods.embedded.enabled") || (len(detectorConfig) == 0 && embedded.Available()) {
+		if c, err := embeddedDetector(detectorConfig); err != nil {
+			m.logger.Errorw("Could not configure the embedded model", "error", err)
+		} else if c != nil {
+			embeddedConfig = c
+			detectorConfig = append([]*dconfig.DetectorConfig{c}, detectorConfig...)
+		}
+	}
+
+	// The keys for signed and encrypted models
+	keys, err := newModelKeys()
+	if err != nil {
+		m.logger.Fatalf("Could not load model keys: %v", err)
+	}
+
+	// Save requests to reproduce them
+	m.debug = newDebugStore(m.logger)
+
+	// Sign the detections for systems that keep them as evidence
+	m.signer, err = signing.New(config.GetString("doods.signing.algorithm"), config.GetString("doods.signing.key_id"), config.GetString("doods.signing.key"))
+	if err != nil {
+		m.logger.Fatalf("Could not load signing key: %v", err)
+	}
+
+	// Create the detectors, each with its own lifecycle so it can be restarted
+	maxRestarts := config.GetInt("doods.max_restarts")
 	for _, c := range detectorConfig {
-		var d Detector
-		var err error
+		c := c
+		var create func(lc *conf.Lifecycle) (Detector, error)
+
+		// Pick the model for this hardware
+		if len(c.Variants) > 0 {
+			selected, reason, err := selectVariant(c)
+			if err != nil {
+				m.logger.Errorf("Could not configure detector %s: %v", c.Name, err)
+				continue
+			}
+			m.logger.Infow("Selected model variant", "name", c.Name, "type", selected.Type, "model", selected.ModelFile, "hw_accel", selected.HWAccel, "delegate", selected.Delegate, "reason", reason)
+			c = selected
+		}
+
+		// Density map models are counted from the raw map, they have no boxes to parse
+		if c.Density != nil {
+			c.RawOutputs = true
+		}
+
+		// Check and decrypt the model, the embedded model is part of the binary
+		if c != embeddedConfig {
+			if err := keys.unseal(c); err != nil {
+				m.logger.Errorf("Could not load model for detector %s: %v", c.Name, err)
+				continue
+			}
+		}
 
 		m.logger.Debugw("Configuring detector", "config", c)
 
 		switch c.Type {
 		case "tflite":
-			d, err = tflite.New(c)
+			create = func(lc *conf.Lifecycle) (Detector, error) { return tflite.New(lc, c) }
+		case "gotflite":
+			create = func(lc *conf.Lifecycle) (Detector, error) { return gotflite.New(lc, c) }
 		case "tensorflow":
-			d, err = tensorflow.New(c)
+			create = func(lc *conf.Lifecycle) (Detector, error) { return tensorflow.New(lc, c) }
+		case "onnx":
+			create = func(lc *conf.Lifecycle) (Detector, error) { return onnx.New(lc, c) }
+		case "rknn":
+			create = func(lc *conf.Lifecycle) (Detector, error) { return rknn.New(lc, c) }
+		case "mock":
+			create = func(lc *conf.Lifecycle) (Detector, error) { return mock.New(lc, c) }
+		case "remote":
+			if router == nil {
+				m.logger.Errorw("Could not initialize remote detector, there are no doods.router.backends", "name", c.Name)
+				continue
+			}
+			create = func(lc *conf.Lifecycle) (Detector, error) { return router.New(c) }
 		default:
 			m.logger.Errorw("Could not initialize detector", "name", c.Name, "type", c.Type)
 			continue
 		}
 
+		d, err := newRestarter(lc, c.Name, maxRestarts, create)
 		if err != nil {
 			m.logger.Errorf("Could not initialize detector %s: %v", c.Name, err)
 			continue
 		}
 
+		md, err := newMuxDetector(d, c)
+		if err != nil {
+			m.logger.Errorf("Could not configure detector %s: %v", c.Name, err)
+			d.Shutdown()
+			continue
+		}
+
 		dc := d.Config()
-		m.logger.Infow("Configured Detector", "name", dc.Name, "type", dc.Type, "model", dc.Model, "labels", len(dc.Labels), "width", dc.Width, "height", dc.Height)
-		m.detectors[c.Name] = d
+		m.logger.Infow("Configured Detector", "name", dc.Name, "type", dc.Type, "model", dc.Model, "labels", len(dc.Labels), "languages", dc.Languages, "width", dc.Width, "height", dc.Height)
+		m.detectors[c.Name] = md
 	}
 
 	if len(m.detectors) == 0 {
 		m.logger.Fatalf("No detectors configured")
 	}
 
+	// Load the script hooks
+	if filename := config.GetString("doods.script"); filename != "" {
+		var err error
+		if m.script, err = script.Load(filename); err != nil {
+			m.logger.Fatalf("Could not load script: %v", err)
+		}
+		m.logger.Infow("Loaded Script", "script", filename)
+	}
+
 	return m
 
 }
 
-// GetDetectors returns the configured detectors
+// newMuxDetector wraps the detector with the mux options from the config
+func newMuxDetector(d Detector, c *dconfig.DetectorConfig) (*muxDetector, error) {
+
+	md := &muxDetector{
+		Detector: d,
+		ignore:   make(map[string]struct{}),
+		weight:   c.Weight,
+		fallback: c.Fallback,
+	}
+	if md.weight <= 0 {
+		md.weight = 1
+	}
+	for _, label := range c.Ignore {
+		md.ignore[label] = struct{}{}
+	}
+
+	// Load the mask
+	if c.Mask != "" {
+		var err error
+		md.mask, err = mask.Load(c.Mask)
+		if err != nil {
+			return nil, err
+		}
+		md.maskInput = c.MaskInput
+	}
+
+	// Time of day profiles
+	for _, pc := range c.Profiles {
+		p, err := newProfile(pc)
+		if err != nil {
+			return nil, fmt.Errorf("could not load profile %s: %v", pc.Name, err)
+		}
+		md.profiles = append(md.profiles, p)
+	}
+
+	if c.Night != nil && c.Night.Detector != "" {
+		md.night = newNight(c.Night)
+	}
+
+	if c.Shadow != nil && c.Shadow.Detector != "" {
+		md.shadow = newShadow(c.Shadow)
+	}
+
+	if c.Retry != nil && c.Retry.Count > 0 {
+		md.retry = newRetry(c.Retry)
+	}
+
+	if c.Breaker != nil && c.Breaker.Failures > 0 {
+		md.breaker = newBreaker(c.Breaker)
+	}
+
+	if c.SLA != nil && c.SLA.Latency > 0 {
+		md.sla = newSLA(c.SLA, c.NumConcurrent)
+	}
+
+	stages := make(map[string]struct{})
+	for _, sc := range c.Cascade {
+		stage := newCascadeStage(sc)
+		// The parent stage runs first so its detections are there to refine
+		if _, ok := stages[stage.parent]; stage.parent != "" && !ok {
+			return nil, fmt.Errorf("cascade stage %s: parent stage %s must be before it", stage.name, stage.parent)
+		}
+		stages[stage.name] = struct{}{}
+		md.cascade = append(md.cascade, stage)
+	}
+
+	if err := applyPreset(c); err != nil {
+		return nil, err
+	}
+	for _, ac := range c.Attributes {
+		if ac.Detector == "" {
+			return nil, fmt.Errorf("attribute %s: detector is required", ac.Name)
+		}
+		md.attributes = append(md.attributes, newAttributeClassifier(ac))
+	}
+
+	if c.Density != nil {
+		md.density = newDensityCounter(c.Density)
+	}
+
+	if c.Quality != nil {
+		md.quality = newQuality(c.Quality)
+	}
+
+	if c.Crops != nil {
+		var err error
+		if md.crops, err = newCrops(c.Crops); err != nil {
+			return nil, err
+		}
+	}
+
+	// Load any label translations
+	if len(c.LabelFiles) > 0 {
+		if err := md.loadTranslations(c); err != nil {
+			return nil, fmt.Errorf("could not load label translations: %v", err)
+		}
+	}
+
+	return md, nil
+
+}
+
+// loadTranslations loads the label files for each language and maps them to the detector labels
+func (d *muxDetector) loadTranslations(c *dconfig.DetectorConfig) error {
+
+	labeler, ok := d.Detector.(Labeler)
+	if !ok {
+		return fmt.Errorf("detector type %s does not support label translations", c.Type)
+	}
+	base := labeler.Labels()
+
+	d.translations = make(map[string]map[string]string)
+	dc := d.Config()
+	for lang, labelFile := range c.LabelFiles {
+		translated, err := labels.Load(c.LabelFormat, labelFile, c.ReadModel)
+		if err != nil {
+			return fmt.Errorf("could not load %s labels: %v", lang, err)
+		}
+		if len(translated) != len(base) {
+			return fmt.Errorf("%s labels has %d labels, expected %d", lang, len(translated), len(base))
+		}
+		d.translations[lang] = labels.Translate(base, translated)
+		dc.Languages = append(dc.Languages, lang)
+	}
+	sort.Strings(dc.Languages)
+
+	return nil
+
+}
+
+// GetDetectors returns the configured detectors and the detectors on the other cluster members
 func (m *Mux) GetDetectors(ctx context.Context, _ *emptypb.Empty) (*odrpc.GetDetectorsResponse, error) {
-	detectors := make([]*odrpc.Detector, 0)
-	for _, d := range m.detectors {
-		detectors = append(detectors, d.Config())
+	detectors := m.LocalDetectors()
+	if m.cluster != nil && !cluster.Forwarded(ctx) {
+		detectors = append(detectors, m.cluster.Detectors()...)
 	}
 	return &odrpc.GetDetectorsResponse{
 		Detectors: detectors,
 	}, nil
 }
 
+// LocalDetectors returns the detectors on this node
+func (m *Mux) LocalDetectors() []*odrpc.Detector {
+	detectors := make([]*odrpc.Detector, 0)
+	for _, d := range m.detectors {
+		detectors = append(detectors, d.Config())
+	}
+	return detectors
+}
+
+// SetCluster forwards requests for detectors that aren't on this node to the cluster members
+func (m *Mux) SetCluster(c *cluster.Cluster) {
+	m.cluster = c
+}
+
 // Shutdown deallocates/shuts down any detectors
 func (m *Mux) Shutdown() {
 	for _, d := range m.detectors {
@@ -109,13 +470,40 @@ func (m *Mux) Shutdown() {
 // Run a detection
 func (m *Mux) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
 
+	start := time.Now()
 	if request.DetectorName == "" {
 		request.DetectorName = "default"
 	}
 
+	// The source for sinks and state
+	source, ok := sink.SourceFromContext(ctx)
+	if !ok {
+		source = request.DetectorName
+	}
+
+	// Let the script change the request
+	if err := m.script.Request(source, request); err != nil {
+		return nil, status.Errorf(codes.Internal, "script error: %v", err)
+	}
+
 	detector, ok := m.detectors[request.DetectorName]
 	if !ok {
-		return nil, status.Errorf(codes.NotFound, "not found")
+		if m.cluster == nil || cluster.Forwarded(ctx) {
+			return nil, status.Errorf(codes.NotFound, "not found")
+		}
+		// The member can't read files on this node
+		if len(request.File) != 0 {
+			data, err := ioutil.ReadFile(request.File)
+			if err != nil {
+				return nil, status.Errorf(codes.NotFound, "could not open file %s", request.File)
+			}
+			request.Data, request.File = data, ""
+		}
+		response, err := m.cluster.Detect(ctx, request)
+		if err == nil {
+			zone.FromContext(ctx).FilterResponse(response)
+		}
+		return response, err
 	}
 
 	// If file is specified, load the data from a file
@@ -127,17 +515,234 @@ func (m *Mux) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.
 		}
 	}
 
-	response, err := detector.Detect(ctx, request)
+	// If image_url is specified, fetch the image
+	if request.ImageUrl != "" {
+		request.Data, err = m.fetcher.fetch(ctx, request.ImageUrl)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+
+	// Reject huge images before anything decodes them
+	if err = m.limits.check(request.Data); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	// Save the original image for the last event
+	named := detector
+	data := request.Data
+
+	// Check the image quality before detecting
+	var report *odrpc.Quality
+	if detector.quality != nil {
+		report = detector.quality.check(request.Data)
+		if report != nil && report.Skipped {
+			m.logger.Debugw("Skipped image", "id", request.Id, "detector", request.DetectorName, "problems", report.Problems)
+			return &odrpc.DetectResponse{
+				Id:         request.Id,
+				Detections: []*odrpc.Detection{},
+				Quality:    report,
+			}, nil
+		}
+	}
+
+	// Switch to the night detector
+	now := time.Now()
+	if detector.night != nil && detector.night.isNight(now, request.Data) {
+		if nightDetector, ok := m.detectors[detector.night.detector]; ok {
+			m.logger.Debugw("Using night detector", "id", request.Id, "detector", request.DetectorName, "night_detector", detector.night.detector)
+			detector = nightDetector
+		} else {
+			m.logger.Warnw("Night detector not found", "detector", request.DetectorName, "night_detector", detector.night.detector)
+		}
+	}
+
+	// Apply the active time of day profile
+	msk := detector.mask
+	if p := detector.activeProfile(now); p != nil {
+		m.logger.Debugw("Active profile", "id", request.Id, "profile", p.name)
+		p.apply(request)
+		if p.mask != nil {
+			msk = p.mask
+		}
+	}
+
+	// Add the regions for this detector
+	zones := m.zones.Get(zone.Target(zone.TargetDetectors, request.DetectorName))
+	zones.Apply(request)
+
+	// Enhance dark images
+	if enhance.Enabled(request.Preprocess) {
+		request.Data, err = enhance.Apply(request.Data, request.Preprocess)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "could not preprocess image: %v", err)
+		}
+	}
+
+	// Black out the masked area before detection
+	if msk != nil && detector.maskInput {
+		request.Data, err = msk.Apply(request.Data)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "could not apply mask: %v", err)
+		}
+	}
+
+	// Rotate and flip the image for the detector, the masks and zones are in the original orientation
+	if err = orient.Validate(request.Rotate, request.Flip); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	request.Data, err = orient.Apply(request.Data, request.Rotate, request.Flip)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not rotate image: %v", err)
+	}
+
+	response, detectTime, err := m.detectWithFallback(ctx, detector, request)
 	if err != nil {
+		if m.debug.wanted(request, response, err) {
+			m.debug.save(detector, request, data, response, err)
+		}
 		return response, err
 	}
+	response.Quality = report
+	orient.MapBack(response.Detections, request.Rotate, request.Flip)
+
+	// Count with the density map, the full map is kept for the heatmap of the last event
+	var density *odrpc.Density
+	if detector.density != nil && response.Error == "" {
+		density, err = detector.density.estimate(response, request.Rotate, request.Flip, msk, zones)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "could not count density: %v", err)
+		}
+		response.Density = density
+		if !request.DensityMap {
+			response.Density = &odrpc.Density{Label: density.Label, Count: density.Count, Zones: density.Zones}
+		}
+		if request.RawOutputs == odrpc.RAW_NONE {
+			response.Outputs = nil
+		}
+	}
+
+	detector.IgnoreResponse(request, response)
+	MaskResponse(msk, response)
+	zones.FilterResponse(response)
+	// The masks of the caller, like a stream
+	zone.FromContext(ctx).FilterResponse(response)
 
+	// Keep the detections below the thresholds for the review queue
+	var unfiltered []*odrpc.Detection
+	if m.review != nil {
+		unfiltered = append(unfiltered, response.Detections...)
+	}
 	m.FilterResponse(request, response)
 
+	// Compare with the shadow detector
+	if named.shadow != nil && response.Error == "" && named.shadow.sample() {
+		m.runShadow(named.shadow, request, msk, zones, response.Detections, detectTime)
+	}
+
+	// Let the script filter the response
+	if err := m.script.Response(source, request, response); err != nil {
+		return nil, status.Errorf(codes.Internal, "script error: %v", err)
+	}
+
+	// Smooth the boxes between frames from the source
+	m.smooth.Apply(source, request.Smooth, response.Detections)
+
+	// Drop the labels that haven't been seen in enough frames from the source
+	response.Detections = m.confirm.Apply(source, request.Confirm, response.Detections)
+
+	// Classify the attributes of the detections, recognize the enrolled pets and nest the detections of the cascade
+	// stages in the detections they refine
+	cascade := request.Cascade && len(named.cascade) > 0
+	if (cascade || len(named.attributes) > 0 || m.pets != nil) && len(response.Detections) > 0 {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "could not decode image: %v", err)
+		}
+		m.classifyAttributes(ctx, request, named, img, response.Detections)
+		if m.pets != nil && request.DetectorName != m.pets.Detector() {
+			m.recognizePets(ctx, request, img, response.Detections)
+		}
+		if cascade {
+			response.Detections, _ = m.runCascade(ctx, request, named, img, response.Detections, nil)
+		}
+	}
+
+	// Track the objects for the source
+	changes := m.state.Update(source, now, response.Detections)
+	m.stats.Record(source, now, time.Since(start), detectTime, response.Detections)
+	event := &sink.Event{
+		Time:     now,
+		ID:       request.Id,
+		Source:   source,
+		Detector: request.DetectorName,
+		Image:    data,
+		Response: eventResponse(response),
+		Changes:  changes,
+		Sinks:    sink.SinksFromContext(ctx),
+		Private:  sink.Private(response.Detections),
+	}
+
+	// Check the alert rules with the original labels
+	m.alerts.Process(event)
+
+	// Save the frame if the model isn't sure
+	m.review.Add(event, unfiltered)
+
+	// Keep the detections so they can be flagged as wrong
+	m.feedback.Record(event)
+
+	// Translate and sign the response and keep the event in the history
+	if err := m.finishResponse(ctx, request, detector, data, response, event); err != nil {
+		return nil, err
+	}
+
+	named.setLastEvent(data, response, density)
+
+	// Send the event to the sinks
+	if len(response.Detections) > 0 || len(changes) > 0 || response.Density.GetCount() >= 1 {
+		m.sinks.Send(event)
+	}
+
 	return response, nil
 
 }
 
+// finishResponse returns the labels in the requested language and signs the response. The event keeps the original
+// labels so the history retention and the sink filters use them, it's signed with them.
+func (m *Mux) finishResponse(ctx context.Context, request *odrpc.DetectRequest, detector *muxDetector, data []byte, response *odrpc.DetectResponse, event *sink.Event) error {
+
+	if err := m.signer.Sign(event.Response, data); err != nil {
+		return status.Errorf(codes.Internal, "could not sign response: %v", err)
+	}
+	response.Signature = event.Response.Signature
+	if detector.translateResponse(requestLanguage(ctx, request), response) {
+		if err := m.signer.Sign(response, data); err != nil {
+			return status.Errorf(codes.Internal, "could not sign response: %v", err)
+		}
+	}
+
+	// Save the request to reproduce it, frames with faces are only saved if they can be stored
+	if m.debug.wanted(request, response, nil) && !event.Private {
+		response.Debug = m.debug.save(detector, request, data, response, nil)
+	}
+	event.Response.Debug = response.Debug
+
+	// Keep the detections as signed and the frame until the retention of the labels
+	m.history.Record(event)
+
+	return nil
+
+}
+
+// eventResponse copies the response for the event so the caller can change the detections after Detect returns while
+// the sinks still read them
+func eventResponse(response *odrpc.DetectResponse) *odrpc.DetectResponse {
+	ret := *response
+	ret.Detections = append([]*odrpc.Detection(nil), response.Detections...)
+	return &ret
+}
+
 // Handle a stream of detections
 func (m *Mux) DetectStream(stream odrpc.Odrpc_DetectStreamServer) error {
 
@@ -146,7 +751,7 @@ func (m *Mux) DetectStream(stream odrpc.Odrpc_DetectStreamServer) error {
 	go func() {
 		select {
 		case <-ctx.Done():
-		case <-conf.Stop.Chan():
+		case <-m.lc.Done():
 			cancel()
 		}
 	}()
@@ -178,6 +783,10 @@ func (m *Mux) DetectStream(stream odrpc.Odrpc_DetectStreamServer) error {
 						Id:    request.Id,
 						Error: err.Error(),
 					}
+					var overload *overloadError
+					if errors.As(err, &overload) {
+						response.RetryAfterMs = overload.retryAfter.Milliseconds()
+					}
 				}
 			}
 
@@ -0,0 +1,209 @@
+// Package diff finds the regions that changed between two images of the same scene
+package diff
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// Options for comparing images
+type Options struct {
+	// The brightness difference (0-255) for a pixel to be changed
+	Threshold int
+	// The smallest region as a fraction of the image
+	MinArea float64
+	// The width the images are compared at, the height keeps the aspect of the first image
+	Width int
+}
+
+// Region is a changed area in relative coordinates. Score is the percentage of its pixels that changed.
+type Region struct {
+	Top, Left, Bottom, Right float32
+	Score                    float32
+}
+
+// Compare returns the regions that changed, largest first, and the percentage of the image that changed
+func Compare(before, after image.Image, opts Options) ([]Region, float32) {
+
+	if opts.Threshold <= 0 {
+		opts.Threshold = 25
+	}
+	if opts.MinArea <= 0 {
+		opts.MinArea = 0.001
+	}
+	if opts.Width <= 0 {
+		opts.Width = 320
+	}
+
+	// Compare small grayscale copies, it's faster and ignores noise
+	b := before.Bounds()
+	w := opts.Width
+	if w > b.Dx() {
+		w = b.Dx()
+	}
+	h := w * b.Dy() / b.Dx()
+	if w == 0 || h == 0 {
+		return []Region{}, 0
+	}
+	g1, g2 := gray(before, w, h), gray(after, w, h)
+
+	changed := make([]bool, w*h)
+	var count int
+	for i := range changed {
+		d := int(g1[i]) - int(g2[i])
+		if d > opts.Threshold || -d > opts.Threshold {
+			changed[i] = true
+			count++
+		}
+	}
+
+	// Grow the changes a pixel so parts of the same object are one region
+	grown := make([]bool, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if !changed[y*w+x] {
+				continue
+			}
+			for yy := max(y-1, 0); yy <= min(y+1, h-1); yy++ {
+				for xx := max(x-1, 0); xx <= min(x+1, w-1); xx++ {
+					grown[yy*w+xx] = true
+				}
+			}
+		}
+	}
+
+	// The bounding boxes of the connected regions
+	minPixels := int(opts.MinArea * float64(w*h))
+	regions := make([]Region, 0)
+	areas := make([]int, 0)
+	visited := make([]bool, w*h)
+	stack := make([]int, 0, 64)
+	for start := range grown {
+		if !grown[start] || visited[start] {
+			continue
+		}
+		x0, y0, x1, y1 := w, h, 0, 0
+		var pixels int
+		visited[start] = true
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x, y := i%w, i/w
+			// The box is only the changed pixels, not the grown ones
+			if changed[i] {
+				x0, y0, x1, y1 = min(x0, x), min(y0, y), max(x1, x), max(y1, y)
+				pixels++
+			}
+			for _, n := range [4]int{i - w, i + w, i - 1, i + 1} {
+				if n < 0 || n >= len(grown) || (n == i-1 && x == 0) || (n == i+1 && x == w-1) {
+					continue
+				}
+				if grown[n] && !visited[n] {
+					visited[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+		if pixels == 0 || pixels < minPixels {
+			continue
+		}
+		area := (x1 - x0 + 1) * (y1 - y0 + 1)
+		regions = append(regions, Region{
+			Top:    float32(y0) / float32(h),
+			Left:   float32(x0) / float32(w),
+			Bottom: float32(y1+1) / float32(h),
+			Right:  float32(x1+1) / float32(w),
+			Score:  100 * float32(pixels) / float32(area),
+		})
+		areas = append(areas, area)
+	}
+
+	sort.Sort(byArea{regions, areas})
+
+	return regions, 100 * float32(count) / float32(w*h)
+
+}
+
+type byArea struct {
+	regions []Region
+	areas   []int
+}
+
+func (a byArea) Len() int           { return len(a.regions) }
+func (a byArea) Less(i, j int) bool { return a.areas[i] > a.areas[j] }
+func (a byArea) Swap(i, j int) {
+	a.regions[i], a.regions[j] = a.regions[j], a.regions[i]
+	a.areas[i], a.areas[j] = a.areas[j], a.areas[i]
+}
+
+// gray returns the brightness of the image scaled to w x h by averaging the pixels of each cell
+func gray(img image.Image, w, h int) []uint8 {
+
+	b := img.Bounds()
+	sums := make([]uint32, w*h)
+	counts := make([]uint32, w*h)
+	sw, sh := b.Dx(), b.Dy()
+
+	add := func(x, y int, v uint32) {
+		i := (y*h/sh)*w + x*w/sw
+		sums[i] += v
+		counts[i]++
+	}
+
+	switch src := img.(type) {
+	case *image.YCbCr:
+		// The brightness is the Y plane
+		for y := 0; y < sh; y++ {
+			row := src.Y[src.YOffset(b.Min.X, b.Min.Y+y):][:sw]
+			for x, v := range row {
+				add(x, y, uint32(v))
+			}
+		}
+	case *image.Gray:
+		for y := 0; y < sh; y++ {
+			row := src.Pix[src.PixOffset(b.Min.X, b.Min.Y+y):][:sw]
+			for x, v := range row {
+				add(x, y, uint32(v))
+			}
+		}
+	case *image.RGBA:
+		for y := 0; y < sh; y++ {
+			row := src.Pix[src.PixOffset(b.Min.X, b.Min.Y+y):][:sw*4]
+			for x := 0; x < sw; x++ {
+				p := row[x*4:]
+				add(x, y, (19595*uint32(p[0])+38470*uint32(p[1])+7471*uint32(p[2])+1<<15)>>16)
+			}
+		}
+	default:
+		for y := 0; y < sh; y++ {
+			for x := 0; x < sw; x++ {
+				add(x, y, uint32(color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y))
+			}
+		}
+	}
+
+	ret := make([]uint8, w*h)
+	for i := range ret {
+		if counts[i] > 0 {
+			ret[i] = uint8(sums[i] / counts[i])
+		}
+	}
+	return ret
+
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
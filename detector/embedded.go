@@ -0,0 +1,47 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+
+	config "github.com/spf13/viper"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/embedded"
+)
+
+// embeddedDetector returns the config for the embedded model, it's named default unless that name is taken
+func embeddedDetector(configured []*dconfig.DetectorConfig) (*dconfig.DetectorConfig, error) {
+
+	dir := config.GetString("doods.embedded.dir")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "doods-embedded")
+	}
+	modelFile, labelFile, err := embedded.Extract(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "default"
+	for _, c := range configured {
+		if c.Name == name {
+			name = "embedded"
+			break
+		}
+	}
+	for _, c := range configured {
+		if c.Name == name {
+			return nil, nil // Already configured
+		}
+	}
+
+	return &dconfig.DetectorConfig{
+		Name:          name,
+		Type:          "tflite",
+		ModelFile:     modelFile,
+		LabelFile:     labelFile,
+		NumThreads:    1,
+		NumConcurrent: 1,
+	}, nil
+
+}
@@ -0,0 +1,64 @@
+// Package embedded has the default model and labels compiled into the binary. They are only there when built with
+// the embedmodel build tag after generating data.go with `make embed`, so doods can run without any model files.
+package embedded
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// The model and labels, set by the generated data.go
+var (
+	modelData  string
+	labelsData string
+)
+
+// Available returns true if the binary has an embedded model
+func Available() bool {
+	return modelData != ""
+}
+
+// Extract writes the embedded model and labels to the directory and returns their paths. The detectors load models
+// from files so they can be used like any other model. Files that are already there are only rewritten if they differ.
+func Extract(dir string) (modelFile string, labelFile string, err error) {
+
+	if !Available() {
+		return "", "", fmt.Errorf("doods was built without an embedded model, build with make embed and -tags embedmodel")
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", "", fmt.Errorf("could not create %s: %v", dir, err)
+	}
+
+	modelFile = filepath.Join(dir, "model.tflite")
+	if err := write(modelFile, modelData); err != nil {
+		return "", "", err
+	}
+	if labelsData != "" {
+		labelFile = filepath.Join(dir, "labels.txt")
+		if err := write(labelFile, labelsData); err != nil {
+			return "", "", err
+		}
+	}
+	return modelFile, labelFile, nil
+
+}
+
+func write(filename string, data string) error {
+	if existing, err := ioutil.ReadFile(filename); err == nil && bytes.Equal(existing, []byte(data)) {
+		return nil
+	}
+	// Write and rename so a running doods never reads a partial model
+	tmp := filename + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(data), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %v", filename, err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("could not write %s: %v", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+//go:build ignore
+// +build ignore
+
+// gen writes data.go with the model and labels for the embedmodel build tag.
+// Usage: go run detector/embedded/gen.go <model file> <label file> <output file>
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+
+	if len(os.Args) != 4 {
+		fmt.Fprintln(os.Stderr, "usage: gen <model file> <label file> <output file>")
+		os.Exit(1)
+	}
+
+	model, err := ioutil.ReadFile(os.Args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not read model: %v\n", err)
+		os.Exit(1)
+	}
+	labels, err := ioutil.ReadFile(os.Args[2])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not read labels: %v\n", err)
+		os.Exit(1)
+	}
+
+	f, err := os.Create(os.Args[3])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not create output: %v\n", err)
+		os.Exit(1)
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "//go:build embedmodel\n// +build embedmodel\n\n// Code generated by gen.go from %s; DO NOT EDIT.\n\npackage embedded\n\n", os.Args[1])
+	fmt.Fprintf(w, "func init() {\n\tmodelData = %s\n\tlabelsData = %s\n}\n", literal(model), literal(labels))
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "could not write output: %v\n", err)
+		os.Exit(1)
+	}
+	f.Close()
+
+}
+
+// literal returns the data as a string literal, the model is binary so every byte is escaped
+func literal(data []byte) string {
+	const hex = "0123456789abcdef"
+	ret := make([]byte, 0, len(data)*4+2)
+	ret = append(ret, '"')
+	for _, b := range data {
+		ret = append(ret, '\\', 'x', hex[b>>4], hex[b&0xf])
+	}
+	return string(append(ret, '"'))
+}
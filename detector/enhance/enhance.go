@@ -0,0 +1,206 @@
+// Package enhance improves the contrast and brightness of images before detection
+package enhance
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/bmp"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The defaults for CLAHE
+const (
+	DefaultClipLimit = 2
+	DefaultTiles     = 8
+)
+
+// Enabled returns true if the options change the image
+func Enabled(p *odrpc.Preprocess) bool {
+	return p != nil && (p.Clahe || (p.Gamma > 0 && p.Gamma != 1))
+}
+
+// Apply enhances the image. If it's brighter than max_brightness the original data is returned.
+func Apply(data []byte, p *odrpc.Preprocess) ([]byte, error) {
+
+	if !Enabled(p) {
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %v", err)
+	}
+
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return data, nil
+	}
+
+	// The brightness of each pixel
+	luma := make([]uint8, w*h)
+	var total int
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := rgba.PixOffset(x, y)
+			l := (299*int(rgba.Pix[i]) + 587*int(rgba.Pix[i+1]) + 114*int(rgba.Pix[i+2])) / 1000
+			luma[y*w+x] = uint8(l)
+			total += l
+		}
+	}
+	if p.MaxBrightness > 0 && float32(total)/float32(w*h) >= p.MaxBrightness {
+		return data, nil
+	}
+
+	if p.Clahe {
+		clipLimit, tiles := p.ClipLimit, int(p.Tiles)
+		if clipLimit <= 0 {
+			clipLimit = DefaultClipLimit
+		}
+		if tiles <= 0 {
+			tiles = DefaultTiles
+		}
+		equalized := clahe(luma, w, h, tiles, clipLimit)
+
+		// Scale the colors by the change in brightness
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				i := rgba.PixOffset(x, y)
+				from, to := luma[y*w+x], equalized[y*w+x]
+				if from == 0 {
+					rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2] = to, to, to
+					continue
+				}
+				factor := float32(to) / float32(from)
+				rgba.Pix[i] = clamp(float32(rgba.Pix[i]) * factor)
+				rgba.Pix[i+1] = clamp(float32(rgba.Pix[i+1]) * factor)
+				rgba.Pix[i+2] = clamp(float32(rgba.Pix[i+2]) * factor)
+			}
+		}
+	}
+
+	if p.Gamma > 0 && p.Gamma != 1 {
+		var lut [256]uint8
+		for i := range lut {
+			lut[i] = clamp(float32(255 * math.Pow(float64(i)/255, 1/float64(p.Gamma))))
+		}
+		for i := 0; i < len(rgba.Pix); i += 4 {
+			rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2] = lut[rgba.Pix[i]], lut[rgba.Pix[i+1]], lut[rgba.Pix[i+2]]
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := bmp.Encode(&buf, rgba); err != nil {
+		return nil, fmt.Errorf("could not encode image: %v", err)
+	}
+	return buf.Bytes(), nil
+
+}
+
+// clahe equalizes the histogram of each tile of the grid with the contrast limited and
+// interpolates between the tiles so there are no edges
+func clahe(luma []uint8, w, h int, tiles int, clipLimit float32) []uint8 {
+
+	if tiles > w {
+		tiles = w
+	}
+	if tiles > h {
+		tiles = h
+	}
+	tileW, tileH := (w+tiles-1)/tiles, (h+tiles-1)/tiles
+
+	// The mapping for each tile
+	luts := make([][256]uint8, tiles*tiles)
+	for ty := 0; ty < tiles; ty++ {
+		for tx := 0; tx < tiles; tx++ {
+			var hist [256]int
+			var count int
+			for y := ty * tileH; y < (ty+1)*tileH && y < h; y++ {
+				for x := tx * tileW; x < (tx+1)*tileW && x < w; x++ {
+					hist[luma[y*w+x]]++
+					count++
+				}
+			}
+			if count == 0 {
+				for i := range luts[ty*tiles+tx] {
+					luts[ty*tiles+tx][i] = uint8(i)
+				}
+				continue
+			}
+
+			// Clip the histogram and spread the excess over all the bins
+			limit := int(clipLimit * float32(count) / 256)
+			if limit < 1 {
+				limit = 1
+			}
+			var excess int
+			for i := range hist {
+				if hist[i] > limit {
+					excess += hist[i] - limit
+					hist[i] = limit
+				}
+			}
+			for i := range hist {
+				hist[i] += excess / 256
+				if i < excess%256 {
+					hist[i]++
+				}
+			}
+
+			var cdf int
+			for i := range hist {
+				cdf += hist[i]
+				luts[ty*tiles+tx][i] = uint8(cdf * 255 / count)
+			}
+		}
+	}
+
+	// Interpolate between the mappings of the 4 closest tile centers
+	out := make([]uint8, len(luma))
+	for y := 0; y < h; y++ {
+		fy := (float32(y)+0.5)/float32(tileH) - 0.5
+		ty0 := int(math.Floor(float64(fy)))
+		wy := fy - float32(ty0)
+		ty1 := ty0 + 1
+		if ty0 < 0 {
+			ty0 = 0
+		}
+		if ty1 > tiles-1 {
+			ty1 = tiles - 1
+		}
+		for x := 0; x < w; x++ {
+			fx := (float32(x)+0.5)/float32(tileW) - 0.5
+			tx0 := int(math.Floor(float64(fx)))
+			wx := fx - float32(tx0)
+			tx1 := tx0 + 1
+			if tx0 < 0 {
+				tx0 = 0
+			}
+			if tx1 > tiles-1 {
+				tx1 = tiles - 1
+			}
+			v := luma[y*w+x]
+			top := float32(luts[ty0*tiles+tx0][v])*(1-wx) + float32(luts[ty0*tiles+tx1][v])*wx
+			bottom := float32(luts[ty1*tiles+tx0][v])*(1-wx) + float32(luts[ty1*tiles+tx1][v])*wx
+			out[y*w+x] = clamp(top*(1-wy) + bottom*wy)
+		}
+	}
+	return out
+
+}
+
+func clamp(v float32) uint8 {
+	if v < 0 {
+		return 0
+	} else if v > 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
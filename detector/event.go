@@ -0,0 +1,44 @@
+package detector
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// event is a detection that had results
+type event struct {
+	time     time.Time
+	data     []byte
+	response *odrpc.DetectResponse
+	// the full density map of crowd counting models
+	density *odrpc.Density
+}
+
+// etag identifies the event for caching
+func (e *event) etag() string {
+	return strconv.FormatInt(e.time.UnixNano(), 36)
+}
+
+// setLastEvent saves the event if there were any detections or a density map
+func (d *muxDetector) setLastEvent(data []byte, response *odrpc.DetectResponse, density *odrpc.Density) {
+	if len(response.Detections) == 0 && density == nil {
+		return
+	}
+	d.lastLock.Lock()
+	d.last = &event{
+		time:     time.Now(),
+		data:     data,
+		response: response,
+		density:  density,
+	}
+	d.lastLock.Unlock()
+}
+
+// lastEvent returns the last event or nil if there hasn't been one
+func (d *muxDetector) lastEvent() *event {
+	d.lastLock.RLock()
+	defer d.lastLock.RUnlock()
+	return d.last
+}
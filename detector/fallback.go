@@ -0,0 +1,129 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// detectWithFallback detects with the detector. If it errors or times out the request is retried on its fallback
+// detectors in order and the response is flagged as degraded.
+func (m *Mux) detectWithFallback(ctx context.Context, detector *muxDetector, request *odrpc.DetectRequest) (*odrpc.DetectResponse, time.Duration, error) {
+
+	name := detector.Config().Name
+	response, detectTime, err := m.detectRetry(ctx, detector, request)
+	for _, fallbackName := range detector.fallback {
+		if !detectFailed(ctx, response, err) {
+			break
+		}
+		fallback, ok := m.detectors[fallbackName]
+		if !ok {
+			m.logger.Warnw("Fallback detector not found", "detector", name, "fallback_detector", fallbackName)
+			continue
+		}
+		m.logger.Warnw("Detector failed, using fallback", "id", request.Id, "detector", name, "fallback_detector", fallbackName, "error", detectError(response, err))
+		if response, detectTime, err = m.detectRetry(ctx, fallback, request); err == nil && response.Error == "" {
+			response.Degraded = true
+			response.FallbackDetector = fallbackName
+		}
+		name = fallbackName
+	}
+
+	return response, detectTime, err
+
+}
+
+// detectOnce waits for capacity and detects with the detector
+func (m *Mux) detectOnce(ctx context.Context, detector *muxDetector, request *odrpc.DetectRequest) (*odrpc.DetectResponse, time.Duration, error) {
+
+	// Fail fast if the detector keeps failing, before it takes any of the SLA or the capacity
+	if detector.breaker != nil && !detector.breaker.allow() {
+		return nil, 0, status.Errorf(codes.Unavailable, "detector %s is unavailable after repeated failures", detector.Config().Name)
+	}
+
+	// Reject it if it won't finish in time
+	if detector.sla != nil {
+		if err := detector.sla.admit(detector.Config().Name); err != nil {
+			if detector.breaker != nil {
+				detector.breaker.skip()
+			}
+			return nil, 0, err
+		}
+		defer detector.sla.done()
+	}
+
+	// Wait for capacity shared with the other detectors
+	if err := m.scheduler.acquire(ctx, detector.weight); err != nil {
+		if detector.breaker != nil {
+			detector.breaker.skip()
+		}
+		return nil, 0, status.Errorf(codes.DeadlineExceeded, "could not schedule detection: %v", err)
+	}
+	defer m.scheduler.release(detector.weight)
+
+	start := time.Now()
+	var response *odrpc.DetectResponse
+	var err error
+	if detector.crops != nil {
+		response, err = detector.crops.detect(ctx, detector, request)
+	} else {
+		response, err = detector.Detect(ctx, request)
+	}
+	detectTime := time.Since(start)
+
+	if detector.sla != nil && err == nil {
+		detector.sla.record(detectTime)
+	}
+
+	// Only results that say something about the detector count, a bad request doesn't close or open the breaker
+	if detector.breaker != nil && requestFailed(ctx, err) {
+		detector.breaker.skip()
+	} else if detector.breaker != nil {
+		if changed, open := detector.breaker.record(detectFailed(ctx, response, err)); changed {
+			if open {
+				m.logger.Errorw("Detector failing, breaker open", "detector", detector.Config().Name, "error", detectError(response, err), "cooldown", detector.breaker.cooldown)
+			} else {
+				m.logger.Infow("Detector recovered, breaker closed", "detector", detector.Config().Name)
+			}
+		}
+	}
+
+	return response, detectTime, err
+
+}
+
+// detectFailed returns true if the detector failed. Bad requests and canceled requests are not retried.
+func detectFailed(ctx context.Context, response *odrpc.DetectResponse, err error) bool {
+	if requestFailed(ctx, err) {
+		return false
+	}
+	return err != nil || response == nil || response.Error != ""
+}
+
+// requestFailed returns true if the request was bad or canceled, it's not a failure of the detector
+func requestFailed(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.Canceled:
+		return true
+	}
+	return false
+}
+
+// detectError returns the error or the response error for logging
+func detectError(response *odrpc.DetectResponse, err error) error {
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		return fmt.Errorf("no response")
+	}
+	return fmt.Errorf("%s", response.Error)
+}
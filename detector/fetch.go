@@ -0,0 +1,162 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	config "github.com/spf13/viper"
+)
+
+const fetchDialTimeout = 30 * time.Second
+
+// fetcher gets images from urls for detect requests
+type fetcher struct {
+	allowed  []string
+	networks []*net.IPNet
+	maxSize  int64
+	client   *http.Client
+}
+
+// newFetcher creates a fetcher from the doods.fetch config. If there are no allowed hosts, fetching is disabled.
+func newFetcher() *fetcher {
+	f := &fetcher{
+		maxSize: config.GetInt64("doods.fetch.max_size"),
+	}
+	for _, allowed := range config.GetStringSlice("doods.fetch.allowed_hosts") {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			f.networks = append(f.networks, network)
+		} else {
+			f.allowed = append(f.allowed, strings.ToLower(allowed))
+		}
+	}
+
+	// Don't use a proxy, the address that is connected to is checked
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = f.dial
+	f.client = &http.Client{
+		Transport: transport,
+		Timeout:   config.GetDuration("doods.fetch.timeout"),
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("too many redirects")
+			}
+			if !f.allowedHost(r.URL.Hostname()) {
+				return fmt.Errorf("redirect to %s is not allowed", r.URL.Hostname())
+			}
+			return nil
+		},
+	}
+	return f
+}
+
+// fetch gets the image from the url
+func (f *fetcher) fetch(ctx context.Context, imageURL string) ([]byte, error) {
+
+	u, err := url.Parse(imageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid image url")
+	}
+	if !f.allowedHost(u.Hostname()) {
+		return nil, fmt.Errorf("host %s is not allowed", u.Hostname())
+	}
+
+	request, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := f.client.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch image: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch image: %s", response.Status)
+	}
+	if f.maxSize > 0 && response.ContentLength > f.maxSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", f.maxSize)
+	}
+
+	var body io.Reader = response.Body
+	if f.maxSize > 0 {
+		body = io.LimitReader(response.Body, f.maxSize+1)
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read image: %v", err)
+	}
+	if f.maxSize > 0 && int64(len(data)) > f.maxSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", f.maxSize)
+	}
+
+	return data, nil
+
+}
+
+// allowedHost returns true if the host can be fetched from. Entries can be a host name, *.domain, a CIDR or * for any
+// host. A host name is allowed if there are CIDR entries, the address it resolves to is checked when connecting.
+func (f *fetcher) allowedHost(host string) bool {
+	if f.allowedName(host) {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return f.allowedIP(ip)
+	}
+	return len(f.networks) > 0
+}
+
+// allowedName returns true if the host matches a name entry
+func (f *fetcher) allowedName(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range f.allowed {
+		switch {
+		case allowed == "*" || allowed == host:
+			return true
+		case strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:]):
+			return true
+		}
+	}
+	return false
+}
+
+// allowedIP returns true if the address is in a CIDR entry
+func (f *fetcher) allowedIP(ip net.IP) bool {
+	for _, network := range f.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// dial connects to hosts allowed by name. Other hosts are only allowed by a CIDR entry, the address is checked after
+// it's resolved so a redirect or a DNS name can't reach other addresses.
+func (f *fetcher) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: fetchDialTimeout, KeepAlive: fetchDialTimeout}
+	if !f.allowedName(host) {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !f.allowedIP(ip) {
+				return fmt.Errorf("address %s is not allowed", host)
+			}
+			return nil
+		}
+	}
+	return dialer.DialContext(ctx, network, address)
+}
@@ -0,0 +1,93 @@
+package detector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	config "github.com/spf13/viper"
+)
+
+// testFetcher returns a fetcher for the allowed hosts
+func testFetcher(allowed ...string) *fetcher {
+	config.Set("doods.fetch.allowed_hosts", allowed)
+	defer config.Set("doods.fetch.allowed_hosts", nil)
+	return newFetcher()
+}
+
+func TestAllowedHost(t *testing.T) {
+
+	f := testFetcher("camera.lan", "*.example.com", "10.0.0.5")
+	for host, allowed := range map[string]bool{
+		"camera.lan":       true,
+		"CAMERA.lan":       true,
+		"nvr.example.com":  true,
+		"example.com":      false,
+		"nvr.example.com.": false,
+		"other.lan":        false,
+		"10.0.0.5":         true,
+		"10.0.0.6":         false,
+	} {
+		if f.allowedHost(host) != allowed {
+			t.Errorf("%s allowed should be %v", host, allowed)
+		}
+	}
+
+	// With CIDR entries other names are checked when connecting
+	f = testFetcher("camera.lan", "192.168.1.0/24")
+	for host, allowed := range map[string]bool{
+		"192.168.1.20":    true,
+		"192.168.2.20":    false,
+		"169.254.169.254": false,
+		"other.lan":       true,
+	} {
+		if f.allowedHost(host) != allowed {
+			t.Errorf("%s allowed should be %v", host, allowed)
+		}
+	}
+
+}
+
+func TestFetch(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if to := r.URL.Query().Get("redirect"); to != "" {
+			http.Redirect(w, r, to, http.StatusFound)
+			return
+		}
+		w.Write([]byte("image"))
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port := u.Port()
+
+	for _, test := range []struct {
+		allowed []string
+		url     string
+		ok      bool
+	}{
+		{[]string{"127.0.0.0/8"}, "http://127.0.0.1:" + port, true},
+		{[]string{"*"}, "http://127.0.0.1:" + port, true},
+		{[]string{"localhost"}, "http://localhost:" + port, true},
+		{[]string{"10.0.0.0/8"}, "http://127.0.0.1:" + port, false},
+		{[]string{}, "http://127.0.0.1:" + port, false},
+		{[]string{"127.0.0.1"}, "file:///etc/passwd", false},
+		// The name resolves to an address outside the CIDR
+		{[]string{"10.0.0.0/8"}, "http://localhost:" + port, false},
+		// Redirects to hosts that aren't allowed
+		{[]string{"127.0.0.1"}, "http://127.0.0.1:" + port + "/?redirect=http://localhost:" + port, false},
+		{[]string{"localhost", "10.0.0.0/8"}, "http://localhost:" + port + "/?redirect=http://127.0.0.1:" + port, false},
+		{[]string{"127.0.0.1", "10.0.0.0/8"}, "http://127.0.0.1:" + port + "/?redirect=http://localhost:" + port, false},
+		{[]string{"127.0.0.0/8"}, "http://127.0.0.1:" + port + "/?redirect=http://localhost:" + port, true},
+	} {
+		data, err := testFetcher(test.allowed...).fetch(context.Background(), test.url)
+		if test.ok && (err != nil || string(data) != "image") {
+			t.Errorf("%v %s: %q %v", test.allowed, test.url, data, err)
+		} else if !test.ok && err == nil {
+			t.Errorf("%v %s: expected an error", test.allowed, test.url)
+		}
+	}
+
+}
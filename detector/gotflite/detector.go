@@ -0,0 +1,370 @@
+package gotflite
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"time"
+
+	"go.uber.org/zap"
+	_ "golang.org/x/image/bmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/labels"
+	"github.com/snowzach/doods/detector/pixel"
+	"github.com/snowzach/doods/detector/postprocess"
+	"github.com/snowzach/doods/detector/tflite/schema"
+	"github.com/snowzach/doods/odrpc"
+)
+
+const (
+	outputDetectionPostProcess = iota
+	outputScores
+	outputRaw
+	outputPlugin
+)
+
+type detector struct {
+	config odrpc.Detector
+	logger *zap.SugaredLogger
+
+	labels       labels.Labels
+	model        *model
+	metadata     *schema.Metadata
+	outputFormat int
+	postProcess  postprocess.Func
+	pool         chan *interpreter
+
+	// How to convert the pixels to the input
+	mean    [3]float32
+	scale   [3]float32
+	timeout time.Duration
+	lc      *conf.Lifecycle
+}
+
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (*detector, error) {
+
+	if c.NumConcurrent <= 0 {
+		c.NumConcurrent = 1
+	}
+
+	d := &detector{
+		logger:  zap.S().With("package", "detector.gotflite", "name", c.Name),
+		pool:    make(chan *interpreter, c.NumConcurrent),
+		timeout: c.Timeout,
+		lc:      lc,
+	}
+
+	d.config.Name = c.Name
+	d.config.Type = c.Type
+	d.config.Model = c.ModelFile
+	d.config.Labels = make([]string, 0)
+
+	modelData, err := c.ReadModel()
+	if err != nil {
+		return nil, err
+	}
+	m, err := schema.ParseModel(modelData)
+	if err != nil {
+		return nil, fmt.Errorf("could not load model %s: %v", c.ModelFile, err)
+	}
+	if d.model, err = loadModel(m); err != nil {
+		return nil, fmt.Errorf("could not load model %s: %v", c.ModelFile, err)
+	}
+
+	// Read the model metadata if there is any
+	d.metadata, err = schema.ParseMetadata(modelData)
+	if err != nil {
+		d.logger.Warnw("Could not read model metadata", "error", err)
+	}
+
+	// Load labels, use the labels from the metadata if there is no label file
+	if c.LabelFile == "" && d.metadata != nil && d.metadata.LabelFile != "" && (c.LabelFormat == "" || c.LabelFormat == labels.FormatAuto || c.LabelFormat == labels.FormatTFLite) {
+		d.labels, err = labels.LoadEmbedded(modelData, d.metadata.LabelFile)
+	} else {
+		d.labels, err = labels.Load(c.LabelFormat, c.LabelFile, c.ReadModel)
+	}
+	if err != nil && c.RawOutputs && c.LabelFile == "" {
+		// Labels are optional for raw outputs
+		d.labels = make(labels.Labels)
+	} else if err != nil {
+		return nil, fmt.Errorf("could not load labels: %v", err)
+	}
+	d.config.Labels = d.labels.Names()
+
+	// Find the image input, other inputs aren't supported
+	if len(m.Inputs) != 1 {
+		return nil, fmt.Errorf("models with %d inputs are not supported", len(m.Inputs))
+	}
+	if c.ImageInput != "" && m.Tensors[m.Inputs[0]].Name != c.ImageInput {
+		return nil, fmt.Errorf("could not find the image input tensor %s", c.ImageInput)
+	}
+	input := m.Tensors[m.Inputs[0]]
+	if len(input.Shape) != 4 || input.Shape[0] != 1 || input.Shape[3] != 3 {
+		return nil, fmt.Errorf("unsupported input tensor shape: %v", input.Shape)
+	}
+	d.config.Height = int32(input.Shape[1])
+	d.config.Width = int32(input.Shape[2])
+	d.config.Channels = 3
+
+	// The pixels are converted to the input values with (value - mean) * scale
+	switch input.Type {
+	case schema.UInt8:
+		d.scale = [3]float32{1, 1, 1}
+		if input.Quantized() {
+			for c := range d.mean {
+				d.scale[c] = input.Scale[0]
+				if len(input.ZeroPoint) > 0 {
+					d.mean[c] = float32(input.ZeroPoint[0])
+				}
+			}
+		}
+	case schema.Float32:
+		// Use the normalization from the metadata if we have it
+		mean, std := []float32{127.5}, []float32{127.5}
+		if d.metadata != nil && len(d.metadata.Mean) > 0 && len(d.metadata.Std) > 0 {
+			mean, std = d.metadata.Mean, d.metadata.Std
+		}
+		if (len(mean) != 1 && len(mean) != 3) || (len(std) != 1 && len(std) != 3) {
+			return nil, fmt.Errorf("invalid normalization parameters mean:%v std:%v for 3 channels", mean, std)
+		}
+		for c := range d.mean {
+			d.mean[c], d.scale[c] = mean[0], 1/std[0]
+			if len(mean) > 1 {
+				d.mean[c] = mean[c]
+			}
+			if len(std) > 1 {
+				d.scale[c] = 1 / std[c]
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported tensor input type: %s", input.Type)
+	}
+	d.config.Input = &odrpc.InputSpec{Type: input.Type.String(), Layout: "NHWC"}
+	if input.Type == schema.Float32 {
+		d.config.Input.Mean, d.config.Input.Scale = d.mean[:], d.scale[:]
+	}
+
+	// The output format
+	count := len(m.Outputs)
+	if c.RawOutputs {
+		d.outputFormat = outputRaw
+	} else if c.PostProcess != nil && c.PostProcess.Plugin != "" {
+		if d.postProcess, err = postprocess.Load(c.PostProcess.Plugin, c.PostProcess.Options); err != nil {
+			return nil, err
+		}
+		d.outputFormat = outputPlugin
+	} else if count == 4 && m.Tensors[m.Outputs[0]].Name == "TFLite_Detection_PostProcess" {
+		d.outputFormat = outputDetectionPostProcess
+	} else if count == 1 && (m.Tensors[m.Outputs[0]].Name == "scores" || c.Classifier) {
+		d.outputFormat = outputScores
+		if classes := shapeSize(m.Tensors[m.Outputs[0]].Shape); classes != len(d.labels) {
+			return nil, fmt.Errorf("model has %d classes but there are %d labels", classes, len(d.labels))
+		}
+	} else {
+		return nil, fmt.Errorf("unsupported output tensor count: %d", count)
+	}
+
+	// Create the pool of interpreters, they share the model weights
+	threads := c.NumThreads
+	if threads <= 0 {
+		threads = 1
+	}
+	for x := 0; x < c.NumConcurrent; x++ {
+		interpreter, err := newInterpreter(d.model, threads)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported model %s: %v", c.ModelFile, err)
+		}
+		d.pool <- interpreter
+	}
+
+	d.logger.Infow("Loaded model", "operators", len(m.Nodes), "width", d.config.Width, "height", d.config.Height, "input_type", input.Type, "kernel", pixel.Kernel())
+
+	return d, nil
+
+}
+
+func (d *detector) Config() *odrpc.Detector {
+	return &d.config
+}
+
+func (d *detector) Labels() labels.Labels {
+	return d.labels
+}
+
+func (d *detector) Shutdown() {}
+
+func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+
+	start := time.Now()
+
+	// PPM data is already RGB, other images are decoded
+	var data []byte
+	var width, height int
+	if ppm := pixel.FindPPMData(request.Data); ppm != nil && len(request.Data)-ppm.Offset >= ppm.Width*ppm.Height*3 {
+		data, width, height = request.Data[ppm.Offset:][:ppm.Width*ppm.Height*3], ppm.Width, ppm.Height
+	} else {
+		img, _, err := image.Decode(bytes.NewReader(request.Data))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "could not decode image: %v", err)
+		}
+		bounds := img.Bounds()
+		if bounds.Empty() {
+			return nil, status.Errorf(codes.InvalidArgument, "could not read image")
+		}
+		data, width, height = pixel.RGB(img), bounds.Dx(), bounds.Dy()
+	}
+
+	// Resize to the model input
+	if int32(width) != d.config.Width || int32(height) != d.config.Height {
+		resized := make([]byte, int(d.config.Width*d.config.Height)*3)
+		pixel.Resize(resized, int(d.config.Width), int(d.config.Height), data, width, height)
+		data = resized
+	}
+
+	d.logger.Debugw("Image pre-processing complete", "id", request.Id, "duration", time.Since(start))
+
+	// Get an interpreter from the pool
+	var interpreter *interpreter
+	select {
+	case interpreter = <-d.pool:
+	case <-d.lc.Done():
+		return nil, status.Errorf(codes.Unavailable, "detector is stopping")
+	case <-ctx.Done():
+		return nil, contextError(ctx.Err())
+	}
+	done := d.lc.Track() // Wait until detection complete before stopping
+	defer func() {
+		d.pool <- interpreter
+		done()
+	}()
+
+	pixel.Normalize(interpreter.inputTensor(0).data, data, d.mean, d.scale)
+
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	inferenceStart := time.Now()
+	if err := interpreter.invoke(ctx); err != nil {
+		d.logger.Warnw("Detection stopped", "id", request.Id, "error", err)
+		return nil, contextError(err)
+	}
+	d.logger.Debugw("Inference complete", "inference_time", time.Since(inferenceStart), "duration", time.Since(start))
+
+	// Return the raw outputs if requested
+	var outputs []*odrpc.OutputTensor
+	if request.RawOutputs != odrpc.RAW_NONE || d.outputFormat == outputRaw || d.outputFormat == outputPlugin {
+		outputs = rawOutputs(interpreter)
+		if request.RawOutputs == odrpc.RAW_ONLY || d.outputFormat == outputRaw {
+			d.logger.Infow("Detection Complete", "id", request.Id, "duration", time.Since(start), "outputs", len(outputs))
+			return &odrpc.DetectResponse{
+				Id:      request.Id,
+				Outputs: outputs,
+			}, nil
+		}
+	}
+
+	detections := make([]*odrpc.Detection, 0)
+
+	switch d.outputFormat {
+	case outputDetectionPostProcess:
+		locations, classes, scores := interpreter.outputTensor(0).data, interpreter.outputTensor(1).data, interpreter.outputTensor(2).data
+		count := int(interpreter.outputTensor(3).data[0])
+		if count > len(scores) {
+			count = len(scores)
+		}
+		for i := 0; i < count; i++ {
+			label, ok := d.labels[int(classes[i])]
+			if !ok {
+				d.logger.Warnw("Missing label", "index", classes[i])
+				label = "unknown"
+			}
+			detections = append(detections, &odrpc.Detection{
+				Top:        locations[(i * 4)],
+				Left:       locations[(i*4)+1],
+				Bottom:     locations[(i*4)+2],
+				Right:      locations[(i*4)+3],
+				Label:      label,
+				Confidence: scores[i] * 100.0,
+			})
+		}
+
+	case outputPlugin:
+		var err error
+		if detections, err = d.postProcess(outputs, d.labels); err != nil {
+			d.logger.Errorw("Post-processor error", "id", request.Id, "error", err)
+			return &odrpc.DetectResponse{
+				Id:    request.Id,
+				Error: "post-processor error",
+			}, nil
+		}
+		if detections == nil {
+			detections = make([]*odrpc.Detection, 0)
+		}
+		if request.RawOutputs == odrpc.RAW_NONE {
+			outputs = nil
+		}
+
+	case outputScores:
+		scores := interpreter.outputTensor(0).data
+		for i := 0; i < len(d.labels) && i < len(scores); i++ {
+			label, ok := d.labels[i]
+			if !ok {
+				label = "unknown"
+			}
+			detections = append(detections, &odrpc.Detection{
+				Top:        0.0,
+				Left:       0.0,
+				Bottom:     1.0,
+				Right:      1.0,
+				Label:      label,
+				Confidence: 100.0 * scores[i],
+			})
+		}
+	}
+
+	d.logger.Infow("Detection Complete", "id", request.Id, "duration", time.Since(start), "detections", len(detections))
+
+	return &odrpc.DetectResponse{
+		Id:         request.Id,
+		Detections: detections,
+		Outputs:    outputs,
+	}, nil
+
+}
+
+// contextError returns the status for a canceled or timed out detection
+func contextError(err error) error {
+	if err == context.DeadlineExceeded {
+		return status.Errorf(codes.DeadlineExceeded, "detect timed out")
+	}
+	return status.Errorf(codes.Canceled, "detect canceled")
+}
+
+// rawOutputs returns the output tensors of the interpreter
+func rawOutputs(interpreter *interpreter) []*odrpc.OutputTensor {
+	outputs := make([]*odrpc.OutputTensor, 0, len(interpreter.model.Outputs))
+	for x := range interpreter.model.Outputs {
+		tensor := interpreter.outputTensor(x)
+		output := &odrpc.OutputTensor{
+			Name:   tensor.name,
+			Type:   tensor.typ.String(),
+			Values: append([]float32(nil), tensor.data...),
+		}
+		for _, dim := range tensor.shape {
+			output.Shape = append(output.Shape, int32(dim))
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
@@ -0,0 +1,318 @@
+// Package gotflite runs TFLite models with an interpreter written in Go. It supports the common operators of small
+// image models like SSD MobileNet. It's slower than the TensorFlow Lite library but needs no C libraries.
+package gotflite
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/snowzach/doods/detector/tflite/schema"
+)
+
+// maxTensorSize is the most values in a tensor, 256MB of floats
+const maxTensorSize = 1 << 26
+
+// tensor holds the values of a tensor as floats whatever the model type
+type tensor struct {
+	name  string
+	typ   schema.TensorType
+	shape []int
+	data  []float32
+
+	// Quantized activations are rounded to the quantization after each operator like the integer kernels
+	quantized  bool
+	scale      float32
+	zeroPoint  float32
+	qmin, qmax float32
+}
+
+func (t *tensor) size() int {
+	return shapeSize(t.shape)
+}
+
+// fakeQuant rounds the values to the quantization of the tensor
+func (t *tensor) fakeQuant() {
+	if !t.quantized {
+		return
+	}
+	for i, v := range t.data {
+		q := float32(math.Round(float64(v/t.scale))) + t.zeroPoint
+		if q < t.qmin {
+			q = t.qmin
+		} else if q > t.qmax {
+			q = t.qmax
+		}
+		t.data[i] = (q - t.zeroPoint) * t.scale
+	}
+}
+
+// model is a parsed model with the constant tensors converted to floats, it's shared by the interpreters
+type model struct {
+	*schema.Model
+	constants [][]float32
+}
+
+func loadModel(m *schema.Model) (*model, error) {
+	ret := &model{
+		Model:     m,
+		constants: make([][]float32, len(m.Tensors)),
+	}
+	for i, t := range m.Tensors {
+		if !validShape(t.Shape) {
+			return nil, fmt.Errorf("tensor %s shape %v is too large", t.Name, t.Shape)
+		}
+		if t.Data == nil {
+			continue
+		}
+		var err error
+		if ret.constants[i], err = dequantize(t); err != nil {
+			return nil, fmt.Errorf("tensor %s: %v", t.Name, err)
+		}
+	}
+	return ret, nil
+}
+
+// dequantize converts the data of a constant tensor to floats
+func dequantize(t *schema.Tensor) ([]float32, error) {
+
+	n := shapeSize(t.Shape)
+	var width int
+	switch t.Type {
+	case schema.Float32, schema.Int32:
+		width = 4
+	case schema.Float16, schema.Int16:
+		width = 2
+	case schema.UInt8, schema.Int8, schema.Bool:
+		width = 1
+	case schema.Int64:
+		width = 8
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t.Type)
+	}
+	if len(t.Data) < n*width {
+		return nil, fmt.Errorf("has %d bytes, expected %d", len(t.Data), n*width)
+	}
+
+	// The scale is per channel of the quantized dimension
+	stride, channels := 1, 1
+	if len(t.Scale) > 1 && t.QuantizedDimension < len(t.Shape) {
+		channels = t.Shape[t.QuantizedDimension]
+		stride = shapeSize(t.Shape[t.QuantizedDimension+1:])
+	}
+
+	ret := make([]float32, n)
+	for i := range ret {
+		var v float64
+		switch t.Type {
+		case schema.Float32:
+			ret[i] = math.Float32frombits(binary.LittleEndian.Uint32(t.Data[4*i:]))
+			continue
+		case schema.Float16:
+			ret[i] = float16(binary.LittleEndian.Uint16(t.Data[2*i:]))
+			continue
+		case schema.Int32:
+			v = float64(int32(binary.LittleEndian.Uint32(t.Data[4*i:])))
+		case schema.Int16:
+			v = float64(int16(binary.LittleEndian.Uint16(t.Data[2*i:])))
+		case schema.UInt8, schema.Bool:
+			v = float64(t.Data[i])
+		case schema.Int8:
+			v = float64(int8(t.Data[i]))
+		case schema.Int64:
+			v = float64(int64(binary.LittleEndian.Uint64(t.Data[8*i:])))
+		}
+		if t.Quantized() && t.Type != schema.Bool {
+			c := (i / stride) % channels
+			if c >= len(t.Scale) {
+				c = 0
+			}
+			var zp float64
+			if c < len(t.ZeroPoint) {
+				zp = float64(t.ZeroPoint[c])
+			}
+			v = (v - zp) * float64(t.Scale[c])
+		}
+		ret[i] = float32(v)
+	}
+	return ret, nil
+
+}
+
+// float16 converts a half precision float
+func float16(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h) & 0x3ff
+	switch {
+	case exp == 0 && frac == 0:
+		return math.Float32frombits(sign)
+	case exp == 0:
+		// Subnormal
+		v := float32(frac) / 1024 / 16384
+		if sign != 0 {
+			v = -v
+		}
+		return v
+	case exp == 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | frac<<13)
+	}
+	return math.Float32frombits(sign | (exp+112)<<23 | frac<<13)
+}
+
+// kernel runs an operator
+type kernel func()
+
+// interpreter runs a model, it's not safe for concurrent use
+type interpreter struct {
+	model   *model
+	tensors []*tensor
+	kernels []kernel
+	threads int
+}
+
+func newInterpreter(m *model, threads int) (*interpreter, error) {
+
+	ip := &interpreter{
+		model:   m,
+		tensors: make([]*tensor, len(m.Tensors)),
+		threads: threads,
+	}
+
+	for i, t := range m.Tensors {
+		tt := &tensor{
+			name:  t.Name,
+			typ:   t.Type,
+			shape: t.Shape,
+			data:  m.constants[i],
+		}
+		if tt.data == nil {
+			tt.data = make([]float32, shapeSize(t.Shape))
+			// Only activations are rounded, constants are already on the grid
+			if t.Quantized() && len(t.Scale) == 1 {
+				tt.quantized = true
+				tt.scale = t.Scale[0]
+				if len(t.ZeroPoint) > 0 {
+					tt.zeroPoint = float32(t.ZeroPoint[0])
+				}
+				switch t.Type {
+				case schema.UInt8:
+					tt.qmin, tt.qmax = 0, 255
+				case schema.Int8:
+					tt.qmin, tt.qmax = -128, 127
+				case schema.Int16:
+					tt.qmin, tt.qmax = -32768, 32767
+				default:
+					tt.quantized = false
+				}
+			}
+		}
+		ip.tensors[i] = tt
+	}
+
+	for i, node := range m.Nodes {
+		k, err := ip.build(node)
+		if err != nil {
+			return nil, fmt.Errorf("operator %d (%s): %v", i, nodeName(node), err)
+		}
+		ip.kernels = append(ip.kernels, k)
+	}
+
+	return ip, nil
+
+}
+
+func nodeName(node *schema.Node) string {
+	if node.Operator == schema.Custom {
+		return node.CustomCode
+	}
+	return node.Operator.String()
+}
+
+// input returns the tensor of an operator input or nil if it's not set
+func (ip *interpreter) input(node *schema.Node, i int) *tensor {
+	if i >= len(node.Inputs) || node.Inputs[i] < 0 {
+		return nil
+	}
+	return ip.tensors[node.Inputs[i]]
+}
+
+func (ip *interpreter) output(node *schema.Node, i int) *tensor {
+	if i >= len(node.Outputs) || node.Outputs[i] < 0 {
+		return nil
+	}
+	return ip.tensors[node.Outputs[i]]
+}
+
+// inputTensor returns a subgraph input
+func (ip *interpreter) inputTensor(i int) *tensor {
+	return ip.tensors[ip.model.Inputs[i]]
+}
+
+// outputTensor returns a subgraph output
+func (ip *interpreter) outputTensor(i int) *tensor {
+	return ip.tensors[ip.model.Outputs[i]]
+}
+
+// invoke runs the operators in order, it stops early if the context is canceled
+func (ip *interpreter) invoke(ctx context.Context) error {
+	for i, k := range ip.kernels {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		k()
+		for _, o := range ip.model.Nodes[i].Outputs {
+			if o >= 0 {
+				ip.tensors[o].fakeQuant()
+			}
+		}
+	}
+	return nil
+}
+
+// parallel splits n rows over the threads
+func parallel(threads int, n int, f func(start, end int)) {
+	if threads <= 1 || n < 2 {
+		f(0, n)
+		return
+	}
+	if threads > n {
+		threads = n
+	}
+	chunk := (n + threads - 1) / threads
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunk {
+		end := start + chunk
+		if end > n {
+			end = n
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			f(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// validShape returns true if the tensor is small enough to allocate, a corrupt shape could be anything
+func validShape(shape []int) bool {
+	n := 1
+	for _, d := range shape {
+		if d < 0 || (d > 0 && n > maxTensorSize/d) {
+			return false
+		}
+		n *= d
+	}
+	return true
+}
+
+func shapeSize(shape []int) int {
+	n := 1
+	for _, d := range shape {
+		n *= d
+	}
+	return n
+}
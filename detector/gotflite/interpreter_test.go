@@ -0,0 +1,140 @@
+package gotflite
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/snowzach/doods/detector/tflite/schema"
+)
+
+var update = flag.Bool("update", false, "rewrite the test model in testdata")
+
+const tinyModelFile = "tiny.tflite"
+
+// tinyModel is a small float model with the common image operators: a 4x4 image is padded, a 3x3 convolution with two
+// filters (edges and blur) and a relu, a 3x3 depthwise convolution with stride 2 and a bilinear resize back to 4x4.
+func tinyModel() []byte {
+	return testModel([]testTensor{
+		{name: "image", shape: []int32{1, 4, 4, 1}},
+		intTensor("paddings", []int32{4, 2}, 0, 0, 1, 1, 1, 1, 0, 0),
+		{name: "padded", shape: []int32{1, 6, 6, 1}},
+		floatTensor("conv/filter", []int32{2, 3, 3, 1},
+			0, 1, 0, 1, -4, 1, 0, 1, 0,
+			1.0/9, 1.0/9, 1.0/9, 1.0/9, 1.0/9, 1.0/9, 1.0/9, 1.0/9, 1.0/9,
+		),
+		floatTensor("conv/bias", []int32{2}, 0.5, 0),
+		{name: "conv", shape: []int32{1, 4, 4, 2}},
+		floatTensor("depthwise/filter", []int32{1, 3, 3, 2},
+			0.1, -0.2, 0.2, 0.3, 0.3, -0.4,
+			0.4, 0.5, 0.5, -0.6, 0.6, 0.7,
+			0.7, -0.8, 0.8, 0.9, 0.9, -1,
+		),
+		floatTensor("depthwise/bias", []int32{2}, -0.25, 0.125),
+		{name: "depthwise", shape: []int32{1, 2, 2, 2}},
+		intTensor("size", []int32{2}, 4, 4),
+		{name: "output", shape: []int32{1, 4, 4, 2}},
+	}, []testNode{
+		{op: int32(schema.Pad), inputs: []int32{0, 1}, outputs: []int32{2}},
+		{op: int32(schema.Conv2D), inputs: []int32{2, 3, 4}, outputs: []int32{5}, options: fbTable{int8(paddingValid), int32(1), int32(1), int8(activationRelu)}},
+		{op: int32(schema.DepthwiseConv2D), inputs: []int32{5, 6, 7}, outputs: []int32{8}, options: fbTable{int8(paddingSame), int32(2), int32(2), int32(1)}},
+		{op: int32(schema.ResizeBilinear), inputs: []int32{8, 9}, outputs: []int32{10}, options: fbTable{nil, nil, false, true}},
+	}, []int32{0}, []int32{10})
+}
+
+// tinyInput is the image of the tiny model
+func tinyInput() []float32 {
+	return []float32{
+		0.1, 0.9, 0.4, 0.2,
+		0.8, 0.3, 0.7, 0.5,
+		0.6, 0.2, 1.0, 0.0,
+		0.3, 0.5, 0.1, 0.7,
+	}
+}
+
+// The output of the tiny model, it was computed from the TFLite operator definitions without the interpreter
+var tinyOutput = []float32{
+	2.77, -0.02833333, 2.6025, 0.006111111, 2.2675, 0.075, 2.1, 0.1094444,
+	2.505, 0.01388889, 2.348125, 0.040625, 2.034375, 0.09409722, 1.8775, 0.1208333,
+	1.975, 0.09833333, 1.839375, 0.1096528, 1.568125, 0.1322917, 1.4325, 0.1436111,
+	1.71, 0.1405556, 1.585, 0.1441667, 1.335, 0.1513889, 1.21, 0.155,
+}
+
+func TestTinyModel(t *testing.T) {
+
+	filename := filepath.Join("testdata", tinyModelFile)
+	if *update {
+		if err := ioutil.WriteFile(filename, tinyModel(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkValues(t, runModel(t, buf, tinyInput()), tinyOutput)
+
+}
+
+// loadCorrupt loads and runs a corrupt model, it returns an error if any step fails
+func loadCorrupt(buf []byte) (err error) {
+
+	// Panics are errors for the test
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	if _, err := schema.ParseMetadata(buf); err != nil {
+		return nil
+	}
+	m, err := schema.ParseModel(buf)
+	if err != nil {
+		return nil
+	}
+	lm, err := loadModel(m)
+	if err != nil {
+		return nil
+	}
+	ip, err := newInterpreter(lm, 1)
+	if err != nil {
+		return nil
+	}
+	// Corrupt shapes that are still consistent only take longer to run
+	for _, t := range ip.tensors {
+		if t.size() > 1024 {
+			return nil
+		}
+	}
+	return ip.invoke(context.Background())
+
+}
+
+func TestMalformedModel(t *testing.T) {
+
+	buf := tinyModel()
+
+	// Every truncation
+	for n := 0; n < len(buf); n++ {
+		if err := loadCorrupt(buf[:n]); err != nil {
+			t.Fatalf("truncated to %d bytes: %v", n, err)
+		}
+	}
+
+	// Every byte changed to a few values that make offsets and lengths out of range or negative
+	for i := range buf {
+		for _, v := range []byte{0x00, 0x7f, 0xff} {
+			corrupt := append([]byte(nil), buf...)
+			corrupt[i] = v
+			if err := loadCorrupt(corrupt); err != nil {
+				t.Fatalf("byte %d set to %#x: %v", i, v, err)
+			}
+		}
+	}
+
+}
@@ -0,0 +1,206 @@
+package gotflite
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/snowzach/doods/detector/tflite/schema"
+)
+
+// fbTable is a flatbuffers table for writing test models, the values are by field id and nil values are not set. The
+// values can be int8, bool, int32, uint32, float32, string, []byte, []int32, []float32, []int64, fbTable or []fbTable.
+type fbTable []interface{}
+
+// fbWriter writes the tables in order from the root so every offset points forward like the reader expects
+type fbWriter struct {
+	buf []byte
+}
+
+// writeModel returns a TFLite model file with the model table as the root
+func writeModel(model fbTable) []byte {
+	w := &fbWriter{buf: make([]byte, 8)}
+	copy(w.buf[4:], "TFL3")
+	w.patch(0, w.table(model))
+	return w.buf
+}
+
+func (w *fbWriter) align(n int) {
+	for len(w.buf)%n != 0 {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func (w *fbWriter) uint32(v uint32) int {
+	pos := len(w.buf)
+	w.buf = append(w.buf, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(w.buf[pos:], v)
+	return pos
+}
+
+// patch sets the offset at pos to the target
+func (w *fbWriter) patch(pos int, target int) {
+	binary.LittleEndian.PutUint32(w.buf[pos:], uint32(target-pos))
+}
+
+// table writes the vtable and then the table with 8 bytes for every field, the referenced values follow
+func (w *fbWriter) table(t fbTable) int {
+
+	w.align(4)
+	vtable := len(w.buf)
+	size := 4 + 8*len(t)
+	for _, v := range []int{4 + 2*len(t), size} {
+		w.buf = append(w.buf, byte(v), byte(v>>8))
+	}
+	for id, v := range t {
+		offset := 0
+		if v != nil {
+			offset = 4 + 8*id
+		}
+		w.buf = append(w.buf, byte(offset), byte(offset>>8))
+	}
+
+	w.align(4)
+	pos := w.uint32(0)
+	binary.LittleEndian.PutUint32(w.buf[pos:], uint32(pos-vtable))
+	w.buf = append(w.buf, make([]byte, 8*len(t))...)
+
+	var refs []func()
+	for id, v := range t {
+		field := pos + 4 + 8*id
+		switch v := v.(type) {
+		case nil:
+		case int8:
+			w.buf[field] = byte(v)
+		case bool:
+			if v {
+				w.buf[field] = 1
+			}
+		case int32:
+			binary.LittleEndian.PutUint32(w.buf[field:], uint32(v))
+		case uint32:
+			binary.LittleEndian.PutUint32(w.buf[field:], v)
+		case float32:
+			binary.LittleEndian.PutUint32(w.buf[field:], math.Float32bits(v))
+		default:
+			refs = append(refs, func() { w.patch(field, w.value(v)) })
+		}
+	}
+	for _, ref := range refs {
+		ref()
+	}
+	return pos
+
+}
+
+// value writes a string, vector or table and returns its position
+func (w *fbWriter) value(v interface{}) int {
+
+	if t, ok := v.(fbTable); ok {
+		return w.table(t)
+	}
+
+	w.align(8)
+	switch v := v.(type) {
+	case string:
+		pos := w.uint32(uint32(len(v)))
+		w.buf = append(append(w.buf, v...), 0)
+		return pos
+	case []byte:
+		pos := w.uint32(uint32(len(v)))
+		w.buf = append(w.buf, v...)
+		return pos
+	case []int32:
+		pos := w.uint32(uint32(len(v)))
+		for _, e := range v {
+			w.uint32(uint32(e))
+		}
+		return pos
+	case []float32:
+		pos := w.uint32(uint32(len(v)))
+		for _, e := range v {
+			w.uint32(math.Float32bits(e))
+		}
+		return pos
+	case []int64:
+		// The elements are aligned to 8 bytes after the length
+		w.buf = append(w.buf, 0, 0, 0, 0)
+		pos := w.uint32(uint32(len(v)))
+		for _, e := range v {
+			w.uint32(uint32(e))
+			w.uint32(uint32(e >> 32))
+		}
+		return pos
+	case []fbTable:
+		pos := w.uint32(uint32(len(v)))
+		for range v {
+			w.uint32(0)
+		}
+		for i, t := range v {
+			w.patch(pos+4+4*i, w.table(t))
+		}
+		return pos
+	}
+	panic("unsupported flatbuffer value")
+
+}
+
+// testTensor is a tensor of a test model, data is set for constants
+type testTensor struct {
+	name  string
+	typ   schema.TensorType
+	shape []int32
+	data  []byte
+}
+
+// floatTensor returns a float constant
+func floatTensor(name string, shape []int32, values ...float32) testTensor {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(v))
+	}
+	return testTensor{name: name, typ: schema.Float32, shape: shape, data: data}
+}
+
+// intTensor returns an int32 constant
+func intTensor(name string, shape []int32, values ...int32) testTensor {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[4*i:], uint32(v))
+	}
+	return testTensor{name: name, typ: schema.Int32, shape: shape, data: data}
+}
+
+// testNode is an operator of a test model
+type testNode struct {
+	op      int32
+	inputs  []int32
+	outputs []int32
+	options fbTable
+}
+
+// testModel returns a TFLite model file with one subgraph
+func testModel(tensors []testTensor, nodes []testNode, inputs []int32, outputs []int32) []byte {
+
+	// Buffer 0 is the empty buffer of the tensors without data
+	buffers := []fbTable{{}}
+	var tt []fbTable
+	for _, t := range tensors {
+		table := fbTable{t.shape, int8(t.typ), uint32(0), t.name}
+		if t.data != nil {
+			table[2] = uint32(len(buffers))
+			buffers = append(buffers, fbTable{t.data})
+		}
+		tt = append(tt, table)
+	}
+
+	// One operator code per operator
+	var codes, operators []fbTable
+	for i, n := range nodes {
+		codes = append(codes, fbTable{int8(n.op), nil, int32(1), n.op})
+		operators = append(operators, fbTable{uint32(i), n.inputs, n.outputs, nil, n.options})
+	}
+
+	subgraph := fbTable{tt, inputs, outputs, operators}
+	return writeModel(fbTable{uint32(3), codes, []fbTable{subgraph}, "test", buffers})
+
+}
@@ -0,0 +1,865 @@
+package gotflite
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/snowzach/doods/detector/tflite/schema"
+)
+
+// Fused activations
+const (
+	activationNone      = 0
+	activationRelu      = 1
+	activationReluN1To1 = 2
+	activationRelu6     = 3
+	activationTanh      = 4
+)
+
+// Padding types
+const (
+	paddingSame  = 0
+	paddingValid = 1
+)
+
+// build checks the operator and returns the kernel that runs it
+func (ip *interpreter) build(node *schema.Node) (kernel, error) {
+
+	if ip.input(node, 0) == nil || ip.output(node, 0) == nil {
+		return nil, fmt.Errorf("missing input or output")
+	}
+	for _, i := range node.Inputs {
+		if i >= 0 && ip.tensors[i].typ == schema.String {
+			return nil, fmt.Errorf("string tensors are not supported")
+		}
+	}
+
+	opts := node.Options
+	switch node.Operator {
+	case schema.Conv2D:
+		return ip.conv2D(node)
+	case schema.DepthwiseConv2D:
+		return ip.depthwiseConv2D(node)
+	case schema.AveragePool2D, schema.MaxPool2D:
+		return ip.pool2D(node)
+	case schema.FullyConnected:
+		return ip.fullyConnected(node)
+
+	case schema.Add:
+		return ip.binary(node, opts.Byte(0, 0), func(a, b float32) float32 { return a + b })
+	case schema.Sub:
+		return ip.binary(node, opts.Byte(0, 0), func(a, b float32) float32 { return a - b })
+	case schema.Mul:
+		return ip.binary(node, opts.Byte(0, 0), func(a, b float32) float32 { return a * b })
+	case schema.Div:
+		return ip.binary(node, opts.Byte(0, 0), func(a, b float32) float32 { return a / b })
+	case schema.Maximum:
+		return ip.binary(node, activationNone, func(a, b float32) float32 { return float32(math.Max(float64(a), float64(b))) })
+	case schema.Minimum:
+		return ip.binary(node, activationNone, func(a, b float32) float32 { return float32(math.Min(float64(a), float64(b))) })
+	case schema.SquaredDifference:
+		return ip.binary(node, activationNone, func(a, b float32) float32 { return (a - b) * (a - b) })
+
+	case schema.Relu:
+		return ip.unary(node, func(v float32) float32 { return clamp(v, 0, math.MaxFloat32) })
+	case schema.Relu6:
+		return ip.unary(node, func(v float32) float32 { return clamp(v, 0, 6) })
+	case schema.ReluN1To1:
+		return ip.unary(node, func(v float32) float32 { return clamp(v, -1, 1) })
+	case schema.Logistic:
+		return ip.unary(node, func(v float32) float32 { return float32(1 / (1 + math.Exp(-float64(v)))) })
+	case schema.Tanh:
+		return ip.unary(node, func(v float32) float32 { return float32(math.Tanh(float64(v))) })
+	case schema.Exp:
+		return ip.unary(node, func(v float32) float32 { return float32(math.Exp(float64(v))) })
+	case schema.HardSwish:
+		return ip.unary(node, func(v float32) float32 { return v * clamp(v+3, 0, 6) / 6 })
+	case schema.LeakyRelu:
+		alpha := opts.Float(0, 0)
+		return ip.unary(node, func(v float32) float32 {
+			if v < 0 {
+				return v * alpha
+			}
+			return v
+		})
+
+	case schema.Reshape, schema.Squeeze, schema.ExpandDims, schema.Dequantize, schema.Quantize:
+		return ip.copy(node)
+	case schema.Concatenation:
+		return ip.concatenation(node)
+	case schema.Softmax:
+		return ip.softmax(node)
+	case schema.Pad, schema.PadV2:
+		return ip.pad(node)
+	case schema.Mean:
+		return ip.mean(node)
+	case schema.Transpose:
+		return ip.transpose(node)
+	case schema.ResizeBilinear:
+		return ip.resize(node, true, opts.Bool(2), opts.Bool(3))
+	case schema.ResizeNearestNeighbor:
+		return ip.resize(node, false, opts.Bool(0), opts.Bool(1))
+
+	case schema.Custom:
+		if node.CustomCode == "TFLite_Detection_PostProcess" {
+			return ip.detectionPostProcess(node)
+		}
+		return nil, fmt.Errorf("unsupported custom operator")
+	}
+
+	return nil, fmt.Errorf("unsupported operator")
+
+}
+
+func clamp(v, min, max float32) float32 {
+	if v < min {
+		return min
+	} else if v > max {
+		return max
+	}
+	return v
+}
+
+// activation returns the fused activation function or nil for none
+func activation(code int) (func(float32) float32, error) {
+	switch code {
+	case activationNone:
+		return nil, nil
+	case activationRelu:
+		return func(v float32) float32 { return clamp(v, 0, math.MaxFloat32) }, nil
+	case activationReluN1To1:
+		return func(v float32) float32 { return clamp(v, -1, 1) }, nil
+	case activationRelu6:
+		return func(v float32) float32 { return clamp(v, 0, 6) }, nil
+	case activationTanh:
+		return func(v float32) float32 { return float32(math.Tanh(float64(v))) }, nil
+	}
+	return nil, fmt.Errorf("unsupported fused activation %d", code)
+}
+
+// constant returns the values of a constant input
+func (ip *interpreter) constant(node *schema.Node, i int) ([]float32, error) {
+	if i >= len(node.Inputs) || node.Inputs[i] < 0 || ip.model.constants[node.Inputs[i]] == nil {
+		return nil, fmt.Errorf("input %d must be constant", i)
+	}
+	return ip.model.constants[node.Inputs[i]], nil
+}
+
+// padding returns the padding before the first element (top or left) like TFLite
+func padding(padding int, in, out, filter, stride, dilation int) int {
+	if padding == paddingValid {
+		return 0
+	}
+	total := (out-1)*stride + (filter-1)*dilation + 1 - in
+	if total < 0 {
+		return 0
+	}
+	return total / 2
+}
+
+func checkRank(t *tensor, rank int) error {
+	if len(t.shape) != rank {
+		return fmt.Errorf("tensor %s has shape %v, expected %d dimensions", t.name, t.shape, rank)
+	}
+	return nil
+}
+
+func (ip *interpreter) conv2D(node *schema.Node) (kernel, error) {
+
+	input, filter, bias, out := ip.input(node, 0), ip.input(node, 1), ip.input(node, 2), ip.output(node, 0)
+	opts := node.Options
+	pad, strideW, strideH := opts.Byte(0, paddingSame), opts.Int(1, 1), opts.Int(2, 1)
+	dilationW, dilationH := opts.Int(4, 1), opts.Int(5, 1)
+	act, err := activation(opts.Byte(3, activationNone))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range []*tensor{input, filter, out} {
+		if t == nil {
+			return nil, fmt.Errorf("missing tensor")
+		} else if err := checkRank(t, 4); err != nil {
+			return nil, err
+		}
+	}
+
+	batches, h, w, inC := input.shape[0], input.shape[1], input.shape[2], input.shape[3]
+	outC, kh, kw := filter.shape[0], filter.shape[1], filter.shape[2]
+	oh, ow := out.shape[1], out.shape[2]
+	if filter.shape[3] != inC || out.shape[3] != outC || out.shape[0] != batches {
+		return nil, fmt.Errorf("filter %v does not match input %v and output %v", filter.shape, input.shape, out.shape)
+	}
+	if bias != nil && bias.size() != outC {
+		return nil, fmt.Errorf("bias has %d values, expected %d", bias.size(), outC)
+	}
+	padT := padding(pad, h, oh, kh, strideH, dilationH)
+	padL := padding(pad, w, ow, kw, strideW, dilationW)
+
+	return func() {
+		parallel(ip.threads, batches*oh, func(start, end int) {
+			for row := start; row < end; row++ {
+				b, oy := row/oh, row%oh
+				for ox := 0; ox < ow; ox++ {
+					o := out.data[((b*oh+oy)*ow+ox)*outC:][:outC]
+					for co := range o {
+						var sum float32
+						if bias != nil {
+							sum = bias.data[co]
+						}
+						f := filter.data[co*kh*kw*inC:]
+						for ky := 0; ky < kh; ky++ {
+							iy := oy*strideH - padT + ky*dilationH
+							if iy < 0 || iy >= h {
+								continue
+							}
+							for kx := 0; kx < kw; kx++ {
+								ix := ox*strideW - padL + kx*dilationW
+								if ix < 0 || ix >= w {
+									continue
+								}
+								sum += dot(input.data[((b*h+iy)*w+ix)*inC:][:inC], f[(ky*kw+kx)*inC:][:inC])
+							}
+						}
+						if act != nil {
+							sum = act(sum)
+						}
+						o[co] = sum
+					}
+				}
+			}
+		})
+	}, nil
+
+}
+
+func (ip *interpreter) depthwiseConv2D(node *schema.Node) (kernel, error) {
+
+	input, filter, bias, out := ip.input(node, 0), ip.input(node, 1), ip.input(node, 2), ip.output(node, 0)
+	opts := node.Options
+	pad, strideW, strideH := opts.Byte(0, paddingSame), opts.Int(1, 1), opts.Int(2, 1)
+	dilationW, dilationH := opts.Int(5, 1), opts.Int(6, 1)
+	act, err := activation(opts.Byte(4, activationNone))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range []*tensor{input, filter, out} {
+		if t == nil {
+			return nil, fmt.Errorf("missing tensor")
+		} else if err := checkRank(t, 4); err != nil {
+			return nil, err
+		}
+	}
+
+	batches, h, w, inC := input.shape[0], input.shape[1], input.shape[2], input.shape[3]
+	kh, kw, outC := filter.shape[1], filter.shape[2], filter.shape[3]
+	oh, ow := out.shape[1], out.shape[2]
+	if filter.shape[0] != 1 || out.shape[3] != outC || out.shape[0] != batches || inC == 0 || outC%inC != 0 {
+		return nil, fmt.Errorf("filter %v does not match input %v and output %v", filter.shape, input.shape, out.shape)
+	}
+	if bias != nil && bias.size() != outC {
+		return nil, fmt.Errorf("bias has %d values, expected %d", bias.size(), outC)
+	}
+	multiplier := outC / inC
+	padT := padding(pad, h, oh, kh, strideH, dilationH)
+	padL := padding(pad, w, ow, kw, strideW, dilationW)
+
+	return func() {
+		parallel(ip.threads, batches*oh, func(start, end int) {
+			for row := start; row < end; row++ {
+				b, oy := row/oh, row%oh
+				for ox := 0; ox < ow; ox++ {
+					o := out.data[((b*oh+oy)*ow+ox)*outC:][:outC]
+					if bias != nil {
+						copy(o, bias.data)
+					} else {
+						for co := range o {
+							o[co] = 0
+						}
+					}
+					for ky := 0; ky < kh; ky++ {
+						iy := oy*strideH - padT + ky*dilationH
+						if iy < 0 || iy >= h {
+							continue
+						}
+						for kx := 0; kx < kw; kx++ {
+							ix := ox*strideW - padL + kx*dilationW
+							if ix < 0 || ix >= w {
+								continue
+							}
+							in := input.data[((b*h+iy)*w+ix)*inC:][:inC]
+							f := filter.data[(ky*kw+kx)*outC:][:outC]
+							if multiplier == 1 {
+								for c, v := range in {
+									o[c] += v * f[c]
+								}
+							} else {
+								for co := range o {
+									o[co] += in[co/multiplier] * f[co]
+								}
+							}
+						}
+					}
+					if act != nil {
+						for co, v := range o {
+							o[co] = act(v)
+						}
+					}
+				}
+			}
+		})
+	}, nil
+
+}
+
+func (ip *interpreter) pool2D(node *schema.Node) (kernel, error) {
+
+	input, out := ip.input(node, 0), ip.output(node, 0)
+	opts := node.Options
+	pad, strideW, strideH := opts.Byte(0, paddingSame), opts.Int(1, 1), opts.Int(2, 1)
+	kw, kh := opts.Int(3, 1), opts.Int(4, 1)
+	act, err := activation(opts.Byte(5, activationNone))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range []*tensor{input, out} {
+		if err := checkRank(t, 4); err != nil {
+			return nil, err
+		}
+	}
+	batches, h, w, c := input.shape[0], input.shape[1], input.shape[2], input.shape[3]
+	oh, ow := out.shape[1], out.shape[2]
+	if out.shape[3] != c || out.shape[0] != batches {
+		return nil, fmt.Errorf("input %v does not match output %v", input.shape, out.shape)
+	}
+	padT := padding(pad, h, oh, kh, strideH, 1)
+	padL := padding(pad, w, ow, kw, strideW, 1)
+	max := node.Operator == schema.MaxPool2D
+
+	return func() {
+		for b := 0; b < batches; b++ {
+			for oy := 0; oy < oh; oy++ {
+				for ox := 0; ox < ow; ox++ {
+					o := out.data[((b*oh+oy)*ow+ox)*c:][:c]
+					for ch := range o {
+						// Averages only count the values inside the input
+						var count int
+						v := float32(0)
+						if max {
+							v = -math.MaxFloat32
+						}
+						for ky := 0; ky < kh; ky++ {
+							iy := oy*strideH - padT + ky
+							if iy < 0 || iy >= h {
+								continue
+							}
+							for kx := 0; kx < kw; kx++ {
+								ix := ox*strideW - padL + kx
+								if ix < 0 || ix >= w {
+									continue
+								}
+								in := input.data[((b*h+iy)*w+ix)*c+ch]
+								if max {
+									if in > v {
+										v = in
+									}
+								} else {
+									v += in
+								}
+								count++
+							}
+						}
+						if !max && count > 0 {
+							v /= float32(count)
+						}
+						if act != nil {
+							v = act(v)
+						}
+						o[ch] = v
+					}
+				}
+			}
+		}
+	}, nil
+
+}
+
+func (ip *interpreter) fullyConnected(node *schema.Node) (kernel, error) {
+
+	input, weights, bias, out := ip.input(node, 0), ip.input(node, 1), ip.input(node, 2), ip.output(node, 0)
+	act, err := activation(node.Options.Byte(0, activationNone))
+	if err != nil {
+		return nil, err
+	}
+	if weights == nil {
+		return nil, fmt.Errorf("missing weights")
+	} else if err := checkRank(weights, 2); err != nil {
+		return nil, err
+	}
+	units, depth := weights.shape[0], weights.shape[1]
+	if depth == 0 || input.size()%depth != 0 {
+		return nil, fmt.Errorf("input %v does not match weights %v", input.shape, weights.shape)
+	}
+	batches := input.size() / depth
+	if out.size() != batches*units {
+		return nil, fmt.Errorf("output %v does not match %d batches of %d units", out.shape, batches, units)
+	}
+	if bias != nil && bias.size() != units {
+		return nil, fmt.Errorf("bias has %d values, expected %d", bias.size(), units)
+	}
+
+	return func() {
+		parallel(ip.threads, units, func(start, end int) {
+			for b := 0; b < batches; b++ {
+				in := input.data[b*depth:][:depth]
+				for u := start; u < end; u++ {
+					sum := dot(in, weights.data[u*depth:][:depth])
+					if bias != nil {
+						sum += bias.data[u]
+					}
+					if act != nil {
+						sum = act(sum)
+					}
+					out.data[b*units+u] = sum
+				}
+			}
+		})
+	}, nil
+
+}
+
+// dot returns the dot product of two vectors of the same length
+func dot(a, b []float32) float32 {
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(a); i += 4 {
+		s0 += a[i] * b[i]
+		s1 += a[i+1] * b[i+1]
+		s2 += a[i+2] * b[i+2]
+		s3 += a[i+3] * b[i+3]
+	}
+	for ; i < len(a); i++ {
+		s0 += a[i] * b[i]
+	}
+	return s0 + s1 + s2 + s3
+}
+
+// binary runs an element wise operator with broadcasting
+func (ip *interpreter) binary(node *schema.Node, fused int, f func(a, b float32) float32) (kernel, error) {
+
+	a, b, out := ip.input(node, 0), ip.input(node, 1), ip.output(node, 0)
+	if b == nil {
+		return nil, fmt.Errorf("missing input")
+	}
+	act, err := activation(fused)
+	if err != nil {
+		return nil, err
+	}
+	as, err := broadcastStrides(a.shape, out.shape)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := broadcastStrides(b.shape, out.shape)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		n := out.size()
+		switch {
+		case a.size() == n && b.size() == n:
+			for i := range out.data {
+				out.data[i] = f(a.data[i], b.data[i])
+			}
+		case a.size() == n && b.size() == 1:
+			for i := range out.data {
+				out.data[i] = f(a.data[i], b.data[0])
+			}
+		default:
+			index := make([]int, len(out.shape))
+			for i := range out.data {
+				var ai, bi int
+				for d, v := range index {
+					ai += v * as[d]
+					bi += v * bs[d]
+				}
+				out.data[i] = f(a.data[ai], b.data[bi])
+				// Next index
+				for d := len(index) - 1; d >= 0; d-- {
+					if index[d]++; index[d] < out.shape[d] {
+						break
+					}
+					index[d] = 0
+				}
+			}
+		}
+		if act != nil {
+			for i, v := range out.data {
+				out.data[i] = act(v)
+			}
+		}
+	}, nil
+
+}
+
+// broadcastStrides returns the strides of the shape in the output, 0 for broadcast dimensions
+func broadcastStrides(shape, out []int) ([]int, error) {
+	if len(shape) > len(out) {
+		return nil, fmt.Errorf("can not broadcast %v to %v", shape, out)
+	}
+	strides := make([]int, len(out))
+	stride := 1
+	for d := len(out) - 1; d >= 0; d-- {
+		i := d - (len(out) - len(shape))
+		if i < 0 {
+			continue
+		}
+		switch shape[i] {
+		case out[d]:
+			strides[d] = stride
+		case 1:
+		default:
+			return nil, fmt.Errorf("can not broadcast %v to %v", shape, out)
+		}
+		stride *= shape[i]
+	}
+	return strides, nil
+}
+
+func (ip *interpreter) unary(node *schema.Node, f func(float32) float32) (kernel, error) {
+	input, out := ip.input(node, 0), ip.output(node, 0)
+	if input.size() != out.size() {
+		return nil, fmt.Errorf("input %v does not match output %v", input.shape, out.shape)
+	}
+	return func() {
+		for i, v := range input.data {
+			out.data[i] = f(v)
+		}
+	}, nil
+}
+
+// copy is for the operators that only change the shape or type
+func (ip *interpreter) copy(node *schema.Node) (kernel, error) {
+	input, out := ip.input(node, 0), ip.output(node, 0)
+	if input.size() != out.size() {
+		return nil, fmt.Errorf("input %v does not match output %v", input.shape, out.shape)
+	}
+	return func() {
+		copy(out.data, input.data)
+	}, nil
+}
+
+func (ip *interpreter) concatenation(node *schema.Node) (kernel, error) {
+
+	out := ip.output(node, 0)
+	axis := node.Options.Int(0, 0)
+	if axis < 0 {
+		axis += len(out.shape)
+	}
+	if axis < 0 || axis >= len(out.shape) {
+		return nil, fmt.Errorf("invalid axis %d", axis)
+	}
+	act, err := activation(node.Options.Byte(1, activationNone))
+	if err != nil {
+		return nil, err
+	}
+
+	outer := shapeSize(out.shape[:axis])
+	inputs := make([]*tensor, len(node.Inputs))
+	var total int
+	for i := range inputs {
+		inputs[i] = ip.input(node, i)
+		if inputs[i] == nil || len(inputs[i].shape) != len(out.shape) {
+			return nil, fmt.Errorf("input %d does not match output %v", i, out.shape)
+		}
+		total += inputs[i].size()
+	}
+	if total != out.size() {
+		return nil, fmt.Errorf("inputs have %d values, expected %d", total, out.size())
+	}
+
+	return func() {
+		pos := 0
+		for o := 0; o < outer; o++ {
+			for _, in := range inputs {
+				inner := in.size() / outer
+				copy(out.data[pos:], in.data[o*inner:(o+1)*inner])
+				pos += inner
+			}
+		}
+		if act != nil {
+			for i, v := range out.data {
+				out.data[i] = act(v)
+			}
+		}
+	}, nil
+
+}
+
+func (ip *interpreter) softmax(node *schema.Node) (kernel, error) {
+	input, out := ip.input(node, 0), ip.output(node, 0)
+	beta := float64(node.Options.Float(0, 1))
+	if input.size() != out.size() || len(input.shape) == 0 || input.shape[len(input.shape)-1] == 0 {
+		return nil, fmt.Errorf("input %v does not match output %v", input.shape, out.shape)
+	}
+	depth := input.shape[len(input.shape)-1]
+	return func() {
+		for start := 0; start+depth <= len(input.data); start += depth {
+			in, o := input.data[start:start+depth], out.data[start:start+depth]
+			max := in[0]
+			for _, v := range in {
+				if v > max {
+					max = v
+				}
+			}
+			var sum float64
+			for i, v := range in {
+				e := math.Exp(float64(v-max) * beta)
+				o[i] = float32(e)
+				sum += e
+			}
+			for i := range o {
+				o[i] = float32(float64(o[i]) / sum)
+			}
+		}
+	}, nil
+}
+
+// shape4 pads a shape to 4 dimensions
+func shape4(shape []int) ([4]int, error) {
+	var ret [4]int
+	if len(shape) > 4 {
+		return ret, fmt.Errorf("shape %v has more than 4 dimensions", shape)
+	}
+	for i := range ret {
+		ret[i] = 1
+	}
+	copy(ret[4-len(shape):], shape)
+	return ret, nil
+}
+
+func (ip *interpreter) pad(node *schema.Node) (kernel, error) {
+
+	input, out := ip.input(node, 0), ip.output(node, 0)
+	paddings, err := ip.constant(node, 1)
+	if err != nil {
+		return nil, err
+	}
+	var value float32
+	if node.Operator == schema.PadV2 && ip.input(node, 2) != nil {
+		v, err := ip.constant(node, 2)
+		if err != nil || len(v) == 0 {
+			return nil, fmt.Errorf("the pad value must be constant")
+		}
+		value = v[0]
+	}
+	rank := len(input.shape)
+	if len(paddings) != rank*2 || len(out.shape) != rank {
+		return nil, fmt.Errorf("paddings %v do not match input %v", paddings, input.shape)
+	}
+	in4, err := shape4(input.shape)
+	if err != nil {
+		return nil, err
+	}
+	out4, _ := shape4(out.shape)
+	var before [4]int
+	for d := 0; d < rank; d++ {
+		before[4-rank+d] = int(paddings[d*2])
+		if paddings[d*2] < 0 || paddings[d*2+1] < 0 || input.shape[d]+int(paddings[d*2])+int(paddings[d*2+1]) != out.shape[d] {
+			return nil, fmt.Errorf("paddings %v do not match input %v and output %v", paddings, input.shape, out.shape)
+		}
+	}
+
+	return func() {
+		for i := range out.data {
+			out.data[i] = value
+		}
+		for a := 0; a < in4[0]; a++ {
+			for b := 0; b < in4[1]; b++ {
+				for c := 0; c < in4[2]; c++ {
+					src := input.data[((a*in4[1]+b)*in4[2]+c)*in4[3]:][:in4[3]]
+					dst := (((a+before[0])*out4[1]+b+before[1])*out4[2]+c+before[2])*out4[3] + before[3]
+					copy(out.data[dst:], src)
+				}
+			}
+		}
+	}, nil
+
+}
+
+func (ip *interpreter) mean(node *schema.Node) (kernel, error) {
+
+	input, out := ip.input(node, 0), ip.output(node, 0)
+	axes, err := ip.constant(node, 1)
+	if err != nil {
+		return nil, err
+	}
+	in4, err := shape4(input.shape)
+	if err != nil {
+		return nil, err
+	}
+	// The strides of the output with the reduced dimensions removed
+	var reduced [4]bool
+	offset := 4 - len(input.shape)
+	for _, a := range axes {
+		axis := int(a)
+		if axis < 0 {
+			axis += len(input.shape)
+		}
+		if axis < 0 || axis >= len(input.shape) {
+			return nil, fmt.Errorf("invalid axis %d", axis)
+		}
+		reduced[offset+axis] = true
+	}
+	var strides [4]int
+	stride, count := 1, 1
+	for d := 3; d >= 0; d-- {
+		if reduced[d] {
+			count *= in4[d]
+			continue
+		}
+		strides[d] = stride
+		stride *= in4[d]
+	}
+	if stride != out.size() {
+		return nil, fmt.Errorf("input %v reduced on %v does not match output %v", input.shape, axes, out.shape)
+	}
+
+	return func() {
+		for i := range out.data {
+			out.data[i] = 0
+		}
+		i := 0
+		for a := 0; a < in4[0]; a++ {
+			for b := 0; b < in4[1]; b++ {
+				for c := 0; c < in4[2]; c++ {
+					for d := 0; d < in4[3]; d++ {
+						out.data[a*strides[0]+b*strides[1]+c*strides[2]+d*strides[3]] += input.data[i]
+						i++
+					}
+				}
+			}
+		}
+		for i := range out.data {
+			out.data[i] /= float32(count)
+		}
+	}, nil
+
+}
+
+func (ip *interpreter) transpose(node *schema.Node) (kernel, error) {
+
+	input, out := ip.input(node, 0), ip.output(node, 0)
+	perm, err := ip.constant(node, 1)
+	if err != nil {
+		return nil, err
+	}
+	rank := len(input.shape)
+	if len(perm) != rank || len(out.shape) != rank || rank > 4 {
+		return nil, fmt.Errorf("permutation %v does not match input %v", perm, input.shape)
+	}
+	// The input stride of each output dimension
+	inStrides := make([]int, rank)
+	stride := 1
+	for d := rank - 1; d >= 0; d-- {
+		inStrides[d] = stride
+		stride *= input.shape[d]
+	}
+	var strides [4]int
+	for d := 0; d < rank; d++ {
+		p := int(perm[d])
+		if p < 0 || p >= rank || out.shape[d] != input.shape[p] {
+			return nil, fmt.Errorf("permutation %v does not match input %v and output %v", perm, input.shape, out.shape)
+		}
+		strides[4-rank+d] = inStrides[p]
+	}
+	out4, _ := shape4(out.shape)
+
+	return func() {
+		i := 0
+		for a := 0; a < out4[0]; a++ {
+			for b := 0; b < out4[1]; b++ {
+				for c := 0; c < out4[2]; c++ {
+					for d := 0; d < out4[3]; d++ {
+						out.data[i] = input.data[a*strides[0]+b*strides[1]+c*strides[2]+d*strides[3]]
+						i++
+					}
+				}
+			}
+		}
+	}, nil
+
+}
+
+func (ip *interpreter) resize(node *schema.Node, bilinear bool, alignCorners bool, halfPixel bool) (kernel, error) {
+
+	input, out := ip.input(node, 0), ip.output(node, 0)
+	for _, t := range []*tensor{input, out} {
+		if err := checkRank(t, 4); err != nil {
+			return nil, err
+		}
+	}
+	batches, h, w, c := input.shape[0], input.shape[1], input.shape[2], input.shape[3]
+	oh, ow := out.shape[1], out.shape[2]
+	if out.shape[3] != c || out.shape[0] != batches || h == 0 || w == 0 {
+		return nil, fmt.Errorf("input %v does not match output %v", input.shape, out.shape)
+	}
+	scale := func(in, out int) float64 {
+		if alignCorners && out > 1 {
+			return float64(in-1) / float64(out-1)
+		}
+		return float64(in) / float64(out)
+	}
+	scaleY, scaleX := scale(h, oh), scale(w, ow)
+
+	// The source position of an output position like TFLite
+	position := func(v int, scale float64) float64 {
+		if halfPixel {
+			return (float64(v)+0.5)*scale - 0.5
+		}
+		return float64(v) * scale
+	}
+	nearest := func(v int, scale float64, size int) int {
+		p := float64(v) * scale
+		if halfPixel {
+			p = (float64(v) + 0.5) * scale
+		}
+		var i int
+		if alignCorners {
+			i = int(math.Round(p))
+		} else {
+			i = int(math.Floor(p))
+		}
+		if i >= size {
+			i = size - 1
+		}
+		if i < 0 {
+			i = 0
+		}
+		return i
+	}
+	pixel := func(b, y, x int) []float32 {
+		return input.data[((b*h+y)*w+x)*c:][:c]
+	}
+
+	return func() {
+		for b := 0; b < batches; b++ {
+			for oy := 0; oy < oh; oy++ {
+				for ox := 0; ox < ow; ox++ {
+					o := out.data[((b*oh+oy)*ow+ox)*c:][:c]
+					if !bilinear {
+						copy(o, pixel(b, nearest(oy, scaleY, h), nearest(ox, scaleX, w)))
+						continue
+					}
+					iy, ix := position(oy, scaleY), position(ox, scaleX)
+					y0, x0 := int(math.Max(math.Floor(iy), 0)), int(math.Max(math.Floor(ix), 0))
+					y1, x1 := int(math.Min(math.Ceil(iy), float64(h-1))), int(math.Min(math.Ceil(ix), float64(w-1)))
+					dy, dx := float32(iy-float64(y0)), float32(ix-float64(x0))
+					p00, p01, p10, p11 := pixel(b, y0, x0), pixel(b, y0, x1), pixel(b, y1, x0), pixel(b, y1, x1)
+					for ch := range o {
+						o[ch] = p00[ch]*(1-dy)*(1-dx) + p10[ch]*dy*(1-dx) + p01[ch]*(1-dy)*dx + p11[ch]*dy*dx
+					}
+				}
+			}
+		}
+	}, nil
+
+}
@@ -0,0 +1,178 @@
+package gotflite
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/snowzach/doods/detector/tflite/schema"
+)
+
+// runModel loads a model file and runs it with the input, it returns the first output
+func runModel(t *testing.T, buf []byte, input []float32) []float32 {
+	t.Helper()
+
+	m, err := schema.ParseModel(buf)
+	if err != nil {
+		t.Fatalf("could not parse model: %v", err)
+	}
+	lm, err := loadModel(m)
+	if err != nil {
+		t.Fatalf("could not load model: %v", err)
+	}
+	ip, err := newInterpreter(lm, 2)
+	if err != nil {
+		t.Fatalf("could not create interpreter: %v", err)
+	}
+	if len(input) != len(ip.inputTensor(0).data) {
+		t.Fatalf("input has %d values, expected %d", len(input), len(ip.inputTensor(0).data))
+	}
+	copy(ip.inputTensor(0).data, input)
+	if err := ip.invoke(context.Background()); err != nil {
+		t.Fatalf("could not invoke: %v", err)
+	}
+	return ip.outputTensor(0).data
+
+}
+
+// singleOp returns a model with one operator, tensor 0 is the input and tensor 1 the output
+func singleOp(op schema.Operator, in []int32, out []int32, options fbTable, constants ...testTensor) []byte {
+	tensors := append([]testTensor{{name: "input", shape: in}, {name: "output", shape: out}}, constants...)
+	inputs := []int32{0}
+	for i := range constants {
+		inputs = append(inputs, int32(2+i))
+	}
+	return testModel(tensors, []testNode{{op: int32(op), inputs: inputs, outputs: []int32{1}, options: options}}, []int32{0}, []int32{1})
+}
+
+func checkValues(t *testing.T, got []float32, expected []float32) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("got %d values %v, expected %v", len(got), got, expected)
+	}
+	for i := range got {
+		if math.Abs(float64(got[i]-expected[i])) > 1e-5 {
+			t.Fatalf("got %v, expected %v", got, expected)
+		}
+	}
+}
+
+func count(n int) []float32 {
+	ret := make([]float32, n)
+	for i := range ret {
+		ret[i] = float32(i + 1)
+	}
+	return ret
+}
+
+func TestConv2D(t *testing.T) {
+
+	// The filter adds the top left and bottom right values
+	filter := floatTensor("filter", []int32{1, 2, 2, 1}, 1, 0, 0, 1)
+	bias := floatTensor("bias", []int32{1}, 1)
+
+	t.Run("valid", func(t *testing.T) {
+		model := singleOp(schema.Conv2D, []int32{1, 3, 3, 1}, []int32{1, 2, 2, 1}, fbTable{int8(paddingValid), int32(1), int32(1)}, filter, bias)
+		checkValues(t, runModel(t, model, count(9)), []float32{7, 9, 13, 15})
+	})
+
+	t.Run("same stride 2", func(t *testing.T) {
+		model := singleOp(schema.Conv2D, []int32{1, 3, 3, 1}, []int32{1, 2, 2, 1}, fbTable{int8(paddingSame), int32(2), int32(2)}, filter, bias)
+		checkValues(t, runModel(t, model, count(9)), []float32{7, 4, 8, 10})
+	})
+
+	t.Run("channels and relu", func(t *testing.T) {
+		weights := floatTensor("filter", []int32{2, 1, 1, 2}, 1, 1, 1, -1)
+		model := singleOp(schema.Conv2D, []int32{1, 1, 2, 2}, []int32{1, 1, 2, 2}, fbTable{int8(paddingValid), int32(1), int32(1), int8(activationRelu)}, weights)
+		checkValues(t, runModel(t, model, count(4)), []float32{3, 0, 7, 0})
+	})
+
+	t.Run("mismatched filter", func(t *testing.T) {
+		model := singleOp(schema.Conv2D, []int32{1, 3, 3, 2}, []int32{1, 2, 2, 1}, nil, filter, bias)
+		m, err := schema.ParseModel(model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lm, err := loadModel(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newInterpreter(lm, 1); err == nil {
+			t.Fatal("expected an error for a filter with the wrong depth")
+		}
+	})
+
+}
+
+func TestDepthwiseConv2D(t *testing.T) {
+
+	t.Run("valid", func(t *testing.T) {
+		filter := floatTensor("filter", []int32{1, 2, 2, 2}, 1, 0.5, 1, 0.5, 1, 0.5, 1, 0.5)
+		bias := floatTensor("bias", []int32{2}, 1, -1)
+		model := singleOp(schema.DepthwiseConv2D, []int32{1, 2, 2, 2}, []int32{1, 1, 1, 2}, fbTable{int8(paddingValid), int32(1), int32(1), int32(1)}, filter, bias)
+		checkValues(t, runModel(t, model, []float32{1, 10, 2, 20, 3, 30, 4, 40}), []float32{11, 49})
+	})
+
+	t.Run("same", func(t *testing.T) {
+		filter := floatTensor("filter", []int32{1, 3, 3, 1}, 1, 1, 1, 1, 1, 1, 1, 1, 1)
+		model := singleOp(schema.DepthwiseConv2D, []int32{1, 3, 3, 1}, []int32{1, 3, 3, 1}, fbTable{int8(paddingSame), int32(1), int32(1), int32(1)}, filter)
+		checkValues(t, runModel(t, model, count(9)), []float32{12, 21, 16, 27, 45, 33, 24, 39, 28})
+	})
+
+	t.Run("multiplier", func(t *testing.T) {
+		filter := floatTensor("filter", []int32{1, 1, 1, 2}, 1, -1)
+		model := singleOp(schema.DepthwiseConv2D, []int32{1, 2, 2, 1}, []int32{1, 2, 2, 2}, fbTable{int8(paddingSame), int32(1), int32(1), int32(2)}, filter)
+		checkValues(t, runModel(t, model, count(4)), []float32{1, -1, 2, -2, 3, -3, 4, -4})
+	})
+
+}
+
+func TestPad(t *testing.T) {
+
+	t.Run("pad", func(t *testing.T) {
+		model := singleOp(schema.Pad, []int32{1, 2, 2, 1}, []int32{1, 3, 3, 1}, nil, intTensor("paddings", []int32{4, 2}, 0, 0, 1, 0, 0, 1, 0, 0))
+		checkValues(t, runModel(t, model, count(4)), []float32{0, 0, 0, 1, 2, 0, 3, 4, 0})
+	})
+
+	t.Run("padv2", func(t *testing.T) {
+		value := floatTensor("value", []int32{1}, 9)
+		model := singleOp(schema.PadV2, []int32{1, 2, 2, 1}, []int32{1, 3, 3, 1}, nil, intTensor("paddings", []int32{4, 2}, 0, 0, 1, 0, 0, 1, 0, 0), value)
+		checkValues(t, runModel(t, model, count(4)), []float32{9, 9, 9, 1, 2, 9, 3, 4, 9})
+	})
+
+}
+
+func TestResize(t *testing.T) {
+
+	// The input is 2y+x so bilinear outputs are 2y+x at the source positions clamped to the input
+	input := []float32{0, 1, 2, 3}
+	linear := func(positions ...float32) []float32 {
+		var ret []float32
+		for _, y := range positions {
+			for _, x := range positions {
+				ret = append(ret, 2*y+x)
+			}
+		}
+		return ret
+	}
+
+	for _, test := range []struct {
+		name     string
+		op       schema.Operator
+		options  fbTable
+		expected []float32
+	}{
+		{"bilinear", schema.ResizeBilinear, nil, linear(0, 0.5, 1, 1)},
+		{"bilinear align corners", schema.ResizeBilinear, fbTable{nil, nil, true}, linear(0, 1.0/3, 2.0/3, 1)},
+		{"bilinear half pixel", schema.ResizeBilinear, fbTable{nil, nil, false, true}, linear(0, 0.25, 0.75, 1)},
+		{"nearest", schema.ResizeNearestNeighbor, nil, linear(0, 0, 1, 1)},
+		{"nearest align corners", schema.ResizeNearestNeighbor, fbTable{true}, linear(0, 0, 1, 1)},
+		{"nearest half pixel", schema.ResizeNearestNeighbor, fbTable{false, true}, linear(0, 0, 1, 1)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			model := singleOp(test.op, []int32{1, 2, 2, 1}, []int32{1, 4, 4, 1}, test.options, intTensor("size", []int32{2}, 4, 4))
+			checkValues(t, runModel(t, model, input), test.expected)
+		})
+	}
+
+}
@@ -0,0 +1,186 @@
+package gotflite
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/snowzach/doods/detector/tflite/schema"
+)
+
+// box is ymin, xmin, ymax, xmax
+type box [4]float32
+
+func (b box) iou(o box) float32 {
+	area := (b[2] - b[0]) * (b[3] - b[1])
+	oarea := (o[2] - o[0]) * (o[3] - o[1])
+	if area <= 0 || oarea <= 0 {
+		return 0
+	}
+	h := float32(math.Min(float64(b[2]), float64(o[2])) - math.Max(float64(b[0]), float64(o[0])))
+	w := float32(math.Min(float64(b[3]), float64(o[3])) - math.Max(float64(b[1]), float64(o[1])))
+	if h <= 0 || w <= 0 {
+		return 0
+	}
+	intersection := h * w
+	return intersection / (area + oarea - intersection)
+}
+
+// detectionPostProcess decodes the SSD boxes and runs non max suppression like the TFLite_Detection_PostProcess operator
+func (ip *interpreter) detectionPostProcess(node *schema.Node) (kernel, error) {
+
+	encodings, predictions, out := ip.input(node, 0), ip.input(node, 1), []*tensor{ip.output(node, 0), ip.output(node, 1), ip.output(node, 2), ip.output(node, 3)}
+	anchors, err := ip.constant(node, 2)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range out {
+		if o == nil {
+			return nil, fmt.Errorf("missing output")
+		}
+	}
+
+	options, err := schema.CustomOptions(node.CustomOptions)
+	if err != nil {
+		return nil, err
+	}
+	option := func(name string, def float64) float64 {
+		if v, ok := options[name]; ok {
+			return v
+		}
+		return def
+	}
+	maxDetections := int(option("max_detections", 10))
+	maxClasses := int(option("max_classes_per_detection", 1))
+	perClass := int(option("detections_per_class", 100))
+	regular := option("use_regular_nms", 0) != 0
+	scoreThreshold := float32(option("nms_score_threshold", 0))
+	iouThreshold := float32(option("nms_iou_threshold", 0.5))
+	numClasses := int(option("num_classes", 0))
+	scales := [4]float32{float32(option("y_scale", 10)), float32(option("x_scale", 10)), float32(option("h_scale", 5)), float32(option("w_scale", 5))}
+
+	if numClasses <= 0 || maxClasses <= 0 || maxDetections <= 0 || scales[0] == 0 || scales[1] == 0 || scales[2] == 0 || scales[3] == 0 {
+		return nil, fmt.Errorf("invalid options %v", options)
+	}
+	numBoxes := len(anchors) / 4
+	if encodings.size() < numBoxes*4 || numBoxes == 0 || predictions.size()%numBoxes != 0 {
+		return nil, fmt.Errorf("inputs %v and %v do not match %d anchors", encodings.shape, predictions.shape, numBoxes)
+	}
+	// The first class is the background if there's an extra one
+	columns := predictions.size() / numBoxes
+	offset := columns - numClasses
+	if offset < 0 {
+		return nil, fmt.Errorf("predictions %v have less than %d classes", predictions.shape, numClasses)
+	}
+	if maxClasses > numClasses {
+		maxClasses = numClasses
+	}
+	maxOutputs := maxDetections * maxClasses
+	if regular {
+		maxOutputs = maxDetections
+	}
+	if out[0].size() < maxOutputs*4 || out[1].size() < maxOutputs || out[2].size() < maxOutputs || out[3].size() < 1 {
+		return nil, fmt.Errorf("outputs are too small for %d detections", maxOutputs)
+	}
+
+	boxes := make([]box, numBoxes)
+	type detection struct {
+		box   int
+		class int
+		score float32
+	}
+
+	return func() {
+
+		// Decode the boxes from the anchors (y center, x center, height, width)
+		for i := range boxes {
+			e, a := encodings.data[i*4:][:4], anchors[i*4:][:4]
+			yc := e[0]/scales[0]*a[2] + a[0]
+			xc := e[1]/scales[1]*a[3] + a[1]
+			h := float32(math.Exp(float64(e[2]/scales[2]))) * a[2]
+			w := float32(math.Exp(float64(e[3]/scales[3]))) * a[3]
+			boxes[i] = box{yc - h/2, xc - w/2, yc + h/2, xc + w/2}
+		}
+		scores := func(i int) []float32 {
+			return predictions.data[i*columns+offset:][:numClasses]
+		}
+
+		// nms returns the candidates with the highest scores that don't overlap
+		nms := func(candidates []detection, max int) []detection {
+			sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+			selected := make([]detection, 0, max)
+			for _, c := range candidates {
+				if len(selected) >= max {
+					break
+				}
+				keep := true
+				for _, s := range selected {
+					if boxes[c.box].iou(boxes[s.box]) > iouThreshold {
+						keep = false
+						break
+					}
+				}
+				if keep {
+					selected = append(selected, c)
+				}
+			}
+			return selected
+		}
+
+		var results []detection
+		if regular {
+			for class := 0; class < numClasses; class++ {
+				var candidates []detection
+				for i := range boxes {
+					if s := scores(i)[class]; s >= scoreThreshold {
+						candidates = append(candidates, detection{box: i, class: class, score: s})
+					}
+				}
+				results = append(results, nms(candidates, perClass)...)
+			}
+			sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+			if len(results) > maxDetections {
+				results = results[:maxDetections]
+			}
+		} else {
+			// Suppress on the best class of each box and then output its top classes
+			var candidates []detection
+			for i := range boxes {
+				best := 0
+				for class, s := range scores(i) {
+					if s > scores(i)[best] {
+						best = class
+					}
+				}
+				if s := scores(i)[best]; s >= scoreThreshold {
+					candidates = append(candidates, detection{box: i, class: best, score: s})
+				}
+			}
+			for _, d := range nms(candidates, maxDetections) {
+				classes := make([]int, numClasses)
+				for class := range classes {
+					classes[class] = class
+				}
+				s := scores(d.box)
+				sort.SliceStable(classes, func(i, j int) bool { return s[classes[i]] > s[classes[j]] })
+				for _, class := range classes[:maxClasses] {
+					results = append(results, detection{box: d.box, class: class, score: s[class]})
+				}
+			}
+		}
+
+		for _, o := range out {
+			for i := range o.data {
+				o.data[i] = 0
+			}
+		}
+		for i, r := range results {
+			copy(out[0].data[i*4:], boxes[r.box][:])
+			out[1].data[i] = float32(r.class)
+			out[2].data[i] = r.score
+		}
+		out[3].data[0] = float32(len(results))
+
+	}, nil
+
+}
@@ -0,0 +1,28 @@
+package detector
+
+// The health of a detector
+const (
+	HealthOnline = "online"
+	// The detector hung and is being recreated
+	HealthRestarting = "restarting"
+	// The detector is failing and requests fail fast (see breaker)
+	HealthUnavailable = "unavailable"
+	// The detector could not be recreated
+	HealthOffline = "offline"
+)
+
+// Health returns the health of each detector by name
+func (m *Mux) Health() map[string]string {
+	health := make(map[string]string, len(m.detectors))
+	for name, md := range m.detectors {
+		h := HealthOnline
+		if r, ok := md.Detector.(*restarter); ok {
+			h = r.health()
+		}
+		if h == HealthOnline && md.breaker != nil && md.breaker.isOpen() {
+			h = HealthUnavailable
+		}
+		health[name] = h
+	}
+	return health
+}
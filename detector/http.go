@@ -0,0 +1,182 @@
+package detector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/detector/annotate"
+	"github.com/snowzach/doods/server"
+)
+
+// RegisterHTTP registers the HTTP only endpoints (images) on the router
+func (m *Mux) RegisterHTTP(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(m.keys))
+		r.Get("/detectors/{name}/last", m.handleLastResponse)
+		r.Get("/detectors/{name}/shadow", m.handleShadow)
+		r.Get("/detectors/{name}/retries", m.handleRetries)
+		r.Get("/state", m.handleState)
+		r.Get("/state/{source}", m.handleSourceState)
+		r.Get("/stats", m.handleStats)
+		r.Get("/stats/{source}", m.handleSourceStats)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(server.ImageAuth(m.keys))
+		r.Get("/detectors/{name}/last.jpg", m.handleLastImage)
+		r.Get("/detectors/{name}/density.jpg", m.handleDensityImage)
+	})
+	// Enroll pets with the detectors, the store has the rest of the endpoints
+	if m.pets != nil {
+		r.Group(func(r chi.Router) {
+			r.Use(server.Auth(m.keys))
+			r.Post("/pets/{name}", m.handleEnrollPet)
+		})
+		m.pets.RegisterHTTP(r)
+	}
+}
+
+// lastEvent returns the last event for the detector in the url
+func (m *Mux) lastEvent(w http.ResponseWriter, r *http.Request) *event {
+	detector, ok := m.detectors[chi.URLParam(r, "name")]
+	if !ok {
+		render.Render(w, r, server.ErrNotFound)
+		return nil
+	}
+	e := detector.lastEvent()
+	if e == nil {
+		render.Render(w, r, server.ErrNotFound)
+		return nil
+	}
+	return e
+}
+
+// handleLastResponse returns the response of the last detection with results
+func (m *Mux) handleLastResponse(w http.ResponseWriter, r *http.Request) {
+	e := m.lastEvent(w, r)
+	if e == nil {
+		return
+	}
+	data, err := json.Marshal(e.response)
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	server.ServeCached(w, r, "application/json", e.time, e.etag(), data)
+}
+
+// handleLastImage returns the image of the last detection with results with the detections drawn.
+// The width query parameter will return a thumbnail.
+func (m *Mux) handleLastImage(w http.ResponseWriter, r *http.Request) {
+	e := m.lastEvent(w, r)
+	if e == nil {
+		return
+	}
+
+	var width int
+	if ws := r.URL.Query().Get("width"); ws != "" {
+		var err error
+		if width, err = strconv.Atoi(ws); err != nil || width < 0 {
+			render.Render(w, r, server.ErrInvalidRequest(fmt.Errorf("invalid width: %s", ws)))
+			return
+		}
+	}
+
+	// The image only changes with the event and width
+	etag := e.etag() + "-" + strconv.Itoa(width)
+	if match := r.Header.Get("If-None-Match"); match != "" && match == `"`+etag+`"` {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	data, err := annotate.Image(e.data, e.response.Detections, width)
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	server.ServeCached(w, r, "image/jpeg", e.time, etag, data)
+}
+
+// handleDensityImage returns the image of the last detection of a crowd counting model with the density map drawn
+// over it. The width query parameter will return a thumbnail.
+func (m *Mux) handleDensityImage(w http.ResponseWriter, r *http.Request) {
+	e := m.lastEvent(w, r)
+	if e == nil {
+		return
+	}
+	if e.density == nil {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+
+	var width int
+	if ws := r.URL.Query().Get("width"); ws != "" {
+		var err error
+		if width, err = strconv.Atoi(ws); err != nil || width < 0 {
+			render.Render(w, r, server.ErrInvalidRequest(fmt.Errorf("invalid width: %s", ws)))
+			return
+		}
+	}
+
+	etag := e.etag() + "-density-" + strconv.Itoa(width)
+	if match := r.Header.Get("If-None-Match"); match != "" && match == `"`+etag+`"` {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	data, err := annotate.Density(e.data, e.density, width)
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	server.ServeCached(w, r, "image/jpeg", e.time, etag, data)
+}
+
+// handleState returns the current object counts for every source
+func (m *Mux) handleState(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]interface{}{"sources": m.state.All()})
+}
+
+// handleSourceState returns the current object counts for a source
+func (m *Mux) handleSourceState(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]interface{}{"objects": m.state.Get(chi.URLParam(r, "source"))})
+}
+
+// handleShadow returns the agreement stats of the shadow detector
+func (m *Mux) handleShadow(w http.ResponseWriter, r *http.Request) {
+	detector, ok := m.detectors[chi.URLParam(r, "name")]
+	if !ok || detector.shadow == nil {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	render.JSON(w, r, detector.shadow.getStats())
+}
+
+// handleRetries returns the retry stats of the detector
+func (m *Mux) handleRetries(w http.ResponseWriter, r *http.Request) {
+	detector, ok := m.detectors[chi.URLParam(r, "name")]
+	if !ok || detector.retry == nil {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	render.JSON(w, r, detector.retry.getStats())
+}
+
+// handleStats returns the rolling statistics for every source
+func (m *Mux) handleStats(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]interface{}{"sources": m.stats.All()})
+}
+
+// handleSourceStats returns the rolling statistics for a source
+func (m *Mux) handleSourceStats(w http.ResponseWriter, r *http.Request) {
+	st := m.stats.Get(chi.URLParam(r, "source"))
+	if st == nil {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	render.JSON(w, r, st)
+}
@@ -0,0 +1,76 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"image"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/detector/diff"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// DiffImages returns the regions that changed between two images so clients can skip detection when nothing changed
+func (m *Mux) DiffImages(ctx context.Context, request *odrpc.DiffRequest) (*odrpc.DiffResponse, error) {
+
+	before, err := m.diffImage(ctx, request.Before, request.BeforeUrl, "before")
+	if err != nil {
+		return nil, err
+	}
+	after, err := m.diffImage(ctx, request.After, request.AfterUrl, "after")
+	if err != nil {
+		return nil, err
+	}
+
+	regions, score := diff.Compare(before, after, diff.Options{
+		Threshold: int(request.Threshold),
+		MinArea:   float64(request.MinArea),
+		Width:     int(request.Width),
+	})
+
+	response := &odrpc.DiffResponse{
+		Id:      request.Id,
+		Regions: make([]*odrpc.ChangedRegion, 0, len(regions)),
+		Score:   score,
+	}
+	for _, r := range regions {
+		response.Regions = append(response.Regions, &odrpc.ChangedRegion{
+			Box:   &odrpc.Box{Top: r.Top, Left: r.Left, Bottom: r.Bottom, Right: r.Right},
+			Score: r.Score,
+		})
+	}
+
+	m.logger.Debugw("Compared images", "id", request.Id, "regions", len(response.Regions), "score", score)
+
+	return response, nil
+
+}
+
+// diffImage fetches and decodes an image to compare like a detect request
+func (m *Mux) diffImage(ctx context.Context, data []byte, url string, name string) (image.Image, error) {
+
+	var err error
+	if url != "" {
+		data, err = m.fetcher.fetch(ctx, url)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %v", name, err)
+		}
+	}
+	if len(data) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "missing %s image", name)
+	}
+	if err = m.limits.check(data); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: %v", name, err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not decode %s image: %v", name, err)
+	}
+	if img.Bounds().Empty() {
+		return nil, status.Errorf(codes.InvalidArgument, "empty %s image", name)
+	}
+	return img, nil
+
+}
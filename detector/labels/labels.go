@@ -0,0 +1,240 @@
+package labels
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The supported label file formats
+const (
+	// FormatAuto will attempt to determine the format from the label file
+	FormatAuto = "auto"
+	// FormatIndexed is one label per line in the form "<index> <name>", lines without an index use the 1 based line number
+	FormatIndexed = "indexed"
+	// FormatList is one label per line with implicit 0 based indexing
+	FormatList = "list"
+	// FormatTFLite reads the labels embedded in the TFLite model metadata
+	FormatTFLite = "tflite"
+	// FormatCOCO is a COCO style JSON list of categories
+	FormatCOCO = "coco"
+)
+
+var indexedLine = regexp.MustCompile(`^\s*\d+\s+\S`)
+
+// Labels maps the class index to the label name
+type Labels map[int]string
+
+// Names returns the label names ordered by index
+func (l Labels) Names() []string {
+	indexes := make([]int, 0, len(l))
+	for i := range l {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	names := make([]string, 0, len(l))
+	for _, i := range indexes {
+		names = append(names, l[i])
+	}
+	return names
+}
+
+// Load reads labels using format. If format is blank or auto, the format will be detected.
+// The model is only read when the labels are embedded in the model.
+func Load(format string, labelFile string, model func() ([]byte, error)) (Labels, error) {
+
+	if format == "" || format == FormatAuto {
+		if labelFile == "" {
+			format = FormatTFLite
+		} else {
+			data, err := ioutil.ReadFile(labelFile)
+			if err != nil {
+				return nil, fmt.Errorf("could not read label file %s: %v", labelFile, err)
+			}
+			return parse(detectFormat(labelFile, data), data)
+		}
+	}
+
+	switch format {
+	case FormatTFLite:
+		data, err := model()
+		if err != nil {
+			return nil, err
+		}
+		return LoadEmbedded(data, "")
+	case FormatIndexed, FormatList, FormatCOCO:
+		data, err := ioutil.ReadFile(labelFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read label file %s: %v", labelFile, err)
+		}
+		return parse(format, data)
+	}
+
+	return nil, fmt.Errorf("unknown label format: %s", format)
+
+}
+
+// LoadEmbedded reads a label list from the files packed into a TFLite model with metadata.
+// If name is blank, it will use the first text file that looks like a label file.
+func LoadEmbedded(model []byte, name string) (Labels, error) {
+
+	z, err := zip.NewReader(bytes.NewReader(model), int64(len(model)))
+	if err != nil {
+		return nil, fmt.Errorf("model does not contain embedded labels: %v", err)
+	}
+
+	var labelFile *zip.File
+	for _, f := range z.File {
+		if name != "" {
+			if f.Name == name {
+				labelFile = f
+				break
+			}
+			continue
+		}
+		if strings.HasSuffix(strings.ToLower(f.Name), ".txt") {
+			// Prefer anything named like a label file
+			if labelFile == nil || strings.Contains(strings.ToLower(f.Name), "label") {
+				labelFile = f
+			}
+		}
+	}
+	if labelFile == nil {
+		return nil, fmt.Errorf("model does not contain embedded labels")
+	}
+
+	r, err := labelFile.Open()
+	if err != nil {
+		return nil, fmt.Errorf("could not open embedded labels %s: %v", labelFile.Name, err)
+	}
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read embedded labels %s: %v", labelFile.Name, err)
+	}
+
+	return parse(FormatList, data)
+
+}
+
+// detectFormat guesses the label format from the filename and data. Plain label files are read as indexed so lines
+// without an index keep the 1 based numbering of the original label files, 0 based lists have to be set with list.
+func detectFormat(filename string, data []byte) string {
+
+	trimmed := bytes.TrimSpace(data)
+	if strings.ToLower(filepath.Ext(filename)) == ".json" || bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("[")) {
+		return FormatCOCO
+	}
+	return FormatIndexed
+
+}
+
+func parse(format string, data []byte) (Labels, error) {
+	switch format {
+	case FormatIndexed:
+		return parseIndexed(bytes.NewReader(data))
+	case FormatList:
+		return parseList(bytes.NewReader(data))
+	case FormatCOCO:
+		return parseCOCO(data)
+	}
+	return nil, fmt.Errorf("unknown label format: %s", format)
+}
+
+// parseIndexed parses the "<index> <name>" format. Lines without an index use the 1 based line number.
+func parseIndexed(r io.Reader) (Labels, error) {
+	labels := make(Labels)
+	scanner := bufio.NewScanner(r)
+	for x := 1; scanner.Scan(); x++ {
+		line := scanner.Text()
+		if !indexedLine.MatchString(line) {
+			// Names can have spaces, like traffic light
+			labels[x] = strings.TrimSpace(line)
+			continue
+		}
+		fields := strings.SplitAfterN(strings.TrimSpace(line), " ", 2)
+		if y, err := strconv.Atoi(strings.TrimSpace(fields[0])); err == nil {
+			labels[y] = strings.TrimSpace(fields[1])
+		}
+	}
+	return labels, scanner.Err()
+}
+
+// parseList parses one label per line, the index is the 0 based line number
+func parseList(r io.Reader) (Labels, error) {
+	labels := make(Labels)
+	scanner := bufio.NewScanner(r)
+	for x := 0; scanner.Scan(); x++ {
+		labels[x] = strings.TrimSpace(scanner.Text())
+	}
+	// Drop a trailing blank line
+	if len(labels) > 0 && labels[len(labels)-1] == "" {
+		delete(labels, len(labels)-1)
+	}
+	return labels, scanner.Err()
+}
+
+type cocoCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// parseCOCO parses COCO categories. It will accept a full COCO annotation file with a categories key,
+// a bare list of categories or an object mapping the id to the name.
+func parseCOCO(data []byte) (Labels, error) {
+
+	var categories []cocoCategory
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &categories); err != nil {
+			return nil, fmt.Errorf("could not parse coco categories: %v", err)
+		}
+	} else {
+		var doc struct {
+			Categories []cocoCategory `json:"categories"`
+		}
+		if err := json.Unmarshal(data, &doc); err == nil && len(doc.Categories) > 0 {
+			categories = doc.Categories
+		} else {
+			// Try a map of id to name
+			var m map[string]string
+			if err := json.Unmarshal(data, &m); err != nil {
+				return nil, fmt.Errorf("could not parse coco categories: %v", err)
+			}
+			for id, name := range m {
+				x, err := strconv.Atoi(id)
+				if err != nil {
+					return nil, fmt.Errorf("invalid category id %s: %v", id, err)
+				}
+				categories = append(categories, cocoCategory{ID: x, Name: name})
+			}
+		}
+	}
+
+	labels := make(Labels)
+	for _, c := range categories {
+		labels[c.ID] = c.Name
+	}
+	return labels, nil
+
+}
+
+// Translate maps the label names in base to the label with the same index in translated
+func Translate(base Labels, translated Labels) map[string]string {
+	ret := make(map[string]string, len(base))
+	for i, name := range base {
+		if t, ok := translated[i]; ok && t != "" {
+			ret[name] = t
+		}
+	}
+	return ret
+}
@@ -0,0 +1,127 @@
+package labels
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func noModel() ([]byte, error) {
+	return nil, errors.New("no model")
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "labels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func writeLabels(t *testing.T, dir string, name string, data string) string {
+	t.Helper()
+	filename := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoad(t *testing.T) {
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		format   string
+		file     string
+		data     string
+		expected Labels
+	}{
+		{"auto plain is 1 based", "", "labels.txt", "person\nbicycle\ntraffic light\n", Labels{1: "person", 2: "bicycle", 3: "traffic light"}},
+		{"auto indexed", "auto", "labels.txt", "0 person\n1 bicycle\n9 traffic light\n", Labels{0: "person", 1: "bicycle", 9: "traffic light"}},
+		{"auto mixed", "", "labels.txt", "person\n5 car\n", Labels{1: "person", 5: "car"}},
+		{"list is 0 based", FormatList, "labels.txt", "person\nbicycle\n", Labels{0: "person", 1: "bicycle"}},
+		{"coco", "", "labels.json", `{"categories":[{"id":1,"name":"person"},{"id":3,"name":"car"}]}`, Labels{1: "person", 3: "car"}},
+		{"coco list", "", "labels", `[{"id":2,"name":"bicycle"}]`, Labels{2: "bicycle"}},
+		{"coco map", FormatCOCO, "labels.json", `{"1":"person"}`, Labels{1: "person"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			labels, err := Load(test.format, writeLabels(t, dir, test.file, test.data), noModel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(labels) != len(test.expected) {
+				t.Fatalf("got %v, expected %v", labels, test.expected)
+			}
+			for i, name := range test.expected {
+				if labels[i] != name {
+					t.Errorf("label %d: got %q, expected %q", i, labels[i], name)
+				}
+			}
+		})
+	}
+
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	if _, err := Load("bogus", writeLabels(t, dir, "labels.txt", "person\n"), noModel); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if _, err := Load("", "/does/not/exist.txt", noModel); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	if _, err := Load(FormatCOCO, writeLabels(t, dir, "labels.json", `{"x":"person"}`), noModel); err == nil {
+		t.Error("expected an error for a bad coco id")
+	}
+}
+
+func TestLoadEmbedded(t *testing.T) {
+
+	// TFLite models with metadata have the label files zipped onto the end
+	var buf bytes.Buffer
+	buf.WriteString("TFL3 model")
+	z := zip.NewWriter(&buf)
+	for name, data := range map[string]string{"vocab.txt": "a\nb\n", "labelmap.txt": "person\nbicycle\n"} {
+		w, err := z.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(data))
+	}
+	z.Close()
+	model := buf.Bytes()
+
+	labels, err := Load(FormatTFLite, "", func() ([]byte, error) { return model, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(labels) != 2 || labels[0] != "person" || labels[1] != "bicycle" {
+		t.Errorf("unexpected labels %v", labels)
+	}
+
+	if labels, err = LoadEmbedded(model, "vocab.txt"); err != nil || labels[1] != "b" {
+		t.Errorf("unexpected named labels %v: %v", labels, err)
+	}
+	if _, err := LoadEmbedded([]byte("TFL3 model"), ""); err == nil {
+		t.Error("expected an error for a model without labels")
+	}
+
+}
+
+func TestTranslate(t *testing.T) {
+	ret := Translate(Labels{1: "person", 2: "car"}, Labels{1: "persona", 2: ""})
+	if len(ret) != 1 || ret["person"] != "persona" {
+		t.Errorf("unexpected translations %v", ret)
+	}
+}
@@ -0,0 +1,98 @@
+package detector
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// requestLanguage returns the requested label language from the request or the Accept-Language header
+func requestLanguage(ctx context.Context, request *odrpc.DetectRequest) string {
+	if request.Language != "" {
+		return request.Language
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		return mdfirst(md, odrpc.AcceptLanguageHeader)
+	}
+	return ""
+}
+
+// matchLanguage picks the best available language for the requested languages. The request
+// can be a single language or an Accept-Language header value. It returns blank if there is no match.
+func matchLanguage(request string, available map[string]map[string]string) string {
+
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	// Parse the languages and weights
+	var tags []weighted
+	for _, part := range strings.Split(request, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	for _, t := range tags {
+		// Exact match
+		for lang := range available {
+			if strings.ToLower(lang) == t.tag {
+				return lang
+			}
+		}
+		// Match the primary language (en-US matches en)
+		primary := strings.SplitN(t.tag, "-", 2)[0]
+		for lang := range available {
+			if strings.SplitN(strings.ToLower(lang), "-", 2)[0] == primary {
+				return lang
+			}
+		}
+	}
+
+	return ""
+
+}
+
+// translateResponse converts the detection labels to the requested language, it returns true if any were converted.
+// The translated detections are copies so the event keeps the original labels.
+func (d *muxDetector) translateResponse(language string, response *odrpc.DetectResponse) bool {
+	if len(d.translations) == 0 || language == "" {
+		return false
+	}
+	lang := matchLanguage(language, d.translations)
+	if lang == "" {
+		return false
+	}
+	translations := d.translations[lang]
+	translated := false
+	for i, detection := range response.Detections {
+		if t, ok := translations[detection.Label]; ok {
+			c := *detection
+			c.Label = t
+			response.Detections[i] = &c
+			translated = true
+		}
+	}
+	return translated
+}
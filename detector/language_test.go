@@ -0,0 +1,78 @@
+package detector
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	config "github.com/spf13/viper"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/history"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/sink"
+)
+
+func TestMatchLanguage(t *testing.T) {
+
+	available := map[string]map[string]string{"es": nil, "pt-BR": nil}
+	for _, test := range []struct {
+		request  string
+		expected string
+	}{
+		{"es", "es"},
+		{"es-MX", "es"},
+		{"pt-br", "pt-BR"},
+		{"fr, es;q=0.5", "es"},
+		{"es;q=0, pt", "pt-BR"},
+		{"fr", ""},
+		{"*", ""},
+	} {
+		if lang := matchLanguage(test.request, available); lang != test.expected {
+			t.Errorf("%q matched %q, expected %q", test.request, lang, test.expected)
+		}
+	}
+
+}
+
+func TestTranslatedEvent(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer config.Reset()
+	config.Set("doods.history.dir", dir)
+	config.Set("doods.history.retention", time.Hour)
+	config.Set("doods.history.labels", map[string]string{"dog": "0s"})
+
+	lc := conf.NewLifecycle()
+	m := &Mux{history: history.New(lc)}
+	d := &muxDetector{translations: map[string]map[string]string{"es": {"dog": "perro", "cat": "gato"}}}
+
+	for label, translated := range map[string]string{"dog": "perro", "cat": "gato"} {
+		response := &odrpc.DetectResponse{Id: label, Detections: []*odrpc.Detection{{Label: label, Confidence: 90}}}
+		event := &sink.Event{Time: time.Now(), ID: label, Response: eventResponse(response)}
+		if err := m.finishResponse(context.Background(), &odrpc.DetectRequest{Id: label, Language: "es"}, d, []byte("image"), response, event); err != nil {
+			t.Fatal(err)
+		}
+		if response.Detections[0].Label != translated {
+			t.Errorf("response label %s, expected %s", response.Detections[0].Label, translated)
+		}
+		if event.Response.Detections[0].Label != label {
+			t.Errorf("event label %s, expected the original label %s", event.Response.Detections[0].Label, label)
+		}
+	}
+
+	// The dog isn't kept even though the response had its translated label
+	lc.Stop()
+	lc.Wait()
+	entries := m.history.Entries(&history.Filter{})
+	if len(entries) != 1 || entries[0].Detections[0].Label != "cat" {
+		t.Fatalf("got entries %v, expected only the cat with its original label", entries)
+	}
+
+}
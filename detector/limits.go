@@ -0,0 +1,40 @@
+package detector
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	config "github.com/spf13/viper"
+)
+
+// imageLimits rejects images that would use too much memory to decode (decompression bombs)
+type imageLimits struct {
+	maxWidth  int
+	maxHeight int
+	maxPixels int64
+}
+
+func newImageLimits() *imageLimits {
+	return &imageLimits{
+		maxWidth:  config.GetInt("doods.image.max_width"),
+		maxHeight: config.GetInt("doods.image.max_height"),
+		maxPixels: config.GetInt64("doods.image.max_pixels"),
+	}
+}
+
+// check reads the image size from the header without decoding it. Formats that can't be read here
+// are left for the detector.
+func (l *imageLimits) check(data []byte) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	if (l.maxWidth > 0 && cfg.Width > l.maxWidth) || (l.maxHeight > 0 && cfg.Height > l.maxHeight) {
+		return fmt.Errorf("image is too large: %dx%d, the max is %dx%d", cfg.Width, cfg.Height, l.maxWidth, l.maxHeight)
+	}
+	if l.maxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > l.maxPixels {
+		return fmt.Errorf("image is too large: %dx%d is more than %d pixels", cfg.Width, cfg.Height, l.maxPixels)
+	}
+	return nil
+}
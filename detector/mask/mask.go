@@ -0,0 +1,86 @@
+package mask
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+
+	// We will support these formats
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/bmp"
+)
+
+// Mask is a grayscale image where black areas are ignored
+type Mask struct {
+	img *image.Gray
+}
+
+// Load reads a mask image. Any image format supported by doods can be used.
+func Load(filename string) (*Mask, error) {
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not open mask %s: %v", filename, err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode mask %s: %v", filename, err)
+	}
+
+	gray := image.NewGray(img.Bounds())
+	draw.Draw(gray, gray.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	return &Mask{img: gray}, nil
+
+}
+
+// Masked returns true if the point (relative coordinates 0-1) is in a masked area
+func (m *Mask) Masked(x, y float32) bool {
+	b := m.img.Bounds()
+	px := b.Min.X + int(x*float32(b.Dx()))
+	py := b.Min.Y + int(y*float32(b.Dy()))
+	if px >= b.Max.X {
+		px = b.Max.X - 1
+	}
+	if py >= b.Max.Y {
+		py = b.Max.Y - 1
+	}
+	return m.img.GrayAt(px, py).Y < 128
+}
+
+// Apply blacks out the masked areas of the image data and returns it as a BMP
+func (m *Mask) Apply(data []byte) ([]byte, error) {
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %v", err)
+	}
+
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+
+	w, h := float32(b.Dx()), float32(b.Dy())
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			if m.Masked(float32(x)/w, float32(y)/h) {
+				i := rgba.PixOffset(x, y)
+				rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2] = 0, 0, 0
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := bmp.Encode(&buf, rgba); err != nil {
+		return nil, fmt.Errorf("could not encode image: %v", err)
+	}
+	return buf.Bytes(), nil
+
+}
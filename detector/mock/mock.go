@@ -0,0 +1,127 @@
+// Package mock is a detector that returns canned detections without a model for testing
+package mock
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/labels"
+	"github.com/snowzach/doods/odrpc"
+)
+
+type detector struct {
+	config odrpc.Detector
+	logger *zap.SugaredLogger
+	lc     *conf.Lifecycle
+
+	mock   dconfig.MockConfig
+	labels labels.Labels
+}
+
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (*detector, error) {
+
+	d := &detector{
+		logger: zap.S().With("package", "detector.mock", "name", c.Name),
+		lc:     lc,
+		labels: make(labels.Labels),
+	}
+	if c.Mock != nil {
+		d.mock = *c.Mock
+	}
+
+	d.config.Name = c.Name
+	d.config.Type = c.Type
+	d.config.Model = "mock"
+	d.config.Width = -1
+	d.config.Height = -1
+
+	// The labels are the extra labels and the detection labels
+	names := make(map[string]struct{})
+	for _, label := range d.mock.Labels {
+		names[label] = struct{}{}
+	}
+	for _, md := range d.mock.Detections {
+		names[md.Label] = struct{}{}
+	}
+	sorted := make([]string, 0, len(names))
+	for label := range names {
+		sorted = append(sorted, label)
+	}
+	sort.Strings(sorted)
+	for i, label := range sorted {
+		d.labels[i] = label
+	}
+	d.config.Labels = d.labels.Names()
+
+	return d, nil
+
+}
+
+func (d *detector) Config() *odrpc.Detector {
+	return &d.config
+}
+
+func (d *detector) Labels() labels.Labels {
+	return d.labels
+}
+
+func (d *detector) Shutdown() {}
+
+func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+
+	done := d.lc.Track() // Wait until detection complete before stopping
+	defer done()
+
+	start := time.Now()
+
+	// Simulate the inference time
+	latency := d.mock.Latency
+	if d.mock.Jitter > 0 {
+		latency += time.Duration(rand.Int63n(int64(d.mock.Jitter)))
+	}
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return nil, status.Errorf(codes.Canceled, "detect canceled")
+		}
+	}
+
+	if d.mock.ErrorPercent > 0 && rand.Float64()*100 < d.mock.ErrorPercent {
+		return &odrpc.DetectResponse{
+			Id:    request.Id,
+			Error: "detector error",
+		}, nil
+	}
+
+	detections := make([]*odrpc.Detection, 0, len(d.mock.Detections))
+	for _, md := range d.mock.Detections {
+		if md.Percent > 0 && md.Percent < 100 && rand.Float64()*100 >= md.Percent {
+			continue
+		}
+		detections = append(detections, &odrpc.Detection{
+			Top:        md.Top,
+			Left:       md.Left,
+			Bottom:     md.Bottom,
+			Right:      md.Right,
+			Label:      md.Label,
+			Confidence: md.Confidence,
+		})
+	}
+
+	d.logger.Debugw("Detection Complete", "id", request.Id, "duration", time.Since(start), "detections", len(detections))
+
+	return &odrpc.DetectResponse{
+		Id:         request.Id,
+		Detections: detections,
+	}, nil
+
+}
@@ -0,0 +1,65 @@
+package detector
+
+import (
+	"bytes"
+	"image"
+	"time"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/sun"
+)
+
+// night switches to another detector (IR model) at night
+type night struct {
+	detector   string
+	latitude   float64
+	longitude  float64
+	brightness float64
+}
+
+func newNight(c *dconfig.NightConfig) *night {
+	return &night{
+		detector:   c.Detector,
+		latitude:   c.Latitude,
+		longitude:  c.Longitude,
+		brightness: c.Brightness,
+	}
+}
+
+// isNight determines if it's night from the image brightness if configured otherwise the position of the sun
+func (n *night) isNight(t time.Time, data []byte) bool {
+	if n.brightness > 0 {
+		if b, ok := averageBrightness(data); ok {
+			return b < n.brightness
+		}
+	}
+	return sun.IsNight(t, n.latitude, n.longitude)
+}
+
+// averageBrightness returns the average luma (0-255) of the image by sampling a grid of pixels
+func averageBrightness(data []byte) (float64, bool) {
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, false
+	}
+
+	const samples = 64
+	b := img.Bounds()
+	stepX, stepY := b.Dx()/samples+1, b.Dy()/samples+1
+
+	var total float64
+	var count int
+	for y := b.Min.Y; y < b.Max.Y; y += stepY {
+		for x := b.Min.X; x < b.Max.X; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			total += (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return total / float64(count), true
+
+}
@@ -0,0 +1,29 @@
+//go:build !onnx
+// +build !onnx
+
+// Package onnx runs ONNX models with ONNX Runtime so AMD GPUs (ROCm or MIGraphX), DirectML on Windows and
+// CUDA can be used. It is only built with the onnx build tag and needs the onnxruntime library and headers.
+package onnx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+type detector struct{}
+
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (*detector, error) {
+	return nil, fmt.Errorf("doods was built without onnx support, build with -tags onnx")
+}
+
+func (d *detector) Config() *odrpc.Detector { return nil }
+
+func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+	return nil, fmt.Errorf("onnx is not supported")
+}
+
+func (d *detector) Shutdown() {}
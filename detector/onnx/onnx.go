@@ -0,0 +1,406 @@
+//go:build onnx
+// +build onnx
+
+// Package onnx runs ONNX models with ONNX Runtime so AMD GPUs (ROCm or MIGraphX), DirectML on Windows and
+// CUDA can be used. It is only built with the onnx build tag and needs the onnxruntime library and headers.
+package onnx
+
+// #include <stdlib.h>
+import "C"
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+	"time"
+	"unsafe"
+
+	"go.uber.org/zap"
+	"gocv.io/x/gocv"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/labels"
+	"github.com/snowzach/doods/detector/pixel"
+	"github.com/snowzach/doods/detector/postprocess"
+	"github.com/snowzach/doods/odrpc"
+)
+
+const (
+	outputFormatRaw = iota
+	outputFormatPlugin
+	outputFormatSSD
+)
+
+// The execution providers
+var providers = map[string]struct{}{"cpu": {}, "cuda": {}, "rocm": {}, "migraphx": {}, "directml": {}}
+
+type detector struct {
+	config odrpc.Detector
+	logger *zap.SugaredLogger
+	lc     *conf.Lifecycle
+
+	labels       labels.Labels
+	pool         chan *session
+	input        *tensorInfo
+	inputShape   []int64
+	nchw         bool
+	outputs      []*tensorInfo
+	outputFormat int
+	postProcess  postprocess.Func
+	provider     string
+	timeout      time.Duration
+}
+
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (*detector, error) {
+
+	d := &detector{
+		logger:   zap.S().With("package", "detector.onnx", "name", c.Name),
+		lc:       lc,
+		provider: strings.ToLower(c.Provider),
+		timeout:  c.Timeout,
+	}
+	if d.provider == "" {
+		d.provider = "cpu"
+	}
+	if _, ok := providers[d.provider]; !ok {
+		return nil, fmt.Errorf("invalid provider: %s", c.Provider)
+	}
+
+	d.config.Name = c.Name
+	d.config.Type = c.Type + "-" + d.provider
+	d.config.Model = c.ModelFile
+	d.config.Labels = make([]string, 0)
+
+	if c.NumConcurrent <= 0 {
+		c.NumConcurrent = 1
+	}
+	d.pool = make(chan *session, c.NumConcurrent)
+
+	// Load labels
+	var err error
+	d.labels, err = labels.Load(c.LabelFormat, c.LabelFile, c.ReadModel)
+	if err != nil && (c.RawOutputs || c.PostProcess != nil) && c.LabelFile == "" {
+		// Labels are optional for raw outputs
+		d.labels = make(labels.Labels)
+	} else if err != nil {
+		return nil, fmt.Errorf("could not load labels: %v", err)
+	}
+	d.config.Labels = d.labels.Names()
+
+	// Create a session for each concurrent detection from the checked data so the file can't change after it was verified
+	model, err := c.ReadModel()
+	if err != nil {
+		return nil, err
+	}
+	for x := 0; x < c.NumConcurrent; x++ {
+		s, err := newSession(model, d.provider, c.DeviceID, c.NumThreads)
+		if err != nil {
+			d.Shutdown()
+			return nil, fmt.Errorf("could not load model %s with %s: %v", c.ModelFile, d.provider, err)
+		}
+		d.pool <- s
+	}
+
+	// Get the model inputs and outputs from one of the sessions
+	s := <-d.pool
+	err = d.loadTensors(s, c)
+	d.pool <- s
+	if err != nil {
+		d.Shutdown()
+		return nil, err
+	}
+
+	switch {
+	case c.RawOutputs:
+		d.outputFormat = outputFormatRaw
+	case c.PostProcess != nil && c.PostProcess.Plugin != "":
+		if d.postProcess, err = postprocess.Load(c.PostProcess.Plugin, c.PostProcess.Options); err != nil {
+			d.Shutdown()
+			return nil, err
+		}
+		d.outputFormat = outputFormatPlugin
+	case len(d.outputs) == 4:
+		d.outputFormat = outputFormatSSD
+	default:
+		d.Shutdown()
+		return nil, fmt.Errorf("unsupported model with %d outputs, use a postProcess plugin or rawOutputs", len(d.outputs))
+	}
+
+	d.logger.Infow("ONNX model loaded", "provider", d.provider, "device_id", c.DeviceID, "width", d.config.Width, "height", d.config.Height, "outputs", len(d.outputs))
+
+	return d, nil
+
+}
+
+// loadTensors reads the image input and the outputs of the model
+func (d *detector) loadTensors(s *session, c *dconfig.DetectorConfig) error {
+
+	inputs, err := s.tensors(false)
+	if err != nil {
+		return fmt.Errorf("could not read model inputs: %v", err)
+	}
+	if len(inputs) != 1 {
+		return fmt.Errorf("unsupported model with %d inputs", len(inputs))
+	}
+	d.input = inputs[0]
+	if len(d.input.shape) != 4 {
+		return fmt.Errorf("unsupported model input with %d dimensions", len(d.input.shape))
+	}
+	if d.input.typ != typeFloat && d.input.typ != typeUint8 {
+		return fmt.Errorf("unsupported model input type %d", d.input.typ)
+	}
+
+	// Channels first (NCHW) or last (NHWC), dynamic sizes use the configured input size
+	shape := append([]int64{}, d.input.shape...)
+	d.nchw = shape[1] == 3
+	hIndex, wIndex, cIndex := 1, 2, 3
+	if d.nchw {
+		hIndex, wIndex, cIndex = 2, 3, 1
+	}
+	shape[0] = 1
+	if shape[hIndex] <= 0 {
+		shape[hIndex] = int64(c.InputHeight)
+	}
+	if shape[wIndex] <= 0 {
+		shape[wIndex] = int64(c.InputWidth)
+	}
+	if shape[hIndex] <= 0 || shape[wIndex] <= 0 {
+		return fmt.Errorf("model has a dynamic input size, set inputWidth and inputHeight")
+	}
+	if shape[cIndex] != 3 {
+		return fmt.Errorf("unsupported model input with %d channels", shape[cIndex])
+	}
+	d.inputShape = shape
+	d.config.Height, d.config.Width, d.config.Channels = int32(shape[hIndex]), int32(shape[wIndex]), 3
+	d.config.Input = &odrpc.InputSpec{Type: "UInt8", Layout: "NHWC"}
+	if d.nchw {
+		d.config.Input.Layout = "NCHW"
+	}
+	if d.input.typ == typeFloat {
+		d.config.Input.Type = "Float32"
+		d.config.Input.Mean = []float32{0, 0, 0}
+		d.config.Input.Scale = []float32{1.0 / 255, 1.0 / 255, 1.0 / 255}
+	}
+
+	if d.outputs, err = s.tensors(true); err != nil {
+		return fmt.Errorf("could not read model outputs: %v", err)
+	}
+
+	return nil
+
+}
+
+func (d *detector) Config() *odrpc.Detector {
+	return &d.config
+}
+
+func (d *detector) Labels() labels.Labels {
+	return d.labels
+}
+
+func (d *detector) Shutdown() {
+	for {
+		select {
+		case s := <-d.pool:
+			s.release()
+		default:
+			return
+		}
+	}
+}
+
+func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+
+	start := time.Now()
+
+	img, err := gocv.IMDecode(request.Data, gocv.IMReadColor)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not decode image: %v", err)
+	} else if img.Empty() {
+		return nil, status.Errorf(codes.InvalidArgument, "could not read image")
+	}
+	defer img.Close()
+
+	if int32(img.Cols()) != d.config.Width || int32(img.Rows()) != d.config.Height {
+		gocv.Resize(img, &img, image.Point{X: int(d.config.Width), Y: int(d.config.Height)}, 0, 0, gocv.InterpolationLinear)
+	}
+	gocv.CvtColor(img, &img, gocv.ColorBGRToRGB)
+	if img.Type() != gocv.MatTypeCV8UC3 {
+		img.ConvertTo(&img, gocv.MatTypeCV8UC3)
+	}
+
+	// Build the input, float inputs are scaled to 0-1. It's leaked if the run hangs.
+	var hung bool
+	data, size := d.inputData(img.ToBytes())
+	defer func() {
+		if !hung {
+			C.free(data)
+		}
+	}()
+
+	d.logger.Debugw("Image pre-processing complete", "id", request.Id, "duration", time.Since(start))
+
+	// Get a session from the pool
+	var s *session
+	select {
+	case s = <-d.pool:
+	case <-d.lc.Done():
+		return nil, status.Errorf(codes.Unavailable, "detector is stopping")
+	}
+	done := d.lc.Track() // Wait until detection complete before stopping
+	defer func() {
+		if !hung {
+			d.pool <- s
+		}
+		done()
+	}()
+
+	inferenceStart := time.Now()
+
+	var values [][]float32
+	var shapes [][]int64
+	complete := make(chan struct{})
+	go func() {
+		values, shapes, err = s.run(d.input, data, size, d.inputShape, d.outputs)
+		close(complete)
+	}()
+
+	// Wait for complete or timeout if there is one set
+	if d.timeout > 0 {
+		select {
+		case <-complete:
+		case <-time.After(d.timeout):
+			// The GPU is hung, stop the detector so it's reinitialized
+			d.logger.Errorw("Detector timeout", "provider", d.provider)
+			hung = true
+			d.lc.Stop()
+			return nil, status.Errorf(codes.Internal, "detect failed")
+		}
+	}
+	<-complete
+
+	if err != nil {
+		d.logger.Errorw("Detector error", "id", request.Id, "error", err)
+		return &odrpc.DetectResponse{
+			Id:    request.Id,
+			Error: "detector error",
+		}, nil
+	}
+
+	d.logger.Debugw("Inference complete", "inference_time", time.Since(inferenceStart), "duration", time.Since(start))
+
+	tensors := make([]*odrpc.OutputTensor, len(values))
+	for x := range values {
+		tensors[x] = &odrpc.OutputTensor{
+			Name:   d.outputs[x].name,
+			Type:   "Float32",
+			Values: values[x],
+		}
+		for _, dim := range shapes[x] {
+			tensors[x].Shape = append(tensors[x].Shape, int32(dim))
+		}
+	}
+
+	response := &odrpc.DetectResponse{
+		Id:         request.Id,
+		Detections: make([]*odrpc.Detection, 0),
+	}
+	if request.RawOutputs != odrpc.RAW_NONE || d.outputFormat == outputFormatRaw {
+		response.Outputs = tensors
+		if request.RawOutputs == odrpc.RAW_ONLY || d.outputFormat == outputFormatRaw {
+			return response, nil
+		}
+	}
+
+	switch d.outputFormat {
+	case outputFormatPlugin:
+		detections, err := d.postProcess(tensors, d.labels)
+		if err != nil {
+			d.logger.Errorw("Post-processor error", "id", request.Id, "error", err)
+			return &odrpc.DetectResponse{
+				Id:    request.Id,
+				Error: "post-processor error",
+			}, nil
+		}
+		if detections != nil {
+			response.Detections = detections
+		}
+
+	case outputFormatSSD:
+		// Boxes, classes, scores and count like the TFLite_Detection_PostProcess outputs
+		locations, classes, scores := values[0], values[1], values[2]
+		if len(values[3]) == 0 {
+			d.logger.Errorw("Detector invalid results", "id", request.Id)
+			return &odrpc.DetectResponse{
+				Id:    request.Id,
+				Error: "detector invalid result",
+			}, nil
+		}
+		count := int(values[3][0])
+		if count > len(scores) {
+			count = len(scores)
+		}
+		if count > len(classes) {
+			count = len(classes)
+		}
+		if count > len(locations)/4 {
+			count = len(locations) / 4
+		}
+		for i := 0; i < count; i++ {
+			label, ok := d.labels[int(classes[i])]
+			if !ok {
+				d.logger.Warnw("Missing label", "index", classes[i])
+				label = "unknown"
+			}
+			response.Detections = append(response.Detections, &odrpc.Detection{
+				Top:        locations[(i * 4)],
+				Left:       locations[(i*4)+1],
+				Bottom:     locations[(i*4)+2],
+				Right:      locations[(i*4)+3],
+				Label:      label,
+				Confidence: scores[i] * 100.0,
+			})
+		}
+	}
+
+	d.logger.Infow("Detection Complete", "id", request.Id, "duration", time.Since(start), "detections", len(response.Detections), "provider", d.provider)
+
+	return response, nil
+
+}
+
+// inputData converts the RGB image (HWC) to the model input in C memory, the caller frees it
+func (d *detector) inputData(rgb []byte) (unsafe.Pointer, int) {
+
+	pixels := int(d.config.Width * d.config.Height)
+
+	if d.input.typ == typeUint8 {
+		size := pixels * 3
+		data := C.malloc(C.size_t(size))
+		out := (*[1 << 30]uint8)(data)[:size:size]
+		if d.nchw {
+			for i := 0; i < pixels; i++ {
+				out[i], out[pixels+i], out[2*pixels+i] = rgb[i*3], rgb[i*3+1], rgb[i*3+2]
+			}
+		} else {
+			copy(out, rgb)
+		}
+		return data, size
+	}
+
+	size := pixels * 3 * 4
+	data := C.malloc(C.size_t(size))
+	out := (*[1 << 28]float32)(data)[: pixels*3 : pixels*3]
+	if !d.nchw {
+		pixel.Normalize(out, rgb, [3]float32{}, [3]float32{1.0 / 255, 1.0 / 255, 1.0 / 255})
+		return data, size
+	}
+	for i := 0; i < pixels; i++ {
+		out[i], out[pixels+i], out[2*pixels+i] = float32(rgb[i*3])/255, float32(rgb[i*3+1])/255, float32(rgb[i*3+2])/255
+	}
+	return data, size
+
+}
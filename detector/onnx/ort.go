@@ -0,0 +1,358 @@
+//go:build onnx
+// +build onnx
+
+package onnx
+
+/*
+#include <stdlib.h>
+#include <string.h>
+#include <onnxruntime_c_api.h>
+#ifdef _WIN32
+#include <dml_provider_factory.h>
+#endif
+#cgo LDFLAGS: -lonnxruntime
+
+static const OrtApi* ort;
+
+// ort_error converts a status to an error message the caller frees, NULL if ok
+static char* ort_error(OrtStatus* status) {
+	if (status == NULL) {
+		return NULL;
+	}
+	char* msg = strdup(ort->GetErrorMessage(status));
+	ort->ReleaseStatus(status);
+	return msg;
+}
+
+static char* ort_init(OrtEnv** env) {
+	ort = OrtGetApiBase()->GetApi(ORT_API_VERSION);
+	if (ort == NULL) {
+		return strdup("unsupported onnxruntime version");
+	}
+	return ort_error(ort->CreateEnv(ORT_LOGGING_LEVEL_WARNING, "doods", env));
+}
+
+// ort_session creates a session on the execution provider: cpu, cuda, rocm, migraphx or directml
+static char* ort_session(OrtEnv* env, const void* model, size_t size, const char* provider, int device, int threads, OrtSession** session) {
+	OrtSessionOptions* options;
+	char* err = ort_error(ort->CreateSessionOptions(&options));
+	if (err != NULL) {
+		return err;
+	}
+	if (threads > 0) {
+		err = ort_error(ort->SetIntraOpNumThreads(options, threads));
+	}
+	if (err == NULL) {
+		err = ort_error(ort->SetSessionGraphOptimizationLevel(options, ORT_ENABLE_ALL));
+	}
+	if (err == NULL && strcmp(provider, "cuda") == 0) {
+		OrtCUDAProviderOptions o;
+		memset(&o, 0, sizeof(o));
+		o.device_id = device;
+		err = ort_error(ort->SessionOptionsAppendExecutionProvider_CUDA(options, &o));
+	} else if (err == NULL && strcmp(provider, "rocm") == 0) {
+		OrtROCMProviderOptions o;
+		memset(&o, 0, sizeof(o));
+		o.device_id = device;
+		err = ort_error(ort->SessionOptionsAppendExecutionProvider_ROCM(options, &o));
+	} else if (err == NULL && strcmp(provider, "migraphx") == 0) {
+		OrtMIGraphXProviderOptions o;
+		memset(&o, 0, sizeof(o));
+		o.device_id = device;
+		err = ort_error(ort->SessionOptionsAppendExecutionProvider_MIGraphX(options, &o));
+	} else if (err == NULL && strcmp(provider, "directml") == 0) {
+#ifdef _WIN32
+		free(ort_error(ort->DisableMemPattern(options)));
+		free(ort_error(ort->SetSessionExecutionMode(options, ORT_SEQUENTIAL)));
+		err = ort_error(OrtSessionOptionsAppendExecutionProvider_DML(options, device));
+#else
+		err = strdup("directml is only supported on windows");
+#endif
+	}
+	if (err == NULL) {
+		err = ort_error(ort->CreateSessionFromArray(env, model, size, options, session));
+	}
+	ort->ReleaseSessionOptions(options);
+	return err;
+}
+
+// ort_tensor_info reads the element type and shape of an input or output, shape must have room for 8 dims
+static char* ort_tensor_info(OrtSession* session, int output, size_t index, char** name, int* type, int64_t* shape, size_t* dims) {
+	OrtAllocator* allocator;
+	char* err = ort_error(ort->GetAllocatorWithDefaultOptions(&allocator));
+	if (err != NULL) {
+		return err;
+	}
+	char* ortName;
+	OrtTypeInfo* info;
+	if (output) {
+		err = ort_error(ort->SessionGetOutputName(session, index, allocator, &ortName));
+		if (err == NULL) {
+			err = ort_error(ort->SessionGetOutputTypeInfo(session, index, &info));
+		}
+	} else {
+		err = ort_error(ort->SessionGetInputName(session, index, allocator, &ortName));
+		if (err == NULL) {
+			err = ort_error(ort->SessionGetInputTypeInfo(session, index, &info));
+		}
+	}
+	if (err != NULL) {
+		return err;
+	}
+	*name = strdup(ortName);
+	ort->AllocatorFree(allocator, ortName);
+
+	const OrtTensorTypeAndShapeInfo* tensor;
+	err = ort_error(ort->CastTypeInfoToTensorInfo(info, &tensor));
+	if (err == NULL) {
+		ONNXTensorElementDataType t;
+		err = ort_error(ort->GetTensorElementType(tensor, &t));
+		*type = (int)t;
+	}
+	if (err == NULL) {
+		err = ort_error(ort->GetDimensionsCount(tensor, dims));
+	}
+	if (err == NULL && *dims > 8) {
+		err = strdup("too many dimensions");
+	}
+	if (err == NULL) {
+		err = ort_error(ort->GetDimensions(tensor, shape, *dims));
+	}
+	ort->ReleaseTypeInfo(info);
+	return err;
+}
+
+// ort_run runs the session with one input, the outputs are released with ort_release_outputs
+static char* ort_run(OrtSession* session, const char* input_name, void* data, size_t size, int type, int64_t* shape, size_t dims, const char** output_names, size_t num_outputs, OrtValue** outputs) {
+	OrtMemoryInfo* memory;
+	char* err = ort_error(ort->CreateCpuMemoryInfo(OrtArenaAllocator, OrtMemTypeDefault, &memory));
+	if (err != NULL) {
+		return err;
+	}
+	OrtValue* input = NULL;
+	err = ort_error(ort->CreateTensorWithDataAsOrtValue(memory, data, size, shape, dims, (ONNXTensorElementDataType)type, &input));
+	ort->ReleaseMemoryInfo(memory);
+	if (err != NULL) {
+		return err;
+	}
+	const OrtValue* inputs[1] = {input};
+	const char* input_names[1] = {input_name};
+	err = ort_error(ort->Run(session, NULL, input_names, inputs, 1, output_names, num_outputs, outputs));
+	ort->ReleaseValue(input);
+	return err;
+}
+
+// ort_output returns the data, element type and shape of an output, shape must have room for 8 dims
+static char* ort_output(OrtValue* value, void** data, int* type, size_t* count, int64_t* shape, size_t* dims) {
+	OrtTensorTypeAndShapeInfo* info;
+	char* err = ort_error(ort->GetTensorTypeAndShape(value, &info));
+	if (err != NULL) {
+		return err;
+	}
+	ONNXTensorElementDataType t;
+	err = ort_error(ort->GetTensorElementType(info, &t));
+	*type = (int)t;
+	if (err == NULL) {
+		err = ort_error(ort->GetTensorShapeElementCount(info, count));
+	}
+	if (err == NULL) {
+		err = ort_error(ort->GetDimensionsCount(info, dims));
+	}
+	if (err == NULL && *dims > 8) {
+		err = strdup("too many dimensions");
+	}
+	if (err == NULL) {
+		err = ort_error(ort->GetDimensions(info, shape, *dims));
+	}
+	ort->ReleaseTensorTypeAndShapeInfo(info);
+	if (err == NULL) {
+		err = ort_error(ort->GetTensorMutableData(value, data));
+	}
+	return err;
+}
+
+static void ort_release_outputs(OrtValue** outputs, size_t count) {
+	for (size_t i = 0; i < count; i++) {
+		if (outputs[i] != NULL) {
+			ort->ReleaseValue(outputs[i]);
+		}
+	}
+}
+
+static char* ort_count(OrtSession* session, int output, size_t* count) {
+	if (output) {
+		return ort_error(ort->SessionGetOutputCount(session, count));
+	}
+	return ort_error(ort->SessionGetInputCount(session, count));
+}
+
+static void ort_release_session(OrtSession* session) {
+	ort->ReleaseSession(session);
+}
+*/
+import "C"
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// The tensor element types
+const (
+	typeFloat = C.ONNX_TENSOR_ELEMENT_DATA_TYPE_FLOAT
+	typeUint8 = C.ONNX_TENSOR_ELEMENT_DATA_TYPE_UINT8
+	typeInt32 = C.ONNX_TENSOR_ELEMENT_DATA_TYPE_INT32
+	typeInt64 = C.ONNX_TENSOR_ELEMENT_DATA_TYPE_INT64
+)
+
+var (
+	env     *C.OrtEnv
+	envErr  error
+	envOnce sync.Once
+)
+
+// ortError converts the error message from the shim and frees it
+func ortError(msg *C.char) error {
+	if msg == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(msg))
+	return fmt.Errorf("%s", C.GoString(msg))
+}
+
+// session is a loaded model, each one can run one detection at a time
+type session struct {
+	s *C.OrtSession
+}
+
+// tensorInfo is an input or output of the model
+type tensorInfo struct {
+	name  string
+	cName *C.char
+	typ   int
+	shape []int64
+}
+
+func newSession(model []byte, provider string, device, threads int) (*session, error) {
+
+	envOnce.Do(func() {
+		envErr = ortError(C.ort_init(&env))
+	})
+	if envErr != nil {
+		return nil, fmt.Errorf("could not initialize onnxruntime: %v", envErr)
+	}
+
+	cModel := C.CBytes(model)
+	defer C.free(cModel)
+	cProvider := C.CString(provider)
+	defer C.free(unsafe.Pointer(cProvider))
+
+	s := new(session)
+	if err := ortError(C.ort_session(env, cModel, C.size_t(len(model)), cProvider, C.int(device), C.int(threads), &s.s)); err != nil {
+		return nil, err
+	}
+	return s, nil
+
+}
+
+// tensors returns the inputs or outputs of the model
+func (s *session) tensors(output bool) ([]*tensorInfo, error) {
+
+	var out C.int
+	if output {
+		out = 1
+	}
+	var count C.size_t
+	if err := ortError(C.ort_count(s.s, out, &count)); err != nil {
+		return nil, err
+	}
+
+	tensors := make([]*tensorInfo, 0, int(count))
+	for x := 0; x < int(count); x++ {
+		var name *C.char
+		var typ C.int
+		var shape [8]C.int64_t
+		var dims C.size_t
+		if err := ortError(C.ort_tensor_info(s.s, out, C.size_t(x), &name, &typ, &shape[0], &dims)); err != nil {
+			return nil, err
+		}
+		t := &tensorInfo{
+			name:  C.GoString(name),
+			cName: name, // Kept for running the session
+			typ:   int(typ),
+		}
+		for i := 0; i < int(dims); i++ {
+			t.shape = append(t.shape, int64(shape[i]))
+		}
+		tensors = append(tensors, t)
+	}
+	return tensors, nil
+
+}
+
+// run runs the session with the input data and returns the outputs converted to float32
+func (s *session) run(input *tensorInfo, data unsafe.Pointer, size int, shape []int64, outputs []*tensorInfo) ([][]float32, [][]int64, error) {
+
+	outputNames := (*[1 << 10]*C.char)(C.malloc(C.size_t(len(outputs)) * C.size_t(unsafe.Sizeof(uintptr(0)))))[:len(outputs):len(outputs)]
+	defer C.free(unsafe.Pointer(&outputNames[0]))
+	for x, o := range outputs {
+		outputNames[x] = o.cName
+	}
+	values := (*[1 << 10]*C.OrtValue)(C.calloc(C.size_t(len(outputs)), C.size_t(unsafe.Sizeof(uintptr(0)))))[:len(outputs):len(outputs)]
+	defer C.free(unsafe.Pointer(&values[0]))
+
+	cShape := make([]C.int64_t, len(shape))
+	for i, dim := range shape {
+		cShape[i] = C.int64_t(dim)
+	}
+
+	if err := ortError(C.ort_run(s.s, input.cName, data, C.size_t(size), C.int(input.typ), &cShape[0], C.size_t(len(cShape)), &outputNames[0], C.size_t(len(outputs)), &values[0])); err != nil {
+		return nil, nil, err
+	}
+	defer C.ort_release_outputs(&values[0], C.size_t(len(outputs)))
+
+	results := make([][]float32, len(outputs))
+	shapes := make([][]int64, len(outputs))
+	for x := range values {
+		var outData unsafe.Pointer
+		var typ C.int
+		var count, dims C.size_t
+		var outShape [8]C.int64_t
+		if err := ortError(C.ort_output(values[x], &outData, &typ, &count, &outShape[0], &dims)); err != nil {
+			return nil, nil, err
+		}
+		for i := 0; i < int(dims); i++ {
+			shapes[x] = append(shapes[x], int64(outShape[i]))
+		}
+		n := int(count)
+		results[x] = make([]float32, n)
+		if n == 0 {
+			continue
+		}
+		switch typ {
+		case typeFloat:
+			copy(results[x], (*[1 << 28]float32)(outData)[:n:n])
+		case typeUint8:
+			for i, v := range (*[1 << 30]uint8)(outData)[:n:n] {
+				results[x][i] = float32(v)
+			}
+		case typeInt32:
+			for i, v := range (*[1 << 28]int32)(outData)[:n:n] {
+				results[x][i] = float32(v)
+			}
+		case typeInt64:
+			for i, v := range (*[1 << 27]int64)(outData)[:n:n] {
+				results[x][i] = float32(v)
+			}
+		default:
+			return nil, nil, fmt.Errorf("unsupported output %s type %d", outputs[x].name, int(typ))
+		}
+	}
+
+	return results, shapes, nil
+
+}
+
+func (s *session) release() {
+	C.ort_release_session(s.s)
+}
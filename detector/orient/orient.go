@@ -0,0 +1,174 @@
+// Package orient rotates and flips images before detection and maps the detections back
+package orient
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/bmp"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The flip directions
+const (
+	FlipHorizontal = "horizontal"
+	FlipVertical   = "vertical"
+	FlipBoth       = "both"
+)
+
+// Validate checks the rotation and flip
+func Validate(rotate int32, flip string) error {
+	switch rotate {
+	case 0, 90, 180, 270:
+	default:
+		return fmt.Errorf("invalid rotate %d, must be 90, 180 or 270", rotate)
+	}
+	switch flip {
+	case "", FlipHorizontal, FlipVertical, FlipBoth:
+	default:
+		return fmt.Errorf("invalid flip %s, must be horizontal, vertical or both", flip)
+	}
+	return nil
+}
+
+// Apply rotates the image clockwise and then flips it
+func Apply(data []byte, rotate int32, flip string) ([]byte, error) {
+
+	if rotate == 0 && flip == "" {
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %v", err)
+	}
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if rotate == 90 || rotate == 270 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	hflip := flip == FlipHorizontal || flip == FlipBoth
+	vflip := flip == FlipVertical || flip == FlipBoth
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch rotate {
+			case 90:
+				dx, dy = h-1-y, x
+			case 180:
+				dx, dy = w-1-x, h-1-y
+			case 270:
+				dx, dy = y, w-1-x
+			default:
+				dx, dy = x, y
+			}
+			if hflip {
+				dx = dw - 1 - dx
+			}
+			if vflip {
+				dy = dh - 1 - dy
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):dst.PixOffset(dx, dy)+4], src.Pix[src.PixOffset(x, y):src.PixOffset(x, y)+4])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := bmp.Encode(&buf, dst); err != nil {
+		return nil, fmt.Errorf("could not encode image: %v", err)
+	}
+	return buf.Bytes(), nil
+
+}
+
+// MapBack converts the detections from the rotated and flipped image to the original image
+func MapBack(detections []*odrpc.Detection, rotate int32, flip string) {
+
+	if rotate == 0 && flip == "" {
+		return
+	}
+
+	for _, d := range detections {
+		x1, y1 := point(d.Left, d.Top, rotate, flip)
+		x2, y2 := point(d.Right, d.Bottom, rotate, flip)
+		d.Left, d.Right = min32(x1, x2), max32(x1, x2)
+		d.Top, d.Bottom = min32(y1, y2), max32(y1, y2)
+	}
+
+}
+
+// MapGrid converts a grid of values row by row, like a density map, from the rotated and flipped image to the original
+// image and returns it with its new width and height
+func MapGrid(values []float32, width, height int, rotate int32, flip string) ([]float32, int, int) {
+
+	if (rotate == 0 && flip == "") || len(values) != width*height {
+		return values, width, height
+	}
+
+	ow, oh := width, height
+	if rotate == 90 || rotate == 270 {
+		ow, oh = height, width
+	}
+	ret := make([]float32, len(values))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			ox, oy := point((float32(x)+0.5)/float32(width), (float32(y)+0.5)/float32(height), rotate, flip)
+			ret[cell(oy, oh)*ow+cell(ox, ow)] = values[y*width+x]
+		}
+	}
+	return ret, ow, oh
+
+}
+
+// point converts a relative point in the rotated and flipped image to the original image
+func point(x, y float32, rotate int32, flip string) (float32, float32) {
+	if flip == FlipHorizontal || flip == FlipBoth {
+		x = 1 - x
+	}
+	if flip == FlipVertical || flip == FlipBoth {
+		y = 1 - y
+	}
+	switch rotate {
+	case 90:
+		return y, 1 - x
+	case 180:
+		return 1 - x, 1 - y
+	case 270:
+		return 1 - y, x
+	}
+	return x, y
+}
+
+// cell returns the cell of a relative coordinate in a grid of size cells
+func cell(v float32, size int) int {
+	c := int(v * float32(size))
+	if c < 0 {
+		return 0
+	}
+	if c >= size {
+		return size - 1
+	}
+	return c
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
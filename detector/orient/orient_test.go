@@ -0,0 +1,114 @@
+package orient
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+func TestValidate(t *testing.T) {
+
+	for _, test := range []struct {
+		rotate int32
+		flip   string
+		valid  bool
+	}{
+		{0, "", true},
+		{90, FlipHorizontal, true},
+		{180, FlipVertical, true},
+		{270, FlipBoth, true},
+		{45, "", false},
+		{-90, "", false},
+		{360, "", false},
+		{0, "diagonal", false},
+	} {
+		if err := Validate(test.rotate, test.flip); (err == nil) != test.valid {
+			t.Errorf("rotate %d flip %q: %v", test.rotate, test.flip, err)
+		}
+	}
+
+}
+
+// TestApply rotates and flips an image where each pixel has a different color and checks that the box and the grid
+// cell of each pixel map back to where the pixel was in the original image
+func TestApply(t *testing.T) {
+
+	const w, h = 3, 2
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(y*w + x + 1), A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rotate := range []int32{0, 90, 180, 270} {
+		for _, flip := range []string{"", FlipHorizontal, FlipVertical, FlipBoth} {
+			data, err := Apply(buf.Bytes(), rotate, flip)
+			if err != nil {
+				t.Fatalf("rotate %d flip %q: %v", rotate, flip, err)
+			}
+			out, _, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("rotate %d flip %q: %v", rotate, flip, err)
+			}
+			dw, dh := out.Bounds().Dx(), out.Bounds().Dy()
+			if (rotate == 90 || rotate == 270) != (dw == h && dh == w) {
+				t.Fatalf("rotate %d flip %q: size %dx%d", rotate, flip, dw, dh)
+			}
+
+			grid := make([]float32, dw*dh)
+			for dy := 0; dy < dh; dy++ {
+				for dx := 0; dx < dw; dx++ {
+					r, _, _, _ := out.At(dx, dy).RGBA()
+					index := int(r>>8) - 1
+					x, y := index%w, index/w
+					grid[dy*dw+dx] = float32(index)
+
+					d := &odrpc.Detection{
+						Left:   float32(dx) / float32(dw),
+						Right:  float32(dx+1) / float32(dw),
+						Top:    float32(dy) / float32(dh),
+						Bottom: float32(dy+1) / float32(dh),
+					}
+					MapBack([]*odrpc.Detection{d}, rotate, flip)
+					if !near(d.Left, float32(x)/w) || !near(d.Right, float32(x+1)/w) || !near(d.Top, float32(y)/h) || !near(d.Bottom, float32(y+1)/h) {
+						t.Errorf("rotate %d flip %q: pixel %d,%d mapped back to %v, expected %d,%d", rotate, flip, dx, dy, d, x, y)
+					}
+				}
+			}
+
+			mapped, mw, mh := MapGrid(grid, dw, dh, rotate, flip)
+			if mw != w || mh != h {
+				t.Fatalf("rotate %d flip %q: grid size %dx%d", rotate, flip, mw, mh)
+			}
+			for i, v := range mapped {
+				if int(v) != i {
+					t.Errorf("rotate %d flip %q: grid %v", rotate, flip, mapped)
+					break
+				}
+			}
+		}
+	}
+
+	// Nothing to do returns the same image
+	if data, err := Apply(buf.Bytes(), 0, ""); err != nil || !bytes.Equal(data, buf.Bytes()) {
+		t.Errorf("image changed without rotate or flip: %v", err)
+	}
+	if _, err := Apply([]byte("not an image"), 90, ""); err == nil {
+		t.Error("expected an error for an invalid image")
+	}
+
+}
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
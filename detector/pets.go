@@ -0,0 +1,160 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/pets"
+	"github.com/snowzach/doods/server"
+)
+
+// recognizePets adds the enrolled pet most like each cat or dog as the pet attribute. Detections that don't match an
+// enrolled pet are left alone.
+func (m *Mux) recognizePets(ctx context.Context, request *odrpc.DetectRequest, img image.Image, detections []*odrpc.Detection) {
+
+	for _, d := range detections {
+		if ctx.Err() != nil {
+			return
+		}
+		if !m.pets.Matches(d.Label, d.Confidence) {
+			continue
+		}
+		embedding, err := m.embed(ctx, request.Id, img, &odrpc.Box{Top: d.Top, Left: d.Left, Bottom: d.Bottom, Right: d.Right})
+		if err != nil {
+			m.logger.Warnw("Could not get pet embedding", "id", request.Id, "error", err)
+			continue
+		}
+		if name, score := m.pets.Match(d.Label, embedding); name != "" {
+			d.Attributes = append(d.Attributes, &odrpc.Attribute{Name: pets.Attribute, Value: name, Confidence: score * 100})
+		}
+	}
+
+}
+
+// embed runs the pet embedding detector on the area of the box and returns its first output tensor
+func (m *Mux) embed(ctx context.Context, id string, img image.Image, box *odrpc.Box) ([]float32, error) {
+
+	detector, ok := m.detectors[m.pets.Detector()]
+	if !ok {
+		return nil, fmt.Errorf("embedding detector %s not found", m.pets.Detector())
+	}
+
+	area := cropArea(img.Bounds(), box, m.pets.Padding())
+	if area.Empty() {
+		return nil, fmt.Errorf("empty box")
+	}
+	data, err := encodeCrop(img, area)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode crop: %v", err)
+	}
+
+	response, _, err := m.detectWithFallback(ctx, detector, &odrpc.DetectRequest{
+		Id:           id,
+		DetectorName: m.pets.Detector(),
+		Data:         data,
+		RawOutputs:   odrpc.RAW_ONLY,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s", response.Error)
+	}
+	if len(response.Outputs) == 0 || len(response.Outputs[0].Values) == 0 {
+		return nil, fmt.Errorf("embedding detector %s returned no outputs", m.pets.Detector())
+	}
+	return response.Outputs[0].Values, nil
+
+}
+
+// handleEnrollPet adds a picture of a pet. The largest cat or dog the detector finds in the image is enrolled, or the
+// whole image with whole=true and the label.
+func (m *Mux) handleEnrollPet(w http.ResponseWriter, r *http.Request) {
+
+	name := chi.URLParam(r, "name")
+	query := r.URL.Query()
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	if err = m.limits.check(data); err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(fmt.Errorf("could not decode image: %v", err)))
+		return
+	}
+
+	label := query.Get("label")
+	box := &odrpc.Box{Bottom: 1, Right: 1}
+	if query.Get("whole") != "true" {
+		detectorName := query.Get("detector")
+		if detectorName == "" {
+			detectorName = "default"
+		}
+		detector, ok := m.detectors[detectorName]
+		if !ok {
+			render.Render(w, r, server.ErrNotFound)
+			return
+		}
+		response, _, err := m.detectWithFallback(r.Context(), detector, &odrpc.DetectRequest{DetectorName: detectorName, Data: data})
+		if err == nil && response.Error != "" {
+			err = fmt.Errorf("%s", response.Error)
+		}
+		if err != nil {
+			render.Render(w, r, server.ErrInternal(err))
+			return
+		}
+		var best *odrpc.Detection
+		for _, d := range response.Detections {
+			if !m.pets.Matches(d.Label, d.Confidence) || (label != "" && d.Label != label) {
+				continue
+			}
+			if best == nil || (d.Bottom-d.Top)*(d.Right-d.Left) > (best.Bottom-best.Top)*(best.Right-best.Left) {
+				best = d
+			}
+		}
+		if best == nil {
+			render.Render(w, r, server.ErrInvalidRequest(fmt.Errorf("no pet found in the image")))
+			return
+		}
+		label = best.Label
+		box = &odrpc.Box{Top: best.Top, Left: best.Left, Bottom: best.Bottom, Right: best.Right}
+	} else if !m.pets.Matches(label, 100) {
+		render.Render(w, r, server.ErrInvalidRequest(fmt.Errorf("label must be one of the pet labels with whole=true")))
+		return
+	}
+
+	embedding, err := m.embed(r.Context(), "", img, box)
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	pet, err := m.pets.Enroll(name, label, embedding)
+	if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+
+	m.logger.Infow("Enrolled pet", "name", pet.Name, "label", pet.Label, "embeddings", len(pet.Embeddings))
+
+	render.JSON(w, r, map[string]interface{}{
+		"name":       pet.Name,
+		"label":      pet.Label,
+		"embeddings": len(pet.Embeddings),
+		"box":        box,
+	})
+
+}
@@ -0,0 +1,141 @@
+// Package pixel has the kernels that convert and resize images for the model inputs. They use AVX2 on amd64
+// and NEON on arm64 when the CPU has them and fall back to pure Go everywhere else, or with the purego build tag.
+package pixel
+
+const (
+	channels = 3
+	// The bytes converted in each step of the SIMD normalize loop, a multiple of the channels
+	normalizeBlock = 24
+	// The bytes blended in each step of the SIMD resize loop
+	blendBlock = 16
+)
+
+var enabled = true
+
+// SetEnabled turns the SIMD kernels on or off, the results are the same either way
+func SetEnabled(e bool) {
+	enabled = e
+}
+
+// Kernel returns the name of the kernels in use, avx2, neon or generic
+func Kernel() string {
+	if enabled && hasSIMD {
+		return simdName
+	}
+	return "generic"
+}
+
+func useSIMD() bool {
+	return enabled && hasSIMD
+}
+
+// Normalize converts interleaved 3 channel bytes to floats as (value - mean) * scale for each channel
+func Normalize(dst []float32, src []byte, mean, scale [3]float32) {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+	var i int
+	if useSIMD() && n >= normalizeBlock {
+		i = n - n%normalizeBlock
+		normalizeSIMD(dst[:i], src[:i], mean, scale)
+	}
+	// The rest starts on a pixel boundary
+	normalizeGeneric(dst[i:n], src[i:n], mean, scale)
+}
+
+func normalizeGeneric(dst []float32, src []byte, mean, scale [3]float32) {
+	for i := range dst {
+		c := i % channels
+		dst[i] = (float32(src[i]) - mean[c]) * scale[c]
+	}
+}
+
+// Resize scales interleaved 3 channel bytes from sw x sh to dw x dh with bilinear interpolation
+func Resize(dst []byte, dw, dh int, src []byte, sw, sh int) {
+
+	if dw <= 0 || dh <= 0 || sw <= 0 || sh <= 0 || len(dst) < dw*dh*channels || len(src) < sw*sh*channels {
+		return
+	}
+	if dw == sw && dh == sh {
+		copy(dst, src[:sw*sh*channels])
+		return
+	}
+
+	xs, xw := axis(sw, dw)
+	ys, yw := axis(sh, dh)
+
+	// The source rows scaled horizontally. Neighbouring rows are in different slots so the two rows
+	// blended for an output row are both kept.
+	rowLen := dw * channels
+	rows := [2][]byte{make([]byte, rowLen), make([]byte, rowLen)}
+	loaded := [2]int{-1, -1}
+	row := func(y int) []byte {
+		k := y & 1
+		if loaded[k] != y {
+			scaleRow(rows[k], src[y*sw*channels:(y+1)*sw*channels], xs, xw)
+			loaded[k] = y
+		}
+		return rows[k]
+	}
+
+	for y := 0; y < dh; y++ {
+		y0 := ys[y]
+		y1 := y0 + 1
+		if y1 >= sh {
+			y1 = sh - 1
+		}
+		a := row(y0)
+		b := row(y1)
+		blend(dst[y*rowLen:(y+1)*rowLen], a, b, yw[y])
+	}
+
+}
+
+// axis returns the first source sample for each destination position and the weight of the next sample (0-256)
+func axis(s, d int) ([]int, []int) {
+	index := make([]int, d)
+	weight := make([]int, d)
+	ratio := float64(s) / float64(d)
+	for i := range index {
+		f := (float64(i)+0.5)*ratio - 0.5
+		if f < 0 {
+			f = 0
+		}
+		index[i] = int(f)
+		weight[i] = int((f-float64(index[i]))*256 + 0.5)
+		if index[i] >= s-1 {
+			index[i], weight[i] = s-1, 0
+		}
+	}
+	return index, weight
+}
+
+// scaleRow scales a source row horizontally
+func scaleRow(dst []byte, src []byte, xs []int, xw []int) {
+	last := len(src) - channels
+	for x, sx := range xs {
+		w := xw[x]
+		a := sx * channels
+		b := a + channels
+		if b > last {
+			b = last
+		}
+		for c := 0; c < channels; c++ {
+			dst[x*channels+c] = byte((int(src[a+c])*(256-w) + int(src[b+c])*w + 128) >> 8)
+		}
+	}
+}
+
+// blend mixes two rows, w is the weight of b (0-256)
+func blend(dst []byte, a []byte, b []byte, w int) {
+	var i int
+	// The kernels use byte weights, 0 and 256 are copies
+	if useSIMD() && w > 0 && w < 256 && len(dst) >= blendBlock {
+		i = len(dst) - len(dst)%blendBlock
+		blendSIMD(dst[:i], a[:i], b[:i], w)
+	}
+	for ; i < len(dst); i++ {
+		dst[i] = byte((int(a[i])*(256-w) + int(b[i])*w + 128) >> 8)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !purego
+// +build !purego
+
+package pixel
+
+import "golang.org/x/sys/cpu"
+
+const simdName = "avx2"
+
+var hasSIMD = cpu.X86.HasAVX2
+
+//go:noescape
+func normalizeAVX2(dst *float32, src *byte, n int, mean *[normalizeBlock]float32, scale *[normalizeBlock]float32)
+
+//go:noescape
+func blendAVX2(dst *byte, a *byte, b *byte, n int, w int)
+
+func normalizeSIMD(dst []float32, src []byte, mean, scale [3]float32) {
+	// The mean and scale repeated across the 24 bytes of each step
+	var m, s [normalizeBlock]float32
+	for i := range m {
+		m[i], s[i] = mean[i%channels], scale[i%channels]
+	}
+	normalizeAVX2(&dst[0], &src[0], len(src), &m, &s)
+}
+
+func blendSIMD(dst []byte, a []byte, b []byte, w int) {
+	blendAVX2(&dst[0], &a[0], &b[0], len(dst), w)
+}
@@ -0,0 +1,97 @@
+//go:build !purego
+// +build !purego
+
+#include "textflag.h"
+
+// func normalizeAVX2(dst *float32, src *byte, n int, mean *[24]float32, scale *[24]float32)
+TEXT ·normalizeAVX2(SB), NOSPLIT, $0-40
+	MOVQ dst+0(FP), DI
+	MOVQ src+8(FP), SI
+	MOVQ n+16(FP), CX
+	MOVQ mean+24(FP), AX
+	MOVQ scale+32(FP), BX
+
+	VMOVUPS 0(AX), Y0
+	VMOVUPS 32(AX), Y1
+	VMOVUPS 64(AX), Y2
+	VMOVUPS 0(BX), Y3
+	VMOVUPS 32(BX), Y4
+	VMOVUPS 64(BX), Y5
+
+loop:
+	CMPQ CX, $24
+	JL   done
+
+	// 24 bytes to 24 floats
+	VPMOVZXBD 0(SI), Y6
+	VPMOVZXBD 8(SI), Y7
+	VPMOVZXBD 16(SI), Y8
+	VCVTDQ2PS Y6, Y6
+	VCVTDQ2PS Y7, Y7
+	VCVTDQ2PS Y8, Y8
+
+	// (value - mean) * scale
+	VSUBPS Y0, Y6, Y6
+	VSUBPS Y1, Y7, Y7
+	VSUBPS Y2, Y8, Y8
+	VMULPS Y3, Y6, Y6
+	VMULPS Y4, Y7, Y7
+	VMULPS Y5, Y8, Y8
+
+	VMOVUPS Y6, 0(DI)
+	VMOVUPS Y7, 32(DI)
+	VMOVUPS Y8, 64(DI)
+
+	ADDQ $24, SI
+	ADDQ $96, DI
+	SUBQ $24, CX
+	JMP  loop
+
+done:
+	VZEROUPPER
+	RET
+
+// func blendAVX2(dst *byte, a *byte, b *byte, n int, w int)
+TEXT ·blendAVX2(SB), NOSPLIT, $0-40
+	MOVQ dst+0(FP), DI
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DX
+	MOVQ n+24(FP), CX
+	MOVQ w+32(FP), AX
+
+	// The weights of b and a and the rounding in every word
+	MOVQ         $256, BX
+	SUBQ         AX, BX
+	MOVQ         AX, X0
+	VPBROADCASTW X0, Y0
+	MOVQ         BX, X1
+	VPBROADCASTW X1, Y1
+	MOVQ         $128, BX
+	MOVQ         BX, X2
+	VPBROADCASTW X2, Y2
+
+loop:
+	CMPQ CX, $16
+	JL   done
+
+	// (a * (256 - w) + b * w + 128) >> 8
+	VPMOVZXBW    0(SI), Y3
+	VPMOVZXBW    0(DX), Y4
+	VPMULLW      Y1, Y3, Y3
+	VPMULLW      Y0, Y4, Y4
+	VPADDW       Y4, Y3, Y3
+	VPADDW       Y2, Y3, Y3
+	VPSRLW       $8, Y3, Y3
+	VEXTRACTI128 $1, Y3, X4
+	VPACKUSWB    X4, X3, X3
+	VMOVDQU      X3, 0(DI)
+
+	ADDQ $16, SI
+	ADDQ $16, DX
+	ADDQ $16, DI
+	SUBQ $16, CX
+	JMP  loop
+
+done:
+	VZEROUPPER
+	RET
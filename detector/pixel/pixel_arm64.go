@@ -0,0 +1,24 @@
+//go:build !purego
+// +build !purego
+
+package pixel
+
+import "golang.org/x/sys/cpu"
+
+const simdName = "neon"
+
+var hasSIMD = cpu.ARM64.HasASIMD
+
+//go:noescape
+func normalizeNEON(dst *float32, src *byte, n int, mean *[3]float32, scale *[3]float32)
+
+//go:noescape
+func blendNEON(dst *byte, a *byte, b *byte, n int, w int)
+
+func normalizeSIMD(dst []float32, src []byte, mean, scale [3]float32) {
+	normalizeNEON(&dst[0], &src[0], len(src), &mean, &scale)
+}
+
+func blendSIMD(dst []byte, a []byte, b []byte, w int) {
+	blendNEON(&dst[0], &a[0], &b[0], len(dst), w)
+}
@@ -0,0 +1,108 @@
+//go:build !purego
+// +build !purego
+
+#include "textflag.h"
+
+// The vector instructions are encoded by hand for older assemblers, the instruction is in the comment.
+
+// func normalizeNEON(dst *float32, src *byte, n int, mean *[3]float32, scale *[3]float32)
+TEXT ·normalizeNEON(SB), NOSPLIT, $0-40
+	MOVD dst+0(FP), R0
+	MOVD src+8(FP), R1
+	MOVD n+16(FP), R2
+	MOVD mean+24(FP), R3
+	MOVD scale+32(FP), R4
+
+	// The mean and scale of each channel in every lane
+	WORD $0x4ddfc860 // ld1r {v0.4s}, [x3], #4
+	WORD $0x4ddfc861 // ld1r {v1.4s}, [x3], #4
+	WORD $0x4d40c862 // ld1r {v2.4s}, [x3]
+	WORD $0x4ddfc883 // ld1r {v3.4s}, [x4], #4
+	WORD $0x4ddfc884 // ld1r {v4.4s}, [x4], #4
+	WORD $0x4d40c885 // ld1r {v5.4s}, [x4]
+
+loop:
+	CMP $24, R2
+	BLT done
+
+	// 8 pixels split into the channels
+	WORD $0x0cdf4030 // ld3 {v16.8b, v17.8b, v18.8b}, [x1], #24
+
+	// Widen to 32 bits, v20-v22 are the first 4 pixels and v23-v25 the last
+	WORD $0x2f08a61a // uxtl v26.8h, v16.8b
+	WORD $0x2f10a754 // uxtl v20.4s, v26.4h
+	WORD $0x6f10a757 // uxtl2 v23.4s, v26.8h
+	WORD $0x2f08a63a // uxtl v26.8h, v17.8b
+	WORD $0x2f10a755 // uxtl v21.4s, v26.4h
+	WORD $0x6f10a758 // uxtl2 v24.4s, v26.8h
+	WORD $0x2f08a65a // uxtl v26.8h, v18.8b
+	WORD $0x2f10a756 // uxtl v22.4s, v26.4h
+	WORD $0x6f10a759 // uxtl2 v25.4s, v26.8h
+
+	WORD $0x6e21da94 // ucvtf v20.4s, v20.4s
+	WORD $0x6e21dab5 // ucvtf v21.4s, v21.4s
+	WORD $0x6e21dad6 // ucvtf v22.4s, v22.4s
+	WORD $0x6e21daf7 // ucvtf v23.4s, v23.4s
+	WORD $0x6e21db18 // ucvtf v24.4s, v24.4s
+	WORD $0x6e21db39 // ucvtf v25.4s, v25.4s
+
+	// (value - mean) * scale
+	WORD $0x4ea0d694 // fsub v20.4s, v20.4s, v0.4s
+	WORD $0x4ea1d6b5 // fsub v21.4s, v21.4s, v1.4s
+	WORD $0x4ea2d6d6 // fsub v22.4s, v22.4s, v2.4s
+	WORD $0x4ea0d6f7 // fsub v23.4s, v23.4s, v0.4s
+	WORD $0x4ea1d718 // fsub v24.4s, v24.4s, v1.4s
+	WORD $0x4ea2d739 // fsub v25.4s, v25.4s, v2.4s
+	WORD $0x6e23de94 // fmul v20.4s, v20.4s, v3.4s
+	WORD $0x6e24deb5 // fmul v21.4s, v21.4s, v4.4s
+	WORD $0x6e25ded6 // fmul v22.4s, v22.4s, v5.4s
+	WORD $0x6e23def7 // fmul v23.4s, v23.4s, v3.4s
+	WORD $0x6e24df18 // fmul v24.4s, v24.4s, v4.4s
+	WORD $0x6e25df39 // fmul v25.4s, v25.4s, v5.4s
+
+	// Interleave the channels again
+	WORD $0x4c9f4814 // st3 {v20.4s, v21.4s, v22.4s}, [x0], #48
+	WORD $0x4c9f4817 // st3 {v23.4s, v24.4s, v25.4s}, [x0], #48
+
+	SUB $24, R2
+	B   loop
+
+done:
+	RET
+
+// func blendNEON(dst *byte, a *byte, b *byte, n int, w int)
+TEXT ·blendNEON(SB), NOSPLIT, $0-40
+	MOVD dst+0(FP), R0
+	MOVD a+8(FP), R1
+	MOVD b+16(FP), R2
+	MOVD n+24(FP), R3
+	MOVD w+32(FP), R4
+
+	// The weights of b and a in every byte, w is 1-255
+	MOVD $256, R5
+	SUB  R4, R5
+	WORD $0x4e010c80 // dup v0.16b, w4
+	WORD $0x4e010ca1 // dup v1.16b, w5
+
+loop:
+	CMP $16, R3
+	BLT done
+
+	WORD $0x4cdf7030 // ld1 {v16.16b}, [x1], #16
+	WORD $0x4cdf7051 // ld1 {v17.16b}, [x2], #16
+
+	// (a * (256 - w) + b * w + 128) >> 8
+	WORD $0x2e21c212 // umull v18.8h, v16.8b, v1.8b
+	WORD $0x6e21c213 // umull2 v19.8h, v16.16b, v1.16b
+	WORD $0x2e208232 // umlal v18.8h, v17.8b, v0.8b
+	WORD $0x6e208233 // umlal2 v19.8h, v17.16b, v0.16b
+	WORD $0x0f088e54 // rshrn v20.8b, v18.8h, #8
+	WORD $0x4f088e74 // rshrn2 v20.16b, v19.8h, #8
+
+	WORD $0x4c9f7014 // st1 {v20.16b}, [x0], #16
+
+	SUB $16, R3
+	B   loop
+
+done:
+	RET
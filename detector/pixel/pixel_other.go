@@ -0,0 +1,18 @@
+//go:build (!amd64 && !arm64) || purego
+// +build !amd64,!arm64 purego
+
+package pixel
+
+const simdName = ""
+
+var hasSIMD = false
+
+func normalizeSIMD(dst []float32, src []byte, mean, scale [3]float32) {
+	normalizeGeneric(dst, src, mean, scale)
+}
+
+func blendSIMD(dst []byte, a []byte, b []byte, w int) {
+	for i := range dst {
+		dst[i] = byte((int(a[i])*(256-w) + int(b[i])*w + 128) >> 8)
+	}
+}
@@ -0,0 +1,102 @@
+package pixel
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// The lengths are around the vector widths so the SIMD loops and the generic tails are both used
+var testLengths = []int{0, 1, 2, 3, 15, 16, 17, 23, 24, 25, 31, 32, 33, 47, 48, 49, 63, 64, 65, 96, 99, 1000, 1001, 4099}
+
+func randomBytes(r *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	r.Read(b)
+	return b
+}
+
+func TestNormalize(t *testing.T) {
+
+	if !hasSIMD {
+		t.Skip("no SIMD kernels")
+	}
+	defer SetEnabled(true)
+
+	r := rand.New(rand.NewSource(1))
+	mean, scale := [3]float32{127.5, 120, 100}, [3]float32{1 / 127.5, 0.01, 2}
+	for _, n := range testLengths {
+		n -= n % channels
+		src := randomBytes(r, n)
+		simd, generic := make([]float32, n), make([]float32, n)
+		SetEnabled(true)
+		Normalize(simd, src, mean, scale)
+		SetEnabled(false)
+		Normalize(generic, src, mean, scale)
+		for i := range simd {
+			if simd[i] != generic[i] {
+				t.Fatalf("length %d value %d is %v, expected %v", n, i, simd[i], generic[i])
+			}
+		}
+	}
+
+}
+
+func TestBlend(t *testing.T) {
+
+	if !hasSIMD {
+		t.Skip("no SIMD kernels")
+	}
+	defer SetEnabled(true)
+
+	r := rand.New(rand.NewSource(2))
+	for _, n := range testLengths {
+		a, b := randomBytes(r, n), randomBytes(r, n)
+		for _, w := range []int{0, 1, 64, 127, 128, 200, 255, 256} {
+			simd, generic := make([]byte, n), make([]byte, n)
+			SetEnabled(true)
+			blend(simd, a, b, w)
+			SetEnabled(false)
+			blend(generic, a, b, w)
+			for i := range simd {
+				if simd[i] != generic[i] {
+					t.Fatalf("length %d weight %d byte %d is %d, expected %d", n, w, i, simd[i], generic[i])
+				}
+			}
+		}
+	}
+
+}
+
+func TestResize(t *testing.T) {
+
+	defer SetEnabled(true)
+
+	r := rand.New(rand.NewSource(3))
+	for _, size := range [][4]int{{7, 5, 3, 2}, {3, 2, 7, 5}, {64, 48, 21, 17}, {13, 9, 33, 31}, {300, 200, 300, 200}} {
+		sw, sh, dw, dh := size[0], size[1], size[2], size[3]
+		src := randomBytes(r, sw*sh*channels)
+		simd, generic := make([]byte, dw*dh*channels), make([]byte, dw*dh*channels)
+		SetEnabled(true)
+		Resize(simd, dw, dh, src, sw, sh)
+		SetEnabled(false)
+		Resize(generic, dw, dh, src, sw, sh)
+		for i := range simd {
+			if simd[i] != generic[i] {
+				t.Fatalf("%dx%d to %dx%d byte %d is %d, expected %d", sw, sh, dw, dh, i, simd[i], generic[i])
+			}
+		}
+	}
+
+	// A solid image stays solid
+	src := make([]byte, 5*4*channels)
+	for i := range src {
+		src[i] = byte(10 * (i%channels + 1))
+	}
+	dst := make([]byte, 9*7*channels)
+	Resize(dst, 9, 7, src, 5, 4)
+	for i, v := range dst {
+		if v != byte(10*(i%channels+1)) {
+			t.Fatalf("byte %d is %d, expected %d", i, v, 10*(i%channels+1))
+		}
+	}
+
+}
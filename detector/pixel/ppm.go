@@ -1,11 +1,12 @@
-package tflite
+package pixel
 
 import (
 	"strconv"
 )
 
+// PPMInfo is the size of a binary (P6) PPM image and the offset of its RGB pixels
 type PPMInfo struct {
-	Width int
+	Width  int
 	Height int
 	Offset int
 }
@@ -24,6 +25,7 @@ func isSpace(b byte) bool {
 	return false
 }
 
+// FindPPMData returns the info of PPM image data or nil if it's not a PPM. RGB data in a PPM can skip decoding.
 func FindPPMData(data []byte) *PPMInfo {
 
 	i := new(PPMInfo)
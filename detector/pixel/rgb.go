@@ -0,0 +1,37 @@
+package pixel
+
+import (
+	"image"
+	"image/color"
+)
+
+// RGB returns the pixels of the image as interleaved RGB bytes
+func RGB(img image.Image) []byte {
+	b := img.Bounds()
+	ret := make([]byte, 0, b.Dx()*b.Dy()*3)
+	switch src := img.(type) {
+	case *image.RGBA:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			row := src.Pix[src.PixOffset(b.Min.X, y):][:b.Dx()*4]
+			for x := 0; x < len(row); x += 4 {
+				ret = append(ret, row[x], row[x+1], row[x+2])
+			}
+		}
+	case *image.YCbCr:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				yi, ci := src.YOffset(x, y), src.COffset(x, y)
+				r, g, bl := color.YCbCrToRGB(src.Y[yi], src.Cb[ci], src.Cr[ci])
+				ret = append(ret, r, g, bl)
+			}
+		}
+	default:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				r, g, bl, _ := img.At(x, y).RGBA()
+				ret = append(ret, byte(r>>8), byte(g>>8), byte(bl>>8))
+			}
+		}
+	}
+	return ret
+}
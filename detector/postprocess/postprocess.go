@@ -0,0 +1,51 @@
+// Package postprocess loads plugins that convert the raw outputs of a model to detections
+package postprocess
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// Func converts the raw output tensors of a model to detections with coordinates from 0-1 and confidence from 0-100.
+// Labels are the detector labels by class index.
+type Func func(outputs []*odrpc.OutputTensor, labels map[int]string) ([]*odrpc.Detection, error)
+
+// NewFunc is the signature of the New symbol a plugin must export
+type NewFunc = func(options map[string]string) (Func, error)
+
+// Load opens a Go plugin (built with go build -buildmode=plugin against the same doods source) and calls its New function with the options
+func Load(filename string, options map[string]string) (Func, error) {
+
+	p, err := plugin.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not open plugin %s: %v", filename, err)
+	}
+
+	symbol, err := p.Lookup("New")
+	if err != nil {
+		return nil, fmt.Errorf("could not find New in plugin %s: %v", filename, err)
+	}
+
+	newFunc, ok := symbol.(NewFunc)
+	if !ok {
+		// Exported functions are looked up as a pointer when declared as a variable
+		if ptr, isPtr := symbol.(*NewFunc); isPtr {
+			newFunc, ok = *ptr, true
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("plugin %s New has type %T, expected %T", filename, symbol, NewFunc(nil))
+	}
+
+	f, err := newFunc(options)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize plugin %s: %v", filename, err)
+	}
+	if f == nil {
+		return nil, fmt.Errorf("plugin %s returned no post-processor", filename)
+	}
+	return f, nil
+
+}
@@ -0,0 +1,221 @@
+package detector
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"math"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/detector/enhance"
+	"github.com/snowzach/doods/detector/orient"
+	"github.com/snowzach/doods/detector/pixel"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// Preprocess returns the image resized to the input of a detector. A PPM or RGB result can be sent to a detect request
+// on another node to skip decoding and resizing, the tensor format is the exact bytes of the detector input.
+func (m *Mux) Preprocess(ctx context.Context, request *odrpc.PreprocessRequest) (*odrpc.PreprocessResponse, error) {
+
+	if request.DetectorName == "" {
+		request.DetectorName = "default"
+	}
+	detector, ok := m.detectors[request.DetectorName]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "not found")
+	}
+	cfg := detector.Config()
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "detector %s has no fixed input size", request.DetectorName)
+	}
+	if cfg.Channels != 0 && cfg.Channels != 3 {
+		return nil, status.Errorf(codes.FailedPrecondition, "detector %s has %d input channels, only 3 are supported", request.DetectorName, cfg.Channels)
+	}
+	switch request.Format {
+	case "":
+		request.Format = "ppm"
+	case "ppm", "rgb":
+	case "tensor":
+		if cfg.Input == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "detector %s has no input tensor spec", request.DetectorName)
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown format %s", request.Format)
+	}
+
+	var err error
+	if len(request.File) != 0 {
+		request.Data, err = ioutil.ReadFile(request.File)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "could not open file %s", request.File)
+		}
+	}
+	if request.ImageUrl != "" {
+		request.Data, err = m.fetcher.fetch(ctx, request.ImageUrl)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+	if err = m.limits.check(request.Data); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	// The same steps as a detect request before the detector gets the image
+	if enhance.Enabled(request.Preprocess) {
+		request.Data, err = enhance.Apply(request.Data, request.Preprocess)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "could not preprocess image: %v", err)
+		}
+	}
+	if detector.mask != nil && detector.maskInput {
+		request.Data, err = detector.mask.Apply(request.Data)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "could not apply mask: %v", err)
+		}
+	}
+	if err = orient.Validate(request.Rotate, request.Flip); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	request.Data, err = orient.Apply(request.Data, request.Rotate, request.Flip)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not rotate image: %v", err)
+	}
+
+	src, sw, sh, err := decodeRGB(request.Data)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not decode image: %v", err)
+	}
+
+	w, h := int(cfg.Width), int(cfg.Height)
+	data, content := resizeInput(src, sw, sh, w, h, request.Letterbox)
+
+	response := &odrpc.PreprocessResponse{
+		Id:       request.Id,
+		Format:   request.Format,
+		Width:    int32(w),
+		Height:   int32(h),
+		Channels: 3,
+		Content:  content,
+	}
+
+	switch request.Format {
+	case "ppm":
+		header := fmt.Sprintf("P6\n%d %d\n255\n", w, h)
+		response.Data = append([]byte(header), data...)
+	case "rgb":
+		response.Data = data
+	case "tensor":
+		response.Input = cfg.Input
+		response.Data, err = inputTensor(data, w, h, cfg.Input)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "detector %s: %v", request.DetectorName, err)
+		}
+		if cfg.Input.Layout == "NCHW" {
+			response.Shape = []int32{1, 3, int32(h), int32(w)}
+		} else {
+			response.Shape = []int32{1, int32(h), int32(w), 3}
+		}
+	}
+
+	m.logger.Debugw("Preprocessed image", "id", request.Id, "detector", request.DetectorName, "format", request.Format, "bytes", len(response.Data))
+
+	return response, nil
+
+}
+
+// decodeRGB returns the image as RGB bytes and its size, PPM images skip decoding
+func decodeRGB(data []byte) ([]byte, int, int, error) {
+	if ppm := pixel.FindPPMData(data); ppm != nil {
+		if ppm.Width <= 0 || ppm.Height <= 0 || len(data)-ppm.Offset < ppm.Width*ppm.Height*3 {
+			return nil, 0, 0, fmt.Errorf("short ppm image")
+		}
+		return data[ppm.Offset : ppm.Offset+ppm.Width*ppm.Height*3], ppm.Width, ppm.Height, nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	b := img.Bounds()
+	if b.Empty() {
+		return nil, 0, 0, fmt.Errorf("empty image")
+	}
+	return pixel.RGB(img), b.Dx(), b.Dy(), nil
+}
+
+// resizeInput resizes the RGB bytes to w x h. Letterboxing keeps the aspect ratio and centers the image on black.
+// It returns the area with the image in relative coordinates.
+func resizeInput(src []byte, sw, sh int, w, h int, letterbox bool) ([]byte, *odrpc.Box) {
+
+	dst := make([]byte, w*h*3)
+	if !letterbox {
+		pixel.Resize(dst, w, h, src, sw, sh)
+		return dst, &odrpc.Box{Top: 0, Left: 0, Bottom: 1, Right: 1}
+	}
+
+	scale := math.Min(float64(w)/float64(sw), float64(h)/float64(sh))
+	iw, ih := int(math.Round(float64(sw)*scale)), int(math.Round(float64(sh)*scale))
+	if iw < 1 {
+		iw = 1
+	}
+	if ih < 1 {
+		ih = 1
+	}
+	scaled := make([]byte, iw*ih*3)
+	pixel.Resize(scaled, iw, ih, src, sw, sh)
+
+	x0, y0 := (w-iw)/2, (h-ih)/2
+	for y := 0; y < ih; y++ {
+		copy(dst[((y0+y)*w+x0)*3:], scaled[y*iw*3:(y+1)*iw*3])
+	}
+
+	return dst, &odrpc.Box{
+		Top:    float32(y0) / float32(h),
+		Left:   float32(x0) / float32(w),
+		Bottom: float32(y0+ih) / float32(h),
+		Right:  float32(x0+iw) / float32(w),
+	}
+
+}
+
+// inputTensor converts the RGB bytes to the input tensor of the spec, floats are little endian
+func inputTensor(data []byte, w, h int, spec *odrpc.InputSpec) ([]byte, error) {
+
+	nchw := spec.Layout == "NCHW"
+	switch spec.Type {
+	case "UInt8":
+		if !nchw {
+			return data, nil
+		}
+		ret := make([]byte, len(data))
+		for i := 0; i < w*h; i++ {
+			for c := 0; c < 3; c++ {
+				ret[c*w*h+i] = data[i*3+c]
+			}
+		}
+		return ret, nil
+
+	case "Float32":
+		mean, scale := [3]float32{0, 0, 0}, [3]float32{1, 1, 1}
+		copy(mean[:], spec.Mean)
+		copy(scale[:], spec.Scale)
+		values := make([]float32, len(data))
+		pixel.Normalize(values, data, mean, scale)
+		ret := make([]byte, len(values)*4)
+		for i, v := range values {
+			j := i
+			if nchw {
+				j = (i%3)*w*h + i/3
+			}
+			binary.LittleEndian.PutUint32(ret[j*4:], math.Float32bits(v))
+		}
+		return ret, nil
+	}
+
+	return nil, fmt.Errorf("unsupported input type %s", spec.Type)
+
+}
@@ -0,0 +1,55 @@
+package detector
+
+import (
+	"fmt"
+
+	"github.com/snowzach/doods/detector/dconfig"
+)
+
+// The animals of the COCO labels for the wildlife preset
+var wildlifeLabels = []string{"bird", "cat", "dog", "horse", "sheep", "cow", "elephant", "bear", "zebra", "giraffe"}
+
+// applyPreset adds the options of the preset to the detector config. Options that are already set are kept so a
+// preset can be tuned.
+func applyPreset(c *dconfig.DetectorConfig) error {
+
+	p := c.Preset
+	if p == nil || p.Name == "" {
+		return nil
+	}
+
+	switch p.Name {
+	case "birds", "wildlife":
+		// Detect the animal, then classify the species of the crop with a fine grained classifier
+		if p.Detector == "" {
+			return fmt.Errorf("preset %s: the species classifier detector is required", p.Name)
+		}
+		labels := p.Labels
+		if len(labels) == 0 {
+			labels = []string{"bird"}
+			if p.Name == "wildlife" {
+				labels = wildlifeLabels
+			}
+		}
+		for _, a := range c.Attributes {
+			if a.Name == "species" {
+				return nil
+			}
+		}
+		c.Attributes = append(c.Attributes, &dconfig.AttributeConfig{
+			Name:          "species",
+			Detector:      p.Detector,
+			Labels:        labels,
+			MinConfidence: 40,
+			// Feeder cameras crop birds tight, the classifiers want some background
+			Padding: 0.2,
+			// Fine grained classifiers spread the score over similar species
+			Threshold: 20,
+		})
+	default:
+		return fmt.Errorf("unknown preset %s", p.Name)
+	}
+
+	return nil
+
+}
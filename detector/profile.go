@@ -0,0 +1,136 @@
+package detector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/mask"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// profile changes the detection options during a time window
+type profile struct {
+	name   string
+	start  time.Duration // Since midnight
+	end    time.Duration
+	days   map[time.Weekday]bool
+	detect map[string]float32
+	ignore []string
+	mask   *mask.Mask
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// newProfile parses a profile config
+func newProfile(c *dconfig.ProfileConfig) (*profile, error) {
+
+	p := &profile{
+		name:   c.Name,
+		detect: c.Detect,
+		ignore: c.Ignore,
+	}
+
+	var err error
+	if p.start, err = conf.ParseTimeOfDay(c.Start); err != nil {
+		return nil, fmt.Errorf("invalid start: %v", err)
+	}
+	if p.end, err = conf.ParseTimeOfDay(c.End); err != nil {
+		return nil, fmt.Errorf("invalid end: %v", err)
+	}
+
+	if len(c.Days) > 0 {
+		p.days = make(map[time.Weekday]bool)
+		for _, day := range c.Days {
+			weekday, ok := weekdays[strings.ToLower(day)[:min(3, len(day))]]
+			if !ok {
+				return nil, fmt.Errorf("invalid day: %s", day)
+			}
+			p.days[weekday] = true
+		}
+	}
+
+	if c.Mask != "" {
+		if p.mask, err = mask.Load(c.Mask); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// active returns if the profile is active at time t. Windows can wrap past midnight.
+func (p *profile) active(t time.Time) bool {
+
+	tod := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	day := t.Weekday()
+
+	var inWindow bool
+	if p.start <= p.end {
+		inWindow = tod >= p.start && tod < p.end
+	} else {
+		// Wraps past midnight, the early part belongs to the previous day
+		if tod >= p.start {
+			inWindow = true
+		} else if tod < p.end {
+			inWindow = true
+			day = (day + 6) % 7
+		}
+	}
+	if !inWindow {
+		return false
+	}
+
+	return p.days == nil || p.days[day]
+
+}
+
+// apply merges the profile options into the request. The detect map and ignore list are copied first, callers like
+// streams and jobs share theirs between requests.
+func (p *profile) apply(request *odrpc.DetectRequest) {
+
+	if len(p.detect) > 0 {
+		detect := make(map[string]float32, len(request.Detect)+len(p.detect))
+		for label, score := range request.Detect {
+			detect[label] = score
+		}
+		for label, score := range p.detect {
+			detect[label] = score
+		}
+		request.Detect = detect
+	}
+
+	if len(p.ignore) > 0 {
+		ignore := make([]string, 0, len(request.Ignore)+len(p.ignore))
+		request.Ignore = append(append(ignore, request.Ignore...), p.ignore...)
+	}
+
+}
+
+// activeProfile returns the first profile active at time t or nil if none are
+func (d *muxDetector) activeProfile(t time.Time) *profile {
+	for _, p := range d.profiles {
+		if p.active(t) {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package detector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+func TestProfileActive(t *testing.T) {
+
+	p, err := newProfile(&dconfig.ProfileConfig{Name: "night", Start: "22:00", End: "06:00", Days: []string{"Friday"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Friday 2020-11-20
+	for _, test := range []struct {
+		time   string
+		active bool
+	}{
+		{"2020-11-20T21:59:00Z", false},
+		{"2020-11-20T22:00:00Z", true},
+		{"2020-11-21T05:59:00Z", true}, // Saturday morning is still Friday night
+		{"2020-11-21T06:00:00Z", false},
+		{"2020-11-20T05:00:00Z", false}, // Thursday night
+	} {
+		tm, _ := time.Parse(time.RFC3339, test.time)
+		if active := p.active(tm); active != test.active {
+			t.Errorf("%s: active %v, expected %v", test.time, active, test.active)
+		}
+	}
+
+	if _, err := newProfile(&dconfig.ProfileConfig{Start: "22:00", End: "6am"}); err == nil {
+		t.Error("expected an error for an invalid end")
+	}
+	if _, err := newProfile(&dconfig.ProfileConfig{Start: "22:00", End: "06:00", Days: []string{"someday"}}); err == nil {
+		t.Error("expected an error for an invalid day")
+	}
+
+}
+
+func TestProfileApplyCopies(t *testing.T) {
+
+	p, err := newProfile(&dconfig.ProfileConfig{Start: "00:00", End: "00:00", Detect: map[string]float32{"person": 70}, Ignore: []string{"cat"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stream config shared by every request
+	detect := map[string]float32{"person": 50, "car": 60}
+	ignore := make([]string, 1, 4)
+	ignore[0] = "dog"
+
+	request := &odrpc.DetectRequest{Detect: detect, Ignore: ignore}
+	p.apply(request)
+
+	if request.Detect["person"] != 70 || request.Detect["car"] != 60 {
+		t.Errorf("unexpected detect %v", request.Detect)
+	}
+	if len(request.Ignore) != 2 || request.Ignore[0] != "dog" || request.Ignore[1] != "cat" {
+		t.Errorf("unexpected ignore %v", request.Ignore)
+	}
+	if detect["person"] != 50 {
+		t.Error("apply changed the shared detect map")
+	}
+	if len(ignore) != 1 || ignore[:2][1] != "" {
+		t.Error("apply changed the shared ignore list")
+	}
+
+}
@@ -0,0 +1,127 @@
+package detector
+
+import (
+	"bytes"
+	"image"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The size of the grayscale copy used for the checks and the level of an overexposed pixel
+const (
+	qualityWidth      = 256
+	overexposedLevel  = 250
+	qualityActionSkip = "skip"
+	qualityActionFlag = "flag"
+)
+
+// quality checks if an image is too dark, bright or blurry to be worth detecting
+type quality struct {
+	minBrightness  float32
+	maxBrightness  float32
+	maxOverexposed float32
+	minSharpness   float32
+	skip           bool
+}
+
+func newQuality(c *dconfig.QualityConfig) *quality {
+	return &quality{
+		minBrightness:  c.MinBrightness,
+		maxBrightness:  c.MaxBrightness,
+		maxOverexposed: c.MaxOverexposed,
+		minSharpness:   c.MinSharpness,
+		skip:           c.Action != qualityActionFlag,
+	}
+}
+
+// check returns the quality report for the image, nil if it can't be decoded
+func (q *quality) check(data []byte) *odrpc.Quality {
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	gray, w, h := grayscale(img)
+	if w == 0 || h == 0 {
+		return nil
+	}
+
+	report := new(odrpc.Quality)
+
+	var total float64
+	var over int
+	for _, v := range gray {
+		total += float64(v)
+		if v >= overexposedLevel {
+			over++
+		}
+	}
+	report.Brightness = float32(total / float64(len(gray)))
+	report.Overexposed = float32(over) / float32(len(gray)) * 100
+	report.Sharpness = laplacianVariance(gray, w, h)
+
+	if q.minBrightness > 0 && report.Brightness < q.minBrightness {
+		report.Problems = append(report.Problems, "dark")
+	}
+	if q.maxBrightness > 0 && report.Brightness > q.maxBrightness {
+		report.Problems = append(report.Problems, "bright")
+	}
+	if q.maxOverexposed > 0 && report.Overexposed > q.maxOverexposed {
+		report.Problems = append(report.Problems, "overexposed")
+	}
+	if q.minSharpness > 0 && report.Sharpness < q.minSharpness {
+		report.Problems = append(report.Problems, "blurry")
+	}
+	report.Skipped = q.skip && len(report.Problems) > 0
+
+	return report
+
+}
+
+// grayscale returns the luma (0-255) of the image scaled down to about qualityWidth pixels wide
+func grayscale(img image.Image) ([]uint8, int, int) {
+
+	b := img.Bounds()
+	step := b.Dx()/qualityWidth + 1
+	w, h := (b.Dx()+step-1)/step, (b.Dy()+step-1)/step
+	gray := make([]uint8, 0, w*h)
+
+	// JPEGs have the luma already
+	if ycc, ok := img.(*image.YCbCr); ok {
+		for y := b.Min.Y; y < b.Max.Y; y += step {
+			for x := b.Min.X; x < b.Max.X; x += step {
+				gray = append(gray, ycc.Y[ycc.YOffset(x, y)])
+			}
+		}
+		return gray, w, h
+	}
+
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			r, g, b, _ := img.At(x, y).RGBA()
+			gray = append(gray, uint8((0.299*float64(r)+0.587*float64(g)+0.114*float64(b))/257))
+		}
+	}
+	return gray, w, h
+
+}
+
+// laplacianVariance returns the variance of the laplacian, a measure of the edges in the image
+func laplacianVariance(gray []uint8, w, h int) float32 {
+	if w < 3 || h < 3 {
+		return 0
+	}
+	var sum, sumSq float64
+	for y := 1; y < h-1; y++ {
+		for x := 1; x < w-1; x++ {
+			i := y*w + x
+			v := float64(gray[i-w]) + float64(gray[i+w]) + float64(gray[i-1]) + float64(gray[i+1]) - 4*float64(gray[i])
+			sum += v
+			sumSq += v * v
+		}
+	}
+	n := float64((w - 2) * (h - 2))
+	mean := sum / n
+	return float32(sumSq/n - mean*mean)
+}
@@ -3,9 +3,54 @@ package detector
 import (
 	"fmt"
 
+	"github.com/snowzach/doods/detector/mask"
 	"github.com/snowzach/doods/odrpc"
 )
 
+// IgnoreResponse removes any detections with labels in the detector or request ignore lists
+func (d *muxDetector) IgnoreResponse(request *odrpc.DetectRequest, response *odrpc.DetectResponse) {
+
+	if len(d.ignore) == 0 && len(request.Ignore) == 0 {
+		return
+	}
+
+	temp := response.Detections[:0]
+
+detectionsLoop:
+	for _, detection := range response.Detections {
+		if _, ok := d.ignore[detection.Label]; ok {
+			continue
+		}
+		for _, label := range request.Ignore {
+			if label == detection.Label {
+				continue detectionsLoop
+			}
+		}
+		temp = append(temp, detection)
+	}
+
+	response.Detections = temp
+
+}
+
+// MaskResponse removes any detections with a center in the masked area
+func MaskResponse(m *mask.Mask, response *odrpc.DetectResponse) {
+
+	if m == nil {
+		return
+	}
+
+	temp := response.Detections[:0]
+	for _, detection := range response.Detections {
+		if m.Masked((detection.Left+detection.Right)/2, (detection.Top+detection.Bottom)/2) {
+			continue
+		}
+		temp = append(temp, detection)
+	}
+	response.Detections = temp
+
+}
+
 func (m *Mux) FilterResponse(request *odrpc.DetectRequest, response *odrpc.DetectResponse) {
 
 	// No filters, return everything
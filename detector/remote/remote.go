@@ -0,0 +1,229 @@
+// Package remote routes detections to other doods instances. Each detector name is placed on the backends with a
+// consistent hash so a detector always runs on the same backend and adding or removing a backend only moves the
+// detectors on it.
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/client"
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The points on the hash ring for each backend, more spread the detectors more evenly
+const ringReplicas = 64
+
+// Router has the backends and the detectors they serve
+type Router struct {
+	backends []*backend
+	ring     []ringPoint
+	logger   *zap.SugaredLogger
+}
+
+type backend struct {
+	address   string
+	client    *client.Client
+	detectors map[string]*odrpc.Detector
+}
+
+type ringPoint struct {
+	hash    uint32
+	backend *backend
+}
+
+// New connects to the backends and gets their detectors, waiting up to timeout for them to answer. A backend that
+// doesn't answer has no detectors until doods restarts.
+func New(lc *conf.Lifecycle, configs []*dconfig.BackendConfig, timeout time.Duration) *Router {
+
+	r := &Router{
+		logger: zap.S().With("package", "detector.remote"),
+	}
+
+	ctx, cancel := context.WithTimeout(lc.Context(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, c := range configs {
+		b := &backend{
+			address:   c.Address,
+			detectors: make(map[string]*odrpc.Detector),
+		}
+		var err error
+		b.client, err = client.New(lc.Context(), c.Address, &client.Options{
+			AuthKey:            c.AuthKey,
+			TLS:                c.TLS,
+			InsecureSkipVerify: c.InsecureSkipVerify,
+		})
+		if err != nil {
+			r.logger.Errorw("Could not connect to backend", "address", c.Address, "error", err)
+			continue
+		}
+		r.backends = append(r.backends, b)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.discover(ctx, r.logger)
+		}()
+	}
+	wg.Wait()
+
+	// The ring is built from all of the backends so a backend that's down doesn't move the others' detectors
+	for _, b := range r.backends {
+		for i := 0; i < ringReplicas; i++ {
+			r.ring = append(r.ring, ringPoint{hash: hash(b.address + "#" + strconv.Itoa(i)), backend: b})
+		}
+	}
+	sort.Slice(r.ring, func(i, j int) bool { return r.ring[i].hash < r.ring[j].hash })
+
+	go func() {
+		<-lc.Done()
+		for _, b := range r.backends {
+			b.client.Close()
+		}
+	}()
+
+	return r
+
+}
+
+// discover gets the detectors of the backend, retrying until the context is done
+func (b *backend) discover(ctx context.Context, logger *zap.SugaredLogger) {
+	for {
+		detectors, err := b.client.Detectors(ctx)
+		if err == nil {
+			for _, d := range detectors {
+				b.detectors[d.Name] = d
+			}
+			logger.Infow("Backend detectors", "address", b.address, "detectors", len(detectors))
+			return
+		}
+		select {
+		case <-ctx.Done():
+			logger.Warnw("Could not get backend detectors", "address", b.address, "error", err)
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Detectors returns the sorted names of the detectors on any backend
+func (r *Router) Detectors() []string {
+	names := make(map[string]struct{})
+	for _, b := range r.backends {
+		for name := range b.detectors {
+			names[name] = struct{}{}
+		}
+	}
+	ret := make([]string, 0, len(names))
+	for name := range names {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// backendsFor returns the backends with the detector in the order they are tried
+func (r *Router) backendsFor(name string) []*backend {
+	if len(r.ring) == 0 {
+		return nil
+	}
+	h := hash(name)
+	start := sort.Search(len(r.ring), func(i int) bool { return r.ring[i].hash >= h })
+	seen := make(map[*backend]struct{})
+	var ret []*backend
+	for i := 0; i < len(r.ring) && len(seen) < len(r.backends); i++ {
+		b := r.ring[(start+i)%len(r.ring)].backend
+		if _, ok := seen[b]; ok {
+			continue
+		}
+		seen[b] = struct{}{}
+		if _, ok := b.detectors[name]; ok {
+			ret = append(ret, b)
+		}
+	}
+	return ret
+}
+
+// hash spreads similar names like camera1 and camera2 around the ring
+func hash(s string) uint32 {
+	sum := sha256.Sum256([]byte(s))
+	return binary.BigEndian.Uint32(sum[:4])
+}
+
+type detector struct {
+	config   odrpc.Detector
+	backends []*backend
+	logger   *zap.SugaredLogger
+}
+
+// New returns the detector for the config that runs on the backends with a detector of the same name
+func (r *Router) New(c *dconfig.DetectorConfig) (*detector, error) {
+
+	backends := r.backendsFor(c.Name)
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("no backend has detector %s", c.Name)
+	}
+
+	d := &detector{
+		config:   *backends[0].detectors[c.Name],
+		backends: backends,
+		logger:   zap.S().With("package", "detector.remote", "name", c.Name),
+	}
+	d.config.Type = c.Type
+
+	addresses := make([]string, 0, len(backends))
+	for _, b := range backends {
+		addresses = append(addresses, b.address)
+	}
+	d.logger.Infow("Routing detector", "backends", addresses)
+
+	return d, nil
+
+}
+
+func (d *detector) Config() *odrpc.Detector {
+	return &d.config
+}
+
+// Detect sends the image to the first backend for the detector and the next one when a backend is unavailable
+func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+
+	// The image was already prepared and the results are filtered here, the backend only runs the detector. The
+	// request can be for another detector that switched to this one, like a night detector.
+	backendRequest := &odrpc.DetectRequest{
+		Id:           request.Id,
+		DetectorName: d.config.Name,
+		Data:         request.Data,
+	}
+
+	var err error
+	for _, b := range d.backends {
+		var response *odrpc.DetectResponse
+		response, err = b.client.Detect(ctx, backendRequest)
+		if err == nil {
+			return response, nil
+		}
+		if !client.Temporary(err) {
+			return nil, err
+		}
+		d.logger.Warnw("Backend unavailable", "id", request.Id, "address", b.address, "error", err)
+	}
+	return nil, status.Errorf(codes.Unavailable, "no backend available: %v", err)
+
+}
+
+func (d *detector) Shutdown() {}
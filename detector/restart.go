@@ -0,0 +1,153 @@
+package detector
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/labels"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// restarter recreates a detector when it stops its lifecycle, like when an edgetpu hangs. After too many
+// restarts it stops the process instead.
+type restarter struct {
+	create      func(lc *conf.Lifecycle) (Detector, error)
+	parent      *conf.Lifecycle
+	maxRestarts int
+	logger      *zap.SugaredLogger
+
+	current  Detector
+	lc       *conf.Lifecycle
+	restarts int
+	failed   bool
+	lock     sync.RWMutex
+	// the health without waiting for a restart
+	state atomic.Value
+}
+
+func newRestarter(parent *conf.Lifecycle, name string, maxRestarts int, create func(lc *conf.Lifecycle) (Detector, error)) (*restarter, error) {
+
+	r := &restarter{
+		create:      create,
+		parent:      parent,
+		maxRestarts: maxRestarts,
+		logger:      zap.S().With("package", "detector", "name", name),
+		lc:          parent.Child(),
+	}
+	r.state.Store(HealthOnline)
+
+	var err error
+	if r.current, err = create(r.lc); err != nil {
+		return nil, err
+	}
+
+	go r.watch()
+
+	return r, nil
+
+}
+
+// watch restarts the detector each time it stops until the process is stopping
+func (r *restarter) watch() {
+	for {
+		select {
+		case <-r.parent.Done():
+			return
+		case <-r.lc.Done():
+		}
+		if r.parent.Stopping() {
+			return
+		}
+		if !r.restart() {
+			r.logger.Error("Could not restart detector, stopping")
+			r.parent.Stop()
+			return
+		}
+	}
+}
+
+// restart replaces the stopped detector with a new one
+func (r *restarter) restart() bool {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// Wait for detections in progress and release the detector
+	r.state.Store(HealthRestarting)
+	r.lc.Wait()
+	r.current.Shutdown()
+	r.failed = true
+	r.state.Store(HealthOffline)
+
+	if r.restarts >= r.maxRestarts {
+		r.logger.Errorw("Detector stopped too many times", "restarts", r.restarts)
+		return false
+	}
+	r.restarts++
+
+	lc := r.parent.Child()
+	d, err := r.create(lc)
+	if err != nil {
+		r.logger.Errorw("Could not recreate detector", "error", err)
+		lc.Stop()
+		return false
+	}
+
+	// Keep the languages that were added after the detector was created
+	d.Config().Languages = r.current.Config().Languages
+	r.current, r.lc, r.failed = d, lc, false
+	r.state.Store(HealthOnline)
+	r.logger.Warnw("Restarted detector", "restarts", r.restarts)
+
+	return true
+
+}
+
+// health returns the health of the detector
+func (r *restarter) health() string {
+	return r.state.Load().(string)
+}
+
+// Config returns the config of the current detector
+func (r *restarter) Config() *odrpc.Detector {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.current.Config()
+}
+
+// Detect detects with the current detector. It waits while the detector is restarting.
+func (r *restarter) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if r.failed {
+		return nil, status.Errorf(codes.Unavailable, "detector is not available")
+	}
+	return r.current.Detect(ctx, request)
+}
+
+// Shutdown shuts down the current detector
+func (r *restarter) Shutdown() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.failed {
+		r.current.Shutdown()
+		r.failed = true
+		r.state.Store(HealthOffline)
+	}
+}
+
+// Labels returns the labels of the current detector if it has them
+func (r *restarter) Labels() labels.Labels {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if labeler, ok := r.current.(Labeler); ok {
+		return labeler.Labels()
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// retry retries transient detector errors with a backoff
+type retry struct {
+	count      int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	stats retryStats
+	lock  sync.Mutex
+}
+
+// retryStats are the retry rates of a detector
+type retryStats struct {
+	Requests int64 `json:"requests"`
+	// Requests that needed at least one retry
+	Retried int64 `json:"retried"`
+	// The total number of retries
+	Retries int64 `json:"retries"`
+	// Retried requests that succeeded and failed after all the retries
+	Recovered int64 `json:"recovered"`
+	Failed    int64 `json:"failed"`
+	// Retried / Requests
+	RetryRate float64 `json:"retry_rate"`
+}
+
+func newRetry(c *dconfig.RetryConfig) *retry {
+	r := &retry{
+		count:      c.Count,
+		backoff:    c.Backoff,
+		maxBackoff: c.MaxBackoff,
+	}
+	if r.backoff <= 0 {
+		r.backoff = 100 * time.Millisecond
+	}
+	if r.maxBackoff < r.backoff {
+		r.maxBackoff = 10 * r.backoff
+	}
+	return r
+}
+
+// wait waits before the retry attempt (starting at 1), returns false if the context is done
+func (r *retry) wait(ctx context.Context, attempt int) bool {
+	backoff := r.backoff
+	for i := 1; i < attempt && backoff < r.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > r.maxBackoff {
+		backoff = r.maxBackoff
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// record updates the stats with the number of retries of a request
+func (r *retry) record(retries int, failed bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.stats.Requests++
+	if retries > 0 {
+		r.stats.Retried++
+		r.stats.Retries += int64(retries)
+		if failed {
+			r.stats.Failed++
+		} else {
+			r.stats.Recovered++
+		}
+	}
+	r.stats.RetryRate = float64(r.stats.Retried) / float64(r.stats.Requests)
+}
+
+// getStats returns a copy of the stats
+func (r *retry) getStats() *retryStats {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	stats := r.stats
+	return &stats
+}
+
+// detectRetry detects with the detector and retries transient errors
+func (m *Mux) detectRetry(ctx context.Context, detector *muxDetector, request *odrpc.DetectRequest) (*odrpc.DetectResponse, time.Duration, error) {
+
+	response, detectTime, err := m.detectOnce(ctx, detector, request)
+	if detector.retry == nil {
+		return response, detectTime, err
+	}
+
+	var retries int
+	for retries < detector.retry.count && detectFailed(ctx, response, err) {
+		// Don't retry when failing fast or overloaded
+		if detector.breaker != nil && detector.breaker.isOpen() {
+			break
+		}
+		var overload *overloadError
+		if errors.As(err, &overload) {
+			break
+		}
+		retries++
+		m.logger.Debugw("Retrying detection", "id", request.Id, "detector", detector.Config().Name, "attempt", retries, "error", detectError(response, err))
+		if !detector.retry.wait(ctx, retries) {
+			break
+		}
+		response, detectTime, err = m.detectOnce(ctx, detector, request)
+	}
+	detector.retry.record(retries, detectFailed(ctx, response, err))
+
+	return response, detectTime, err
+
+}
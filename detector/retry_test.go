@@ -0,0 +1,57 @@
+package detector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/snowzach/doods/detector/dconfig"
+)
+
+func TestRetryDefaults(t *testing.T) {
+	r := newRetry(&dconfig.RetryConfig{Count: 2})
+	if r.backoff != 100*time.Millisecond || r.maxBackoff != time.Second {
+		t.Errorf("backoff %v max %v, expected 100ms and 1s", r.backoff, r.maxBackoff)
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+
+	r := newRetry(&dconfig.RetryConfig{Count: 5, Backoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond})
+
+	// 10ms, 20ms and then the 25ms max
+	for attempt, expected := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 4: 25 * time.Millisecond} {
+		start := time.Now()
+		if !r.wait(context.Background(), attempt) {
+			t.Fatalf("attempt %d: wait returned false", attempt)
+		}
+		if took := time.Since(start); took < expected || took > expected+50*time.Millisecond {
+			t.Errorf("attempt %d: waited %v, expected %v", attempt, took, expected)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if r.wait(ctx, 1) {
+		t.Error("wait with a done context returned true")
+	}
+
+}
+
+func TestRetryStats(t *testing.T) {
+
+	r := newRetry(&dconfig.RetryConfig{Count: 3})
+	r.record(0, false)
+	r.record(2, false)
+	r.record(3, true)
+	r.record(0, true)
+
+	stats := r.getStats()
+	if stats.Requests != 4 || stats.Retried != 2 || stats.Retries != 5 || stats.Recovered != 1 || stats.Failed != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.RetryRate != 0.5 {
+		t.Errorf("retry rate %v, expected 0.5", stats.RetryRate)
+	}
+
+}
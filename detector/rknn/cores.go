@@ -0,0 +1,54 @@
+//go:build rknn
+// +build rknn
+
+package rknn
+
+/*
+#include <rknn_api.h>
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+)
+
+// coreMask is the NPU cores a context runs on
+type coreMask struct {
+	name string
+	mask C.rknn_core_mask
+}
+
+// The NPU core options, the RK3588 has 3 cores, the RK3566/RK3568 have 1
+var npuCores = map[string]C.rknn_core_mask{
+	"auto":  C.RKNN_NPU_CORE_AUTO,
+	"0":     C.RKNN_NPU_CORE_0,
+	"1":     C.RKNN_NPU_CORE_1,
+	"2":     C.RKNN_NPU_CORE_2,
+	"0_1":   C.RKNN_NPU_CORE_0_1,
+	"0_1_2": C.RKNN_NPU_CORE_0_1_2,
+	"all":   C.RKNN_NPU_CORE_0_1_2,
+}
+
+// coreMasks returns the cores for each context. each runs context N on core N.
+func coreMasks(cores string, count int) ([]coreMask, error) {
+
+	cores = strings.ToLower(cores)
+	if cores == "" {
+		cores = "auto"
+	}
+
+	masks := make([]coreMask, count)
+	for x := range masks {
+		name := cores
+		if cores == "each" {
+			name = fmt.Sprint(x % 3)
+		}
+		mask, ok := npuCores[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid npu cores: %s", cores)
+		}
+		masks[x] = coreMask{name: name, mask: mask}
+	}
+	return masks, nil
+
+}
@@ -0,0 +1,29 @@
+//go:build !rknn
+// +build !rknn
+
+// Package rknn runs models on the Rockchip NPU (RK3566/RK3568/RK3588) with the RKNN runtime.
+// It is only built with the rknn build tag and needs librknnrt and rknn_api.h.
+package rknn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+type detector struct{}
+
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (*detector, error) {
+	return nil, fmt.Errorf("doods was built without rknn support, build with -tags rknn")
+}
+
+func (d *detector) Config() *odrpc.Detector { return nil }
+
+func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+	return nil, fmt.Errorf("rknn is not supported")
+}
+
+func (d *detector) Shutdown() {}
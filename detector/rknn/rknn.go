@@ -0,0 +1,396 @@
+//go:build rknn
+// +build rknn
+
+// Package rknn runs models on the Rockchip NPU (RK3566/RK3568/RK3588) with the RKNN runtime.
+// It is only built with the rknn build tag and needs librknnrt and rknn_api.h.
+package rknn
+
+/*
+#include <stdlib.h>
+#include <rknn_api.h>
+#cgo LDFLAGS: -lrknnrt
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"image"
+	"time"
+	"unsafe"
+
+	"go.uber.org/zap"
+	"gocv.io/x/gocv"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/labels"
+	"github.com/snowzach/doods/detector/postprocess"
+	"github.com/snowzach/doods/odrpc"
+)
+
+const (
+	outputFormatRaw = iota
+	outputFormatPlugin
+	outputFormatSSD
+)
+
+type detector struct {
+	config odrpc.Detector
+	logger *zap.SugaredLogger
+	lc     *conf.Lifecycle
+
+	labels       labels.Labels
+	pool         chan *rknnContext
+	outputFormat int
+	postProcess  postprocess.Func
+	outputs      []*outputAttr
+	timeout      time.Duration
+}
+
+// rknnContext is a loaded model, each one can run one detection at a time
+type rknnContext struct {
+	ctx  C.rknn_context
+	core string
+}
+
+// outputAttr describes an output tensor
+type outputAttr struct {
+	name  string
+	shape []int32
+}
+
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (*detector, error) {
+
+	d := &detector{
+		logger:  zap.S().With("package", "detector.rknn", "name", c.Name),
+		lc:      lc,
+		timeout: c.Timeout,
+	}
+
+	d.config.Name = c.Name
+	d.config.Type = c.Type
+	d.config.Model = c.ModelFile
+	d.config.Labels = make([]string, 0)
+
+	if c.NumConcurrent <= 0 {
+		c.NumConcurrent = 1
+	}
+	d.pool = make(chan *rknnContext, c.NumConcurrent)
+
+	cores, err := coreMasks(c.NPUCores, c.NumConcurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load labels
+	d.labels, err = labels.Load(c.LabelFormat, c.LabelFile, c.ReadModel)
+	if err != nil && (c.RawOutputs || c.PostProcess != nil) && c.LabelFile == "" {
+		// Labels are optional for raw outputs
+		d.labels = make(labels.Labels)
+	} else if err != nil {
+		return nil, fmt.Errorf("could not load labels: %v", err)
+	}
+	d.config.Labels = d.labels.Names()
+
+	model, err := c.ReadModel()
+	if err != nil {
+		return nil, err
+	}
+	cModel := C.CBytes(model)
+	defer C.free(cModel)
+
+	// Create a context for each concurrent detection
+	for x := 0; x < c.NumConcurrent; x++ {
+		rc := &rknnContext{core: cores[x].name}
+		if ret := C.rknn_init(&rc.ctx, cModel, C.uint32_t(len(model)), 0, nil); ret != C.RKNN_SUCC {
+			d.Shutdown()
+			return nil, fmt.Errorf("could not load model %s: %d", c.ModelFile, int(ret))
+		}
+		if cores[x].mask != C.RKNN_NPU_CORE_AUTO {
+			if ret := C.rknn_set_core_mask(rc.ctx, cores[x].mask); ret != C.RKNN_SUCC {
+				C.rknn_destroy(rc.ctx)
+				d.Shutdown()
+				return nil, fmt.Errorf("could not set npu core %s: %d", rc.core, int(ret))
+			}
+		}
+		d.pool <- rc
+	}
+
+	// Get the model inputs and outputs from one of the contexts
+	rc := <-d.pool
+	err = d.loadAttrs(rc)
+	d.pool <- rc
+	if err != nil {
+		d.Shutdown()
+		return nil, err
+	}
+
+	switch {
+	case c.RawOutputs:
+		d.outputFormat = outputFormatRaw
+	case c.PostProcess != nil && c.PostProcess.Plugin != "":
+		if d.postProcess, err = postprocess.Load(c.PostProcess.Plugin, c.PostProcess.Options); err != nil {
+			d.Shutdown()
+			return nil, err
+		}
+		d.outputFormat = outputFormatPlugin
+	case len(d.outputs) == 4:
+		d.outputFormat = outputFormatSSD
+	default:
+		d.Shutdown()
+		return nil, fmt.Errorf("unsupported model with %d outputs, use a postProcess plugin or rawOutputs", len(d.outputs))
+	}
+
+	d.logger.Infow("RKNN model loaded", "width", d.config.Width, "height", d.config.Height, "outputs", len(d.outputs), "contexts", c.NumConcurrent, "npu_cores", c.NPUCores)
+
+	return d, nil
+
+}
+
+// loadAttrs reads the input size and output tensors of the model
+func (d *detector) loadAttrs(rc *rknnContext) error {
+
+	var ioNum C.rknn_input_output_num
+	if ret := C.rknn_query(rc.ctx, C.RKNN_QUERY_IN_OUT_NUM, unsafe.Pointer(&ioNum), C.uint32_t(unsafe.Sizeof(ioNum))); ret != C.RKNN_SUCC {
+		return fmt.Errorf("could not query model: %d", int(ret))
+	}
+	if ioNum.n_input != 1 {
+		return fmt.Errorf("unsupported model with %d inputs", int(ioNum.n_input))
+	}
+
+	var input C.rknn_tensor_attr
+	input.index = 0
+	if ret := C.rknn_query(rc.ctx, C.RKNN_QUERY_INPUT_ATTR, unsafe.Pointer(&input), C.uint32_t(unsafe.Sizeof(input))); ret != C.RKNN_SUCC {
+		return fmt.Errorf("could not query model input: %d", int(ret))
+	}
+	if input.n_dims != 4 {
+		return fmt.Errorf("unsupported model input with %d dimensions", int(input.n_dims))
+	}
+	if input.fmt == C.RKNN_TENSOR_NCHW {
+		d.config.Channels, d.config.Height, d.config.Width = int32(input.dims[1]), int32(input.dims[2]), int32(input.dims[3])
+	} else {
+		d.config.Height, d.config.Width, d.config.Channels = int32(input.dims[1]), int32(input.dims[2]), int32(input.dims[3])
+	}
+	if d.config.Channels != 3 {
+		return fmt.Errorf("unsupported model input with %d channels", d.config.Channels)
+	}
+
+	for x := 0; x < int(ioNum.n_output); x++ {
+		var output C.rknn_tensor_attr
+		output.index = C.uint32_t(x)
+		if ret := C.rknn_query(rc.ctx, C.RKNN_QUERY_OUTPUT_ATTR, unsafe.Pointer(&output), C.uint32_t(unsafe.Sizeof(output))); ret != C.RKNN_SUCC {
+			return fmt.Errorf("could not query model output %d: %d", x, int(ret))
+		}
+		attr := &outputAttr{name: C.GoString(&output.name[0])}
+		for i := 0; i < int(output.n_dims); i++ {
+			attr.shape = append(attr.shape, int32(output.dims[i]))
+		}
+		d.outputs = append(d.outputs, attr)
+	}
+
+	return nil
+
+}
+
+func (d *detector) Config() *odrpc.Detector {
+	return &d.config
+}
+
+func (d *detector) Labels() labels.Labels {
+	return d.labels
+}
+
+func (d *detector) Shutdown() {
+	for {
+		select {
+		case rc := <-d.pool:
+			C.rknn_destroy(rc.ctx)
+		default:
+			return
+		}
+	}
+}
+
+func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+
+	start := time.Now()
+
+	img, err := gocv.IMDecode(request.Data, gocv.IMReadColor)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not decode image: %v", err)
+	} else if img.Empty() {
+		return nil, status.Errorf(codes.InvalidArgument, "could not read image")
+	}
+	defer img.Close()
+
+	// Resize it to the model input and convert to RGB, the runtime does any normalization
+	if int32(img.Cols()) != d.config.Width || int32(img.Rows()) != d.config.Height {
+		gocv.Resize(img, &img, image.Point{X: int(d.config.Width), Y: int(d.config.Height)}, 0, 0, gocv.InterpolationLinear)
+	}
+	gocv.CvtColor(img, &img, gocv.ColorBGRToRGB)
+	if img.Type() != gocv.MatTypeCV8UC3 {
+		img.ConvertTo(&img, gocv.MatTypeCV8UC3)
+	}
+	// The input is leaked if the run hangs
+	var hung bool
+	data := C.CBytes(img.ToBytes())
+	defer func() {
+		if !hung {
+			C.free(data)
+		}
+	}()
+
+	d.logger.Debugw("Image pre-processing complete", "id", request.Id, "duration", time.Since(start))
+
+	// Get a context from the pool
+	var rc *rknnContext
+	select {
+	case rc = <-d.pool:
+	case <-d.lc.Done():
+		return nil, status.Errorf(codes.Unavailable, "detector is stopping")
+	}
+	done := d.lc.Track() // Wait until detection complete before stopping
+	defer func() {
+		if !hung {
+			d.pool <- rc
+		}
+		done()
+	}()
+
+	inferenceStart := time.Now()
+
+	// Perform the detection
+	outputs := make([]C.rknn_output, len(d.outputs))
+	var ret C.int
+	complete := make(chan struct{})
+	go func() {
+		defer close(complete)
+		input := C.rknn_input{
+			index: 0,
+			buf:   data,
+			size:  C.uint32_t(d.config.Width * d.config.Height * 3),
+			_type: C.RKNN_TENSOR_UINT8,
+			fmt:   C.RKNN_TENSOR_NHWC,
+		}
+		if ret = C.rknn_inputs_set(rc.ctx, 1, &input); ret != C.RKNN_SUCC {
+			return
+		}
+		if ret = C.rknn_run(rc.ctx, nil); ret != C.RKNN_SUCC {
+			return
+		}
+		for x := range outputs {
+			outputs[x].want_float = 1
+			outputs[x].index = C.uint32_t(x)
+		}
+		ret = C.rknn_outputs_get(rc.ctx, C.uint32_t(len(outputs)), &outputs[0], nil)
+	}()
+
+	// Wait for complete or timeout if there is one set
+	if d.timeout > 0 {
+		select {
+		case <-complete:
+		case <-time.After(d.timeout):
+			// The NPU is hung, stop the detector so it's reinitialized
+			d.logger.Errorw("Detector timeout", "core", rc.core)
+			hung = true
+			d.lc.Stop()
+			return nil, status.Errorf(codes.Internal, "detect failed")
+		}
+	}
+	<-complete
+
+	if ret != C.RKNN_SUCC {
+		d.logger.Errorw("Detector error", "id", request.Id, "status", int(ret), "core", rc.core)
+		return &odrpc.DetectResponse{
+			Id:    request.Id,
+			Error: "detector error",
+		}, nil
+	}
+
+	// Copy the outputs and release them
+	tensors := make([]*odrpc.OutputTensor, len(outputs))
+	for x := range outputs {
+		values := make([]float32, int(outputs[x].size)/4)
+		if len(values) > 0 {
+			copy(values, (*[1 << 28]float32)(outputs[x].buf)[:len(values):len(values)])
+		}
+		tensors[x] = &odrpc.OutputTensor{
+			Name:   d.outputs[x].name,
+			Type:   "Float32",
+			Shape:  d.outputs[x].shape,
+			Values: values,
+		}
+	}
+	C.rknn_outputs_release(rc.ctx, C.uint32_t(len(outputs)), &outputs[0])
+
+	d.logger.Debugw("Inference complete", "inference_time", time.Since(inferenceStart), "duration", time.Since(start))
+
+	response := &odrpc.DetectResponse{
+		Id:         request.Id,
+		Detections: make([]*odrpc.Detection, 0),
+	}
+	if request.RawOutputs != odrpc.RAW_NONE || d.outputFormat == outputFormatRaw {
+		response.Outputs = tensors
+		if request.RawOutputs == odrpc.RAW_ONLY || d.outputFormat == outputFormatRaw {
+			return response, nil
+		}
+	}
+
+	switch d.outputFormat {
+	case outputFormatPlugin:
+		detections, err := d.postProcess(tensors, d.labels)
+		if err != nil {
+			d.logger.Errorw("Post-processor error", "id", request.Id, "error", err)
+			return &odrpc.DetectResponse{
+				Id:    request.Id,
+				Error: "post-processor error",
+			}, nil
+		}
+		if detections != nil {
+			response.Detections = detections
+		}
+
+	case outputFormatSSD:
+		// Boxes, classes, scores and count like the TFLite_Detection_PostProcess outputs
+		locations, classes, scores := tensors[0].Values, tensors[1].Values, tensors[2].Values
+		if len(tensors[3].Values) == 0 {
+			d.logger.Errorw("Detector invalid results", "id", request.Id)
+			return &odrpc.DetectResponse{
+				Id:    request.Id,
+				Error: "detector invalid result",
+			}, nil
+		}
+		count := int(tensors[3].Values[0])
+		if count > len(scores) {
+			count = len(scores)
+		}
+		if count > len(classes) {
+			count = len(classes)
+		}
+		if count > len(locations)/4 {
+			count = len(locations) / 4
+		}
+		for i := 0; i < count; i++ {
+			label, ok := d.labels[int(classes[i])]
+			if !ok {
+				d.logger.Warnw("Missing label", "index", classes[i])
+				label = "unknown"
+			}
+			response.Detections = append(response.Detections, &odrpc.Detection{
+				Top:        locations[(i * 4)],
+				Left:       locations[(i*4)+1],
+				Bottom:     locations[(i*4)+2],
+				Right:      locations[(i*4)+3],
+				Label:      label,
+				Confidence: scores[i] * 100.0,
+			})
+		}
+	}
+
+	d.logger.Infow("Detection Complete", "id", request.Id, "duration", time.Since(start), "detections", len(response.Detections), "core", rc.core)
+
+	return response, nil
+
+}
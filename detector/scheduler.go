@@ -0,0 +1,102 @@
+package detector
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// scheduler limits the total weight of the detections running at the same time across all detectors.
+// Waiters are served in order so a heavy detector isn't starved by lighter ones.
+type scheduler struct {
+	capacity int64
+	used     int64
+	waiters  list.List
+	lock     sync.Mutex
+}
+
+type waiter struct {
+	weight int64
+	ready  chan struct{}
+}
+
+// newScheduler returns a scheduler with the capacity, nil (no limit) if capacity <= 0
+func newScheduler(capacity int64) *scheduler {
+	if capacity <= 0 {
+		return nil
+	}
+	return &scheduler{capacity: capacity}
+}
+
+// acquire waits until the weight is available or the context is done
+func (s *scheduler) acquire(ctx context.Context, weight int64) error {
+	if s == nil {
+		return nil
+	}
+	if weight > s.capacity {
+		weight = s.capacity
+	}
+
+	s.lock.Lock()
+	if s.used+weight <= s.capacity && s.waiters.Len() == 0 {
+		s.used += weight
+		s.lock.Unlock()
+		return nil
+	}
+	w := waiter{weight: weight, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.lock.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.lock.Lock()
+		select {
+		case <-w.ready:
+			// Acquired while cancelling, give it back
+			s.used -= weight
+			s.notify()
+		default:
+			front := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+			// If it was first, the next waiters may fit now
+			if front {
+				s.notify()
+			}
+		}
+		s.lock.Unlock()
+		return ctx.Err()
+	}
+}
+
+// release returns the weight
+func (s *scheduler) release(weight int64) {
+	if s == nil {
+		return
+	}
+	if weight > s.capacity {
+		weight = s.capacity
+	}
+	s.lock.Lock()
+	s.used -= weight
+	s.notify()
+	s.lock.Unlock()
+}
+
+// notify wakes the waiters that fit in order, the lock must be held
+func (s *scheduler) notify() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+		w := next.Value.(waiter)
+		if s.used+w.weight > s.capacity {
+			return
+		}
+		s.used += w.weight
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
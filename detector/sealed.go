@@ -0,0 +1,123 @@
+package detector
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	config "github.com/spf13/viper"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/sealed"
+)
+
+// modelKeys are the keys to check and decrypt model files
+type modelKeys struct {
+	// Models must be signed by one of these
+	public []ed25519.PublicKey
+	// Encrypted models are decrypted with this, it's loaded the first time it's needed
+	key    []byte
+	keyErr error
+}
+
+// newModelKeys loads the public keys from the config
+func newModelKeys() (*modelKeys, error) {
+
+	k := new(modelKeys)
+	for _, s := range config.GetStringSlice("doods.models.public_keys") {
+		key, err := sealed.ParsePublicKey(s)
+		if err != nil {
+			return nil, err
+		}
+		k.public = append(k.public, key)
+	}
+	return k, nil
+
+}
+
+// unseal checks the signature of the model and decrypts it when it's encrypted. The checked and decrypted model is
+// kept in the config for the detector to load from memory, it's never written to disk.
+func (k *modelKeys) unseal(c *dconfig.DetectorConfig) error {
+
+	// Detectors without a model file like mock and remote
+	if c.ModelFile == "" {
+		return nil
+	}
+
+	if len(k.public) == 0 && !strings.HasSuffix(c.ModelFile, ".enc") {
+		// Only read the model if it could be encrypted
+		if f, err := os.Open(c.ModelFile); err == nil {
+			head := make([]byte, 16)
+			n, _ := f.Read(head)
+			f.Close()
+			if !sealed.IsEncrypted(head[:n]) {
+				return nil
+			}
+		} else {
+			return nil // The detector reports the missing file
+		}
+	}
+
+	data, err := ioutil.ReadFile(c.ModelFile)
+	if err != nil {
+		return fmt.Errorf("could not read model %s: %v", c.ModelFile, err)
+	}
+
+	// The signature is of the file as it's distributed, encrypted or not
+	if len(k.public) > 0 {
+		sig, err := ioutil.ReadFile(c.ModelFile + ".sig")
+		if err != nil {
+			return fmt.Errorf("model %s is not signed: %v", c.ModelFile, err)
+		}
+		if !sealed.Verify(data, sig, k.public) {
+			return fmt.Errorf("model %s has an invalid signature", c.ModelFile)
+		}
+	}
+
+	// The detector loads the checked data, the file could be replaced after it was checked
+	if !sealed.IsEncrypted(data) {
+		c.ModelData = data
+		return nil
+	}
+	key, err := k.decryptionKey()
+	if err != nil {
+		return err
+	}
+	plain, err := sealed.Decrypt(data, key)
+	if err != nil {
+		return fmt.Errorf("model %s: %v", c.ModelFile, err)
+	}
+
+	c.ModelData = plain
+	return nil
+
+}
+
+// decryptionKey returns the key from the config (or DOODS_MODELS_KEY) or the output of the key command
+func (k *modelKeys) decryptionKey() ([]byte, error) {
+
+	if k.key != nil || k.keyErr != nil {
+		return k.key, k.keyErr
+	}
+
+	s := config.GetString("doods.models.key")
+	if command := config.GetStringSlice("doods.models.key_command"); s == "" && len(command) > 0 {
+		// A command that gets the key from a key management service
+		out, err := exec.Command(command[0], command[1:]...).Output()
+		if err != nil {
+			k.keyErr = fmt.Errorf("could not run model key command %s: %v", command[0], err)
+			return nil, k.keyErr
+		}
+		s = string(out)
+	}
+	if s == "" {
+		k.keyErr = fmt.Errorf("model is encrypted but there is no doods.models.key or doods.models.key_command")
+		return nil, k.keyErr
+	}
+	k.key, k.keyErr = sealed.ParseKey(s)
+	return k.key, k.keyErr
+
+}
@@ -0,0 +1,139 @@
+// Package sealed encrypts and signs model files so custom models can be shipped to sites that shouldn't be able to
+// read or change them. Models are encrypted with AES-256-GCM and signed with a detached ed25519 signature.
+package sealed
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// The header of an encrypted model, the version is part of it
+const header = "DOODSENC1\n"
+
+// KeySize is the size of the encryption key
+const KeySize = 32
+
+// IsEncrypted returns true if the data is an encrypted model
+func IsEncrypted(data []byte) bool {
+	return len(data) >= len(header) && string(data[:len(header)]) == header
+}
+
+// Encrypt returns the encrypted model
+func Encrypt(plain []byte, key []byte) ([]byte, error) {
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("could not generate nonce: %v", err)
+	}
+
+	ret := make([]byte, 0, len(header)+len(nonce)+len(plain)+gcm.Overhead())
+	ret = append(ret, header...)
+	ret = append(ret, nonce...)
+	// The header is authenticated so it can't be changed
+	return gcm.Seal(ret, nonce, plain, []byte(header)), nil
+
+}
+
+// Decrypt returns the model from the encrypted data. It fails if the key is wrong or the data was changed.
+func Decrypt(data []byte, key []byte) ([]byte, error) {
+
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("not an encrypted model")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	data = data[len(header):]
+	if len(data) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, fmt.Errorf("encrypted model is truncated")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(header))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt model, wrong key or corrupt file")
+	}
+	return plain, nil
+
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size %d, expected %d", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Sign returns the base64 signature of the data
+func Sign(data []byte, key ed25519.PrivateKey) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)) + "\n")
+}
+
+// Verify returns true if the base64 signature of the data is valid for any of the keys
+func Verify(data []byte, signature []byte, keys []ed25519.PublicKey) bool {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key, data, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateKey returns a new encryption key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ParseKey parses a base64 or hex encryption key
+func ParseKey(s string) ([]byte, error) {
+	return parse(s, KeySize, "encryption key")
+}
+
+// ParsePublicKey parses a base64 or hex ed25519 public key
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := parse(s, ed25519.PublicKeySize, "public key")
+	return ed25519.PublicKey(key), err
+}
+
+// ParsePrivateKey parses a base64 or hex ed25519 private key
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	key, err := parse(s, ed25519.PrivateKeySize, "private key")
+	return ed25519.PrivateKey(key), err
+}
+
+// EncodeKey returns the key as base64
+func EncodeKey(key []byte) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func parse(s string, size int, name string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if key, err := hex.DecodeString(s); err == nil && len(key) == size {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(s); err == nil && len(key) == size {
+		return key, nil
+	}
+	return nil, fmt.Errorf("invalid %s, expected %d bytes as base64 or hex", name, size)
+}
@@ -0,0 +1,113 @@
+package sealed
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := []byte("TFL3 a model")
+
+	data, err := Encrypt(plain, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(data) || IsEncrypted(plain) {
+		t.Fatal("IsEncrypted doesn't match the header")
+	}
+	if bytes.Contains(data, plain) {
+		t.Fatal("encrypted model contains the plain model")
+	}
+
+	out, err := Decrypt(data, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, plain) {
+		t.Fatalf("decrypted %q, expected %q", out, plain)
+	}
+
+	// Wrong key
+	other, _ := GenerateKey()
+	if _, err := Decrypt(data, other); err == nil {
+		t.Error("expected an error with the wrong key")
+	}
+	// Changed data
+	changed := append([]byte{}, data...)
+	changed[len(changed)-1] ^= 1
+	if _, err := Decrypt(changed, key); err == nil {
+		t.Error("expected an error with changed data")
+	}
+	// Truncated
+	if _, err := Decrypt(data[:len(header)+4], key); err == nil {
+		t.Error("expected an error with truncated data")
+	}
+	// Not encrypted
+	if _, err := Decrypt(plain, key); err == nil {
+		t.Error("expected an error with a plain model")
+	}
+	// Bad key size
+	if _, err := Encrypt(plain, key[:16]); err == nil {
+		t.Error("expected an error with a short key")
+	}
+
+}
+
+func TestSignVerify(t *testing.T) {
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	data := []byte("a model")
+	sig := Sign(data, private)
+
+	if !Verify(data, sig, []ed25519.PublicKey{otherPublic, public}) {
+		t.Error("signature didn't verify with the key")
+	}
+	if Verify(data, sig, []ed25519.PublicKey{otherPublic}) {
+		t.Error("signature verified with another key")
+	}
+	if Verify([]byte("another model"), sig, []ed25519.PublicKey{public}) {
+		t.Error("signature verified for other data")
+	}
+	if Verify(data, []byte("not base64!"), []ed25519.PublicKey{public}) {
+		t.Error("garbage signature verified")
+	}
+
+}
+
+func TestParseKeys(t *testing.T) {
+
+	key, _ := GenerateKey()
+	for _, s := range []string{EncodeKey(key), hex.EncodeToString(key), " " + EncodeKey(key) + "\n"} {
+		parsed, err := ParseKey(s)
+		if err != nil || !bytes.Equal(parsed, key) {
+			t.Errorf("could not parse %q: %v", s, err)
+		}
+	}
+	if _, err := ParseKey(EncodeKey(key[:16])); err == nil {
+		t.Error("expected an error for a short key")
+	}
+
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	if parsed, err := ParsePublicKey(EncodeKey(public)); err != nil || !bytes.Equal(parsed, public) {
+		t.Errorf("could not parse the public key: %v", err)
+	}
+	if parsed, err := ParsePrivateKey(hex.EncodeToString(private)); err != nil || !bytes.Equal(parsed, private) {
+		t.Errorf("could not parse the private key: %v", err)
+	}
+	if _, err := ParsePublicKey(EncodeKey(private)); err == nil {
+		t.Error("expected an error for a private key as a public key")
+	}
+
+}
@@ -0,0 +1,229 @@
+package detector
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/mask"
+	"github.com/snowzach/doods/detector/orient"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/zone"
+)
+
+const shadowTimeout = time.Minute
+
+// shadow runs a percentage of the requests through another detector and compares the results
+type shadow struct {
+	detector string
+	percent  float64
+	iou      float32
+
+	stats shadowStats
+	lock  sync.Mutex
+}
+
+// shadowStats is the agreement between the detector and the shadow detector
+type shadowStats struct {
+	Detector    string                       `json:"detector"`
+	Requests    int64                        `json:"requests"`
+	Errors      int64                        `json:"errors"`
+	Matched     int64                        `json:"matched"`
+	OnlyPrimary int64                        `json:"only_primary"`
+	OnlyShadow  int64                        `json:"only_shadow"`
+	Agreement   float64                      `json:"agreement"`
+	Labels      map[string]*shadowLabelStats `json:"labels"`
+	// Average detection times
+	PrimaryTime time.Duration `json:"primary_time"`
+	ShadowTime  time.Duration `json:"shadow_time"`
+}
+
+// shadowLabelStats are the stats for a label
+type shadowLabelStats struct {
+	Matched     int64   `json:"matched"`
+	OnlyPrimary int64   `json:"only_primary"`
+	OnlyShadow  int64   `json:"only_shadow"`
+	Agreement   float64 `json:"agreement"`
+	// Average confidence difference (shadow - primary) of the matched detections
+	ConfidenceDelta float64 `json:"confidence_delta"`
+}
+
+func newShadow(c *dconfig.ShadowConfig) *shadow {
+	s := &shadow{
+		detector: c.Detector,
+		percent:  c.Percent,
+		iou:      c.IOU,
+		stats: shadowStats{
+			Detector: c.Detector,
+			Labels:   make(map[string]*shadowLabelStats),
+		},
+	}
+	if s.percent <= 0 {
+		s.percent = 100
+	}
+	if s.iou <= 0 {
+		s.iou = 0.5
+	}
+	return s
+}
+
+// sample returns true if this request should be run through the shadow detector
+func (s *shadow) sample() bool {
+	return s.percent >= 100 || rand.Float64()*100 < s.percent
+}
+
+// runShadow detects with the shadow detector in the background and compares it with the primary detections.
+// The same mask and zones as the primary detection are applied.
+func (m *Mux) runShadow(s *shadow, request *odrpc.DetectRequest, msk *mask.Mask, zones *zone.Zones, primary []*odrpc.Detection, primaryTime time.Duration) {
+
+	detector, ok := m.detectors[s.detector]
+	if !ok {
+		m.logger.Warnw("Shadow detector not found", "detector", request.DetectorName, "shadow_detector", s.detector)
+		return
+	}
+
+	// Copy what's needed, the request and response are still being used
+	shadowRequest := *request
+	shadowRequest.DetectorName = s.detector
+	expected := make([]odrpc.Detection, len(primary))
+	for i, d := range primary {
+		expected[i] = *d
+	}
+
+	m.lc.Go(func(ctx context.Context) {
+
+		ctx, cancel := context.WithTimeout(ctx, shadowTimeout)
+		defer cancel()
+
+		start := time.Now()
+		err := m.scheduler.acquire(ctx, detector.weight)
+		if err == nil {
+			var response *odrpc.DetectResponse
+			response, err = detector.Detect(ctx, &shadowRequest)
+			m.scheduler.release(detector.weight)
+			if err == nil && response.Error != "" {
+				err = fmt.Errorf("%s", response.Error)
+			}
+			if err == nil {
+				orient.MapBack(response.Detections, shadowRequest.Rotate, shadowRequest.Flip)
+				detector.IgnoreResponse(&shadowRequest, response)
+				MaskResponse(msk, response)
+				zones.FilterResponse(response)
+				m.FilterResponse(&shadowRequest, response)
+				matched, onlyPrimary, onlyShadow := s.record(expected, response.Detections, primaryTime, time.Since(start))
+				m.logger.Debugw("Shadow detection", "id", request.Id, "detector", request.DetectorName, "shadow_detector", s.detector, "matched", matched, "only_primary", onlyPrimary, "only_shadow", onlyShadow)
+				return
+			}
+		}
+
+		m.logger.Warnw("Shadow detection failed", "id", request.Id, "detector", request.DetectorName, "shadow_detector", s.detector, "error", err)
+		s.lock.Lock()
+		s.stats.Requests++
+		s.stats.Errors++
+		s.lock.Unlock()
+	})
+
+}
+
+// record matches the detections by label and overlap and updates the stats
+func (s *shadow) record(primary []odrpc.Detection, shadowed []*odrpc.Detection, primaryTime, shadowTime time.Duration) (int, int, int) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	label := func(name string) *shadowLabelStats {
+		l, ok := s.stats.Labels[name]
+		if !ok {
+			l = new(shadowLabelStats)
+			s.stats.Labels[name] = l
+		}
+		return l
+	}
+
+	// Greedily match each primary detection with the best overlapping shadow detection with the same label
+	used := make([]bool, len(shadowed))
+	var matched, onlyPrimary, onlyShadow int
+	for i := range primary {
+		p := &primary[i]
+		best, bestIOU := -1, s.iou
+		for j, d := range shadowed {
+			if used[j] || d.Label != p.Label {
+				continue
+			}
+			if overlap := odrpc.IoU(p, d); overlap >= bestIOU {
+				best, bestIOU = j, overlap
+			}
+		}
+		l := label(p.Label)
+		if best < 0 {
+			onlyPrimary++
+			l.OnlyPrimary++
+			continue
+		}
+		used[best] = true
+		matched++
+		l.Matched++
+		// Running average of the confidence difference
+		l.ConfidenceDelta += (float64(shadowed[best].Confidence-p.Confidence) - l.ConfidenceDelta) / float64(l.Matched)
+	}
+	for j, d := range shadowed {
+		if !used[j] {
+			onlyShadow++
+			label(d.Label).OnlyShadow++
+		}
+	}
+
+	s.stats.Requests++
+	s.stats.Matched += int64(matched)
+	s.stats.OnlyPrimary += int64(onlyPrimary)
+	s.stats.OnlyShadow += int64(onlyShadow)
+	s.stats.Agreement = agreement(s.stats.Matched, s.stats.OnlyPrimary, s.stats.OnlyShadow)
+	for _, l := range s.stats.Labels {
+		l.Agreement = agreement(l.Matched, l.OnlyPrimary, l.OnlyShadow)
+	}
+	n := time.Duration(s.stats.Requests - s.stats.Errors)
+	s.stats.PrimaryTime += (primaryTime - s.stats.PrimaryTime) / n
+	s.stats.ShadowTime += (shadowTime - s.stats.ShadowTime) / n
+
+	return matched, onlyPrimary, onlyShadow
+
+}
+
+// getStats returns a copy of the stats
+func (s *shadow) getStats() *shadowStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stats := s.stats
+	stats.Labels = make(map[string]*shadowLabelStats, len(s.stats.Labels))
+	for name, l := range s.stats.Labels {
+		c := *l
+		stats.Labels[name] = &c
+	}
+	return &stats
+}
+
+// agreement is the share of the detections found by both detectors
+func agreement(matched, onlyPrimary, onlyShadow int64) float64 {
+	total := matched + onlyPrimary + onlyShadow
+	if total == 0 {
+		return 1
+	}
+	return float64(matched) / float64(total)
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
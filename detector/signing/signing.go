@@ -0,0 +1,143 @@
+// Package signing signs detections so systems that keep them as evidence can check they weren't changed. The
+// signature covers the request id, the detections, the SHA-256 of the image and when they were signed.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/snowzach/doods/detector/sealed"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The signature algorithms
+const (
+	HMACSHA256 = "hmac-sha256"
+	Ed25519    = "ed25519"
+)
+
+// Signer signs responses with a server key
+type Signer struct {
+	algorithm string
+	keyID     string
+	secret    []byte
+	private   ed25519.PrivateKey
+}
+
+// New returns a signer for the algorithm, nil if the algorithm is empty. The key is a 32 byte secret for
+// hmac-sha256 or an ed25519 private key, base64 or hex.
+func New(algorithm string, keyID string, key string) (*Signer, error) {
+	s := &Signer{
+		algorithm: algorithm,
+		keyID:     keyID,
+	}
+	var err error
+	switch algorithm {
+	case "":
+		return nil, nil
+	case HMACSHA256:
+		s.secret, err = sealed.ParseKey(key)
+	case Ed25519:
+		s.private, err = sealed.ParsePrivateKey(key)
+	default:
+		return nil, fmt.Errorf("unknown signing algorithm %s", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Sign sets the signature of the response for the detections from the image
+func (s *Signer) Sign(response *odrpc.DetectResponse, image []byte) error {
+	if s == nil {
+		return nil
+	}
+	hash := sha256.Sum256(image)
+	sig := &odrpc.Signature{
+		Algorithm:   s.algorithm,
+		KeyId:       s.keyID,
+		SignedAt:    time.Now().UnixNano() / int64(time.Millisecond),
+		ImageSha256: hex.EncodeToString(hash[:]),
+	}
+	payload, err := Payload(response.Id, response.Detections, sig)
+	if err != nil {
+		return err
+	}
+	switch s.algorithm {
+	case HMACSHA256:
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(payload)
+		sig.Value = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	case Ed25519:
+		sig.Value = base64.StdEncoding.EncodeToString(ed25519.Sign(s.private, payload))
+	}
+	response.Signature = sig
+	return nil
+}
+
+// Payload returns the bytes that are signed
+func Payload(id string, detections []*odrpc.Detection, sig *odrpc.Signature) ([]byte, error) {
+	return (&odrpc.SignedDetections{
+		Id:          id,
+		Detections:  detections,
+		Algorithm:   sig.Algorithm,
+		KeyId:       sig.KeyId,
+		SignedAt:    sig.SignedAt,
+		ImageSha256: sig.ImageSha256,
+	}).Marshal()
+}
+
+// Verify checks the signature of the detections with the key, the hmac-sha256 secret or the ed25519 public key. If
+// the image is set it must be the image that was signed.
+func Verify(id string, detections []*odrpc.Detection, sig *odrpc.Signature, image []byte, key string) error {
+
+	if sig == nil || sig.Value == "" {
+		return fmt.Errorf("not signed")
+	}
+	value, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	payload, err := Payload(id, detections, sig)
+	if err != nil {
+		return err
+	}
+
+	switch sig.Algorithm {
+	case HMACSHA256:
+		secret, err := sealed.ParseKey(key)
+		if err != nil {
+			return err
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(payload)
+		if !hmac.Equal(mac.Sum(nil), value) {
+			return fmt.Errorf("signature does not match")
+		}
+	case Ed25519:
+		public, err := sealed.ParsePublicKey(key)
+		if err != nil {
+			return err
+		}
+		if !ed25519.Verify(public, payload, value) {
+			return fmt.Errorf("signature does not match")
+		}
+	default:
+		return fmt.Errorf("unknown signing algorithm %s", sig.Algorithm)
+	}
+
+	if image != nil {
+		hash := sha256.Sum256(image)
+		if hex.EncodeToString(hash[:]) != sig.ImageSha256 {
+			return fmt.Errorf("image does not match")
+		}
+	}
+	return nil
+
+}
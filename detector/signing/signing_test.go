@@ -0,0 +1,120 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/snowzach/doods/detector/sealed"
+	"github.com/snowzach/doods/odrpc"
+)
+
+func testResponse() *odrpc.DetectResponse {
+	return &odrpc.DetectResponse{
+		Id: "test",
+		Detections: []*odrpc.Detection{
+			{Top: 0.1, Left: 0.2, Bottom: 0.5, Right: 0.6, Label: "person", Confidence: 87.5},
+			{Top: 0.3, Left: 0.1, Bottom: 0.9, Right: 0.4, Label: "dog", Confidence: 61},
+		},
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+
+	secret, err := sealed.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		algorithm string
+		key       string
+		verifyKey string
+	}{
+		{HMACSHA256, sealed.EncodeKey(secret), sealed.EncodeKey(secret)},
+		{Ed25519, sealed.EncodeKey(private), sealed.EncodeKey(public)},
+	} {
+		t.Run(test.algorithm, func(t *testing.T) {
+
+			signer, err := New(test.algorithm, "key1", test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			image := []byte("an image")
+			response := testResponse()
+			if err := signer.Sign(response, image); err != nil {
+				t.Fatal(err)
+			}
+			sig := response.Signature
+			if sig == nil || sig.Value == "" || sig.Algorithm != test.algorithm || sig.KeyId != "key1" || sig.SignedAt == 0 {
+				t.Fatalf("invalid signature %v", sig)
+			}
+
+			if err := Verify(response.Id, response.Detections, sig, image, test.verifyKey); err != nil {
+				t.Fatalf("could not verify: %v", err)
+			}
+			// The image is optional
+			if err := Verify(response.Id, response.Detections, sig, nil, test.verifyKey); err != nil {
+				t.Fatalf("could not verify without the image: %v", err)
+			}
+
+			// Anything changed doesn't verify
+			if err := Verify(response.Id, response.Detections, sig, []byte("another image"), test.verifyKey); err == nil {
+				t.Fatal("verified another image")
+			}
+			if err := Verify("other", response.Detections, sig, image, test.verifyKey); err == nil {
+				t.Fatal("verified another id")
+			}
+			changed := testResponse()
+			changed.Detections[1].Confidence = 95
+			if err := Verify(response.Id, changed.Detections, sig, image, test.verifyKey); err == nil {
+				t.Fatal("verified changed detections")
+			}
+			if err := Verify(response.Id, response.Detections[:1], sig, image, test.verifyKey); err == nil {
+				t.Fatal("verified removed detections")
+			}
+			later := *sig
+			later.SignedAt++
+			if err := Verify(response.Id, response.Detections, &later, image, test.verifyKey); err == nil {
+				t.Fatal("verified a changed time")
+			}
+			other, _ := sealed.GenerateKey()
+			if err := Verify(response.Id, response.Detections, sig, image, sealed.EncodeKey(other)); err == nil {
+				t.Fatal("verified with another key")
+			}
+
+		})
+	}
+
+}
+
+func TestSignerErrors(t *testing.T) {
+
+	// No algorithm is no signing
+	signer, err := New("", "", "")
+	if err != nil || signer != nil {
+		t.Fatalf("expected no signer, got %v %v", signer, err)
+	}
+	response := testResponse()
+	if err := signer.Sign(response, nil); err != nil || response.Signature != nil {
+		t.Fatal("nil signer signed the response")
+	}
+
+	if _, err := New("rsa", "", ""); err == nil {
+		t.Fatal("expected an error for an unknown algorithm")
+	}
+	if _, err := New(HMACSHA256, "", "short"); err == nil {
+		t.Fatal("expected an error for an invalid key")
+	}
+	if err := Verify("test", nil, nil, nil, ""); err == nil {
+		t.Fatal("verified without a signature")
+	}
+	if err := Verify("test", nil, &odrpc.Signature{Algorithm: "rsa", Value: "AAAA"}, nil, ""); err == nil {
+		t.Fatal("verified an unknown algorithm")
+	}
+
+}
@@ -0,0 +1,106 @@
+package detector
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/snowzach/doods/detector/dconfig"
+)
+
+// The number of detections needed before requests are rejected
+const slaMinSamples = 10
+
+// sla tracks the p95 latency of a detector and rejects new requests when the projected completion time,
+// the requests already running or waiting plus this one, is over the latency.
+type sla struct {
+	latency     time.Duration
+	concurrency int
+
+	samples  []time.Duration
+	next     int
+	inflight int
+	lock     sync.Mutex
+}
+
+// overloadError is returned when a request is rejected, clients should try again later or reduce their rate
+type overloadError struct {
+	detector   string
+	projected  time.Duration
+	retryAfter time.Duration
+}
+
+func (e *overloadError) Error() string {
+	return fmt.Sprintf("detector %s is overloaded (projected %v), try again in %v or reduce the request rate", e.detector, e.projected, e.retryAfter)
+}
+
+// GRPCStatus makes it a ResourceExhausted error (HTTP 429)
+func (e *overloadError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
+
+func newSLA(c *dconfig.SLAConfig, concurrency int) *sla {
+	window := c.Window
+	if window <= 0 {
+		window = 100
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &sla{
+		latency:     c.Latency,
+		concurrency: concurrency,
+		samples:     make([]time.Duration, 0, window),
+	}
+}
+
+// admit returns an overloadError if the request won't finish in time, otherwise done must be called when it's finished.
+// A request is always admitted when nothing is running so the p95 can recover after the detector was slow.
+func (s *sla) admit(detector string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.inflight > 0 && len(s.samples) >= slaMinSamples {
+		// Each full set of concurrent requests ahead of this one takes about the p95
+		projected := s.p95() * time.Duration(s.inflight/s.concurrency+1)
+		if projected > s.latency {
+			return &overloadError{
+				detector:   detector,
+				projected:  projected,
+				retryAfter: (projected - s.latency).Round(time.Millisecond),
+			}
+		}
+	}
+	s.inflight++
+	return nil
+}
+
+// done finishes an admitted request
+func (s *sla) done() {
+	s.lock.Lock()
+	s.inflight--
+	s.lock.Unlock()
+}
+
+// record adds the time of a detection
+func (s *sla) record(d time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.samples) < cap(s.samples) {
+		s.samples = append(s.samples, d)
+		return
+	}
+	s.samples[s.next] = d
+	s.next = (s.next + 1) % len(s.samples)
+}
+
+// p95 returns the 95th percentile detection time, the lock must be held
+func (s *sla) p95() time.Duration {
+	sorted := make([]time.Duration, len(s.samples))
+	copy(sorted, s.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[(len(sorted)*95+99)/100-1]
+}
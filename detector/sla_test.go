@@ -0,0 +1,71 @@
+package detector
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/snowzach/doods/detector/dconfig"
+)
+
+func TestSLA(t *testing.T) {
+
+	s := newSLA(&dconfig.SLAConfig{Latency: 100 * time.Millisecond, Window: 20}, 1)
+
+	// Not enough samples yet
+	for i := 0; i < slaMinSamples; i++ {
+		if err := s.admit("test"); err != nil {
+			t.Fatalf("request %d rejected without samples: %v", i, err)
+		}
+		s.done()
+		s.record(60 * time.Millisecond)
+	}
+
+	// One running takes 60ms, a second would finish after 120ms
+	if err := s.admit("test"); err != nil {
+		t.Fatalf("first request rejected: %v", err)
+	}
+	err := s.admit("test")
+	var overload *overloadError
+	if !errors.As(err, &overload) {
+		t.Fatalf("expected an overload error, got %v", err)
+	}
+	if overload.retryAfter != 20*time.Millisecond {
+		t.Errorf("retry after %v, expected 20ms", overload.retryAfter)
+	}
+	s.done()
+
+}
+
+func TestSLARecovers(t *testing.T) {
+
+	s := newSLA(&dconfig.SLAConfig{Latency: 100 * time.Millisecond, Window: 10}, 1)
+	for i := 0; i < 10; i++ {
+		s.record(time.Second)
+	}
+
+	// The p95 is over the latency but nothing is running
+	if err := s.admit("test"); err != nil {
+		t.Fatalf("idle detector rejected a request: %v", err)
+	}
+	if err := s.admit("test"); err == nil {
+		t.Fatal("expected a second request to be rejected")
+	}
+	s.done()
+
+	// The fast requests that are let through replace the slow samples
+	for i := 0; i < 10; i++ {
+		if err := s.admit("test"); err != nil {
+			t.Fatalf("request %d rejected: %v", i, err)
+		}
+		s.done()
+		s.record(10 * time.Millisecond)
+	}
+	if err := s.admit("test"); err != nil {
+		t.Fatalf("first request rejected: %v", err)
+	}
+	if err := s.admit("test"); err != nil {
+		t.Fatalf("recovered detector rejected a second request: %v", err)
+	}
+
+}
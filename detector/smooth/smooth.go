@@ -0,0 +1,226 @@
+// Package smooth tracks the boxes from a source between frames and smooths them so they don't jitter
+package smooth
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The smoothing methods
+const (
+	MethodEMA    = "ema"
+	MethodKalman = "kalman"
+)
+
+// The defaults
+const (
+	DefaultAlpha     = 0.5
+	DefaultIOU       = 0.3
+	DefaultMaxMissed = 3
+)
+
+// The kalman process and measurement noise for relative coordinates
+const (
+	kalmanQ = 1e-4
+	kalmanR = 1e-3
+)
+
+// Smoother keeps the tracked objects for each source
+type Smoother struct {
+	sources map[string]*tracks
+	lock    sync.Mutex
+}
+
+// tracks are the objects for a source
+type tracks struct {
+	tracks []*track
+	nextID uint32
+	lock   sync.Mutex
+}
+
+// track is an object seen in previous frames
+type track struct {
+	id     uint32
+	label  string
+	box    [4]float32 // top, left, bottom, right
+	kalman [4]kalman
+	missed int
+}
+
+// kalman is a constant velocity filter for one coordinate
+type kalman struct {
+	x, v          float32
+	p00, p01, p11 float32
+}
+
+// New creates a smoother
+func New() *Smoother {
+	return &Smoother{
+		sources: make(map[string]*tracks),
+	}
+}
+
+// Apply matches the detections with the objects in the previous frames of the source and smooths the boxes.
+// The detections get the track id and the box before smoothing.
+func (s *Smoother) Apply(source string, opts *odrpc.Smooth, detections []*odrpc.Detection) {
+
+	if s == nil || opts == nil {
+		return
+	}
+	alpha, minIOU, maxMissed := opts.Alpha, opts.Iou, int(opts.MaxMissed)
+	if alpha <= 0 || alpha > 1 {
+		alpha = DefaultAlpha
+	}
+	if minIOU <= 0 {
+		minIOU = DefaultIOU
+	}
+	if maxMissed <= 0 {
+		maxMissed = DefaultMaxMissed
+	}
+
+	s.lock.Lock()
+	t, ok := s.sources[source]
+	if !ok {
+		t = new(tracks)
+		s.sources[source] = t
+	}
+	s.lock.Unlock()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// Match the best overlaps first
+	type pair struct {
+		d, t int
+		iou  float32
+	}
+	var pairs []pair
+	for i, d := range detections {
+		for j, tr := range t.tracks {
+			if tr.label != d.Label {
+				continue
+			}
+			if v := iou(box(d), tr.box); v >= minIOU {
+				pairs = append(pairs, pair{d: i, t: j, iou: v})
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].iou > pairs[j].iou })
+
+	matchedD := make([]bool, len(detections))
+	matchedT := make([]bool, len(t.tracks))
+	for _, p := range pairs {
+		if matchedD[p.d] || matchedT[p.t] {
+			continue
+		}
+		matchedD[p.d], matchedT[p.t] = true, true
+		t.tracks[p.t].update(detections[p.d], opts.Method, alpha)
+	}
+
+	// Forget the objects that have been gone too long
+	kept := t.tracks[:0]
+	for j, tr := range t.tracks {
+		if !matchedT[j] {
+			tr.missed++
+			if tr.missed > maxMissed {
+				continue
+			}
+		}
+		kept = append(kept, tr)
+	}
+	t.tracks = kept
+
+	// New objects
+	for i, d := range detections {
+		if matchedD[i] {
+			continue
+		}
+		t.nextID++
+		tr := &track{
+			id:    t.nextID,
+			label: d.Label,
+			box:   box(d),
+		}
+		for k := range tr.kalman {
+			tr.kalman[k] = kalman{x: tr.box[k], p00: kalmanR, p11: kalmanR}
+		}
+		t.tracks = append(t.tracks, tr)
+		d.Track = tr.id
+		d.Raw = &odrpc.Box{Top: d.Top, Left: d.Left, Bottom: d.Bottom, Right: d.Right}
+	}
+
+}
+
+// update smooths the track with the detection and sets the smoothed box on the detection
+func (tr *track) update(d *odrpc.Detection, method string, alpha float32) {
+
+	raw := box(d)
+	for k := range raw {
+		if method == MethodKalman {
+			tr.box[k] = tr.kalman[k].update(raw[k])
+		} else {
+			tr.box[k] = alpha*raw[k] + (1-alpha)*tr.box[k]
+		}
+	}
+	tr.missed = 0
+
+	d.Track = tr.id
+	d.Raw = &odrpc.Box{Top: d.Top, Left: d.Left, Bottom: d.Bottom, Right: d.Right}
+	d.Top, d.Left, d.Bottom, d.Right = tr.box[0], tr.box[1], tr.box[2], tr.box[3]
+
+}
+
+// update predicts the next position and corrects it with the measurement
+func (k *kalman) update(z float32) float32 {
+
+	// Predict
+	k.x += k.v
+	k.p00 += 2*k.p01 + k.p11 + kalmanQ
+	k.p01 += k.p11
+	k.p11 += kalmanQ
+
+	// Correct
+	s := k.p00 + kalmanR
+	g0, g1 := k.p00/s, k.p01/s
+	y := z - k.x
+	k.x += g0 * y
+	k.v += g1 * y
+	k.p00, k.p01, k.p11 = (1-g0)*k.p00, (1-g0)*k.p01, k.p11-g1*k.p01
+
+	return k.x
+
+}
+
+func box(d *odrpc.Detection) [4]float32 {
+	return [4]float32{d.Top, d.Left, d.Bottom, d.Right}
+}
+
+func iou(a, b [4]float32) float32 {
+	top, left := max32(a[0], b[0]), max32(a[1], b[1])
+	bottom, right := min32(a[2], b[2]), min32(a[3], b[3])
+	if right <= left || bottom <= top {
+		return 0
+	}
+	intersection := (right - left) * (bottom - top)
+	union := (a[2]-a[0])*(a[3]-a[1]) + (b[2]-b[0])*(b[3]-b[1]) - intersection
+	if union <= 0 {
+		return 0
+	}
+	return intersection / union
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,148 @@
+package smooth
+
+import (
+	"math"
+	"testing"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+func detection(label string, top, left, bottom, right float32) *odrpc.Detection {
+	return &odrpc.Detection{Label: label, Top: top, Left: left, Bottom: bottom, Right: right}
+}
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-4
+}
+
+func TestApply(t *testing.T) {
+
+	s := New()
+	opts := &odrpc.Smooth{Method: MethodEMA, Alpha: 0.5, MaxMissed: 1}
+
+	for _, test := range []struct {
+		name     string
+		source   string
+		frame    []*odrpc.Detection
+		tracks   []uint32
+		expected []*odrpc.Detection
+	}{
+		{
+			name:     "new objects",
+			source:   "camera",
+			frame:    []*odrpc.Detection{detection("person", 0, 0, 0.4, 0.4), detection("car", 0.5, 0.5, 1, 1)},
+			tracks:   []uint32{1, 2},
+			expected: []*odrpc.Detection{detection("person", 0, 0, 0.4, 0.4), detection("car", 0.5, 0.5, 1, 1)},
+		},
+		{
+			name:     "moved objects are averaged",
+			source:   "camera",
+			frame:    []*odrpc.Detection{detection("car", 0.5, 0.6, 1, 1), detection("person", 0.1, 0.1, 0.5, 0.5)},
+			tracks:   []uint32{2, 1},
+			expected: []*odrpc.Detection{detection("car", 0.5, 0.55, 1, 1), detection("person", 0.05, 0.05, 0.45, 0.45)},
+		},
+		{
+			name:     "other label is a new object",
+			source:   "camera",
+			frame:    []*odrpc.Detection{detection("dog", 0.05, 0.05, 0.45, 0.45)},
+			tracks:   []uint32{3},
+			expected: []*odrpc.Detection{detection("dog", 0.05, 0.05, 0.45, 0.45)},
+		},
+		{
+			name:     "other source has its own objects",
+			source:   "door",
+			frame:    []*odrpc.Detection{detection("car", 0.5, 0.6, 1, 1)},
+			tracks:   []uint32{1},
+			expected: []*odrpc.Detection{detection("car", 0.5, 0.6, 1, 1)},
+		},
+		{
+			name:   "missed once",
+			source: "camera",
+			frame:  nil,
+		},
+		{
+			name:     "missed objects are forgotten",
+			source:   "camera",
+			frame:    []*odrpc.Detection{detection("car", 0.5, 0.55, 1, 1)},
+			tracks:   []uint32{4},
+			expected: []*odrpc.Detection{detection("car", 0.5, 0.55, 1, 1)},
+		},
+	} {
+		raw := make([]odrpc.Box, len(test.frame))
+		for i, d := range test.frame {
+			raw[i] = odrpc.Box{Top: d.Top, Left: d.Left, Bottom: d.Bottom, Right: d.Right}
+		}
+		s.Apply(test.source, opts, test.frame)
+		for i, d := range test.frame {
+			e := test.expected[i]
+			if d.Track != test.tracks[i] {
+				t.Errorf("%s: %s track %d, expected %d", test.name, d.Label, d.Track, test.tracks[i])
+			}
+			if !near(d.Top, e.Top) || !near(d.Left, e.Left) || !near(d.Bottom, e.Bottom) || !near(d.Right, e.Right) {
+				t.Errorf("%s: %s box %v, expected %v", test.name, d.Label, d, e)
+			}
+			if d.Raw == nil || *d.Raw != raw[i] {
+				t.Errorf("%s: %s raw %v, expected %v", test.name, d.Label, d.Raw, raw[i])
+			}
+		}
+	}
+
+	// No options or smoother does nothing
+	d := detection("person", 0, 0, 1, 1)
+	s.Apply("camera", nil, []*odrpc.Detection{d})
+	var none *Smoother
+	none.Apply("camera", opts, []*odrpc.Detection{d})
+	if d.Track != 0 || d.Raw != nil {
+		t.Errorf("detection changed: %v", d)
+	}
+
+}
+
+func TestKalman(t *testing.T) {
+
+	s := New()
+	opts := &odrpc.Smooth{Method: MethodKalman}
+
+	// A still object stays where it is
+	for i := 0; i < 5; i++ {
+		d := detection("person", 0.2, 0.2, 0.6, 0.6)
+		s.Apply("camera", opts, []*odrpc.Detection{d})
+		if !near(d.Top, 0.2) || !near(d.Bottom, 0.6) {
+			t.Fatalf("frame %d: still object moved to %v", i, d)
+		}
+	}
+
+	// A moving object is followed, between the last box and the new one
+	last := float32(0.2)
+	for i := 1; i <= 10; i++ {
+		offset := float32(i) * 0.01
+		d := detection("person", 0.2+offset, 0.2, 0.6+offset, 0.6)
+		s.Apply("camera", opts, []*odrpc.Detection{d})
+		if d.Track != 1 {
+			t.Fatalf("frame %d: track %d", i, d.Track)
+		}
+		if d.Top < last || d.Top > 0.2+offset+1e-4 {
+			t.Errorf("frame %d: top %v, expected between %v and %v", i, d.Top, last, 0.2+offset)
+		}
+		last = d.Top
+	}
+
+}
+
+func TestIOU(t *testing.T) {
+
+	for _, test := range []struct {
+		a, b     [4]float32
+		expected float32
+	}{
+		{[4]float32{0, 0, 1, 1}, [4]float32{0, 0, 1, 1}, 1},
+		{[4]float32{0, 0, 1, 1}, [4]float32{0, 0.5, 1, 1.5}, 1.0 / 3},
+		{[4]float32{0, 0, 0.5, 0.5}, [4]float32{0.5, 0.5, 1, 1}, 0},
+		{[4]float32{0, 0, 0, 0}, [4]float32{0, 0, 0, 0}, 0},
+	} {
+		if v := iou(test.a, test.b); !near(v, test.expected) {
+			t.Errorf("iou %v %v = %v, expected %v", test.a, test.b, v, test.expected)
+		}
+	}
+
+}
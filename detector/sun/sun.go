@@ -0,0 +1,68 @@
+// Package sun calculates sunrise and sunset times
+package sun
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	julian1970 = 2440587.5
+	julian2000 = 2451545.0
+	degrees    = math.Pi / 180
+)
+
+func toJulian(t time.Time) float64 {
+	return float64(t.Unix())/86400 + julian1970
+}
+
+func fromJulian(j float64) time.Time {
+	return time.Unix(int64(math.Round((j-julian1970)*86400)), 0)
+}
+
+// Times returns the sunrise and sunset for the day of t at the latitude and longitude (in degrees).
+// If the sun does not rise or set that day, rise and set will be zero and up indicates if it's up all day.
+func Times(t time.Time, latitude float64, longitude float64) (rise time.Time, set time.Time, up bool) {
+
+	// Use noon of the local day
+	y, m, d := t.Date()
+	noon := time.Date(y, m, d, 12, 0, 0, 0, t.Location())
+
+	// Mean solar time
+	n := math.Round(toJulian(noon) - julian2000 + 0.0008)
+	jstar := n - longitude/360
+
+	// Solar mean anomaly
+	ma := math.Mod(357.5291+0.98560028*jstar, 360)
+	// Equation of the center
+	c := 1.9148*math.Sin(ma*degrees) + 0.02*math.Sin(2*ma*degrees) + 0.0003*math.Sin(3*ma*degrees)
+	// Ecliptic longitude
+	lambda := math.Mod(ma+c+180+102.9372, 360)
+	// Solar transit
+	transit := julian2000 + jstar + 0.0053*math.Sin(ma*degrees) - 0.0069*math.Sin(2*lambda*degrees)
+	// Declination of the sun
+	sinDec := math.Sin(lambda*degrees) * math.Sin(23.4397*degrees)
+	cosDec := math.Cos(math.Asin(sinDec))
+	// Hour angle
+	cosW := (math.Sin(-0.833*degrees) - math.Sin(latitude*degrees)*sinDec) / (math.Cos(latitude*degrees) * cosDec)
+	if cosW > 1 {
+		// Polar night
+		return time.Time{}, time.Time{}, false
+	} else if cosW < -1 {
+		// Midnight sun
+		return time.Time{}, time.Time{}, true
+	}
+	w := math.Acos(cosW) / degrees
+
+	return fromJulian(transit - w/360).In(t.Location()), fromJulian(transit + w/360).In(t.Location()), false
+
+}
+
+// IsNight returns true if the sun is down at time t at the latitude and longitude
+func IsNight(t time.Time, latitude float64, longitude float64) bool {
+	rise, set, up := Times(t, latitude, longitude)
+	if rise.IsZero() {
+		return !up
+	}
+	return t.Before(rise) || t.After(set)
+}
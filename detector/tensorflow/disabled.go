@@ -0,0 +1,27 @@
+//go:build !cgo
+// +build !cgo
+
+package tensorflow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/odrpc"
+)
+
+type detector struct{}
+
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (*detector, error) {
+	return nil, fmt.Errorf("doods was built without cgo, tensorflow needs the TensorFlow library")
+}
+
+func (d *detector) Config() *odrpc.Detector { return nil }
+
+func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
+	return nil, fmt.Errorf("tensorflow is not supported")
+}
+
+func (d *detector) Shutdown() {}
@@ -1,15 +1,13 @@
+//go:build cgo
+// +build cgo
+
 package tensorflow
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"fmt"
 	"image"
-	"io/ioutil"
-	"os"
-	"strconv"
-	"strings"
 	"time"
 
 	tf "github.com/tensorflow/tensorflow/tensorflow/go"
@@ -21,24 +19,26 @@ import (
 
 	"github.com/snowzach/doods/conf"
 	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/labels"
 	"github.com/snowzach/doods/odrpc"
 )
 
 type detector struct {
 	config odrpc.Detector
 	logger *zap.SugaredLogger
+	lc     *conf.Lifecycle
 
-	labels map[int]string
+	labels labels.Labels
 	graph  *tf.Graph
 	pool   chan *tf.Session
 }
 
-func New(c *dconfig.DetectorConfig) (*detector, error) {
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (*detector, error) {
 
 	d := &detector{
-		labels: make(map[int]string),
 		logger: zap.S().With("package", "detector.tensorflow", "name", c.Name),
 		pool:   make(chan *tf.Session, c.NumConcurrent),
+		lc:     lc,
 	}
 
 	d.config.Name = c.Name
@@ -49,29 +49,17 @@ func New(c *dconfig.DetectorConfig) (*detector, error) {
 	d.config.Height = -1
 
 	// Load labels
-	f, err := os.Open(c.LabelFile)
+	var err error
+	d.labels, err = labels.Load(c.LabelFormat, c.LabelFile, c.ReadModel)
 	if err != nil {
-		return nil, fmt.Errorf("could not load label", "error", err)
-	}
-	defer f.Close()
-	scanner := bufio.NewScanner(f)
-	for x := 1; scanner.Scan(); x++ {
-		fields := strings.SplitAfterN(scanner.Text(), " ", 2)
-		if len(fields) == 1 {
-			d.labels[x] = fields[0]
-			d.config.Labels = append(d.config.Labels, fields[0])
-		} else if len(fields) == 2 {
-			if y, err := strconv.Atoi(strings.TrimSpace(fields[0])); err == nil {
-				d.labels[y] = strings.TrimSpace(fields[1])
-				d.config.Labels = append(d.config.Labels, strings.TrimSpace(fields[1]))
-			}
-		}
+		return nil, fmt.Errorf("could not load labels: %v", err)
 	}
+	d.config.Labels = d.labels.Names()
 
 	// Raw model data
-	modelData, err := ioutil.ReadFile(c.ModelFile)
+	modelData, err := c.ReadModel()
 	if err != nil {
-		return nil, fmt.Errorf("Could not read model file %s: %v", c.ModelFile, err)
+		return nil, err
 	}
 
 	d.graph = tf.NewGraph()
@@ -79,6 +67,24 @@ func New(c *dconfig.DetectorConfig) (*detector, error) {
 		return nil, fmt.Errorf("Could not import model: %v", err)
 	}
 
+	// Make sure the model has the inputs and outputs we use
+	if len(d.labels) == 0 {
+		return nil, fmt.Errorf("no labels loaded")
+	}
+	for _, name := range []string{"image_tensor", "detection_boxes", "detection_scores", "detection_classes", "num_detections"} {
+		if d.graph.Operation(name) == nil {
+			return nil, fmt.Errorf("invalid model %s: missing operation %s", c.ModelFile, name)
+		}
+	}
+	if dt := d.graph.Operation("image_tensor").Output(0).DataType(); dt != tf.Uint8 {
+		return nil, fmt.Errorf("invalid model %s: unsupported input type %v", c.ModelFile, dt)
+	}
+	for _, name := range []string{"detection_boxes", "detection_scores", "detection_classes", "num_detections"} {
+		if dt := d.graph.Operation(name).Output(0).DataType(); dt != tf.Float {
+			return nil, fmt.Errorf("invalid model %s: output %s has type %v, expected float", c.ModelFile, name, dt)
+		}
+	}
+
 	// Create sessions
 	for x := 0; x < c.NumConcurrent; x++ {
 		s, err := tf.NewSession(d.graph, nil)
@@ -96,6 +102,10 @@ func (d *detector) Config() *odrpc.Detector {
 	return &d.config
 }
 
+func (d *detector) Labels() labels.Labels {
+	return d.labels
+}
+
 func (d *detector) Shutdown() {
 	close(d.pool)
 	for {
@@ -110,10 +120,10 @@ func (d *detector) Shutdown() {
 func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error) {
 
 	sess := <-d.pool
-	conf.Stop.Add(1) // Wait until detection complete before stopping
+	done := d.lc.Track() // Wait until detection complete before stopping
 	defer func() {
 		d.pool <- sess
-		conf.Stop.Done()
+		done()
 	}()
 
 	// Determine the image type
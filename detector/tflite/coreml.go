@@ -0,0 +1,43 @@
+//go:build darwin && coreml && cgo
+// +build darwin,coreml,cgo
+
+package tflite
+
+import (
+	"fmt"
+
+	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates"
+	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates/coreml"
+)
+
+func init() {
+	delegateFactories["coreml"] = newCoreMLDelegate
+}
+
+// newCoreMLDelegate creates the CoreML delegate for Apple Silicon. The devices option is ane (default, only
+// devices with a Neural Engine) or all.
+func newCoreMLDelegate(options map[string]string) (delegates.Delegater, error) {
+
+	var o coreml.Options
+	switch options["devices"] {
+	case "", "ane":
+	case "all":
+		o.AllDevices = true
+	default:
+		return nil, fmt.Errorf("invalid devices: %s", options["devices"])
+	}
+
+	var err error
+	if o.Version, err = intOption(options, "version", 0); err != nil {
+		return nil, err
+	}
+	if o.MaxPartitions, err = intOption(options, "max_partitions", 0); err != nil {
+		return nil, err
+	}
+	if o.MinNodes, err = intOption(options, "min_nodes", 2); err != nil {
+		return nil, err
+	}
+
+	return coreml.New(o), nil
+
+}
@@ -0,0 +1,48 @@
+//go:build cgo
+// +build cgo
+
+package tflite
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates"
+)
+
+// delegateFactory creates a delegate from the delegateOptions of the detector config. It returns nil if the
+// device isn't supported so the interpreter runs on the CPU.
+type delegateFactory func(options map[string]string) (delegates.Delegater, error)
+
+// The delegates other than the edgetpu, they are registered by the files for their build tags
+var delegateFactories = map[string]delegateFactory{}
+
+// supportedDelegates returns the delegates in this build for errors
+func supportedDelegates() []string {
+	names := []string{"edgetpu"}
+	for name := range delegateFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// intOption returns an integer delegate option or the default if it's not set
+func intOption(options map[string]string, name string, def int) (int, error) {
+	value, ok := options[name]
+	if !ok || value == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", name, value)
+	}
+	return i, nil
+}
+
+// HasDelegate returns true if the delegate is built in
+func HasDelegate(name string) bool {
+	_, ok := delegateFactories[name]
+	return ok || name == "edgetpu"
+}
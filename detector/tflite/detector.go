@@ -1,13 +1,12 @@
+//go:build cgo
+// +build cgo
+
 package tflite
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"image"
-	"os"
-	"strconv"
-	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -16,33 +15,54 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/affinity"
 	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/labels"
+	"github.com/snowzach/doods/detector/pixel"
+	"github.com/snowzach/doods/detector/postprocess"
 	"github.com/snowzach/doods/odrpc"
 
 	"github.com/snowzach/doods/detector/tflite/go-tflite"
+	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates"
 	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates/edgetpu"
+	"github.com/snowzach/doods/detector/tflite/schema"
 )
 
 const (
 	OutputFormat_4_TFLite_Detection_PostProcess = iota
 	OutputFormat_2_identity
 	OutputFormat_1_scores
+	OutputFormat_raw
+	OutputFormat_plugin
 )
 
 type detector struct {
 	config odrpc.Detector
 	logger *zap.SugaredLogger
 
-	labels       map[int]string
+	labels       labels.Labels
 	model        *tflite.Model
+	metadata     *schema.Metadata
 	inputType    tflite.TensorType
+	mean         []float32
+	std          []float32
 	outputFormat int
+	postProcess  postprocess.Func
 	pool         chan *tflInterpreter
 
 	devices    []edgetpu.Device
+	etpuOpts   edgetpu.DelegateOptions
+	delegate   func() (delegates.Delegater, error)
 	numThreads int
-	hwAccel    bool
-	timeout    time.Duration
+	inputSize  [2]int // width, height
+	// The image and other inputs
+	imageInputName string
+	imageInput     int
+	inputs         []*modelInput
+	cpus           []int
+	hwAccel        bool
+	timeout        time.Duration
+	lc             *conf.Lifecycle
 }
 
 type tflInterpreter struct {
@@ -50,15 +70,17 @@ type tflInterpreter struct {
 	*tflite.Interpreter
 }
 
-func New(c *dconfig.DetectorConfig) (*detector, error) {
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (*detector, error) {
 
 	d := &detector{
-		labels:     make(map[int]string),
-		logger:     zap.S().With("package", "detector.tflite", "name", c.Name),
-		pool:       make(chan *tflInterpreter, c.NumConcurrent),
-		numThreads: c.NumThreads,
-		hwAccel:    c.HWAccel,
-		timeout:    c.Timeout,
+		logger:         zap.S().With("package", "detector.tflite", "name", c.Name),
+		pool:           make(chan *tflInterpreter, c.NumConcurrent),
+		numThreads:     c.NumThreads,
+		inputSize:      [2]int{c.InputWidth, c.InputHeight},
+		imageInputName: c.ImageInput,
+		hwAccel:        c.HWAccel,
+		timeout:        c.Timeout,
+		lc:             lc,
 	}
 
 	d.config.Name = c.Name
@@ -66,30 +88,58 @@ func New(c *dconfig.DetectorConfig) (*detector, error) {
 	d.config.Model = c.ModelFile
 	d.config.Labels = make([]string, 0)
 
-	// Create the model
-	d.model = tflite.NewModelFromFile(d.config.Model)
+	// Thread placement
+	var err error
+	d.cpus, err = affinity.Parse(c.CPUs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cpus: %v", err)
+	}
+	if len(d.cpus) > 0 {
+		d.logger.Infow("Thread placement", "cpus", d.cpus)
+	}
+
+	// Create the model from the checked data so the file can't change after it was verified
+	modelData, err := c.ReadModel()
+	if err != nil {
+		return nil, err
+	}
+	d.model = tflite.NewModel(modelData)
 	if d.model == nil {
 		return nil, fmt.Errorf("could not load model %s", d.config.Model)
 	}
 
-	// Load labels
-	f, err := os.Open(c.LabelFile)
+	// Read the model metadata if there is any
+	d.metadata, err = schema.ParseMetadata(modelData)
 	if err != nil {
-		return nil, fmt.Errorf("could not load label", "error", err)
-	}
-	defer f.Close()
-	scanner := bufio.NewScanner(f)
-	for x := 1; scanner.Scan(); x++ {
-		fields := strings.SplitAfterN(scanner.Text(), " ", 2)
-		if len(fields) == 1 {
-			d.labels[x] = fields[0]
-			d.config.Labels = append(d.config.Labels, fields[0])
-		} else if len(fields) == 2 {
-			if y, err := strconv.Atoi(strings.TrimSpace(fields[0])); err == nil {
-				d.labels[y] = strings.TrimSpace(fields[1])
-				d.config.Labels = append(d.config.Labels, strings.TrimSpace(fields[1]))
-			}
+		d.logger.Warnw("Could not read model metadata", "error", err)
+	} else if d.metadata != nil {
+		d.logger.Debugw("Model Metadata", "name", d.metadata.Name, "label_file", d.metadata.LabelFile, "mean", d.metadata.Mean, "std", d.metadata.Std, "width", d.metadata.Width, "height", d.metadata.Height)
+	}
+
+	// Load labels, use the labels from the metadata if there is no label file
+	if c.LabelFile == "" && d.metadata != nil && d.metadata.LabelFile != "" && (c.LabelFormat == "" || c.LabelFormat == labels.FormatAuto || c.LabelFormat == labels.FormatTFLite) {
+		d.labels, err = labels.LoadEmbedded(modelData, d.metadata.LabelFile)
+	} else {
+		d.labels, err = labels.Load(c.LabelFormat, c.LabelFile, c.ReadModel)
+	}
+	if err != nil && c.RawOutputs && c.LabelFile == "" {
+		// Labels are optional for raw outputs
+		d.labels = make(labels.Labels)
+	} else if err != nil {
+		return nil, fmt.Errorf("could not load labels: %v", err)
+	}
+	d.config.Labels = d.labels.Names()
+
+	// Other delegates, each interpreter gets its own
+	if d.hwAccel && c.Delegate != "" && c.Delegate != "edgetpu" {
+		factory, ok := delegateFactories[c.Delegate]
+		if !ok {
+			return nil, fmt.Errorf("delegate %s is not supported by this build, supported: %v", c.Delegate, supportedDelegates())
 		}
+		options := c.DelegateOptions
+		d.delegate = func() (delegates.Delegater, error) { return factory(options) }
+		d.config.Type = "tflite-" + c.Delegate
+		d.hwAccel = false
 	}
 
 	// If we are using edgetpu, make sure we have one
@@ -100,9 +150,18 @@ func New(c *dconfig.DetectorConfig) (*detector, error) {
 		if err != nil {
 			return nil, fmt.Errorf("Could not fetch edgetpu device list: %v", err)
 		}
+		if d.devices, err = filterDevices(d.devices, c.DeviceType); err != nil {
+			return nil, err
+		}
 		if len(d.devices) == 0 {
 			return nil, fmt.Errorf("no edgetpu devices detected")
 		}
+		d.logger.Infow("EdgeTPU devices", "devices", d.devices)
+
+		// The clock speed
+		if d.etpuOpts, err = delegateOptions(c.Performance); err != nil {
+			return nil, err
+		}
 		c.NumConcurrent = len(d.devices)
 		d.config.Type = "tflite-edgetpu"
 
@@ -124,7 +183,12 @@ func New(c *dconfig.DetectorConfig) (*detector, error) {
 			interpreter.device = &d.devices[x]
 		}
 
-		interpreter.Interpreter, err = d.newInterpreter(interpreter.device)
+		// Create it on the cpus so the thread pool starts there
+		if perr := affinity.Run(d.cpus, func() {
+			interpreter.Interpreter, err = d.newInterpreter(interpreter.device)
+		}); perr != nil {
+			return nil, perr
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -133,20 +197,48 @@ func New(c *dconfig.DetectorConfig) (*detector, error) {
 	}
 
 	// Get the settings from the input tensor
-	if inputCount := interpreter.GetInputTensorCount(); inputCount != 1 {
-		return nil, fmt.Errorf("unsupported input tensor count: %d", inputCount)
+	if d.imageInput, err = d.findImageInput(interpreter.Interpreter); err != nil {
+		return nil, err
 	}
-	input := interpreter.GetInputTensor(0)
-	if input.Name() != "normalized_input_image_tensor" && input.Name() != "image" && input.Name() != "input_1" {
-		return nil, fmt.Errorf("unsupported input tensor name: %s", input.Name())
+	if err = d.loadInputs(interpreter.Interpreter, c.Inputs); err != nil {
+		return nil, err
 	}
+	input := interpreter.GetInputTensor(d.imageInput)
 	d.config.Height = int32(input.Dim(1))
 	d.config.Width = int32(input.Dim(2))
 	d.config.Channels = int32(input.Dim(3))
 	d.inputType = input.Type()
-	if d.inputType != tflite.UInt8 {
+	switch d.inputType {
+	case tflite.UInt8:
+	case tflite.Float32:
+		// Use the normalization from the metadata if we have it
+		d.mean = []float32{127.5}
+		d.std = []float32{127.5}
+		if d.metadata != nil && len(d.metadata.Mean) > 0 && len(d.metadata.Std) > 0 {
+			d.mean = d.metadata.Mean
+			d.std = d.metadata.Std
+		}
+		if (len(d.mean) != 1 && len(d.mean) != int(d.config.Channels)) || (len(d.std) != 1 && len(d.std) != int(d.config.Channels)) {
+			return nil, fmt.Errorf("invalid normalization parameters mean:%v std:%v for %d channels", d.mean, d.std, d.config.Channels)
+		}
+	default:
 		return nil, fmt.Errorf("unsupported tensor input type: %s", d.inputType)
 	}
+	d.config.Input = &odrpc.InputSpec{Type: d.inputType.String(), Layout: "NHWC"}
+	for c := 0; d.inputType == tflite.Float32 && c < int(d.config.Channels); c++ {
+		mean, std := d.mean[0], d.std[0]
+		if len(d.mean) > 1 {
+			mean = d.mean[c]
+		}
+		if len(d.std) > 1 {
+			std = d.std[c]
+		}
+		d.config.Input.Mean = append(d.config.Input.Mean, mean)
+		d.config.Input.Scale = append(d.config.Input.Scale, 1/std)
+	}
+	if d.metadata != nil && d.metadata.Width > 0 && (int32(d.metadata.Width) != d.config.Width || int32(d.metadata.Height) != d.config.Height) {
+		d.logger.Warnw("Model metadata input size does not match input tensor", "metadata_width", d.metadata.Width, "metadata_height", d.metadata.Height, "width", d.config.Width, "height", d.config.Height)
+	}
 
 	// Dump output tensor information
 	count := interpreter.GetOutputTensorCount()
@@ -161,29 +253,122 @@ func New(c *dconfig.DetectorConfig) (*detector, error) {
 		}
 	}
 
-	if count == 4 && interpreter.GetOutputTensor(0).Name() == "TFLite_Detection_PostProcess" {
+	if c.RawOutputs {
+		d.outputFormat = OutputFormat_raw
+		return d, nil
+	} else if c.PostProcess != nil && c.PostProcess.Plugin != "" {
+		if d.postProcess, err = postprocess.Load(c.PostProcess.Plugin, c.PostProcess.Options); err != nil {
+			return nil, err
+		}
+		d.outputFormat = OutputFormat_plugin
+		return d, nil
+	} else if count == 4 && interpreter.GetOutputTensor(0).Name() == "TFLite_Detection_PostProcess" {
 		d.outputFormat = OutputFormat_4_TFLite_Detection_PostProcess
 	} else if count == 2 && interpreter.GetOutputTensor(0).Name() == "Identity" {
 		d.outputFormat = OutputFormat_2_identity
-	} else if count == 1 && interpreter.GetOutputTensor(0).Name() == "scores" {
+	} else if count == 1 && (interpreter.GetOutputTensor(0).Name() == "scores" || c.Classifier) {
 		d.outputFormat = OutputFormat_1_scores
-		// Check the output types
-		tensor := interpreter.GetOutputTensor(0)
-		if tensor.Type() != tflite.UInt8 {
-			return nil, fmt.Errorf("unsupported tensor output type: %s", tensor.Type())
-		}
-		// Ensure the length of the labels match the detection size
-		for x := int(tensor.ByteSize()) - len(d.labels); x > 0; x-- {
-			d.labels[x] = "unknown"
-		}
 	} else {
 		return nil, fmt.Errorf("unsupported output tensor count: %d", count)
 	}
 
+	// Make sure the outputs are what we expect before we get any requests
+	if err := d.validateOutputs(interpreter.Interpreter); err != nil {
+		return nil, fmt.Errorf("invalid model %s: %v", d.config.Model, err)
+	}
+
 	return d, nil
 }
 
+// validateOutputs checks the output tensor shapes and types match the output format and the labels
+func (d *detector) validateOutputs(interpreter *tflite.Interpreter) error {
+
+	if len(d.labels) == 0 {
+		return fmt.Errorf("no labels loaded")
+	}
+
+	switch d.outputFormat {
+	case OutputFormat_4_TFLite_Detection_PostProcess:
+		// locations [1,N,4], classes [1,N], scores [1,N], count [1]
+		expected := [][]int{{1, -1, 4}, {1, -1}, {1, -1}, {1}}
+		var maxDetections int
+		for x, shape := range expected {
+			tensor := interpreter.GetOutputTensor(x)
+			if !dequantizable(tensor) {
+				return fmt.Errorf("output tensor %d (%s) has unsupported type %s", x, tensor.Name(), tensor.Type())
+			}
+			if err := checkShape(tensor, shape); err != nil {
+				return fmt.Errorf("output tensor %d (%s): %v", x, tensor.Name(), err)
+			}
+			if x == 0 {
+				maxDetections = tensor.Dim(1)
+			} else if x < 3 && tensor.Dim(1) != maxDetections {
+				return fmt.Errorf("output tensor %d (%s) has %d detections, expected %d", x, tensor.Name(), tensor.Dim(1), maxDetections)
+			}
+		}
+		if maxDetections > 100 {
+			return fmt.Errorf("unsupported max detections: %d", maxDetections)
+		}
+
+	case OutputFormat_1_scores:
+		tensor := interpreter.GetOutputTensor(0)
+		if !dequantizable(tensor) {
+			return fmt.Errorf("unsupported tensor output type: %s", tensor.Type())
+		}
+		if err := checkShape(tensor, []int{1, -1}); err != nil {
+			return fmt.Errorf("output tensor 0 (%s): %v", tensor.Name(), err)
+		}
+		// There must be one label per class
+		if classes := tensor.Dim(1); classes != len(d.labels) {
+			return fmt.Errorf("model has %d classes but there are %d labels", classes, len(d.labels))
+		}
+		for x := 0; x < len(d.labels); x++ {
+			if _, ok := d.labels[x]; !ok {
+				return fmt.Errorf("missing label for class %d", x)
+			}
+		}
+	}
+
+	return nil
+
+}
+
+// dequantizable returns true if the tensor is float32 or a quantized integer type
+func dequantizable(tensor *tflite.Tensor) bool {
+	switch tensor.Type() {
+	case tflite.Float32:
+		return true
+	case tflite.UInt8, tflite.Int8, tflite.Int16, tflite.Int32:
+		return tensor.AffineQuantization() != nil
+	}
+	return false
+}
+
+// checkShape ensures the tensor dimensions match shape. A dimension of -1 will match any size.
+func checkShape(tensor *tflite.Tensor, shape []int) error {
+	if tensor.NumDims() != len(shape) {
+		return fmt.Errorf("has shape %v, expected %d dimensions", tensor.Shape(), len(shape))
+	}
+	for x, size := range shape {
+		if size >= 0 && tensor.Dim(x) != size {
+			return fmt.Errorf("has shape %v, expected dimension %d to be %d", tensor.Shape(), x, size)
+		}
+	}
+	return nil
+}
+
 func (d *detector) newInterpreter(device *edgetpu.Device) (*tflite.Interpreter, error) {
+	interpreter, err := d.createInterpreter(device, true)
+	if err != nil && device == nil && d.delegate != nil {
+		// The delegate couldn't run the model, fall back to the CPU
+		d.logger.Warnw("Could not use delegate, using the CPU", "type", d.config.Type, "error", err)
+		return d.createInterpreter(nil, false)
+	}
+	return interpreter, err
+}
+
+// createInterpreter creates an interpreter with the edgetpu device or the other delegate
+func (d *detector) createInterpreter(device *edgetpu.Device, useDelegate bool) (*tflite.Interpreter, error) {
 	// Options
 	options := tflite.NewInterpreterOptions()
 	options.SetNumThread(d.numThreads)
@@ -193,11 +378,21 @@ func (d *detector) newInterpreter(device *edgetpu.Device) (*tflite.Interpreter,
 
 	// Use edgetpu
 	if device != nil {
-		etpuInstance := edgetpu.New(*device)
+		etpuInstance := edgetpu.NewWithOptions(*device, d.etpuOpts)
 		if etpuInstance == nil {
 			return nil, fmt.Errorf("could not initialize edgetpu %s", device.Path)
 		}
 		options.AddDelegate(etpuInstance)
+	} else if d.delegate != nil && useDelegate {
+		delegate, err := d.delegate()
+		if err != nil {
+			return nil, fmt.Errorf("could not create %s delegate: %v", d.config.Type, err)
+		}
+		if delegate == nil {
+			d.logger.Warnw("Delegate not supported on this device, using the CPU", "type", d.config.Type)
+		} else {
+			options.AddDelegate(delegate)
+		}
 	}
 
 	interpreter := tflite.NewInterpreter(d.model, options)
@@ -205,6 +400,26 @@ func (d *detector) newInterpreter(device *edgetpu.Device) (*tflite.Interpreter,
 		return nil, fmt.Errorf("Could not create interpreter")
 	}
 
+	// Resize the image input to the configured size
+	if interpreter.GetInputTensorCount() == 0 {
+		return nil, fmt.Errorf("model has no input tensor")
+	}
+	index, err := d.findImageInput(interpreter)
+	if err != nil {
+		return nil, err
+	}
+	input := interpreter.GetInputTensor(index)
+	if d.inputSize[0] > 0 && d.inputSize[1] > 0 {
+		if input.NumDims() != 4 {
+			return nil, fmt.Errorf("could not resize input tensor with shape %v", input.ShapeSignature())
+		}
+		if status := interpreter.ResizeInputTensor(index, []int{1, d.inputSize[1], d.inputSize[0], input.Dim(3)}); status != tflite.OK {
+			return nil, fmt.Errorf("could not resize input tensor to %dx%d", d.inputSize[0], d.inputSize[1])
+		}
+	} else if input.Dynamic() {
+		return nil, fmt.Errorf("model has a dynamic input shape %v, inputWidth and inputHeight are required", input.ShapeSignature())
+	}
+
 	// Allocate
 	status := interpreter.AllocateTensors()
 	if status != tflite.OK {
@@ -218,6 +433,10 @@ func (d *detector) Config() *odrpc.Detector {
 	return &d.config
 }
 
+func (d *detector) Labels() labels.Labels {
+	return d.labels
+}
+
 func (d *detector) Shutdown() {
 	close(d.pool)
 	for {
@@ -236,9 +455,15 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 	start := time.Now()
 
 	// If this is ppm data, move it right to tensorflow
-	if ppmInfo := FindPPMData(request.Data); ppmInfo != nil && int32(ppmInfo.Width) == d.config.Width && int32(ppmInfo.Height) == d.config.Height {
+	ppmInfo := pixel.FindPPMData(request.Data)
+	if ppmInfo != nil && int32(ppmInfo.Width) == d.config.Width && int32(ppmInfo.Height) == d.config.Height {
 		// Dump data right to data input
 		data = request.Data[ppmInfo.Offset:]
+	} else if ppmInfo != nil && d.config.Channels == 3 && len(request.Data)-ppmInfo.Offset >= ppmInfo.Width*ppmInfo.Height*3 {
+		// Resize the RGB data without decoding it
+		data = make([]byte, int(d.config.Width*d.config.Height)*3)
+		pixel.Resize(data, int(d.config.Width), int(d.config.Height), request.Data[ppmInfo.Offset:], ppmInfo.Width, ppmInfo.Height)
+		d.logger.Debugw("Resized Image", "id", request.Id, "width", d.config.Width, "height", d.config.Height, "duration", time.Now().Sub(start))
 	} else {
 
 		img, err := gocv.IMDecode(request.Data, gocv.IMReadColor)
@@ -274,16 +499,36 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 	d.logger.Debugw("Image pre-processing complete", "duration", time.Now().Sub(start))
 
 	// Get an interpreter from the pool
-	interpreter := <-d.pool
-	conf.Stop.Add(1) // Wait until detection complete before stopping
+	var interpreter *tflInterpreter
+	select {
+	case interpreter = <-d.pool:
+	case <-d.lc.Done():
+		return nil, status.Errorf(codes.Unavailable, "detector is stopping")
+	}
+	done := d.lc.Track() // Wait until detection complete before stopping
+	var hung bool
 	defer func() {
-		d.pool <- interpreter
-		conf.Stop.Done()
+		if !hung {
+			d.pool <- interpreter
+		}
+		done()
 	}()
 
 	// Build the tensor input
-	input := interpreter.GetInputTensor(0)
-	input.CopyFromBuffer(data)
+	input := interpreter.GetInputTensor(d.imageInput)
+	switch d.inputType {
+	case tflite.Float32:
+		d.normalize(data, input.Float32s())
+	default:
+		input.CopyFromBuffer(data)
+	}
+
+	// Any other inputs
+	for _, in := range d.inputs {
+		if err := in.fill(interpreter.GetInputTensor(in.index), request.Inputs[in.field]); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid input %s: %v", in.field, err)
+		}
+	}
 
 	inferenceStart := time.Now()
 
@@ -291,7 +536,10 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 	var invokeStatus tflite.Status
 	complete := make(chan struct{})
 	go func() {
-		invokeStatus = interpreter.Invoke()
+		if err := affinity.Run(d.cpus, func() { invokeStatus = interpreter.Invoke() }); err != nil {
+			d.logger.Warnw("Could not set thread placement", "error", err)
+			invokeStatus = interpreter.Invoke()
+		}
 		close(complete)
 	}()
 
@@ -301,9 +549,10 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 		case <-complete:
 			// We're done
 		case <-time.After(d.timeout):
-			// The detector is hung, it needs to be reinitialized
+			// The detector is hung, stop it so it's reinitialized
 			d.logger.Errorw("Detector timeout", zap.Any("device", interpreter.device))
-			conf.Stop.Stop() // Exit after all threads complete
+			hung = true
+			d.lc.Stop()
 			return nil, status.Errorf(codes.Internal, "detect failed")
 		}
 	}
@@ -320,14 +569,36 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 
 	d.logger.Debugw("Inference complete", "inference_time", time.Now().Sub(inferenceStart), "duration", time.Now().Sub(start))
 
+	// Return the raw outputs if requested
+	var outputs []*odrpc.OutputTensor
+	if request.RawOutputs != odrpc.RAW_NONE || d.outputFormat == OutputFormat_raw || d.outputFormat == OutputFormat_plugin {
+		outputs = rawOutputs(interpreter.Interpreter)
+		if request.RawOutputs == odrpc.RAW_ONLY || d.outputFormat == OutputFormat_raw {
+			d.logger.Infow("Detection Complete", "id", request.Id, "duration", time.Since(start), "outputs", len(outputs), zap.Any("device", interpreter.device))
+			return &odrpc.DetectResponse{
+				Id:      request.Id,
+				Outputs: outputs,
+			}, nil
+		}
+	}
+
 	detections := make([]*odrpc.Detection, 0)
 
 	switch d.outputFormat {
 	case OutputFormat_4_TFLite_Detection_PostProcess:
-		// Parse results
-		var countResult float32
-		interpreter.GetOutputTensor(3).CopyToBuffer(&countResult)
-		count := int(countResult)
+		// Parse results, quantized outputs are converted to float
+		outputs := make([][]float32, 4)
+		for x := range outputs {
+			var err error
+			if outputs[x], err = interpreter.GetOutputTensor(x).Dequantize(); err != nil || len(outputs[x]) == 0 {
+				d.logger.Errorw("Detector invalid results", "id", request.Id, "output", x, "error", err, zap.Any("device", interpreter.device))
+				return &odrpc.DetectResponse{
+					Id:    request.Id,
+					Error: "detector invalid result",
+				}, nil
+			}
+		}
+		count := int(outputs[3][0])
 
 		// Check for a sane count value
 		if count < 0 || count > 100 {
@@ -338,14 +609,9 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 			}, nil
 		}
 
-		locations := make([]float32, count*4, count*4)
-		classes := make([]float32, count, count)
-		scores := make([]float32, count, count)
-
-		if count > 0 {
-			interpreter.GetOutputTensor(0).CopyToBuffer(&locations[0])
-			interpreter.GetOutputTensor(1).CopyToBuffer(&classes[0])
-			interpreter.GetOutputTensor(2).CopyToBuffer(&scores[0])
+		locations, classes, scores := outputs[0], outputs[1], outputs[2]
+		if count > len(scores) {
+			count = len(scores)
 		}
 
 		for i := 0; i < count; i++ {
@@ -366,6 +632,22 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 			})
 		}
 
+	case OutputFormat_plugin:
+		var err error
+		if detections, err = d.postProcess(outputs, d.labels); err != nil {
+			d.logger.Errorw("Post-processor error", "id", request.Id, "error", err)
+			return &odrpc.DetectResponse{
+				Id:    request.Id,
+				Error: "post-processor error",
+			}, nil
+		}
+		if detections == nil {
+			detections = make([]*odrpc.Detection, 0)
+		}
+		if request.RawOutputs == odrpc.RAW_NONE {
+			outputs = nil
+		}
+
 	case OutputFormat_2_identity:
 
 		// https://github.com/guichristmann/edge-tpu-tiny-yolo
@@ -374,10 +656,16 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 		d.logger.Warnw("RESULTS", "test", test)
 
 	case OutputFormat_1_scores:
-		scores := make([]uint8, len(d.labels), len(d.labels))
-		interpreter.GetOutputTensor(0).CopyToBuffer(scores)
+		scores, err := interpreter.GetOutputTensor(0).Dequantize()
+		if err != nil || len(scores) < len(d.labels) {
+			d.logger.Errorw("Detector invalid results", "id", request.Id, "error", err, zap.Any("device", interpreter.device))
+			return &odrpc.DetectResponse{
+				Id:    request.Id,
+				Error: "detector invalid result",
+			}, nil
+		}
 
-		for i := range scores {
+		for i := 0; i < len(d.labels); i++ {
 			// Get the label
 			label, ok := d.labels[i]
 			if !ok {
@@ -391,7 +679,7 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 				Bottom:     1.0,
 				Right:      1.0,
 				Label:      label,
-				Confidence: 100.0 * (float32(scores[i]) / 255.0),
+				Confidence: 100.0 * scores[i],
 			})
 		}
 	}
@@ -401,5 +689,64 @@ func (d *detector) Detect(ctx context.Context, request *odrpc.DetectRequest) (*o
 	return &odrpc.DetectResponse{
 		Id:         request.Id,
 		Detections: detections,
+		Outputs:    outputs,
 	}, nil
 }
+
+// rawOutputs returns the output tensors of the interpreter
+func rawOutputs(interpreter *tflite.Interpreter) []*odrpc.OutputTensor {
+	count := interpreter.GetOutputTensorCount()
+	outputs := make([]*odrpc.OutputTensor, 0, count)
+	for x := 0; x < count; x++ {
+		tensor := interpreter.GetOutputTensor(x)
+		output := &odrpc.OutputTensor{
+			Name: tensor.Name(),
+			Type: tensor.Type().String(),
+		}
+		for _, dim := range tensor.Shape() {
+			output.Shape = append(output.Shape, int32(dim))
+		}
+		var err error
+		if output.Values, err = tensor.Dequantize(); err != nil {
+			output.Data = make([]byte, tensor.ByteSize())
+			if len(output.Data) > 0 {
+				tensor.CopyToBuffer(&output.Data[0])
+			}
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+// normalize converts the RGB image data to floats using the mean and std for each channel
+func (d *detector) normalize(data []byte, out []float32) {
+	channels := int(d.config.Channels)
+	if channels == 3 {
+		var mean, scale [3]float32
+		for c := range mean {
+			mean[c], scale[c] = d.mean[0], 1/d.std[0]
+			if len(d.mean) > 1 {
+				mean[c] = d.mean[c]
+			}
+			if len(d.std) > 1 {
+				scale[c] = 1 / d.std[c]
+			}
+		}
+		pixel.Normalize(out, data, mean, scale)
+		return
+	}
+	for i := range out {
+		if i >= len(data) {
+			break
+		}
+		c := i % channels
+		mean, std := d.mean[0], d.std[0]
+		if len(d.mean) > 1 {
+			mean = d.mean[c]
+		}
+		if len(d.std) > 1 {
+			std = d.std[c]
+		}
+		out[i] = (float32(data[i]) - mean) / std
+	}
+}
@@ -0,0 +1,68 @@
+//go:build cgo
+// +build cgo
+
+package tflite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates/edgetpu"
+)
+
+// The runtime performance modes, max runs at the highest clock speed and needs adequate cooling
+var performanceModes = map[string]string{
+	"low":    "Low",
+	"medium": "Medium",
+	"high":   "High",
+	"max":    "Max",
+}
+
+// filterDevices returns the devices of the type: usb or pcie (pci), all if blank
+func filterDevices(devices []edgetpu.Device, deviceType string) ([]edgetpu.Device, error) {
+
+	var want edgetpu.DeviceType
+	switch strings.ToLower(deviceType) {
+	case "":
+		return devices, nil
+	case "usb":
+		want = edgetpu.TypeApexUSB
+	case "pcie", "pci":
+		want = edgetpu.TypeApexPCI
+	default:
+		return nil, fmt.Errorf("invalid device type: %s", deviceType)
+	}
+
+	filtered := make([]edgetpu.Device, 0, len(devices))
+	for _, device := range devices {
+		if device.Type == want {
+			filtered = append(filtered, device)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no %s edgetpu devices detected", deviceType)
+	}
+	return filtered, nil
+
+}
+
+// delegateOptions returns the runtime options for the performance mode
+func delegateOptions(performance string) (edgetpu.DelegateOptions, error) {
+	if performance == "" {
+		return nil, nil
+	}
+	mode, ok := performanceModes[strings.ToLower(performance)]
+	if !ok {
+		return nil, fmt.Errorf("invalid performance: %s", performance)
+	}
+	return edgetpu.DelegateOptions{"Performance": mode}, nil
+}
+
+// EdgeTPUDevices returns the edgetpu devices found
+func EdgeTPUDevices() []edgetpu.Device {
+	devices, err := edgetpu.DeviceList()
+	if err != nil {
+		return nil
+	}
+	return devices
+}
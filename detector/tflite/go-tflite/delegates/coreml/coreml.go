@@ -0,0 +1,63 @@
+//go:build darwin && coreml
+// +build darwin,coreml
+
+package coreml
+
+/*
+#include <tensorflow/lite/delegates/coreml/coreml_delegate.h>
+#cgo LDFLAGS: -framework CoreML -framework Foundation
+*/
+import "C"
+import (
+	"unsafe"
+
+	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates"
+)
+
+// Options are the CoreML delegate options
+type Options struct {
+	// Use the delegate on all devices, by default only devices with a Neural Engine are used
+	AllDevices bool
+	// The CoreML version (2 or 3), 0 uses the newest supported version
+	Version int
+	// The maximum number of CoreML partitions, 0 is no limit
+	MaxPartitions int
+	// The minimum number of nodes per partition
+	MinNodes int
+}
+
+// Delegate is the tflite delegate
+type Delegate struct {
+	d *C.TfLiteDelegate
+}
+
+// New creates the CoreML delegate, it returns nil if the device isn't supported
+func New(options Options) delegates.Delegater {
+	var cOptions C.TfLiteCoreMlDelegateOptions
+	if options.AllDevices {
+		cOptions.enabled_devices = C.TfLiteCoreMlDelegateAllDevices
+	} else {
+		cOptions.enabled_devices = C.TfLiteCoreMlDelegateDevicesWithNeuralEngine
+	}
+	cOptions.coreml_version = C.int(options.Version)
+	cOptions.max_delegated_partitions = C.int(options.MaxPartitions)
+	cOptions.min_nodes_per_partition = C.int(options.MinNodes)
+
+	d := C.TfLiteCoreMlDelegateCreate(&cOptions)
+	if d == nil {
+		return nil
+	}
+	return &Delegate{
+		d: d,
+	}
+}
+
+// Delete the delegate
+func (c *Delegate) Delete() {
+	C.TfLiteCoreMlDelegateDelete(c.d)
+}
+
+// Return a pointer
+func (c *Delegate) Ptr() unsafe.Pointer {
+	return unsafe.Pointer(c.d)
+}
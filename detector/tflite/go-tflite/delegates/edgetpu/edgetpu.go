@@ -28,9 +28,8 @@ type Device struct {
 	Path string
 }
 
-// There are no options
-type DelegateOptions struct {
-}
+// DelegateOptions are passed to the runtime, like Performance (Low, Medium, High or Max)
+type DelegateOptions map[string]string
 
 // Delegate is the tflite delegate
 type Delegate struct {
@@ -38,8 +37,33 @@ type Delegate struct {
 }
 
 func New(device Device) delegates.Delegater {
+	return NewWithOptions(device, nil)
+}
+
+// NewWithOptions creates the delegate with runtime options
+func NewWithOptions(device Device, options DelegateOptions) delegates.Delegater {
+
+	path := C.CString(device.Path)
+	defer C.free(unsafe.Pointer(path))
+
+	// Build the C array of options
+	var cOptions *C.struct_edgetpu_option
+	if len(options) > 0 {
+		cOptions = (*C.struct_edgetpu_option)(C.malloc(C.size_t(len(options)) * C.size_t(unsafe.Sizeof(C.struct_edgetpu_option{}))))
+		defer C.free(unsafe.Pointer(cOptions))
+		optionSlice := (*[1024]C.struct_edgetpu_option)(unsafe.Pointer(cOptions))[:len(options):len(options)]
+		i := 0
+		for name, value := range options {
+			optionSlice[i].name = C.CString(name)
+			optionSlice[i].value = C.CString(value)
+			defer C.free(unsafe.Pointer(optionSlice[i].name))
+			defer C.free(unsafe.Pointer(optionSlice[i].value))
+			i++
+		}
+	}
+
 	var d *C.TfLiteDelegate
-	d = C.edgetpu_create_delegate(uint32(device.Type), C.CString(device.Path), nil, 0)
+	d = C.edgetpu_create_delegate(uint32(device.Type), path, cOptions, C.size_t(len(options)))
 	if d == nil {
 		return nil
 	}
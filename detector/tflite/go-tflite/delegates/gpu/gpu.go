@@ -0,0 +1,77 @@
+//go:build gpu
+// +build gpu
+
+package gpu
+
+/*
+#include <tensorflow/lite/delegates/gpu/delegate.h>
+#cgo LDFLAGS: -ltensorflowlite_gpu_delegate
+*/
+import "C"
+import (
+	"unsafe"
+
+	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates"
+)
+
+// The backends
+const (
+	BackendAuto = iota
+	BackendOpenCL
+	BackendOpenGL
+)
+
+// Options are the GPU delegate options
+type Options struct {
+	Backend int
+	// Allow float16 precision, faster but less precise
+	AllowPrecisionLoss bool
+	// Run quantized models
+	EnableQuant bool
+	// Favor speed after the first inference over the startup time
+	SustainedSpeed bool
+}
+
+// Delegate is the tflite delegate
+type Delegate struct {
+	d *C.TfLiteDelegate
+}
+
+// New creates the GPU delegate, it returns nil if it couldn't be created
+func New(options Options) delegates.Delegater {
+	cOptions := C.TfLiteGpuDelegateOptionsV2Default()
+	if options.AllowPrecisionLoss {
+		cOptions.is_precision_loss_allowed = 1
+	}
+	if options.SustainedSpeed {
+		cOptions.inference_preference = C.TFLITE_GPU_INFERENCE_PREFERENCE_SUSTAINED_SPEED
+	}
+	cOptions.experimental_flags = C.TFLITE_GPU_EXPERIMENTAL_FLAGS_NONE
+	if options.EnableQuant {
+		cOptions.experimental_flags |= C.TFLITE_GPU_EXPERIMENTAL_FLAGS_ENABLE_QUANT
+	}
+	switch options.Backend {
+	case BackendOpenCL:
+		cOptions.experimental_flags |= C.TFLITE_GPU_EXPERIMENTAL_FLAGS_CL_ONLY
+	case BackendOpenGL:
+		cOptions.experimental_flags |= C.TFLITE_GPU_EXPERIMENTAL_FLAGS_GL_ONLY
+	}
+
+	d := C.TfLiteGpuDelegateV2Create(&cOptions)
+	if d == nil {
+		return nil
+	}
+	return &Delegate{
+		d: d,
+	}
+}
+
+// Delete the delegate
+func (g *Delegate) Delete() {
+	C.TfLiteGpuDelegateV2Delete(g.d)
+}
+
+// Return a pointer
+func (g *Delegate) Ptr() unsafe.Pointer {
+	return unsafe.Pointer(g.d)
+}
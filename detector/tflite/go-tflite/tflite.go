@@ -142,9 +142,16 @@ const (
 	Error
 )
 
-// ResizeInputTensor resize the tensor specified by index with dims.
+// ResizeInputTensor resize the tensor specified by index with dims. AllocateTensors must be called after.
 func (i *Interpreter) ResizeInputTensor(index int, dims []int) Status {
-	s := C.TfLiteInterpreterResizeInputTensor(i.i, C.int32_t(index), (*C.int)(unsafe.Pointer(&dims[0])), C.int32_t(len(dims)))
+	if len(dims) == 0 {
+		return Error
+	}
+	cdims := make([]C.int, len(dims))
+	for x, dim := range dims {
+		cdims[x] = C.int(dim)
+	}
+	s := C.TfLiteInterpreterResizeInputTensor(i.i, C.int32_t(index), &cdims[0], C.int32_t(len(cdims)))
 	return Status(s)
 }
 
@@ -232,6 +239,25 @@ func (t *Tensor) Shape() []int {
 	return shape
 }
 
+// ShapeSignature return the shape the model was exported with. Dynamic dimensions are -1.
+func (t *Tensor) ShapeSignature() []int {
+	shape := make([]int, t.NumDims())
+	for i := 0; i < t.NumDims(); i++ {
+		shape[i] = int(C._TfLiteTensorDimSignature(t.t, C.int(i)))
+	}
+	return shape
+}
+
+// Dynamic return true if any dimension of the tensor is dynamic.
+func (t *Tensor) Dynamic() bool {
+	for _, dim := range t.ShapeSignature() {
+		if dim < 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // QuantizationParams implement TfLiteQuantizationParams.
 type QuantizationParams struct {
 	Scale     float64
@@ -247,6 +273,92 @@ func (t *Tensor) QuantizationParams() QuantizationParams {
 	}
 }
 
+// AffineQuantization is the per-tensor or per-channel quantization of a tensor.
+type AffineQuantization struct {
+	Scale     []float32
+	ZeroPoint []int32
+	// The dimension of the channels for per-channel quantization
+	QuantizedDimension int
+}
+
+// AffineQuantization return the quantization of the tensor, nil if it isn't quantized.
+func (t *Tensor) AffineQuantization() *AffineQuantization {
+	channels := int(C._TfLiteTensorQuantizationChannels(t.t))
+	if channels == 0 {
+		// Fall back to the per-tensor parameters
+		q := t.QuantizationParams()
+		if q.Scale == 0 {
+			return nil
+		}
+		return &AffineQuantization{
+			Scale:     []float32{float32(q.Scale)},
+			ZeroPoint: []int32{int32(q.ZeroPoint)},
+		}
+	}
+	q := &AffineQuantization{
+		Scale:              make([]float32, channels),
+		ZeroPoint:          make([]int32, channels),
+		QuantizedDimension: int(C._TfLiteTensorQuantizedDimension(t.t)),
+	}
+	for i := 0; i < channels; i++ {
+		q.Scale[i] = float32(C._TfLiteTensorQuantizationScale(t.t, C.int(i)))
+		q.ZeroPoint[i] = int32(C._TfLiteTensorQuantizationZeroPoint(t.t, C.int(i)))
+	}
+	return q
+}
+
+// Dequantize return the tensor values as float32s. Quantized tensors are converted with (value - zero point) * scale
+// using the per-tensor or per-channel parameters. Tensors without quantization are converted as is.
+func (t *Tensor) Dequantize() ([]float32, error) {
+
+	var values []float32
+	switch t.Type() {
+	case Float32:
+		return append([]float32(nil), t.Float32s()...), nil
+	case UInt8:
+		for _, v := range t.UInt8s() {
+			values = append(values, float32(v))
+		}
+	case Int8:
+		for _, v := range t.Int8s() {
+			values = append(values, float32(v))
+		}
+	case Int16:
+		for _, v := range t.Int16s() {
+			values = append(values, float32(v))
+		}
+	case Int32:
+		for _, v := range t.Int32s() {
+			values = append(values, float32(v))
+		}
+	default:
+		return nil, ErrTypeMismatch
+	}
+
+	q := t.AffineQuantization()
+	if q == nil {
+		return values, nil
+	}
+
+	// The number of values for each step of the quantized dimension
+	stride := 1
+	if len(q.Scale) > 1 {
+		if q.QuantizedDimension >= t.NumDims() || t.Dim(q.QuantizedDimension) != len(q.Scale) {
+			return nil, ErrBadTensor
+		}
+		for i := q.QuantizedDimension + 1; i < t.NumDims(); i++ {
+			stride *= t.Dim(i)
+		}
+	}
+
+	for i := range values {
+		channel := (i / stride) % len(q.Scale)
+		values[i] = (values[i] - float32(q.ZeroPoint[channel])) * q.Scale[channel]
+	}
+	return values, nil
+
+}
+
 // CopyFromBuffer write buffer to the tensor.
 func (t *Tensor) CopyFromBuffer(b interface{}) Status {
 	return Status(C.TfLiteTensorCopyFromBuffer(t.t, unsafe.Pointer(reflect.ValueOf(b).Pointer()), C.size_t(t.ByteSize())))
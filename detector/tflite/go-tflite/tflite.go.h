@@ -6,6 +6,7 @@
 #include <stdarg.h>
 #include <stdlib.h>
 #include <tensorflow/lite/c/c_api.h>
+#include <tensorflow/lite/c/common.h>
 
 extern void _go_error_reporter(void*, char*);
 
@@ -21,4 +22,44 @@ static void
 _TfLiteInterpreterOptionsSetErrorReporter(TfLiteInterpreterOptions* options, void* user_data) {
   TfLiteInterpreterOptionsSetErrorReporter(options, _error_reporter, user_data);
 }
+
+static int
+_TfLiteTensorDimSignature(const TfLiteTensor* tensor, int index) {
+  if (tensor->dims_signature == NULL || tensor->dims_signature->size != tensor->dims->size) return tensor->dims->data[index];
+  return tensor->dims_signature->data[index];
+}
+
+static const TfLiteAffineQuantization*
+_TfLiteTensorAffineQuantization(const TfLiteTensor* tensor) {
+  if (tensor->quantization.type != kTfLiteAffineQuantization) return NULL;
+  return (const TfLiteAffineQuantization*)(tensor->quantization.params);
+}
+
+static int
+_TfLiteTensorQuantizationChannels(const TfLiteTensor* tensor) {
+  const TfLiteAffineQuantization* q = _TfLiteTensorAffineQuantization(tensor);
+  if (q == NULL || q->scale == NULL) return 0;
+  return q->scale->size;
+}
+
+static float
+_TfLiteTensorQuantizationScale(const TfLiteTensor* tensor, int index) {
+  const TfLiteAffineQuantization* q = _TfLiteTensorAffineQuantization(tensor);
+  if (q == NULL || q->scale == NULL || index >= q->scale->size) return 0;
+  return q->scale->data[index];
+}
+
+static int32_t
+_TfLiteTensorQuantizationZeroPoint(const TfLiteTensor* tensor, int index) {
+  const TfLiteAffineQuantization* q = _TfLiteTensorAffineQuantization(tensor);
+  if (q == NULL || q->zero_point == NULL || index >= q->zero_point->size) return 0;
+  return q->zero_point->data[index];
+}
+
+static int32_t
+_TfLiteTensorQuantizedDimension(const TfLiteTensor* tensor) {
+  const TfLiteAffineQuantization* q = _TfLiteTensorAffineQuantization(tensor);
+  if (q == NULL) return 0;
+  return q->quantized_dimension;
+}
 #endif
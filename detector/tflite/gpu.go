@@ -0,0 +1,39 @@
+//go:build gpu && cgo
+// +build gpu,cgo
+
+package tflite
+
+import (
+	"fmt"
+
+	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates"
+	"github.com/snowzach/doods/detector/tflite/go-tflite/delegates/gpu"
+)
+
+func init() {
+	delegateFactories["gpu"] = newGPUDelegate
+}
+
+// newGPUDelegate creates the GPU delegate. The backend option is auto (default, OpenCL then OpenGL ES), opencl or opengl.
+func newGPUDelegate(options map[string]string) (delegates.Delegater, error) {
+
+	var o gpu.Options
+	switch options["backend"] {
+	case "", "auto":
+		o.Backend = gpu.BackendAuto
+	case "opencl":
+		o.Backend = gpu.BackendOpenCL
+	case "opengl":
+		o.Backend = gpu.BackendOpenGL
+	case "vulkan":
+		return nil, fmt.Errorf("the tflite gpu delegate has no vulkan backend, use opengl for drivers without opencl")
+	default:
+		return nil, fmt.Errorf("invalid backend: %s", options["backend"])
+	}
+	o.AllowPrecisionLoss = options["precision"] == "fp16"
+	o.EnableQuant = options["quantized"] != "false"
+	o.SustainedSpeed = options["sustained"] == "true"
+
+	return gpu.New(o), nil
+
+}
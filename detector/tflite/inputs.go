@@ -0,0 +1,188 @@
+//go:build cgo
+// +build cgo
+
+package tflite
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/tflite/go-tflite"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The names of image input tensors if there isn't one configured
+var imageInputNames = map[string]struct{}{
+	"normalized_input_image_tensor": {},
+	"image":                         {},
+	"input_1":                       {},
+}
+
+// modelInput is an extra input tensor fed from the request or the configured defaults
+type modelInput struct {
+	index  int
+	name   string
+	field  string
+	values []float32
+	data   []byte
+}
+
+// findImageInput returns the index of the image input tensor
+func (d *detector) findImageInput(interpreter *tflite.Interpreter) (int, error) {
+	count := interpreter.GetInputTensorCount()
+	for x := 0; x < count; x++ {
+		name := interpreter.GetInputTensor(x).Name()
+		if d.imageInputName != "" {
+			if name == d.imageInputName {
+				return x, nil
+			}
+		} else if _, ok := imageInputNames[name]; ok {
+			return x, nil
+		}
+	}
+	if count == 1 {
+		return 0, fmt.Errorf("unsupported input tensor name: %s", interpreter.GetInputTensor(0).Name())
+	}
+	return 0, fmt.Errorf("could not find the image input tensor, set imageInput")
+}
+
+// loadInputs configures the inputs other than the image
+func (d *detector) loadInputs(interpreter *tflite.Interpreter, configs []*dconfig.InputConfig) error {
+
+	byName := make(map[string]*dconfig.InputConfig)
+	for _, c := range configs {
+		byName[c.Name] = c
+	}
+
+	for x := 0; x < interpreter.GetInputTensorCount(); x++ {
+		if x == d.imageInput {
+			continue
+		}
+		tensor := interpreter.GetInputTensor(x)
+		c, ok := byName[tensor.Name()]
+		if !ok {
+			return fmt.Errorf("missing config for input tensor %s", tensor.Name())
+		}
+
+		in := &modelInput{
+			index:  x,
+			name:   c.Name,
+			field:  c.Field,
+			values: c.Values,
+		}
+		if in.field == "" {
+			in.field = c.Name
+		}
+		if c.File != "" {
+			var err error
+			if in.data, err = ioutil.ReadFile(c.File); err != nil {
+				return fmt.Errorf("could not read input %s: %v", c.Name, err)
+			}
+		}
+		// Make sure the defaults fit
+		if in.data != nil || in.values != nil {
+			if err := in.check(tensor, in.values, in.data); err != nil {
+				return fmt.Errorf("invalid input %s: %v", c.Name, err)
+			}
+		}
+
+		d.inputs = append(d.inputs, in)
+		d.config.Inputs = append(d.config.Inputs, in.field)
+	}
+
+	return nil
+
+}
+
+// check returns an error if the values or data don't fit the tensor
+func (in *modelInput) check(tensor *tflite.Tensor, values []float32, data []byte) error {
+	if len(data) > 0 {
+		if uint(len(data)) != tensor.ByteSize() {
+			return fmt.Errorf("has %d bytes, expected %d", len(data), tensor.ByteSize())
+		}
+		return nil
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("no data")
+	}
+	if size := elementSize(tensor.Type()); size == 0 {
+		return fmt.Errorf("unsupported tensor type %s for values", tensor.Type())
+	} else if uint(len(values)*size) != tensor.ByteSize() {
+		return fmt.Errorf("has %d values, expected %d", len(values), tensor.ByteSize()/uint(size))
+	}
+	return nil
+}
+
+// fill copies the input from the request, or the default, to the tensor
+func (in *modelInput) fill(tensor *tflite.Tensor, input *odrpc.InputTensor) error {
+
+	values, data := in.values, in.data
+	if input != nil {
+		values, data = input.Values, []byte(input.Data)
+	}
+	if err := in.check(tensor, values, data); err != nil {
+		return err
+	}
+
+	if len(data) > 0 {
+		if status := tensor.CopyFromBuffer(data); status != tflite.OK {
+			return fmt.Errorf("could not copy data")
+		}
+		return nil
+	}
+
+	// Quantize the values for quantized integer tensors
+	q := tensor.QuantizationParams()
+	quantize := func(v float32) float64 {
+		if q.Scale == 0 {
+			return math.Round(float64(v))
+		}
+		return math.Round(float64(v)/q.Scale) + float64(q.ZeroPoint)
+	}
+
+	switch tensor.Type() {
+	case tflite.Float32:
+		return tensor.SetFloat32s(values)
+	case tflite.Int32:
+		converted := make([]int32, len(values))
+		for i, v := range values {
+			converted[i] = int32(quantize(v))
+		}
+		return tensor.SetInt32s(converted)
+	case tflite.Int64:
+		converted := make([]int64, len(values))
+		for i, v := range values {
+			converted[i] = int64(quantize(v))
+		}
+		return tensor.SetInt64s(converted)
+	case tflite.UInt8:
+		converted := make([]uint8, len(values))
+		for i, v := range values {
+			converted[i] = uint8(math.Max(0, math.Min(math.MaxUint8, quantize(v))))
+		}
+		return tensor.SetUint8s(converted)
+	case tflite.Int8:
+		converted := make([]int8, len(values))
+		for i, v := range values {
+			converted[i] = int8(math.Max(math.MinInt8, math.Min(math.MaxInt8, quantize(v))))
+		}
+		return tensor.SetInt8s(converted)
+	}
+	return fmt.Errorf("unsupported tensor type %s for values", tensor.Type())
+
+}
+
+// elementSize returns the size of a tensor element for the types that can be set from values
+func elementSize(t tflite.TensorType) int {
+	switch t {
+	case tflite.UInt8, tflite.Int8:
+		return 1
+	case tflite.Float32, tflite.Int32:
+		return 4
+	case tflite.Int64:
+		return 8
+	}
+	return 0
+}
@@ -0,0 +1,45 @@
+//go:build !cgo
+// +build !cgo
+
+package tflite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/gotflite"
+	"github.com/snowzach/doods/detector/labels"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// detector is the pure Go interpreter when doods is built without cgo
+type detector interface {
+	Config() *odrpc.Detector
+	Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error)
+	Labels() labels.Labels
+	Shutdown()
+}
+
+// New runs the model with the pure Go interpreter, there are no delegates without the TensorFlow Lite library
+func New(lc *conf.Lifecycle, c *dconfig.DetectorConfig) (detector, error) {
+	if c.HWAccel {
+		return nil, fmt.Errorf("doods was built without cgo, hwAccel is not supported")
+	}
+	d, err := gotflite.New(lc, c)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// EdgeTPUDevices returns no devices without the TensorFlow Lite library
+func EdgeTPUDevices() []string {
+	return nil
+}
+
+// HasDelegate returns false, no delegates are built in without cgo
+func HasDelegate(name string) bool {
+	return false
+}
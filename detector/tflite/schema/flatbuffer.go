@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// fbTable is a minimal read only flatbuffers table
+type fbTable struct {
+	buf []byte
+	pos int
+}
+
+func fbRoot(buf []byte) fbTable {
+	return fbTable{buf: buf, pos: int(binary.LittleEndian.Uint32(buf))}
+}
+
+// field returns the absolute position of a field or 0 if it's not present
+func (t fbTable) field(id int) int {
+	vtable := t.pos - int(int32(binary.LittleEndian.Uint32(t.buf[t.pos:])))
+	slot := 4 + 2*id
+	if slot >= int(binary.LittleEndian.Uint16(t.buf[vtable:])) {
+		return 0
+	}
+	offset := int(binary.LittleEndian.Uint16(t.buf[vtable+slot:]))
+	if offset == 0 {
+		return 0
+	}
+	return t.pos + offset
+}
+
+// end checks that the vtable and the table are in the buffer and returns the end of the table
+func (t fbTable) end() int {
+	vtable := t.pos - int(int32(binary.LittleEndian.Uint32(t.buf[t.pos:])))
+	if vtable < 0 || vtable+4 > len(t.buf) || vtable+int(binary.LittleEndian.Uint16(t.buf[vtable:])) > len(t.buf) {
+		panic("vtable out of range")
+	}
+	end := t.pos + int(binary.LittleEndian.Uint16(t.buf[vtable+2:]))
+	if end > len(t.buf) {
+		panic("table out of range")
+	}
+	return end
+}
+
+// indirect follows the offset stored at pos
+func (t fbTable) indirect(pos int) int {
+	return pos + int(binary.LittleEndian.Uint32(t.buf[pos:]))
+}
+
+func (t fbTable) uint8(id int) uint8 {
+	if p := t.field(id); p != 0 {
+		return t.buf[p]
+	}
+	return 0
+}
+
+func (t fbTable) int8(id int, def int8) int8 {
+	if p := t.field(id); p != 0 {
+		return int8(t.buf[p])
+	}
+	return def
+}
+
+func (t fbTable) bool(id int) bool {
+	return t.uint8(id) != 0
+}
+
+func (t fbTable) int32(id int, def int32) int32 {
+	if p := t.field(id); p != 0 {
+		return int32(binary.LittleEndian.Uint32(t.buf[p:]))
+	}
+	return def
+}
+
+func (t fbTable) uint64(id int) uint64 {
+	if p := t.field(id); p != 0 {
+		return binary.LittleEndian.Uint64(t.buf[p:])
+	}
+	return 0
+}
+
+func (t fbTable) float32(id int, def float32) float32 {
+	if p := t.field(id); p != 0 {
+		return math.Float32frombits(binary.LittleEndian.Uint32(t.buf[p:]))
+	}
+	return def
+}
+
+func (t fbTable) uint32(id int) uint32 {
+	if p := t.field(id); p != 0 {
+		return binary.LittleEndian.Uint32(t.buf[p:])
+	}
+	return 0
+}
+
+func (t fbTable) table(id int) (fbTable, bool) {
+	if p := t.field(id); p != 0 {
+		return fbTable{buf: t.buf, pos: t.indirect(p)}, true
+	}
+	return fbTable{}, false
+}
+
+// vector returns the position of the first element and the length of a vector. The length is checked against the
+// buffer so a corrupt length can't allocate more than the buffer size.
+func (t fbTable) vector(id int) (int, int) {
+	if p := t.field(id); p != 0 {
+		v := t.indirect(p)
+		n := int(binary.LittleEndian.Uint32(t.buf[v:]))
+		if n > len(t.buf)-v-4 {
+			panic("vector out of range")
+		}
+		return v + 4, n
+	}
+	return 0, 0
+}
+
+func (t fbTable) vectorLen(id int) int {
+	_, n := t.vector(id)
+	return n
+}
+
+func (t fbTable) tableAt(id int, i int) fbTable {
+	start, _ := t.vector(id)
+	return fbTable{buf: t.buf, pos: t.indirect(start + 4*i)}
+}
+
+func (t fbTable) bytes(id int) []byte {
+	start, n := t.vector(id)
+	return t.buf[start : start+n]
+}
+
+func (t fbTable) string(id int) string {
+	return string(t.bytes(id))
+}
+
+func (t fbTable) floats(id int) []float32 {
+	start, n := t.vector(id)
+	data := t.buf[start : start+4*n]
+	ret := make([]float32, n)
+	for i := range ret {
+		ret[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return ret
+}
+
+func (t fbTable) ints(id int) []int {
+	start, n := t.vector(id)
+	data := t.buf[start : start+4*n]
+	ret := make([]int, n)
+	for i := range ret {
+		ret[i] = int(int32(binary.LittleEndian.Uint32(data[4*i:])))
+	}
+	return ret
+}
+
+func (t fbTable) int64s(id int) []int64 {
+	start, n := t.vector(id)
+	data := t.buf[start : start+8*n]
+	ret := make([]int64, n)
+	for i := range ret {
+		ret[i] = int64(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	return ret
+}
@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// FlexBuffers value types
+const (
+	flexInt           = 1
+	flexUInt          = 2
+	flexFloat         = 3
+	flexIndirectInt   = 6
+	flexIndirectUInt  = 7
+	flexIndirectFloat = 8
+	flexMap           = 9
+	flexBool          = 26
+)
+
+// CustomOptions parses the FlexBuffers map of a custom operator's options. Only the number and bool values are
+// returned, bools as 0 or 1.
+func CustomOptions(buf []byte) (options map[string]float64, err error) {
+
+	if len(buf) < 3 {
+		return nil, fmt.Errorf("invalid custom options")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			options = nil
+			err = fmt.Errorf("invalid custom options: %v", r)
+		}
+	}()
+
+	// The root is at the end: the value, its packed type and the width of the value
+	rootWidth := int(buf[len(buf)-1])
+	rootType := buf[len(buf)-2]
+	rootPos := len(buf) - 2 - rootWidth
+	if rootType>>2 != flexMap {
+		return nil, fmt.Errorf("custom options are not a map")
+	}
+	mapPos := rootPos - int(flexUint(buf, rootPos, rootWidth))
+	width := 1 << (rootType & 3)
+
+	// Before the values are the keys vector offset, the keys width and the length
+	size := int(flexUint(buf, mapPos-width, width))
+	keysPos := mapPos - 3*width - int(flexUint(buf, mapPos-3*width, width))
+	keysWidth := int(flexUint(buf, mapPos-2*width, width))
+	types := mapPos + size*width
+
+	options = make(map[string]float64, size)
+	for i := 0; i < size; i++ {
+		keyPos := keysPos + i*keysWidth - int(flexUint(buf, keysPos+i*keysWidth, keysWidth))
+		end := keyPos
+		for buf[end] != 0 {
+			end++
+		}
+		key := string(buf[keyPos:end])
+
+		pos := mapPos + i*width
+		packed := buf[types+i]
+		switch packed >> 2 {
+		case flexInt:
+			options[key] = float64(flexInt64(buf, pos, width))
+		case flexUInt, flexBool:
+			options[key] = float64(flexUint(buf, pos, width))
+		case flexFloat:
+			options[key] = flexFloat64(buf, pos, width)
+		case flexIndirectInt:
+			options[key] = float64(flexInt64(buf, pos-int(flexUint(buf, pos, width)), 1<<(packed&3)))
+		case flexIndirectUInt:
+			options[key] = float64(flexUint(buf, pos-int(flexUint(buf, pos, width)), 1<<(packed&3)))
+		case flexIndirectFloat:
+			options[key] = flexFloat64(buf, pos-int(flexUint(buf, pos, width)), 1<<(packed&3))
+		}
+	}
+
+	return options, nil
+
+}
+
+func flexUint(buf []byte, pos int, width int) uint64 {
+	switch width {
+	case 1:
+		return uint64(buf[pos])
+	case 2:
+		return uint64(binary.LittleEndian.Uint16(buf[pos:]))
+	case 4:
+		return uint64(binary.LittleEndian.Uint32(buf[pos:]))
+	}
+	return binary.LittleEndian.Uint64(buf[pos:])
+}
+
+func flexInt64(buf []byte, pos int, width int) int64 {
+	switch width {
+	case 1:
+		return int64(int8(buf[pos]))
+	case 2:
+		return int64(int16(binary.LittleEndian.Uint16(buf[pos:])))
+	case 4:
+		return int64(int32(binary.LittleEndian.Uint32(buf[pos:])))
+	}
+	return int64(binary.LittleEndian.Uint64(buf[pos:]))
+}
+
+func flexFloat64(buf []byte, pos int, width int) float64 {
+	if width == 4 {
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(buf[pos:])))
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(buf[pos:]))
+}
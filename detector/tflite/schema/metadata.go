@@ -0,0 +1,136 @@
+package schema
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+const (
+	// The name of the metadata buffer in the model
+	metadataName = "TFLITE_METADATA"
+
+	// Associated file types
+	associatedFileTensorAxisLabels  = 2
+	associatedFileTensorValueLabels = 3
+
+	// Union types
+	processUnitNormalizationOptions = 1
+	contentPropertiesImage          = 2
+)
+
+// Metadata is the information we use from the metadata embedded in a TFLite model
+type Metadata struct {
+	Name string
+	// The associated file with the default labels
+	LabelFile string
+	// Associated label files by locale
+	LocaleLabelFiles map[string]string
+	// Normalization parameters for the input tensor (float models)
+	Mean []float32
+	Std  []float32
+	// The default input image size
+	Width  int
+	Height int
+}
+
+// ReadMetadata reads the metadata from a TFLite model file. It returns nil if there is no metadata.
+func ReadMetadata(modelFile string) (*Metadata, error) {
+	buf, err := ioutil.ReadFile(modelFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read model %s: %v", modelFile, err)
+	}
+	return ParseMetadata(buf)
+}
+
+// ParseMetadata reads the metadata from a TFLite model. It returns nil if there is no metadata.
+func ParseMetadata(buf []byte) (md *Metadata, err error) {
+
+	// Any out of bounds access means the flatbuffer is corrupt
+	defer func() {
+		if r := recover(); r != nil {
+			md = nil
+			err = fmt.Errorf("invalid model metadata: %v", r)
+		}
+	}()
+
+	model := fbRoot(buf)
+
+	// Find the metadata buffer
+	var mdBuf []byte
+	for i, n := 0, model.vectorLen(6); i < n; i++ {
+		m := model.tableAt(6, i)
+		if m.string(0) == metadataName {
+			bufferIndex := int(m.uint32(1))
+			if bufferIndex < model.vectorLen(4) {
+				mdBuf = model.tableAt(4, bufferIndex).bytes(0)
+			}
+			break
+		}
+	}
+	if len(mdBuf) == 0 {
+		return nil, nil
+	}
+
+	mm := fbRoot(mdBuf)
+	md = &Metadata{
+		Name:             mm.string(0),
+		LocaleLabelFiles: make(map[string]string),
+	}
+
+	if mm.vectorLen(3) == 0 {
+		return md, nil
+	}
+	subgraph := mm.tableAt(3, 0)
+
+	// Input tensor
+	if subgraph.vectorLen(2) > 0 {
+		input := subgraph.tableAt(2, 0)
+		// Content properties
+		if content, ok := input.table(3); ok && content.uint8(0) == contentPropertiesImage {
+			if imageProperties, ok := content.table(1); ok {
+				if size, ok := imageProperties.table(1); ok {
+					md.Width = int(size.uint32(0))
+					md.Height = int(size.uint32(1))
+				}
+			}
+		}
+		// Process units
+		for i, n := 0, input.vectorLen(4); i < n; i++ {
+			unit := input.tableAt(4, i)
+			if unit.uint8(0) != processUnitNormalizationOptions {
+				continue
+			}
+			if options, ok := unit.table(1); ok {
+				md.Mean = options.floats(0)
+				md.Std = options.floats(1)
+			}
+		}
+	}
+
+	// Label files are attached to output tensors
+	var firstLabelFile string
+	for i, n := 0, subgraph.vectorLen(3); i < n; i++ {
+		output := subgraph.tableAt(3, i)
+		for j, m := 0, output.vectorLen(6); j < m; j++ {
+			file := output.tableAt(6, j)
+			if fileType := file.uint8(2); fileType != associatedFileTensorAxisLabels && fileType != associatedFileTensorValueLabels {
+				continue
+			}
+			// Prefer the labels without a locale as the default
+			if locale := file.string(3); locale != "" {
+				md.LocaleLabelFiles[locale] = file.string(0)
+				if firstLabelFile == "" {
+					firstLabelFile = file.string(0)
+				}
+			} else if md.LabelFile == "" {
+				md.LabelFile = file.string(0)
+			}
+		}
+	}
+	if md.LabelFile == "" {
+		md.LabelFile = firstLabelFile
+	}
+
+	return md, nil
+
+}
@@ -0,0 +1,318 @@
+// Package schema reads TFLite model files without the TensorFlow Lite library: the model graph and the metadata.
+package schema
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+)
+
+// TensorType is the type of the tensor elements
+type TensorType int8
+
+const (
+	Float32 TensorType = 0
+	Float16 TensorType = 1
+	Int32   TensorType = 2
+	UInt8   TensorType = 3
+	Int64   TensorType = 4
+	String  TensorType = 5
+	Bool    TensorType = 6
+	Int16   TensorType = 7
+	Int8    TensorType = 9
+)
+
+func (t TensorType) String() string {
+	switch t {
+	case Float32:
+		return "Float32"
+	case Float16:
+		return "Float16"
+	case Int32:
+		return "Int32"
+	case UInt8:
+		return "UInt8"
+	case Int64:
+		return "Int64"
+	case String:
+		return "String"
+	case Bool:
+		return "Bool"
+	case Int16:
+		return "Int16"
+	case Int8:
+		return "Int8"
+	}
+	return fmt.Sprintf("Type(%d)", t)
+}
+
+// Operator is a builtin operator code
+type Operator int32
+
+// The builtin operators that have a name here, the rest are numbered
+const (
+	Add                   Operator = 0
+	AveragePool2D         Operator = 1
+	Concatenation         Operator = 2
+	Conv2D                Operator = 3
+	DepthwiseConv2D       Operator = 4
+	Dequantize            Operator = 6
+	FullyConnected        Operator = 9
+	Logistic              Operator = 14
+	MaxPool2D             Operator = 17
+	Mul                   Operator = 18
+	Relu                  Operator = 19
+	ReluN1To1             Operator = 20
+	Relu6                 Operator = 21
+	Reshape               Operator = 22
+	ResizeBilinear        Operator = 23
+	Softmax               Operator = 25
+	Tanh                  Operator = 28
+	Custom                Operator = 32
+	Pad                   Operator = 34
+	Transpose             Operator = 39
+	Mean                  Operator = 40
+	Sub                   Operator = 41
+	Div                   Operator = 42
+	Squeeze               Operator = 43
+	Exp                   Operator = 47
+	Maximum               Operator = 55
+	Minimum               Operator = 57
+	PadV2                 Operator = 60
+	ExpandDims            Operator = 70
+	ResizeNearestNeighbor Operator = 97
+	LeakyRelu             Operator = 98
+	SquaredDifference     Operator = 99
+	Quantize              Operator = 114
+	HardSwish             Operator = 117
+)
+
+var operatorNames = map[Operator]string{
+	Add: "ADD", AveragePool2D: "AVERAGE_POOL_2D", Concatenation: "CONCATENATION", Conv2D: "CONV_2D",
+	DepthwiseConv2D: "DEPTHWISE_CONV_2D", Dequantize: "DEQUANTIZE", FullyConnected: "FULLY_CONNECTED",
+	Logistic: "LOGISTIC", MaxPool2D: "MAX_POOL_2D", Mul: "MUL", Relu: "RELU", ReluN1To1: "RELU_N1_TO_1",
+	Relu6: "RELU6", Reshape: "RESHAPE", ResizeBilinear: "RESIZE_BILINEAR", Softmax: "SOFTMAX", Tanh: "TANH",
+	Custom: "CUSTOM", Pad: "PAD", Transpose: "TRANSPOSE", Mean: "MEAN", Sub: "SUB", Div: "DIV",
+	Squeeze: "SQUEEZE", Exp: "EXP", Maximum: "MAXIMUM", Minimum: "MINIMUM", PadV2: "PADV2",
+	ExpandDims: "EXPAND_DIMS", ResizeNearestNeighbor: "RESIZE_NEAREST_NEIGHBOR", LeakyRelu: "LEAKY_RELU",
+	SquaredDifference: "SQUARED_DIFFERENCE", Quantize: "QUANTIZE", HardSwish: "HARD_SWISH",
+}
+
+func (o Operator) String() string {
+	if name, ok := operatorNames[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("operator %d", int32(o))
+}
+
+// Model is the main subgraph of a TFLite model
+type Model struct {
+	Tensors []*Tensor
+	Nodes   []*Node
+	Inputs  []int
+	Outputs []int
+}
+
+// Tensor is a tensor of the model, Data is set for constant tensors
+type Tensor struct {
+	Name  string
+	Type  TensorType
+	Shape []int
+	Data  []byte
+	// Quantization, the scale and zero point are per channel of the quantized dimension when there is more than one
+	Scale              []float32
+	ZeroPoint          []int64
+	QuantizedDimension int
+}
+
+// Quantized returns true if the tensor has quantization parameters
+func (t *Tensor) Quantized() bool {
+	return len(t.Scale) > 0 && t.Scale[0] != 0
+}
+
+// Node is an operator in the graph. Inputs that are not used are -1.
+type Node struct {
+	Operator      Operator
+	CustomCode    string
+	Version       int
+	Inputs        []int
+	Outputs       []int
+	Options       Options
+	CustomOptions []byte
+}
+
+// Options are the builtin options of a node, read by field id from the schema. The fields are read within the table so
+// a corrupt table reads as the defaults.
+type Options struct {
+	t   fbTable
+	end int
+	ok  bool
+}
+
+// field returns the bytes of a field or nil if it's not set
+func (o Options) field(id int, size int) []byte {
+	if !o.ok {
+		return nil
+	}
+	if p := o.t.field(id); p != 0 && p+size <= o.end {
+		return o.t.buf[p : p+size]
+	}
+	return nil
+}
+
+// Int returns an integer option
+func (o Options) Int(id int, def int) int {
+	if b := o.field(id, 4); b != nil {
+		return int(int32(binary.LittleEndian.Uint32(b)))
+	}
+	return def
+}
+
+// Byte returns an enum option
+func (o Options) Byte(id int, def int) int {
+	if b := o.field(id, 1); b != nil {
+		return int(int8(b[0]))
+	}
+	return def
+}
+
+// Float returns a float option
+func (o Options) Float(id int, def float32) float32 {
+	if b := o.field(id, 4); b != nil {
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	}
+	return def
+}
+
+// Bool returns a bool option
+func (o Options) Bool(id int) bool {
+	b := o.field(id, 1)
+	return b != nil && b[0] != 0
+}
+
+// ReadModel reads the main subgraph of a TFLite model file
+func ReadModel(modelFile string) (*Model, error) {
+	buf, err := ioutil.ReadFile(modelFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read model %s: %v", modelFile, err)
+	}
+	return ParseModel(buf)
+}
+
+// ParseModel parses the main subgraph of a TFLite model
+func ParseModel(buf []byte) (m *Model, err error) {
+
+	if len(buf) < 8 || string(buf[4:8]) != "TFL3" {
+		return nil, fmt.Errorf("not a TFLite model")
+	}
+
+	// Any out of bounds access means the flatbuffer is corrupt
+	defer func() {
+		if r := recover(); r != nil {
+			m = nil
+			err = fmt.Errorf("invalid model: %v", r)
+		}
+	}()
+
+	model := fbRoot(buf)
+	if model.vectorLen(2) == 0 {
+		return nil, fmt.Errorf("model has no subgraphs")
+	}
+	subgraph := model.tableAt(2, 0)
+
+	m = &Model{
+		Inputs:  subgraph.ints(1),
+		Outputs: subgraph.ints(2),
+	}
+
+	buffers := model.vectorLen(4)
+	for i, n := 0, subgraph.vectorLen(0); i < n; i++ {
+		t := subgraph.tableAt(0, i)
+		tensor := &Tensor{
+			Name:  t.string(3),
+			Type:  TensorType(t.int8(1, 0)),
+			Shape: t.ints(0),
+		}
+		for _, d := range tensor.Shape {
+			if d < 0 {
+				return nil, fmt.Errorf("tensor %s has invalid shape %v", tensor.Name, tensor.Shape)
+			}
+		}
+		if b := int(t.uint32(2)); b > 0 && b < buffers {
+			buffer := model.tableAt(4, b)
+			tensor.Data = buffer.bytes(0)
+			// Models over 2GB have the data after the flatbuffer
+			if offset, size := buffer.uint64(1), buffer.uint64(2); len(tensor.Data) == 0 && offset > 1 {
+				if offset+size > uint64(len(buf)) {
+					return nil, fmt.Errorf("tensor %s data is out of range", tensor.Name)
+				}
+				tensor.Data = buf[offset : offset+size]
+			}
+			if len(tensor.Data) == 0 {
+				tensor.Data = nil
+			}
+		}
+		if q, ok := t.table(4); ok {
+			tensor.Scale = q.floats(2)
+			tensor.ZeroPoint = q.int64s(3)
+			tensor.QuantizedDimension = int(q.int32(6, 0))
+		}
+		m.Tensors = append(m.Tensors, tensor)
+	}
+
+	// The operator codes
+	type opCode struct {
+		op      Operator
+		custom  string
+		version int
+	}
+	codes := make([]opCode, model.vectorLen(1))
+	for i := range codes {
+		c := model.tableAt(1, i)
+		// Newer operators are only in the extended code, the old field has a placeholder (127)
+		op := Operator(c.int8(0, 0))
+		if extended := Operator(c.int32(3, 0)); extended > op {
+			op = extended
+		}
+		codes[i] = opCode{op: op, custom: c.string(1), version: int(c.int32(2, 1))}
+	}
+
+	for i, n := 0, subgraph.vectorLen(3); i < n; i++ {
+		o := subgraph.tableAt(3, i)
+		index := int(o.uint32(0))
+		if index >= len(codes) {
+			return nil, fmt.Errorf("operator %d has invalid opcode index %d", i, index)
+		}
+		node := &Node{
+			Operator:      codes[index].op,
+			CustomCode:    codes[index].custom,
+			Version:       codes[index].version,
+			Inputs:        o.ints(1),
+			Outputs:       o.ints(2),
+			CustomOptions: o.bytes(5),
+		}
+		if node.Options.t, node.Options.ok = o.table(4); node.Options.ok {
+			node.Options.end = node.Options.t.end()
+		}
+		for _, list := range [][]int{node.Inputs, node.Outputs} {
+			for _, t := range list {
+				if t >= len(m.Tensors) {
+					return nil, fmt.Errorf("operator %d (%s) uses invalid tensor %d", i, node.Operator, t)
+				}
+			}
+		}
+		m.Nodes = append(m.Nodes, node)
+	}
+
+	for _, list := range [][]int{m.Inputs, m.Outputs} {
+		for _, t := range list {
+			if t < 0 || t >= len(m.Tensors) {
+				return nil, fmt.Errorf("invalid subgraph tensor %d", t)
+			}
+		}
+	}
+
+	return m, nil
+
+}
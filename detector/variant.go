@@ -0,0 +1,102 @@
+package detector
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/cpu"
+
+	"github.com/snowzach/doods/detector/dconfig"
+	"github.com/snowzach/doods/detector/tflite"
+)
+
+// hardware is what the machine supports, it's probed once
+type hardware struct {
+	once     sync.Once
+	features map[string]string // feature -> why it's supported
+}
+
+var probed hardware
+
+// probe finds the supported hardware
+func (h *hardware) probe() map[string]string {
+	h.once.Do(func() {
+		h.features = make(map[string]string)
+		if devices := tflite.EdgeTPUDevices(); len(devices) > 0 {
+			h.features["edgetpu"] = fmt.Sprintf("%d edgetpu devices", len(devices))
+		}
+		if tflite.HasDelegate("gpu") {
+			for _, pattern := range []string{"/dev/dri/renderD*", "/dev/mali*", "/dev/nvidia[0-9]*"} {
+				if matches, _ := filepath.Glob(pattern); len(matches) > 0 {
+					h.features["gpu"] = "gpu delegate and " + matches[0]
+					break
+				}
+			}
+		}
+		if cpu.X86.HasAVX2 {
+			h.features["avx2"] = "cpu has avx2"
+		}
+		if cpu.X86.HasAVX512F {
+			h.features["avx512"] = "cpu has avx512"
+		}
+		if cpu.ARM64.HasASIMD || cpu.ARM.HasNEON {
+			h.features["neon"] = "cpu has neon"
+		}
+	})
+	return h.features
+}
+
+// selectVariant returns the config with the first variant the hardware supports and why it was picked
+func selectVariant(c *dconfig.DetectorConfig) (*dconfig.DetectorConfig, string, error) {
+
+	features := probed.probe()
+
+variants:
+	for i, v := range c.Variants {
+		var reasons []string
+		for _, feature := range strings.Split(v.Requires, ",") {
+			feature = strings.ToLower(strings.TrimSpace(feature))
+			if feature == "" {
+				continue
+			}
+			reason, ok := features[feature]
+			if !ok {
+				continue variants
+			}
+			reasons = append(reasons, reason)
+		}
+		if len(reasons) == 0 {
+			reasons = append(reasons, "no requirements")
+		}
+
+		selected := *c
+		selected.Variants = nil
+		if v.Type != "" {
+			selected.Type = v.Type
+		}
+		if v.ModelFile != "" {
+			selected.ModelFile = v.ModelFile
+		}
+		if v.LabelFile != "" {
+			selected.LabelFile = v.LabelFile
+		}
+		if v.HWAccel {
+			selected.HWAccel = true
+		}
+		if v.Delegate != "" {
+			selected.Delegate = v.Delegate
+		}
+		if v.NumThreads > 0 {
+			selected.NumThreads = v.NumThreads
+		}
+		if v.InputWidth > 0 && v.InputHeight > 0 {
+			selected.InputWidth, selected.InputHeight = v.InputWidth, v.InputHeight
+		}
+		return &selected, fmt.Sprintf("variant %d: %s", i, strings.Join(reasons, ", ")), nil
+	}
+
+	return nil, "", fmt.Errorf("no variant is supported by this hardware")
+
+}
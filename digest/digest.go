@@ -0,0 +1,157 @@
+// Package digest implements an http.RoundTripper for cameras that use basic or digest authentication
+package digest
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Transport adds basic or digest authentication to requests. Basic auth is sent until the server
+// responds with a digest challenge. The challenge is reused so there's only one request per call.
+type Transport struct {
+	Username string
+	Password string
+	// The underlying transport, http.DefaultTransport if nil
+	Transport http.RoundTripper
+
+	challenge map[string]string
+	nc        int
+	mu        sync.Mutex
+}
+
+// NewTransport creates a transport with the credentials
+func NewTransport(username string, password string) *Transport {
+	return &Transport{
+		Username: username,
+		Password: password,
+	}
+}
+
+// RoundTrip makes the request, retrying with digest authentication if challenged. Requests with
+// a body can't be retried.
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	response, err := transport.RoundTrip(t.authorize(request))
+	if err != nil || response.StatusCode != http.StatusUnauthorized || request.Body != nil {
+		return response, err
+	}
+
+	challenge := response.Header.Get("WWW-Authenticate")
+	if !strings.HasPrefix(strings.ToLower(challenge), "digest ") {
+		return response, nil
+	}
+	response.Body.Close()
+
+	t.mu.Lock()
+	t.challenge = parseChallenge(challenge[7:])
+	t.nc = 0
+	t.mu.Unlock()
+
+	return transport.RoundTrip(t.authorize(request))
+
+}
+
+// authorize returns a copy of the request with the Authorization header
+func (t *Transport) authorize(request *http.Request) *http.Request {
+
+	r := request.Clone(request.Context())
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.challenge == nil {
+		r.SetBasicAuth(t.Username, t.Password)
+		return r
+	}
+	t.nc++
+	r.Header.Set("Authorization", authorization(t.challenge, t.nc, t.Username, t.Password, r.Method, r.URL.RequestURI()))
+	return r
+
+}
+
+// parseChallenge parses the parameters of a digest WWW-Authenticate header
+func parseChallenge(challenge string) map[string]string {
+	params := make(map[string]string)
+	for _, part := range splitParams(challenge) {
+		if i := strings.Index(part, "="); i > 0 {
+			params[strings.ToLower(strings.TrimSpace(part[:i]))] = strings.Trim(strings.TrimSpace(part[i+1:]), `"`)
+		}
+	}
+	return params
+}
+
+// splitParams splits on commas that are not quoted
+func splitParams(s string) []string {
+	var parts []string
+	var quoted bool
+	start := 0
+	for i, c := range s {
+		switch c {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// authorization builds an RFC 2617 digest Authorization header (MD5)
+func authorization(challenge map[string]string, nc int, username string, password string, method string, uri string) string {
+
+	h := func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	cnonce := hex.EncodeToString(b)
+	ncValue := fmt.Sprintf("%08x", nc)
+
+	ha1 := h(username + ":" + challenge["realm"] + ":" + password)
+	if strings.EqualFold(challenge["algorithm"], "MD5-sess") {
+		ha1 = h(ha1 + ":" + challenge["nonce"] + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+
+	var response string
+	qop := ""
+	for _, q := range strings.Split(challenge["qop"], ",") {
+		if strings.TrimSpace(q) == "auth" {
+			qop = "auth"
+		}
+	}
+	if qop != "" {
+		response = h(ha1 + ":" + challenge["nonce"] + ":" + ncValue + ":" + cnonce + ":" + qop + ":" + ha2)
+	} else {
+		response = h(ha1 + ":" + challenge["nonce"] + ":" + ha2)
+	}
+
+	header := fmt.Sprintf(`Digest username="%s", realm="%s", nonce="%s", uri="%s", response="%s"`, username, challenge["realm"], challenge["nonce"], uri, response)
+	if qop != "" {
+		header += fmt.Sprintf(`, qop=%s, nc=%s, cnonce="%s"`, qop, ncValue, cnonce)
+	}
+	if challenge["opaque"] != "" {
+		header += fmt.Sprintf(`, opaque="%s"`, challenge["opaque"])
+	}
+	if challenge["algorithm"] != "" {
+		header += ", algorithm=" + challenge["algorithm"]
+	}
+
+	return header
+
+}
@@ -0,0 +1,51 @@
+// An example post-processor plugin for a model with a single output of [1, N, 6] (top, left, bottom, right, score, class)
+// Build with: go build -buildmode=plugin -o postprocess.so ./examples/postprocess
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/snowzach/doods/detector/postprocess"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// New is called by doods with the options from the config
+func New(options map[string]string) (postprocess.Func, error) {
+
+	minScore := float32(0.1)
+	if s, ok := options["min_score"]; ok {
+		v, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min_score: %v", err)
+		}
+		minScore = float32(v)
+	}
+
+	return func(outputs []*odrpc.OutputTensor, labels map[int]string) ([]*odrpc.Detection, error) {
+		if len(outputs) != 1 || len(outputs[0].Shape) != 3 || outputs[0].Shape[2] != 6 {
+			return nil, fmt.Errorf("expected one output of [1, N, 6]")
+		}
+		values := outputs[0].Values
+		detections := make([]*odrpc.Detection, 0)
+		for i := 0; i+6 <= len(values); i += 6 {
+			if values[i+4] < minScore {
+				continue
+			}
+			label, ok := labels[int(values[i+5])]
+			if !ok {
+				label = "unknown"
+			}
+			detections = append(detections, &odrpc.Detection{
+				Top:        values[i],
+				Left:       values[i+1],
+				Bottom:     values[i+2],
+				Right:      values[i+3],
+				Label:      label,
+				Confidence: values[i+4] * 100,
+			})
+		}
+		return detections, nil
+	}, nil
+
+}
@@ -0,0 +1,378 @@
+// Package feedback stores detections flagged as wrong as hard negatives and keeps false positive stats
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
+	"github.com/snowzach/doods/sink"
+	"github.com/snowzach/doods/zone"
+)
+
+// The minimum overlap for a flagged box to match a detection
+const matchIOU = 0.5
+
+// Sample is a detection flagged as a false positive
+type Sample struct {
+	ID        string    `json:"id"`
+	RequestID string    `json:"request_id,omitempty"`
+	Time      time.Time `json:"time"`
+	Flagged   time.Time `json:"flagged"`
+	Source    string    `json:"source"`
+	Detector  string    `json:"detector"`
+	// The regions the center of the detection is in
+	Zones     []string         `json:"zones,omitempty"`
+	Detection *odrpc.Detection `json:"detection"`
+	Comment   string           `json:"comment,omitempty"`
+}
+
+// Flag is a request to flag a detection as wrong. The frame is found with the request ID for recent
+// detections, otherwise the image has to be included.
+type Flag struct {
+	RequestID string           `json:"id"`
+	Detection *odrpc.Detection `json:"detection"`
+	Comment   string           `json:"comment"`
+	// For detections that aren't recent
+	Image    []byte `json:"image"`
+	Source   string `json:"source"`
+	Detector string `json:"detector"`
+}
+
+// Store keeps recent detections so they can be flagged and saves the flagged samples
+type Store struct {
+	dir  string
+	sink string
+
+	// Recent events by request id
+	recent      map[string]*sink.Event
+	recentOrder []string
+	recentNext  int
+
+	samples map[string]*Sample
+	lock    sync.Mutex
+
+	zones  *zone.Store
+	sinks  *sink.Manager
+	keys   *server.AuthKeys
+	logger *zap.SugaredLogger
+}
+
+// New creates the feedback store, it returns nil if it's not configured
+func New(zones *zone.Store, sinks *sink.Manager) *Store {
+
+	dir := config.GetString("doods.feedback.dir")
+	if dir == "" {
+		return nil
+	}
+
+	s := &Store{
+		dir:         dir,
+		sink:        config.GetString("doods.feedback.sink"),
+		recent:      make(map[string]*sink.Event),
+		recentOrder: make([]string, config.GetInt("doods.feedback.recent")),
+		samples:     make(map[string]*Sample),
+		zones:       zones,
+		sinks:       sinks,
+		keys:        server.Keys(),
+		logger:      zap.S().With("package", "feedback"),
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		s.logger.Fatalf("Could not create feedback dir: %v", err)
+	}
+	if err := s.load(); err != nil {
+		s.logger.Fatalf("Could not load feedback: %v", err)
+	}
+
+	s.logger.Infow("Feedback Store", "dir", dir, "samples", len(s.samples))
+
+	return s
+
+}
+
+// load reads the saved samples
+func (s *Store) load() error {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, filename := range files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		sample := new(Sample)
+		if err := json.Unmarshal(data, sample); err != nil {
+			return fmt.Errorf("could not parse %s: %v", filename, err)
+		}
+		s.samples[sample.ID] = sample
+	}
+	return nil
+}
+
+// Record keeps the event so its detections can be flagged by request id
+func (s *Store) Record(e *sink.Event) {
+	if s == nil || e.ID == "" || len(s.recentOrder) == 0 || len(e.Response.Detections) == 0 || e.Private {
+		return
+	}
+
+	// Copy the detections, the labels are translated in place after
+	detections := make([]*odrpc.Detection, 0, len(e.Response.Detections))
+	for _, d := range e.Response.Detections {
+		dc := *d
+		detections = append(detections, &dc)
+	}
+	r := &sink.Event{
+		Time:     e.Time,
+		ID:       e.ID,
+		Source:   e.Source,
+		Detector: e.Detector,
+		Image:    e.Image,
+		Response: &odrpc.DetectResponse{Id: e.ID, Detections: detections},
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Replace the oldest
+	if _, ok := s.recent[e.ID]; ok {
+		s.recent[e.ID] = r
+		return
+	}
+	if old := s.recentOrder[s.recentNext]; old != "" {
+		delete(s.recent, old)
+	}
+	s.recentOrder[s.recentNext] = e.ID
+	s.recentNext = (s.recentNext + 1) % len(s.recentOrder)
+	s.recent[e.ID] = r
+}
+
+// Flag saves a detection as a false positive
+func (s *Store) Flag(f *Flag) (*Sample, error) {
+
+	if f.Detection == nil {
+		return nil, fmt.Errorf("detection is required")
+	}
+
+	s.lock.Lock()
+	e, ok := s.recent[f.RequestID]
+	s.lock.Unlock()
+
+	sample := &Sample{
+		RequestID: f.RequestID,
+		Flagged:   time.Now(),
+		Detection: f.Detection,
+		Comment:   f.Comment,
+	}
+	sample.ID = strconv.FormatInt(sample.Flagged.UnixNano(), 10)
+
+	var image []byte
+	var others []*odrpc.Detection
+	if ok {
+		// Use the detection as it was returned by the model, the client may have translated labels
+		index := match(e.Response.Detections, f.Detection)
+		if index < 0 {
+			return nil, fmt.Errorf("detection not found in request %s", f.RequestID)
+		}
+		sample.Detection = e.Response.Detections[index]
+		sample.Time = e.Time
+		sample.Source = e.Source
+		sample.Detector = e.Detector
+		image = e.Image
+		for i, d := range e.Response.Detections {
+			if i != index {
+				others = append(others, d)
+			}
+		}
+	} else if len(f.Image) > 0 {
+		if f.Source == "" {
+			f.Source = f.Detector
+		}
+		sample.Time = sample.Flagged
+		sample.Source = f.Source
+		sample.Detector = f.Detector
+		image = f.Image
+	} else {
+		return nil, os.ErrNotExist
+	}
+
+	if sample.Detection.Label == "" {
+		return nil, fmt.Errorf("detection is missing a label")
+	}
+	sample.Zones = s.inZones(sample.Source, sample.Detector, sample.Detection)
+
+	if err := ioutil.WriteFile(s.imageFile(sample.ID), image, 0644); err != nil {
+		return nil, fmt.Errorf("could not save image: %v", err)
+	}
+	data, err := json.MarshalIndent(sample, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(s.sampleFile(sample.ID), data, 0644); err != nil {
+		return nil, fmt.Errorf("could not save sample: %v", err)
+	}
+
+	s.lock.Lock()
+	s.samples[sample.ID] = sample
+	s.lock.Unlock()
+
+	s.logger.Infow("False positive", "id", sample.ID, "request_id", sample.RequestID, "source", sample.Source, "label", sample.Detection.Label, "confidence", sample.Detection.Confidence)
+
+	// The frame without the wrong box is a hard negative for the dataset. Only recent frames are sent
+	// since the other objects in the frame aren't known otherwise.
+	if s.sink != "" && ok {
+		s.sinks.Send(&sink.Event{
+			Time:     sample.Time,
+			ID:       sample.ID,
+			Source:   sample.Source,
+			Detector: sample.Detector,
+			Image:    image,
+			Response: &odrpc.DetectResponse{
+				Id:         sample.RequestID,
+				Detections: others,
+			},
+			Labeled: true,
+			Sinks:   []string{s.sink},
+		})
+	}
+
+	return sample, nil
+
+}
+
+// match returns the index of the detection with the most overlap with the box or -1
+func match(detections []*odrpc.Detection, box *odrpc.Detection) int {
+	index := -1
+	var best float32 = matchIOU
+	for i, d := range detections {
+		if v := odrpc.IoU(d, box); v >= best {
+			index, best = i, v
+		}
+	}
+	return index
+}
+
+// inZones returns the regions that contain the center of the detection
+func (s *Store) inZones(source, detector string, d *odrpc.Detection) []string {
+	zones := s.zones.Get(zone.Target(zone.TargetStreams, source))
+	if zones == nil {
+		zones = s.zones.Get(zone.Target(zone.TargetDetectors, detector))
+	}
+	if zones == nil {
+		return nil
+	}
+	var names []string
+	x, y := (d.Left+d.Right)/2, (d.Top+d.Bottom)/2
+	for name, region := range zones.Regions {
+		if x >= region.Left && x <= region.Right && y >= region.Top && y <= region.Bottom {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Samples returns the flagged samples, oldest first
+func (s *Store) Samples() []*Sample {
+	s.lock.Lock()
+	samples := make([]*Sample, 0, len(s.samples))
+	for _, sample := range s.samples {
+		samples = append(samples, sample)
+	}
+	s.lock.Unlock()
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Flagged.Before(samples[j].Flagged) })
+	return samples
+}
+
+// Image returns the frame for a sample
+func (s *Store) Image(id string) ([]byte, error) {
+	s.lock.Lock()
+	_, ok := s.samples[id]
+	s.lock.Unlock()
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return ioutil.ReadFile(s.imageFile(id))
+}
+
+// Delete removes a sample and its image
+func (s *Store) Delete(id string) (bool, error) {
+	s.lock.Lock()
+	_, ok := s.samples[id]
+	delete(s.samples, id)
+	s.lock.Unlock()
+	if !ok {
+		return false, nil
+	}
+	for _, filename := range []string{s.imageFile(id), s.sampleFile(id)} {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// LabelStats are the false positives for a label
+type LabelStats struct {
+	FalsePositives int64   `json:"false_positives"`
+	AvgConfidence  float32 `json:"avg_confidence"`
+	MaxConfidence  float32 `json:"max_confidence"`
+	// The number of false positives in each 10% of confidence
+	Confidence [10]int64 `json:"confidence"`
+	// The number of false positives by source and by region
+	Sources map[string]int64 `json:"sources"`
+	Zones   map[string]int64 `json:"zones"`
+}
+
+// Stats returns the false positive stats by label
+func (s *Store) Stats() map[string]*LabelStats {
+	stats := make(map[string]*LabelStats)
+	for _, sample := range s.Samples() {
+		d := sample.Detection
+		ls, ok := stats[d.Label]
+		if !ok {
+			ls = &LabelStats{
+				Sources: make(map[string]int64),
+				Zones:   make(map[string]int64),
+			}
+			stats[d.Label] = ls
+		}
+		ls.FalsePositives++
+		ls.AvgConfidence += (d.Confidence - ls.AvgConfidence) / float32(ls.FalsePositives)
+		if d.Confidence > ls.MaxConfidence {
+			ls.MaxConfidence = d.Confidence
+		}
+		bucket := int(d.Confidence / 10)
+		if bucket < 0 {
+			bucket = 0
+		} else if bucket > 9 {
+			bucket = 9
+		}
+		ls.Confidence[bucket]++
+		ls.Sources[sample.Source]++
+		for _, z := range sample.Zones {
+			ls.Zones[z]++
+		}
+	}
+	return stats
+}
+
+func (s *Store) sampleFile(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *Store) imageFile(id string) string {
+	return filepath.Join(s.dir, id+".img")
+}
@@ -0,0 +1,91 @@
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/server"
+)
+
+// RegisterHTTP registers the feedback endpoints on the router
+func (s *Store) RegisterHTTP(r chi.Router) {
+	if s == nil {
+		return
+	}
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(s.keys))
+		r.Post("/feedback", s.handleFlag)
+		r.Get("/feedback", s.handleList)
+		r.Get("/feedback/stats", s.handleStats)
+		r.Delete("/feedback/{id}", s.handleDelete)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(server.ImageAuth(s.keys))
+		r.Get("/feedback/{id}/image", s.handleImage)
+	})
+}
+
+// handleFlag flags a detection as a false positive
+func (s *Store) handleFlag(w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	flag := new(Flag)
+	if err := json.Unmarshal(data, flag); err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(fmt.Errorf("could not parse request: %v", err)))
+		return
+	}
+	sample, err := s.Flag(flag)
+	if os.IsNotExist(err) {
+		// The request is too old or unknown and there's no image
+		render.Render(w, r, server.ErrNotFound)
+		return
+	} else if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	render.JSON(w, r, sample)
+}
+
+func (s *Store) handleList(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]interface{}{"samples": s.Samples()})
+}
+
+// handleStats returns the false positive stats by label
+func (s *Store) handleStats(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]interface{}{"labels": s.Stats()})
+}
+
+func (s *Store) handleImage(w http.ResponseWriter, r *http.Request) {
+	data, err := s.Image(chi.URLParam(r, "id"))
+	if os.IsNotExist(err) {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	} else if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Write(data)
+}
+
+func (s *Store) handleDelete(w http.ResponseWriter, r *http.Request) {
+	found, err := s.Delete(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	if !found {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
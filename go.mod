@@ -17,12 +17,15 @@ require (
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/viper v1.7.1
 	github.com/tensorflow/tensorflow v2.0.3+incompatible
+	go.starlark.net v0.0.0-20200306205701-8dd3e2ee1dd5
 	go.uber.org/zap v1.16.0
 	gocv.io/x/gocv v0.25.1-0.20201108120252-7f525fdbcb78
 	golang.org/x/image v0.0.0-20200927104501-e162460cd6b5
 	golang.org/x/net v0.0.0-20201110031124-69a78807bb2b
+	golang.org/x/sys v0.0.0-20200930185726-fdedc70b468f
 	google.golang.org/genproto v0.0.0-20201119123407-9b1e624d6bc4
 	google.golang.org/grpc v1.33.2
+	google.golang.org/protobuf v1.25.0
 )
 
 //replace github.com/tensorflow/tensorflow v2.3.1+incompatible => github.com/tensorflow/tensorflow v2.0.3+incompatible
@@ -0,0 +1,401 @@
+// Package history keeps the detections and their frames for a while. Each label can be kept for a different time and
+// the entries can be purged by time, source or label.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
+	"github.com/snowzach/doods/sink"
+)
+
+// How often the expired entries are deleted
+const expireInterval = time.Minute
+
+// How many entries can wait to be written
+const queueSize = 100
+
+// Entry is a detection event
+type Entry struct {
+	ID         string             `json:"id"`
+	RequestID  string             `json:"request_id,omitempty"`
+	Time       time.Time          `json:"time"`
+	Source     string             `json:"source"`
+	Detector   string             `json:"detector"`
+	Detections []*odrpc.Detection `json:"detections"`
+	// The frame was saved
+	Image bool `json:"image"`
+	// The signature of the detections if doods.signing is set
+	Signature *odrpc.Signature `json:"signature,omitempty"`
+	// When it's deleted
+	Expires time.Time `json:"expires"`
+}
+
+// Filter selects entries, empty fields match everything
+type Filter struct {
+	// From is inclusive, To is exclusive
+	From   time.Time
+	To     time.Time
+	Source string
+	// The entry has a detection with the label
+	Label string
+}
+
+// Store keeps the detection events on disk
+type Store struct {
+	dir       string
+	retention time.Duration
+	labels    map[string]time.Duration
+	sources   map[string]struct{}
+	images    bool
+
+	entries map[string]*Entry
+	lock    sync.Mutex
+
+	// The entries are written to disk in their own goroutine so a slow disk doesn't block detection
+	writes chan *write
+
+	keys   *server.AuthKeys
+	logger *zap.SugaredLogger
+}
+
+// New creates the history store, it returns nil if it's not configured
+func New(lc *conf.Lifecycle) *Store {
+
+	dir := config.GetString("doods.history.dir")
+	if dir == "" {
+		return nil
+	}
+
+	s := &Store{
+		dir:       dir,
+		retention: config.GetDuration("doods.history.retention"),
+		labels:    make(map[string]time.Duration),
+		images:    config.GetBool("doods.history.images"),
+		entries:   make(map[string]*Entry),
+		writes:    make(chan *write, queueSize),
+		keys:      server.Keys(),
+		logger:    zap.S().With("package", "history"),
+	}
+
+	// The config keys are lower case
+	for label, value := range config.GetStringMapString("doods.history.labels") {
+		retention, err := time.ParseDuration(value)
+		if err != nil {
+			s.logger.Fatalf("Invalid retention for label %s: %v", label, err)
+		}
+		s.labels[label] = retention
+	}
+	if sources := config.GetStringSlice("doods.history.sources"); len(sources) > 0 {
+		s.sources = make(map[string]struct{})
+		for _, source := range sources {
+			s.sources[source] = struct{}{}
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		s.logger.Fatalf("Could not create history dir: %v", err)
+	}
+	if err := s.load(); err != nil {
+		s.logger.Fatalf("Could not load history: %v", err)
+	}
+
+	// Delete the entries past their retention, including the ones that expired while doods was stopped
+	s.expire(time.Now())
+	lc.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(expireInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.expire(now)
+			}
+		}
+	})
+
+	lc.Go(s.run)
+
+	s.logger.Infow("History", "dir", dir, "retention", s.retention, "labels", len(s.labels), "entries", len(s.entries))
+
+	return s
+
+}
+
+// load reads the saved entries
+func (s *Store) load() error {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, filename := range files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		entry := new(Entry)
+		if err := json.Unmarshal(data, entry); err != nil {
+			return fmt.Errorf("could not parse %s: %v", filename, err)
+		}
+		s.entries[entry.ID] = entry
+	}
+	return nil
+}
+
+// retentionFor returns how long to keep detections with the labels, the shortest retention of the labels so a frame
+// isn't kept longer than any label in it allows
+func (s *Store) retentionFor(detections []*odrpc.Detection) time.Duration {
+	retention := time.Duration(-1)
+	for _, d := range detections {
+		r, ok := s.labels[strings.ToLower(d.Label)]
+		if !ok {
+			r = s.retention
+		}
+		if retention < 0 || r < retention {
+			retention = r
+		}
+	}
+	return retention
+}
+
+// write is an entry waiting to be saved
+type write struct {
+	entry *Entry
+	image []byte
+}
+
+// Record queues an event with detections to be saved, it's dropped if the queue is full. The detections are saved as
+// they're returned and signed. The frame isn't saved if the event is private.
+func (s *Store) Record(e *sink.Event) {
+	if s == nil || len(e.Response.Detections) == 0 {
+		return
+	}
+	if s.sources != nil {
+		if _, ok := s.sources[e.Source]; !ok {
+			return
+		}
+	}
+	retention := s.retentionFor(e.Response.Detections)
+	if retention <= 0 {
+		return
+	}
+
+	// Copy the detections, the caller can change them after
+	detections := make([]*odrpc.Detection, 0, len(e.Response.Detections))
+	for _, d := range e.Response.Detections {
+		dc := *d
+		detections = append(detections, &dc)
+	}
+	w := &write{
+		entry: &Entry{
+			ID:         strconv.FormatInt(e.Time.UnixNano(), 10),
+			RequestID:  e.ID,
+			Time:       e.Time,
+			Source:     e.Source,
+			Detector:   e.Detector,
+			Detections: detections,
+			Image:      s.images && len(e.Image) > 0 && !e.Private,
+			Signature:  e.Response.Signature,
+			Expires:    e.Time.Add(retention),
+		},
+	}
+	if w.entry.Image {
+		w.image = e.Image
+	}
+
+	select {
+	case s.writes <- w:
+	default:
+		s.logger.Warnw("History queue full, dropping event", "id", e.ID, "source", e.Source)
+	}
+}
+
+// run saves the queued entries until stopped, the entries still queued are saved before it returns
+func (s *Store) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case w := <-s.writes:
+					s.save(w)
+				default:
+					return
+				}
+			}
+		case w := <-s.writes:
+			s.save(w)
+		}
+	}
+}
+
+// save writes an entry and its frame, the entry is only listed once it's written
+func (s *Store) save(w *write) {
+	entry := w.entry
+	if entry.Image {
+		if err := ioutil.WriteFile(s.imageFile(entry.ID), w.image, 0600); err != nil {
+			s.logger.Errorf("Could not save history image: %v", err)
+			return
+		}
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(s.entryFile(entry.ID), data, 0600)
+	}
+	if err != nil {
+		s.logger.Errorf("Could not save history entry: %v", err)
+		os.Remove(s.imageFile(entry.ID))
+		return
+	}
+
+	s.lock.Lock()
+	s.entries[entry.ID] = entry
+	s.lock.Unlock()
+}
+
+// Entries returns the entries matching the filter, newest first
+func (s *Store) Entries(f *Filter) []*Entry {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ret := make([]*Entry, 0)
+	for _, entry := range s.entries {
+		if f.matches(entry) {
+			ret = append(ret, entry)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Time.After(ret[j].Time) })
+	return ret
+}
+
+// Get returns an entry
+func (s *Store) Get(id string) (*Entry, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	entry, ok := s.entries[id]
+	return entry, ok
+}
+
+// Image returns the frame of an entry
+func (s *Store) Image(id string) ([]byte, error) {
+	entry, ok := s.Get(id)
+	if !ok || !entry.Image {
+		return nil, os.ErrNotExist
+	}
+	return ioutil.ReadFile(s.imageFile(id))
+}
+
+// Delete removes an entry and its image
+func (s *Store) Delete(id string) (bool, error) {
+	s.lock.Lock()
+	_, ok := s.entries[id]
+	delete(s.entries, id)
+	s.lock.Unlock()
+	if !ok {
+		return false, nil
+	}
+	return true, s.remove(id)
+}
+
+// Purge deletes the entries matching the filter and returns how many were deleted
+func (s *Store) Purge(f *Filter) (int, error) {
+	s.lock.Lock()
+	var ids []string
+	for id, entry := range s.entries {
+		if f.matches(entry) {
+			ids = append(ids, id)
+			delete(s.entries, id)
+		}
+	}
+	s.lock.Unlock()
+
+	var ret error
+	for _, id := range ids {
+		if err := s.remove(id); err != nil {
+			ret = err
+		}
+	}
+	return len(ids), ret
+}
+
+// expire deletes the entries past their retention
+func (s *Store) expire(now time.Time) {
+	s.lock.Lock()
+	var ids []string
+	for id, entry := range s.entries {
+		if !now.Before(entry.Expires) {
+			ids = append(ids, id)
+			delete(s.entries, id)
+		}
+	}
+	s.lock.Unlock()
+
+	for _, id := range ids {
+		if err := s.remove(id); err != nil {
+			s.logger.Errorf("Could not delete history entry: %v", err)
+		}
+	}
+	if len(ids) > 0 {
+		s.logger.Debugw("Expired history", "entries", len(ids))
+	}
+}
+
+// remove deletes the files of an entry
+func (s *Store) remove(id string) error {
+	for _, filename := range []string{s.imageFile(id), s.entryFile(id)} {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) entryFile(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *Store) imageFile(id string) string {
+	return filepath.Join(s.dir, id+".img")
+}
+
+// matches returns true if the entry matches the filter
+func (f *Filter) matches(entry *Entry) bool {
+	if !f.From.IsZero() && entry.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !entry.Time.Before(f.To) {
+		return false
+	}
+	if f.Source != "" && entry.Source != f.Source {
+		return false
+	}
+	if f.Label != "" {
+		for _, d := range entry.Detections {
+			if d.Label == f.Label {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Empty returns true if the filter matches everything
+func (f *Filter) Empty() bool {
+	return f.From.IsZero() && f.To.IsZero() && f.Source == "" && f.Label == ""
+}
@@ -0,0 +1,104 @@
+package history
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/sink"
+)
+
+func testStore(dir string) *Store {
+	return &Store{
+		dir:       dir,
+		retention: time.Hour,
+		labels:    map[string]time.Duration{"person": time.Minute, "bird": 0},
+		images:    true,
+		entries:   make(map[string]*Entry),
+		writes:    make(chan *write, queueSize),
+		logger:    zap.S(),
+	}
+}
+
+func TestRecord(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s := testStore(dir)
+
+	now := time.Now()
+	sig := &odrpc.Signature{Algorithm: "hmac-sha256", Value: "c2ln"}
+	s.Record(&sink.Event{
+		Time:   now,
+		ID:     "request",
+		Source: "cam",
+		Image:  []byte("image"),
+		Response: &odrpc.DetectResponse{
+			Detections: []*odrpc.Detection{{Label: "person"}, {Label: "car"}},
+			Signature:  sig,
+		},
+	})
+	// Not kept, the label has no retention
+	s.Record(&sink.Event{Time: now.Add(time.Second), Response: &odrpc.DetectResponse{Detections: []*odrpc.Detection{{Label: "bird"}}}})
+	// Private frames aren't saved
+	s.Record(&sink.Event{Time: now.Add(2 * time.Second), Image: []byte("face"), Private: true, Response: &odrpc.DetectResponse{Detections: []*odrpc.Detection{{Label: "face"}}}})
+
+	// Nothing is listed until it's written
+	if entries := s.Entries(&Filter{}); len(entries) != 0 {
+		t.Fatalf("%d entries listed before they're written", len(entries))
+	}
+
+	// Stopping writes what's queued
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.run(ctx)
+
+	entries := s.Entries(&Filter{})
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, expected 2", len(entries))
+	}
+	entry := entries[1]
+	if entry.RequestID != "request" || !entry.Image || entry.Signature == nil || entry.Signature.Value != sig.Value {
+		t.Fatalf("invalid entry %+v", entry)
+	}
+	if !entry.Expires.Equal(now.Add(time.Minute)) {
+		t.Errorf("expires %v, expected the person retention", entry.Expires)
+	}
+	if image, err := s.Image(entry.ID); err != nil || string(image) != "image" {
+		t.Errorf("image %q, %v", image, err)
+	}
+	if entries[0].Image {
+		t.Error("private frame was saved")
+	}
+
+	// The entries are read back
+	loaded := testStore(dir)
+	if err := loaded.load(); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := loaded.Get(entry.ID); !ok || got.Signature == nil || got.Signature.Value != sig.Value || len(got.Detections) != 2 {
+		t.Fatalf("loaded entry %+v", got)
+	}
+
+}
+
+func TestRecordQueueFull(t *testing.T) {
+
+	s := testStore("")
+	s.writes = make(chan *write, 1)
+	for i := 0; i < 3; i++ {
+		s.Record(&sink.Event{Time: time.Unix(int64(i), 0), Response: &odrpc.DetectResponse{Detections: []*odrpc.Detection{{Label: "car"}}}})
+	}
+	if len(s.writes) != 1 {
+		t.Fatalf("%d queued, expected 1", len(s.writes))
+	}
+
+}
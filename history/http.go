@@ -0,0 +1,117 @@
+package history
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/server"
+)
+
+// RegisterHTTP registers the history endpoints on the router
+func (s *Store) RegisterHTTP(r chi.Router) {
+	if s == nil {
+		return
+	}
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(s.keys))
+		r.Get("/history", s.handleList)
+		r.Delete("/history", s.handlePurge)
+		r.Get("/history/{id}", s.handleGet)
+		r.Delete("/history/{id}", s.handleDelete)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(server.ImageAuth(s.keys))
+		r.Get("/history/{id}/image", s.handleImage)
+	})
+}
+
+// parseFilter reads the from, to, source and label query parameters
+func parseFilter(query url.Values) (*Filter, error) {
+	f := &Filter{
+		Source: query.Get("source"),
+		Label:  query.Get("label"),
+	}
+	var err error
+	if from := query.Get("from"); from != "" {
+		if f.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return nil, fmt.Errorf("invalid from: %v", err)
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if f.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return nil, fmt.Errorf("invalid to: %v", err)
+		}
+	}
+	return f, nil
+}
+
+func (s *Store) handleList(w http.ResponseWriter, r *http.Request) {
+	f, err := parseFilter(r.URL.Query())
+	if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	render.JSON(w, r, map[string]interface{}{"entries": s.Entries(f)})
+}
+
+// handlePurge deletes the entries matching the query, all=true is required to delete everything
+func (s *Store) handlePurge(w http.ResponseWriter, r *http.Request) {
+	f, err := parseFilter(r.URL.Query())
+	if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	if f.Empty() && r.URL.Query().Get("all") != "true" {
+		render.Render(w, r, server.ErrInvalidRequest(fmt.Errorf("from, to, source or label is required, or all=true")))
+		return
+	}
+	purged, err := s.Purge(f)
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	s.logger.Infow("Purged history", "entries", purged, "from", f.From, "to", f.To, "source", f.Source, "label", f.Label)
+	render.JSON(w, r, map[string]interface{}{"purged": purged})
+}
+
+func (s *Store) handleGet(w http.ResponseWriter, r *http.Request) {
+	entry, ok := s.Get(chi.URLParam(r, "id"))
+	if !ok {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	render.JSON(w, r, entry)
+}
+
+// handleImage returns the original frame without the detections drawn
+func (s *Store) handleImage(w http.ResponseWriter, r *http.Request) {
+	data, err := s.Image(chi.URLParam(r, "id"))
+	if os.IsNotExist(err) {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	} else if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Write(data)
+}
+
+func (s *Store) handleDelete(w http.ResponseWriter, r *http.Request) {
+	found, err := s.Delete(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	if !found {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,155 @@
+package job
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cron is a parsed cron schedule
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	// If either day field is restricted, a day matches if either field matches
+	domStar, dowStar bool
+}
+
+// parseCron parses a standard 5 field cron schedule. Fields can be *, a number, a range (1-5),
+// a list (1,3,5) and have a step (*/5). Shortcuts like @hourly and @daily are supported.
+func parseCron(schedule string) (*cron, error) {
+
+	switch schedule {
+	case "@yearly", "@annually":
+		schedule = "0 0 1 1 *"
+	case "@monthly":
+		schedule = "0 0 1 * *"
+	case "@weekly":
+		schedule = "0 0 * * 0"
+	case "@daily", "@midnight":
+		schedule = "0 0 * * *"
+	case "@hourly":
+		schedule = "0 * * * *"
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in schedule %s", schedule)
+	}
+
+	c := &cron{
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute: %v", err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour: %v", err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day of month: %v", err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month: %v", err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid day of week: %v", err)
+	}
+	// 7 is also Sunday
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+
+	return c, nil
+
+}
+
+// parseCronField returns a bitset of the values in the field
+func parseCronField(field string, min int, max int) (uint64, error) {
+
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %s", part)
+			}
+			part = part[:i]
+		}
+
+		start, end := min, max
+		if part != "*" {
+			if i := strings.Index(part, "-"); i >= 0 {
+				var err error
+				if start, err = strconv.Atoi(part[:i]); err != nil {
+					return 0, fmt.Errorf("invalid range %s", part)
+				}
+				if end, err = strconv.Atoi(part[i+1:]); err != nil {
+					return 0, fmt.Errorf("invalid range %s", part)
+				}
+			} else {
+				var err error
+				if start, err = strconv.Atoi(part); err != nil {
+					return 0, fmt.Errorf("invalid value %s", part)
+				}
+				end = start
+				// A single value with a step runs to the max (5/15)
+				if step > 1 {
+					end = max
+				}
+			}
+		}
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("%s out of range %d-%d", part, min, max)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+
+}
+
+// next returns the next time after t that matches the schedule
+func (c *cron) next(t time.Time) time.Time {
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+
+	// Every schedule matches at least once in a few years (Feb 29)
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+
+}
+
+func (c *cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
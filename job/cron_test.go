@@ -0,0 +1,89 @@
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+
+	for _, test := range []struct {
+		schedule string
+		valid    bool
+	}{
+		{"* * * * *", true},
+		{"*/5 * * * *", true},
+		{"0 9-17 * * 1-5", true},
+		{"0,30 8 1,15 * *", true},
+		{"5/15 * * * *", true},
+		{"0 0 * * 7", true},
+		{"@hourly", true},
+		{"@daily", true},
+		{"* * * *", false},
+		{"60 * * * *", false},
+		{"* 24 * * *", false},
+		{"* * 0 * *", false},
+		{"* * * 13 *", false},
+		{"* * * * 8", false},
+		{"5-1 * * * *", false},
+		{"*/0 * * * *", false},
+		{"a * * * *", false},
+		{"@sometimes", false},
+	} {
+		_, err := parseCron(test.schedule)
+		if (err == nil) != test.valid {
+			t.Errorf("schedule %q error %v, expected valid %v", test.schedule, err, test.valid)
+		}
+	}
+
+}
+
+func TestCronNext(t *testing.T) {
+
+	// A Wednesday
+	base := time.Date(2020, 1, 15, 10, 7, 30, 0, time.UTC)
+
+	for _, test := range []struct {
+		schedule string
+		from     time.Time
+		expect   time.Time
+	}{
+		{"* * * * *", base, time.Date(2020, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", base, time.Date(2020, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"5/15 * * * *", base, time.Date(2020, 1, 15, 10, 20, 0, 0, time.UTC)},
+		{"0 * * * *", base, time.Date(2020, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 8 * * *", base, time.Date(2020, 1, 16, 8, 30, 0, 0, time.UTC)},
+		{"0 9-17 * * 1-5", time.Date(2020, 1, 17, 17, 30, 0, 0, time.UTC), time.Date(2020, 1, 20, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", base, time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", base, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// Sunday as 0 and 7
+		{"0 0 * * 0", base, time.Date(2020, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", base, time.Date(2020, 1, 19, 0, 0, 0, 0, time.UTC)},
+		// Either day field matches when both are restricted, the 20th or a Friday
+		{"0 0 20 * 5", base, time.Date(2020, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 20 * 5", time.Date(2020, 1, 17, 12, 0, 0, 0, time.UTC), time.Date(2020, 1, 20, 0, 0, 0, 0, time.UTC)},
+		// Only in leap years
+		{"0 0 29 2 *", base, time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Exactly on a match is the next one
+		{"0 * * * *", time.Date(2020, 1, 15, 10, 0, 0, 0, time.UTC), time.Date(2020, 1, 15, 11, 0, 0, 0, time.UTC)},
+	} {
+		c, err := parseCron(test.schedule)
+		if err != nil {
+			t.Fatalf("schedule %q: %v", test.schedule, err)
+		}
+		if next := c.next(test.from); !next.Equal(test.expect) {
+			t.Errorf("schedule %q from %v: next %v, expected %v", test.schedule, test.from, next, test.expect)
+		}
+	}
+
+	// Never matches
+	c, err := parseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := c.next(base); !next.IsZero() {
+		t.Errorf("expected no next time, got %v", next)
+	}
+
+}
@@ -0,0 +1,72 @@
+package job
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
+)
+
+// RegisterHTTP registers the job endpoints on the router
+func (m *Manager) RegisterHTTP(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(m.keys))
+		r.Get("/jobs", m.handleJobs)
+		r.Post("/jobs/{name}/run", m.handleRun)
+	})
+}
+
+type jobInfo struct {
+	Name     string                `json:"name"`
+	Detector string                `json:"detector"`
+	LastRun  *time.Time            `json:"last_run,omitempty"`
+	NextRun  *time.Time            `json:"next_run,omitempty"`
+	Error    string                `json:"error,omitempty"`
+	Response *odrpc.DetectResponse `json:"response,omitempty"`
+}
+
+func (j *Job) info() *jobInfo {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+	info := &jobInfo{
+		Name:     j.config.Name,
+		Detector: j.config.Detector,
+		Error:    j.lastError,
+		Response: j.lastResponse,
+	}
+	if !j.lastRun.IsZero() {
+		lastRun := j.lastRun
+		info.LastRun = &lastRun
+	}
+	if !j.nextRun.IsZero() {
+		nextRun := j.nextRun
+		info.NextRun = &nextRun
+	}
+	return info
+}
+
+// handleJobs returns the configured jobs and their last results
+func (m *Manager) handleJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := make([]*jobInfo, 0, len(m.jobs))
+	for _, j := range m.jobs {
+		jobs = append(jobs, j.info())
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	render.JSON(w, r, map[string]interface{}{"jobs": jobs})
+}
+
+// handleRun runs a job now
+func (m *Manager) handleRun(w http.ResponseWriter, r *http.Request) {
+	j, ok := m.jobs[chi.URLParam(r, "name")]
+	if !ok {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	j.run(m.lc.Context())
+	render.JSON(w, r, j.info())
+}
@@ -0,0 +1,177 @@
+// Package job runs detections on a schedule
+package job
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/digest"
+	"github.com/snowzach/doods/job/jobconfig"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/sink"
+)
+
+const fetchTimeout = 30 * time.Second
+
+// Detector runs detections for a job
+type Detector interface {
+	Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error)
+}
+
+// Job fetches an image and runs a detection on a schedule
+type Job struct {
+	config   *jobconfig.JobConfig
+	cron     *cron
+	detector Detector
+	client   *http.Client
+	logger   *zap.SugaredLogger
+
+	// The last run
+	lastRun      time.Time
+	lastResponse *odrpc.DetectResponse
+	lastError    string
+	nextRun      time.Time
+	lock         sync.RWMutex
+}
+
+// newJob creates a job from the config
+func newJob(c *jobconfig.JobConfig, detector Detector) (*Job, error) {
+
+	if c.Name == "" {
+		return nil, fmt.Errorf("job name is required")
+	}
+	if c.URL == "" && c.File == "" {
+		return nil, fmt.Errorf("job url or file is required")
+	}
+
+	j := &Job{
+		config:   c,
+		detector: detector,
+		client:   &http.Client{Timeout: fetchTimeout},
+		logger:   zap.S().With("package", "job", "name", c.Name),
+	}
+
+	switch {
+	case c.Schedule != "":
+		var err error
+		if j.cron, err = parseCron(c.Schedule); err != nil {
+			return nil, fmt.Errorf("invalid schedule: %v", err)
+		}
+	case c.Every <= 0:
+		return nil, fmt.Errorf("job schedule or every is required")
+	}
+
+	if c.Username != "" {
+		j.client.Transport = digest.NewTransport(c.Username, c.Password)
+	}
+
+	return j, nil
+
+}
+
+// next returns the next run time after t
+func (j *Job) next(t time.Time) time.Time {
+	if j.cron != nil {
+		return j.cron.next(t)
+	}
+	return t.Add(j.config.Every)
+}
+
+// Run runs the job on its schedule until the context is canceled
+func (j *Job) Run(ctx context.Context) {
+	for {
+		next := j.next(time.Now())
+		if next.IsZero() {
+			j.logger.Errorw("Schedule never runs")
+			return
+		}
+		j.lock.Lock()
+		j.nextRun = next
+		j.lock.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		j.run(ctx)
+	}
+}
+
+// run fetches the image and runs the detection. Events go to the sinks through the detector.
+func (j *Job) run(ctx context.Context) {
+
+	response, err := j.detect(ctx)
+
+	j.lock.Lock()
+	j.lastRun = time.Now()
+	j.lastResponse = response
+	j.lastError = ""
+	if err != nil {
+		j.lastError = err.Error()
+	}
+	j.lock.Unlock()
+
+	if err != nil {
+		j.logger.Errorw("Job error", "error", err)
+		return
+	}
+	j.logger.Debugw("Job complete", "detections", len(response.Detections))
+
+}
+
+func (j *Job) detect(ctx context.Context) (*odrpc.DetectResponse, error) {
+
+	request := &odrpc.DetectRequest{
+		Id:           j.config.Name,
+		DetectorName: j.config.Detector,
+		File:         j.config.File,
+		Detect:       j.config.Detect,
+		Regions:      j.config.Regions,
+		Ignore:       j.config.Ignore,
+	}
+
+	if j.config.URL != "" {
+		var err error
+		if request.Data, err = j.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = sink.WithSource(ctx, j.config.Name)
+	if len(j.config.Sinks) > 0 {
+		ctx = sink.WithSinks(ctx, j.config.Sinks)
+	}
+
+	return j.detector.Detect(ctx, request)
+
+}
+
+// fetch gets the image from the url
+func (j *Job) fetch(ctx context.Context) ([]byte, error) {
+
+	request, err := http.NewRequest(http.MethodGet, j.config.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := j.client.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch image: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch image: %s", response.Status)
+	}
+
+	return ioutil.ReadAll(response.Body)
+
+}
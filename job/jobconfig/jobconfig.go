@@ -0,0 +1,28 @@
+package jobconfig
+
+import (
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// JobConfig is used for parsing scheduled job configuration from the config file
+type JobConfig struct {
+	Name string `json:"name"`
+	// A cron schedule (minute hour day-of-month month day-of-week)
+	Schedule string `json:"schedule"`
+	// Or run at a fixed interval
+	Every time.Duration `json:"every"`
+	// The snapshot url, basic or digest auth
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Or a file
+	File     string                `json:"file"`
+	Detector string                `json:"detector"`
+	Detect   map[string]float32    `json:"detect"`
+	Regions  []*odrpc.DetectRegion `json:"regions"`
+	Ignore   []string              `json:"ignore"`
+	// Only send events to these sinks, all if empty
+	Sinks []string `json:"sinks"`
+}
@@ -0,0 +1,49 @@
+package job
+
+import (
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/job/jobconfig"
+	"github.com/snowzach/doods/server"
+)
+
+// Manager runs the configured jobs
+type Manager struct {
+	jobs   map[string]*Job
+	lc     *conf.Lifecycle
+	keys   *server.AuthKeys
+	logger *zap.SugaredLogger
+}
+
+// New creates and starts the configured jobs
+func New(lc *conf.Lifecycle, detector Detector) *Manager {
+
+	m := &Manager{
+		jobs:   make(map[string]*Job),
+		lc:     lc,
+		keys:   server.Keys(),
+		logger: zap.S().With("package", "job"),
+	}
+
+	// Get the jobs config
+	var jobConfig []*jobconfig.JobConfig
+	config.UnmarshalKey("doods.jobs", &jobConfig)
+
+	for _, c := range jobConfig {
+		j, err := newJob(c, detector)
+		if err != nil {
+			m.logger.Errorf("Could not configure job %s: %v", c.Name, err)
+			continue
+		}
+		m.jobs[c.Name] = j
+
+		lc.Go(j.Run)
+
+		m.logger.Infow("Configured Job", "name", c.Name, "detector", c.Detector, "schedule", c.Schedule, "every", c.Every)
+	}
+
+	return m
+
+}
@@ -0,0 +1,30 @@
+package odrpc
+
+// IoU is the intersection over union of the detection boxes, 0 if they don't overlap
+func IoU(a, b *Detection) float32 {
+	left, right := max32(a.Left, b.Left), min32(a.Right, b.Right)
+	top, bottom := max32(a.Top, b.Top), min32(a.Bottom, b.Bottom)
+	if right <= left || bottom <= top {
+		return 0
+	}
+	intersection := (right - left) * (bottom - top)
+	union := (a.Right-a.Left)*(a.Bottom-a.Top) + (b.Right-b.Left)*(b.Bottom-b.Top) - intersection
+	if union <= 0 {
+		return 0
+	}
+	return intersection / union
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,34 @@
+package odrpc
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIoU(t *testing.T) {
+
+	box := func(left, top, right, bottom float32) *Detection {
+		return &Detection{Left: left, Top: top, Right: right, Bottom: bottom}
+	}
+
+	for _, test := range []struct {
+		name     string
+		a, b     *Detection
+		expected float32
+	}{
+		{"same", box(0, 0, 0.5, 0.5), box(0, 0, 0.5, 0.5), 1},
+		{"half", box(0, 0, 0.5, 0.5), box(0.25, 0, 0.75, 0.5), 1.0 / 3},
+		{"inside", box(0, 0, 1, 1), box(0.25, 0.25, 0.75, 0.75), 0.25},
+		{"touching", box(0, 0, 0.5, 0.5), box(0.5, 0, 1, 0.5), 0},
+		{"apart", box(0, 0, 0.2, 0.2), box(0.5, 0.5, 1, 1), 0},
+		{"empty", box(0.5, 0.5, 0.5, 0.5), box(0.5, 0.5, 0.5, 0.5), 0},
+	} {
+		if got := IoU(test.a, test.b); math.Abs(float64(got-test.expected)) > 1e-6 {
+			t.Errorf("%s: got %v, expected %v", test.name, got, test.expected)
+		}
+		if got := IoU(test.b, test.a); math.Abs(float64(got-test.expected)) > 1e-6 {
+			t.Errorf("%s reversed: got %v, expected %v", test.name, got, test.expected)
+		}
+	}
+
+}
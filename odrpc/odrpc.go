@@ -1,5 +1,6 @@
 package odrpc
 
 const (
-	DoodsAuthKeyHeader = "doods-auth-key"
+	DoodsAuthKeyHeader   = "doods-auth-key"
+	AcceptLanguageHeader = "accept-language"
 )
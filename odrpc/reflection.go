@@ -0,0 +1,56 @@
+package odrpc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+
+	gogoproto "github.com/gogo/protobuf/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	rpcProtoFile  = "odrpc/rpc.proto"
+	gogoProtoFile = "github.com/gogo/protobuf/gogoproto/gogo.proto"
+)
+
+// RegisterReflection registers the gogo generated file descriptor with the protobuf registry that the
+// gRPC reflection service uses. The gogo.proto import only carries code generation options so it is dropped.
+func RegisterReflection() error {
+
+	if _, err := protoregistry.GlobalFiles.FindFileByPath(rpcProtoFile); err == nil {
+		return nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(gogoproto.FileDescriptor(rpcProtoFile)))
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return err
+	}
+
+	var fdp descriptorpb.FileDescriptorProto
+	if err := proto.Unmarshal(b, &fdp); err != nil {
+		return err
+	}
+	deps := fdp.Dependency[:0]
+	for _, dep := range fdp.Dependency {
+		if dep != gogoProtoFile {
+			deps = append(deps, dep)
+		}
+	}
+	fdp.Dependency = deps
+
+	fd, err := protodesc.NewFile(&fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		return err
+	}
+
+	return protoregistry.GlobalFiles.RegisterFile(fd)
+
+}
@@ -14,9 +14,13 @@ import (
 	empty "github.com/golang/protobuf/ptypes/empty"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 	io "io"
 	math "math"
+	math_bits "math/bits"
 	reflect "reflect"
+	strconv "strconv"
 	strings "strings"
 )
 
@@ -29,7 +33,34 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type RawOutputs int32
+
+const (
+	// Only the detections
+	RAW_NONE RawOutputs = 0
+	// The raw outputs and the detections
+	RAW_INCLUDE RawOutputs = 1
+	// Only the raw outputs, the outputs are not parsed
+	RAW_ONLY RawOutputs = 2
+)
+
+var RawOutputs_name = map[int32]string{
+	0: "RAW_NONE",
+	1: "RAW_INCLUDE",
+	2: "RAW_ONLY",
+}
+
+var RawOutputs_value = map[string]int32{
+	"RAW_NONE":    0,
+	"RAW_INCLUDE": 1,
+	"RAW_ONLY":    2,
+}
+
+func (RawOutputs) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{0}
+}
 
 type GetDetectorsResponse struct {
 	Detectors []*Detector `protobuf:"bytes,1,rep,name=detectors,proto3" json:"detectors,omitempty"`
@@ -48,7 +79,7 @@ func (m *GetDetectorsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte
 		return xxx_messageInfo_GetDetectorsResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -89,6 +120,12 @@ type Detector struct {
 	Height int32 `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
 	// The detection channels
 	Channels int32 `protobuf:"varint,7,opt,name=channels,proto3" json:"channels,omitempty"`
+	// Available label languages
+	Languages []string `protobuf:"bytes,8,rep,name=languages,proto3" json:"languages,omitempty"`
+	// The extra inputs for models with more than one input
+	Inputs []string `protobuf:"bytes,9,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	// The image input tensor if the detector has one
+	Input *InputSpec `protobuf:"bytes,10,opt,name=input,proto3" json:"input,omitempty"`
 }
 
 func (m *Detector) Reset()      { *m = Detector{} }
@@ -104,7 +141,7 @@ func (m *Detector) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return xxx_messageInfo_Detector.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -172,6 +209,98 @@ func (m *Detector) GetChannels() int32 {
 	return 0
 }
 
+func (m *Detector) GetLanguages() []string {
+	if m != nil {
+		return m.Languages
+	}
+	return nil
+}
+
+func (m *Detector) GetInputs() []string {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *Detector) GetInput() *InputSpec {
+	if m != nil {
+		return m.Input
+	}
+	return nil
+}
+
+// The image input tensor of a model
+type InputSpec struct {
+	// The element type (UInt8 or Float32)
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// NHWC (channels last) or NCHW (channels first)
+	Layout string `protobuf:"bytes,2,opt,name=layout,proto3" json:"layout,omitempty"`
+	// Float inputs are (pixel - mean) * scale for each RGB channel
+	Mean  []float32 `protobuf:"fixed32,3,rep,packed,name=mean,proto3" json:"mean,omitempty"`
+	Scale []float32 `protobuf:"fixed32,4,rep,packed,name=scale,proto3" json:"scale,omitempty"`
+}
+
+func (m *InputSpec) Reset()      { *m = InputSpec{} }
+func (*InputSpec) ProtoMessage() {}
+func (*InputSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{2}
+}
+func (m *InputSpec) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *InputSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_InputSpec.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *InputSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InputSpec.Merge(m, src)
+}
+func (m *InputSpec) XXX_Size() int {
+	return m.Size()
+}
+func (m *InputSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_InputSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InputSpec proto.InternalMessageInfo
+
+func (m *InputSpec) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *InputSpec) GetLayout() string {
+	if m != nil {
+		return m.Layout
+	}
+	return ""
+}
+
+func (m *InputSpec) GetMean() []float32 {
+	if m != nil {
+		return m.Mean
+	}
+	return nil
+}
+
+func (m *InputSpec) GetScale() []float32 {
+	if m != nil {
+		return m.Scale
+	}
+	return nil
+}
+
 // The Process Request
 type DetectRequest struct {
 	// The ID for the request.
@@ -186,12 +315,38 @@ type DetectRequest struct {
 	Detect map[string]float32 `protobuf:"bytes,5,rep,name=detect,proto3" json:"detect,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed32,2,opt,name=value,proto3"`
 	// Sub regions for detection
 	Regions []*DetectRegion `protobuf:"bytes,6,rep,name=regions,proto3" json:"regions,omitempty"`
+	// The language for the returned labels
+	Language string `protobuf:"bytes,7,opt,name=language,proto3" json:"language,omitempty"`
+	// Labels to never return
+	Ignore []string `protobuf:"bytes,8,rep,name=ignore,proto3" json:"ignore,omitempty"`
+	// Fetch the image from a url
+	ImageUrl string `protobuf:"bytes,9,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	// Extra inputs for models with more than one input
+	Inputs map[string]*InputTensor `protobuf:"bytes,10,rep,name=inputs,proto3" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Return the raw output tensors of the model
+	RawOutputs RawOutputs `protobuf:"varint,11,opt,name=raw_outputs,json=rawOutputs,proto3,enum=odrpc.RawOutputs" json:"raw_outputs,omitempty"`
+	// Enhance the image before detection
+	Preprocess *Preprocess `protobuf:"bytes,12,opt,name=preprocess,proto3" json:"preprocess,omitempty"`
+	// Rotate the image clockwise before detection (90, 180 or 270), the detections are in the original orientation
+	Rotate int32 `protobuf:"varint,13,opt,name=rotate,proto3" json:"rotate,omitempty"`
+	// Flip the image after rotating it (horizontal, vertical or both)
+	Flip string `protobuf:"bytes,14,opt,name=flip,proto3" json:"flip,omitempty"`
+	// Smooth the boxes between requests from the same source
+	Smooth *Smooth `protobuf:"bytes,15,opt,name=smooth,proto3" json:"smooth,omitempty"`
+	// Only return a label once it's seen in enough of the recent requests from the same source
+	Confirm *Confirm `protobuf:"bytes,16,opt,name=confirm,proto3" json:"confirm,omitempty"`
+	// Save the image and the detector input to doods.debug.dir to reproduce the result
+	Debug bool `protobuf:"varint,17,opt,name=debug,proto3" json:"debug,omitempty"`
+	// Run the cascade stages of the detector and return their detections as children of the detections they refine
+	Cascade bool `protobuf:"varint,18,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	// Return the density map values of crowd counting models, the count is always returned
+	DensityMap bool `protobuf:"varint,19,opt,name=density_map,json=densityMap,proto3" json:"density_map,omitempty"`
 }
 
 func (m *DetectRequest) Reset()      { *m = DetectRequest{} }
 func (*DetectRequest) ProtoMessage() {}
 func (*DetectRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_edafdb9f55df517e, []int{2}
+	return fileDescriptor_edafdb9f55df517e, []int{3}
 }
 func (m *DetectRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -201,7 +356,7 @@ func (m *DetectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error
 		return xxx_messageInfo_DetectRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
@@ -262,1485 +417,12999 @@ func (m *DetectRequest) GetRegions() []*DetectRegion {
 	return nil
 }
 
-type DetectRegion struct {
-	// Coordinates
-	Top    float32 `protobuf:"fixed32,1,opt,name=top,proto3" json:"top"`
-	Left   float32 `protobuf:"fixed32,2,opt,name=left,proto3" json:"left"`
-	Bottom float32 `protobuf:"fixed32,3,opt,name=bottom,proto3" json:"bottom"`
-	Right  float32 `protobuf:"fixed32,4,opt,name=right,proto3" json:"right"`
-	// What to detect
-	Detect map[string]float32 `protobuf:"bytes,5,rep,name=detect,proto3" json:"detect,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed32,2,opt,name=value,proto3"`
-	Covers bool               `protobuf:"varint,6,opt,name=covers,proto3" json:"covers,omitempty"`
+func (m *DetectRequest) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
 }
 
-func (m *DetectRegion) Reset()      { *m = DetectRegion{} }
-func (*DetectRegion) ProtoMessage() {}
-func (*DetectRegion) Descriptor() ([]byte, []int) {
-	return fileDescriptor_edafdb9f55df517e, []int{3}
-}
-func (m *DetectRegion) XXX_Unmarshal(b []byte) error {
-	return m.Unmarshal(b)
-}
-func (m *DetectRegion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	if deterministic {
-		return xxx_messageInfo_DetectRegion.Marshal(b, m, deterministic)
-	} else {
-		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
-		if err != nil {
-			return nil, err
-		}
-		return b[:n], nil
+func (m *DetectRequest) GetIgnore() []string {
+	if m != nil {
+		return m.Ignore
 	}
+	return nil
 }
-func (m *DetectRegion) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DetectRegion.Merge(m, src)
-}
-func (m *DetectRegion) XXX_Size() int {
-	return m.Size()
+
+func (m *DetectRequest) GetImageUrl() string {
+	if m != nil {
+		return m.ImageUrl
+	}
+	return ""
 }
-func (m *DetectRegion) XXX_DiscardUnknown() {
-	xxx_messageInfo_DetectRegion.DiscardUnknown(m)
+
+func (m *DetectRequest) GetInputs() map[string]*InputTensor {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
 }
 
-var xxx_messageInfo_DetectRegion proto.InternalMessageInfo
+func (m *DetectRequest) GetRawOutputs() RawOutputs {
+	if m != nil {
+		return m.RawOutputs
+	}
+	return RAW_NONE
+}
 
-func (m *DetectRegion) GetTop() float32 {
+func (m *DetectRequest) GetPreprocess() *Preprocess {
 	if m != nil {
-		return m.Top
+		return m.Preprocess
 	}
-	return 0
+	return nil
 }
 
-func (m *DetectRegion) GetLeft() float32 {
+func (m *DetectRequest) GetRotate() int32 {
 	if m != nil {
-		return m.Left
+		return m.Rotate
 	}
 	return 0
 }
 
-func (m *DetectRegion) GetBottom() float32 {
+func (m *DetectRequest) GetFlip() string {
 	if m != nil {
-		return m.Bottom
+		return m.Flip
 	}
-	return 0
+	return ""
 }
 
-func (m *DetectRegion) GetRight() float32 {
+func (m *DetectRequest) GetSmooth() *Smooth {
 	if m != nil {
-		return m.Right
+		return m.Smooth
 	}
-	return 0
+	return nil
 }
 
-func (m *DetectRegion) GetDetect() map[string]float32 {
+func (m *DetectRequest) GetConfirm() *Confirm {
 	if m != nil {
-		return m.Detect
+		return m.Confirm
 	}
 	return nil
 }
 
-func (m *DetectRegion) GetCovers() bool {
+func (m *DetectRequest) GetDebug() bool {
 	if m != nil {
-		return m.Covers
+		return m.Debug
 	}
 	return false
 }
 
-// Area for detection
-type Detection struct {
-	// Coordinates
-	Top        float32 `protobuf:"fixed32,1,opt,name=top,proto3" json:"top"`
-	Left       float32 `protobuf:"fixed32,2,opt,name=left,proto3" json:"left"`
-	Bottom     float32 `protobuf:"fixed32,3,opt,name=bottom,proto3" json:"bottom"`
-	Right      float32 `protobuf:"fixed32,4,opt,name=right,proto3" json:"right"`
-	Label      string  `protobuf:"bytes,5,opt,name=label,proto3" json:"label"`
-	Confidence float32 `protobuf:"fixed32,6,opt,name=confidence,proto3" json:"confidence"`
+func (m *DetectRequest) GetCascade() bool {
+	if m != nil {
+		return m.Cascade
+	}
+	return false
 }
 
-func (m *Detection) Reset()      { *m = Detection{} }
-func (*Detection) ProtoMessage() {}
-func (*Detection) Descriptor() ([]byte, []int) {
+func (m *DetectRequest) GetDensityMap() bool {
+	if m != nil {
+		return m.DensityMap
+	}
+	return false
+}
+
+// Confirm a label is seen in min_frames of the last frames from a source before it's returned
+type Confirm struct {
+	MinFrames int32 `protobuf:"varint,1,opt,name=min_frames,json=minFrames,proto3" json:"min_frames,omitempty"`
+	// The number of recent frames, default min_frames
+	Frames int32 `protobuf:"varint,2,opt,name=frames,proto3" json:"frames,omitempty"`
+	// The labels to confirm, all if empty
+	Labels []string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty"`
+}
+
+func (m *Confirm) Reset()      { *m = Confirm{} }
+func (*Confirm) ProtoMessage() {}
+func (*Confirm) Descriptor() ([]byte, []int) {
 	return fileDescriptor_edafdb9f55df517e, []int{4}
 }
-func (m *Detection) XXX_Unmarshal(b []byte) error {
+func (m *Confirm) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Detection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Confirm) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Detection.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Confirm.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
 		return b[:n], nil
 	}
 }
-func (m *Detection) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Detection.Merge(m, src)
+func (m *Confirm) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Confirm.Merge(m, src)
 }
-func (m *Detection) XXX_Size() int {
+func (m *Confirm) XXX_Size() int {
 	return m.Size()
 }
-func (m *Detection) XXX_DiscardUnknown() {
-	xxx_messageInfo_Detection.DiscardUnknown(m)
+func (m *Confirm) XXX_DiscardUnknown() {
+	xxx_messageInfo_Confirm.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Detection proto.InternalMessageInfo
+var xxx_messageInfo_Confirm proto.InternalMessageInfo
 
-func (m *Detection) GetTop() float32 {
+func (m *Confirm) GetMinFrames() int32 {
 	if m != nil {
-		return m.Top
+		return m.MinFrames
 	}
 	return 0
 }
 
-func (m *Detection) GetLeft() float32 {
+func (m *Confirm) GetFrames() int32 {
 	if m != nil {
-		return m.Left
+		return m.Frames
 	}
 	return 0
 }
 
-func (m *Detection) GetBottom() float32 {
+func (m *Confirm) GetLabels() []string {
 	if m != nil {
-		return m.Bottom
+		return m.Labels
 	}
-	return 0
+	return nil
 }
 
-func (m *Detection) GetRight() float32 {
+// Temporal smoothing of the boxes from a source so they don't jitter between frames
+type Smooth struct {
+	// ema (default) or kalman
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// The weight of the new box for ema (0-1), default 0.5
+	Alpha float32 `protobuf:"fixed32,2,opt,name=alpha,proto3" json:"alpha,omitempty"`
+	// The minimum overlap (intersection over union) to match a box in the previous frame, default 0.3
+	Iou float32 `protobuf:"fixed32,3,opt,name=iou,proto3" json:"iou,omitempty"`
+	// The number of frames an object can be missing before it's forgotten, default 3
+	MaxMissed int32 `protobuf:"varint,4,opt,name=max_missed,json=maxMissed,proto3" json:"max_missed,omitempty"`
+}
+
+func (m *Smooth) Reset()      { *m = Smooth{} }
+func (*Smooth) ProtoMessage() {}
+func (*Smooth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{5}
+}
+func (m *Smooth) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Smooth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Smooth.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Smooth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Smooth.Merge(m, src)
+}
+func (m *Smooth) XXX_Size() int {
+	return m.Size()
+}
+func (m *Smooth) XXX_DiscardUnknown() {
+	xxx_messageInfo_Smooth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Smooth proto.InternalMessageInfo
+
+func (m *Smooth) GetMethod() string {
 	if m != nil {
-		return m.Right
+		return m.Method
+	}
+	return ""
+}
+
+func (m *Smooth) GetAlpha() float32 {
+	if m != nil {
+		return m.Alpha
 	}
 	return 0
 }
 
-func (m *Detection) GetLabel() string {
+func (m *Smooth) GetIou() float32 {
 	if m != nil {
-		return m.Label
+		return m.Iou
 	}
-	return ""
+	return 0
 }
 
-func (m *Detection) GetConfidence() float32 {
+func (m *Smooth) GetMaxMissed() int32 {
 	if m != nil {
-		return m.Confidence
+		return m.MaxMissed
 	}
 	return 0
 }
 
-type DetectResponse struct {
-	// The id for the response
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// The detected areas
-	Detections []*Detection `protobuf:"bytes,2,rep,name=detections,proto3" json:"detections,omitempty"`
-	// If there was an error (streaming endpoint only)
-	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+// Image enhancement before detection, mostly for night frames
+type Preprocess struct {
+	// Contrast limited adaptive histogram equalization of the brightness
+	Clahe bool `protobuf:"varint,1,opt,name=clahe,proto3" json:"clahe,omitempty"`
+	// The CLAHE contrast limit, default 2
+	ClipLimit float32 `protobuf:"fixed32,2,opt,name=clip_limit,json=clipLimit,proto3" json:"clip_limit,omitempty"`
+	// The CLAHE grid size, default 8
+	Tiles int32 `protobuf:"varint,3,opt,name=tiles,proto3" json:"tiles,omitempty"`
+	// Gamma correction, values above 1 brighten the image
+	Gamma float32 `protobuf:"fixed32,4,opt,name=gamma,proto3" json:"gamma,omitempty"`
+	// Only enhance when the average brightness (0-255) is below this, always if 0
+	MaxBrightness float32 `protobuf:"fixed32,5,opt,name=max_brightness,json=maxBrightness,proto3" json:"max_brightness,omitempty"`
 }
 
-func (m *DetectResponse) Reset()      { *m = DetectResponse{} }
-func (*DetectResponse) ProtoMessage() {}
-func (*DetectResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_edafdb9f55df517e, []int{5}
+func (m *Preprocess) Reset()      { *m = Preprocess{} }
+func (*Preprocess) ProtoMessage() {}
+func (*Preprocess) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{6}
 }
-func (m *DetectResponse) XXX_Unmarshal(b []byte) error {
+func (m *Preprocess) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *DetectResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Preprocess) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_DetectResponse.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Preprocess.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
-		n, err := m.MarshalTo(b)
+		n, err := m.MarshalToSizedBuffer(b)
 		if err != nil {
 			return nil, err
 		}
 		return b[:n], nil
 	}
 }
-func (m *DetectResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DetectResponse.Merge(m, src)
+func (m *Preprocess) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Preprocess.Merge(m, src)
 }
-func (m *DetectResponse) XXX_Size() int {
+func (m *Preprocess) XXX_Size() int {
 	return m.Size()
 }
-func (m *DetectResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_DetectResponse.DiscardUnknown(m)
+func (m *Preprocess) XXX_DiscardUnknown() {
+	xxx_messageInfo_Preprocess.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DetectResponse proto.InternalMessageInfo
+var xxx_messageInfo_Preprocess proto.InternalMessageInfo
 
-func (m *DetectResponse) GetId() string {
+func (m *Preprocess) GetClahe() bool {
 	if m != nil {
-		return m.Id
+		return m.Clahe
 	}
-	return ""
+	return false
 }
 
-func (m *DetectResponse) GetDetections() []*Detection {
+func (m *Preprocess) GetClipLimit() float32 {
 	if m != nil {
-		return m.Detections
+		return m.ClipLimit
 	}
-	return nil
+	return 0
 }
 
-func (m *DetectResponse) GetError() string {
+func (m *Preprocess) GetTiles() int32 {
 	if m != nil {
-		return m.Error
+		return m.Tiles
 	}
-	return ""
+	return 0
 }
 
-func init() {
-	proto.RegisterType((*GetDetectorsResponse)(nil), "odrpc.GetDetectorsResponse")
-	proto.RegisterType((*Detector)(nil), "odrpc.Detector")
-	proto.RegisterType((*DetectRequest)(nil), "odrpc.DetectRequest")
-	proto.RegisterMapType((map[string]float32)(nil), "odrpc.DetectRequest.DetectEntry")
-	proto.RegisterType((*DetectRegion)(nil), "odrpc.DetectRegion")
-	proto.RegisterMapType((map[string]float32)(nil), "odrpc.DetectRegion.DetectEntry")
-	proto.RegisterType((*Detection)(nil), "odrpc.Detection")
-	proto.RegisterType((*DetectResponse)(nil), "odrpc.DetectResponse")
+func (m *Preprocess) GetGamma() float32 {
+	if m != nil {
+		return m.Gamma
+	}
+	return 0
 }
 
-func init() { proto.RegisterFile("odrpc/rpc.proto", fileDescriptor_edafdb9f55df517e) }
-
-var fileDescriptor_edafdb9f55df517e = []byte{
-	// 775 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x54, 0xbf, 0x6f, 0xc3, 0x44,
-	0x14, 0xce, 0x39, 0x89, 0x93, 0xbc, 0xa6, 0x3f, 0x74, 0x94, 0x62, 0xd2, 0xca, 0x8e, 0xdc, 0x25,
-	0xaa, 0x54, 0xbb, 0x2a, 0x03, 0xa5, 0x1b, 0x11, 0x15, 0x1b, 0xc3, 0x21, 0x84, 0xd4, 0x05, 0x39,
-	0xf6, 0xc5, 0xb1, 0x48, 0x7c, 0xc6, 0xbe, 0xb4, 0x0a, 0x08, 0x09, 0x31, 0x33, 0x20, 0xb1, 0xb0,
-	0xb2, 0x21, 0xfe, 0x12, 0xc6, 0x4a, 0x2c, 0x15, 0x43, 0x44, 0x53, 0x06, 0x94, 0xa9, 0x33, 0x13,
-	0xba, 0x1f, 0x6e, 0xd3, 0x28, 0x0b, 0x62, 0x60, 0xb1, 0xef, 0xfb, 0xee, 0xbb, 0xe7, 0xf7, 0x3e,
-	0xdf, 0x7b, 0xb0, 0xcb, 0xa2, 0x3c, 0x0b, 0xfd, 0x3c, 0x0b, 0xbd, 0x2c, 0x67, 0x9c, 0xe1, 0xba,
-	0x24, 0x3a, 0x47, 0x31, 0x63, 0xf1, 0x98, 0xfa, 0x41, 0x96, 0xf8, 0x41, 0x9a, 0x32, 0x1e, 0xf0,
-	0x84, 0xa5, 0x85, 0x12, 0x75, 0x0e, 0xf5, 0xae, 0x44, 0x83, 0xe9, 0xd0, 0xa7, 0x93, 0x8c, 0xcf,
-	0xf4, 0xe6, 0x69, 0x9c, 0xf0, 0xd1, 0x74, 0xe0, 0x85, 0x6c, 0xe2, 0xc7, 0x2c, 0x66, 0x2f, 0x2a,
-	0x81, 0x24, 0x90, 0x2b, 0x25, 0x77, 0xaf, 0x60, 0xff, 0x43, 0xca, 0x3f, 0xa0, 0x9c, 0x86, 0x9c,
-	0xe5, 0x05, 0xa1, 0x45, 0xc6, 0xd2, 0x82, 0xe2, 0x53, 0x68, 0x45, 0x25, 0x69, 0xa1, 0x6e, 0xb5,
-	0xb7, 0x75, 0xbe, 0xeb, 0xc9, 0xe4, 0xbc, 0x52, 0x4c, 0x5e, 0x14, 0xee, 0x2f, 0x08, 0x9a, 0x25,
-	0x8f, 0x31, 0xd4, 0xd2, 0x60, 0x42, 0x2d, 0xd4, 0x45, 0xbd, 0x16, 0x91, 0x6b, 0xc1, 0xf1, 0x59,
-	0x46, 0x2d, 0x43, 0x71, 0x62, 0x8d, 0xf7, 0xa1, 0x3e, 0x61, 0x11, 0x1d, 0x5b, 0x55, 0x49, 0x2a,
-	0x80, 0x0f, 0xc0, 0x1c, 0x07, 0x03, 0x3a, 0x2e, 0xac, 0x5a, 0xb7, 0xda, 0x6b, 0x11, 0x8d, 0x84,
-	0xfa, 0x36, 0x89, 0xf8, 0xc8, 0xaa, 0x77, 0x51, 0xaf, 0x4e, 0x14, 0x10, 0xea, 0x11, 0x4d, 0xe2,
-	0x11, 0xb7, 0x4c, 0x49, 0x6b, 0x84, 0x3b, 0xd0, 0x0c, 0x47, 0x41, 0x9a, 0x8a, 0x38, 0x0d, 0xb9,
-	0xf3, 0x8c, 0xdd, 0x9f, 0x0c, 0xd8, 0x56, 0xc9, 0x12, 0xfa, 0xc5, 0x94, 0x16, 0x1c, 0xef, 0x80,
-	0x91, 0x44, 0x3a, 0x5f, 0x23, 0x89, 0xf0, 0x31, 0x6c, 0x97, 0xb5, 0x7d, 0x26, 0x4b, 0x51, 0x69,
-	0xb7, 0x4b, 0xf2, 0x23, 0x51, 0xd2, 0x31, 0xd4, 0xa2, 0x80, 0x07, 0x32, 0xfb, 0x76, 0x7f, 0x77,
-	0x39, 0x77, 0x24, 0xfe, 0x7b, 0xee, 0x54, 0x49, 0x70, 0x4b, 0x24, 0x10, 0x75, 0x0f, 0x93, 0x31,
-	0xb5, 0x6a, 0xaa, 0x6e, 0xb1, 0xc6, 0x17, 0x60, 0xaa, 0x40, 0x56, 0x5d, 0x1a, 0xdb, 0x7d, 0x65,
-	0xac, 0xce, 0x49, 0xa3, 0xab, 0x94, 0xe7, 0x33, 0xa2, 0xf5, 0xf8, 0x14, 0x1a, 0x39, 0x8d, 0xc5,
-	0x55, 0xb0, 0x4c, 0x79, 0xf4, 0x8d, 0xb5, 0xa3, 0x62, 0x8f, 0x94, 0x9a, 0xce, 0x7b, 0xb0, 0xb5,
-	0x12, 0x05, 0xef, 0x41, 0xf5, 0x73, 0x3a, 0xd3, 0x65, 0x8a, 0xa5, 0xf0, 0xf4, 0x26, 0x18, 0x4f,
-	0x55, 0x7d, 0x06, 0x51, 0xe0, 0xd2, 0xb8, 0x40, 0xee, 0x8f, 0x06, 0xb4, 0x57, 0x83, 0xe2, 0xb7,
-	0xa1, 0xca, 0x59, 0x26, 0x0f, 0x1b, 0xfd, 0xc6, 0x72, 0xee, 0x08, 0x48, 0xc4, 0x03, 0x1f, 0x41,
-	0x6d, 0x4c, 0x87, 0x5c, 0x05, 0xe9, 0x37, 0x85, 0x11, 0x02, 0x13, 0xf9, 0xc4, 0x2e, 0x98, 0x03,
-	0xc6, 0x39, 0x9b, 0x48, 0xa3, 0x8c, 0x3e, 0x2c, 0xe7, 0x8e, 0x66, 0x88, 0x7e, 0x63, 0x07, 0xea,
-	0xb9, 0xfc, 0x89, 0x35, 0x29, 0x69, 0x2d, 0xe7, 0x8e, 0x22, 0x88, 0x7a, 0xe1, 0x77, 0xd7, 0x2c,
-	0x73, 0x36, 0xd4, 0xbd, 0xd1, 0xb1, 0x03, 0x30, 0x43, 0x76, 0x43, 0xf3, 0x42, 0xde, 0x8f, 0x26,
-	0xd1, 0xe8, 0xbf, 0x58, 0xf3, 0x3b, 0x82, 0x96, 0x3a, 0xfb, 0xff, 0xfb, 0xe2, 0x40, 0x5d, 0xb6,
-	0x87, 0x6c, 0x8a, 0x96, 0x12, 0x48, 0x82, 0xa8, 0x17, 0xf6, 0x00, 0x42, 0x96, 0x0e, 0x93, 0x88,
-	0xa6, 0x21, 0x95, 0x1e, 0x18, 0xfd, 0x9d, 0xe5, 0xdc, 0x59, 0x61, 0xc9, 0xca, 0xda, 0x1d, 0xc1,
-	0x4e, 0xe9, 0xa9, 0x9e, 0x04, 0xeb, 0xbd, 0x71, 0x06, 0x10, 0x95, 0xd5, 0x17, 0x96, 0x21, 0x7f,
-	0xc7, 0xde, 0xab, 0xdf, 0x21, 0xee, 0xe0, 0x8a, 0x46, 0x58, 0x49, 0xf3, 0x9c, 0xe5, 0x65, 0x9f,
-	0x4b, 0x70, 0xfe, 0x9d, 0x01, 0x6a, 0xda, 0xe1, 0x4f, 0xa1, 0xbd, 0x3a, 0x83, 0xf0, 0x81, 0xa7,
-	0x06, 0x9c, 0x57, 0x8e, 0x2e, 0xef, 0x4a, 0x0c, 0xb8, 0xce, 0xa1, 0xfe, 0xca, 0xa6, 0x81, 0xe5,
-	0xe2, 0x6f, 0x7f, 0xfb, 0xf3, 0x07, 0xa3, 0x8d, 0xc1, 0x7f, 0x9e, 0x4a, 0x38, 0x06, 0x53, 0x09,
-	0xf1, 0xfe, 0xa6, 0x16, 0xeb, 0xbc, 0xb9, 0xc6, 0xea, 0x50, 0x67, 0x32, 0xd4, 0x89, 0xdb, 0xd0,
-	0xa1, 0x2e, 0xd1, 0xc9, 0xf5, 0x91, 0xfb, 0x96, 0x46, 0xfe, 0x57, 0xaf, 0x46, 0xc3, 0xd7, 0x97,
-	0xe8, 0x04, 0xbf, 0x5f, 0x36, 0xcb, 0xc7, 0x3c, 0xa7, 0xc1, 0xe4, 0xdf, 0x7d, 0xae, 0xd2, 0x43,
-	0x67, 0xa8, 0xff, 0xc9, 0xdd, 0x83, 0x5d, 0xb9, 0x7f, 0xb0, 0x2b, 0x4f, 0x0f, 0x36, 0xfa, 0x66,
-	0x61, 0xa3, 0x9f, 0x17, 0x36, 0xfa, 0x75, 0x61, 0xa3, 0xbb, 0x85, 0x8d, 0xfe, 0x58, 0xd8, 0xe8,
-	0xaf, 0x85, 0x5d, 0x79, 0x5a, 0xd8, 0xe8, 0xfb, 0x47, 0xbb, 0x72, 0xf7, 0x68, 0x57, 0xee, 0x1f,
-	0xed, 0xca, 0xb5, 0xb3, 0x32, 0xed, 0x8b, 0x94, 0xdd, 0x7e, 0x19, 0x84, 0x23, 0x3f, 0x62, 0x2c,
-	0x2a, 0x7c, 0xf9, 0xad, 0x81, 0x29, 0x3d, 0x7c, 0xe7, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x9b,
-	0x27, 0xc3, 0x26, 0x6a, 0x06, 0x00, 0x00,
+func (m *Preprocess) GetMaxBrightness() float32 {
+	if m != nil {
+		return m.MaxBrightness
+	}
+	return 0
 }
 
-func (this *GetDetectorsResponse) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
+// Data for a model input tensor
+type InputTensor struct {
+	// The values, converted to the tensor type
+	Values []float32 `protobuf:"fixed32,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	// Or the raw tensor data
+	Data Raw `protobuf:"bytes,2,opt,name=data,proto3,casttype=Raw" json:"data,omitempty"`
+}
 
-	that1, ok := that.(*GetDetectorsResponse)
-	if !ok {
-		that2, ok := that.(GetDetectorsResponse)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+func (m *InputTensor) Reset()      { *m = InputTensor{} }
+func (*InputTensor) ProtoMessage() {}
+func (*InputTensor) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{7}
+}
+func (m *InputTensor) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *InputTensor) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_InputTensor.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+}
+func (m *InputTensor) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InputTensor.Merge(m, src)
+}
+func (m *InputTensor) XXX_Size() int {
+	return m.Size()
+}
+func (m *InputTensor) XXX_DiscardUnknown() {
+	xxx_messageInfo_InputTensor.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InputTensor proto.InternalMessageInfo
+
+func (m *InputTensor) GetValues() []float32 {
+	if m != nil {
+		return m.Values
 	}
-	if len(this.Detectors) != len(that1.Detectors) {
-		return false
+	return nil
+}
+
+func (m *InputTensor) GetData() Raw {
+	if m != nil {
+		return m.Data
 	}
-	for i := range this.Detectors {
-		if !this.Detectors[i].Equal(that1.Detectors[i]) {
-			return false
+	return nil
+}
+
+type DetectRegion struct {
+	// Coordinates
+	Top    float32 `protobuf:"fixed32,1,opt,name=top,proto3" json:"top"`
+	Left   float32 `protobuf:"fixed32,2,opt,name=left,proto3" json:"left"`
+	Bottom float32 `protobuf:"fixed32,3,opt,name=bottom,proto3" json:"bottom"`
+	Right  float32 `protobuf:"fixed32,4,opt,name=right,proto3" json:"right"`
+	// What to detect
+	Detect map[string]float32 `protobuf:"bytes,5,rep,name=detect,proto3" json:"detect,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed32,2,opt,name=value,proto3"`
+	Covers bool               `protobuf:"varint,6,opt,name=covers,proto3" json:"covers,omitempty"`
+}
+
+func (m *DetectRegion) Reset()      { *m = DetectRegion{} }
+func (*DetectRegion) ProtoMessage() {}
+func (*DetectRegion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{8}
+}
+func (m *DetectRegion) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DetectRegion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DetectRegion.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return true
 }
-func (this *Detector) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
+func (m *DetectRegion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DetectRegion.Merge(m, src)
+}
+func (m *DetectRegion) XXX_Size() int {
+	return m.Size()
+}
+func (m *DetectRegion) XXX_DiscardUnknown() {
+	xxx_messageInfo_DetectRegion.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*Detector)
-	if !ok {
-		that2, ok := that.(Detector)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+var xxx_messageInfo_DetectRegion proto.InternalMessageInfo
+
+func (m *DetectRegion) GetTop() float32 {
+	if m != nil {
+		return m.Top
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return 0
+}
+
+func (m *DetectRegion) GetLeft() float32 {
+	if m != nil {
+		return m.Left
 	}
-	if this.Name != that1.Name {
-		return false
+	return 0
+}
+
+func (m *DetectRegion) GetBottom() float32 {
+	if m != nil {
+		return m.Bottom
 	}
-	if this.Type != that1.Type {
-		return false
+	return 0
+}
+
+func (m *DetectRegion) GetRight() float32 {
+	if m != nil {
+		return m.Right
 	}
-	if this.Model != that1.Model {
-		return false
+	return 0
+}
+
+func (m *DetectRegion) GetDetect() map[string]float32 {
+	if m != nil {
+		return m.Detect
 	}
-	if len(this.Labels) != len(that1.Labels) {
-		return false
+	return nil
+}
+
+func (m *DetectRegion) GetCovers() bool {
+	if m != nil {
+		return m.Covers
 	}
-	for i := range this.Labels {
-		if this.Labels[i] != that1.Labels[i] {
-			return false
+	return false
+}
+
+// Area for detection
+type Detection struct {
+	// Coordinates
+	Top        float32 `protobuf:"fixed32,1,opt,name=top,proto3" json:"top"`
+	Left       float32 `protobuf:"fixed32,2,opt,name=left,proto3" json:"left"`
+	Bottom     float32 `protobuf:"fixed32,3,opt,name=bottom,proto3" json:"bottom"`
+	Right      float32 `protobuf:"fixed32,4,opt,name=right,proto3" json:"right"`
+	Label      string  `protobuf:"bytes,5,opt,name=label,proto3" json:"label"`
+	Confidence float32 `protobuf:"fixed32,6,opt,name=confidence,proto3" json:"confidence"`
+	// The id of the object between frames when smoothing
+	Track uint32 `protobuf:"varint,7,opt,name=track,proto3" json:"track,omitempty"`
+	// The detected box before smoothing
+	Raw *Box `protobuf:"bytes,8,opt,name=raw,proto3" json:"raw,omitempty"`
+	// The cascade stage that found it, blank for the detector of the request
+	Stage string `protobuf:"bytes,9,opt,name=stage,proto3" json:"stage,omitempty"`
+	// The detections the cascade stages found in it, like the face of a person or the text of a plate
+	Children []*Detection `protobuf:"bytes,10,rep,name=children,proto3" json:"children,omitempty"`
+	// What the attribute classifiers found in it, like the color of a car
+	Attributes []*Attribute `protobuf:"bytes,11,rep,name=attributes,proto3" json:"attributes,omitempty"`
+}
+
+func (m *Detection) Reset()      { *m = Detection{} }
+func (*Detection) ProtoMessage() {}
+func (*Detection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{9}
+}
+func (m *Detection) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Detection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Detection.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if this.Width != that1.Width {
-		return false
+}
+func (m *Detection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Detection.Merge(m, src)
+}
+func (m *Detection) XXX_Size() int {
+	return m.Size()
+}
+func (m *Detection) XXX_DiscardUnknown() {
+	xxx_messageInfo_Detection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Detection proto.InternalMessageInfo
+
+func (m *Detection) GetTop() float32 {
+	if m != nil {
+		return m.Top
 	}
-	if this.Height != that1.Height {
-		return false
+	return 0
+}
+
+func (m *Detection) GetLeft() float32 {
+	if m != nil {
+		return m.Left
 	}
-	if this.Channels != that1.Channels {
-		return false
+	return 0
+}
+
+func (m *Detection) GetBottom() float32 {
+	if m != nil {
+		return m.Bottom
 	}
-	return true
+	return 0
 }
-func (this *DetectRequest) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *Detection) GetRight() float32 {
+	if m != nil {
+		return m.Right
 	}
+	return 0
+}
 
-	that1, ok := that.(*DetectRequest)
-	if !ok {
-		that2, ok := that.(DetectRequest)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *Detection) GetLabel() string {
+	if m != nil {
+		return m.Label
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return ""
+}
+
+func (m *Detection) GetConfidence() float32 {
+	if m != nil {
+		return m.Confidence
 	}
-	if this.Id != that1.Id {
-		return false
+	return 0
+}
+
+func (m *Detection) GetTrack() uint32 {
+	if m != nil {
+		return m.Track
 	}
-	if this.DetectorName != that1.DetectorName {
-		return false
+	return 0
+}
+
+func (m *Detection) GetRaw() *Box {
+	if m != nil {
+		return m.Raw
 	}
-	if !bytes.Equal(this.Data, that1.Data) {
-		return false
+	return nil
+}
+
+func (m *Detection) GetStage() string {
+	if m != nil {
+		return m.Stage
 	}
-	if this.File != that1.File {
-		return false
+	return ""
+}
+
+func (m *Detection) GetChildren() []*Detection {
+	if m != nil {
+		return m.Children
 	}
-	if len(this.Detect) != len(that1.Detect) {
-		return false
+	return nil
+}
+
+func (m *Detection) GetAttributes() []*Attribute {
+	if m != nil {
+		return m.Attributes
 	}
-	for i := range this.Detect {
-		if this.Detect[i] != that1.Detect[i] {
-			return false
+	return nil
+}
+
+// An attribute of a detection from a classifier run on its area
+type Attribute struct {
+	// The attribute name from the config, like color or helmet
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name"`
+	// The label the classifier returned, like red
+	Value      string  `protobuf:"bytes,2,opt,name=value,proto3" json:"value"`
+	Confidence float32 `protobuf:"fixed32,3,opt,name=confidence,proto3" json:"confidence"`
+}
+
+func (m *Attribute) Reset()      { *m = Attribute{} }
+func (*Attribute) ProtoMessage() {}
+func (*Attribute) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{10}
+}
+func (m *Attribute) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Attribute) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Attribute.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if len(this.Regions) != len(that1.Regions) {
-		return false
+}
+func (m *Attribute) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Attribute.Merge(m, src)
+}
+func (m *Attribute) XXX_Size() int {
+	return m.Size()
+}
+func (m *Attribute) XXX_DiscardUnknown() {
+	xxx_messageInfo_Attribute.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Attribute proto.InternalMessageInfo
+
+func (m *Attribute) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	for i := range this.Regions {
-		if !this.Regions[i].Equal(that1.Regions[i]) {
-			return false
-		}
+	return ""
+}
+
+func (m *Attribute) GetValue() string {
+	if m != nil {
+		return m.Value
 	}
-	return true
+	return ""
 }
-func (this *DetectRegion) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *Attribute) GetConfidence() float32 {
+	if m != nil {
+		return m.Confidence
 	}
+	return 0
+}
 
-	that1, ok := that.(*DetectRegion)
-	if !ok {
-		that2, ok := that.(DetectRegion)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// A box in relative coordinates
+type Box struct {
+	Top    float32 `protobuf:"fixed32,1,opt,name=top,proto3" json:"top,omitempty"`
+	Left   float32 `protobuf:"fixed32,2,opt,name=left,proto3" json:"left,omitempty"`
+	Bottom float32 `protobuf:"fixed32,3,opt,name=bottom,proto3" json:"bottom,omitempty"`
+	Right  float32 `protobuf:"fixed32,4,opt,name=right,proto3" json:"right,omitempty"`
+}
+
+func (m *Box) Reset()      { *m = Box{} }
+func (*Box) ProtoMessage() {}
+func (*Box) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{11}
+}
+func (m *Box) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Box) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Box.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
-	}
-	if this.Top != that1.Top {
-		return false
-	}
-	if this.Left != that1.Left {
-		return false
+}
+func (m *Box) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Box.Merge(m, src)
+}
+func (m *Box) XXX_Size() int {
+	return m.Size()
+}
+func (m *Box) XXX_DiscardUnknown() {
+	xxx_messageInfo_Box.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Box proto.InternalMessageInfo
+
+func (m *Box) GetTop() float32 {
+	if m != nil {
+		return m.Top
 	}
-	if this.Bottom != that1.Bottom {
-		return false
+	return 0
+}
+
+func (m *Box) GetLeft() float32 {
+	if m != nil {
+		return m.Left
 	}
-	if this.Right != that1.Right {
-		return false
+	return 0
+}
+
+func (m *Box) GetBottom() float32 {
+	if m != nil {
+		return m.Bottom
 	}
-	if len(this.Detect) != len(that1.Detect) {
-		return false
+	return 0
+}
+
+func (m *Box) GetRight() float32 {
+	if m != nil {
+		return m.Right
 	}
-	for i := range this.Detect {
-		if this.Detect[i] != that1.Detect[i] {
-			return false
+	return 0
+}
+
+type DetectResponse struct {
+	// The id for the response
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The detected areas
+	Detections []*Detection `protobuf:"bytes,2,rep,name=detections,proto3" json:"detections,omitempty"`
+	// If there was an error (streaming endpoint only)
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// The raw output tensors if requested
+	Outputs []*OutputTensor `protobuf:"bytes,4,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	// The image quality report if the detector has quality checks
+	Quality *Quality `protobuf:"bytes,5,opt,name=quality,proto3" json:"quality,omitempty"`
+	// The detector failed and a fallback detector was used
+	Degraded         bool   `protobuf:"varint,6,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	FallbackDetector string `protobuf:"bytes,7,opt,name=fallback_detector,json=fallbackDetector,proto3" json:"fallback_detector,omitempty"`
+	// The detector is overloaded, try again after this long or reduce the request rate (streaming endpoint only)
+	RetryAfterMs int64 `protobuf:"varint,8,opt,name=retry_after_ms,json=retryAfterMs,proto3" json:"retry_after_ms,omitempty"`
+	// The signature of the detections if doods.signing is set
+	Signature *Signature `protobuf:"bytes,9,opt,name=signature,proto3" json:"signature,omitempty"`
+	// The directory in doods.debug.dir the request was saved to
+	Debug string `protobuf:"bytes,10,opt,name=debug,proto3" json:"debug,omitempty"`
+	// The estimated count of crowd counting (density map) models
+	Density *Density `protobuf:"bytes,11,opt,name=density,proto3" json:"density,omitempty"`
+}
+
+func (m *DetectResponse) Reset()      { *m = DetectResponse{} }
+func (*DetectResponse) ProtoMessage() {}
+func (*DetectResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{12}
+}
+func (m *DetectResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DetectResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DetectResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if this.Covers != that1.Covers {
-		return false
-	}
-	return true
 }
-func (this *Detection) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
+func (m *DetectResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DetectResponse.Merge(m, src)
+}
+func (m *DetectResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DetectResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DetectResponse.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*Detection)
-	if !ok {
-		that2, ok := that.(Detection)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+var xxx_messageInfo_DetectResponse proto.InternalMessageInfo
+
+func (m *DetectResponse) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return ""
+}
+
+func (m *DetectResponse) GetDetections() []*Detection {
+	if m != nil {
+		return m.Detections
 	}
-	if this.Top != that1.Top {
-		return false
+	return nil
+}
+
+func (m *DetectResponse) GetError() string {
+	if m != nil {
+		return m.Error
 	}
-	if this.Left != that1.Left {
-		return false
+	return ""
+}
+
+func (m *DetectResponse) GetOutputs() []*OutputTensor {
+	if m != nil {
+		return m.Outputs
 	}
-	if this.Bottom != that1.Bottom {
-		return false
+	return nil
+}
+
+func (m *DetectResponse) GetQuality() *Quality {
+	if m != nil {
+		return m.Quality
 	}
-	if this.Right != that1.Right {
-		return false
+	return nil
+}
+
+func (m *DetectResponse) GetDegraded() bool {
+	if m != nil {
+		return m.Degraded
 	}
-	if this.Label != that1.Label {
-		return false
+	return false
+}
+
+func (m *DetectResponse) GetFallbackDetector() string {
+	if m != nil {
+		return m.FallbackDetector
 	}
-	if this.Confidence != that1.Confidence {
-		return false
+	return ""
+}
+
+func (m *DetectResponse) GetRetryAfterMs() int64 {
+	if m != nil {
+		return m.RetryAfterMs
 	}
-	return true
+	return 0
 }
-func (this *DetectResponse) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *DetectResponse) GetSignature() *Signature {
+	if m != nil {
+		return m.Signature
 	}
+	return nil
+}
 
-	that1, ok := that.(*DetectResponse)
-	if !ok {
-		that2, ok := that.(DetectResponse)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *DetectResponse) GetDebug() string {
+	if m != nil {
+		return m.Debug
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return ""
+}
+
+func (m *DetectResponse) GetDensity() *Density {
+	if m != nil {
+		return m.Density
 	}
-	if this.Id != that1.Id {
-		return false
-	}
-	if len(this.Detections) != len(that1.Detections) {
-		return false
-	}
-	for i := range this.Detections {
-		if !this.Detections[i].Equal(that1.Detections[i]) {
-			return false
+	return nil
+}
+
+// A signature of the detections of a response so they can be checked for changes
+type Signature struct {
+	// hmac-sha256 or ed25519
+	Algorithm string `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	// The doods.signing.key_id to find the key to check it with
+	KeyId string `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// When the detections were signed in unix milliseconds
+	SignedAt int64 `protobuf:"varint,3,opt,name=signed_at,json=signedAt,proto3" json:"signed_at,omitempty"`
+	// The hex SHA-256 of the image the detections are from
+	ImageSha256 string `protobuf:"bytes,4,opt,name=image_sha256,json=imageSha256,proto3" json:"image_sha256,omitempty"`
+	// The base64 signature of the SignedDetections
+	Value string `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *Signature) Reset()      { *m = Signature{} }
+func (*Signature) ProtoMessage() {}
+func (*Signature) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{13}
+}
+func (m *Signature) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Signature) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Signature.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if this.Error != that1.Error {
-		return false
-	}
-	return true
 }
-func (this *GetDetectorsResponse) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 5)
-	s = append(s, "&odrpc.GetDetectorsResponse{")
-	if this.Detectors != nil {
-		s = append(s, "Detectors: "+fmt.Sprintf("%#v", this.Detectors)+",\n")
-	}
-	s = append(s, "}")
-	return strings.Join(s, "")
+func (m *Signature) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Signature.Merge(m, src)
 }
-func (this *Detector) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 11)
-	s = append(s, "&odrpc.Detector{")
-	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
-	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
-	s = append(s, "Model: "+fmt.Sprintf("%#v", this.Model)+",\n")
-	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
-	s = append(s, "Width: "+fmt.Sprintf("%#v", this.Width)+",\n")
-	s = append(s, "Height: "+fmt.Sprintf("%#v", this.Height)+",\n")
-	s = append(s, "Channels: "+fmt.Sprintf("%#v", this.Channels)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+func (m *Signature) XXX_Size() int {
+	return m.Size()
 }
-func (this *DetectRequest) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 10)
-	s = append(s, "&odrpc.DetectRequest{")
-	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
-	s = append(s, "DetectorName: "+fmt.Sprintf("%#v", this.DetectorName)+",\n")
-	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
-	s = append(s, "File: "+fmt.Sprintf("%#v", this.File)+",\n")
-	keysForDetect := make([]string, 0, len(this.Detect))
-	for k, _ := range this.Detect {
-		keysForDetect = append(keysForDetect, k)
-	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
-	mapStringForDetect := "map[string]float32{"
-	for _, k := range keysForDetect {
-		mapStringForDetect += fmt.Sprintf("%#v: %#v,", k, this.Detect[k])
-	}
-	mapStringForDetect += "}"
-	if this.Detect != nil {
-		s = append(s, "Detect: "+mapStringForDetect+",\n")
-	}
-	if this.Regions != nil {
-		s = append(s, "Regions: "+fmt.Sprintf("%#v", this.Regions)+",\n")
-	}
-	s = append(s, "}")
-	return strings.Join(s, "")
+func (m *Signature) XXX_DiscardUnknown() {
+	xxx_messageInfo_Signature.DiscardUnknown(m)
 }
-func (this *DetectRegion) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 10)
-	s = append(s, "&odrpc.DetectRegion{")
-	s = append(s, "Top: "+fmt.Sprintf("%#v", this.Top)+",\n")
-	s = append(s, "Left: "+fmt.Sprintf("%#v", this.Left)+",\n")
-	s = append(s, "Bottom: "+fmt.Sprintf("%#v", this.Bottom)+",\n")
-	s = append(s, "Right: "+fmt.Sprintf("%#v", this.Right)+",\n")
-	keysForDetect := make([]string, 0, len(this.Detect))
-	for k, _ := range this.Detect {
-		keysForDetect = append(keysForDetect, k)
-	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
-	mapStringForDetect := "map[string]float32{"
-	for _, k := range keysForDetect {
-		mapStringForDetect += fmt.Sprintf("%#v: %#v,", k, this.Detect[k])
-	}
-	mapStringForDetect += "}"
-	if this.Detect != nil {
-		s = append(s, "Detect: "+mapStringForDetect+",\n")
+
+var xxx_messageInfo_Signature proto.InternalMessageInfo
+
+func (m *Signature) GetAlgorithm() string {
+	if m != nil {
+		return m.Algorithm
 	}
-	s = append(s, "Covers: "+fmt.Sprintf("%#v", this.Covers)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return ""
 }
-func (this *Detection) GoString() string {
-	if this == nil {
-		return "nil"
+
+func (m *Signature) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
 	}
-	s := make([]string, 0, 10)
-	s = append(s, "&odrpc.Detection{")
-	s = append(s, "Top: "+fmt.Sprintf("%#v", this.Top)+",\n")
-	s = append(s, "Left: "+fmt.Sprintf("%#v", this.Left)+",\n")
-	s = append(s, "Bottom: "+fmt.Sprintf("%#v", this.Bottom)+",\n")
-	s = append(s, "Right: "+fmt.Sprintf("%#v", this.Right)+",\n")
-	s = append(s, "Label: "+fmt.Sprintf("%#v", this.Label)+",\n")
-	s = append(s, "Confidence: "+fmt.Sprintf("%#v", this.Confidence)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return ""
 }
-func (this *DetectResponse) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 7)
-	s = append(s, "&odrpc.DetectResponse{")
-	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
-	if this.Detections != nil {
-		s = append(s, "Detections: "+fmt.Sprintf("%#v", this.Detections)+",\n")
+
+func (m *Signature) GetSignedAt() int64 {
+	if m != nil {
+		return m.SignedAt
 	}
-	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return 0
 }
-func valueToGoStringRpc(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+func (m *Signature) GetImageSha256() string {
+	if m != nil {
+		return m.ImageSha256
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+	return ""
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// OdrpcClient is the client API for Odrpc service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type OdrpcClient interface {
-	// Get Config
-	GetDetectors(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*GetDetectorsResponse, error)
-	// Process an request
-	Detect(ctx context.Context, in *DetectRequest, opts ...grpc.CallOption) (*DetectResponse, error)
-	// Process stream requests
-	DetectStream(ctx context.Context, opts ...grpc.CallOption) (Odrpc_DetectStreamClient, error)
+func (m *Signature) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
 }
 
-type odrpcClient struct {
-	cc *grpc.ClientConn
+// What a signature signs, the protobuf encoding of this with the values from the response and its signature
+type SignedDetections struct {
+	Id          string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Detections  []*Detection `protobuf:"bytes,2,rep,name=detections,proto3" json:"detections,omitempty"`
+	Algorithm   string       `protobuf:"bytes,3,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	KeyId       string       `protobuf:"bytes,4,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	SignedAt    int64        `protobuf:"varint,5,opt,name=signed_at,json=signedAt,proto3" json:"signed_at,omitempty"`
+	ImageSha256 string       `protobuf:"bytes,6,opt,name=image_sha256,json=imageSha256,proto3" json:"image_sha256,omitempty"`
 }
 
-func NewOdrpcClient(cc *grpc.ClientConn) OdrpcClient {
-	return &odrpcClient{cc}
+func (m *SignedDetections) Reset()      { *m = SignedDetections{} }
+func (*SignedDetections) ProtoMessage() {}
+func (*SignedDetections) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{14}
 }
-
-func (c *odrpcClient) GetDetectors(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*GetDetectorsResponse, error) {
-	out := new(GetDetectorsResponse)
-	err := c.cc.Invoke(ctx, "/odrpc.odrpc/GetDetectors", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SignedDetections) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SignedDetections) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SignedDetections.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return out, nil
+}
+func (m *SignedDetections) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedDetections.Merge(m, src)
+}
+func (m *SignedDetections) XXX_Size() int {
+	return m.Size()
+}
+func (m *SignedDetections) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedDetections.DiscardUnknown(m)
 }
 
-func (c *odrpcClient) Detect(ctx context.Context, in *DetectRequest, opts ...grpc.CallOption) (*DetectResponse, error) {
-	out := new(DetectResponse)
-	err := c.cc.Invoke(ctx, "/odrpc.odrpc/Detect", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SignedDetections proto.InternalMessageInfo
+
+func (m *SignedDetections) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return out, nil
+	return ""
 }
 
-func (c *odrpcClient) DetectStream(ctx context.Context, opts ...grpc.CallOption) (Odrpc_DetectStreamClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Odrpc_serviceDesc.Streams[0], "/odrpc.odrpc/DetectStream", opts...)
-	if err != nil {
-		return nil, err
+func (m *SignedDetections) GetDetections() []*Detection {
+	if m != nil {
+		return m.Detections
 	}
-	x := &odrpcDetectStreamClient{stream}
-	return x, nil
+	return nil
 }
 
-type Odrpc_DetectStreamClient interface {
-	Send(*DetectRequest) error
-	Recv() (*DetectResponse, error)
-	grpc.ClientStream
+func (m *SignedDetections) GetAlgorithm() string {
+	if m != nil {
+		return m.Algorithm
+	}
+	return ""
 }
 
-type odrpcDetectStreamClient struct {
-	grpc.ClientStream
+func (m *SignedDetections) GetKeyId() string {
+	if m != nil {
+		return m.KeyId
+	}
+	return ""
 }
 
-func (x *odrpcDetectStreamClient) Send(m *DetectRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (m *SignedDetections) GetSignedAt() int64 {
+	if m != nil {
+		return m.SignedAt
+	}
+	return 0
 }
 
-func (x *odrpcDetectStreamClient) Recv() (*DetectResponse, error) {
-	m := new(DetectResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *SignedDetections) GetImageSha256() string {
+	if m != nil {
+		return m.ImageSha256
 	}
-	return m, nil
+	return ""
 }
 
-// OdrpcServer is the server API for Odrpc service.
-type OdrpcServer interface {
-	// Get Config
-	GetDetectors(context.Context, *empty.Empty) (*GetDetectorsResponse, error)
-	// Process an request
-	Detect(context.Context, *DetectRequest) (*DetectResponse, error)
-	// Process stream requests
-	DetectStream(Odrpc_DetectStreamServer) error
+// A result of a cascade detection
+type CascadeResult struct {
+	// The id of the request
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The cascade stage, blank for the first stage
+	Stage string `protobuf:"bytes,2,opt,name=stage,proto3" json:"stage,omitempty"`
+	// The index of the first stage detection that was refined or that the refined detection is in, -1 for the first stage
+	Parent int32 `protobuf:"varint,3,opt,name=parent,proto3" json:"parent,omitempty"`
+	// The detections in the coordinates of the whole image
+	Detections []*Detection `protobuf:"bytes,4,rep,name=detections,proto3" json:"detections,omitempty"`
+	// If the stage failed
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// The last result for the request
+	Done bool `protobuf:"varint,6,opt,name=done,proto3" json:"done,omitempty"`
+	// The stage of the detection that was refined, blank for the first stage
+	ParentStage string `protobuf:"bytes,7,opt,name=parent_stage,json=parentStage,proto3" json:"parent_stage,omitempty"`
+	// The path to the refined detection, the index of the first stage detection and then its index in the children
+	// of each level
+	Path []int32 `protobuf:"varint,8,rep,packed,name=path,proto3" json:"path,omitempty"`
+	// The first stage detections with the stage detections as children (last result only)
+	Objects []*Detection `protobuf:"bytes,9,rep,name=objects,proto3" json:"objects,omitempty"`
 }
 
-func RegisterOdrpcServer(s *grpc.Server, srv OdrpcServer) {
-	s.RegisterService(&_Odrpc_serviceDesc, srv)
+func (m *CascadeResult) Reset()      { *m = CascadeResult{} }
+func (*CascadeResult) ProtoMessage() {}
+func (*CascadeResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{15}
 }
-
-func _Odrpc_GetDetectors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(empty.Empty)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(OdrpcServer).GetDetectors(ctx, in)
+func (m *CascadeResult) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CascadeResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CascadeResult.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/odrpc.odrpc/GetDetectors",
+}
+func (m *CascadeResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CascadeResult.Merge(m, src)
+}
+func (m *CascadeResult) XXX_Size() int {
+	return m.Size()
+}
+func (m *CascadeResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_CascadeResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CascadeResult proto.InternalMessageInfo
+
+func (m *CascadeResult) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OdrpcServer).GetDetectors(ctx, req.(*empty.Empty))
+	return ""
+}
+
+func (m *CascadeResult) GetStage() string {
+	if m != nil {
+		return m.Stage
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _Odrpc_Detect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DetectRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *CascadeResult) GetParent() int32 {
+	if m != nil {
+		return m.Parent
 	}
-	if interceptor == nil {
-		return srv.(OdrpcServer).Detect(ctx, in)
+	return 0
+}
+
+func (m *CascadeResult) GetDetections() []*Detection {
+	if m != nil {
+		return m.Detections
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/odrpc.odrpc/Detect",
+	return nil
+}
+
+func (m *CascadeResult) GetError() string {
+	if m != nil {
+		return m.Error
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OdrpcServer).Detect(ctx, req.(*DetectRequest))
+	return ""
+}
+
+func (m *CascadeResult) GetDone() bool {
+	if m != nil {
+		return m.Done
 	}
-	return interceptor(ctx, in, info, handler)
+	return false
 }
 
-func _Odrpc_DetectStream_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(OdrpcServer).DetectStream(&odrpcDetectStreamServer{stream})
+func (m *CascadeResult) GetParentStage() string {
+	if m != nil {
+		return m.ParentStage
+	}
+	return ""
 }
 
-type Odrpc_DetectStreamServer interface {
-	Send(*DetectResponse) error
-	Recv() (*DetectRequest, error)
-	grpc.ServerStream
+func (m *CascadeResult) GetPath() []int32 {
+	if m != nil {
+		return m.Path
+	}
+	return nil
 }
 
-type odrpcDetectStreamServer struct {
-	grpc.ServerStream
+func (m *CascadeResult) GetObjects() []*Detection {
+	if m != nil {
+		return m.Objects
+	}
+	return nil
 }
 
-func (x *odrpcDetectStreamServer) Send(m *DetectResponse) error {
-	return x.ServerStream.SendMsg(m)
+// Detect in the areas of an image
+type DetectBoxesRequest struct {
+	// The ID for the request
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The detector to run on the boxes
+	DetectorName string `protobuf:"bytes,2,opt,name=detector_name,json=detectorName,proto3" json:"detector_name,omitempty"`
+	// The image data
+	Data Raw `protobuf:"bytes,3,opt,name=data,proto3,casttype=Raw" json:"data"`
+	// A filename
+	File string `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	// Fetch the image from a url
+	ImageUrl string `protobuf:"bytes,5,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	// The boxes in relative coordinates (0-1) or in pixels if pixels is set
+	Boxes  []*Box `protobuf:"bytes,6,rep,name=boxes,proto3" json:"boxes,omitempty"`
+	Pixels bool   `protobuf:"varint,7,opt,name=pixels,proto3" json:"pixels,omitempty"`
+	// Grow each box by this fraction of its size on each side
+	Padding float32 `protobuf:"fixed32,8,opt,name=padding,proto3" json:"padding,omitempty"`
+	// What to detect
+	Detect map[string]float32 `protobuf:"bytes,9,rep,name=detect,proto3" json:"detect,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed32,2,opt,name=value,proto3"`
+	// The language for the returned labels
+	Language string `protobuf:"bytes,10,opt,name=language,proto3" json:"language,omitempty"`
+	// Labels to never return
+	Ignore []string `protobuf:"bytes,11,rep,name=ignore,proto3" json:"ignore,omitempty"`
+	// Only return the best detections of each box, all if 0
+	MaxDetections int32 `protobuf:"varint,12,opt,name=max_detections,json=maxDetections,proto3" json:"max_detections,omitempty"`
 }
 
-func (x *odrpcDetectStreamServer) Recv() (*DetectRequest, error) {
-	m := new(DetectRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *DetectBoxesRequest) Reset()      { *m = DetectBoxesRequest{} }
+func (*DetectBoxesRequest) ProtoMessage() {}
+func (*DetectBoxesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{16}
+}
+func (m *DetectBoxesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DetectBoxesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DetectBoxesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return m, nil
+}
+func (m *DetectBoxesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DetectBoxesRequest.Merge(m, src)
+}
+func (m *DetectBoxesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DetectBoxesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DetectBoxesRequest.DiscardUnknown(m)
 }
 
-var _Odrpc_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "odrpc.odrpc",
-	HandlerType: (*OdrpcServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetDetectors",
-			Handler:    _Odrpc_GetDetectors_Handler,
-		},
-		{
-			MethodName: "Detect",
-			Handler:    _Odrpc_Detect_Handler,
-		},
-	},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "DetectStream",
-			Handler:       _Odrpc_DetectStream_Handler,
-			ServerStreams: true,
-			ClientStreams: true,
-		},
-	},
-	Metadata: "odrpc/rpc.proto",
+var xxx_messageInfo_DetectBoxesRequest proto.InternalMessageInfo
+
+func (m *DetectBoxesRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
 }
 
-func (m *GetDetectorsResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *DetectBoxesRequest) GetDetectorName() string {
+	if m != nil {
+		return m.DetectorName
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *GetDetectorsResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Detectors) > 0 {
-		for _, msg := range m.Detectors {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
+func (m *DetectBoxesRequest) GetData() Raw {
+	if m != nil {
+		return m.Data
 	}
-	return i, nil
+	return nil
 }
 
-func (m *Detector) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *DetectBoxesRequest) GetFile() string {
+	if m != nil {
+		return m.File
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Detector) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Name) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Name)))
-		i += copy(dAtA[i:], m.Name)
+func (m *DetectBoxesRequest) GetImageUrl() string {
+	if m != nil {
+		return m.ImageUrl
 	}
-	if len(m.Type) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Type)))
-		i += copy(dAtA[i:], m.Type)
+	return ""
+}
+
+func (m *DetectBoxesRequest) GetBoxes() []*Box {
+	if m != nil {
+		return m.Boxes
 	}
-	if len(m.Model) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Model)))
-		i += copy(dAtA[i:], m.Model)
+	return nil
+}
+
+func (m *DetectBoxesRequest) GetPixels() bool {
+	if m != nil {
+		return m.Pixels
 	}
-	if len(m.Labels) > 0 {
-		for _, s := range m.Labels {
-			dAtA[i] = 0x22
-			i++
-			l = len(s)
-			for l >= 1<<7 {
-				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
-				l >>= 7
-				i++
-			}
-			dAtA[i] = uint8(l)
-			i++
-			i += copy(dAtA[i:], s)
-		}
+	return false
+}
+
+func (m *DetectBoxesRequest) GetPadding() float32 {
+	if m != nil {
+		return m.Padding
 	}
-	if m.Width != 0 {
-		dAtA[i] = 0x28
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(m.Width))
+	return 0
+}
+
+func (m *DetectBoxesRequest) GetDetect() map[string]float32 {
+	if m != nil {
+		return m.Detect
 	}
-	if m.Height != 0 {
-		dAtA[i] = 0x30
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(m.Height))
+	return nil
+}
+
+func (m *DetectBoxesRequest) GetLanguage() string {
+	if m != nil {
+		return m.Language
 	}
-	if m.Channels != 0 {
-		dAtA[i] = 0x38
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(m.Channels))
+	return ""
+}
+
+func (m *DetectBoxesRequest) GetIgnore() []string {
+	if m != nil {
+		return m.Ignore
 	}
-	return i, nil
+	return nil
 }
 
-func (m *DetectRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *DetectBoxesRequest) GetMaxDetections() int32 {
+	if m != nil {
+		return m.MaxDetections
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *DetectRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Id) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
-		i += copy(dAtA[i:], m.Id)
+// The detections in a box
+type BoxResult struct {
+	// The index of the box in the request
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// The area that was detected in relative coordinates, with the padding
+	Box *Box `protobuf:"bytes,2,opt,name=box,proto3" json:"box,omitempty"`
+	// The detections in the coordinates of the whole image
+	Detections []*Detection `protobuf:"bytes,3,rep,name=detections,proto3" json:"detections,omitempty"`
+	// If the box failed
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *BoxResult) Reset()      { *m = BoxResult{} }
+func (*BoxResult) ProtoMessage() {}
+func (*BoxResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{17}
+}
+func (m *BoxResult) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *BoxResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_BoxResult.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if len(m.DetectorName) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.DetectorName)))
-		i += copy(dAtA[i:], m.DetectorName)
+}
+func (m *BoxResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BoxResult.Merge(m, src)
+}
+func (m *BoxResult) XXX_Size() int {
+	return m.Size()
+}
+func (m *BoxResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BoxResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BoxResult proto.InternalMessageInfo
+
+func (m *BoxResult) GetIndex() int32 {
+	if m != nil {
+		return m.Index
 	}
-	if len(m.Data) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Data)))
-		i += copy(dAtA[i:], m.Data)
+	return 0
+}
+
+func (m *BoxResult) GetBox() *Box {
+	if m != nil {
+		return m.Box
 	}
-	if len(m.File) > 0 {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.File)))
-		i += copy(dAtA[i:], m.File)
+	return nil
+}
+
+func (m *BoxResult) GetDetections() []*Detection {
+	if m != nil {
+		return m.Detections
 	}
-	if len(m.Detect) > 0 {
-		for k, _ := range m.Detect {
-			dAtA[i] = 0x2a
-			i++
-			v := m.Detect[k]
-			mapSize := 1 + len(k) + sovRpc(uint64(len(k))) + 1 + 4
-			i = encodeVarintRpc(dAtA, i, uint64(mapSize))
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(len(k)))
-			i += copy(dAtA[i:], k)
-			dAtA[i] = 0x15
-			i++
-			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(v))))
-			i += 4
-		}
+	return nil
+}
+
+func (m *BoxResult) GetError() string {
+	if m != nil {
+		return m.Error
 	}
-	if len(m.Regions) > 0 {
-		for _, msg := range m.Regions {
-			dAtA[i] = 0x32
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
+	return ""
+}
+
+type DetectBoxesResponse struct {
+	// The id for the response
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The results in the order of the boxes
+	Results []*BoxResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (m *DetectBoxesResponse) Reset()      { *m = DetectBoxesResponse{} }
+func (*DetectBoxesResponse) ProtoMessage() {}
+func (*DetectBoxesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{18}
+}
+func (m *DetectBoxesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DetectBoxesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DetectBoxesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *DetectBoxesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DetectBoxesResponse.Merge(m, src)
+}
+func (m *DetectBoxesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DetectBoxesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DetectBoxesResponse.DiscardUnknown(m)
 }
 
-func (m *DetectRegion) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_DetectBoxesResponse proto.InternalMessageInfo
+
+func (m *DetectBoxesResponse) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *DetectRegion) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Top != 0 {
-		dAtA[i] = 0xd
-		i++
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Top))))
-		i += 4
-	}
-	if m.Left != 0 {
-		dAtA[i] = 0x15
-		i++
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Left))))
-		i += 4
-	}
-	if m.Bottom != 0 {
-		dAtA[i] = 0x1d
-		i++
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Bottom))))
-		i += 4
-	}
-	if m.Right != 0 {
-		dAtA[i] = 0x25
-		i++
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Right))))
-		i += 4
-	}
-	if len(m.Detect) > 0 {
-		for k, _ := range m.Detect {
-			dAtA[i] = 0x2a
-			i++
-			v := m.Detect[k]
-			mapSize := 1 + len(k) + sovRpc(uint64(len(k))) + 1 + 4
-			i = encodeVarintRpc(dAtA, i, uint64(mapSize))
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(len(k)))
-			i += copy(dAtA[i:], k)
-			dAtA[i] = 0x15
-			i++
-			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(v))))
-			i += 4
-		}
+func (m *DetectBoxesResponse) GetResults() []*BoxResult {
+	if m != nil {
+		return m.Results
 	}
-	if m.Covers {
-		dAtA[i] = 0x30
-		i++
-		if m.Covers {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	return nil
+}
+
+// Compare two images of the same scene
+type DiffRequest struct {
+	// The ID for the request
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The earlier image
+	Before Raw `protobuf:"bytes,2,opt,name=before,proto3,casttype=Raw" json:"before,omitempty"`
+	// The later image
+	After Raw `protobuf:"bytes,3,opt,name=after,proto3,casttype=Raw" json:"after,omitempty"`
+	// Or fetch the images from urls
+	BeforeUrl string `protobuf:"bytes,4,opt,name=before_url,json=beforeUrl,proto3" json:"before_url,omitempty"`
+	AfterUrl  string `protobuf:"bytes,5,opt,name=after_url,json=afterUrl,proto3" json:"after_url,omitempty"`
+	// The brightness difference (0-255) for a pixel to be changed, default 25
+	Threshold int32 `protobuf:"varint,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// The smallest region as a fraction of the image, default 0.001
+	MinArea float32 `protobuf:"fixed32,7,opt,name=min_area,json=minArea,proto3" json:"min_area,omitempty"`
+	// The width the images are compared at, default 320
+	Width int32 `protobuf:"varint,8,opt,name=width,proto3" json:"width,omitempty"`
+}
+
+func (m *DiffRequest) Reset()      { *m = DiffRequest{} }
+func (*DiffRequest) ProtoMessage() {}
+func (*DiffRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{19}
+}
+func (m *DiffRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DiffRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DiffRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
-		i++
+		return b[:n], nil
 	}
-	return i, nil
+}
+func (m *DiffRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffRequest.Merge(m, src)
+}
+func (m *DiffRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DiffRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffRequest.DiscardUnknown(m)
 }
 
-func (m *Detection) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_DiffRequest proto.InternalMessageInfo
+
+func (m *DiffRequest) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Detection) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Top != 0 {
-		dAtA[i] = 0xd
-		i++
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Top))))
-		i += 4
-	}
-	if m.Left != 0 {
-		dAtA[i] = 0x15
-		i++
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Left))))
-		i += 4
+func (m *DiffRequest) GetBefore() Raw {
+	if m != nil {
+		return m.Before
 	}
-	if m.Bottom != 0 {
-		dAtA[i] = 0x1d
-		i++
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Bottom))))
-		i += 4
+	return nil
+}
+
+func (m *DiffRequest) GetAfter() Raw {
+	if m != nil {
+		return m.After
 	}
-	if m.Right != 0 {
-		dAtA[i] = 0x25
-		i++
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Right))))
-		i += 4
+	return nil
+}
+
+func (m *DiffRequest) GetBeforeUrl() string {
+	if m != nil {
+		return m.BeforeUrl
 	}
-	if len(m.Label) > 0 {
-		dAtA[i] = 0x2a
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Label)))
-		i += copy(dAtA[i:], m.Label)
+	return ""
+}
+
+func (m *DiffRequest) GetAfterUrl() string {
+	if m != nil {
+		return m.AfterUrl
 	}
-	if m.Confidence != 0 {
-		dAtA[i] = 0x35
-		i++
-		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Confidence))))
-		i += 4
+	return ""
+}
+
+func (m *DiffRequest) GetThreshold() int32 {
+	if m != nil {
+		return m.Threshold
 	}
-	return i, nil
+	return 0
 }
 
-func (m *DetectResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *DiffRequest) GetMinArea() float32 {
+	if m != nil {
+		return m.MinArea
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *DetectResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Id) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
-		i += copy(dAtA[i:], m.Id)
+func (m *DiffRequest) GetWidth() int32 {
+	if m != nil {
+		return m.Width
 	}
-	if len(m.Detections) > 0 {
-		for _, msg := range m.Detections {
-			dAtA[i] = 0x12
-			i++
-			i = encodeVarintRpc(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
+	return 0
+}
+
+// An area that changed
+type ChangedRegion struct {
+	// The bounding box in relative coordinates
+	Box *Box `protobuf:"bytes,1,opt,name=box,proto3" json:"box,omitempty"`
+	// The percentage of the pixels in the box that changed
+	Score float32 `protobuf:"fixed32,2,opt,name=score,proto3" json:"score,omitempty"`
+}
+
+func (m *ChangedRegion) Reset()      { *m = ChangedRegion{} }
+func (*ChangedRegion) ProtoMessage() {}
+func (*ChangedRegion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{20}
+}
+func (m *ChangedRegion) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ChangedRegion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ChangedRegion.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if len(m.Error) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintRpc(dAtA, i, uint64(len(m.Error)))
-		i += copy(dAtA[i:], m.Error)
-	}
-	return i, nil
+}
+func (m *ChangedRegion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChangedRegion.Merge(m, src)
+}
+func (m *ChangedRegion) XXX_Size() int {
+	return m.Size()
+}
+func (m *ChangedRegion) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChangedRegion.DiscardUnknown(m)
 }
 
-func encodeVarintRpc(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+var xxx_messageInfo_ChangedRegion proto.InternalMessageInfo
+
+func (m *ChangedRegion) GetBox() *Box {
+	if m != nil {
+		return m.Box
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return nil
 }
-func (m *GetDetectorsResponse) Size() (n int) {
-	if m == nil {
-		return 0
+
+func (m *ChangedRegion) GetScore() float32 {
+	if m != nil {
+		return m.Score
 	}
-	var l int
-	_ = l
-	if len(m.Detectors) > 0 {
-		for _, e := range m.Detectors {
-			l = e.Size()
-			n += 1 + l + sovRpc(uint64(l))
+	return 0
+}
+
+type DiffResponse struct {
+	// The id for the response
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The changed regions, largest first
+	Regions []*ChangedRegion `protobuf:"bytes,2,rep,name=regions,proto3" json:"regions"`
+	// The percentage of the image that changed
+	Score float32 `protobuf:"fixed32,3,opt,name=score,proto3" json:"score"`
+}
+
+func (m *DiffResponse) Reset()      { *m = DiffResponse{} }
+func (*DiffResponse) ProtoMessage() {}
+func (*DiffResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{21}
+}
+func (m *DiffResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DiffResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DiffResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	return n
+}
+func (m *DiffResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffResponse.Merge(m, src)
+}
+func (m *DiffResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DiffResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffResponse.DiscardUnknown(m)
 }
 
-func (m *Detector) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
+var xxx_messageInfo_DiffResponse proto.InternalMessageInfo
+
+func (m *DiffResponse) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	l = len(m.Type)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
+	return ""
+}
+
+func (m *DiffResponse) GetRegions() []*ChangedRegion {
+	if m != nil {
+		return m.Regions
 	}
-	l = len(m.Model)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
+	return nil
+}
+
+func (m *DiffResponse) GetScore() float32 {
+	if m != nil {
+		return m.Score
 	}
-	if len(m.Labels) > 0 {
-		for _, s := range m.Labels {
-			l = len(s)
-			n += 1 + l + sovRpc(uint64(l))
+	return 0
+}
+
+// Prepare an image for a detector
+type PreprocessRequest struct {
+	// The ID for the request
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The detector whose input is prepared
+	DetectorName string `protobuf:"bytes,2,opt,name=detector_name,json=detectorName,proto3" json:"detector_name,omitempty"`
+	// The image data
+	Data Raw `protobuf:"bytes,3,opt,name=data,proto3,casttype=Raw" json:"data"`
+	// A filename
+	File string `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	// Fetch the image from a url
+	ImageUrl string `protobuf:"bytes,5,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	// ppm (default), rgb or tensor
+	Format string `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`
+	// Keep the aspect ratio and pad the image instead of stretching it
+	Letterbox bool `protobuf:"varint,7,opt,name=letterbox,proto3" json:"letterbox,omitempty"`
+	// Enhance, rotate and flip the image like a detect request
+	Preprocess *Preprocess `protobuf:"bytes,8,opt,name=preprocess,proto3" json:"preprocess,omitempty"`
+	Rotate     int32       `protobuf:"varint,9,opt,name=rotate,proto3" json:"rotate,omitempty"`
+	Flip       string      `protobuf:"bytes,10,opt,name=flip,proto3" json:"flip,omitempty"`
+}
+
+func (m *PreprocessRequest) Reset()      { *m = PreprocessRequest{} }
+func (*PreprocessRequest) ProtoMessage() {}
+func (*PreprocessRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{22}
+}
+func (m *PreprocessRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PreprocessRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PreprocessRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if m.Width != 0 {
-		n += 1 + sovRpc(uint64(m.Width))
-	}
-	if m.Height != 0 {
-		n += 1 + sovRpc(uint64(m.Height))
-	}
-	if m.Channels != 0 {
-		n += 1 + sovRpc(uint64(m.Channels))
-	}
-	return n
+}
+func (m *PreprocessRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreprocessRequest.Merge(m, src)
+}
+func (m *PreprocessRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PreprocessRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreprocessRequest.DiscardUnknown(m)
 }
 
-func (m *DetectRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
-	}
-	l = len(m.DetectorName)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
+var xxx_messageInfo_PreprocessRequest proto.InternalMessageInfo
+
+func (m *PreprocessRequest) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	l = len(m.Data)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
+	return ""
+}
+
+func (m *PreprocessRequest) GetDetectorName() string {
+	if m != nil {
+		return m.DetectorName
 	}
-	l = len(m.File)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
+	return ""
+}
+
+func (m *PreprocessRequest) GetData() Raw {
+	if m != nil {
+		return m.Data
 	}
-	if len(m.Detect) > 0 {
-		for k, v := range m.Detect {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovRpc(uint64(len(k))) + 1 + 4
-			n += mapEntrySize + 1 + sovRpc(uint64(mapEntrySize))
-		}
+	return nil
+}
+
+func (m *PreprocessRequest) GetFile() string {
+	if m != nil {
+		return m.File
 	}
-	if len(m.Regions) > 0 {
-		for _, e := range m.Regions {
-			l = e.Size()
-			n += 1 + l + sovRpc(uint64(l))
-		}
+	return ""
+}
+
+func (m *PreprocessRequest) GetImageUrl() string {
+	if m != nil {
+		return m.ImageUrl
 	}
-	return n
+	return ""
 }
 
-func (m *DetectRegion) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *PreprocessRequest) GetFormat() string {
+	if m != nil {
+		return m.Format
 	}
-	var l int
-	_ = l
-	if m.Top != 0 {
-		n += 5
+	return ""
+}
+
+func (m *PreprocessRequest) GetLetterbox() bool {
+	if m != nil {
+		return m.Letterbox
 	}
-	if m.Left != 0 {
-		n += 5
+	return false
+}
+
+func (m *PreprocessRequest) GetPreprocess() *Preprocess {
+	if m != nil {
+		return m.Preprocess
 	}
-	if m.Bottom != 0 {
-		n += 5
+	return nil
+}
+
+func (m *PreprocessRequest) GetRotate() int32 {
+	if m != nil {
+		return m.Rotate
 	}
-	if m.Right != 0 {
-		n += 5
+	return 0
+}
+
+func (m *PreprocessRequest) GetFlip() string {
+	if m != nil {
+		return m.Flip
 	}
-	if len(m.Detect) > 0 {
-		for k, v := range m.Detect {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovRpc(uint64(len(k))) + 1 + 4
-			n += mapEntrySize + 1 + sovRpc(uint64(mapEntrySize))
+	return ""
+}
+
+type PreprocessResponse struct {
+	// The id for the response
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The format of the data
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	Data   Raw    `protobuf:"bytes,3,opt,name=data,proto3,casttype=Raw" json:"data,omitempty"`
+	// The size of the image
+	Width    int32 `protobuf:"varint,4,opt,name=width,proto3" json:"width,omitempty"`
+	Height   int32 `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	Channels int32 `protobuf:"varint,6,opt,name=channels,proto3" json:"channels,omitempty"`
+	// The input tensor for the tensor format
+	Input *InputSpec `protobuf:"bytes,7,opt,name=input,proto3" json:"input,omitempty"`
+	Shape []int32    `protobuf:"varint,8,rep,packed,name=shape,proto3" json:"shape,omitempty"`
+	// The area of the output with the image in relative coordinates, it's smaller than the output when letterboxed
+	Content *Box `protobuf:"bytes,9,opt,name=content,proto3" json:"content,omitempty"`
+}
+
+func (m *PreprocessResponse) Reset()      { *m = PreprocessResponse{} }
+func (*PreprocessResponse) ProtoMessage() {}
+func (*PreprocessResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{23}
+}
+func (m *PreprocessResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PreprocessResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PreprocessResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if m.Covers {
-		n += 2
-	}
-	return n
+}
+func (m *PreprocessResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreprocessResponse.Merge(m, src)
+}
+func (m *PreprocessResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PreprocessResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreprocessResponse.DiscardUnknown(m)
 }
 
-func (m *Detection) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Top != 0 {
-		n += 5
-	}
-	if m.Left != 0 {
-		n += 5
-	}
-	if m.Bottom != 0 {
-		n += 5
+var xxx_messageInfo_PreprocessResponse proto.InternalMessageInfo
+
+func (m *PreprocessResponse) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	if m.Right != 0 {
-		n += 5
+	return ""
+}
+
+func (m *PreprocessResponse) GetFormat() string {
+	if m != nil {
+		return m.Format
 	}
-	l = len(m.Label)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
+	return ""
+}
+
+func (m *PreprocessResponse) GetData() Raw {
+	if m != nil {
+		return m.Data
 	}
-	if m.Confidence != 0 {
-		n += 5
+	return nil
+}
+
+func (m *PreprocessResponse) GetWidth() int32 {
+	if m != nil {
+		return m.Width
 	}
-	return n
+	return 0
 }
 
-func (m *DetectResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *PreprocessResponse) GetHeight() int32 {
+	if m != nil {
+		return m.Height
 	}
-	var l int
-	_ = l
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
+	return 0
+}
+
+func (m *PreprocessResponse) GetChannels() int32 {
+	if m != nil {
+		return m.Channels
 	}
-	if len(m.Detections) > 0 {
-		for _, e := range m.Detections {
-			l = e.Size()
-			n += 1 + l + sovRpc(uint64(l))
-		}
+	return 0
+}
+
+func (m *PreprocessResponse) GetInput() *InputSpec {
+	if m != nil {
+		return m.Input
 	}
-	l = len(m.Error)
-	if l > 0 {
-		n += 1 + l + sovRpc(uint64(l))
+	return nil
+}
+
+func (m *PreprocessResponse) GetShape() []int32 {
+	if m != nil {
+		return m.Shape
 	}
-	return n
+	return nil
 }
 
-func sovRpc(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+func (m *PreprocessResponse) GetContent() *Box {
+	if m != nil {
+		return m.Content
 	}
-	return n
+	return nil
 }
-func sozRpc(x uint64) (n int) {
-	return sovRpc(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+
+// The image quality checked before detection
+type Quality struct {
+	// The average brightness (0-255)
+	Brightness float32 `protobuf:"fixed32,1,opt,name=brightness,proto3" json:"brightness,omitempty"`
+	// The percentage of pixels that are almost white
+	Overexposed float32 `protobuf:"fixed32,2,opt,name=overexposed,proto3" json:"overexposed,omitempty"`
+	// The variance of the laplacian, low values are blurry
+	Sharpness float32 `protobuf:"fixed32,3,opt,name=sharpness,proto3" json:"sharpness,omitempty"`
+	// The failed checks (dark, bright, overexposed, blurry)
+	Problems []string `protobuf:"bytes,4,rep,name=problems,proto3" json:"problems,omitempty"`
+	// The detection was skipped because of the problems
+	Skipped bool `protobuf:"varint,5,opt,name=skipped,proto3" json:"skipped,omitempty"`
 }
-func (this *GetDetectorsResponse) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *Quality) Reset()      { *m = Quality{} }
+func (*Quality) ProtoMessage() {}
+func (*Quality) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{24}
+}
+func (m *Quality) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Quality) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Quality.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	s := strings.Join([]string{`&GetDetectorsResponse{`,
-		`Detectors:` + strings.Replace(fmt.Sprintf("%v", this.Detectors), "Detector", "Detector", 1) + `,`,
-		`}`,
-	}, "")
-	return s
 }
-func (this *Detector) String() string {
-	if this == nil {
-		return "nil"
+func (m *Quality) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Quality.Merge(m, src)
+}
+func (m *Quality) XXX_Size() int {
+	return m.Size()
+}
+func (m *Quality) XXX_DiscardUnknown() {
+	xxx_messageInfo_Quality.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Quality proto.InternalMessageInfo
+
+func (m *Quality) GetBrightness() float32 {
+	if m != nil {
+		return m.Brightness
 	}
-	s := strings.Join([]string{`&Detector{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
-		`Model:` + fmt.Sprintf("%v", this.Model) + `,`,
-		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
-		`Width:` + fmt.Sprintf("%v", this.Width) + `,`,
-		`Height:` + fmt.Sprintf("%v", this.Height) + `,`,
-		`Channels:` + fmt.Sprintf("%v", this.Channels) + `,`,
-		`}`,
-	}, "")
-	return s
+	return 0
 }
-func (this *DetectRequest) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *Quality) GetOverexposed() float32 {
+	if m != nil {
+		return m.Overexposed
 	}
-	keysForDetect := make([]string, 0, len(this.Detect))
-	for k, _ := range this.Detect {
-		keysForDetect = append(keysForDetect, k)
+	return 0
+}
+
+func (m *Quality) GetSharpness() float32 {
+	if m != nil {
+		return m.Sharpness
 	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
-	mapStringForDetect := "map[string]float32{"
-	for _, k := range keysForDetect {
-		mapStringForDetect += fmt.Sprintf("%v: %v,", k, this.Detect[k])
+	return 0
+}
+
+func (m *Quality) GetProblems() []string {
+	if m != nil {
+		return m.Problems
 	}
-	mapStringForDetect += "}"
-	s := strings.Join([]string{`&DetectRequest{`,
-		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
-		`DetectorName:` + fmt.Sprintf("%v", this.DetectorName) + `,`,
-		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
-		`File:` + fmt.Sprintf("%v", this.File) + `,`,
-		`Detect:` + mapStringForDetect + `,`,
-		`Regions:` + strings.Replace(fmt.Sprintf("%v", this.Regions), "DetectRegion", "DetectRegion", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	return nil
 }
-func (this *DetectRegion) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *Quality) GetSkipped() bool {
+	if m != nil {
+		return m.Skipped
 	}
-	keysForDetect := make([]string, 0, len(this.Detect))
-	for k, _ := range this.Detect {
-		keysForDetect = append(keysForDetect, k)
+	return false
+}
+
+// The estimated count of a density map model
+type Density struct {
+	// What was counted, like person
+	Label string `protobuf:"bytes,1,opt,name=label,proto3" json:"label"`
+	// The sum of the density map
+	Count float32 `protobuf:"fixed32,2,opt,name=count,proto3" json:"count"`
+	// The count in each of the named zone regions of the detector
+	Zones map[string]float32 `protobuf:"bytes,3,rep,name=zones,proto3" json:"zones,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed32,2,opt,name=value,proto3"`
+	// The density map, row by row in the original orientation, with density_map in the request
+	Width  int32     `protobuf:"varint,4,opt,name=width,proto3" json:"width,omitempty"`
+	Height int32     `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	Values []float32 `protobuf:"fixed32,6,rep,packed,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *Density) Reset()      { *m = Density{} }
+func (*Density) ProtoMessage() {}
+func (*Density) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{25}
+}
+func (m *Density) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Density) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Density.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
-	mapStringForDetect := "map[string]float32{"
-	for _, k := range keysForDetect {
-		mapStringForDetect += fmt.Sprintf("%v: %v,", k, this.Detect[k])
+}
+func (m *Density) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Density.Merge(m, src)
+}
+func (m *Density) XXX_Size() int {
+	return m.Size()
+}
+func (m *Density) XXX_DiscardUnknown() {
+	xxx_messageInfo_Density.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Density proto.InternalMessageInfo
+
+func (m *Density) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *Density) GetCount() float32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Density) GetZones() map[string]float32 {
+	if m != nil {
+		return m.Zones
+	}
+	return nil
+}
+
+func (m *Density) GetWidth() int32 {
+	if m != nil {
+		return m.Width
+	}
+	return 0
+}
+
+func (m *Density) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Density) GetValues() []float32 {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+// A raw model output tensor
+type OutputTensor struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The tensor type (Float32, UInt8...)
+	Type  string  `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Shape []int32 `protobuf:"varint,3,rep,packed,name=shape,proto3" json:"shape,omitempty"`
+	// The values, dequantized for quantized tensors
+	Values []float32 `protobuf:"fixed32,4,rep,packed,name=values,proto3" json:"values,omitempty"`
+	// The raw data for types that can't be converted to values
+	Data Raw `protobuf:"bytes,5,opt,name=data,proto3,casttype=Raw" json:"data,omitempty"`
+}
+
+func (m *OutputTensor) Reset()      { *m = OutputTensor{} }
+func (*OutputTensor) ProtoMessage() {}
+func (*OutputTensor) Descriptor() ([]byte, []int) {
+	return fileDescriptor_edafdb9f55df517e, []int{26}
+}
+func (m *OutputTensor) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OutputTensor) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OutputTensor.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *OutputTensor) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OutputTensor.Merge(m, src)
+}
+func (m *OutputTensor) XXX_Size() int {
+	return m.Size()
+}
+func (m *OutputTensor) XXX_DiscardUnknown() {
+	xxx_messageInfo_OutputTensor.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OutputTensor proto.InternalMessageInfo
+
+func (m *OutputTensor) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *OutputTensor) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *OutputTensor) GetShape() []int32 {
+	if m != nil {
+		return m.Shape
+	}
+	return nil
+}
+
+func (m *OutputTensor) GetValues() []float32 {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *OutputTensor) GetData() Raw {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("odrpc.RawOutputs", RawOutputs_name, RawOutputs_value)
+	proto.RegisterType((*GetDetectorsResponse)(nil), "odrpc.GetDetectorsResponse")
+	proto.RegisterType((*Detector)(nil), "odrpc.Detector")
+	proto.RegisterType((*InputSpec)(nil), "odrpc.InputSpec")
+	proto.RegisterType((*DetectRequest)(nil), "odrpc.DetectRequest")
+	proto.RegisterMapType((map[string]float32)(nil), "odrpc.DetectRequest.DetectEntry")
+	proto.RegisterMapType((map[string]*InputTensor)(nil), "odrpc.DetectRequest.InputsEntry")
+	proto.RegisterType((*Confirm)(nil), "odrpc.Confirm")
+	proto.RegisterType((*Smooth)(nil), "odrpc.Smooth")
+	proto.RegisterType((*Preprocess)(nil), "odrpc.Preprocess")
+	proto.RegisterType((*InputTensor)(nil), "odrpc.InputTensor")
+	proto.RegisterType((*DetectRegion)(nil), "odrpc.DetectRegion")
+	proto.RegisterMapType((map[string]float32)(nil), "odrpc.DetectRegion.DetectEntry")
+	proto.RegisterType((*Detection)(nil), "odrpc.Detection")
+	proto.RegisterType((*Attribute)(nil), "odrpc.Attribute")
+	proto.RegisterType((*Box)(nil), "odrpc.Box")
+	proto.RegisterType((*DetectResponse)(nil), "odrpc.DetectResponse")
+	proto.RegisterType((*Signature)(nil), "odrpc.Signature")
+	proto.RegisterType((*SignedDetections)(nil), "odrpc.SignedDetections")
+	proto.RegisterType((*CascadeResult)(nil), "odrpc.CascadeResult")
+	proto.RegisterType((*DetectBoxesRequest)(nil), "odrpc.DetectBoxesRequest")
+	proto.RegisterMapType((map[string]float32)(nil), "odrpc.DetectBoxesRequest.DetectEntry")
+	proto.RegisterType((*BoxResult)(nil), "odrpc.BoxResult")
+	proto.RegisterType((*DetectBoxesResponse)(nil), "odrpc.DetectBoxesResponse")
+	proto.RegisterType((*DiffRequest)(nil), "odrpc.DiffRequest")
+	proto.RegisterType((*ChangedRegion)(nil), "odrpc.ChangedRegion")
+	proto.RegisterType((*DiffResponse)(nil), "odrpc.DiffResponse")
+	proto.RegisterType((*PreprocessRequest)(nil), "odrpc.PreprocessRequest")
+	proto.RegisterType((*PreprocessResponse)(nil), "odrpc.PreprocessResponse")
+	proto.RegisterType((*Quality)(nil), "odrpc.Quality")
+	proto.RegisterType((*Density)(nil), "odrpc.Density")
+	proto.RegisterMapType((map[string]float32)(nil), "odrpc.Density.ZonesEntry")
+	proto.RegisterType((*OutputTensor)(nil), "odrpc.OutputTensor")
+}
+
+func init() { proto.RegisterFile("odrpc/rpc.proto", fileDescriptor_edafdb9f55df517e) }
+
+var fileDescriptor_edafdb9f55df517e = []byte{
+	// 2538 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0xcd, 0x6f, 0x24, 0x47,
+	0x15, 0x77, 0xf7, 0x4c, 0xcf, 0xc7, 0x9b, 0xb1, 0x3d, 0x5b, 0xeb, 0x98, 0xde, 0xb1, 0x33, 0x63,
+	0x3a, 0x09, 0x32, 0x86, 0x78, 0x16, 0x23, 0x20, 0x31, 0xe2, 0xe0, 0x59, 0x2f, 0xd1, 0xa2, 0xec,
+	0x86, 0x94, 0x89, 0xc2, 0xe6, 0xc0, 0xa8, 0x66, 0xba, 0x66, 0xa6, 0x71, 0x7f, 0xa5, 0xbb, 0x27,
+	0xb6, 0x03, 0x48, 0xc0, 0x25, 0x1c, 0x38, 0x20, 0x81, 0x10, 0x37, 0x38, 0x72, 0x44, 0xe2, 0x9f,
+	0x08, 0xb7, 0x95, 0xb8, 0xe4, 0x64, 0xb1, 0x5e, 0x0e, 0x91, 0xb9, 0x44, 0xe2, 0x86, 0x38, 0xa0,
+	0x7a, 0x55, 0x3d, 0xdd, 0x33, 0x3b, 0x4e, 0x64, 0xed, 0x21, 0x5c, 0x3c, 0xfd, 0x7e, 0xf5, 0xea,
+	0x55, 0xbd, 0x8f, 0xaa, 0xf7, 0xea, 0x19, 0x56, 0x03, 0x3b, 0x0a, 0x07, 0x9d, 0x28, 0x1c, 0xec,
+	0x86, 0x51, 0x90, 0x04, 0xc4, 0x40, 0xa0, 0xb9, 0x39, 0x0a, 0x82, 0x91, 0xcb, 0x3b, 0x2c, 0x74,
+	0x3a, 0xcc, 0xf7, 0x83, 0x84, 0x25, 0x4e, 0xe0, 0xc7, 0x92, 0xa9, 0xb9, 0xa1, 0x46, 0x91, 0xea,
+	0x4f, 0x86, 0x1d, 0xee, 0x85, 0xc9, 0x99, 0x1a, 0x7c, 0x79, 0xe4, 0x24, 0xe3, 0x49, 0x7f, 0x77,
+	0x10, 0x78, 0x9d, 0x51, 0x30, 0x0a, 0x32, 0x2e, 0x41, 0x21, 0x81, 0x5f, 0x92, 0xdd, 0xba, 0x0b,
+	0x6b, 0xaf, 0xf1, 0xe4, 0x90, 0x27, 0x7c, 0x90, 0x04, 0x51, 0x4c, 0x79, 0x1c, 0x06, 0x7e, 0xcc,
+	0xc9, 0xcb, 0x50, 0xb5, 0x53, 0xd0, 0xd4, 0xb6, 0x0a, 0xdb, 0xb5, 0xbd, 0xd5, 0x5d, 0xdc, 0xdc,
+	0x6e, 0xca, 0x4c, 0x33, 0x0e, 0xeb, 0x57, 0x3a, 0x54, 0x52, 0x9c, 0x10, 0x28, 0xfa, 0xcc, 0xe3,
+	0xa6, 0xb6, 0xa5, 0x6d, 0x57, 0x29, 0x7e, 0x0b, 0x2c, 0x39, 0x0b, 0xb9, 0xa9, 0x4b, 0x4c, 0x7c,
+	0x93, 0x35, 0x30, 0xbc, 0xc0, 0xe6, 0xae, 0x59, 0x40, 0x50, 0x12, 0x64, 0x1d, 0x4a, 0x2e, 0xeb,
+	0x73, 0x37, 0x36, 0x8b, 0x5b, 0x85, 0xed, 0x2a, 0x55, 0x94, 0xe0, 0x3e, 0x71, 0xec, 0x64, 0x6c,
+	0x1a, 0x5b, 0xda, 0xb6, 0x41, 0x25, 0x21, 0xb8, 0xc7, 0xdc, 0x19, 0x8d, 0x13, 0xb3, 0x84, 0xb0,
+	0xa2, 0x48, 0x13, 0x2a, 0x83, 0x31, 0xf3, 0x7d, 0x21, 0xa7, 0x8c, 0x23, 0x53, 0x9a, 0x6c, 0x42,
+	0xd5, 0x65, 0xfe, 0x68, 0xc2, 0x46, 0x3c, 0x36, 0x2b, 0xb8, 0x48, 0x06, 0x08, 0x89, 0x8e, 0x1f,
+	0x4e, 0x92, 0xd8, 0xac, 0xca, 0xf5, 0x25, 0x45, 0xbe, 0x04, 0x06, 0x7e, 0x99, 0xb0, 0xa5, 0x6d,
+	0xd7, 0xf6, 0x1a, 0xca, 0x1a, 0xf7, 0x04, 0x76, 0x14, 0xf2, 0x01, 0x95, 0xc3, 0x16, 0x83, 0xea,
+	0x14, 0x9b, 0xaa, 0xad, 0xe5, 0xd4, 0x46, 0x05, 0xcf, 0x82, 0x49, 0xa2, 0x8c, 0xa1, 0x28, 0xc1,
+	0xeb, 0x71, 0xe6, 0x9b, 0x85, 0xad, 0xc2, 0xb6, 0x4e, 0xf1, 0x5b, 0x28, 0x1d, 0x0f, 0x98, 0xcb,
+	0xd1, 0x16, 0x3a, 0x95, 0x84, 0xf5, 0x97, 0x12, 0x2c, 0x4b, 0x6b, 0x53, 0xfe, 0xee, 0x84, 0xc7,
+	0x09, 0x59, 0x01, 0xdd, 0xb1, 0xd5, 0x2a, 0xba, 0x63, 0x93, 0x17, 0x60, 0x39, 0x75, 0x4e, 0x0f,
+	0x7d, 0x21, 0x97, 0xaa, 0xa7, 0xe0, 0x03, 0xe1, 0x93, 0x17, 0xa0, 0x68, 0xb3, 0x84, 0xa1, 0xf9,
+	0xeb, 0xdd, 0xd5, 0xcb, 0xf3, 0x36, 0xd2, 0xff, 0x39, 0x6f, 0x17, 0x28, 0x3b, 0xa1, 0x48, 0x88,
+	0x5d, 0x0d, 0x1d, 0xdc, 0x00, 0x6a, 0x20, 0xbe, 0xc9, 0x2b, 0x50, 0x92, 0x82, 0x4c, 0x03, 0x23,
+	0x63, 0x6b, 0x26, 0x32, 0xd4, 0x9e, 0x14, 0x75, 0xd7, 0x4f, 0xa2, 0x33, 0xaa, 0xf8, 0xc9, 0xcb,
+	0x50, 0x8e, 0xf8, 0x48, 0xc4, 0xb2, 0x59, 0xc2, 0xa9, 0x37, 0xe7, 0xa6, 0x8a, 0x31, 0x9a, 0xf2,
+	0x08, 0x2f, 0xa6, 0x8e, 0x41, 0x2f, 0x56, 0xe9, 0x94, 0x46, 0x3f, 0x8d, 0xfc, 0x20, 0xe2, 0xca,
+	0x85, 0x8a, 0x22, 0x1b, 0x50, 0x75, 0x3c, 0x36, 0xe2, 0xbd, 0x49, 0xe4, 0x9a, 0x55, 0x39, 0x09,
+	0x81, 0xb7, 0x22, 0x57, 0xec, 0x5c, 0x39, 0x17, 0x3e, 0x65, 0xe7, 0xe8, 0xbf, 0x58, 0xed, 0x5c,
+	0xb9, 0x7f, 0x0f, 0x6a, 0x11, 0x3b, 0xe9, 0x05, 0x93, 0x04, 0xa7, 0xd7, 0xb6, 0xb4, 0xed, 0x95,
+	0xbd, 0x1b, 0x6a, 0x3a, 0x65, 0x27, 0x6f, 0xc8, 0x01, 0x0a, 0xd1, 0xf4, 0x9b, 0x7c, 0x0d, 0x20,
+	0x8c, 0x78, 0x18, 0x05, 0x03, 0x1e, 0xc7, 0x66, 0x1d, 0xe3, 0x26, 0x9d, 0xf2, 0xfd, 0xe9, 0x00,
+	0xcd, 0x31, 0x09, 0xad, 0x22, 0x71, 0xdc, 0xb9, 0xb9, 0x2c, 0xe3, 0x59, 0x52, 0xe8, 0x06, 0xd7,
+	0x09, 0xcd, 0x15, 0xe5, 0x06, 0xd7, 0x09, 0xc9, 0x4b, 0x50, 0x8a, 0xbd, 0x20, 0x48, 0xc6, 0xe6,
+	0x2a, 0x8a, 0x5e, 0x56, 0xa2, 0x8f, 0x10, 0xa4, 0x6a, 0x90, 0x6c, 0x43, 0x79, 0x10, 0xf8, 0x43,
+	0x27, 0xf2, 0xcc, 0x06, 0xf2, 0xad, 0x28, 0xbe, 0x3b, 0x12, 0xa5, 0xe9, 0xb0, 0x88, 0x36, 0x9b,
+	0xf7, 0x27, 0x23, 0xf3, 0xc6, 0x96, 0xb6, 0x5d, 0xa1, 0x92, 0x20, 0x26, 0x94, 0x07, 0x2c, 0x1e,
+	0x30, 0x9b, 0x9b, 0x04, 0xf1, 0x94, 0x24, 0x6d, 0xa8, 0xd9, 0xdc, 0x8f, 0x9d, 0xe4, 0xac, 0xe7,
+	0xb1, 0xd0, 0xbc, 0x89, 0xa3, 0xa0, 0xa0, 0xfb, 0x2c, 0x6c, 0xbe, 0x0a, 0xb5, 0x5c, 0x14, 0x90,
+	0x06, 0x14, 0x8e, 0xf9, 0x99, 0x0a, 0x53, 0xf1, 0x29, 0x56, 0x7c, 0x8f, 0xb9, 0x13, 0x19, 0x9f,
+	0x3a, 0x95, 0xc4, 0xbe, 0xfe, 0x8a, 0xd6, 0xbc, 0x0f, 0xb5, 0x9c, 0x1b, 0x16, 0x4c, 0xdd, 0xce,
+	0x4f, 0xad, 0xed, 0x91, 0xfc, 0x79, 0xfc, 0x01, 0xf7, 0xe3, 0x20, 0xca, 0x89, 0xb3, 0x7e, 0x08,
+	0x65, 0xa5, 0x2e, 0x79, 0x1e, 0xc0, 0x73, 0xfc, 0xde, 0x30, 0x62, 0x1e, 0x8f, 0x51, 0xa2, 0x41,
+	0xab, 0x9e, 0xe3, 0x7f, 0x17, 0x01, 0xe1, 0x01, 0x35, 0xa4, 0x4b, 0x0f, 0x0c, 0xa7, 0xb8, 0xba,
+	0x97, 0x0a, 0xf9, 0x7b, 0xc9, 0x1a, 0x41, 0x49, 0x1a, 0x5c, 0x70, 0x78, 0x3c, 0x19, 0x07, 0xe9,
+	0x41, 0x54, 0x94, 0x50, 0x92, 0xb9, 0xe1, 0x98, 0xa5, 0x4a, 0x22, 0x21, 0x34, 0x72, 0x82, 0x09,
+	0x1e, 0x3e, 0x9d, 0x8a, 0x4f, 0xdc, 0x18, 0x3b, 0xed, 0x79, 0x4e, 0x1c, 0x73, 0xdb, 0x2c, 0xaa,
+	0x8d, 0xb1, 0xd3, 0xfb, 0x08, 0x58, 0xbf, 0xd3, 0x00, 0xb2, 0xa8, 0x11, 0x52, 0x07, 0x2e, 0x1b,
+	0xcb, 0xbb, 0xa5, 0x42, 0x25, 0x21, 0x64, 0x0c, 0x5c, 0x27, 0xec, 0xb9, 0x8e, 0xe7, 0x24, 0x6a,
+	0xc1, 0xaa, 0x40, 0x5e, 0x17, 0x80, 0x98, 0x94, 0x38, 0x2e, 0x8f, 0x71, 0x59, 0x83, 0x4a, 0x42,
+	0xa0, 0x23, 0xe6, 0x79, 0x0c, 0xd7, 0xd4, 0xa9, 0x24, 0xc8, 0x4b, 0xb0, 0x22, 0xb6, 0xd3, 0x8f,
+	0xc4, 0x85, 0xea, 0x8b, 0x08, 0x36, 0x70, 0x78, 0xd9, 0x63, 0xa7, 0xdd, 0x29, 0x68, 0x75, 0xa1,
+	0x96, 0xb3, 0xb9, 0x30, 0x02, 0x5a, 0x5d, 0x66, 0x0d, 0x9d, 0x2a, 0x8a, 0x6c, 0xa8, 0xcb, 0x46,
+	0xc7, 0xcb, 0xa6, 0x3c, 0x73, 0xc9, 0x58, 0x7f, 0xd0, 0xa1, 0x9e, 0xbf, 0x01, 0xc8, 0x2d, 0x28,
+	0x24, 0x41, 0x88, 0xaa, 0xe9, 0xdd, 0xf2, 0xe5, 0x79, 0x5b, 0x90, 0x54, 0xfc, 0x21, 0x9b, 0x50,
+	0x74, 0xf9, 0x50, 0xe9, 0xd6, 0xad, 0x88, 0x5b, 0x4b, 0xd0, 0x14, 0xff, 0x12, 0x0b, 0x4a, 0xfd,
+	0x20, 0x49, 0x02, 0x4f, 0x1a, 0xb6, 0x0b, 0x97, 0xe7, 0x6d, 0x85, 0x50, 0xf5, 0x4b, 0xda, 0x60,
+	0xe0, 0xf6, 0xa5, 0xba, 0xdd, 0xea, 0xe5, 0x79, 0x5b, 0x02, 0x54, 0xfe, 0x90, 0x6f, 0xcd, 0xdd,
+	0x6f, 0xed, 0x05, 0x97, 0xd4, 0xc2, 0xeb, 0x6d, 0x1d, 0x4a, 0x83, 0xe0, 0x3d, 0x1e, 0xc5, 0x98,
+	0x8d, 0x2a, 0x54, 0x51, 0xcf, 0x70, 0x0e, 0xac, 0x7f, 0x17, 0xa0, 0x2a, 0xe7, 0x7e, 0xfe, 0x76,
+	0x69, 0x83, 0x81, 0x41, 0x8f, 0x81, 0x50, 0x95, 0x0c, 0x08, 0x50, 0xf9, 0x43, 0x76, 0x01, 0xf0,
+	0x2e, 0xb1, 0xb9, 0x3f, 0xe0, 0x68, 0x03, 0xbd, 0xbb, 0x72, 0x79, 0xde, 0xce, 0xa1, 0x34, 0xf7,
+	0x4d, 0xbe, 0x0c, 0x46, 0x12, 0xb1, 0xc1, 0x31, 0x5e, 0xee, 0xcb, 0xdd, 0x9b, 0x97, 0xe7, 0xed,
+	0x55, 0x04, 0xbe, 0x1a, 0x78, 0x4e, 0x82, 0x65, 0x0d, 0x95, 0x1c, 0xa4, 0x03, 0x85, 0x88, 0x9d,
+	0x98, 0x15, 0x3c, 0xec, 0xa0, 0x1c, 0xd2, 0x0d, 0x4e, 0xbb, 0x37, 0x2e, 0xcf, 0xdb, 0xcb, 0x11,
+	0x3b, 0xc9, 0x4d, 0x11, 0x9c, 0x42, 0x76, 0x9c, 0x88, 0xc4, 0x81, 0x39, 0x40, 0xca, 0x46, 0x20,
+	0x2f, 0x1b, 0x01, 0x72, 0x28, 0x8a, 0x05, 0xc7, 0xb5, 0x23, 0xee, 0xab, 0xbc, 0xd0, 0x98, 0xf1,
+	0xb8, 0x13, 0xf8, 0xdd, 0xf5, 0xcb, 0xf3, 0x36, 0x49, 0xb9, 0x72, 0x22, 0xa6, 0x33, 0xc9, 0xf7,
+	0x00, 0x58, 0x92, 0x44, 0x4e, 0x7f, 0x92, 0x70, 0x91, 0x20, 0xf2, 0x72, 0x0e, 0xd2, 0x81, 0xae,
+	0x79, 0x79, 0xde, 0x5e, 0xcb, 0xf8, 0x72, 0x92, 0x72, 0xb3, 0xad, 0xf7, 0xa1, 0x3a, 0x9d, 0x22,
+	0x3c, 0x9b, 0xd5, 0x53, 0xd2, 0xb3, 0x82, 0x56, 0x95, 0x55, 0x3b, 0x1f, 0x3a, 0xca, 0x29, 0x08,
+	0xa8, 0x28, 0x9a, 0x73, 0x4a, 0xe1, 0xb3, 0x9c, 0x62, 0x3d, 0x84, 0x42, 0x37, 0x38, 0x25, 0x8d,
+	0x5c, 0xa8, 0xc9, 0x08, 0x23, 0xf9, 0x08, 0x53, 0x71, 0xb5, 0x3e, 0x1b, 0x57, 0xd3, 0x58, 0x5a,
+	0x9b, 0x89, 0x25, 0x15, 0x40, 0xd6, 0x9f, 0x0a, 0xb0, 0x92, 0x1e, 0x22, 0x55, 0x68, 0xce, 0x57,
+	0x2e, 0xb7, 0x01, 0xec, 0xd4, 0xe8, 0xe2, 0x0a, 0x5e, 0xe8, 0x0d, 0x9a, 0xe3, 0x11, 0x4b, 0xf1,
+	0x28, 0x0a, 0xa2, 0xb4, 0x8c, 0x44, 0x42, 0x54, 0x1a, 0x69, 0xae, 0x2e, 0xce, 0x54, 0x1a, 0x32,
+	0x39, 0xab, 0x0c, 0x91, 0xf2, 0x88, 0x24, 0xf9, 0xee, 0x84, 0xb9, 0x4e, 0x72, 0x66, 0x1a, 0x33,
+	0x49, 0xf2, 0x4d, 0x89, 0xd2, 0x74, 0x58, 0xd4, 0x24, 0x36, 0x1f, 0x45, 0xcc, 0xe6, 0xb6, 0x3a,
+	0xe5, 0x53, 0x9a, 0x7c, 0x05, 0x6e, 0x0c, 0x99, 0xeb, 0xf6, 0xd9, 0xe0, 0xb8, 0x97, 0x96, 0x5a,
+	0xaa, 0x70, 0x69, 0xa4, 0x03, 0xd3, 0x32, 0xf9, 0x45, 0x58, 0x89, 0x78, 0x12, 0x9d, 0xf5, 0xd8,
+	0x30, 0xe1, 0x51, 0xcf, 0x8b, 0x31, 0xb8, 0x0b, 0xb4, 0x8e, 0xe8, 0x81, 0x00, 0xef, 0xc7, 0x64,
+	0x17, 0xaa, 0xb1, 0x33, 0xf2, 0x59, 0x32, 0x89, 0x64, 0x28, 0x67, 0xe6, 0x38, 0x4a, 0x71, 0x9a,
+	0xb1, 0x64, 0x39, 0x1c, 0xa4, 0x35, 0x90, 0x10, 0xea, 0xa9, 0xb4, 0x6c, 0xd6, 0x66, 0xd4, 0x3b,
+	0x94, 0x28, 0x4d, 0x87, 0xad, 0xdf, 0x6b, 0x50, 0x9d, 0x0a, 0x16, 0xa5, 0x32, 0x73, 0x47, 0x41,
+	0xe4, 0x24, 0x63, 0x4f, 0x39, 0x29, 0x03, 0xc8, 0x73, 0x50, 0x3a, 0xe6, 0x67, 0x3d, 0xc7, 0x56,
+	0xe5, 0xa5, 0x71, 0xcc, 0xcf, 0xee, 0xd9, 0xa2, 0x02, 0x13, 0xfb, 0xe1, 0x76, 0x8f, 0x25, 0xe8,
+	0x94, 0x02, 0xad, 0x48, 0xe0, 0x20, 0x21, 0x5f, 0x84, 0xba, 0x2c, 0xcf, 0xe2, 0x31, 0xdb, 0xfb,
+	0xc6, 0x37, 0x55, 0x5d, 0x59, 0x43, 0xec, 0x08, 0xa1, 0xec, 0x32, 0x34, 0xa4, 0x54, 0x24, 0xac,
+	0xbf, 0x69, 0xd0, 0x38, 0x42, 0x29, 0x87, 0x99, 0xef, 0x9f, 0x3d, 0x7a, 0x66, 0x34, 0x2c, 0x5c,
+	0xad, 0x61, 0xf1, 0x4a, 0x0d, 0x8d, 0xcf, 0xd0, 0xb0, 0xf4, 0x94, 0x86, 0xd6, 0xaf, 0x75, 0x58,
+	0xbe, 0x23, 0x8b, 0x28, 0xca, 0xe3, 0x89, 0xfb, 0x74, 0x01, 0xbf, 0x96, 0xde, 0x5e, 0xca, 0xb2,
+	0x48, 0x88, 0xd3, 0x16, 0xb2, 0x88, 0xfb, 0x89, 0xca, 0xdf, 0x8a, 0x9a, 0x53, 0xbb, 0x78, 0x9d,
+	0x43, 0x63, 0xe4, 0x0f, 0x0d, 0x81, 0xa2, 0x1d, 0xf8, 0x5c, 0xc5, 0x35, 0x7e, 0x0b, 0x75, 0xe4,
+	0x2a, 0x3d, 0xb9, 0x21, 0x19, 0xce, 0x35, 0x89, 0x1d, 0xe1, 0xb6, 0x08, 0x14, 0x43, 0x96, 0x8c,
+	0xb1, 0x10, 0x37, 0x28, 0x7e, 0x93, 0x1d, 0x28, 0x07, 0xfd, 0x1f, 0xf3, 0x81, 0x7a, 0x47, 0x2d,
+	0xda, 0x4f, 0xca, 0x60, 0x7d, 0x58, 0x00, 0x22, 0xe1, 0x6e, 0x70, 0xca, 0xe3, 0xcf, 0xe7, 0x51,
+	0x33, 0xf3, 0x6e, 0x30, 0xe6, 0xde, 0x0d, 0x5b, 0x60, 0xf4, 0xc5, 0xd6, 0xd4, 0xab, 0x25, 0x97,
+	0x7f, 0xa8, 0x1c, 0x40, 0xd7, 0x38, 0xa7, 0xe9, 0x73, 0xb3, 0x42, 0x15, 0x25, 0xaa, 0xe7, 0x90,
+	0xd9, 0xb6, 0xe3, 0x8f, 0xf0, 0x78, 0xeb, 0x34, 0x25, 0xc9, 0x77, 0xa6, 0x55, 0x86, 0x34, 0xd0,
+	0x4b, 0x33, 0x06, 0xca, 0x5b, 0x62, 0x61, 0xad, 0x91, 0x7f, 0x1b, 0xc1, 0x95, 0x6f, 0xa3, 0xda,
+	0xcc, 0xdb, 0x48, 0x95, 0x74, 0xb9, 0x58, 0xa9, 0x63, 0x1c, 0x89, 0x92, 0x2e, 0x3b, 0x55, 0xcf,
+	0x52, 0xae, 0x7c, 0xa0, 0x41, 0x55, 0x58, 0x45, 0x46, 0xf5, 0x9a, 0x78, 0x33, 0xdb, 0xfc, 0x54,
+	0x55, 0xd9, 0x92, 0x20, 0x9b, 0x50, 0xe8, 0x07, 0xa7, 0xaa, 0x6e, 0xcf, 0x9b, 0x52, 0xc0, 0x73,
+	0xb1, 0x5c, 0xb8, 0x4e, 0x2c, 0x17, 0x73, 0xb1, 0x6c, 0xbd, 0x09, 0x37, 0x67, 0x2c, 0x79, 0x45,
+	0xbe, 0xd9, 0x11, 0x2f, 0x52, 0xb1, 0xd9, 0xf9, 0xeb, 0x62, 0xaa, 0x05, 0x4d, 0x19, 0xac, 0x8f,
+	0x35, 0xa8, 0x1d, 0x3a, 0xc3, 0xe1, 0x55, 0x01, 0xda, 0x86, 0x52, 0x9f, 0x0f, 0x85, 0xd9, 0xe7,
+	0xaa, 0x5c, 0x05, 0x93, 0xe7, 0xc1, 0xc0, 0xcb, 0xde, 0x2c, 0xcc, 0x8e, 0x4b, 0x54, 0x14, 0xef,
+	0x92, 0x11, 0x63, 0x50, 0x6a, 0x53, 0x95, 0x88, 0x08, 0xc2, 0x0d, 0xa8, 0xca, 0x54, 0x91, 0x8b,
+	0x50, 0x04, 0xc4, 0xe0, 0x26, 0x54, 0x93, 0x71, 0xc4, 0xe3, 0x71, 0xe0, 0xda, 0xaa, 0x17, 0x92,
+	0x01, 0xe4, 0x16, 0x54, 0xc4, 0x9b, 0x87, 0x45, 0x9c, 0x61, 0x7c, 0xea, 0xb4, 0xec, 0x39, 0xfe,
+	0x41, 0xc4, 0x59, 0xd6, 0x57, 0xa9, 0xe4, 0xfa, 0x2a, 0xd6, 0x1d, 0x58, 0xbe, 0x33, 0x66, 0xfe,
+	0x88, 0xdb, 0xaa, 0x22, 0x57, 0x4e, 0xd3, 0x16, 0x3b, 0x0d, 0xfb, 0x14, 0xa9, 0xe2, 0xd8, 0xa7,
+	0x08, 0x22, 0x6e, 0xfd, 0x14, 0xea, 0xd2, 0x5c, 0x57, 0xd8, 0xfe, 0xdb, 0x59, 0x37, 0x40, 0xda,
+	0x7e, 0x2d, 0x7d, 0x99, 0xe6, 0x97, 0xee, 0xd6, 0x2e, 0xcf, 0xdb, 0x29, 0x63, 0xd6, 0x1b, 0x68,
+	0xa7, 0x4b, 0x16, 0xb2, 0x6a, 0x15, 0x81, 0x74, 0xf5, 0xbf, 0xea, 0x70, 0x23, 0xf7, 0xca, 0xfe,
+	0xff, 0xbb, 0x54, 0xc4, 0x4b, 0x33, 0x88, 0x3c, 0x96, 0xa8, 0x14, 0xa1, 0x28, 0xec, 0x4f, 0xf1,
+	0x24, 0xe1, 0x91, 0x30, 0xb8, 0xbc, 0x4d, 0x32, 0x60, 0xae, 0xa9, 0x50, 0xb9, 0x5e, 0x53, 0xa1,
+	0xba, 0xb0, 0xa9, 0x00, 0x59, 0x53, 0xc1, 0xfa, 0x40, 0x07, 0x92, 0xb7, 0xda, 0x15, 0xae, 0xcb,
+	0xf6, 0xae, 0xcf, 0xec, 0x7d, 0x63, 0xc6, 0x52, 0xb3, 0xcf, 0xbc, 0x2c, 0xd4, 0x8a, 0x8b, 0x5b,
+	0x78, 0xc6, 0x95, 0x2d, 0xbc, 0xd2, 0x5c, 0x0b, 0x6f, 0xda, 0x8c, 0x2b, 0x7f, 0x6a, 0x33, 0x0e,
+	0xe3, 0x72, 0xcc, 0x42, 0xae, 0x52, 0x93, 0x24, 0xc8, 0x8b, 0xd8, 0x11, 0x49, 0x44, 0x1e, 0xad,
+	0x3e, 0x15, 0xcf, 0xe9, 0x90, 0xf5, 0x47, 0x0d, 0xca, 0xaa, 0xfa, 0x23, 0x2d, 0x80, 0xdc, 0x3b,
+	0x58, 0xd6, 0xc4, 0x39, 0x84, 0x6c, 0x41, 0x4d, 0xbc, 0xf4, 0xf8, 0x69, 0x18, 0x88, 0xb7, 0xbb,
+	0x3c, 0x05, 0x79, 0x48, 0x38, 0x35, 0x1e, 0xb3, 0x28, 0x44, 0x01, 0xb2, 0x56, 0xce, 0x00, 0xa1,
+	0x6b, 0x18, 0x05, 0x7d, 0x97, 0x7b, 0x69, 0xdb, 0x73, 0x4a, 0x8b, 0x0c, 0x12, 0x1f, 0x3b, 0x61,
+	0xc8, 0x6d, 0x34, 0x50, 0x85, 0xa6, 0xa4, 0xf5, 0x5f, 0x0d, 0xca, 0xaa, 0x80, 0xcb, 0xde, 0x66,
+	0xda, 0x15, 0x6f, 0xb3, 0x36, 0x18, 0x83, 0x60, 0xe2, 0xa7, 0x0f, 0x44, 0x64, 0x40, 0x80, 0xca,
+	0x1f, 0xd2, 0x01, 0xe3, 0xfd, 0xc0, 0xe7, 0xe9, 0x9d, 0x7b, 0x6b, 0xb6, 0x42, 0xdc, 0x7d, 0x47,
+	0x8c, 0xc9, 0x14, 0x24, 0xf9, 0xae, 0xe9, 0xce, 0xac, 0x31, 0x50, 0xca, 0x37, 0x06, 0x9a, 0xaf,
+	0x00, 0x64, 0xa2, 0xaf, 0x95, 0x6b, 0x7e, 0xa1, 0x41, 0x3d, 0x5f, 0xcd, 0x5f, 0xa7, 0xf1, 0x2c,
+	0xa3, 0xa2, 0x90, 0x8f, 0x8a, 0x6c, 0x83, 0xc5, 0x85, 0x9d, 0x0b, 0x63, 0x41, 0x48, 0xef, 0xbc,
+	0x0a, 0x90, 0x35, 0xff, 0x48, 0x1d, 0x2a, 0xf4, 0xe0, 0xed, 0xde, 0x83, 0x37, 0x1e, 0xdc, 0x6d,
+	0x2c, 0x91, 0x55, 0xa8, 0x09, 0xea, 0xde, 0x83, 0x3b, 0xaf, 0xbf, 0x75, 0x78, 0xb7, 0xa1, 0xa5,
+	0xc3, 0x6f, 0x3c, 0x78, 0xfd, 0x61, 0x43, 0xdf, 0xfb, 0x57, 0x11, 0x64, 0xbb, 0x9f, 0xbc, 0x0d,
+	0xf5, 0x7c, 0x13, 0x9e, 0xac, 0xef, 0xca, 0x0e, 0xff, 0x6e, 0xda, 0xbb, 0xdf, 0xbd, 0x2b, 0x5e,
+	0x88, 0xcd, 0x0d, 0xe5, 0x92, 0x45, 0x1d, 0x7b, 0x8b, 0xfc, 0xf2, 0xef, 0xff, 0xfc, 0xad, 0x5e,
+	0x27, 0xd0, 0x99, 0xb6, 0xe5, 0xc9, 0x08, 0x4a, 0x92, 0x91, 0xac, 0x2d, 0x6a, 0x74, 0x36, 0x9f,
+	0x9b, 0x43, 0x95, 0xa8, 0xdb, 0x28, 0x6a, 0xc7, 0x2a, 0x2b, 0x51, 0xfb, 0xda, 0xce, 0x3b, 0x9b,
+	0xd6, 0x17, 0x14, 0xd5, 0xf9, 0xc9, 0xcc, 0x85, 0xf9, 0xb3, 0x7d, 0x6d, 0x87, 0x1c, 0xa4, 0xfd,
+	0x9b, 0xa3, 0x24, 0xe2, 0xcc, 0xbb, 0xde, 0x72, 0x4b, 0xdb, 0xda, 0x6d, 0x8d, 0x3c, 0x4c, 0x7b,
+	0xda, 0xaa, 0x30, 0xbe, 0x42, 0xc6, 0x34, 0x45, 0xe4, 0xcb, 0x67, 0xab, 0x89, 0x3b, 0x5e, 0xb3,
+	0x56, 0xd3, 0x3d, 0xaa, 0x16, 0xe5, 0xbe, 0xb6, 0x73, 0x5b, 0x23, 0x3f, 0x82, 0x5a, 0xae, 0x14,
+	0x20, 0xb7, 0xae, 0x2c, 0xb4, 0x9a, 0xcd, 0x45, 0x43, 0x6a, 0x9b, 0x26, 0xae, 0x41, 0xf6, 0xb5,
+	0x1d, 0x6b, 0x39, 0x5d, 0x46, 0xd6, 0x7e, 0xaf, 0x01, 0x88, 0x3c, 0x77, 0xcf, 0xc3, 0x7f, 0x20,
+	0xa4, 0x9d, 0xc8, 0x5c, 0xa5, 0xd0, 0xbc, 0x39, 0x83, 0x29, 0x81, 0x0d, 0x14, 0x08, 0x96, 0xd1,
+	0xb1, 0x9d, 0xe1, 0x50, 0x98, 0xf1, 0xe1, 0x4c, 0x87, 0xcf, 0x7c, 0xfa, 0x56, 0x57, 0xe2, 0x6e,
+	0x2d, 0x18, 0x51, 0x42, 0xd7, 0x51, 0x68, 0xc3, 0xaa, 0x75, 0xb2, 0x04, 0xb0, 0xaf, 0xed, 0x74,
+	0xdf, 0x7a, 0xf4, 0xb8, 0xb5, 0xf4, 0xd1, 0xe3, 0xd6, 0xd2, 0x27, 0x8f, 0x5b, 0xda, 0xcf, 0x2f,
+	0x5a, 0xda, 0x9f, 0x2f, 0x5a, 0xda, 0x87, 0x17, 0x2d, 0xed, 0xd1, 0x45, 0x4b, 0xfb, 0xc7, 0x45,
+	0x4b, 0xfb, 0xf8, 0xa2, 0xb5, 0xf4, 0xc9, 0x45, 0x4b, 0xfb, 0xcd, 0x93, 0xd6, 0xd2, 0xa3, 0x27,
+	0xad, 0xa5, 0x8f, 0x9e, 0xb4, 0x96, 0xde, 0x69, 0xe7, 0xfe, 0x9b, 0x14, 0xfb, 0xc1, 0xc9, 0xfb,
+	0x6c, 0x30, 0xee, 0xd8, 0x41, 0x60, 0xc7, 0x1d, 0xdc, 0x41, 0xbf, 0x84, 0x21, 0xfa, 0xf5, 0xff,
+	0x0d, 0x00, 0x0b, 0xb3, 0x07, 0x00, 0xca, 0x1a, 0x00, 0x00,
+}
+
+func (x RawOutputs) String() string {
+	s, ok := RawOutputs_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
+func (this *GetDetectorsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GetDetectorsResponse)
+	if !ok {
+		that2, ok := that.(GetDetectorsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Detectors) != len(that1.Detectors) {
+		return false
+	}
+	for i := range this.Detectors {
+		if !this.Detectors[i].Equal(that1.Detectors[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Detector) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Detector)
+	if !ok {
+		that2, ok := that.(Detector)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if this.Model != that1.Model {
+		return false
+	}
+	if len(this.Labels) != len(that1.Labels) {
+		return false
+	}
+	for i := range this.Labels {
+		if this.Labels[i] != that1.Labels[i] {
+			return false
+		}
+	}
+	if this.Width != that1.Width {
+		return false
+	}
+	if this.Height != that1.Height {
+		return false
+	}
+	if this.Channels != that1.Channels {
+		return false
+	}
+	if len(this.Languages) != len(that1.Languages) {
+		return false
+	}
+	for i := range this.Languages {
+		if this.Languages[i] != that1.Languages[i] {
+			return false
+		}
+	}
+	if len(this.Inputs) != len(that1.Inputs) {
+		return false
+	}
+	for i := range this.Inputs {
+		if this.Inputs[i] != that1.Inputs[i] {
+			return false
+		}
+	}
+	if !this.Input.Equal(that1.Input) {
+		return false
+	}
+	return true
+}
+func (this *InputSpec) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InputSpec)
+	if !ok {
+		that2, ok := that.(InputSpec)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if this.Layout != that1.Layout {
+		return false
+	}
+	if len(this.Mean) != len(that1.Mean) {
+		return false
+	}
+	for i := range this.Mean {
+		if this.Mean[i] != that1.Mean[i] {
+			return false
+		}
+	}
+	if len(this.Scale) != len(that1.Scale) {
+		return false
+	}
+	for i := range this.Scale {
+		if this.Scale[i] != that1.Scale[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *DetectRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DetectRequest)
+	if !ok {
+		that2, ok := that.(DetectRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.DetectorName != that1.DetectorName {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	if this.File != that1.File {
+		return false
+	}
+	if len(this.Detect) != len(that1.Detect) {
+		return false
+	}
+	for i := range this.Detect {
+		if this.Detect[i] != that1.Detect[i] {
+			return false
+		}
+	}
+	if len(this.Regions) != len(that1.Regions) {
+		return false
+	}
+	for i := range this.Regions {
+		if !this.Regions[i].Equal(that1.Regions[i]) {
+			return false
+		}
+	}
+	if this.Language != that1.Language {
+		return false
+	}
+	if len(this.Ignore) != len(that1.Ignore) {
+		return false
+	}
+	for i := range this.Ignore {
+		if this.Ignore[i] != that1.Ignore[i] {
+			return false
+		}
+	}
+	if this.ImageUrl != that1.ImageUrl {
+		return false
+	}
+	if len(this.Inputs) != len(that1.Inputs) {
+		return false
+	}
+	for i := range this.Inputs {
+		if !this.Inputs[i].Equal(that1.Inputs[i]) {
+			return false
+		}
+	}
+	if this.RawOutputs != that1.RawOutputs {
+		return false
+	}
+	if !this.Preprocess.Equal(that1.Preprocess) {
+		return false
+	}
+	if this.Rotate != that1.Rotate {
+		return false
+	}
+	if this.Flip != that1.Flip {
+		return false
+	}
+	if !this.Smooth.Equal(that1.Smooth) {
+		return false
+	}
+	if !this.Confirm.Equal(that1.Confirm) {
+		return false
+	}
+	if this.Debug != that1.Debug {
+		return false
+	}
+	if this.Cascade != that1.Cascade {
+		return false
+	}
+	if this.DensityMap != that1.DensityMap {
+		return false
+	}
+	return true
+}
+func (this *Confirm) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Confirm)
+	if !ok {
+		that2, ok := that.(Confirm)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.MinFrames != that1.MinFrames {
+		return false
+	}
+	if this.Frames != that1.Frames {
+		return false
+	}
+	if len(this.Labels) != len(that1.Labels) {
+		return false
+	}
+	for i := range this.Labels {
+		if this.Labels[i] != that1.Labels[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *Smooth) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Smooth)
+	if !ok {
+		that2, ok := that.(Smooth)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Method != that1.Method {
+		return false
+	}
+	if this.Alpha != that1.Alpha {
+		return false
+	}
+	if this.Iou != that1.Iou {
+		return false
+	}
+	if this.MaxMissed != that1.MaxMissed {
+		return false
+	}
+	return true
+}
+func (this *Preprocess) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Preprocess)
+	if !ok {
+		that2, ok := that.(Preprocess)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Clahe != that1.Clahe {
+		return false
+	}
+	if this.ClipLimit != that1.ClipLimit {
+		return false
+	}
+	if this.Tiles != that1.Tiles {
+		return false
+	}
+	if this.Gamma != that1.Gamma {
+		return false
+	}
+	if this.MaxBrightness != that1.MaxBrightness {
+		return false
+	}
+	return true
+}
+func (this *InputTensor) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*InputTensor)
+	if !ok {
+		that2, ok := that.(InputTensor)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Values) != len(that1.Values) {
+		return false
+	}
+	for i := range this.Values {
+		if this.Values[i] != that1.Values[i] {
+			return false
+		}
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	return true
+}
+func (this *DetectRegion) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DetectRegion)
+	if !ok {
+		that2, ok := that.(DetectRegion)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Top != that1.Top {
+		return false
+	}
+	if this.Left != that1.Left {
+		return false
+	}
+	if this.Bottom != that1.Bottom {
+		return false
+	}
+	if this.Right != that1.Right {
+		return false
+	}
+	if len(this.Detect) != len(that1.Detect) {
+		return false
+	}
+	for i := range this.Detect {
+		if this.Detect[i] != that1.Detect[i] {
+			return false
+		}
+	}
+	if this.Covers != that1.Covers {
+		return false
+	}
+	return true
+}
+func (this *Detection) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Detection)
+	if !ok {
+		that2, ok := that.(Detection)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Top != that1.Top {
+		return false
+	}
+	if this.Left != that1.Left {
+		return false
+	}
+	if this.Bottom != that1.Bottom {
+		return false
+	}
+	if this.Right != that1.Right {
+		return false
+	}
+	if this.Label != that1.Label {
+		return false
+	}
+	if this.Confidence != that1.Confidence {
+		return false
+	}
+	if this.Track != that1.Track {
+		return false
+	}
+	if !this.Raw.Equal(that1.Raw) {
+		return false
+	}
+	if this.Stage != that1.Stage {
+		return false
+	}
+	if len(this.Children) != len(that1.Children) {
+		return false
+	}
+	for i := range this.Children {
+		if !this.Children[i].Equal(that1.Children[i]) {
+			return false
+		}
+	}
+	if len(this.Attributes) != len(that1.Attributes) {
+		return false
+	}
+	for i := range this.Attributes {
+		if !this.Attributes[i].Equal(that1.Attributes[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Attribute) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Attribute)
+	if !ok {
+		that2, ok := that.(Attribute)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Value != that1.Value {
+		return false
+	}
+	if this.Confidence != that1.Confidence {
+		return false
+	}
+	return true
+}
+func (this *Box) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Box)
+	if !ok {
+		that2, ok := that.(Box)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Top != that1.Top {
+		return false
+	}
+	if this.Left != that1.Left {
+		return false
+	}
+	if this.Bottom != that1.Bottom {
+		return false
+	}
+	if this.Right != that1.Right {
+		return false
+	}
+	return true
+}
+func (this *DetectResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DetectResponse)
+	if !ok {
+		that2, ok := that.(DetectResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if len(this.Detections) != len(that1.Detections) {
+		return false
+	}
+	for i := range this.Detections {
+		if !this.Detections[i].Equal(that1.Detections[i]) {
+			return false
+		}
+	}
+	if this.Error != that1.Error {
+		return false
+	}
+	if len(this.Outputs) != len(that1.Outputs) {
+		return false
+	}
+	for i := range this.Outputs {
+		if !this.Outputs[i].Equal(that1.Outputs[i]) {
+			return false
+		}
+	}
+	if !this.Quality.Equal(that1.Quality) {
+		return false
+	}
+	if this.Degraded != that1.Degraded {
+		return false
+	}
+	if this.FallbackDetector != that1.FallbackDetector {
+		return false
+	}
+	if this.RetryAfterMs != that1.RetryAfterMs {
+		return false
+	}
+	if !this.Signature.Equal(that1.Signature) {
+		return false
+	}
+	if this.Debug != that1.Debug {
+		return false
+	}
+	if !this.Density.Equal(that1.Density) {
+		return false
+	}
+	return true
+}
+func (this *Signature) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Signature)
+	if !ok {
+		that2, ok := that.(Signature)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Algorithm != that1.Algorithm {
+		return false
+	}
+	if this.KeyId != that1.KeyId {
+		return false
+	}
+	if this.SignedAt != that1.SignedAt {
+		return false
+	}
+	if this.ImageSha256 != that1.ImageSha256 {
+		return false
+	}
+	if this.Value != that1.Value {
+		return false
+	}
+	return true
+}
+func (this *SignedDetections) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SignedDetections)
+	if !ok {
+		that2, ok := that.(SignedDetections)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if len(this.Detections) != len(that1.Detections) {
+		return false
+	}
+	for i := range this.Detections {
+		if !this.Detections[i].Equal(that1.Detections[i]) {
+			return false
+		}
+	}
+	if this.Algorithm != that1.Algorithm {
+		return false
+	}
+	if this.KeyId != that1.KeyId {
+		return false
+	}
+	if this.SignedAt != that1.SignedAt {
+		return false
+	}
+	if this.ImageSha256 != that1.ImageSha256 {
+		return false
+	}
+	return true
+}
+func (this *CascadeResult) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*CascadeResult)
+	if !ok {
+		that2, ok := that.(CascadeResult)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.Stage != that1.Stage {
+		return false
+	}
+	if this.Parent != that1.Parent {
+		return false
+	}
+	if len(this.Detections) != len(that1.Detections) {
+		return false
+	}
+	for i := range this.Detections {
+		if !this.Detections[i].Equal(that1.Detections[i]) {
+			return false
+		}
+	}
+	if this.Error != that1.Error {
+		return false
+	}
+	if this.Done != that1.Done {
+		return false
+	}
+	if this.ParentStage != that1.ParentStage {
+		return false
+	}
+	if len(this.Path) != len(that1.Path) {
+		return false
+	}
+	for i := range this.Path {
+		if this.Path[i] != that1.Path[i] {
+			return false
+		}
+	}
+	if len(this.Objects) != len(that1.Objects) {
+		return false
+	}
+	for i := range this.Objects {
+		if !this.Objects[i].Equal(that1.Objects[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *DetectBoxesRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DetectBoxesRequest)
+	if !ok {
+		that2, ok := that.(DetectBoxesRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.DetectorName != that1.DetectorName {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	if this.File != that1.File {
+		return false
+	}
+	if this.ImageUrl != that1.ImageUrl {
+		return false
+	}
+	if len(this.Boxes) != len(that1.Boxes) {
+		return false
+	}
+	for i := range this.Boxes {
+		if !this.Boxes[i].Equal(that1.Boxes[i]) {
+			return false
+		}
+	}
+	if this.Pixels != that1.Pixels {
+		return false
+	}
+	if this.Padding != that1.Padding {
+		return false
+	}
+	if len(this.Detect) != len(that1.Detect) {
+		return false
+	}
+	for i := range this.Detect {
+		if this.Detect[i] != that1.Detect[i] {
+			return false
+		}
+	}
+	if this.Language != that1.Language {
+		return false
+	}
+	if len(this.Ignore) != len(that1.Ignore) {
+		return false
+	}
+	for i := range this.Ignore {
+		if this.Ignore[i] != that1.Ignore[i] {
+			return false
+		}
+	}
+	if this.MaxDetections != that1.MaxDetections {
+		return false
+	}
+	return true
+}
+func (this *BoxResult) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*BoxResult)
+	if !ok {
+		that2, ok := that.(BoxResult)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Index != that1.Index {
+		return false
+	}
+	if !this.Box.Equal(that1.Box) {
+		return false
+	}
+	if len(this.Detections) != len(that1.Detections) {
+		return false
+	}
+	for i := range this.Detections {
+		if !this.Detections[i].Equal(that1.Detections[i]) {
+			return false
+		}
+	}
+	if this.Error != that1.Error {
+		return false
+	}
+	return true
+}
+func (this *DetectBoxesResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DetectBoxesResponse)
+	if !ok {
+		that2, ok := that.(DetectBoxesResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if len(this.Results) != len(that1.Results) {
+		return false
+	}
+	for i := range this.Results {
+		if !this.Results[i].Equal(that1.Results[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *DiffRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DiffRequest)
+	if !ok {
+		that2, ok := that.(DiffRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if !bytes.Equal(this.Before, that1.Before) {
+		return false
+	}
+	if !bytes.Equal(this.After, that1.After) {
+		return false
+	}
+	if this.BeforeUrl != that1.BeforeUrl {
+		return false
+	}
+	if this.AfterUrl != that1.AfterUrl {
+		return false
+	}
+	if this.Threshold != that1.Threshold {
+		return false
+	}
+	if this.MinArea != that1.MinArea {
+		return false
+	}
+	if this.Width != that1.Width {
+		return false
+	}
+	return true
+}
+func (this *ChangedRegion) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ChangedRegion)
+	if !ok {
+		that2, ok := that.(ChangedRegion)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Box.Equal(that1.Box) {
+		return false
+	}
+	if this.Score != that1.Score {
+		return false
+	}
+	return true
+}
+func (this *DiffResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DiffResponse)
+	if !ok {
+		that2, ok := that.(DiffResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if len(this.Regions) != len(that1.Regions) {
+		return false
+	}
+	for i := range this.Regions {
+		if !this.Regions[i].Equal(that1.Regions[i]) {
+			return false
+		}
+	}
+	if this.Score != that1.Score {
+		return false
+	}
+	return true
+}
+func (this *PreprocessRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PreprocessRequest)
+	if !ok {
+		that2, ok := that.(PreprocessRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.DetectorName != that1.DetectorName {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	if this.File != that1.File {
+		return false
+	}
+	if this.ImageUrl != that1.ImageUrl {
+		return false
+	}
+	if this.Format != that1.Format {
+		return false
+	}
+	if this.Letterbox != that1.Letterbox {
+		return false
+	}
+	if !this.Preprocess.Equal(that1.Preprocess) {
+		return false
+	}
+	if this.Rotate != that1.Rotate {
+		return false
+	}
+	if this.Flip != that1.Flip {
+		return false
+	}
+	return true
+}
+func (this *PreprocessResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PreprocessResponse)
+	if !ok {
+		that2, ok := that.(PreprocessResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.Format != that1.Format {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	if this.Width != that1.Width {
+		return false
+	}
+	if this.Height != that1.Height {
+		return false
+	}
+	if this.Channels != that1.Channels {
+		return false
+	}
+	if !this.Input.Equal(that1.Input) {
+		return false
+	}
+	if len(this.Shape) != len(that1.Shape) {
+		return false
+	}
+	for i := range this.Shape {
+		if this.Shape[i] != that1.Shape[i] {
+			return false
+		}
+	}
+	if !this.Content.Equal(that1.Content) {
+		return false
+	}
+	return true
+}
+func (this *Quality) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Quality)
+	if !ok {
+		that2, ok := that.(Quality)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Brightness != that1.Brightness {
+		return false
+	}
+	if this.Overexposed != that1.Overexposed {
+		return false
+	}
+	if this.Sharpness != that1.Sharpness {
+		return false
+	}
+	if len(this.Problems) != len(that1.Problems) {
+		return false
+	}
+	for i := range this.Problems {
+		if this.Problems[i] != that1.Problems[i] {
+			return false
+		}
+	}
+	if this.Skipped != that1.Skipped {
+		return false
+	}
+	return true
+}
+func (this *Density) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Density)
+	if !ok {
+		that2, ok := that.(Density)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Label != that1.Label {
+		return false
+	}
+	if this.Count != that1.Count {
+		return false
+	}
+	if len(this.Zones) != len(that1.Zones) {
+		return false
+	}
+	for i := range this.Zones {
+		if this.Zones[i] != that1.Zones[i] {
+			return false
+		}
+	}
+	if this.Width != that1.Width {
+		return false
+	}
+	if this.Height != that1.Height {
+		return false
+	}
+	if len(this.Values) != len(that1.Values) {
+		return false
+	}
+	for i := range this.Values {
+		if this.Values[i] != that1.Values[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *OutputTensor) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*OutputTensor)
+	if !ok {
+		that2, ok := that.(OutputTensor)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if len(this.Shape) != len(that1.Shape) {
+		return false
+	}
+	for i := range this.Shape {
+		if this.Shape[i] != that1.Shape[i] {
+			return false
+		}
+	}
+	if len(this.Values) != len(that1.Values) {
+		return false
+	}
+	for i := range this.Values {
+		if this.Values[i] != that1.Values[i] {
+			return false
+		}
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	return true
+}
+func (this *GetDetectorsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&odrpc.GetDetectorsResponse{")
+	if this.Detectors != nil {
+		s = append(s, "Detectors: "+fmt.Sprintf("%#v", this.Detectors)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Detector) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 14)
+	s = append(s, "&odrpc.Detector{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "Model: "+fmt.Sprintf("%#v", this.Model)+",\n")
+	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
+	s = append(s, "Width: "+fmt.Sprintf("%#v", this.Width)+",\n")
+	s = append(s, "Height: "+fmt.Sprintf("%#v", this.Height)+",\n")
+	s = append(s, "Channels: "+fmt.Sprintf("%#v", this.Channels)+",\n")
+	s = append(s, "Languages: "+fmt.Sprintf("%#v", this.Languages)+",\n")
+	s = append(s, "Inputs: "+fmt.Sprintf("%#v", this.Inputs)+",\n")
+	if this.Input != nil {
+		s = append(s, "Input: "+fmt.Sprintf("%#v", this.Input)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *InputSpec) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&odrpc.InputSpec{")
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "Layout: "+fmt.Sprintf("%#v", this.Layout)+",\n")
+	s = append(s, "Mean: "+fmt.Sprintf("%#v", this.Mean)+",\n")
+	s = append(s, "Scale: "+fmt.Sprintf("%#v", this.Scale)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DetectRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 23)
+	s = append(s, "&odrpc.DetectRequest{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "DetectorName: "+fmt.Sprintf("%#v", this.DetectorName)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "File: "+fmt.Sprintf("%#v", this.File)+",\n")
+	keysForDetect := make([]string, 0, len(this.Detect))
+	for k, _ := range this.Detect {
+		keysForDetect = append(keysForDetect, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
+	mapStringForDetect := "map[string]float32{"
+	for _, k := range keysForDetect {
+		mapStringForDetect += fmt.Sprintf("%#v: %#v,", k, this.Detect[k])
+	}
+	mapStringForDetect += "}"
+	if this.Detect != nil {
+		s = append(s, "Detect: "+mapStringForDetect+",\n")
+	}
+	if this.Regions != nil {
+		s = append(s, "Regions: "+fmt.Sprintf("%#v", this.Regions)+",\n")
+	}
+	s = append(s, "Language: "+fmt.Sprintf("%#v", this.Language)+",\n")
+	s = append(s, "Ignore: "+fmt.Sprintf("%#v", this.Ignore)+",\n")
+	s = append(s, "ImageUrl: "+fmt.Sprintf("%#v", this.ImageUrl)+",\n")
+	keysForInputs := make([]string, 0, len(this.Inputs))
+	for k, _ := range this.Inputs {
+		keysForInputs = append(keysForInputs, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForInputs)
+	mapStringForInputs := "map[string]*InputTensor{"
+	for _, k := range keysForInputs {
+		mapStringForInputs += fmt.Sprintf("%#v: %#v,", k, this.Inputs[k])
+	}
+	mapStringForInputs += "}"
+	if this.Inputs != nil {
+		s = append(s, "Inputs: "+mapStringForInputs+",\n")
+	}
+	s = append(s, "RawOutputs: "+fmt.Sprintf("%#v", this.RawOutputs)+",\n")
+	if this.Preprocess != nil {
+		s = append(s, "Preprocess: "+fmt.Sprintf("%#v", this.Preprocess)+",\n")
+	}
+	s = append(s, "Rotate: "+fmt.Sprintf("%#v", this.Rotate)+",\n")
+	s = append(s, "Flip: "+fmt.Sprintf("%#v", this.Flip)+",\n")
+	if this.Smooth != nil {
+		s = append(s, "Smooth: "+fmt.Sprintf("%#v", this.Smooth)+",\n")
+	}
+	if this.Confirm != nil {
+		s = append(s, "Confirm: "+fmt.Sprintf("%#v", this.Confirm)+",\n")
+	}
+	s = append(s, "Debug: "+fmt.Sprintf("%#v", this.Debug)+",\n")
+	s = append(s, "Cascade: "+fmt.Sprintf("%#v", this.Cascade)+",\n")
+	s = append(s, "DensityMap: "+fmt.Sprintf("%#v", this.DensityMap)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Confirm) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&odrpc.Confirm{")
+	s = append(s, "MinFrames: "+fmt.Sprintf("%#v", this.MinFrames)+",\n")
+	s = append(s, "Frames: "+fmt.Sprintf("%#v", this.Frames)+",\n")
+	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Smooth) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&odrpc.Smooth{")
+	s = append(s, "Method: "+fmt.Sprintf("%#v", this.Method)+",\n")
+	s = append(s, "Alpha: "+fmt.Sprintf("%#v", this.Alpha)+",\n")
+	s = append(s, "Iou: "+fmt.Sprintf("%#v", this.Iou)+",\n")
+	s = append(s, "MaxMissed: "+fmt.Sprintf("%#v", this.MaxMissed)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Preprocess) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&odrpc.Preprocess{")
+	s = append(s, "Clahe: "+fmt.Sprintf("%#v", this.Clahe)+",\n")
+	s = append(s, "ClipLimit: "+fmt.Sprintf("%#v", this.ClipLimit)+",\n")
+	s = append(s, "Tiles: "+fmt.Sprintf("%#v", this.Tiles)+",\n")
+	s = append(s, "Gamma: "+fmt.Sprintf("%#v", this.Gamma)+",\n")
+	s = append(s, "MaxBrightness: "+fmt.Sprintf("%#v", this.MaxBrightness)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *InputTensor) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&odrpc.InputTensor{")
+	s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DetectRegion) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&odrpc.DetectRegion{")
+	s = append(s, "Top: "+fmt.Sprintf("%#v", this.Top)+",\n")
+	s = append(s, "Left: "+fmt.Sprintf("%#v", this.Left)+",\n")
+	s = append(s, "Bottom: "+fmt.Sprintf("%#v", this.Bottom)+",\n")
+	s = append(s, "Right: "+fmt.Sprintf("%#v", this.Right)+",\n")
+	keysForDetect := make([]string, 0, len(this.Detect))
+	for k, _ := range this.Detect {
+		keysForDetect = append(keysForDetect, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
+	mapStringForDetect := "map[string]float32{"
+	for _, k := range keysForDetect {
+		mapStringForDetect += fmt.Sprintf("%#v: %#v,", k, this.Detect[k])
+	}
+	mapStringForDetect += "}"
+	if this.Detect != nil {
+		s = append(s, "Detect: "+mapStringForDetect+",\n")
+	}
+	s = append(s, "Covers: "+fmt.Sprintf("%#v", this.Covers)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Detection) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 15)
+	s = append(s, "&odrpc.Detection{")
+	s = append(s, "Top: "+fmt.Sprintf("%#v", this.Top)+",\n")
+	s = append(s, "Left: "+fmt.Sprintf("%#v", this.Left)+",\n")
+	s = append(s, "Bottom: "+fmt.Sprintf("%#v", this.Bottom)+",\n")
+	s = append(s, "Right: "+fmt.Sprintf("%#v", this.Right)+",\n")
+	s = append(s, "Label: "+fmt.Sprintf("%#v", this.Label)+",\n")
+	s = append(s, "Confidence: "+fmt.Sprintf("%#v", this.Confidence)+",\n")
+	s = append(s, "Track: "+fmt.Sprintf("%#v", this.Track)+",\n")
+	if this.Raw != nil {
+		s = append(s, "Raw: "+fmt.Sprintf("%#v", this.Raw)+",\n")
+	}
+	s = append(s, "Stage: "+fmt.Sprintf("%#v", this.Stage)+",\n")
+	if this.Children != nil {
+		s = append(s, "Children: "+fmt.Sprintf("%#v", this.Children)+",\n")
+	}
+	if this.Attributes != nil {
+		s = append(s, "Attributes: "+fmt.Sprintf("%#v", this.Attributes)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Attribute) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&odrpc.Attribute{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "Confidence: "+fmt.Sprintf("%#v", this.Confidence)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Box) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&odrpc.Box{")
+	s = append(s, "Top: "+fmt.Sprintf("%#v", this.Top)+",\n")
+	s = append(s, "Left: "+fmt.Sprintf("%#v", this.Left)+",\n")
+	s = append(s, "Bottom: "+fmt.Sprintf("%#v", this.Bottom)+",\n")
+	s = append(s, "Right: "+fmt.Sprintf("%#v", this.Right)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DetectResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 15)
+	s = append(s, "&odrpc.DetectResponse{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	if this.Detections != nil {
+		s = append(s, "Detections: "+fmt.Sprintf("%#v", this.Detections)+",\n")
+	}
+	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
+	if this.Outputs != nil {
+		s = append(s, "Outputs: "+fmt.Sprintf("%#v", this.Outputs)+",\n")
+	}
+	if this.Quality != nil {
+		s = append(s, "Quality: "+fmt.Sprintf("%#v", this.Quality)+",\n")
+	}
+	s = append(s, "Degraded: "+fmt.Sprintf("%#v", this.Degraded)+",\n")
+	s = append(s, "FallbackDetector: "+fmt.Sprintf("%#v", this.FallbackDetector)+",\n")
+	s = append(s, "RetryAfterMs: "+fmt.Sprintf("%#v", this.RetryAfterMs)+",\n")
+	if this.Signature != nil {
+		s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	}
+	s = append(s, "Debug: "+fmt.Sprintf("%#v", this.Debug)+",\n")
+	if this.Density != nil {
+		s = append(s, "Density: "+fmt.Sprintf("%#v", this.Density)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Signature) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&odrpc.Signature{")
+	s = append(s, "Algorithm: "+fmt.Sprintf("%#v", this.Algorithm)+",\n")
+	s = append(s, "KeyId: "+fmt.Sprintf("%#v", this.KeyId)+",\n")
+	s = append(s, "SignedAt: "+fmt.Sprintf("%#v", this.SignedAt)+",\n")
+	s = append(s, "ImageSha256: "+fmt.Sprintf("%#v", this.ImageSha256)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SignedDetections) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&odrpc.SignedDetections{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	if this.Detections != nil {
+		s = append(s, "Detections: "+fmt.Sprintf("%#v", this.Detections)+",\n")
+	}
+	s = append(s, "Algorithm: "+fmt.Sprintf("%#v", this.Algorithm)+",\n")
+	s = append(s, "KeyId: "+fmt.Sprintf("%#v", this.KeyId)+",\n")
+	s = append(s, "SignedAt: "+fmt.Sprintf("%#v", this.SignedAt)+",\n")
+	s = append(s, "ImageSha256: "+fmt.Sprintf("%#v", this.ImageSha256)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *CascadeResult) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 13)
+	s = append(s, "&odrpc.CascadeResult{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "Stage: "+fmt.Sprintf("%#v", this.Stage)+",\n")
+	s = append(s, "Parent: "+fmt.Sprintf("%#v", this.Parent)+",\n")
+	if this.Detections != nil {
+		s = append(s, "Detections: "+fmt.Sprintf("%#v", this.Detections)+",\n")
+	}
+	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
+	s = append(s, "Done: "+fmt.Sprintf("%#v", this.Done)+",\n")
+	s = append(s, "ParentStage: "+fmt.Sprintf("%#v", this.ParentStage)+",\n")
+	s = append(s, "Path: "+fmt.Sprintf("%#v", this.Path)+",\n")
+	if this.Objects != nil {
+		s = append(s, "Objects: "+fmt.Sprintf("%#v", this.Objects)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DetectBoxesRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 16)
+	s = append(s, "&odrpc.DetectBoxesRequest{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "DetectorName: "+fmt.Sprintf("%#v", this.DetectorName)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "File: "+fmt.Sprintf("%#v", this.File)+",\n")
+	s = append(s, "ImageUrl: "+fmt.Sprintf("%#v", this.ImageUrl)+",\n")
+	if this.Boxes != nil {
+		s = append(s, "Boxes: "+fmt.Sprintf("%#v", this.Boxes)+",\n")
+	}
+	s = append(s, "Pixels: "+fmt.Sprintf("%#v", this.Pixels)+",\n")
+	s = append(s, "Padding: "+fmt.Sprintf("%#v", this.Padding)+",\n")
+	keysForDetect := make([]string, 0, len(this.Detect))
+	for k, _ := range this.Detect {
+		keysForDetect = append(keysForDetect, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
+	mapStringForDetect := "map[string]float32{"
+	for _, k := range keysForDetect {
+		mapStringForDetect += fmt.Sprintf("%#v: %#v,", k, this.Detect[k])
+	}
+	mapStringForDetect += "}"
+	if this.Detect != nil {
+		s = append(s, "Detect: "+mapStringForDetect+",\n")
+	}
+	s = append(s, "Language: "+fmt.Sprintf("%#v", this.Language)+",\n")
+	s = append(s, "Ignore: "+fmt.Sprintf("%#v", this.Ignore)+",\n")
+	s = append(s, "MaxDetections: "+fmt.Sprintf("%#v", this.MaxDetections)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *BoxResult) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&odrpc.BoxResult{")
+	s = append(s, "Index: "+fmt.Sprintf("%#v", this.Index)+",\n")
+	if this.Box != nil {
+		s = append(s, "Box: "+fmt.Sprintf("%#v", this.Box)+",\n")
+	}
+	if this.Detections != nil {
+		s = append(s, "Detections: "+fmt.Sprintf("%#v", this.Detections)+",\n")
+	}
+	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DetectBoxesResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&odrpc.DetectBoxesResponse{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	if this.Results != nil {
+		s = append(s, "Results: "+fmt.Sprintf("%#v", this.Results)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DiffRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 12)
+	s = append(s, "&odrpc.DiffRequest{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "Before: "+fmt.Sprintf("%#v", this.Before)+",\n")
+	s = append(s, "After: "+fmt.Sprintf("%#v", this.After)+",\n")
+	s = append(s, "BeforeUrl: "+fmt.Sprintf("%#v", this.BeforeUrl)+",\n")
+	s = append(s, "AfterUrl: "+fmt.Sprintf("%#v", this.AfterUrl)+",\n")
+	s = append(s, "Threshold: "+fmt.Sprintf("%#v", this.Threshold)+",\n")
+	s = append(s, "MinArea: "+fmt.Sprintf("%#v", this.MinArea)+",\n")
+	s = append(s, "Width: "+fmt.Sprintf("%#v", this.Width)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ChangedRegion) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&odrpc.ChangedRegion{")
+	if this.Box != nil {
+		s = append(s, "Box: "+fmt.Sprintf("%#v", this.Box)+",\n")
+	}
+	s = append(s, "Score: "+fmt.Sprintf("%#v", this.Score)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DiffResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&odrpc.DiffResponse{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	if this.Regions != nil {
+		s = append(s, "Regions: "+fmt.Sprintf("%#v", this.Regions)+",\n")
+	}
+	s = append(s, "Score: "+fmt.Sprintf("%#v", this.Score)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PreprocessRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 14)
+	s = append(s, "&odrpc.PreprocessRequest{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "DetectorName: "+fmt.Sprintf("%#v", this.DetectorName)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "File: "+fmt.Sprintf("%#v", this.File)+",\n")
+	s = append(s, "ImageUrl: "+fmt.Sprintf("%#v", this.ImageUrl)+",\n")
+	s = append(s, "Format: "+fmt.Sprintf("%#v", this.Format)+",\n")
+	s = append(s, "Letterbox: "+fmt.Sprintf("%#v", this.Letterbox)+",\n")
+	if this.Preprocess != nil {
+		s = append(s, "Preprocess: "+fmt.Sprintf("%#v", this.Preprocess)+",\n")
+	}
+	s = append(s, "Rotate: "+fmt.Sprintf("%#v", this.Rotate)+",\n")
+	s = append(s, "Flip: "+fmt.Sprintf("%#v", this.Flip)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PreprocessResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 13)
+	s = append(s, "&odrpc.PreprocessResponse{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "Format: "+fmt.Sprintf("%#v", this.Format)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "Width: "+fmt.Sprintf("%#v", this.Width)+",\n")
+	s = append(s, "Height: "+fmt.Sprintf("%#v", this.Height)+",\n")
+	s = append(s, "Channels: "+fmt.Sprintf("%#v", this.Channels)+",\n")
+	if this.Input != nil {
+		s = append(s, "Input: "+fmt.Sprintf("%#v", this.Input)+",\n")
+	}
+	s = append(s, "Shape: "+fmt.Sprintf("%#v", this.Shape)+",\n")
+	if this.Content != nil {
+		s = append(s, "Content: "+fmt.Sprintf("%#v", this.Content)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Quality) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&odrpc.Quality{")
+	s = append(s, "Brightness: "+fmt.Sprintf("%#v", this.Brightness)+",\n")
+	s = append(s, "Overexposed: "+fmt.Sprintf("%#v", this.Overexposed)+",\n")
+	s = append(s, "Sharpness: "+fmt.Sprintf("%#v", this.Sharpness)+",\n")
+	s = append(s, "Problems: "+fmt.Sprintf("%#v", this.Problems)+",\n")
+	s = append(s, "Skipped: "+fmt.Sprintf("%#v", this.Skipped)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Density) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&odrpc.Density{")
+	s = append(s, "Label: "+fmt.Sprintf("%#v", this.Label)+",\n")
+	s = append(s, "Count: "+fmt.Sprintf("%#v", this.Count)+",\n")
+	keysForZones := make([]string, 0, len(this.Zones))
+	for k, _ := range this.Zones {
+		keysForZones = append(keysForZones, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForZones)
+	mapStringForZones := "map[string]float32{"
+	for _, k := range keysForZones {
+		mapStringForZones += fmt.Sprintf("%#v: %#v,", k, this.Zones[k])
+	}
+	mapStringForZones += "}"
+	if this.Zones != nil {
+		s = append(s, "Zones: "+mapStringForZones+",\n")
+	}
+	s = append(s, "Width: "+fmt.Sprintf("%#v", this.Width)+",\n")
+	s = append(s, "Height: "+fmt.Sprintf("%#v", this.Height)+",\n")
+	s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *OutputTensor) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&odrpc.OutputTensor{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
+	s = append(s, "Shape: "+fmt.Sprintf("%#v", this.Shape)+",\n")
+	s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringRpc(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// OdrpcClient is the client API for Odrpc service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type OdrpcClient interface {
+	// Get Config
+	GetDetectors(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*GetDetectorsResponse, error)
+	// Process an request
+	Detect(ctx context.Context, in *DetectRequest, opts ...grpc.CallOption) (*DetectResponse, error)
+	// Process stream requests
+	DetectStream(ctx context.Context, opts ...grpc.CallOption) (Odrpc_DetectStreamClient, error)
+	// Detect and stream the first stage detections right away and then the results of the cascade stages as they finish
+	DetectCascade(ctx context.Context, in *DetectRequest, opts ...grpc.CallOption) (Odrpc_DetectCascadeClient, error)
+	// Run the detector on each of the boxes in the image, like classifying the objects found by a motion detector
+	DetectBoxes(ctx context.Context, in *DetectBoxesRequest, opts ...grpc.CallOption) (*DetectBoxesResponse, error)
+	// Compare two images and return the regions that changed, a cheap check before detecting
+	DiffImages(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error)
+	// Prepare an image for a detector like detect does and return the resized image or the model input tensor
+	Preprocess(ctx context.Context, in *PreprocessRequest, opts ...grpc.CallOption) (*PreprocessResponse, error)
+}
+
+type odrpcClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewOdrpcClient(cc *grpc.ClientConn) OdrpcClient {
+	return &odrpcClient{cc}
+}
+
+func (c *odrpcClient) GetDetectors(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*GetDetectorsResponse, error) {
+	out := new(GetDetectorsResponse)
+	err := c.cc.Invoke(ctx, "/odrpc.odrpc/GetDetectors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *odrpcClient) Detect(ctx context.Context, in *DetectRequest, opts ...grpc.CallOption) (*DetectResponse, error) {
+	out := new(DetectResponse)
+	err := c.cc.Invoke(ctx, "/odrpc.odrpc/Detect", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *odrpcClient) DetectStream(ctx context.Context, opts ...grpc.CallOption) (Odrpc_DetectStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Odrpc_serviceDesc.Streams[0], "/odrpc.odrpc/DetectStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &odrpcDetectStreamClient{stream}
+	return x, nil
+}
+
+type Odrpc_DetectStreamClient interface {
+	Send(*DetectRequest) error
+	Recv() (*DetectResponse, error)
+	grpc.ClientStream
+}
+
+type odrpcDetectStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *odrpcDetectStreamClient) Send(m *DetectRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *odrpcDetectStreamClient) Recv() (*DetectResponse, error) {
+	m := new(DetectResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *odrpcClient) DetectCascade(ctx context.Context, in *DetectRequest, opts ...grpc.CallOption) (Odrpc_DetectCascadeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Odrpc_serviceDesc.Streams[1], "/odrpc.odrpc/DetectCascade", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &odrpcDetectCascadeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Odrpc_DetectCascadeClient interface {
+	Recv() (*CascadeResult, error)
+	grpc.ClientStream
+}
+
+type odrpcDetectCascadeClient struct {
+	grpc.ClientStream
+}
+
+func (x *odrpcDetectCascadeClient) Recv() (*CascadeResult, error) {
+	m := new(CascadeResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *odrpcClient) DetectBoxes(ctx context.Context, in *DetectBoxesRequest, opts ...grpc.CallOption) (*DetectBoxesResponse, error) {
+	out := new(DetectBoxesResponse)
+	err := c.cc.Invoke(ctx, "/odrpc.odrpc/DetectBoxes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *odrpcClient) DiffImages(ctx context.Context, in *DiffRequest, opts ...grpc.CallOption) (*DiffResponse, error) {
+	out := new(DiffResponse)
+	err := c.cc.Invoke(ctx, "/odrpc.odrpc/DiffImages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *odrpcClient) Preprocess(ctx context.Context, in *PreprocessRequest, opts ...grpc.CallOption) (*PreprocessResponse, error) {
+	out := new(PreprocessResponse)
+	err := c.cc.Invoke(ctx, "/odrpc.odrpc/Preprocess", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OdrpcServer is the server API for Odrpc service.
+type OdrpcServer interface {
+	// Get Config
+	GetDetectors(context.Context, *empty.Empty) (*GetDetectorsResponse, error)
+	// Process an request
+	Detect(context.Context, *DetectRequest) (*DetectResponse, error)
+	// Process stream requests
+	DetectStream(Odrpc_DetectStreamServer) error
+	// Detect and stream the first stage detections right away and then the results of the cascade stages as they finish
+	DetectCascade(*DetectRequest, Odrpc_DetectCascadeServer) error
+	// Run the detector on each of the boxes in the image, like classifying the objects found by a motion detector
+	DetectBoxes(context.Context, *DetectBoxesRequest) (*DetectBoxesResponse, error)
+	// Compare two images and return the regions that changed, a cheap check before detecting
+	DiffImages(context.Context, *DiffRequest) (*DiffResponse, error)
+	// Prepare an image for a detector like detect does and return the resized image or the model input tensor
+	Preprocess(context.Context, *PreprocessRequest) (*PreprocessResponse, error)
+}
+
+// UnimplementedOdrpcServer can be embedded to have forward compatible implementations.
+type UnimplementedOdrpcServer struct {
+}
+
+func (*UnimplementedOdrpcServer) GetDetectors(ctx context.Context, req *empty.Empty) (*GetDetectorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDetectors not implemented")
+}
+func (*UnimplementedOdrpcServer) Detect(ctx context.Context, req *DetectRequest) (*DetectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Detect not implemented")
+}
+func (*UnimplementedOdrpcServer) DetectStream(srv Odrpc_DetectStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method DetectStream not implemented")
+}
+func (*UnimplementedOdrpcServer) DetectCascade(req *DetectRequest, srv Odrpc_DetectCascadeServer) error {
+	return status.Errorf(codes.Unimplemented, "method DetectCascade not implemented")
+}
+func (*UnimplementedOdrpcServer) DetectBoxes(ctx context.Context, req *DetectBoxesRequest) (*DetectBoxesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetectBoxes not implemented")
+}
+func (*UnimplementedOdrpcServer) DiffImages(ctx context.Context, req *DiffRequest) (*DiffResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffImages not implemented")
+}
+func (*UnimplementedOdrpcServer) Preprocess(ctx context.Context, req *PreprocessRequest) (*PreprocessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Preprocess not implemented")
+}
+
+func RegisterOdrpcServer(s *grpc.Server, srv OdrpcServer) {
+	s.RegisterService(&_Odrpc_serviceDesc, srv)
+}
+
+func _Odrpc_GetDetectors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(empty.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OdrpcServer).GetDetectors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odrpc.odrpc/GetDetectors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OdrpcServer).GetDetectors(ctx, req.(*empty.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Odrpc_Detect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OdrpcServer).Detect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odrpc.odrpc/Detect",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OdrpcServer).Detect(ctx, req.(*DetectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Odrpc_DetectStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OdrpcServer).DetectStream(&odrpcDetectStreamServer{stream})
+}
+
+type Odrpc_DetectStreamServer interface {
+	Send(*DetectResponse) error
+	Recv() (*DetectRequest, error)
+	grpc.ServerStream
+}
+
+type odrpcDetectStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *odrpcDetectStreamServer) Send(m *DetectResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *odrpcDetectStreamServer) Recv() (*DetectRequest, error) {
+	m := new(DetectRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Odrpc_DetectCascade_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DetectRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OdrpcServer).DetectCascade(m, &odrpcDetectCascadeServer{stream})
+}
+
+type Odrpc_DetectCascadeServer interface {
+	Send(*CascadeResult) error
+	grpc.ServerStream
+}
+
+type odrpcDetectCascadeServer struct {
+	grpc.ServerStream
+}
+
+func (x *odrpcDetectCascadeServer) Send(m *CascadeResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Odrpc_DetectBoxes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetectBoxesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OdrpcServer).DetectBoxes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odrpc.odrpc/DetectBoxes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OdrpcServer).DetectBoxes(ctx, req.(*DetectBoxesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Odrpc_DiffImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OdrpcServer).DiffImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odrpc.odrpc/DiffImages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OdrpcServer).DiffImages(ctx, req.(*DiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Odrpc_Preprocess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreprocessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OdrpcServer).Preprocess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/odrpc.odrpc/Preprocess",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OdrpcServer).Preprocess(ctx, req.(*PreprocessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Odrpc_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "odrpc.odrpc",
+	HandlerType: (*OdrpcServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDetectors",
+			Handler:    _Odrpc_GetDetectors_Handler,
+		},
+		{
+			MethodName: "Detect",
+			Handler:    _Odrpc_Detect_Handler,
+		},
+		{
+			MethodName: "DetectBoxes",
+			Handler:    _Odrpc_DetectBoxes_Handler,
+		},
+		{
+			MethodName: "DiffImages",
+			Handler:    _Odrpc_DiffImages_Handler,
+		},
+		{
+			MethodName: "Preprocess",
+			Handler:    _Odrpc_Preprocess_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DetectStream",
+			Handler:       _Odrpc_DetectStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DetectCascade",
+			Handler:       _Odrpc_DetectCascade_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "odrpc/rpc.proto",
+}
+
+func (m *GetDetectorsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetDetectorsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetDetectorsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Detectors) > 0 {
+		for iNdEx := len(m.Detectors) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Detectors[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Detector) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Detector) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Detector) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Input != nil {
+		{
+			size, err := m.Input.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.Inputs) > 0 {
+		for iNdEx := len(m.Inputs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Inputs[iNdEx])
+			copy(dAtA[i:], m.Inputs[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Inputs[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.Languages) > 0 {
+		for iNdEx := len(m.Languages) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Languages[iNdEx])
+			copy(dAtA[i:], m.Languages[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Languages[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if m.Channels != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Channels))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.Height != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Width != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Width))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Labels[iNdEx])
+			copy(dAtA[i:], m.Labels[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Labels[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Model) > 0 {
+		i -= len(m.Model)
+		copy(dAtA[i:], m.Model)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Model)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *InputSpec) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *InputSpec) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *InputSpec) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Scale) > 0 {
+		for iNdEx := len(m.Scale) - 1; iNdEx >= 0; iNdEx-- {
+			f2 := math.Float32bits(float32(m.Scale[iNdEx]))
+			i -= 4
+			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(f2))
+		}
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Scale)*4))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Mean) > 0 {
+		for iNdEx := len(m.Mean) - 1; iNdEx >= 0; iNdEx-- {
+			f3 := math.Float32bits(float32(m.Mean[iNdEx]))
+			i -= 4
+			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(f3))
+		}
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Mean)*4))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Layout) > 0 {
+		i -= len(m.Layout)
+		copy(dAtA[i:], m.Layout)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Layout)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DetectRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DetectRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DetectRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.DensityMap {
+		i--
+		if m.DensityMap {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x98
+	}
+	if m.Cascade {
+		i--
+		if m.Cascade {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x90
+	}
+	if m.Debug {
+		i--
+		if m.Debug {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x88
+	}
+	if m.Confirm != nil {
+		{
+			size, err := m.Confirm.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	if m.Smooth != nil {
+		{
+			size, err := m.Smooth.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x7a
+	}
+	if len(m.Flip) > 0 {
+		i -= len(m.Flip)
+		copy(dAtA[i:], m.Flip)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Flip)))
+		i--
+		dAtA[i] = 0x72
+	}
+	if m.Rotate != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Rotate))
+		i--
+		dAtA[i] = 0x68
+	}
+	if m.Preprocess != nil {
+		{
+			size, err := m.Preprocess.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.RawOutputs != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.RawOutputs))
+		i--
+		dAtA[i] = 0x58
+	}
+	if len(m.Inputs) > 0 {
+		for k := range m.Inputs {
+			v := m.Inputs[k]
+			baseI := i
+			if v != nil {
+				{
+					size, err := v.MarshalToSizedBuffer(dAtA[:i])
+					if err != nil {
+						return 0, err
+					}
+					i -= size
+					i = encodeVarintRpc(dAtA, i, uint64(size))
+				}
+				i--
+				dAtA[i] = 0x12
+			}
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintRpc(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintRpc(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if len(m.ImageUrl) > 0 {
+		i -= len(m.ImageUrl)
+		copy(dAtA[i:], m.ImageUrl)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.ImageUrl)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Ignore) > 0 {
+		for iNdEx := len(m.Ignore) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Ignore[iNdEx])
+			copy(dAtA[i:], m.Ignore[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Ignore[iNdEx])))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.Language) > 0 {
+		i -= len(m.Language)
+		copy(dAtA[i:], m.Language)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Language)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.Regions) > 0 {
+		for iNdEx := len(m.Regions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Regions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.Detect) > 0 {
+		for k := range m.Detect {
+			v := m.Detect[k]
+			baseI := i
+			i -= 4
+			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(v))))
+			i--
+			dAtA[i] = 0x15
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintRpc(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintRpc(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.File) > 0 {
+		i -= len(m.File)
+		copy(dAtA[i:], m.File)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.File)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.DetectorName) > 0 {
+		i -= len(m.DetectorName)
+		copy(dAtA[i:], m.DetectorName)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.DetectorName)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Confirm) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Confirm) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Confirm) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Labels[iNdEx])
+			copy(dAtA[i:], m.Labels[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Labels[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Frames != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Frames))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.MinFrames != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.MinFrames))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Smooth) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Smooth) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Smooth) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.MaxMissed != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.MaxMissed))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Iou != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Iou))))
+		i--
+		dAtA[i] = 0x1d
+	}
+	if m.Alpha != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Alpha))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if len(m.Method) > 0 {
+		i -= len(m.Method)
+		copy(dAtA[i:], m.Method)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Method)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Preprocess) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Preprocess) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Preprocess) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.MaxBrightness != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.MaxBrightness))))
+		i--
+		dAtA[i] = 0x2d
+	}
+	if m.Gamma != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Gamma))))
+		i--
+		dAtA[i] = 0x25
+	}
+	if m.Tiles != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Tiles))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.ClipLimit != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.ClipLimit))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if m.Clahe {
+		i--
+		if m.Clahe {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *InputTensor) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *InputTensor) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *InputTensor) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			f8 := math.Float32bits(float32(m.Values[iNdEx]))
+			i -= 4
+			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(f8))
+		}
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Values)*4))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DetectRegion) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DetectRegion) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DetectRegion) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Covers {
+		i--
+		if m.Covers {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Detect) > 0 {
+		for k := range m.Detect {
+			v := m.Detect[k]
+			baseI := i
+			i -= 4
+			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(v))))
+			i--
+			dAtA[i] = 0x15
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintRpc(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintRpc(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.Right != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Right))))
+		i--
+		dAtA[i] = 0x25
+	}
+	if m.Bottom != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Bottom))))
+		i--
+		dAtA[i] = 0x1d
+	}
+	if m.Left != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Left))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if m.Top != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Top))))
+		i--
+		dAtA[i] = 0xd
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Detection) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Detection) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Detection) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Attributes) > 0 {
+		for iNdEx := len(m.Attributes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Attributes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if len(m.Children) > 0 {
+		for iNdEx := len(m.Children) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Children[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if len(m.Stage) > 0 {
+		i -= len(m.Stage)
+		copy(dAtA[i:], m.Stage)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Stage)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.Raw != nil {
+		{
+			size, err := m.Raw.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.Track != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Track))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.Confidence != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Confidence))))
+		i--
+		dAtA[i] = 0x35
+	}
+	if len(m.Label) > 0 {
+		i -= len(m.Label)
+		copy(dAtA[i:], m.Label)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Label)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Right != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Right))))
+		i--
+		dAtA[i] = 0x25
+	}
+	if m.Bottom != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Bottom))))
+		i--
+		dAtA[i] = 0x1d
+	}
+	if m.Left != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Left))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if m.Top != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Top))))
+		i--
+		dAtA[i] = 0xd
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Attribute) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Attribute) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Attribute) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Confidence != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Confidence))))
+		i--
+		dAtA[i] = 0x1d
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Box) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Box) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Box) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Right != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Right))))
+		i--
+		dAtA[i] = 0x25
+	}
+	if m.Bottom != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Bottom))))
+		i--
+		dAtA[i] = 0x1d
+	}
+	if m.Left != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Left))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if m.Top != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Top))))
+		i--
+		dAtA[i] = 0xd
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DetectResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DetectResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DetectResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Density != nil {
+		{
+			size, err := m.Density.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	if len(m.Debug) > 0 {
+		i -= len(m.Debug)
+		copy(dAtA[i:], m.Debug)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Debug)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.Signature != nil {
+		{
+			size, err := m.Signature.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.RetryAfterMs != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.RetryAfterMs))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.FallbackDetector) > 0 {
+		i -= len(m.FallbackDetector)
+		copy(dAtA[i:], m.FallbackDetector)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.FallbackDetector)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Degraded {
+		i--
+		if m.Degraded {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Quality != nil {
+		{
+			size, err := m.Quality.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Outputs) > 0 {
+		for iNdEx := len(m.Outputs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Outputs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Detections) > 0 {
+		for iNdEx := len(m.Detections) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Detections[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Signature) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Signature) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Signature) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.ImageSha256) > 0 {
+		i -= len(m.ImageSha256)
+		copy(dAtA[i:], m.ImageSha256)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.ImageSha256)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.SignedAt != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.SignedAt))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.KeyId) > 0 {
+		i -= len(m.KeyId)
+		copy(dAtA[i:], m.KeyId)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.KeyId)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Algorithm) > 0 {
+		i -= len(m.Algorithm)
+		copy(dAtA[i:], m.Algorithm)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Algorithm)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SignedDetections) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SignedDetections) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SignedDetections) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.ImageSha256) > 0 {
+		i -= len(m.ImageSha256)
+		copy(dAtA[i:], m.ImageSha256)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.ImageSha256)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.SignedAt != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.SignedAt))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.KeyId) > 0 {
+		i -= len(m.KeyId)
+		copy(dAtA[i:], m.KeyId)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.KeyId)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Algorithm) > 0 {
+		i -= len(m.Algorithm)
+		copy(dAtA[i:], m.Algorithm)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Algorithm)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Detections) > 0 {
+		for iNdEx := len(m.Detections) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Detections[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CascadeResult) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CascadeResult) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CascadeResult) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Objects) > 0 {
+		for iNdEx := len(m.Objects) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Objects[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if len(m.Path) > 0 {
+		dAtA14 := make([]byte, len(m.Path)*10)
+		var j13 int
+		for _, num1 := range m.Path {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA14[j13] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j13++
+			}
+			dAtA14[j13] = uint8(num)
+			j13++
+		}
+		i -= j13
+		copy(dAtA[i:], dAtA14[:j13])
+		i = encodeVarintRpc(dAtA, i, uint64(j13))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.ParentStage) > 0 {
+		i -= len(m.ParentStage)
+		copy(dAtA[i:], m.ParentStage)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.ParentStage)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Done {
+		i--
+		if m.Done {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Detections) > 0 {
+		for iNdEx := len(m.Detections) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Detections[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.Parent != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Parent))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Stage) > 0 {
+		i -= len(m.Stage)
+		copy(dAtA[i:], m.Stage)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Stage)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DetectBoxesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DetectBoxesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DetectBoxesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.MaxDetections != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.MaxDetections))
+		i--
+		dAtA[i] = 0x60
+	}
+	if len(m.Ignore) > 0 {
+		for iNdEx := len(m.Ignore) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Ignore[iNdEx])
+			copy(dAtA[i:], m.Ignore[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Ignore[iNdEx])))
+			i--
+			dAtA[i] = 0x5a
+		}
+	}
+	if len(m.Language) > 0 {
+		i -= len(m.Language)
+		copy(dAtA[i:], m.Language)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Language)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.Detect) > 0 {
+		for k := range m.Detect {
+			v := m.Detect[k]
+			baseI := i
+			i -= 4
+			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(v))))
+			i--
+			dAtA[i] = 0x15
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintRpc(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintRpc(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if m.Padding != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Padding))))
+		i--
+		dAtA[i] = 0x45
+	}
+	if m.Pixels {
+		i--
+		if m.Pixels {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
+	if len(m.Boxes) > 0 {
+		for iNdEx := len(m.Boxes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Boxes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.ImageUrl) > 0 {
+		i -= len(m.ImageUrl)
+		copy(dAtA[i:], m.ImageUrl)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.ImageUrl)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.File) > 0 {
+		i -= len(m.File)
+		copy(dAtA[i:], m.File)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.File)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.DetectorName) > 0 {
+		i -= len(m.DetectorName)
+		copy(dAtA[i:], m.DetectorName)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.DetectorName)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *BoxResult) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BoxResult) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *BoxResult) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Detections) > 0 {
+		for iNdEx := len(m.Detections) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Detections[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Box != nil {
+		{
+			size, err := m.Box.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Index != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DetectBoxesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DetectBoxesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DetectBoxesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Results) > 0 {
+		for iNdEx := len(m.Results) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Results[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DiffRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DiffRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DiffRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Width != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Width))
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.MinArea != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.MinArea))))
+		i--
+		dAtA[i] = 0x3d
+	}
+	if m.Threshold != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Threshold))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.AfterUrl) > 0 {
+		i -= len(m.AfterUrl)
+		copy(dAtA[i:], m.AfterUrl)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.AfterUrl)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.BeforeUrl) > 0 {
+		i -= len(m.BeforeUrl)
+		copy(dAtA[i:], m.BeforeUrl)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.BeforeUrl)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.After) > 0 {
+		i -= len(m.After)
+		copy(dAtA[i:], m.After)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.After)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Before) > 0 {
+		i -= len(m.Before)
+		copy(dAtA[i:], m.Before)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Before)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ChangedRegion) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ChangedRegion) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ChangedRegion) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Score != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Score))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if m.Box != nil {
+		{
+			size, err := m.Box.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DiffResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DiffResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DiffResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Score != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Score))))
+		i--
+		dAtA[i] = 0x1d
+	}
+	if len(m.Regions) > 0 {
+		for iNdEx := len(m.Regions) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Regions[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRpc(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PreprocessRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PreprocessRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PreprocessRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Flip) > 0 {
+		i -= len(m.Flip)
+		copy(dAtA[i:], m.Flip)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Flip)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if m.Rotate != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Rotate))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.Preprocess != nil {
+		{
+			size, err := m.Preprocess.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.Letterbox {
+		i--
+		if m.Letterbox {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
+	if len(m.Format) > 0 {
+		i -= len(m.Format)
+		copy(dAtA[i:], m.Format)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Format)))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.ImageUrl) > 0 {
+		i -= len(m.ImageUrl)
+		copy(dAtA[i:], m.ImageUrl)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.ImageUrl)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.File) > 0 {
+		i -= len(m.File)
+		copy(dAtA[i:], m.File)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.File)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.DetectorName) > 0 {
+		i -= len(m.DetectorName)
+		copy(dAtA[i:], m.DetectorName)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.DetectorName)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *PreprocessResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PreprocessResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PreprocessResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Content != nil {
+		{
+			size, err := m.Content.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Shape) > 0 {
+		dAtA20 := make([]byte, len(m.Shape)*10)
+		var j19 int
+		for _, num1 := range m.Shape {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA20[j19] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j19++
+			}
+			dAtA20[j19] = uint8(num)
+			j19++
+		}
+		i -= j19
+		copy(dAtA[i:], dAtA20[:j19])
+		i = encodeVarintRpc(dAtA, i, uint64(j19))
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.Input != nil {
+		{
+			size, err := m.Input.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRpc(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Channels != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Channels))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Height != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Width != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Width))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Format) > 0 {
+		i -= len(m.Format)
+		copy(dAtA[i:], m.Format)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Format)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Quality) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Quality) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Quality) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Skipped {
+		i--
+		if m.Skipped {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Problems) > 0 {
+		for iNdEx := len(m.Problems) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Problems[iNdEx])
+			copy(dAtA[i:], m.Problems[iNdEx])
+			i = encodeVarintRpc(dAtA, i, uint64(len(m.Problems[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.Sharpness != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Sharpness))))
+		i--
+		dAtA[i] = 0x1d
+	}
+	if m.Overexposed != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Overexposed))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if m.Brightness != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Brightness))))
+		i--
+		dAtA[i] = 0xd
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Density) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Density) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Density) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			f22 := math.Float32bits(float32(m.Values[iNdEx]))
+			i -= 4
+			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(f22))
+		}
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Values)*4))
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.Height != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Width != 0 {
+		i = encodeVarintRpc(dAtA, i, uint64(m.Width))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Zones) > 0 {
+		for k := range m.Zones {
+			v := m.Zones[k]
+			baseI := i
+			i -= 4
+			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(v))))
+			i--
+			dAtA[i] = 0x15
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintRpc(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintRpc(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Count != 0 {
+		i -= 4
+		encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.Count))))
+		i--
+		dAtA[i] = 0x15
+	}
+	if len(m.Label) > 0 {
+		i -= len(m.Label)
+		copy(dAtA[i:], m.Label)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Label)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *OutputTensor) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OutputTensor) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OutputTensor) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			f23 := math.Float32bits(float32(m.Values[iNdEx]))
+			i -= 4
+			encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(f23))
+		}
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Values)*4))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Shape) > 0 {
+		dAtA25 := make([]byte, len(m.Shape)*10)
+		var j24 int
+		for _, num1 := range m.Shape {
+			num := uint64(num1)
+			for num >= 1<<7 {
+				dAtA25[j24] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j24++
+			}
+			dAtA25[j24] = uint8(num)
+			j24++
+		}
+		i -= j24
+		copy(dAtA[i:], dAtA25[:j24])
+		i = encodeVarintRpc(dAtA, i, uint64(j24))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintRpc(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintRpc(dAtA []byte, offset int, v uint64) int {
+	offset -= sovRpc(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *GetDetectorsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Detectors) > 0 {
+		for _, e := range m.Detectors {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Detector) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Model)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Labels) > 0 {
+		for _, s := range m.Labels {
+			l = len(s)
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if m.Width != 0 {
+		n += 1 + sovRpc(uint64(m.Width))
+	}
+	if m.Height != 0 {
+		n += 1 + sovRpc(uint64(m.Height))
+	}
+	if m.Channels != 0 {
+		n += 1 + sovRpc(uint64(m.Channels))
+	}
+	if len(m.Languages) > 0 {
+		for _, s := range m.Languages {
+			l = len(s)
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if len(m.Inputs) > 0 {
+		for _, s := range m.Inputs {
+			l = len(s)
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if m.Input != nil {
+		l = m.Input.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func (m *InputSpec) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Layout)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Mean) > 0 {
+		n += 1 + sovRpc(uint64(len(m.Mean)*4)) + len(m.Mean)*4
+	}
+	if len(m.Scale) > 0 {
+		n += 1 + sovRpc(uint64(len(m.Scale)*4)) + len(m.Scale)*4
+	}
+	return n
+}
+
+func (m *DetectRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.DetectorName)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.File)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Detect) > 0 {
+		for k, v := range m.Detect {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovRpc(uint64(len(k))) + 1 + 4
+			n += mapEntrySize + 1 + sovRpc(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Regions) > 0 {
+		for _, e := range m.Regions {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	l = len(m.Language)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Ignore) > 0 {
+		for _, s := range m.Ignore {
+			l = len(s)
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	l = len(m.ImageUrl)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Inputs) > 0 {
+		for k, v := range m.Inputs {
+			_ = k
+			_ = v
+			l = 0
+			if v != nil {
+				l = v.Size()
+				l += 1 + sovRpc(uint64(l))
+			}
+			mapEntrySize := 1 + len(k) + sovRpc(uint64(len(k))) + l
+			n += mapEntrySize + 1 + sovRpc(uint64(mapEntrySize))
+		}
+	}
+	if m.RawOutputs != 0 {
+		n += 1 + sovRpc(uint64(m.RawOutputs))
+	}
+	if m.Preprocess != nil {
+		l = m.Preprocess.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Rotate != 0 {
+		n += 1 + sovRpc(uint64(m.Rotate))
+	}
+	l = len(m.Flip)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Smooth != nil {
+		l = m.Smooth.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Confirm != nil {
+		l = m.Confirm.Size()
+		n += 2 + l + sovRpc(uint64(l))
+	}
+	if m.Debug {
+		n += 3
+	}
+	if m.Cascade {
+		n += 3
+	}
+	if m.DensityMap {
+		n += 3
+	}
+	return n
+}
+
+func (m *Confirm) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MinFrames != 0 {
+		n += 1 + sovRpc(uint64(m.MinFrames))
+	}
+	if m.Frames != 0 {
+		n += 1 + sovRpc(uint64(m.Frames))
+	}
+	if len(m.Labels) > 0 {
+		for _, s := range m.Labels {
+			l = len(s)
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Smooth) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Method)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Alpha != 0 {
+		n += 5
+	}
+	if m.Iou != 0 {
+		n += 5
+	}
+	if m.MaxMissed != 0 {
+		n += 1 + sovRpc(uint64(m.MaxMissed))
+	}
+	return n
+}
+
+func (m *Preprocess) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Clahe {
+		n += 2
+	}
+	if m.ClipLimit != 0 {
+		n += 5
+	}
+	if m.Tiles != 0 {
+		n += 1 + sovRpc(uint64(m.Tiles))
+	}
+	if m.Gamma != 0 {
+		n += 5
+	}
+	if m.MaxBrightness != 0 {
+		n += 5
+	}
+	return n
+}
+
+func (m *InputTensor) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		n += 1 + sovRpc(uint64(len(m.Values)*4)) + len(m.Values)*4
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func (m *DetectRegion) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Top != 0 {
+		n += 5
+	}
+	if m.Left != 0 {
+		n += 5
+	}
+	if m.Bottom != 0 {
+		n += 5
+	}
+	if m.Right != 0 {
+		n += 5
+	}
+	if len(m.Detect) > 0 {
+		for k, v := range m.Detect {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovRpc(uint64(len(k))) + 1 + 4
+			n += mapEntrySize + 1 + sovRpc(uint64(mapEntrySize))
+		}
+	}
+	if m.Covers {
+		n += 2
+	}
+	return n
+}
+
+func (m *Detection) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Top != 0 {
+		n += 5
+	}
+	if m.Left != 0 {
+		n += 5
+	}
+	if m.Bottom != 0 {
+		n += 5
+	}
+	if m.Right != 0 {
+		n += 5
+	}
+	l = len(m.Label)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Confidence != 0 {
+		n += 5
+	}
+	if m.Track != 0 {
+		n += 1 + sovRpc(uint64(m.Track))
+	}
+	if m.Raw != nil {
+		l = m.Raw.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Stage)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Children) > 0 {
+		for _, e := range m.Children {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Attribute) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Confidence != 0 {
+		n += 5
+	}
+	return n
+}
+
+func (m *Box) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Top != 0 {
+		n += 5
+	}
+	if m.Left != 0 {
+		n += 5
+	}
+	if m.Bottom != 0 {
+		n += 5
+	}
+	if m.Right != 0 {
+		n += 5
+	}
+	return n
+}
+
+func (m *DetectResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Detections) > 0 {
+		for _, e := range m.Detections {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Outputs) > 0 {
+		for _, e := range m.Outputs {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if m.Quality != nil {
+		l = m.Quality.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Degraded {
+		n += 2
+	}
+	l = len(m.FallbackDetector)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.RetryAfterMs != 0 {
+		n += 1 + sovRpc(uint64(m.RetryAfterMs))
+	}
+	if m.Signature != nil {
+		l = m.Signature.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Debug)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Density != nil {
+		l = m.Density.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func (m *Signature) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Algorithm)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.KeyId)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.SignedAt != 0 {
+		n += 1 + sovRpc(uint64(m.SignedAt))
+	}
+	l = len(m.ImageSha256)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func (m *SignedDetections) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Detections) > 0 {
+		for _, e := range m.Detections {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	l = len(m.Algorithm)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.KeyId)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.SignedAt != 0 {
+		n += 1 + sovRpc(uint64(m.SignedAt))
+	}
+	l = len(m.ImageSha256)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func (m *CascadeResult) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Stage)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Parent != 0 {
+		n += 1 + sovRpc(uint64(m.Parent))
+	}
+	if len(m.Detections) > 0 {
+		for _, e := range m.Detections {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Done {
+		n += 2
+	}
+	l = len(m.ParentStage)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Path) > 0 {
+		l = 0
+		for _, e := range m.Path {
+			l += sovRpc(uint64(e))
+		}
+		n += 1 + sovRpc(uint64(l)) + l
+	}
+	if len(m.Objects) > 0 {
+		for _, e := range m.Objects {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DetectBoxesRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.DetectorName)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.File)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.ImageUrl)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Boxes) > 0 {
+		for _, e := range m.Boxes {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if m.Pixels {
+		n += 2
+	}
+	if m.Padding != 0 {
+		n += 5
+	}
+	if len(m.Detect) > 0 {
+		for k, v := range m.Detect {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovRpc(uint64(len(k))) + 1 + 4
+			n += mapEntrySize + 1 + sovRpc(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.Language)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Ignore) > 0 {
+		for _, s := range m.Ignore {
+			l = len(s)
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if m.MaxDetections != 0 {
+		n += 1 + sovRpc(uint64(m.MaxDetections))
+	}
+	return n
+}
+
+func (m *BoxResult) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Index != 0 {
+		n += 1 + sovRpc(uint64(m.Index))
+	}
+	if m.Box != nil {
+		l = m.Box.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Detections) > 0 {
+		for _, e := range m.Detections {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func (m *DetectBoxesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Results) > 0 {
+		for _, e := range m.Results {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DiffRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Before)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.After)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.BeforeUrl)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.AfterUrl)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Threshold != 0 {
+		n += 1 + sovRpc(uint64(m.Threshold))
+	}
+	if m.MinArea != 0 {
+		n += 5
+	}
+	if m.Width != 0 {
+		n += 1 + sovRpc(uint64(m.Width))
+	}
+	return n
+}
+
+func (m *ChangedRegion) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Box != nil {
+		l = m.Box.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Score != 0 {
+		n += 5
+	}
+	return n
+}
+
+func (m *DiffResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Regions) > 0 {
+		for _, e := range m.Regions {
+			l = e.Size()
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if m.Score != 0 {
+		n += 5
+	}
+	return n
+}
+
+func (m *PreprocessRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.DetectorName)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.File)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.ImageUrl)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Format)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Letterbox {
+		n += 2
+	}
+	if m.Preprocess != nil {
+		l = m.Preprocess.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Rotate != 0 {
+		n += 1 + sovRpc(uint64(m.Rotate))
+	}
+	l = len(m.Flip)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func (m *PreprocessResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Format)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Width != 0 {
+		n += 1 + sovRpc(uint64(m.Width))
+	}
+	if m.Height != 0 {
+		n += 1 + sovRpc(uint64(m.Height))
+	}
+	if m.Channels != 0 {
+		n += 1 + sovRpc(uint64(m.Channels))
+	}
+	if m.Input != nil {
+		l = m.Input.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Shape) > 0 {
+		l = 0
+		for _, e := range m.Shape {
+			l += sovRpc(uint64(e))
+		}
+		n += 1 + sovRpc(uint64(l)) + l
+	}
+	if m.Content != nil {
+		l = m.Content.Size()
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func (m *Quality) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Brightness != 0 {
+		n += 5
+	}
+	if m.Overexposed != 0 {
+		n += 5
+	}
+	if m.Sharpness != 0 {
+		n += 5
+	}
+	if len(m.Problems) > 0 {
+		for _, s := range m.Problems {
+			l = len(s)
+			n += 1 + l + sovRpc(uint64(l))
+		}
+	}
+	if m.Skipped {
+		n += 2
+	}
+	return n
+}
+
+func (m *Density) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Label)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if m.Count != 0 {
+		n += 5
+	}
+	if len(m.Zones) > 0 {
+		for k, v := range m.Zones {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovRpc(uint64(len(k))) + 1 + 4
+			n += mapEntrySize + 1 + sovRpc(uint64(mapEntrySize))
+		}
+	}
+	if m.Width != 0 {
+		n += 1 + sovRpc(uint64(m.Width))
+	}
+	if m.Height != 0 {
+		n += 1 + sovRpc(uint64(m.Height))
+	}
+	if len(m.Values) > 0 {
+		n += 1 + sovRpc(uint64(len(m.Values)*4)) + len(m.Values)*4
+	}
+	return n
+}
+
+func (m *OutputTensor) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	if len(m.Shape) > 0 {
+		l = 0
+		for _, e := range m.Shape {
+			l += sovRpc(uint64(e))
+		}
+		n += 1 + sovRpc(uint64(l)) + l
+	}
+	if len(m.Values) > 0 {
+		n += 1 + sovRpc(uint64(len(m.Values)*4)) + len(m.Values)*4
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovRpc(uint64(l))
+	}
+	return n
+}
+
+func sovRpc(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozRpc(x uint64) (n int) {
+	return sovRpc(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *GetDetectorsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForDetectors := "[]*Detector{"
+	for _, f := range this.Detectors {
+		repeatedStringForDetectors += strings.Replace(f.String(), "Detector", "Detector", 1) + ","
+	}
+	repeatedStringForDetectors += "}"
+	s := strings.Join([]string{`&GetDetectorsResponse{`,
+		`Detectors:` + repeatedStringForDetectors + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Detector) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Detector{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`Model:` + fmt.Sprintf("%v", this.Model) + `,`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`Width:` + fmt.Sprintf("%v", this.Width) + `,`,
+		`Height:` + fmt.Sprintf("%v", this.Height) + `,`,
+		`Channels:` + fmt.Sprintf("%v", this.Channels) + `,`,
+		`Languages:` + fmt.Sprintf("%v", this.Languages) + `,`,
+		`Inputs:` + fmt.Sprintf("%v", this.Inputs) + `,`,
+		`Input:` + strings.Replace(this.Input.String(), "InputSpec", "InputSpec", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *InputSpec) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&InputSpec{`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`Layout:` + fmt.Sprintf("%v", this.Layout) + `,`,
+		`Mean:` + fmt.Sprintf("%v", this.Mean) + `,`,
+		`Scale:` + fmt.Sprintf("%v", this.Scale) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DetectRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRegions := "[]*DetectRegion{"
+	for _, f := range this.Regions {
+		repeatedStringForRegions += strings.Replace(f.String(), "DetectRegion", "DetectRegion", 1) + ","
+	}
+	repeatedStringForRegions += "}"
+	keysForDetect := make([]string, 0, len(this.Detect))
+	for k, _ := range this.Detect {
+		keysForDetect = append(keysForDetect, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
+	mapStringForDetect := "map[string]float32{"
+	for _, k := range keysForDetect {
+		mapStringForDetect += fmt.Sprintf("%v: %v,", k, this.Detect[k])
+	}
+	mapStringForDetect += "}"
+	keysForInputs := make([]string, 0, len(this.Inputs))
+	for k, _ := range this.Inputs {
+		keysForInputs = append(keysForInputs, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForInputs)
+	mapStringForInputs := "map[string]*InputTensor{"
+	for _, k := range keysForInputs {
+		mapStringForInputs += fmt.Sprintf("%v: %v,", k, this.Inputs[k])
+	}
+	mapStringForInputs += "}"
+	s := strings.Join([]string{`&DetectRequest{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`DetectorName:` + fmt.Sprintf("%v", this.DetectorName) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`File:` + fmt.Sprintf("%v", this.File) + `,`,
+		`Detect:` + mapStringForDetect + `,`,
+		`Regions:` + repeatedStringForRegions + `,`,
+		`Language:` + fmt.Sprintf("%v", this.Language) + `,`,
+		`Ignore:` + fmt.Sprintf("%v", this.Ignore) + `,`,
+		`ImageUrl:` + fmt.Sprintf("%v", this.ImageUrl) + `,`,
+		`Inputs:` + mapStringForInputs + `,`,
+		`RawOutputs:` + fmt.Sprintf("%v", this.RawOutputs) + `,`,
+		`Preprocess:` + strings.Replace(this.Preprocess.String(), "Preprocess", "Preprocess", 1) + `,`,
+		`Rotate:` + fmt.Sprintf("%v", this.Rotate) + `,`,
+		`Flip:` + fmt.Sprintf("%v", this.Flip) + `,`,
+		`Smooth:` + strings.Replace(this.Smooth.String(), "Smooth", "Smooth", 1) + `,`,
+		`Confirm:` + strings.Replace(this.Confirm.String(), "Confirm", "Confirm", 1) + `,`,
+		`Debug:` + fmt.Sprintf("%v", this.Debug) + `,`,
+		`Cascade:` + fmt.Sprintf("%v", this.Cascade) + `,`,
+		`DensityMap:` + fmt.Sprintf("%v", this.DensityMap) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Confirm) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Confirm{`,
+		`MinFrames:` + fmt.Sprintf("%v", this.MinFrames) + `,`,
+		`Frames:` + fmt.Sprintf("%v", this.Frames) + `,`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Smooth) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Smooth{`,
+		`Method:` + fmt.Sprintf("%v", this.Method) + `,`,
+		`Alpha:` + fmt.Sprintf("%v", this.Alpha) + `,`,
+		`Iou:` + fmt.Sprintf("%v", this.Iou) + `,`,
+		`MaxMissed:` + fmt.Sprintf("%v", this.MaxMissed) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Preprocess) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Preprocess{`,
+		`Clahe:` + fmt.Sprintf("%v", this.Clahe) + `,`,
+		`ClipLimit:` + fmt.Sprintf("%v", this.ClipLimit) + `,`,
+		`Tiles:` + fmt.Sprintf("%v", this.Tiles) + `,`,
+		`Gamma:` + fmt.Sprintf("%v", this.Gamma) + `,`,
+		`MaxBrightness:` + fmt.Sprintf("%v", this.MaxBrightness) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *InputTensor) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&InputTensor{`,
+		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DetectRegion) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForDetect := make([]string, 0, len(this.Detect))
+	for k, _ := range this.Detect {
+		keysForDetect = append(keysForDetect, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
+	mapStringForDetect := "map[string]float32{"
+	for _, k := range keysForDetect {
+		mapStringForDetect += fmt.Sprintf("%v: %v,", k, this.Detect[k])
+	}
+	mapStringForDetect += "}"
+	s := strings.Join([]string{`&DetectRegion{`,
+		`Top:` + fmt.Sprintf("%v", this.Top) + `,`,
+		`Left:` + fmt.Sprintf("%v", this.Left) + `,`,
+		`Bottom:` + fmt.Sprintf("%v", this.Bottom) + `,`,
+		`Right:` + fmt.Sprintf("%v", this.Right) + `,`,
+		`Detect:` + mapStringForDetect + `,`,
+		`Covers:` + fmt.Sprintf("%v", this.Covers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Detection) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForChildren := "[]*Detection{"
+	for _, f := range this.Children {
+		repeatedStringForChildren += strings.Replace(f.String(), "Detection", "Detection", 1) + ","
+	}
+	repeatedStringForChildren += "}"
+	repeatedStringForAttributes := "[]*Attribute{"
+	for _, f := range this.Attributes {
+		repeatedStringForAttributes += strings.Replace(f.String(), "Attribute", "Attribute", 1) + ","
+	}
+	repeatedStringForAttributes += "}"
+	s := strings.Join([]string{`&Detection{`,
+		`Top:` + fmt.Sprintf("%v", this.Top) + `,`,
+		`Left:` + fmt.Sprintf("%v", this.Left) + `,`,
+		`Bottom:` + fmt.Sprintf("%v", this.Bottom) + `,`,
+		`Right:` + fmt.Sprintf("%v", this.Right) + `,`,
+		`Label:` + fmt.Sprintf("%v", this.Label) + `,`,
+		`Confidence:` + fmt.Sprintf("%v", this.Confidence) + `,`,
+		`Track:` + fmt.Sprintf("%v", this.Track) + `,`,
+		`Raw:` + strings.Replace(this.Raw.String(), "Box", "Box", 1) + `,`,
+		`Stage:` + fmt.Sprintf("%v", this.Stage) + `,`,
+		`Children:` + repeatedStringForChildren + `,`,
+		`Attributes:` + repeatedStringForAttributes + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Attribute) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Attribute{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`Confidence:` + fmt.Sprintf("%v", this.Confidence) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Box) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Box{`,
+		`Top:` + fmt.Sprintf("%v", this.Top) + `,`,
+		`Left:` + fmt.Sprintf("%v", this.Left) + `,`,
+		`Bottom:` + fmt.Sprintf("%v", this.Bottom) + `,`,
+		`Right:` + fmt.Sprintf("%v", this.Right) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DetectResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForDetections := "[]*Detection{"
+	for _, f := range this.Detections {
+		repeatedStringForDetections += strings.Replace(f.String(), "Detection", "Detection", 1) + ","
+	}
+	repeatedStringForDetections += "}"
+	repeatedStringForOutputs := "[]*OutputTensor{"
+	for _, f := range this.Outputs {
+		repeatedStringForOutputs += strings.Replace(f.String(), "OutputTensor", "OutputTensor", 1) + ","
+	}
+	repeatedStringForOutputs += "}"
+	s := strings.Join([]string{`&DetectResponse{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Detections:` + repeatedStringForDetections + `,`,
+		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
+		`Outputs:` + repeatedStringForOutputs + `,`,
+		`Quality:` + strings.Replace(this.Quality.String(), "Quality", "Quality", 1) + `,`,
+		`Degraded:` + fmt.Sprintf("%v", this.Degraded) + `,`,
+		`FallbackDetector:` + fmt.Sprintf("%v", this.FallbackDetector) + `,`,
+		`RetryAfterMs:` + fmt.Sprintf("%v", this.RetryAfterMs) + `,`,
+		`Signature:` + strings.Replace(this.Signature.String(), "Signature", "Signature", 1) + `,`,
+		`Debug:` + fmt.Sprintf("%v", this.Debug) + `,`,
+		`Density:` + strings.Replace(this.Density.String(), "Density", "Density", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Signature) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Signature{`,
+		`Algorithm:` + fmt.Sprintf("%v", this.Algorithm) + `,`,
+		`KeyId:` + fmt.Sprintf("%v", this.KeyId) + `,`,
+		`SignedAt:` + fmt.Sprintf("%v", this.SignedAt) + `,`,
+		`ImageSha256:` + fmt.Sprintf("%v", this.ImageSha256) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SignedDetections) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForDetections := "[]*Detection{"
+	for _, f := range this.Detections {
+		repeatedStringForDetections += strings.Replace(f.String(), "Detection", "Detection", 1) + ","
+	}
+	repeatedStringForDetections += "}"
+	s := strings.Join([]string{`&SignedDetections{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Detections:` + repeatedStringForDetections + `,`,
+		`Algorithm:` + fmt.Sprintf("%v", this.Algorithm) + `,`,
+		`KeyId:` + fmt.Sprintf("%v", this.KeyId) + `,`,
+		`SignedAt:` + fmt.Sprintf("%v", this.SignedAt) + `,`,
+		`ImageSha256:` + fmt.Sprintf("%v", this.ImageSha256) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *CascadeResult) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForDetections := "[]*Detection{"
+	for _, f := range this.Detections {
+		repeatedStringForDetections += strings.Replace(f.String(), "Detection", "Detection", 1) + ","
+	}
+	repeatedStringForDetections += "}"
+	repeatedStringForObjects := "[]*Detection{"
+	for _, f := range this.Objects {
+		repeatedStringForObjects += strings.Replace(f.String(), "Detection", "Detection", 1) + ","
+	}
+	repeatedStringForObjects += "}"
+	s := strings.Join([]string{`&CascadeResult{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Stage:` + fmt.Sprintf("%v", this.Stage) + `,`,
+		`Parent:` + fmt.Sprintf("%v", this.Parent) + `,`,
+		`Detections:` + repeatedStringForDetections + `,`,
+		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
+		`Done:` + fmt.Sprintf("%v", this.Done) + `,`,
+		`ParentStage:` + fmt.Sprintf("%v", this.ParentStage) + `,`,
+		`Path:` + fmt.Sprintf("%v", this.Path) + `,`,
+		`Objects:` + repeatedStringForObjects + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DetectBoxesRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForBoxes := "[]*Box{"
+	for _, f := range this.Boxes {
+		repeatedStringForBoxes += strings.Replace(f.String(), "Box", "Box", 1) + ","
+	}
+	repeatedStringForBoxes += "}"
+	keysForDetect := make([]string, 0, len(this.Detect))
+	for k, _ := range this.Detect {
+		keysForDetect = append(keysForDetect, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForDetect)
+	mapStringForDetect := "map[string]float32{"
+	for _, k := range keysForDetect {
+		mapStringForDetect += fmt.Sprintf("%v: %v,", k, this.Detect[k])
+	}
+	mapStringForDetect += "}"
+	s := strings.Join([]string{`&DetectBoxesRequest{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`DetectorName:` + fmt.Sprintf("%v", this.DetectorName) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`File:` + fmt.Sprintf("%v", this.File) + `,`,
+		`ImageUrl:` + fmt.Sprintf("%v", this.ImageUrl) + `,`,
+		`Boxes:` + repeatedStringForBoxes + `,`,
+		`Pixels:` + fmt.Sprintf("%v", this.Pixels) + `,`,
+		`Padding:` + fmt.Sprintf("%v", this.Padding) + `,`,
+		`Detect:` + mapStringForDetect + `,`,
+		`Language:` + fmt.Sprintf("%v", this.Language) + `,`,
+		`Ignore:` + fmt.Sprintf("%v", this.Ignore) + `,`,
+		`MaxDetections:` + fmt.Sprintf("%v", this.MaxDetections) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *BoxResult) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForDetections := "[]*Detection{"
+	for _, f := range this.Detections {
+		repeatedStringForDetections += strings.Replace(f.String(), "Detection", "Detection", 1) + ","
+	}
+	repeatedStringForDetections += "}"
+	s := strings.Join([]string{`&BoxResult{`,
+		`Index:` + fmt.Sprintf("%v", this.Index) + `,`,
+		`Box:` + strings.Replace(this.Box.String(), "Box", "Box", 1) + `,`,
+		`Detections:` + repeatedStringForDetections + `,`,
+		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DetectBoxesResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForResults := "[]*BoxResult{"
+	for _, f := range this.Results {
+		repeatedStringForResults += strings.Replace(f.String(), "BoxResult", "BoxResult", 1) + ","
+	}
+	repeatedStringForResults += "}"
+	s := strings.Join([]string{`&DetectBoxesResponse{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Results:` + repeatedStringForResults + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DiffRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DiffRequest{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Before:` + fmt.Sprintf("%v", this.Before) + `,`,
+		`After:` + fmt.Sprintf("%v", this.After) + `,`,
+		`BeforeUrl:` + fmt.Sprintf("%v", this.BeforeUrl) + `,`,
+		`AfterUrl:` + fmt.Sprintf("%v", this.AfterUrl) + `,`,
+		`Threshold:` + fmt.Sprintf("%v", this.Threshold) + `,`,
+		`MinArea:` + fmt.Sprintf("%v", this.MinArea) + `,`,
+		`Width:` + fmt.Sprintf("%v", this.Width) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ChangedRegion) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ChangedRegion{`,
+		`Box:` + strings.Replace(this.Box.String(), "Box", "Box", 1) + `,`,
+		`Score:` + fmt.Sprintf("%v", this.Score) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DiffResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRegions := "[]*ChangedRegion{"
+	for _, f := range this.Regions {
+		repeatedStringForRegions += strings.Replace(f.String(), "ChangedRegion", "ChangedRegion", 1) + ","
+	}
+	repeatedStringForRegions += "}"
+	s := strings.Join([]string{`&DiffResponse{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Regions:` + repeatedStringForRegions + `,`,
+		`Score:` + fmt.Sprintf("%v", this.Score) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PreprocessRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PreprocessRequest{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`DetectorName:` + fmt.Sprintf("%v", this.DetectorName) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`File:` + fmt.Sprintf("%v", this.File) + `,`,
+		`ImageUrl:` + fmt.Sprintf("%v", this.ImageUrl) + `,`,
+		`Format:` + fmt.Sprintf("%v", this.Format) + `,`,
+		`Letterbox:` + fmt.Sprintf("%v", this.Letterbox) + `,`,
+		`Preprocess:` + strings.Replace(this.Preprocess.String(), "Preprocess", "Preprocess", 1) + `,`,
+		`Rotate:` + fmt.Sprintf("%v", this.Rotate) + `,`,
+		`Flip:` + fmt.Sprintf("%v", this.Flip) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PreprocessResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PreprocessResponse{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Format:` + fmt.Sprintf("%v", this.Format) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`Width:` + fmt.Sprintf("%v", this.Width) + `,`,
+		`Height:` + fmt.Sprintf("%v", this.Height) + `,`,
+		`Channels:` + fmt.Sprintf("%v", this.Channels) + `,`,
+		`Input:` + strings.Replace(this.Input.String(), "InputSpec", "InputSpec", 1) + `,`,
+		`Shape:` + fmt.Sprintf("%v", this.Shape) + `,`,
+		`Content:` + strings.Replace(this.Content.String(), "Box", "Box", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Quality) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Quality{`,
+		`Brightness:` + fmt.Sprintf("%v", this.Brightness) + `,`,
+		`Overexposed:` + fmt.Sprintf("%v", this.Overexposed) + `,`,
+		`Sharpness:` + fmt.Sprintf("%v", this.Sharpness) + `,`,
+		`Problems:` + fmt.Sprintf("%v", this.Problems) + `,`,
+		`Skipped:` + fmt.Sprintf("%v", this.Skipped) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Density) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForZones := make([]string, 0, len(this.Zones))
+	for k, _ := range this.Zones {
+		keysForZones = append(keysForZones, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForZones)
+	mapStringForZones := "map[string]float32{"
+	for _, k := range keysForZones {
+		mapStringForZones += fmt.Sprintf("%v: %v,", k, this.Zones[k])
+	}
+	mapStringForZones += "}"
+	s := strings.Join([]string{`&Density{`,
+		`Label:` + fmt.Sprintf("%v", this.Label) + `,`,
+		`Count:` + fmt.Sprintf("%v", this.Count) + `,`,
+		`Zones:` + mapStringForZones + `,`,
+		`Width:` + fmt.Sprintf("%v", this.Width) + `,`,
+		`Height:` + fmt.Sprintf("%v", this.Height) + `,`,
+		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *OutputTensor) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&OutputTensor{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Type:` + fmt.Sprintf("%v", this.Type) + `,`,
+		`Shape:` + fmt.Sprintf("%v", this.Shape) + `,`,
+		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringRpc(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *GetDetectorsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetDetectorsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetDetectorsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detectors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Detectors = append(m.Detectors, &Detector{})
+			if err := m.Detectors[len(m.Detectors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Detector) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Detector: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Detector: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Model", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Model = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Width", wireType)
+			}
+			m.Width = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Width |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Channels", wireType)
+			}
+			m.Channels = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Channels |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Languages", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Languages = append(m.Languages, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Inputs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Inputs = append(m.Inputs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Input == nil {
+				m.Input = &InputSpec{}
+			}
+			if err := m.Input.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *InputSpec) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: InputSpec: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: InputSpec: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Layout", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Layout = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType == 5 {
+				var v uint32
+				if (iNdEx + 4) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+				iNdEx += 4
+				v2 := float32(math.Float32frombits(v))
+				m.Mean = append(m.Mean, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthRpc
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthRpc
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 4
+				if elementCount != 0 && len(m.Mean) == 0 {
+					m.Mean = make([]float32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					if (iNdEx + 4) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+					iNdEx += 4
+					v2 := float32(math.Float32frombits(v))
+					m.Mean = append(m.Mean, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mean", wireType)
+			}
+		case 4:
+			if wireType == 5 {
+				var v uint32
+				if (iNdEx + 4) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+				iNdEx += 4
+				v2 := float32(math.Float32frombits(v))
+				m.Scale = append(m.Scale, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthRpc
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthRpc
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 4
+				if elementCount != 0 && len(m.Scale) == 0 {
+					m.Scale = make([]float32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					if (iNdEx + 4) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+					iNdEx += 4
+					v2 := float32(math.Float32frombits(v))
+					m.Scale = append(m.Scale, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scale", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DetectRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DetectRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DetectRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DetectorName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DetectorName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.File = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detect", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Detect == nil {
+				m.Detect = make(map[string]float32)
+			}
+			var mapkey string
+			var mapvalue float32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRpc
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapvaluetemp uint32
+					if (iNdEx + 4) > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvaluetemp = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+					iNdEx += 4
+					mapvalue = math.Float32frombits(mapvaluetemp)
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipRpc(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Detect[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Regions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Regions = append(m.Regions, &DetectRegion{})
+			if err := m.Regions[len(m.Regions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Language", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Language = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ignore", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ignore = append(m.Ignore, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ImageUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ImageUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Inputs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Inputs == nil {
+				m.Inputs = make(map[string]*InputTensor)
+			}
+			var mapkey string
+			var mapvalue *InputTensor
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRpc
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapmsglen int
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRpc
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapmsglen |= int(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					if mapmsglen < 0 {
+						return ErrInvalidLengthRpc
+					}
+					postmsgIndex := iNdEx + mapmsglen
+					if postmsgIndex < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if postmsgIndex > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = &InputTensor{}
+					if err := mapvalue.Unmarshal(dAtA[iNdEx:postmsgIndex]); err != nil {
+						return err
+					}
+					iNdEx = postmsgIndex
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipRpc(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Inputs[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RawOutputs", wireType)
+			}
+			m.RawOutputs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RawOutputs |= RawOutputs(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Preprocess", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Preprocess == nil {
+				m.Preprocess = &Preprocess{}
+			}
+			if err := m.Preprocess.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rotate", wireType)
+			}
+			m.Rotate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rotate |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Flip", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Flip = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Smooth", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Smooth == nil {
+				m.Smooth = &Smooth{}
+			}
+			if err := m.Smooth.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Confirm", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Confirm == nil {
+				m.Confirm = &Confirm{}
+			}
+			if err := m.Confirm.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Debug", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Debug = bool(v != 0)
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cascade", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Cascade = bool(v != 0)
+		case 19:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DensityMap", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DensityMap = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Confirm) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Confirm: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Confirm: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinFrames", wireType)
+			}
+			m.MinFrames = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinFrames |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Frames", wireType)
+			}
+			m.Frames = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Frames |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Smooth) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Smooth: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Smooth: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Method", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Method = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Alpha", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Alpha = float32(math.Float32frombits(v))
+		case 3:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Iou", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Iou = float32(math.Float32frombits(v))
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxMissed", wireType)
+			}
+			m.MaxMissed = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxMissed |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Preprocess) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Preprocess: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Preprocess: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Clahe", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Clahe = bool(v != 0)
+		case 2:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClipLimit", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.ClipLimit = float32(math.Float32frombits(v))
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tiles", wireType)
+			}
+			m.Tiles = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Tiles |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Gamma", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Gamma = float32(math.Float32frombits(v))
+		case 5:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBrightness", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.MaxBrightness = float32(math.Float32frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *InputTensor) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: InputTensor: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: InputTensor: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType == 5 {
+				var v uint32
+				if (iNdEx + 4) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+				iNdEx += 4
+				v2 := float32(math.Float32frombits(v))
+				m.Values = append(m.Values, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthRpc
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthRpc
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 4
+				if elementCount != 0 && len(m.Values) == 0 {
+					m.Values = make([]float32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					if (iNdEx + 4) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+					iNdEx += 4
+					v2 := float32(math.Float32frombits(v))
+					m.Values = append(m.Values, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DetectRegion) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DetectRegion: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DetectRegion: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Top", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Top = float32(math.Float32frombits(v))
+		case 2:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Left = float32(math.Float32frombits(v))
+		case 3:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bottom", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Bottom = float32(math.Float32frombits(v))
+		case 4:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Right = float32(math.Float32frombits(v))
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detect", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Detect == nil {
+				m.Detect = make(map[string]float32)
+			}
+			var mapkey string
+			var mapvalue float32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRpc
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapvaluetemp uint32
+					if (iNdEx + 4) > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvaluetemp = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+					iNdEx += 4
+					mapvalue = math.Float32frombits(mapvaluetemp)
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipRpc(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Detect[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Covers", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Covers = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Detection) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Detection: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Detection: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Top", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Top = float32(math.Float32frombits(v))
+		case 2:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Left = float32(math.Float32frombits(v))
+		case 3:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bottom", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Bottom = float32(math.Float32frombits(v))
+		case 4:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Right = float32(math.Float32frombits(v))
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Label = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Confidence", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Confidence = float32(math.Float32frombits(v))
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Track", wireType)
+			}
+			m.Track = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Track |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Raw", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Raw == nil {
+				m.Raw = &Box{}
+			}
+			if err := m.Raw.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Children", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Children = append(m.Children, &Detection{})
+			if err := m.Children[len(m.Children)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attributes = append(m.Attributes, &Attribute{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Attribute) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Attribute: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Attribute: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Confidence", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Confidence = float32(math.Float32frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Box) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Box: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Box: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Top", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Top = float32(math.Float32frombits(v))
+		case 2:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Left = float32(math.Float32frombits(v))
+		case 3:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bottom", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Bottom = float32(math.Float32frombits(v))
+		case 4:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Right = float32(math.Float32frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DetectResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DetectResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DetectResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detections", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Detections = append(m.Detections, &Detection{})
+			if err := m.Detections[len(m.Detections)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Outputs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Outputs = append(m.Outputs, &OutputTensor{})
+			if err := m.Outputs[len(m.Outputs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Quality", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Quality == nil {
+				m.Quality = &Quality{}
+			}
+			if err := m.Quality.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Degraded", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Degraded = bool(v != 0)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FallbackDetector", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FallbackDetector = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetryAfterMs", wireType)
+			}
+			m.RetryAfterMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RetryAfterMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Signature == nil {
+				m.Signature = &Signature{}
+			}
+			if err := m.Signature.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Debug", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Debug = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Density", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Density == nil {
+				m.Density = &Density{}
+			}
+			if err := m.Density.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Signature) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Signature: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Signature: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Algorithm", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Algorithm = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KeyId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SignedAt", wireType)
+			}
+			m.SignedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SignedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ImageSha256", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ImageSha256 = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SignedDetections) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SignedDetections: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SignedDetections: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detections", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Detections = append(m.Detections, &Detection{})
+			if err := m.Detections[len(m.Detections)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Algorithm", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Algorithm = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KeyId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SignedAt", wireType)
+			}
+			m.SignedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SignedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ImageSha256", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ImageSha256 = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CascadeResult) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CascadeResult: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CascadeResult: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Parent", wireType)
+			}
+			m.Parent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Parent |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detections", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Detections = append(m.Detections, &Detection{})
+			if err := m.Detections[len(m.Detections)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Done", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Done = bool(v != 0)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ParentStage", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ParentStage = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Path = append(m.Path, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthRpc
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthRpc
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Path) == 0 {
+					m.Path = make([]int32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRpc
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Path = append(m.Path, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Path", wireType)
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Objects", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Objects = append(m.Objects, &Detection{})
+			if err := m.Objects[len(m.Objects)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DetectBoxesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DetectBoxesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DetectBoxesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DetectorName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DetectorName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.File = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ImageUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ImageUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Boxes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Boxes = append(m.Boxes, &Box{})
+			if err := m.Boxes[len(m.Boxes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pixels", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Pixels = bool(v != 0)
+		case 8:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Padding", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Padding = float32(math.Float32frombits(v))
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detect", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Detect == nil {
+				m.Detect = make(map[string]float32)
+			}
+			var mapkey string
+			var mapvalue float32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRpc
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapvaluetemp uint32
+					if (iNdEx + 4) > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvaluetemp = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+					iNdEx += 4
+					mapvalue = math.Float32frombits(mapvaluetemp)
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipRpc(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Detect[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Language", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Language = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ignore", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ignore = append(m.Ignore, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxDetections", wireType)
+			}
+			m.MaxDetections = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxDetections |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BoxResult) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BoxResult: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BoxResult: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Box", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Box == nil {
+				m.Box = &Box{}
+			}
+			if err := m.Box.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Detections", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Detections = append(m.Detections, &Detection{})
+			if err := m.Detections[len(m.Detections)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DetectBoxesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DetectBoxesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DetectBoxesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Results", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Results = append(m.Results, &BoxResult{})
+			if err := m.Results[len(m.Results)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DiffRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DiffRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DiffRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Before", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Before = append(m.Before[:0], dAtA[iNdEx:postIndex]...)
+			if m.Before == nil {
+				m.Before = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field After", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.After = append(m.After[:0], dAtA[iNdEx:postIndex]...)
+			if m.After == nil {
+				m.After = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BeforeUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BeforeUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AfterUrl", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AfterUrl = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Threshold", wireType)
+			}
+			m.Threshold = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Threshold |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinArea", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.MinArea = float32(math.Float32frombits(v))
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Width", wireType)
+			}
+			m.Width = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Width |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	mapStringForDetect += "}"
-	s := strings.Join([]string{`&DetectRegion{`,
-		`Top:` + fmt.Sprintf("%v", this.Top) + `,`,
-		`Left:` + fmt.Sprintf("%v", this.Left) + `,`,
-		`Bottom:` + fmt.Sprintf("%v", this.Bottom) + `,`,
-		`Right:` + fmt.Sprintf("%v", this.Right) + `,`,
-		`Detect:` + mapStringForDetect + `,`,
-		`Covers:` + fmt.Sprintf("%v", this.Covers) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Detection) String() string {
-	if this == nil {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	s := strings.Join([]string{`&Detection{`,
-		`Top:` + fmt.Sprintf("%v", this.Top) + `,`,
-		`Left:` + fmt.Sprintf("%v", this.Left) + `,`,
-		`Bottom:` + fmt.Sprintf("%v", this.Bottom) + `,`,
-		`Right:` + fmt.Sprintf("%v", this.Right) + `,`,
-		`Label:` + fmt.Sprintf("%v", this.Label) + `,`,
-		`Confidence:` + fmt.Sprintf("%v", this.Confidence) + `,`,
-		`}`,
-	}, "")
-	return s
+	return nil
 }
-func (this *DetectResponse) String() string {
-	if this == nil {
-		return "nil"
+func (m *ChangedRegion) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRpc
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ChangedRegion: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ChangedRegion: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Box", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Box == nil {
+				m.Box = &Box{}
+			}
+			if err := m.Box.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Score", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Score = float32(math.Float32frombits(v))
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRpc(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	s := strings.Join([]string{`&DetectResponse{`,
-		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
-		`Detections:` + strings.Replace(fmt.Sprintf("%v", this.Detections), "Detection", "Detection", 1) + `,`,
-		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringRpc(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *GetDetectorsResponse) Unmarshal(dAtA []byte) error {
+func (m *DiffResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1763,15 +13432,47 @@ func (m *GetDetectorsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetDetectorsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: DiffResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetDetectorsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DiffResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Detectors", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Regions", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1798,11 +13499,22 @@ func (m *GetDetectorsResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Detectors = append(m.Detectors, &Detector{})
-			if err := m.Detectors[len(m.Detectors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Regions = append(m.Regions, &ChangedRegion{})
+			if err := m.Regions[len(m.Regions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Score", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Score = float32(math.Float32frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRpc(dAtA[iNdEx:])
@@ -1827,7 +13539,7 @@ func (m *GetDetectorsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Detector) Unmarshal(dAtA []byte) error {
+func (m *PreprocessRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1837,28 +13549,60 @@ func (m *Detector) Unmarshal(dAtA []byte) error {
 			if shift >= 64 {
 				return ErrIntOverflowRpc
 			}
-			if iNdEx >= l {
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PreprocessRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PreprocessRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Detector: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Detector: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DetectorName", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1886,11 +13630,45 @@ func (m *Detector) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			m.DetectorName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1918,11 +13696,11 @@ func (m *Detector) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Type = string(dAtA[iNdEx:postIndex])
+			m.File = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Model", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ImageUrl", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1950,11 +13728,11 @@ func (m *Detector) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Model = string(dAtA[iNdEx:postIndex])
+			m.ImageUrl = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Format", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1982,13 +13760,13 @@ func (m *Detector) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Labels = append(m.Labels, string(dAtA[iNdEx:postIndex]))
+			m.Format = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 5:
+		case 7:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Width", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Letterbox", wireType)
 			}
-			m.Width = 0
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRpc
@@ -1998,16 +13776,17 @@ func (m *Detector) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Width |= int32(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			m.Letterbox = bool(v != 0)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Preprocess", wireType)
 			}
-			m.Height = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRpc
@@ -2017,16 +13796,33 @@ func (m *Detector) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Height |= int32(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Preprocess == nil {
+				m.Preprocess = &Preprocess{}
+			}
+			if err := m.Preprocess.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Channels", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Rotate", wireType)
 			}
-			m.Channels = 0
+			m.Rotate = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRpc
@@ -2036,11 +13832,43 @@ func (m *Detector) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Channels |= int32(b&0x7F) << shift
+				m.Rotate |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Flip", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Flip = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRpc(dAtA[iNdEx:])
@@ -2065,7 +13893,7 @@ func (m *Detector) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DetectRequest) Unmarshal(dAtA []byte) error {
+func (m *PreprocessResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2088,10 +13916,10 @@ func (m *DetectRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DetectRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: PreprocessResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DetectRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PreprocessResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -2128,7 +13956,7 @@ func (m *DetectRequest) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DetectorName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Format", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2156,7 +13984,7 @@ func (m *DetectRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DetectorName = string(dAtA[iNdEx:postIndex])
+			m.Format = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
@@ -2193,10 +14021,10 @@ func (m *DetectRequest) Unmarshal(dAtA []byte) error {
 			}
 			iNdEx = postIndex
 		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field File", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Width", wireType)
 			}
-			var stringLen uint64
+			m.Width = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRpc
@@ -2206,27 +14034,52 @@ func (m *DetectRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Width |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthRpc
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthRpc
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Channels", wireType)
 			}
-			m.File = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 5:
+			m.Channels = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Channels |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Detect", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Input", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2253,14 +14106,16 @@ func (m *DetectRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Detect == nil {
-				m.Detect = make(map[string]float32)
+			if m.Input == nil {
+				m.Input = &InputSpec{}
 			}
-			var mapkey string
-			var mapvalue float32
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
+			if err := m.Input.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType == 0 {
+				var v int32
 				for shift := uint(0); ; shift += 7 {
 					if shift >= 64 {
 						return ErrIntOverflowRpc
@@ -2270,69 +14125,73 @@ func (m *DetectRequest) Unmarshal(dAtA []byte) error {
 					}
 					b := dAtA[iNdEx]
 					iNdEx++
-					wire |= uint64(b&0x7F) << shift
+					v |= int32(b&0x7F) << shift
 					if b < 0x80 {
 						break
 					}
 				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowRpc
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthRpc
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthRpc
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
+				m.Shape = append(m.Shape, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
 					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapvaluetemp uint32
-					if (iNdEx + 4) > l {
+					if iNdEx >= l {
 						return io.ErrUnexpectedEOF
 					}
-					mapvaluetemp = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-					iNdEx += 4
-					mapvalue = math.Float32frombits(mapvaluetemp)
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipRpc(dAtA[iNdEx:])
-					if err != nil {
-						return err
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
 					}
-					if skippy < 0 {
-						return ErrInvalidLengthRpc
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthRpc
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthRpc
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
 					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Shape) == 0 {
+					m.Shape = make([]int32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRpc
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
 					}
-					iNdEx += skippy
+					m.Shape = append(m.Shape, v)
 				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shape", wireType)
 			}
-			m.Detect[mapkey] = mapvalue
-			iNdEx = postIndex
-		case 6:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Regions", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Content", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2359,8 +14218,10 @@ func (m *DetectRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Regions = append(m.Regions, &DetectRegion{})
-			if err := m.Regions[len(m.Regions)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Content == nil {
+				m.Content = &Box{}
+			}
+			if err := m.Content.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -2388,7 +14249,7 @@ func (m *DetectRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DetectRegion) Unmarshal(dAtA []byte) error {
+func (m *Quality) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2411,15 +14272,15 @@ func (m *DetectRegion) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DetectRegion: wiretype end group for non-group")
+			return fmt.Errorf("proto: Quality: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DetectRegion: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Quality: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Top", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Brightness", wireType)
 			}
 			var v uint32
 			if (iNdEx + 4) > l {
@@ -2427,10 +14288,10 @@ func (m *DetectRegion) Unmarshal(dAtA []byte) error {
 			}
 			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
 			iNdEx += 4
-			m.Top = float32(math.Float32frombits(v))
+			m.Brightness = float32(math.Float32frombits(v))
 		case 2:
 			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Overexposed", wireType)
 			}
 			var v uint32
 			if (iNdEx + 4) > l {
@@ -2438,10 +14299,10 @@ func (m *DetectRegion) Unmarshal(dAtA []byte) error {
 			}
 			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
 			iNdEx += 4
-			m.Left = float32(math.Float32frombits(v))
+			m.Overexposed = float32(math.Float32frombits(v))
 		case 3:
 			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bottom", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Sharpness", wireType)
 			}
 			var v uint32
 			if (iNdEx + 4) > l {
@@ -2449,23 +14310,12 @@ func (m *DetectRegion) Unmarshal(dAtA []byte) error {
 			}
 			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
 			iNdEx += 4
-			m.Bottom = float32(math.Float32frombits(v))
+			m.Sharpness = float32(math.Float32frombits(v))
 		case 4:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
-			}
-			var v uint32
-			if (iNdEx + 4) > l {
-				return io.ErrUnexpectedEOF
-			}
-			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-			iNdEx += 4
-			m.Right = float32(math.Float32frombits(v))
-		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Detect", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Problems", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRpc
@@ -2475,101 +14325,27 @@ func (m *DetectRegion) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthRpc
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthRpc
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Detect == nil {
-				m.Detect = make(map[string]float32)
-			}
-			var mapkey string
-			var mapvalue float32
-			for iNdEx < postIndex {
-				entryPreIndex := iNdEx
-				var wire uint64
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return ErrIntOverflowRpc
-					}
-					if iNdEx >= l {
-						return io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					wire |= uint64(b&0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				fieldNum := int32(wire >> 3)
-				if fieldNum == 1 {
-					var stringLenmapkey uint64
-					for shift := uint(0); ; shift += 7 {
-						if shift >= 64 {
-							return ErrIntOverflowRpc
-						}
-						if iNdEx >= l {
-							return io.ErrUnexpectedEOF
-						}
-						b := dAtA[iNdEx]
-						iNdEx++
-						stringLenmapkey |= uint64(b&0x7F) << shift
-						if b < 0x80 {
-							break
-						}
-					}
-					intStringLenmapkey := int(stringLenmapkey)
-					if intStringLenmapkey < 0 {
-						return ErrInvalidLengthRpc
-					}
-					postStringIndexmapkey := iNdEx + intStringLenmapkey
-					if postStringIndexmapkey < 0 {
-						return ErrInvalidLengthRpc
-					}
-					if postStringIndexmapkey > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
-					iNdEx = postStringIndexmapkey
-				} else if fieldNum == 2 {
-					var mapvaluetemp uint32
-					if (iNdEx + 4) > l {
-						return io.ErrUnexpectedEOF
-					}
-					mapvaluetemp = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-					iNdEx += 4
-					mapvalue = math.Float32frombits(mapvaluetemp)
-				} else {
-					iNdEx = entryPreIndex
-					skippy, err := skipRpc(dAtA[iNdEx:])
-					if err != nil {
-						return err
-					}
-					if skippy < 0 {
-						return ErrInvalidLengthRpc
-					}
-					if (iNdEx + skippy) > postIndex {
-						return io.ErrUnexpectedEOF
-					}
-					iNdEx += skippy
-				}
-			}
-			m.Detect[mapkey] = mapvalue
+			m.Problems = append(m.Problems, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
-		case 6:
+		case 5:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Covers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Skipped", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -2586,7 +14362,7 @@ func (m *DetectRegion) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.Covers = bool(v != 0)
+			m.Skipped = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRpc(dAtA[iNdEx:])
@@ -2611,7 +14387,7 @@ func (m *DetectRegion) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Detection) Unmarshal(dAtA []byte) error {
+func (m *Density) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2634,26 +14410,47 @@ func (m *Detection) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Detection: wiretype end group for non-group")
+			return fmt.Errorf("proto: Density: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Detection: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Density: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Top", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
 			}
-			var v uint32
-			if (iNdEx + 4) > l {
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-			iNdEx += 4
-			m.Top = float32(math.Float32frombits(v))
+			m.Label = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Left", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
 			}
 			var v uint32
 			if (iNdEx + 4) > l {
@@ -2661,34 +14458,137 @@ func (m *Detection) Unmarshal(dAtA []byte) error {
 			}
 			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
 			iNdEx += 4
-			m.Left = float32(math.Float32frombits(v))
+			m.Count = float32(math.Float32frombits(v))
 		case 3:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bottom", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Zones", wireType)
 			}
-			var v uint32
-			if (iNdEx + 4) > l {
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRpc
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRpc
+			}
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-			iNdEx += 4
-			m.Bottom = float32(math.Float32frombits(v))
+			if m.Zones == nil {
+				m.Zones = make(map[string]float32)
+			}
+			var mapkey string
+			var mapvalue float32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRpc
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var mapvaluetemp uint32
+					if (iNdEx + 4) > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvaluetemp = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+					iNdEx += 4
+					mapvalue = math.Float32frombits(mapvaluetemp)
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipRpc(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthRpc
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Zones[mapkey] = mapvalue
+			iNdEx = postIndex
 		case 4:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Right", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Width", wireType)
 			}
-			var v uint32
-			if (iNdEx + 4) > l {
-				return io.ErrUnexpectedEOF
+			m.Width = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRpc
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Width |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-			iNdEx += 4
-			m.Right = float32(math.Float32frombits(v))
 		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Label", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
 			}
-			var stringLen uint64
+			m.Height = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRpc
@@ -2698,35 +14598,65 @@ func (m *Detection) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Height |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthRpc
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthRpc
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Label = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 6:
-			if wireType != 5 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Confidence", wireType)
-			}
-			var v uint32
-			if (iNdEx + 4) > l {
-				return io.ErrUnexpectedEOF
+			if wireType == 5 {
+				var v uint32
+				if (iNdEx + 4) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+				iNdEx += 4
+				v2 := float32(math.Float32frombits(v))
+				m.Values = append(m.Values, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthRpc
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthRpc
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 4
+				if elementCount != 0 && len(m.Values) == 0 {
+					m.Values = make([]float32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					if (iNdEx + 4) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+					iNdEx += 4
+					v2 := float32(math.Float32frombits(v))
+					m.Values = append(m.Values, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
 			}
-			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
-			iNdEx += 4
-			m.Confidence = float32(math.Float32frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRpc(dAtA[iNdEx:])
@@ -2751,7 +14681,7 @@ func (m *Detection) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DetectResponse) Unmarshal(dAtA []byte) error {
+func (m *OutputTensor) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2774,15 +14704,15 @@ func (m *DetectResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DetectResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: OutputTensor: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DetectResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: OutputTensor: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -2810,13 +14740,13 @@ func (m *DetectResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Id = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Detections", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRpc
@@ -2826,31 +14756,159 @@ func (m *DetectResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthRpc
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthRpc
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Detections = append(m.Detections, &Detection{})
-			if err := m.Detections[len(m.Detections)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Type = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
+			if wireType == 0 {
+				var v int32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= int32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Shape = append(m.Shape, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthRpc
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthRpc
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Shape) == 0 {
+					m.Shape = make([]int32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v int32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRpc
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= int32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Shape = append(m.Shape, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shape", wireType)
+			}
+		case 4:
+			if wireType == 5 {
+				var v uint32
+				if (iNdEx + 4) > l {
+					return io.ErrUnexpectedEOF
+				}
+				v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+				iNdEx += 4
+				v2 := float32(math.Float32frombits(v))
+				m.Values = append(m.Values, v2)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRpc
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthRpc
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthRpc
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				elementCount = packedLen / 4
+				if elementCount != 0 && len(m.Values) == 0 {
+					m.Values = make([]float32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					if (iNdEx + 4) > l {
+						return io.ErrUnexpectedEOF
+					}
+					v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+					iNdEx += 4
+					v2 := float32(math.Float32frombits(v))
+					m.Values = append(m.Values, v2)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
 			}
-			var stringLen uint64
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowRpc
@@ -2860,23 +14918,25 @@ func (m *DetectResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthRpc
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthRpc
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Error = string(dAtA[iNdEx:postIndex])
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -2905,6 +14965,7 @@ func (m *DetectResponse) Unmarshal(dAtA []byte) error {
 func skipRpc(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -2936,10 +14997,8 @@ func skipRpc(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -2960,55 +15019,30 @@ func skipRpc(dAtA []byte) (n int, err error) {
 				return 0, ErrInvalidLengthRpc
 			}
 			iNdEx += length
-			if iNdEx < 0 {
-				return 0, ErrInvalidLengthRpc
-			}
-			return iNdEx, nil
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowRpc
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipRpc(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-				if iNdEx < 0 {
-					return 0, ErrInvalidLengthRpc
-				}
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupRpc
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthRpc
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthRpc = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowRpc   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthRpc        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowRpc          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupRpc = fmt.Errorf("proto: unexpected end of group")
 )
@@ -0,0 +1,193 @@
+package onvif
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const soapEnvelope = `<?xml version="1.0" encoding="UTF-8"?>
+<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" xmlns:a="http://www.w3.org/2005/08/addressing">
+<s:Header>%s</s:Header>
+<s:Body>%s</s:Body>
+</s:Envelope>`
+
+const securityHeader = `<Security s:mustUnderstand="1" xmlns="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-secext-1.0.xsd"><UsernameToken><Username>%s</Username><Password Type="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-username-token-profile-1.0#PasswordDigest">%s</Password><Nonce EncodingType="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-soap-message-security-1.0#Base64Binary">%s</Nonce><Created xmlns="http://docs.oasis-open.org/wss/2004/01/oasis-200401-wss-wssecurity-utility-1.0.xsd">%s</Created></UsernameToken></Security>`
+
+// Client talks to an ONVIF camera
+type Client struct {
+	xaddr    string
+	username string
+	password string
+	client   *http.Client
+
+	mediaAddr  string
+	eventsAddr string
+}
+
+// NewClient creates a client for the device service url. If address is just a host (or host:port)
+// the default device service path is used.
+func NewClient(address string, username string, password string) *Client {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address + "/onvif/device_service"
+	}
+	return &Client{
+		xaddr:    address,
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// call makes a SOAP request and decodes the response body into resp
+func (c *Client) call(ctx context.Context, addr string, action string, body string, resp interface{}) error {
+
+	var header string
+	if c.username != "" {
+		nonce := make([]byte, 16)
+		rand.Read(nonce)
+		created := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+		h := sha1.New()
+		h.Write(nonce)
+		h.Write([]byte(created))
+		h.Write([]byte(c.password))
+		header = fmt.Sprintf(securityHeader, xmlEscape(c.username), base64.StdEncoding.EncodeToString(h.Sum(nil)), base64.StdEncoding.EncodeToString(nonce), created)
+	}
+	if action != "" {
+		header += `<a:Action s:mustUnderstand="1">` + action + `</a:Action><a:To s:mustUnderstand="1">` + xmlEscape(addr) + `</a:To>`
+	}
+
+	req, err := http.NewRequest(http.MethodPost, addr, strings.NewReader(fmt.Sprintf(soapEnvelope, header, body)))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/soap+xml; charset=utf-8")
+
+	r, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if r.StatusCode != http.StatusOK {
+		var fault struct {
+			Reason string `xml:"Body>Fault>Reason>Text"`
+		}
+		xml.Unmarshal(data, &fault)
+		return fmt.Errorf("onvif request failed: %s %s", r.Status, fault.Reason)
+	}
+
+	return xml.Unmarshal(data, resp)
+
+}
+
+// capabilities gets the media and events service addresses
+func (c *Client) capabilities(ctx context.Context) error {
+	if c.mediaAddr != "" {
+		return nil
+	}
+	var resp struct {
+		Media  string `xml:"Body>GetCapabilitiesResponse>Capabilities>Media>XAddr"`
+		Events string `xml:"Body>GetCapabilitiesResponse>Capabilities>Events>XAddr"`
+	}
+	if err := c.call(ctx, c.xaddr, "", `<GetCapabilities xmlns="http://www.onvif.org/ver10/device/wsdl"><Category>All</Category></GetCapabilities>`, &resp); err != nil {
+		return fmt.Errorf("could not get capabilities: %v", err)
+	}
+	if resp.Media == "" {
+		return fmt.Errorf("device does not support media")
+	}
+	c.mediaAddr = resp.Media
+	c.eventsAddr = resp.Events
+	return nil
+}
+
+// Profile is a media profile (stream) on the device
+type Profile struct {
+	Token string `json:"token"`
+	Name  string `json:"name"`
+	URI   string `json:"uri"`
+}
+
+// Profiles returns the media profiles and their RTSP urls. The credentials are added to the urls.
+func (c *Client) Profiles(ctx context.Context) ([]*Profile, error) {
+
+	if err := c.capabilities(ctx); err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Profiles []struct {
+			Token string `xml:"token,attr"`
+			Name  string `xml:"Name"`
+		} `xml:"Body>GetProfilesResponse>Profiles"`
+	}
+	if err := c.call(ctx, c.mediaAddr, "", `<GetProfiles xmlns="http://www.onvif.org/ver10/media/wsdl"/>`, &resp); err != nil {
+		return nil, fmt.Errorf("could not get profiles: %v", err)
+	}
+
+	profiles := make([]*Profile, 0, len(resp.Profiles))
+	for _, p := range resp.Profiles {
+		var uriResp struct {
+			URI string `xml:"Body>GetStreamUriResponse>MediaUri>Uri"`
+		}
+		body := `<GetStreamUri xmlns="http://www.onvif.org/ver10/media/wsdl"><StreamSetup><Stream xmlns="http://www.onvif.org/ver10/schema">RTP-Unicast</Stream><Transport xmlns="http://www.onvif.org/ver10/schema"><Protocol>RTSP</Protocol></Transport></StreamSetup><ProfileToken>` + xmlEscape(p.Token) + `</ProfileToken></GetStreamUri>`
+		if err := c.call(ctx, c.mediaAddr, "", body, &uriResp); err != nil {
+			return nil, fmt.Errorf("could not get stream uri for %s: %v", p.Name, err)
+		}
+		profiles = append(profiles, &Profile{
+			Token: p.Token,
+			Name:  p.Name,
+			URI:   c.withCredentials(uriResp.URI),
+		})
+	}
+
+	return profiles, nil
+
+}
+
+// StreamURI returns the RTSP url for the profile name or token. If profile is blank, the first profile is used.
+func (c *Client) StreamURI(ctx context.Context, profile string) (string, error) {
+	profiles, err := c.Profiles(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range profiles {
+		if profile == "" || p.Name == profile || p.Token == profile {
+			return p.URI, nil
+		}
+	}
+	return "", fmt.Errorf("profile %s not found", profile)
+}
+
+// withCredentials adds the username and password to the url if it doesn't have any
+func (c *Client) withCredentials(uri string) string {
+	if c.username == "" {
+		return uri
+	}
+	u, err := url.Parse(uri)
+	if err != nil || u.User != nil {
+		return uri
+	}
+	u.User = url.UserPassword(c.username, c.password)
+	return u.String()
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
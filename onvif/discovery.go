@@ -0,0 +1,113 @@
+// Package onvif implements the parts of ONVIF used to find cameras and their streams
+package onvif
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	discoveryAddress = "239.255.255.250:3702"
+
+	probeMessage = `<?xml version="1.0" encoding="UTF-8"?>
+<e:Envelope xmlns:e="http://www.w3.org/2003/05/soap-envelope" xmlns:w="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery" xmlns:dn="http://www.onvif.org/ver10/network/wsdl">
+<e:Header><w:MessageID>uuid:%s</w:MessageID><w:To e:mustUnderstand="true">urn:schemas-xmlsoap-org:ws:2005:04:discovery</w:To><w:Action e:mustUnderstand="true">http://schemas.xmlsoap.org/ws/2005/04/discovery/Probe</w:Action></e:Header>
+<e:Body><d:Probe><d:Types>dn:NetworkVideoTransmitter</d:Types></d:Probe></e:Body>
+</e:Envelope>`
+)
+
+// Device is a camera found with discovery
+type Device struct {
+	// The endpoint reference (unique id)
+	ID string `json:"id"`
+	// The device service urls
+	XAddrs []string `json:"xaddrs"`
+	Name   string   `json:"name"`
+	// The hardware model
+	Hardware string `json:"hardware"`
+}
+
+type probeMatches struct {
+	Matches []struct {
+		Address string `xml:"EndpointReference>Address"`
+		Scopes  string `xml:"Scopes"`
+		XAddrs  string `xml:"XAddrs"`
+	} `xml:"Body>ProbeMatches>ProbeMatch"`
+}
+
+// Discover sends a WS-Discovery probe and returns the cameras that respond before the context is done
+func Discover(ctx context.Context) ([]*Device, error) {
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, fmt.Errorf("could not listen: %v", err)
+	}
+	defer conn.Close()
+
+	addr, err := net.ResolveUDPAddr("udp4", discoveryAddress)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP([]byte(fmt.Sprintf(probeMessage, uuid())), addr); err != nil {
+		return nil, fmt.Errorf("could not send probe: %v", err)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(3 * time.Second)
+	}
+	conn.SetReadDeadline(deadline)
+
+	devices := make(map[string]*Device)
+	buf := make([]byte, 65536)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			// Timeout, we're done
+			break
+		}
+		var matches probeMatches
+		if err := xml.Unmarshal(buf[:n], &matches); err != nil {
+			continue
+		}
+		for _, m := range matches.Matches {
+			d := &Device{
+				ID:     m.Address,
+				XAddrs: strings.Fields(m.XAddrs),
+			}
+			for _, scope := range strings.Fields(m.Scopes) {
+				if strings.HasPrefix(scope, "onvif://www.onvif.org/name/") {
+					d.Name = unescapeScope(strings.TrimPrefix(scope, "onvif://www.onvif.org/name/"))
+				} else if strings.HasPrefix(scope, "onvif://www.onvif.org/hardware/") {
+					d.Hardware = unescapeScope(strings.TrimPrefix(scope, "onvif://www.onvif.org/hardware/"))
+				}
+			}
+			devices[d.ID] = d
+		}
+	}
+
+	ret := make([]*Device, 0, len(devices))
+	for _, d := range devices {
+		ret = append(ret, d)
+	}
+	return ret, nil
+
+}
+
+func unescapeScope(s string) string {
+	return strings.Replace(s, "%20", " ", -1)
+}
+
+// uuid returns a random version 4 uuid
+func uuid() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,77 @@
+package onvif
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	actionPullMessages = "http://www.onvif.org/ver10/events/wsdl/PullPointSubscription/PullMessagesRequest"
+	actionCreatePull   = "http://www.onvif.org/ver10/events/wsdl/EventPortType/CreatePullPointSubscriptionRequest"
+)
+
+type pullMessagesResponse struct {
+	Messages []struct {
+		Topic string `xml:"Topic"`
+		Items []struct {
+			Name  string `xml:"Name,attr"`
+			Value string `xml:"Value,attr"`
+		} `xml:"Message>Message>Data>SimpleItem"`
+	} `xml:"Body>PullMessagesResponse>NotificationMessage"`
+}
+
+// WatchMotion subscribes to the camera events and calls motion when the camera reports motion starting or stopping.
+// It runs until the context is canceled, resubscribing on errors.
+func (c *Client) WatchMotion(ctx context.Context, motion func(bool), errors func(error)) {
+	for ctx.Err() == nil {
+		if err := c.pullMotion(ctx, motion); err != nil && ctx.Err() == nil {
+			errors(err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(10 * time.Second):
+			}
+		}
+	}
+}
+
+func (c *Client) pullMotion(ctx context.Context, motion func(bool)) error {
+
+	if err := c.capabilities(ctx); err != nil {
+		return err
+	}
+	if c.eventsAddr == "" {
+		return fmt.Errorf("device does not support events")
+	}
+
+	var sub struct {
+		Address string `xml:"Body>CreatePullPointSubscriptionResponse>SubscriptionReference>Address"`
+	}
+	if err := c.call(ctx, c.eventsAddr, actionCreatePull, `<CreatePullPointSubscription xmlns="http://www.onvif.org/ver10/events/wsdl"><InitialTerminationTime>PT10M</InitialTerminationTime></CreatePullPointSubscription>`, &sub); err != nil {
+		return fmt.Errorf("could not subscribe to events: %v", err)
+	}
+	if sub.Address == "" {
+		return fmt.Errorf("no subscription address")
+	}
+
+	for ctx.Err() == nil {
+		var resp pullMessagesResponse
+		if err := c.call(ctx, sub.Address, actionPullMessages, `<PullMessages xmlns="http://www.onvif.org/ver10/events/wsdl"><Timeout>PT10S</Timeout><MessageLimit>16</MessageLimit></PullMessages>`, &resp); err != nil {
+			return fmt.Errorf("could not pull events: %v", err)
+		}
+		for _, m := range resp.Messages {
+			if !strings.Contains(strings.ToLower(m.Topic), "motion") {
+				continue
+			}
+			for _, item := range m.Items {
+				if item.Name == "IsMotion" || item.Name == "State" {
+					motion(item.Value == "true")
+				}
+			}
+		}
+	}
+
+	return nil
+
+}
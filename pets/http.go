@@ -0,0 +1,53 @@
+package pets
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/server"
+)
+
+// RegisterHTTP registers the pet list and delete endpoints on the router, pets are enrolled with the detector
+func (s *Store) RegisterHTTP(r chi.Router) {
+	if s == nil {
+		return
+	}
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(s.keys))
+		r.Get("/pets", s.handleList)
+		r.Delete("/pets/{name}", s.handleDelete)
+	})
+}
+
+// summary is a pet without the embeddings
+type summary struct {
+	Name       string    `json:"name"`
+	Label      string    `json:"label"`
+	Embeddings int       `json:"embeddings"`
+	Updated    time.Time `json:"updated"`
+}
+
+func (s *Store) handleList(w http.ResponseWriter, r *http.Request) {
+	pets := s.Pets()
+	ret := make([]*summary, 0, len(pets))
+	for _, pet := range pets {
+		ret = append(ret, &summary{Name: pet.Name, Label: pet.Label, Embeddings: len(pet.Embeddings), Updated: pet.Updated})
+	}
+	render.JSON(w, r, map[string]interface{}{"pets": ret})
+}
+
+func (s *Store) handleDelete(w http.ResponseWriter, r *http.Request) {
+	found, err := s.Delete(chi.URLParam(r, "name"))
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	if !found {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
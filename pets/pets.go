@@ -0,0 +1,256 @@
+// Package pets keeps the embeddings of enrolled pets and matches the cats and dogs found in frames with them, so "my
+// dog" can be told from "a dog".
+package pets
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/server"
+)
+
+// The attribute name of the recognized pet in the detections
+const Attribute = "pet"
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Pet is an enrolled pet and the embeddings of its pictures
+type Pet struct {
+	Name string `json:"name"`
+	// The label it was enrolled as, like cat or dog
+	Label      string      `json:"label"`
+	Embeddings [][]float32 `json:"embeddings"`
+	Updated    time.Time   `json:"updated"`
+}
+
+// Store keeps the enrolled pets
+type Store struct {
+	dir           string
+	detector      string
+	labels        map[string]struct{}
+	minConfidence float32
+	padding       float32
+	threshold     float32
+	max           int
+
+	pets map[string]*Pet
+	lock sync.RWMutex
+
+	keys   *server.AuthKeys
+	logger *zap.SugaredLogger
+}
+
+// New creates the pet store, it returns nil if it's not configured
+func New() *Store {
+
+	dir := config.GetString("doods.pets.dir")
+	if dir == "" {
+		return nil
+	}
+
+	s := &Store{
+		dir:           dir,
+		detector:      config.GetString("doods.pets.detector"),
+		labels:        make(map[string]struct{}),
+		minConfidence: float32(config.GetFloat64("doods.pets.min_confidence")),
+		padding:       float32(config.GetFloat64("doods.pets.padding")),
+		threshold:     float32(config.GetFloat64("doods.pets.threshold")),
+		max:           config.GetInt("doods.pets.max_embeddings"),
+		pets:          make(map[string]*Pet),
+		keys:          server.Keys(),
+		logger:        zap.S().With("package", "pets"),
+	}
+	if s.detector == "" {
+		s.logger.Fatalf("doods.pets.detector is required")
+	}
+	for _, label := range config.GetStringSlice("doods.pets.labels") {
+		s.labels[label] = struct{}{}
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		s.logger.Fatalf("Could not create pets dir: %v", err)
+	}
+	if err := s.load(); err != nil {
+		s.logger.Fatalf("Could not load pets: %v", err)
+	}
+
+	s.logger.Infow("Pets", "dir", dir, "detector", s.detector, "pets", len(s.pets))
+
+	return s
+
+}
+
+// load reads the enrolled pets
+func (s *Store) load() error {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, filename := range files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		pet := new(Pet)
+		if err := json.Unmarshal(data, pet); err != nil {
+			return fmt.Errorf("could not parse %s: %v", filename, err)
+		}
+		s.pets[pet.Name] = pet
+	}
+	return nil
+}
+
+// Detector returns the embedding detector
+func (s *Store) Detector() string {
+	return s.detector
+}
+
+// Padding returns the extra area around a detection as a fraction of its size
+func (s *Store) Padding() float32 {
+	return s.padding
+}
+
+// Matches returns true if the detection is a pet that should be recognized
+func (s *Store) Matches(label string, confidence float32) bool {
+	if s == nil || confidence < s.minConfidence {
+		return false
+	}
+	_, ok := s.labels[label]
+	return ok
+}
+
+// Enroll adds an embedding to the pet, the pet is created if it's new. The oldest embeddings are dropped over the max.
+func (s *Store) Enroll(name string, label string, embedding []float32) (*Pet, error) {
+
+	if !validName.MatchString(name) {
+		return nil, fmt.Errorf("invalid name %q, use letters, numbers, _ and -", name)
+	}
+	embedding = Normalize(embedding)
+	if embedding == nil {
+		return nil, fmt.Errorf("empty embedding")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Copy it, the pets are returned without the lock
+	pet := &Pet{Name: name, Label: label}
+	if old, ok := s.pets[name]; ok {
+		if len(old.Embeddings) > 0 && len(old.Embeddings[0]) != len(embedding) {
+			return nil, fmt.Errorf("embedding size %d does not match the enrolled size %d", len(embedding), len(old.Embeddings[0]))
+		}
+		pet.Embeddings = append(pet.Embeddings, old.Embeddings...)
+	}
+	pet.Embeddings = append(pet.Embeddings, embedding)
+	if s.max > 0 && len(pet.Embeddings) > s.max {
+		pet.Embeddings = pet.Embeddings[len(pet.Embeddings)-s.max:]
+	}
+	pet.Updated = time.Now()
+
+	data, err := json.Marshal(pet)
+	if err != nil {
+		return nil, err
+	}
+	if err = ioutil.WriteFile(s.petFile(name), data, 0600); err != nil {
+		return nil, err
+	}
+	s.pets[name] = pet
+
+	return pet, nil
+
+}
+
+// Match returns the enrolled pet with the label most like the embedding and the cosine similarity (0-1), blank if
+// none is over the threshold
+func (s *Store) Match(label string, embedding []float32) (string, float32) {
+
+	embedding = Normalize(embedding)
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var best string
+	var bestScore float32
+	for name, pet := range s.pets {
+		// A cat isn't matched with a dog
+		if pet.Label != "" && pet.Label != label {
+			continue
+		}
+		for _, e := range pet.Embeddings {
+			if len(e) != len(embedding) {
+				continue
+			}
+			var score float32
+			for i := range e {
+				score += e[i] * embedding[i]
+			}
+			if score > bestScore {
+				best, bestScore = name, score
+			}
+		}
+	}
+	if best == "" || bestScore < s.threshold {
+		return "", bestScore
+	}
+	return best, bestScore
+
+}
+
+// Pets returns the enrolled pets by name
+func (s *Store) Pets() []*Pet {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	ret := make([]*Pet, 0, len(s.pets))
+	for _, pet := range s.pets {
+		ret = append(ret, pet)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// Delete removes a pet
+func (s *Store) Delete(name string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.pets[name]; !ok {
+		return false, nil
+	}
+	delete(s.pets, name)
+	if err := os.Remove(s.petFile(name)); err != nil && !os.IsNotExist(err) {
+		return true, err
+	}
+	return true, nil
+}
+
+func (s *Store) petFile(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// Normalize returns the embedding scaled to length 1 so the dot product is the cosine similarity, nil if it's empty
+// or all zeros
+func Normalize(embedding []float32) []float32 {
+	var sum float64
+	for _, v := range embedding {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return nil
+	}
+	norm := float32(math.Sqrt(sum))
+	ret := make([]float32, len(embedding))
+	for i, v := range embedding {
+		ret[i] = v / norm
+	}
+	return ret
+}
@@ -0,0 +1,99 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
+)
+
+// RegisterHTTP registers the review endpoints on the router
+func (q *Queue) RegisterHTTP(r chi.Router) {
+	if q == nil {
+		return
+	}
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(q.keys))
+		r.Get("/review", q.handleList)
+		r.Get("/review/{id}", q.handleGet)
+		r.Put("/review/{id}", q.handleLabel)
+		r.Delete("/review/{id}", q.handleDelete)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(server.ImageAuth(q.keys))
+		r.Get("/review/{id}/image", q.handleImage)
+	})
+}
+
+// handleList returns the review items, the status query parameter filters them
+func (q *Queue) handleList(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, map[string]interface{}{"items": q.Items(r.URL.Query().Get("status"))})
+}
+
+func (q *Queue) handleGet(w http.ResponseWriter, r *http.Request) {
+	item, ok := q.Get(chi.URLParam(r, "id"))
+	if !ok {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	render.JSON(w, r, item)
+}
+
+// handleImage returns the original frame without the detections drawn
+func (q *Queue) handleImage(w http.ResponseWriter, r *http.Request) {
+	data, err := q.Image(chi.URLParam(r, "id"))
+	if os.IsNotExist(err) {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	} else if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Write(data)
+}
+
+// handleLabel sets the correct detections for the item, an empty list means there is nothing in the frame
+func (q *Queue) handleLabel(w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	var request struct {
+		Detections []*odrpc.Detection `json:"detections"`
+	}
+	if err := json.Unmarshal(data, &request); err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(fmt.Errorf("could not parse request: %v", err)))
+		return
+	}
+	item, err := q.Label(chi.URLParam(r, "id"), request.Detections)
+	if os.IsNotExist(err) {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	} else if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	render.JSON(w, r, item)
+}
+
+func (q *Queue) handleDelete(w http.ResponseWriter, r *http.Request) {
+	found, err := q.Delete(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	if !found {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
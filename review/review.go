@@ -0,0 +1,330 @@
+// Package review keeps detections the model isn't sure about so people can label them for training
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/server"
+	"github.com/snowzach/doods/sink"
+)
+
+// The item statuses
+const (
+	StatusPending = "pending"
+	StatusLabeled = "labeled"
+)
+
+// Item is a frame waiting to be labeled
+type Item struct {
+	ID        string    `json:"id"`
+	RequestID string    `json:"request_id,omitempty"`
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"`
+	Detector  string    `json:"detector"`
+	Status    string    `json:"status"`
+	// The detections from the model, at least one is in the review band
+	Detections []*odrpc.Detection `json:"detections"`
+	// The detections from the person labeling it
+	Labeled []*odrpc.Detection `json:"labeled,omitempty"`
+}
+
+// Queue stores frames with detections in the review confidence band
+type Queue struct {
+	dir       string
+	min       float32
+	max       float32
+	labels    map[string]struct{}
+	sources   map[string]struct{}
+	maxItems  int
+	rateLimit time.Duration
+	sink      string
+
+	items    map[string]*Item
+	pending  int
+	lastSent map[string]time.Time
+	lock     sync.Mutex
+
+	sinks  *sink.Manager
+	keys   *server.AuthKeys
+	logger *zap.SugaredLogger
+}
+
+// New creates the review queue, it returns nil if it's not configured
+func New(sinks *sink.Manager) *Queue {
+
+	dir := config.GetString("doods.review.dir")
+	if dir == "" {
+		return nil
+	}
+
+	q := &Queue{
+		dir:       dir,
+		min:       float32(config.GetFloat64("doods.review.min")),
+		max:       float32(config.GetFloat64("doods.review.max")),
+		maxItems:  config.GetInt("doods.review.max_items"),
+		rateLimit: config.GetDuration("doods.review.rate_limit"),
+		sink:      config.GetString("doods.review.sink"),
+		items:     make(map[string]*Item),
+		lastSent:  make(map[string]time.Time),
+		sinks:     sinks,
+		keys:      server.Keys(),
+		logger:    zap.S().With("package", "review"),
+	}
+
+	if labels := config.GetStringSlice("doods.review.labels"); len(labels) > 0 {
+		q.labels = make(map[string]struct{})
+		for _, label := range labels {
+			q.labels[label] = struct{}{}
+		}
+	}
+	if sources := config.GetStringSlice("doods.review.sources"); len(sources) > 0 {
+		q.sources = make(map[string]struct{})
+		for _, source := range sources {
+			q.sources[source] = struct{}{}
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		q.logger.Fatalf("Could not create review dir: %v", err)
+	}
+	if err := q.load(); err != nil {
+		q.logger.Fatalf("Could not load review queue: %v", err)
+	}
+
+	q.logger.Infow("Review Queue", "dir", dir, "min", q.min, "max", q.max, "items", len(q.items), "pending", q.pending)
+
+	return q
+
+}
+
+// load reads the saved items
+func (q *Queue) load() error {
+	files, err := filepath.Glob(filepath.Join(q.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, filename := range files {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		item := new(Item)
+		if err := json.Unmarshal(data, item); err != nil {
+			return fmt.Errorf("could not parse %s: %v", filename, err)
+		}
+		q.items[item.ID] = item
+		if item.Status == StatusPending {
+			q.pending++
+		}
+	}
+	return nil
+}
+
+// Add saves the event for review if a detection is in the review band. The detections should be the ones before
+// the thresholds are applied or the low confidence detections will already be gone.
+func (q *Queue) Add(e *sink.Event, detections []*odrpc.Detection) {
+	if q == nil || len(e.Image) == 0 || e.Private {
+		return
+	}
+	if q.sources != nil {
+		if _, ok := q.sources[e.Source]; !ok {
+			return
+		}
+	}
+
+	var found bool
+	keep := make([]*odrpc.Detection, 0, len(detections))
+	for _, d := range detections {
+		if d.Confidence < q.min {
+			continue
+		}
+		// Copy it, the labels are translated in place after
+		c := *d
+		keep = append(keep, &c)
+		if d.Confidence >= q.max {
+			continue
+		}
+		if q.labels != nil {
+			if _, ok := q.labels[d.Label]; !ok {
+				continue
+			}
+		}
+		found = true
+	}
+	if !found {
+		return
+	}
+
+	q.lock.Lock()
+	if q.maxItems > 0 && q.pending >= q.maxItems {
+		q.lock.Unlock()
+		return
+	}
+	if q.rateLimit > 0 && e.Time.Sub(q.lastSent[e.Source]) < q.rateLimit {
+		q.lock.Unlock()
+		return
+	}
+	q.lastSent[e.Source] = e.Time
+	item := &Item{
+		ID:         strconv.FormatInt(e.Time.UnixNano(), 10),
+		RequestID:  e.ID,
+		Time:       e.Time,
+		Source:     e.Source,
+		Detector:   e.Detector,
+		Status:     StatusPending,
+		Detections: keep,
+	}
+	q.items[item.ID] = item
+	q.pending++
+	q.lock.Unlock()
+
+	if err := ioutil.WriteFile(q.imageFile(item.ID), e.Image, 0644); err != nil {
+		q.logger.Errorf("Could not save review image: %v", err)
+		return
+	}
+	if err := q.save(item); err != nil {
+		q.logger.Errorf("Could not save review item: %v", err)
+		return
+	}
+	q.logger.Debugw("Added review item", "id", item.ID, "source", item.Source, "detections", len(keep))
+}
+
+// Items returns the items with the status, all if empty, oldest first
+func (q *Queue) Items(status string) []*Item {
+	q.lock.Lock()
+	items := make([]*Item, 0, len(q.items))
+	for _, item := range q.items {
+		if status == "" || item.Status == status {
+			items = append(items, item)
+		}
+	}
+	q.lock.Unlock()
+	sort.Slice(items, func(i, j int) bool { return items[i].Time.Before(items[j].Time) })
+	return items
+}
+
+// Get returns an item
+func (q *Queue) Get(id string) (*Item, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	item, ok := q.items[id]
+	return item, ok
+}
+
+// Image returns the frame for an item
+func (q *Queue) Image(id string) ([]byte, error) {
+	if _, ok := q.Get(id); !ok {
+		return nil, os.ErrNotExist
+	}
+	return ioutil.ReadFile(q.imageFile(id))
+}
+
+// Label sets the correct detections for an item and sends it to the review sink for the dataset
+func (q *Queue) Label(id string, detections []*odrpc.Detection) (*Item, error) {
+
+	for _, d := range detections {
+		if d.Label == "" {
+			return nil, fmt.Errorf("detection is missing a label")
+		}
+		if d.Top < 0 || d.Left < 0 || d.Bottom > 1 || d.Right > 1 || d.Top >= d.Bottom || d.Left >= d.Right {
+			return nil, fmt.Errorf("invalid box for %s", d.Label)
+		}
+		// Labels from a person are certain
+		if d.Confidence == 0 {
+			d.Confidence = 100
+		}
+	}
+
+	q.lock.Lock()
+	item, ok := q.items[id]
+	if !ok {
+		q.lock.Unlock()
+		return nil, os.ErrNotExist
+	}
+	if item.Status == StatusPending {
+		q.pending--
+	}
+	labeled := *item
+	labeled.Status = StatusLabeled
+	labeled.Labeled = detections
+	q.items[id] = &labeled
+	q.lock.Unlock()
+
+	if err := q.save(&labeled); err != nil {
+		return nil, fmt.Errorf("could not save item: %v", err)
+	}
+
+	if q.sink != "" {
+		image, err := ioutil.ReadFile(q.imageFile(id))
+		if err != nil {
+			return nil, fmt.Errorf("could not read image: %v", err)
+		}
+		q.sinks.Send(&sink.Event{
+			Time:     labeled.Time,
+			ID:       labeled.ID,
+			Source:   labeled.Source,
+			Detector: labeled.Detector,
+			Image:    image,
+			Response: &odrpc.DetectResponse{
+				Id:         labeled.RequestID,
+				Detections: detections,
+			},
+			Labeled: true,
+			Sinks:   []string{q.sink},
+		})
+	}
+
+	return &labeled, nil
+
+}
+
+// Delete removes an item and its image
+func (q *Queue) Delete(id string) (bool, error) {
+	q.lock.Lock()
+	item, ok := q.items[id]
+	if !ok {
+		q.lock.Unlock()
+		return false, nil
+	}
+	if item.Status == StatusPending {
+		q.pending--
+	}
+	delete(q.items, id)
+	q.lock.Unlock()
+
+	for _, filename := range []string{q.imageFile(id), q.itemFile(id)} {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// save writes the item
+func (q *Queue) save(item *Item) error {
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(q.itemFile(item.ID), data, 0644)
+}
+
+func (q *Queue) itemFile(id string) string {
+	return filepath.Join(q.dir, id+".json")
+}
+
+func (q *Queue) imageFile(id string) string {
+	return filepath.Join(q.dir, id+".img")
+}
@@ -0,0 +1,271 @@
+// Package script runs Starlark hooks that can change detection requests and responses
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The hook functions a script can define
+const (
+	onRequest  = "on_request"
+	onResponse = "on_response"
+)
+
+func init() {
+	// Scores and coordinates are floats
+	resolve.AllowFloat = true
+}
+
+// Hooks are the functions from a Starlark script
+type Hooks struct {
+	filename   string
+	onRequest  starlark.Callable
+	onResponse starlark.Callable
+	logger     *zap.SugaredLogger
+}
+
+// Load runs the script and returns its hooks. The script can define:
+//
+//	on_request(request) - return the request dict with any changes
+//	on_response(request, detections) - return the list of detections to keep
+func Load(filename string) (*Hooks, error) {
+
+	h := &Hooks{
+		filename: filename,
+		logger:   zap.S().With("package", "script", "script", filename),
+	}
+
+	globals, err := starlark.ExecFile(h.thread("load"), filename, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not load script %s: %v", filename, err)
+	}
+
+	var ok bool
+	if fn, exists := globals[onRequest]; exists {
+		if h.onRequest, ok = fn.(starlark.Callable); !ok {
+			return nil, fmt.Errorf("%s is not a function", onRequest)
+		}
+	}
+	if fn, exists := globals[onResponse]; exists {
+		if h.onResponse, ok = fn.(starlark.Callable); !ok {
+			return nil, fmt.Errorf("%s is not a function", onResponse)
+		}
+	}
+	if h.onRequest == nil && h.onResponse == nil {
+		return nil, fmt.Errorf("script %s has no %s or %s function", filename, onRequest, onResponse)
+	}
+
+	return h, nil
+
+}
+
+// thread returns a thread for one call, print() from the script is logged
+func (h *Hooks) thread(name string) *starlark.Thread {
+	return &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			h.logger.Infow("Script", "hook", name, "message", msg)
+		},
+	}
+}
+
+// Request calls on_request with the request and applies the detector_name, detect, regions, ignore and language it returns
+func (h *Hooks) Request(source string, request *odrpc.DetectRequest) error {
+	if h == nil || h.onRequest == nil {
+		return nil
+	}
+
+	args, err := requestValue(source, request)
+	if err != nil {
+		return err
+	}
+
+	ret, err := starlark.Call(h.thread(onRequest), h.onRequest, starlark.Tuple{args}, nil)
+	if err != nil {
+		return fmt.Errorf("%s failed: %v", onRequest, err)
+	}
+	if ret == starlark.None {
+		return nil
+	}
+
+	var changed odrpc.DetectRequest
+	if err := fromValue(ret, &changed); err != nil {
+		return fmt.Errorf("%s returned an invalid request: %v", onRequest, err)
+	}
+	request.DetectorName = changed.DetectorName
+	request.Detect = changed.Detect
+	request.Regions = changed.Regions
+	request.Ignore = changed.Ignore
+	request.Language = changed.Language
+	return nil
+}
+
+// Response calls on_response with the request and detections and replaces the detections with the ones it returns
+func (h *Hooks) Response(source string, request *odrpc.DetectRequest, response *odrpc.DetectResponse) error {
+	if h == nil || h.onResponse == nil {
+		return nil
+	}
+
+	req, err := requestValue(source, request)
+	if err != nil {
+		return err
+	}
+	detections, err := toValue(response.Detections)
+	if err != nil {
+		return err
+	}
+
+	ret, err := starlark.Call(h.thread(onResponse), h.onResponse, starlark.Tuple{req, detections}, nil)
+	if err != nil {
+		return fmt.Errorf("%s failed: %v", onResponse, err)
+	}
+	if ret == starlark.None {
+		return nil
+	}
+
+	changed := make([]*odrpc.Detection, 0)
+	if err := fromValue(ret, &changed); err != nil {
+		return fmt.Errorf("%s returned invalid detections: %v", onResponse, err)
+	}
+	response.Detections = changed
+	return nil
+}
+
+// requestValue returns the request without the image data as a dict
+func requestValue(source string, request *odrpc.DetectRequest) (starlark.Value, error) {
+	view := *request
+	view.Data = nil
+	view.Inputs = nil
+	b, err := json.Marshal(&view)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	delete(m, "data")
+	m["source"] = source
+	return goToValue(m)
+}
+
+// toValue converts v to a Starlark value using its JSON encoding
+func toValue(v interface{}) (starlark.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+	return goToValue(generic)
+}
+
+// fromValue converts the Starlark value into v using the JSON encoding
+func fromValue(value starlark.Value, v interface{}) error {
+	generic, err := valueToGo(value)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// goToValue converts decoded JSON to Starlark values
+func goToValue(v interface{}) (starlark.Value, error) {
+	switch t := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(t), nil
+	case float64:
+		return starlark.Float(t), nil
+	case string:
+		return starlark.String(t), nil
+	case []interface{}:
+		list := make([]starlark.Value, 0, len(t))
+		for _, item := range t {
+			value, err := goToValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return starlark.NewList(list), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		dict := starlark.NewDict(len(t))
+		for _, k := range keys {
+			value, err := goToValue(t[k])
+			if err != nil {
+				return nil, err
+			}
+			if err := dict.SetKey(starlark.String(k), value); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", v)
+}
+
+// valueToGo converts Starlark values to values that can be encoded as JSON
+func valueToGo(v starlark.Value) (interface{}, error) {
+	switch t := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(t), nil
+	case starlark.Int:
+		i, ok := t.Int64()
+		if !ok {
+			return nil, fmt.Errorf("int out of range: %s", t)
+		}
+		return i, nil
+	case starlark.Float:
+		return float64(t), nil
+	case starlark.String:
+		return string(t), nil
+	case starlark.Indexable: // list and tuple
+		list := make([]interface{}, 0, t.Len())
+		for i := 0; i < t.Len(); i++ {
+			item, err := valueToGo(t.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	case *starlark.Dict:
+		m := make(map[string]interface{}, t.Len())
+		for _, item := range t.Items() {
+			k, ok := item[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf("dict keys must be strings, got %s", item[0].Type())
+			}
+			value, err := valueToGo(item[1])
+			if err != nil {
+				return nil, err
+			}
+			m[string(k)] = value
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
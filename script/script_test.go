@@ -0,0 +1,136 @@
+package script
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// load writes the script to a temporary directory and loads it
+func load(t *testing.T, source string) (*Hooks, error) {
+	dir, err := ioutil.TempDir("", "script")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "doods.script")
+	if err = ioutil.WriteFile(filename, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return Load(filename)
+}
+
+func TestLoad(t *testing.T) {
+
+	for name, source := range map[string]string{
+		"no hooks":     "x = 1\n",
+		"not function": "on_request = 1\n",
+		"syntax error": "def on_request(request)\n",
+	} {
+		if _, err := load(t, source); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// A nil or partial hook set does nothing
+	var hooks *Hooks
+	if err := hooks.Request("camera", &odrpc.DetectRequest{}); err != nil {
+		t.Error(err)
+	}
+	hooks, err := load(t, "def on_request(request):\n    return None\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := &odrpc.DetectResponse{Detections: []*odrpc.Detection{{Label: "person"}}}
+	if err := hooks.Response("camera", &odrpc.DetectRequest{}, response); err != nil || len(response.Detections) != 1 {
+		t.Errorf("response changed without on_response: %v %v", response.Detections, err)
+	}
+
+}
+
+func TestRequest(t *testing.T) {
+
+	hooks, err := load(t, `
+def on_request(request):
+    if request["source"] == "garage":
+        request["detector_name"] = "edgetpu"
+        request["detect"] = {"car": 60}
+        request["regions"] = [{"top": 0.5, "left": 0, "bottom": 1, "right": 1, "detect": {"person": 50}}]
+    return request
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := &odrpc.DetectRequest{DetectorName: "default", Data: odrpc.Raw("image"), Language: "es"}
+	if err = hooks.Request("garage", request); err != nil {
+		t.Fatal(err)
+	}
+	if request.DetectorName != "edgetpu" || request.Detect["car"] != 60 || len(request.Regions) != 1 || request.Regions[0].Top != 0.5 || request.Regions[0].Detect["person"] != 50 {
+		t.Errorf("request not changed: %+v", request)
+	}
+	if string(request.Data) != "image" || request.Language != "es" {
+		t.Errorf("request lost fields: %+v", request)
+	}
+
+	request = &odrpc.DetectRequest{DetectorName: "default"}
+	if err = hooks.Request("door", request); err != nil {
+		t.Fatal(err)
+	}
+	if request.DetectorName != "default" || request.Detect != nil {
+		t.Errorf("request changed: %+v", request)
+	}
+
+}
+
+func TestResponse(t *testing.T) {
+
+	hooks, err := load(t, `
+def on_response(request, detections):
+    print("filtering", len(detections))
+    return [d for d in detections if d["label"] != "cat" or request["source"] != "yard"]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detections := []*odrpc.Detection{
+		{Label: "person", Confidence: 90, Top: 0.1, Left: 0.2, Bottom: 0.3, Right: 0.4},
+		{Label: "cat", Confidence: 80},
+	}
+	response := &odrpc.DetectResponse{Detections: detections}
+	if err = hooks.Response("yard", &odrpc.DetectRequest{}, response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Detections) != 1 || !reflect.DeepEqual(response.Detections[0], detections[0]) {
+		t.Errorf("unexpected detections: %v", response.Detections)
+	}
+
+	response = &odrpc.DetectResponse{Detections: detections}
+	if err = hooks.Response("porch", &odrpc.DetectRequest{}, response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Detections) != 2 {
+		t.Errorf("unexpected detections: %v", response.Detections)
+	}
+
+	// Invalid return values and failing scripts are errors
+	for _, source := range []string{
+		"def on_response(request, detections):\n    return 1\n",
+		"def on_response(request, detections):\n    return [{1: 2}]\n",
+		"def on_response(request, detections):\n    return detections[5]\n",
+	} {
+		hooks, err := load(t, source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = hooks.Response("yard", &odrpc.DetectRequest{}, &odrpc.DetectResponse{Detections: detections}); err == nil {
+			t.Errorf("expected an error for %q", source)
+		}
+	}
+
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// AuthKeyQueryParam can be used to pass the auth key for things that can't set headers (like an img tag)
+const AuthKeyQueryParam = "auth_key"
+
+// The roles of auth keys. Read keys can detect and list, admin keys can also change things.
+const (
+	RoleRead  = "read"
+	RoleAdmin = "admin"
+)
+
+// AuthKeyConfig is an auth key and its role
+type AuthKeyConfig struct {
+	Key string `json:"key"`
+	// read or admin (default)
+	Role string `json:"role"`
+}
+
+// AuthKeys are the auth keys and their roles
+type AuthKeys struct {
+	roles map[string]string
+	// Internal keys for the roles of listeners with their own auth key, they're kept when the keys are reloaded
+	listeners map[string]string
+	lock      sync.RWMutex
+}
+
+var (
+	authKeys     *AuthKeys
+	authKeysOnce sync.Once
+)
+
+// Keys returns the auth keys, doods.auth_key is an admin key and doods.auth_keys has keys with roles. They're reloaded
+// when the config file changes.
+func Keys() *AuthKeys {
+	authKeysOnce.Do(func() {
+		roles, err := authKeyRoles()
+		if err != nil {
+			zap.S().Fatalw("Invalid auth keys", "error", err)
+		}
+		authKeys = &AuthKeys{
+			roles: roles,
+		}
+		conf.OnConfigChange(authKeys.reload, "doods.auth_key")
+	})
+	return authKeys
+}
+
+// authKeyRoles returns the roles of the configured auth keys
+func authKeyRoles() (map[string]string, error) {
+	roles := make(map[string]string)
+	if key := conf.Live().GetString("doods.auth_key"); key != "" {
+		roles[key] = RoleAdmin
+	}
+	var keyConfig []*AuthKeyConfig
+	if err := conf.Live().UnmarshalKey("doods.auth_keys", &keyConfig); err != nil {
+		return nil, err
+	}
+	for _, c := range keyConfig {
+		if c.Role == "" {
+			c.Role = RoleAdmin
+		}
+		if c.Key == "" || (c.Role != RoleRead && c.Role != RoleAdmin) {
+			return nil, fmt.Errorf("auth key with role %q needs a key and the role read or admin", c.Role)
+		}
+		roles[c.Key] = c.Role
+	}
+	return roles, nil
+}
+
+// reload replaces the keys with the config, invalid keys are logged and the current keys are kept
+func (k *AuthKeys) reload() {
+	roles, err := authKeyRoles()
+	if err != nil {
+		zap.S().Errorw("Invalid auth keys, keeping the current keys", "error", err)
+		return
+	}
+	k.lock.Lock()
+	k.roles = roles
+	k.lock.Unlock()
+	zap.S().Infow("Reloaded auth keys", "keys", len(roles))
+}
+
+// Enabled returns true if requests need an auth key
+func (k *AuthKeys) Enabled() bool {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	return len(k.roles) > 0
+}
+
+// Allowed returns true if the key has the role, admin keys have every role. Everything is allowed without keys. Requests
+// a listener let in with its own auth key have the role of the listener instead of a key.
+func (k *AuthKeys) Allowed(ctx context.Context, key string, role string) bool {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	if len(k.roles) == 0 {
+		return true
+	}
+	keyRole, ok := listenerRole(ctx)
+	if !ok {
+		if keyRole, ok = k.roles[key]; !ok {
+			if keyRole, ok = k.listeners[key]; !ok {
+				return false
+			}
+		}
+	}
+	return keyRole == RoleAdmin || keyRole == role
+}
+
+// listenerKey returns the internal key for the role of a listener. The listener passes it on for the gRPC gateway which
+// calls the gRPC services on its own connection without the request context.
+func (k *AuthKeys) listenerKey(role string) string {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	for key, keyRole := range k.listeners {
+		if keyRole == role {
+			return key
+		}
+	}
+	key := randomKey()
+	if k.listeners == nil {
+		k.listeners = make(map[string]string)
+	}
+	k.listeners[key] = role
+	return key
+}
+
+// randomKey returns a random key for internal requests
+func randomKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type listenerRoleKey struct{}
+
+type listenerQueryRoleKey struct{}
+
+// withListenerRole returns a context with the role of the listener the request came in on
+func withListenerRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, listenerRoleKey{}, role)
+}
+
+// listenerRole returns the role of the listener the request came in on if it has its own auth key
+func listenerRole(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(listenerRoleKey{}).(string)
+	return role, ok
+}
+
+// withListenerQueryRole returns a context with the role of the listener for a request with its key in the query param,
+// it only applies to the image routes
+func withListenerQueryRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, listenerQueryRoleKey{}, role)
+}
+
+// listenerQueryRole returns the role of the listener if the request had its key in the query param
+func listenerQueryRole(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(listenerQueryRoleKey{}).(string)
+	return role, ok
+}
+
+// RequestKey returns the auth key header of the request
+func RequestKey(r *http.Request) string {
+	return r.Header.Get(odrpc.DoodsAuthKeyHeader)
+}
+
+// QueryKey returns the auth key query param of GET requests
+func QueryKey(r *http.Request) string {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return ""
+	}
+	return r.URL.Query().Get(AuthKeyQueryParam)
+}
+
+// RequestRole is the role needed for the request, reading needs a read key and everything else an admin key
+func RequestRole(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return RoleRead
+	}
+	return RoleAdmin
+}
+
+// Auth is middleware that requires an auth key with the role for the request if there are auth keys
+func Auth(keys *AuthKeys) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := listenerQueryRole(r.Context()); ok || !keys.Allowed(r.Context(), RequestKey(r), RequestRole(r)) {
+				render.Render(w, r, ErrPermissionDenied)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ImageAuth is Auth that also takes the key from the auth_key query param of GET requests. It's for the images and
+// streams a browser opens directly (like an img tag) which can't set headers.
+func ImageAuth(keys *AuthKeys) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			key := RequestKey(r)
+			if key == "" {
+				key = QueryKey(r)
+			}
+			if role, ok := listenerQueryRole(ctx); ok {
+				ctx = withListenerRole(ctx, role)
+			}
+			if !keys.Allowed(ctx, key, RequestRole(r)) {
+				render.Render(w, r, ErrPermissionDenied)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	config "github.com/spf13/viper"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+func TestAllowed(t *testing.T) {
+
+	keys := &AuthKeys{roles: map[string]string{"reader": RoleRead, "admin": RoleAdmin}}
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		key    string
+		role   string
+		expect bool
+	}{
+		{"reader", RoleRead, true},
+		{"reader", RoleAdmin, false},
+		{"admin", RoleRead, true},
+		{"admin", RoleAdmin, true},
+		{"", RoleRead, false},
+		{"other", RoleRead, false},
+	} {
+		if allowed := keys.Allowed(ctx, test.key, test.role); allowed != test.expect {
+			t.Errorf("key %q role %s allowed %v, expected %v", test.key, test.role, allowed, test.expect)
+		}
+	}
+
+	// Without keys everything is allowed
+	if !(&AuthKeys{roles: map[string]string{}}).Allowed(ctx, "", RoleAdmin) {
+		t.Error("expected everything allowed without keys")
+	}
+
+}
+
+func TestListenerRole(t *testing.T) {
+
+	keys := &AuthKeys{roles: map[string]string{"admin": RoleAdmin}}
+
+	// The listener role replaces the key
+	read := withListenerRole(context.Background(), RoleRead)
+	if !keys.Allowed(read, "", RoleRead) || keys.Allowed(read, "", RoleAdmin) {
+		t.Error("read listener role not applied")
+	}
+	if keys.Allowed(read, "admin", RoleAdmin) {
+		t.Error("key used on a listener with its own auth key")
+	}
+	if !keys.Allowed(withListenerRole(context.Background(), RoleAdmin), "", RoleAdmin) {
+		t.Error("admin listener role not applied")
+	}
+
+	// The listener doesn't add keys that work anywhere else
+	if len(keys.roles) != 1 {
+		t.Errorf("listener added keys: %v", keys.roles)
+	}
+
+	// The listener checks its key and passes the role on
+	s := &Server{}
+	var allowed bool
+	s.handler = Auth(keys)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { allowed = true }))
+	h := s.listenerHandler(&ListenerConfig{AuthKey: "listener", AuthRole: RoleRead, Protocol: ProtocolAll})
+
+	for _, test := range []struct {
+		method string
+		key    string
+		expect bool
+	}{
+		{"GET", "listener", true},
+		{"POST", "listener", false},
+		{"GET", "admin", false},
+		{"GET", "", false},
+	} {
+		allowed = false
+		r := httptest.NewRequest(test.method, "/", nil)
+		r.Header.Set(odrpc.DoodsAuthKeyHeader, test.key)
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		if allowed != test.expect {
+			t.Errorf("%s with key %q allowed %v, expected %v", test.method, test.key, allowed, test.expect)
+		}
+	}
+
+	// The gRPC gateway only gets the header, the listener replaces its key with an internal key for the role
+	var key string
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { key = r.Header.Get(odrpc.DoodsAuthKeyHeader) })
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(odrpc.DoodsAuthKeyHeader, "listener")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	gateway := &AuthKeys{roles: map[string]string{"admin": RoleAdmin}, listeners: Keys().listeners}
+	if key == "listener" || !gateway.Allowed(context.Background(), key, RoleRead) || gateway.Allowed(context.Background(), key, RoleAdmin) {
+		t.Errorf("gateway key %q does not have the listener role", key)
+	}
+
+}
+
+func TestReloadKeys(t *testing.T) {
+
+	defer config.Reset()
+	config.Set("doods.auth_key", "admin")
+	roles, err := authKeyRoles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := &AuthKeys{roles: roles}
+	if !keys.Allowed(context.Background(), "admin", RoleAdmin) {
+		t.Fatal("admin key not loaded")
+	}
+
+	config.Set("doods.auth_key", "")
+	config.Set("doods.auth_keys", []map[string]interface{}{{"key": "reader", "role": "read"}})
+	keys.reload()
+	if keys.Allowed(context.Background(), "admin", RoleRead) || !keys.Allowed(context.Background(), "reader", RoleRead) {
+		t.Fatalf("keys not reloaded: %v", keys.roles)
+	}
+
+	// Invalid keys keep the current keys
+	config.Set("doods.auth_keys", []map[string]interface{}{{"key": "reader", "role": "owner"}})
+	keys.reload()
+	if !keys.Allowed(context.Background(), "reader", RoleRead) {
+		t.Fatalf("invalid keys replaced the current keys: %v", keys.roles)
+	}
+
+}
+
+func TestImageAuth(t *testing.T) {
+
+	keys := &AuthKeys{roles: map[string]string{"reader": RoleRead}}
+	var allowed bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { allowed = true })
+	auth, image := Auth(keys)(next), ImageAuth(keys)(next)
+
+	// Only the image routes take the key in the query param
+	for _, test := range []struct {
+		name    string
+		handler http.Handler
+		method  string
+		url     string
+		expect  bool
+	}{
+		{"auth query", auth, "GET", "/?auth_key=reader", false},
+		{"image query", image, "GET", "/?auth_key=reader", true},
+		{"image query post", image, "POST", "/?auth_key=reader", false},
+		{"image wrong query", image, "GET", "/?auth_key=other", false},
+		{"image no key", image, "GET", "/", false},
+	} {
+		allowed = false
+		test.handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(test.method, test.url, nil))
+		if allowed != test.expect {
+			t.Errorf("%s allowed %v, expected %v", test.name, allowed, test.expect)
+		}
+	}
+
+	// The listener key in the query param also only works on the image routes
+	s := &Server{}
+	l := &ListenerConfig{AuthKey: "listener", AuthRole: RoleRead, Protocol: ProtocolAll}
+	for _, test := range []struct {
+		name    string
+		handler http.Handler
+		url     string
+		expect  bool
+	}{
+		{"auth", auth, "/?auth_key=listener", false},
+		{"image", image, "/?auth_key=listener", true},
+		{"image wrong key", image, "/?auth_key=reader", false},
+	} {
+		allowed = false
+		s.handler = test.handler
+		s.listenerHandler(l).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", test.url, nil))
+		if allowed != test.expect {
+			t.Errorf("listener %s allowed %v, expected %v", test.name, allowed, test.expect)
+		}
+	}
+
+}
@@ -0,0 +1,19 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+)
+
+// ServeCached writes data with caching headers. If etag is blank, only the modified time is used
+// for conditional requests. It handles If-None-Match, If-Modified-Since and Range requests.
+func ServeCached(w http.ResponseWriter, r *http.Request, contentType string, modTime time.Time, etag string, data []byte) {
+	w.Header().Set("Content-Type", contentType)
+	// Allow caching but the client must revalidate
+	w.Header().Set("Cache-Control", "no-cache")
+	if etag != "" {
+		w.Header().Set("ETag", `"`+etag+`"`)
+	}
+	http.ServeContent(w, r, "", modTime, bytes.NewReader(data))
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/go-chi/render"
+	config "github.com/spf13/viper"
+	_ "google.golang.org/grpc/encoding/gzip" // Registers the gzip compressor
+)
+
+// Compress compresses responses with gzip or deflate based on Accept-Encoding for the
+// server.compression.types content types. Paths in server.compression.exclude_paths are not compressed.
+func Compress() func(next http.Handler) http.Handler {
+
+	level := config.GetInt("server.compression.level")
+	compressor := middleware.NewCompressor(level, config.GetStringSlice("server.compression.types")...)
+	excluded := config.GetStringSlice("server.compression.exclude_paths")
+
+	return func(next http.Handler) http.Handler {
+		compressed := compressor.Handler(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, prefix := range excluded {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			compressed.ServeHTTP(w, r)
+		})
+	}
+
+}
+
+// Decompress decompresses request bodies with a gzip Content-Encoding
+func Decompress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		var err error
+		switch strings.ToLower(r.Header.Get("Content-Encoding")) {
+		case "":
+			next.ServeHTTP(w, r)
+			return
+		case "gzip":
+			var zr *gzip.Reader
+			if zr, err = gzip.NewReader(r.Body); err == nil {
+				defer zr.Close()
+				r.Body = ioutil.NopCloser(zr)
+			}
+		default:
+			err = fmt.Errorf("unsupported content encoding %s", r.Header.Get("Content-Encoding"))
+		}
+		if err != nil {
+			render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+
+		r.Header.Del("Content-Encoding")
+		r.ContentLength = -1
+		next.ServeHTTP(w, r)
+
+	})
+}
+
+// LimitBody limits the size of request bodies after they are decompressed. Requests that say they are
+// too large are rejected before reading the body.
+func LimitBody(maxSize int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maxSize > 0 && r.ContentLength > maxSize {
+				render.Render(w, r, ErrTooLarge(maxSize))
+				return
+			}
+			if maxSize > 0 && r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
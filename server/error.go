@@ -1,9 +1,12 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/render"
+	gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/status"
 )
 
 // ErrResponse is a generic struct for returning a standard error document
@@ -54,3 +57,34 @@ func ErrInternal(err error) render.Renderer {
 		ErrorText:      "Server Error.",
 	}
 }
+
+// StatusCode returns the HTTP status the gateway uses for the code of a gRPC error
+func StatusCode(err error) int {
+	return gwruntime.HTTPStatusFromCode(status.Code(err))
+}
+
+// ErrStatus returns a gRPC error with the HTTP status of its code, server errors are generic
+func ErrStatus(err error) render.Renderer {
+	code := StatusCode(err)
+	if code == http.StatusInternalServerError {
+		return ErrInternal(err)
+	}
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: code,
+		StatusText:     http.StatusText(code) + ".",
+		ErrorText:      status.Convert(err).Message(),
+	}
+}
+
+// ErrPermissionDenied is a pre-built permission denied error
+var ErrPermissionDenied = &ErrResponse{HTTPStatusCode: 403, StatusText: "Permission Denied."}
+
+// ErrTooLarge returns a request too large error with the limit
+func ErrTooLarge(maxSize int64) render.Renderer {
+	return &ErrResponse{
+		HTTPStatusCode: http.StatusRequestEntityTooLarge,
+		StatusText:     "Request too large.",
+		ErrorText:      fmt.Sprintf("the max request size is %d bytes", maxSize),
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-chi/render"
+	"github.com/snowzach/certtools"
+	"github.com/snowzach/certtools/autocert"
+	config "github.com/spf13/viper"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The listener protocols
+const (
+	ProtocolAll  = "all"
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// AuthKeyNone disables the auth key on a listener
+const AuthKeyNone = "none"
+
+// ListenerConfig is an address the server listens on
+type ListenerConfig struct {
+	// tcp (default), tcp4, tcp6 or unix
+	Network string `json:"network"`
+	// host:port or the socket path
+	Address string `json:"address"`
+	// all (default), http or grpc
+	Protocol string `json:"protocol"`
+	TLS      bool   `json:"tls"`
+	DevCert  bool   `json:"devcert"`
+	// Defaults to server.certfile and server.keyfile
+	CertFile string `json:"certfile"`
+	KeyFile  string `json:"keyfile"`
+	// Overrides the auth keys for requests on this listener, none disables them
+	AuthKey string `json:"auth_key"`
+	// The role of requests with the listener auth key, read or admin (default)
+	AuthRole string `json:"auth_role"`
+}
+
+// listenerConfigs returns the configured listeners, server.host and server.port are the default
+func listenerConfigs() []*ListenerConfig {
+	var listenerConfig []*ListenerConfig
+	config.UnmarshalKey("server.listeners", &listenerConfig)
+	if len(listenerConfig) == 0 {
+		listenerConfig = append(listenerConfig, &ListenerConfig{
+			Address: net.JoinHostPort(config.GetString("server.host"), config.GetString("server.port")),
+			TLS:     config.GetBool("server.tls"),
+			DevCert: config.GetBool("server.devcert"),
+		})
+	}
+	return listenerConfig
+}
+
+// listen starts serving on a listener
+func (s *Server) listen(c *ListenerConfig) error {
+
+	if c.Network == "" {
+		c.Network = "tcp"
+	}
+	switch c.Protocol {
+	case "":
+		c.Protocol = ProtocolAll
+	case ProtocolAll, ProtocolHTTP, ProtocolGRPC:
+	default:
+		return fmt.Errorf("Invalid protocol %s for listener %s", c.Protocol, c.Address)
+	}
+
+	// Remove a socket left from the last run
+	if c.Network == "unix" {
+		if fi, err := os.Stat(c.Address); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			os.Remove(c.Address)
+		}
+	}
+
+	listener, err := net.Listen(c.Network, c.Address)
+	if err != nil {
+		return fmt.Errorf("Could not listen on %s: %v", c.Address, err)
+	}
+
+	server := &http.Server{
+		Handler:  s.listenerHandler(c),
+		ErrorLog: log.New(&errorLogger{logger: s.logger}, "", 0),
+	}
+
+	if c.TLS {
+		var cert tls.Certificate
+		if c.DevCert {
+			s.logger.Warn("WARNING: This server is using an insecure development tls certificate. This is for development only!!!")
+			cert, err = autocert.New(autocert.InsecureStringReader("localhost"))
+			if err != nil {
+				return fmt.Errorf("Could not autocert generate server certificate: %v", err)
+			}
+		} else {
+			certFile, keyFile := c.CertFile, c.KeyFile
+			if certFile == "" {
+				certFile = config.GetString("server.certfile")
+			}
+			if keyFile == "" {
+				keyFile = config.GetString("server.keyfile")
+			}
+			cert, err = tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return fmt.Errorf("Could not load server certificate: %v", err)
+			}
+		}
+		server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   certtools.SecureTLSMinVersion(),
+			CipherSuites: certtools.SecureTLSCipherSuites(),
+			NextProtos:   []string{"h2"},
+		}
+		listener = tls.NewListener(listener, server.TLSConfig)
+	} else {
+		// This h2c helper allows using insecure requests to http2/grpc
+		server.Handler = h2c.NewHandler(server.Handler, &http2.Server{})
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			s.logger.Fatalw("API Listen error", "error", err, "address", c.Address)
+		}
+	}()
+	s.logger.Infow("API Listening", "network", c.Network, "address", listener.Addr().String(), "protocol", c.Protocol, "tls", c.TLS, "version", conf.GitVersion)
+
+	return nil
+
+}
+
+// listenerHandler limits the protocols of the listener and checks its auth key
+func (s *Server) listenerHandler(c *ListenerConfig) http.Handler {
+
+	// Requests with the listener auth key are passed on with the role of the listener
+	var roleKey string
+	if c.AuthKey != "" {
+		if c.AuthRole == "" {
+			c.AuthRole = RoleAdmin
+		}
+		if c.AuthRole != RoleRead && c.AuthRole != RoleAdmin {
+			s.logger.Fatalw("Invalid listener auth role", "address", c.Address, "role", c.AuthRole)
+		}
+		roleKey = Keys().listenerKey(c.AuthRole)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		grpcRequest := isGRPC(r)
+		if (grpcRequest && c.Protocol == ProtocolHTTP) || (!grpcRequest && c.Protocol == ProtocolGRPC) {
+			http.NotFound(w, r)
+			return
+		}
+
+		// Check the listener auth key, the key in the query param only lets requests on to the image routes
+		if c.AuthKey != "" {
+			switch {
+			case c.AuthKey == AuthKeyNone || RequestKey(r) == c.AuthKey:
+				r = r.WithContext(withListenerRole(r.Context(), c.AuthRole))
+				r.Header.Set(odrpc.DoodsAuthKeyHeader, roleKey)
+			case !grpcRequest && RequestKey(r) == "" && QueryKey(r) == c.AuthKey:
+				r = r.WithContext(withListenerQueryRole(r.Context(), c.AuthRole))
+			case grpcRequest:
+				w.WriteHeader(http.StatusForbidden)
+				return
+			default:
+				render.Render(w, r, ErrPermissionDenied)
+				return
+			}
+		}
+
+		s.handler.ServeHTTP(w, r)
+
+	})
+
+}
+
+// isGRPC returns true for gRPC requests
+func isGRPC(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc")
+}
@@ -0,0 +1,359 @@
+package server
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+
+	gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+)
+
+// Content types for MessagePack bodies
+const (
+	MIMEMsgpack  = "application/msgpack"
+	MIMEMsgpack2 = "application/x-msgpack"
+)
+
+// msgpackMaxDepth is how deep arrays and maps can be nested in a body, requests are only a few levels deep
+const msgpackMaxDepth = 32
+
+// MsgpackMarshaler marshals MessagePack using the same field names as the JSON marshaler. Binary values
+// are accepted for bytes fields (like the image data) so they don't need to be base64 encoded.
+type MsgpackMarshaler struct{}
+
+func (mm *MsgpackMarshaler) Marshal(v interface{}) ([]byte, error) {
+	// Convert to generic values with encoding/json so the field names match
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var generic interface{}
+	if err := d.Decode(&generic); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := msgpackEncode(&buf, generic); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (mm *MsgpackMarshaler) Unmarshal(data []byte, v interface{}) error {
+	generic, err := msgpackDecode(bytes.NewReader(data), 0)
+	if err != nil {
+		return fmt.Errorf("invalid msgpack: %v", err)
+	}
+	data, err = json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (mm *MsgpackMarshaler) NewDecoder(r io.Reader) gwruntime.Decoder {
+	return gwruntime.DecoderFunc(func(v interface{}) error {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return mm.Unmarshal(data, v)
+	})
+}
+
+func (mm *MsgpackMarshaler) NewEncoder(w io.Writer) gwruntime.Encoder {
+	return gwruntime.EncoderFunc(func(v interface{}) error {
+		data, err := mm.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+}
+
+func (mm *MsgpackMarshaler) ContentType() string {
+	return MIMEMsgpack
+}
+
+// msgpackEncode encodes the generic values produced by encoding/json
+func msgpackEncode(w *bytes.Buffer, v interface{}) error {
+
+	switch v := v.(type) {
+	case nil:
+		w.WriteByte(0xc0)
+	case bool:
+		if v {
+			w.WriteByte(0xc3)
+		} else {
+			w.WriteByte(0xc2)
+		}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			msgpackInt(w, i)
+		} else if f, err := v.Float64(); err == nil {
+			w.WriteByte(0xcb)
+			binary.Write(w, binary.BigEndian, math.Float64bits(f))
+		} else {
+			return err
+		}
+	case string:
+		switch n := len(v); {
+		case n < 32:
+			w.WriteByte(0xa0 | byte(n))
+		case n <= math.MaxUint8:
+			w.Write([]byte{0xd9, byte(n)})
+		case n <= math.MaxUint16:
+			w.WriteByte(0xda)
+			binary.Write(w, binary.BigEndian, uint16(n))
+		default:
+			w.WriteByte(0xdb)
+			binary.Write(w, binary.BigEndian, uint32(n))
+		}
+		w.WriteString(v)
+	case []interface{}:
+		switch n := len(v); {
+		case n < 16:
+			w.WriteByte(0x90 | byte(n))
+		case n <= math.MaxUint16:
+			w.WriteByte(0xdc)
+			binary.Write(w, binary.BigEndian, uint16(n))
+		default:
+			w.WriteByte(0xdd)
+			binary.Write(w, binary.BigEndian, uint32(n))
+		}
+		for _, e := range v {
+			if err := msgpackEncode(w, e); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		switch n := len(v); {
+		case n < 16:
+			w.WriteByte(0x80 | byte(n))
+		case n <= math.MaxUint16:
+			w.WriteByte(0xde)
+			binary.Write(w, binary.BigEndian, uint16(n))
+		default:
+			w.WriteByte(0xdf)
+			binary.Write(w, binary.BigEndian, uint32(n))
+		}
+		// Sorted for stable output
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			msgpackEncode(w, k)
+			if err := msgpackEncode(w, v[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+
+	return nil
+
+}
+
+func msgpackInt(w *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i < 128:
+		w.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		w.WriteByte(byte(int8(i)))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		w.WriteByte(0xd2)
+		binary.Write(w, binary.BigEndian, int32(i))
+	default:
+		w.WriteByte(0xd3)
+		binary.Write(w, binary.BigEndian, i)
+	}
+}
+
+// msgpackDecode decodes a value into generic values for encoding/json. Binary values become base64 strings. The depth
+// is how many arrays and maps the value is in.
+func msgpackDecode(r *bytes.Reader, depth int) (interface{}, error) {
+
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xe0 == 0xa0:
+		return msgpackString(r, int(b&0x1f))
+	case b&0xf0 == 0x90:
+		return msgpackArray(r, int(b&0x0f), depth)
+	case b&0xf0 == 0x80:
+		return msgpackMap(r, int(b&0x0f), depth)
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := msgpackLength(r, b-0xc4)
+		if err != nil {
+			return nil, err
+		}
+		data, err := msgpackBytes(r, n)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	case 0xca:
+		var f float32
+		err := binary.Read(r, binary.BigEndian, &f)
+		return f, err
+	case 0xcb:
+		var f float64
+		err := binary.Read(r, binary.BigEndian, &f)
+		return f, err
+	case 0xcc:
+		var i uint8
+		err := binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	case 0xcd:
+		var i uint16
+		err := binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	case 0xce:
+		var i uint32
+		err := binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	case 0xcf:
+		var i uint64
+		err := binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	case 0xd0:
+		var i int8
+		err := binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	case 0xd1:
+		var i int16
+		err := binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	case 0xd2:
+		var i int32
+		err := binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	case 0xd3:
+		var i int64
+		err := binary.Read(r, binary.BigEndian, &i)
+		return i, err
+	case 0xd9, 0xda, 0xdb:
+		n, err := msgpackLength(r, b-0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackString(r, n)
+	case 0xdc, 0xdd:
+		n, err := msgpackLength(r, b-0xdc+1)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackArray(r, n, depth)
+	case 0xde, 0xdf:
+		n, err := msgpackLength(r, b-0xde+1)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackMap(r, n, depth)
+	}
+
+	return nil, fmt.Errorf("unsupported type 0x%x", b)
+
+}
+
+// msgpackLength reads a 1, 2 or 4 byte length (size 0, 1, 2)
+func msgpackLength(r *bytes.Reader, size byte) (int, error) {
+	switch size {
+	case 0:
+		var n uint8
+		err := binary.Read(r, binary.BigEndian, &n)
+		return int(n), err
+	case 1:
+		var n uint16
+		err := binary.Read(r, binary.BigEndian, &n)
+		return int(n), err
+	default:
+		var n uint32
+		err := binary.Read(r, binary.BigEndian, &n)
+		return int(n), err
+	}
+}
+
+// msgpackBytes reads n bytes, the length is checked against the rest of the body before allocating
+func msgpackBytes(r *bytes.Reader, n int) ([]byte, error) {
+	if n > r.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data := make([]byte, n)
+	_, err := io.ReadFull(r, data)
+	return data, err
+}
+
+func msgpackString(r *bytes.Reader, n int) (string, error) {
+	data, err := msgpackBytes(r, n)
+	return string(data), err
+}
+
+// msgpackArray reads n values, every value is at least a byte so longer arrays can't be in the rest of the body
+func msgpackArray(r *bytes.Reader, n int, depth int) ([]interface{}, error) {
+	if depth >= msgpackMaxDepth {
+		return nil, fmt.Errorf("nested more than %d levels", msgpackMaxDepth)
+	}
+	if n > r.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	ret := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		v, err := msgpackDecode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
+
+// msgpackMap reads n keys and values, every entry is at least two bytes
+func msgpackMap(r *bytes.Reader, n int, depth int) (map[string]interface{}, error) {
+	if depth >= msgpackMaxDepth {
+		return nil, fmt.Errorf("nested more than %d levels", msgpackMaxDepth)
+	}
+	if n > r.Len()/2 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	ret := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		k, err := msgpackDecode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := msgpackDecode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		ret[fmt.Sprint(k)] = v
+	}
+	return ret, nil
+}
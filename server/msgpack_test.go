@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMsgpackRoundTrip(t *testing.T) {
+
+	mm := &MsgpackMarshaler{}
+	for _, value := range []interface{}{
+		map[string]interface{}{"id": "test", "detector_name": "default", "detect": map[string]interface{}{"*": 50.0}},
+		map[string]interface{}{"values": []interface{}{1.0, -1.0, 300.0, -40000.0, 1e12, 0.5, nil, true, false}},
+		map[string]interface{}{"long": string(bytes.Repeat([]byte("x"), 70000)), "list": make([]interface{}, 20)},
+		[]interface{}{},
+	} {
+		data, err := mm.Marshal(value)
+		if err != nil {
+			t.Fatalf("could not marshal %v: %v", value, err)
+		}
+		var got interface{}
+		if err := mm.Unmarshal(data, &got); err != nil {
+			t.Fatalf("could not unmarshal %v: %v", value, err)
+		}
+		if !reflect.DeepEqual(got, value) {
+			t.Errorf("got %v, expected %v", got, value)
+		}
+	}
+
+	// Binary values are base64 strings for the bytes fields
+	var got map[string]interface{}
+	if err := mm.Unmarshal([]byte{0x81, 0xa4, 'd', 'a', 't', 'a', 0xc4, 0x02, 0x01, 0x02}, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["data"] != "AQI=" {
+		t.Errorf("got %v, expected base64 data", got)
+	}
+
+}
+
+func TestMsgpackTruncated(t *testing.T) {
+
+	mm := &MsgpackMarshaler{}
+	data, err := mm.Marshal(map[string]interface{}{"id": "test", "values": []interface{}{1.5, 70000.0, "abc"}, "ok": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(data); i++ {
+		var got interface{}
+		if err := mm.Unmarshal(data[:i], &got); err == nil {
+			t.Errorf("no error for %d of %d bytes", i, len(data))
+		}
+	}
+
+}
+
+func TestMsgpackInvalid(t *testing.T) {
+
+	deep := func(n int) []byte {
+		return append(bytes.Repeat([]byte{0x91}, n), 0xc0)
+	}
+
+	for _, test := range []struct {
+		name  string
+		data  []byte
+		valid bool
+	}{
+		{"array length", []byte{0xdd, 0xff, 0xff, 0xff, 0xff, 0xc0}, false},
+		{"array16 length", []byte{0xdc, 0xff, 0xff, 0xc0}, false},
+		{"map length", []byte{0xdf, 0xff, 0xff, 0xff, 0xff, 0xc0, 0xc0}, false},
+		{"fixmap length", []byte{0x8f, 0xc0, 0xc0}, false},
+		{"string length", []byte{0xdb, 0xff, 0xff, 0xff, 0xff, 'a'}, false},
+		{"binary length", []byte{0xc6, 0x7f, 0xff, 0xff, 0xff, 'a'}, false},
+		{"unsupported type", []byte{0xc1}, false},
+		{"nested", deep(msgpackMaxDepth), true},
+		{"too deep", deep(msgpackMaxDepth + 1), false},
+		{"too deep map", append(bytes.Repeat([]byte{0x81, 0xc0}, msgpackMaxDepth+1), 0xc0), false},
+		{"stack overflow", deep(8 << 20), false},
+	} {
+		var got interface{}
+		err := (&MsgpackMarshaler{}).Unmarshal(test.data, &got)
+		if test.valid && err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	golangproto "github.com/golang/protobuf/proto"
+	gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+)
+
+// Content types for protobuf bodies
+const (
+	MIMEProtobuf  = "application/x-protobuf"
+	MIMEProtobuf2 = "application/protobuf"
+)
+
+// ProtoMarshaler marshals the binary protobuf wire format. Generated messages use their own
+// Marshal/Unmarshal methods, anything else (like gateway errors) uses golang/protobuf.
+type ProtoMarshaler struct{}
+
+type protoMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+type protoUnmarshaler interface {
+	Unmarshal([]byte) error
+}
+
+func (pm *ProtoMarshaler) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case protoMarshaler:
+		return m.Marshal()
+	case golangproto.Message:
+		return golangproto.Marshal(m)
+	}
+	return nil, fmt.Errorf("%T is not a protobuf message", v)
+}
+
+func (pm *ProtoMarshaler) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case protoUnmarshaler:
+		return m.Unmarshal(data)
+	case golangproto.Message:
+		return golangproto.Unmarshal(data, m)
+	}
+	return fmt.Errorf("%T is not a protobuf message", v)
+}
+
+func (pm *ProtoMarshaler) NewDecoder(r io.Reader) gwruntime.Decoder {
+	return gwruntime.DecoderFunc(func(v interface{}) error {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return pm.Unmarshal(data, v)
+	})
+}
+
+func (pm *ProtoMarshaler) NewEncoder(w io.Writer) gwruntime.Encoder {
+	return gwruntime.EncoderFunc(func(v interface{}) error {
+		data, err := pm.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+}
+
+func (pm *ProtoMarshaler) ContentType() string {
+	return MIMEProtobuf
+}
@@ -3,7 +3,10 @@ package server
 import (
 	"net/http"
 
+	config "github.com/spf13/viper"
+
 	"github.com/snowzach/doods/server/rpc"
+	"github.com/snowzach/doods/ui"
 )
 
 // SetupRoutes configures all the routes for this service
@@ -12,6 +15,14 @@ func (s *Server) SetupRoutes() {
 	// Register our routes - you need at aleast one route
 	s.router.Get("/none", func(w http.ResponseWriter, r *http.Request) {})
 
+	// The web interface
+	if config.GetBool("server.ui_enabled") {
+		s.router.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/ui/", http.StatusFound)
+		})
+		s.router.Mount("/ui", ui.Handler())
+	}
+
 	// Register RPC Services
 	rpc.RegisterVersionRPCServer(s.grpcServer, s)
 	s.GWReg(rpc.RegisterVersionRPCHandlerFromEndpoint)
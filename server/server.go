@@ -2,8 +2,6 @@ package server
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"log"
 	"net"
@@ -23,16 +21,14 @@ import (
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
 	gwruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
-	"github.com/snowzach/certtools"
-	"github.com/snowzach/certtools/autocert"
 	config "github.com/spf13/viper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
 
-	"github.com/snowzach/doods/conf"
 	"github.com/snowzach/doods/odrpc"
 )
 
@@ -40,7 +36,7 @@ import (
 type Server struct {
 	logger     *zap.SugaredLogger
 	router     chi.Router
-	server     *http.Server
+	handler    http.Handler
 	grpcServer *grpc.Server
 	gwRegFuncs []gwRegFunc
 }
@@ -142,14 +138,27 @@ func New() (*Server, error) {
 		}
 	}
 
-	// CORS Config
-	r.Use(cors.New(cors.Options{
-		AllowedOrigins:   config.GetStringSlice("server.cors.allowed_origins"),
-		AllowedMethods:   config.GetStringSlice("server.cors.allowed_methods"),
-		AllowedHeaders:   config.GetStringSlice("server.cors.allowed_headers"),
-		AllowCredentials: config.GetBool("server.cors.allowed_credentials"),
-		MaxAge:           config.GetInt("server.cors.max_age"),
-	}).Handler)
+	// CORS Config for browser clients, this also answers the preflight requests for the gateway endpoints
+	if config.GetBool("server.cors.enabled") {
+		r.Use(cors.New(cors.Options{
+			AllowedOrigins:   config.GetStringSlice("server.cors.allowed_origins"),
+			AllowedMethods:   config.GetStringSlice("server.cors.allowed_methods"),
+			AllowedHeaders:   config.GetStringSlice("server.cors.allowed_headers"),
+			ExposedHeaders:   config.GetStringSlice("server.cors.exposed_headers"),
+			AllowCredentials: config.GetBool("server.cors.allowed_credentials"),
+			MaxAge:           config.GetInt("server.cors.max_age"),
+		}).Handler)
+	}
+
+	// Compression
+	r.Use(Decompress)
+	r.Use(LimitBody(config.GetInt64("server.max_msg_size")))
+	if config.GetBool("server.compression.enabled") {
+		r.Use(Compress())
+	}
+
+	// Accept image uploads on the detect endpoint
+	r.Use(UploadRequest)
 
 	// GRPC Interceptors
 	streamInterceptors := []grpc.StreamServerInterceptor{
@@ -169,7 +178,12 @@ func New() (*Server, error) {
 	// Create gRPC Server
 	g := grpc.NewServer(serverOptions...)
 	// Register reflection service on gRPC server (so people know what we have)
-	reflection.Register(g)
+	if config.GetBool("server.reflection") {
+		if err := odrpc.RegisterReflection(); err != nil {
+			zap.S().Warnw("Could not register descriptors for reflection", "error", err)
+		}
+		reflection.Register(g)
+	}
 
 	s := &Server{
 		logger:     zap.S().With("package", "server"),
@@ -177,16 +191,13 @@ func New() (*Server, error) {
 		grpcServer: g,
 		gwRegFuncs: make([]gwRegFunc, 0),
 	}
-	s.server = &http.Server{
-		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc") {
-				g.ServeHTTP(w, r)
-			} else {
-				s.router.ServeHTTP(w, r)
-			}
-		}),
-		ErrorLog: log.New(&errorLogger{logger: s.logger}, "", 0),
-	}
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGRPC(r) {
+			g.ServeHTTP(w, r)
+		} else {
+			s.router.ServeHTTP(w, r)
+		}
+	})
 
 	s.SetupRoutes()
 
@@ -194,74 +205,47 @@ func New() (*Server, error) {
 
 }
 
+// The gateway listener buffer and the header with its key
+const (
+	gatewayBufferSize = 1 << 20
+	gatewayKeyHeader  = "x-doods-gateway-key"
+)
+
 // ListenAndServe will listen for requests
 func (s *Server) ListenAndServe() error {
 
-	s.server.Addr = net.JoinHostPort(config.GetString("server.host"), config.GetString("server.port"))
-
-	// Listen
-	listener, err := net.Listen("tcp", s.server.Addr)
-	if err != nil {
-		return fmt.Errorf("Could not listen on %s: %v", s.server.Addr, err)
-	}
-
-	grpcGatewayDialOptions := []grpc.DialOption{}
-
-	// Enable TLS?
-	if config.GetBool("server.tls") {
-		var cert tls.Certificate
-		if config.GetBool("server.devcert") {
-			s.logger.Warn("WARNING: This server is using an insecure development tls certificate. This is for development only!!!")
-			cert, err = autocert.New(autocert.InsecureStringReader("localhost"))
-			if err != nil {
-				return fmt.Errorf("Could not autocert generate server certificate: %v", err)
-			}
-		} else {
-			// Load keys from file
-			cert, err = tls.LoadX509KeyPair(config.GetString("server.certfile"), config.GetString("server.keyfile"))
-			if err != nil {
-				return fmt.Errorf("Could not load server certificate: %v", err)
-			}
-		}
-
-		// Enabed Certs - TODO Add/Get a cert
-		s.server.TLSConfig = &tls.Config{
-			Certificates: []tls.Certificate{cert},
-			MinVersion:   certtools.SecureTLSMinVersion(),
-			CipherSuites: certtools.SecureTLSCipherSuites(),
-			NextProtos:   []string{"h2"},
+	// The gateway calls the gRPC services on an in process listener so it works with any listener settings without
+	// opening another port. Only the gateway has the key for it.
+	internal := bufconn.Listen(gatewayBufferSize)
+	gatewayKey := randomKey()
+	go func() {
+		server := &http.Server{
+			Handler:  h2c.NewHandler(gatewayHandler(gatewayKey, s.handler), &http2.Server{}),
+			ErrorLog: log.New(&errorLogger{logger: s.logger}, "", 0),
 		}
-		// Wrap the listener in a TLS Listener
-		listener = tls.NewListener(listener, s.server.TLSConfig)
-
-		// Fetch the CommonName from the certificate and generate a cert pool for the grpc gateway to use
-		// This essentially figures out whatever certificate we happen to be using and makes it valid for the call between the GRPC gateway and the GRPC endpoint
-		x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
-		if err != nil {
-			return fmt.Errorf("Could not parse x509 public cert from tls certificate: %v", err)
+		if err := server.Serve(internal); err != nil {
+			s.logger.Fatalw("API Listen error", "error", err, "address", internal.Addr().String())
 		}
-		clientCertPool := x509.NewCertPool()
-		clientCertPool.AddCert(x509Cert)
-		grpcCreds := credentials.NewClientTLSFromCert(clientCertPool, x509Cert.Subject.CommonName)
-		grpcGatewayDialOptions = append(grpcGatewayDialOptions, grpc.WithTransportCredentials(grpcCreds))
-
-	} else {
-		// This h2c helper allows using insecure requests to http2/grpc
-		s.server.Handler = h2c.NewHandler(s.server.Handler, &http2.Server{})
-		grpcGatewayDialOptions = append(grpcGatewayDialOptions, grpc.WithInsecure())
-	}
+	}()
 
 	// Setup the GRPC gateway
 	grpcGatewayMux := gwruntime.NewServeMux(
 		gwruntime.WithMarshalerOption(gwruntime.MIMEWildcard, &JSONMarshaler{}),
+		gwruntime.WithMarshalerOption(MIMEProtobuf, &ProtoMarshaler{}),
+		gwruntime.WithMarshalerOption(MIMEProtobuf2, &ProtoMarshaler{}),
+		gwruntime.WithMarshalerOption(MIMEMsgpack, &MsgpackMarshaler{}),
+		gwruntime.WithMarshalerOption(MIMEMsgpack2, &MsgpackMarshaler{}),
 		gwruntime.WithIncomingHeaderMatcher(func(header string) (string, bool) {
 			// Pass our headers
 			switch strings.ToLower(header) {
-			case odrpc.DoodsAuthKeyHeader:
+			case odrpc.DoodsAuthKeyHeader, odrpc.AcceptLanguageHeader:
 				return header, true
 			}
 			return header, false
 		}),
+		gwruntime.WithMetadata(func(ctx context.Context, r *http.Request) metadata.MD {
+			return metadata.Pairs(gatewayKeyHeader, gatewayKey)
+		}),
 	)
 	// If the main router did not find and endpoint, pass it to the grpcGateway
 	s.router.NotFound(func(w http.ResponseWriter, r *http.Request) {
@@ -269,19 +253,25 @@ func (s *Server) ListenAndServe() error {
 	})
 
 	// Register all the GRPC gateway functions
+	dialOptions := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			return internal.Dial()
+		}),
+	}
 	for _, gwrf := range s.gwRegFuncs {
-		err = gwrf(context.Background(), grpcGatewayMux, listener.Addr().String(), grpcGatewayDialOptions)
+		err := gwrf(context.Background(), grpcGatewayMux, internal.Addr().String(), dialOptions)
 		if err != nil {
 			return fmt.Errorf("Could not register HTTP/gRPC gateway: %s", err)
 		}
 	}
 
-	go func() {
-		if err = s.server.Serve(listener); err != nil {
-			s.logger.Fatalw("API Listen error", "error", err, "address", s.server.Addr)
+	// Listen on each of the listeners
+	for _, c := range listenerConfigs() {
+		if err := s.listen(c); err != nil {
+			return err
 		}
-	}()
-	s.logger.Infow("API Listening", "address", s.server.Addr, "tls", config.GetBool("server.tls"), "version", conf.GitVersion)
+	}
 
 	// Enable profiler
 	if config.GetBool("server.profiler_enabled") && config.GetString("server.profiler_path") != "" {
@@ -293,11 +283,27 @@ func (s *Server) ListenAndServe() error {
 
 }
 
+// gatewayHandler only passes on requests from the gateway with its key
+func gatewayHandler(key string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(gatewayKeyHeader) != key {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // GWReg will save a gateway registration function for later when the server is started
 func (s *Server) GWReg(gwrf gwRegFunc) {
 	s.gwRegFuncs = append(s.gwRegFuncs, gwrf)
 }
 
+// Router returns the http router to allow functions to register routes
+func (s *Server) Router() chi.Router {
+	return s.router
+}
+
 // GRPCServer will return the grpc server to allow functions to register themselves
 func (s *Server) GRPCServer() *grpc.Server {
 	return s.grpcServer
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGatewayHandler(t *testing.T) {
+
+	internal := bufconn.Listen(gatewayBufferSize)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	server := &http.Server{Handler: h2c.NewHandler(gatewayHandler("gateway", next), &http2.Server{})}
+	go server.Serve(internal)
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return internal.Dial()
+		},
+	}}
+
+	// Requests on the internal listener without the gateway key are rejected even without auth keys
+	for _, test := range []struct {
+		key    string
+		status int
+	}{
+		{"", http.StatusForbidden},
+		{"other", http.StatusForbidden},
+		{"gateway", http.StatusOK},
+	} {
+		r, _ := http.NewRequest("POST", "http://internal/odrpc.odrpc/Detect", nil)
+		if test.key != "" {
+			r.Header.Set(gatewayKeyHeader, test.key)
+		}
+		resp, err := client.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.status {
+			t.Errorf("key %q got status %d, expected %d", test.key, resp.StatusCode, test.status)
+		}
+	}
+
+}
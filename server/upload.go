@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The form fields that can have the image in a multipart upload
+var uploadFields = []string{"image", "data"}
+
+// UploadRequest converts multipart/form-data and raw image bodies posted to the detect endpoint into a
+// protobuf request for the gateway. The image doesn't need to be base64 encoded and the other request
+// fields are passed as form fields or query parameters.
+func UploadRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method != http.MethodPost || r.URL.Path != "/detect" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if mediaType != "multipart/form-data" && mediaType != "application/octet-stream" && !strings.HasPrefix(mediaType, "image/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		request := new(odrpc.DetectRequest)
+		var err error
+		if mediaType == "multipart/form-data" {
+			err = multipartRequest(r, request)
+		} else {
+			err = rawRequest(r, request)
+		}
+		if err != nil {
+			render.Render(w, r, ErrInvalidRequest(err))
+			return
+		}
+
+		data, err := request.Marshal()
+		if err != nil {
+			render.Render(w, r, ErrInternal(err))
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(data))
+		r.ContentLength = int64(len(data))
+		r.Header.Set("Content-Type", MIMEProtobuf)
+
+		// Respond with JSON unless another format was asked for
+		switch r.Header.Get("Accept") {
+		case MIMEProtobuf, MIMEProtobuf2, MIMEMsgpack, MIMEMsgpack2:
+		default:
+			r.Header.Set("Accept", "application/json")
+		}
+
+		next.ServeHTTP(w, r)
+
+	})
+}
+
+// multipartRequest reads the image from the form file and the other fields from the form values
+func multipartRequest(r *http.Request, request *odrpc.DetectRequest) error {
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		return fmt.Errorf("invalid form: %v", err)
+	}
+	if err := formRequest(r.MultipartForm.Value, request); err != nil {
+		return err
+	}
+
+	for _, field := range uploadFields {
+		f, _, err := r.FormFile(field)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		request.Data, err = ioutil.ReadAll(f)
+		return err
+	}
+
+	if len(request.Data) == 0 && request.File == "" {
+		return fmt.Errorf("missing image")
+	}
+	return nil
+
+}
+
+// rawRequest reads the image from the body and the other fields from the query parameters
+func rawRequest(r *http.Request, request *odrpc.DetectRequest) error {
+
+	if err := formRequest(r.URL.Query(), request); err != nil {
+		return err
+	}
+
+	var err error
+	if request.Data, err = ioutil.ReadAll(r.Body); err != nil {
+		return fmt.Errorf("could not read image: %v", err)
+	}
+	if len(request.Data) == 0 {
+		return fmt.Errorf("missing image")
+	}
+	return nil
+
+}
+
+// formRequest fills the request from form values. A full JSON request can be passed in the request field.
+// The detect field is JSON or a list of label:score and ignore is a list of labels.
+func formRequest(values url.Values, request *odrpc.DetectRequest) error {
+
+	if v := values.Get("request"); v != "" {
+		if err := json.Unmarshal([]byte(v), request); err != nil {
+			return fmt.Errorf("invalid request: %v", err)
+		}
+	}
+	if v := values.Get("detector_name"); v != "" {
+		request.DetectorName = v
+	}
+	if v := values.Get("id"); v != "" {
+		request.Id = v
+	}
+	if v := values.Get("file"); v != "" {
+		request.File = v
+	}
+	if v := values.Get("language"); v != "" {
+		request.Language = v
+	}
+	if v := values.Get("detect"); v != "" {
+		detect, err := parseDetect(v)
+		if err != nil {
+			return err
+		}
+		request.Detect = detect
+	}
+	if v := values.Get("regions"); v != "" {
+		if err := json.Unmarshal([]byte(v), &request.Regions); err != nil {
+			return fmt.Errorf("invalid regions: %v", err)
+		}
+	}
+	for _, v := range values["ignore"] {
+		for _, label := range strings.Split(v, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				request.Ignore = append(request.Ignore, label)
+			}
+		}
+	}
+
+	return nil
+
+}
+
+// parseDetect parses a JSON object or a comma separated list of label:score
+func parseDetect(v string) (map[string]float32, error) {
+
+	detect := make(map[string]float32)
+	if strings.HasPrefix(strings.TrimSpace(v), "{") {
+		if err := json.Unmarshal([]byte(v), &detect); err != nil {
+			return nil, fmt.Errorf("invalid detect: %v", err)
+		}
+		return detect, nil
+	}
+
+	for _, item := range strings.Split(v, ",") {
+		i := strings.LastIndex(item, ":")
+		if i < 0 {
+			detect[strings.TrimSpace(item)] = 0
+			continue
+		}
+		score, err := strconv.ParseFloat(strings.TrimSpace(item[i+1:]), 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid detect score %s: %v", item, err)
+		}
+		detect[strings.TrimSpace(item[:i])] = float32(score)
+	}
+	return detect, nil
+
+}
@@ -0,0 +1,309 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/sink/sinkconfig"
+)
+
+// The dataset formats
+const (
+	DatasetVOC  = "voc"
+	DatasetCOCO = "coco"
+)
+
+var unsafeName = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// datasetStore saves a file to a directory or S3
+type datasetStore interface {
+	put(ctx context.Context, key string, contentType string, data []byte) error
+}
+
+// dirStore saves files in a directory
+type dirStore string
+
+func (d dirStore) put(_ context.Context, key string, _ string, data []byte) error {
+	filename := filepath.Join(string(d), filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// dataset saves the images and detections as Pascal VOC or COCO annotations for training.
+// It's only called from the sink goroutine so it doesn't need a lock.
+type dataset struct {
+	config *sinkconfig.DatasetConfig
+	store  datasetStore
+	prefix string
+
+	// COCO annotations are one file for the dataset
+	coco     *cocoDataset
+	cocoKey  string
+	labelIDs map[string]int
+}
+
+func init() {
+	Register("dataset", func(c *sinkconfig.SinkConfig) (Sink, error) {
+		if c.Dataset == nil {
+			return nil, fmt.Errorf("missing dataset config")
+		}
+		return newDataset(c.Dataset)
+	})
+}
+
+func newDataset(c *sinkconfig.DatasetConfig) (*dataset, error) {
+
+	d := &dataset{
+		config: c,
+	}
+	if d.config.Percent <= 0 {
+		d.config.Percent = 100
+	}
+
+	if c.S3 != nil {
+		s, err := newS3(c.S3)
+		if err != nil {
+			return nil, err
+		}
+		d.store = s
+		d.prefix = strings.Trim(c.Dir, "/")
+	} else if c.Dir != "" {
+		d.store = dirStore(c.Dir)
+	} else {
+		return nil, fmt.Errorf("dir or s3 is required")
+	}
+
+	switch c.Format {
+	case DatasetVOC:
+	case DatasetCOCO:
+		d.coco = &cocoDataset{
+			Info:        cocoInfo{Description: "doods", DateCreated: time.Now().Format(time.RFC3339)},
+			Images:      make([]*cocoImage, 0),
+			Annotations: make([]*cocoAnnotation, 0),
+			Categories:  make([]*cocoCategory, 0),
+		}
+		d.labelIDs = make(map[string]int)
+		d.cocoKey = "annotations.json"
+		if c.S3 != nil {
+			// Objects can't be appended, each run gets its own file
+			d.cocoKey = fmt.Sprintf("annotations-%d.json", time.Now().Unix())
+		} else if err := d.loadCOCO(filepath.Join(c.Dir, d.cocoKey)); err != nil {
+			return nil, fmt.Errorf("could not load annotations: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown dataset format: %s", c.Format)
+	}
+
+	return d, nil
+
+}
+
+// Send saves the image and the annotations
+func (d *dataset) Send(ctx context.Context, e *Event) error {
+
+	if e.Clip != "" || len(e.Image) == 0 || e.Private {
+		return nil
+	}
+	if d.config.Percent < 100 && rand.Float64()*100 >= d.config.Percent {
+		return nil
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(e.Image))
+	if err != nil {
+		return fmt.Errorf("could not decode image: %v", err)
+	}
+	if format == "jpeg" {
+		format = "jpg"
+	}
+
+	name := unsafeName.ReplaceAllString(e.Source, "_") + "_" + e.Time.Format("20060102_150405.000")
+	if e.ID != "" {
+		name += "_" + unsafeName.ReplaceAllString(e.ID, "_")
+	}
+	filename := name + "." + format
+
+	switch d.config.Format {
+	case DatasetVOC:
+		if err := d.store.put(ctx, d.key("JPEGImages", filename), "image/"+format, e.Image); err != nil {
+			return fmt.Errorf("could not save image: %v", err)
+		}
+		annotation, err := vocAnnotation(filename, cfg, e.Response.Detections)
+		if err != nil {
+			return fmt.Errorf("could not encode annotation: %v", err)
+		}
+		return d.store.put(ctx, d.key("Annotations", name+".xml"), "application/xml", annotation)
+
+	case DatasetCOCO:
+		if err := d.store.put(ctx, d.key("images", filename), "image/"+format, e.Image); err != nil {
+			return fmt.Errorf("could not save image: %v", err)
+		}
+		d.addCOCO(filename, e.Time, cfg, e.Response.Detections)
+		data, err := json.Marshal(d.coco)
+		if err != nil {
+			return fmt.Errorf("could not encode annotations: %v", err)
+		}
+		return d.store.put(ctx, d.key(d.cocoKey), "application/json", data)
+	}
+
+	return nil
+
+}
+
+// key joins the prefix and the path elements
+func (d *dataset) key(elem ...string) string {
+	if d.prefix != "" {
+		elem = append([]string{d.prefix}, elem...)
+	}
+	return strings.Join(elem, "/")
+}
+
+// vocXML is a Pascal VOC annotation
+type vocXML struct {
+	XMLName   xml.Name    `xml:"annotation"`
+	Folder    string      `xml:"folder"`
+	Filename  string      `xml:"filename"`
+	Database  string      `xml:"source>database"`
+	Width     int         `xml:"size>width"`
+	Height    int         `xml:"size>height"`
+	Depth     int         `xml:"size>depth"`
+	Segmented int         `xml:"segmented"`
+	Objects   []vocObject `xml:"object"`
+}
+
+type vocObject struct {
+	Name      string `xml:"name"`
+	Pose      string `xml:"pose"`
+	Truncated int    `xml:"truncated"`
+	Difficult int    `xml:"difficult"`
+	XMin      int    `xml:"bndbox>xmin"`
+	YMin      int    `xml:"bndbox>ymin"`
+	XMax      int    `xml:"bndbox>xmax"`
+	YMax      int    `xml:"bndbox>ymax"`
+}
+
+// vocAnnotation returns the VOC XML for the image, the boxes are in pixels
+func vocAnnotation(filename string, cfg image.Config, detections []*odrpc.Detection) ([]byte, error) {
+	v := vocXML{
+		Folder:   "JPEGImages",
+		Filename: filename,
+		Database: "doods",
+		Width:    cfg.Width,
+		Height:   cfg.Height,
+		Depth:    3,
+	}
+	for _, det := range detections {
+		v.Objects = append(v.Objects, vocObject{
+			Name: det.Label,
+			Pose: "Unspecified",
+			XMin: int(det.Left * float32(cfg.Width)),
+			YMin: int(det.Top * float32(cfg.Height)),
+			XMax: int(det.Right * float32(cfg.Width)),
+			YMax: int(det.Bottom * float32(cfg.Height)),
+		})
+	}
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// cocoDataset is a COCO object detection dataset
+type cocoDataset struct {
+	Info        cocoInfo          `json:"info"`
+	Images      []*cocoImage      `json:"images"`
+	Annotations []*cocoAnnotation `json:"annotations"`
+	Categories  []*cocoCategory   `json:"categories"`
+}
+
+type cocoInfo struct {
+	Description string `json:"description"`
+	DateCreated string `json:"date_created"`
+}
+
+type cocoImage struct {
+	ID           int    `json:"id"`
+	FileName     string `json:"file_name"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	DateCaptured string `json:"date_captured"`
+}
+
+type cocoAnnotation struct {
+	ID         int       `json:"id"`
+	ImageID    int       `json:"image_id"`
+	CategoryID int       `json:"category_id"`
+	BBox       []float32 `json:"bbox"` // x, y, width, height in pixels
+	Area       float32   `json:"area"`
+	IsCrowd    int       `json:"iscrowd"`
+	Score      float32   `json:"score"`
+}
+
+type cocoCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// loadCOCO continues an existing annotations file
+func (d *dataset) loadCOCO(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, d.coco); err != nil {
+		return err
+	}
+	for _, c := range d.coco.Categories {
+		d.labelIDs[c.Name] = c.ID
+	}
+	return nil
+}
+
+// addCOCO adds the image and its annotations
+func (d *dataset) addCOCO(filename string, t time.Time, cfg image.Config, detections []*odrpc.Detection) {
+
+	img := &cocoImage{
+		ID:           len(d.coco.Images) + 1,
+		FileName:     filename,
+		Width:        cfg.Width,
+		Height:       cfg.Height,
+		DateCaptured: t.Format(time.RFC3339),
+	}
+	d.coco.Images = append(d.coco.Images, img)
+
+	for _, det := range detections {
+		id, ok := d.labelIDs[det.Label]
+		if !ok {
+			id = len(d.coco.Categories) + 1
+			d.labelIDs[det.Label] = id
+			d.coco.Categories = append(d.coco.Categories, &cocoCategory{ID: id, Name: det.Label})
+		}
+		w := (det.Right - det.Left) * float32(cfg.Width)
+		h := (det.Bottom - det.Top) * float32(cfg.Height)
+		d.coco.Annotations = append(d.coco.Annotations, &cocoAnnotation{
+			ID:         len(d.coco.Annotations) + 1,
+			ImageID:    img.ID,
+			CategoryID: id,
+			BBox:       []float32{det.Left * float32(cfg.Width), det.Top * float32(cfg.Height), w, h},
+			Area:       w * h,
+			Score:      det.Confidence,
+		})
+	}
+
+}
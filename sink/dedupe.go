@@ -0,0 +1,101 @@
+package sink
+
+import (
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// dedupe drops detection events where every object was already reported for the source within the window.
+// The next event that is sent has the number of events dropped since the last one.
+type dedupe struct {
+	window  time.Duration
+	iou     float32
+	sources map[string]*dedupeSource
+	lock    sync.Mutex
+}
+
+type dedupeSource struct {
+	objects    []*dedupeObject
+	duplicates int
+}
+
+// dedupeObject is a reported object
+type dedupeObject struct {
+	label    string
+	box      odrpc.Detection
+	reported time.Time
+}
+
+// newDedupe returns nil (disabled) if the window is 0
+func newDedupe(window time.Duration, iou float32) *dedupe {
+	if window <= 0 {
+		return nil
+	}
+	if iou <= 0 {
+		iou = 0.5
+	}
+	return &dedupe{
+		window:  window,
+		iou:     iou,
+		sources: make(map[string]*dedupeSource),
+	}
+}
+
+// check returns false if the event is a duplicate, otherwise it sets the number of duplicates dropped before it
+func (d *dedupe) check(e *Event) bool {
+
+	// Only detections, changes, alerts and clips are always sent
+	if d == nil || !e.detectionEvent() || len(e.Changes) > 0 || len(e.Response.Detections) == 0 {
+		return true
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	s, ok := d.sources[e.Source]
+	if !ok {
+		s = new(dedupeSource)
+		d.sources[e.Source] = s
+	}
+
+	// Forget the objects reported before the window
+	objects := s.objects[:0]
+	for _, o := range s.objects {
+		if e.Time.Sub(o.reported) < d.window {
+			objects = append(objects, o)
+		}
+	}
+	s.objects = objects
+
+	var send bool
+	for _, det := range e.Response.Detections {
+		if o := d.find(s, det); o != nil {
+			// Follow the object while it moves slowly
+			o.box = *det
+			continue
+		}
+		s.objects = append(s.objects, &dedupeObject{label: det.Label, box: *det, reported: e.Time})
+		send = true
+	}
+
+	if !send {
+		s.duplicates++
+		return false
+	}
+	e.Duplicates = s.duplicates
+	s.duplicates = 0
+	return true
+
+}
+
+// find returns the reported object matching the detection
+func (d *dedupe) find(s *dedupeSource, det *odrpc.Detection) *dedupeObject {
+	for _, o := range s.objects {
+		if o.label == det.Label && odrpc.IoU(&o.box, det) >= d.iou {
+			return o
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package sink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/state"
+)
+
+// testEvent returns a detection event for the source at the seconds from a fixed time
+func testEvent(source string, at int, detections ...*odrpc.Detection) *Event {
+	return &Event{
+		Time:     time.Date(2020, 1, 1, 12, 0, at, 0, time.UTC),
+		Source:   source,
+		Response: &odrpc.DetectResponse{Detections: detections},
+	}
+}
+
+func testDetection(label string, left float32, confidence float32) *odrpc.Detection {
+	return &odrpc.Detection{Label: label, Top: 0.2, Left: left, Bottom: 0.6, Right: left + 0.3, Confidence: confidence}
+}
+
+func TestDedupe(t *testing.T) {
+
+	if newDedupe(0, 0.5) != nil {
+		t.Error("dedupe with no window should be disabled")
+	}
+
+	d := newDedupe(10*time.Second, 0)
+	for _, test := range []struct {
+		describe   string
+		event      *Event
+		send       bool
+		duplicates int
+	}{
+		{"new person", testEvent("yard", 0, testDetection("person", 0.1, 90)), true, 0},
+		{"same person moved", testEvent("yard", 1, testDetection("person", 0.12, 80)), false, 0},
+		{"person moved slowly", testEvent("yard", 2, testDetection("person", 0.2, 80)), false, 0},
+		{"new car", testEvent("yard", 3, testDetection("person", 0.2, 80), testDetection("car", 0.6, 70)), true, 2},
+		{"same car", testEvent("yard", 4, testDetection("car", 0.6, 70)), false, 0},
+		{"other label at the same place", testEvent("yard", 5, testDetection("dog", 0.2, 60)), true, 1},
+		{"other source", testEvent("door", 6, testDetection("person", 0.1, 90)), true, 0},
+		{"person reported before the window", testEvent("yard", 11, testDetection("person", 0.2, 80)), true, 0},
+		{"car still in the window", testEvent("yard", 12, testDetection("car", 0.6, 70)), false, 0},
+		{"no detections", testEvent("yard", 13), true, 0},
+		{"changes", &Event{Time: time.Now(), Source: "yard", Response: &odrpc.DetectResponse{Detections: []*odrpc.Detection{testDetection("car", 0.6, 70)}}, Changes: []*state.Change{{Label: "car"}}}, true, 0},
+		{"alert", &Event{Time: time.Now(), Source: "yard", Alert: "car", Response: &odrpc.DetectResponse{Detections: []*odrpc.Detection{testDetection("car", 0.6, 70)}}}, true, 0},
+	} {
+		if send := d.check(test.event); send != test.send || test.event.Duplicates != test.duplicates {
+			t.Errorf("%s: send %v duplicates %d, expected %v and %d", test.describe, send, test.event.Duplicates, test.send, test.duplicates)
+		}
+	}
+
+	// Disabled sends everything
+	var disabled *dedupe
+	if !disabled.check(testEvent("yard", 0, testDetection("person", 0.1, 90))) {
+		t.Error("disabled dedupe dropped an event")
+	}
+
+}
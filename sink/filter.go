@@ -0,0 +1,138 @@
+package sink
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/sink/sinkconfig"
+)
+
+// filter decides if an event should be sent to a sink
+type filter struct {
+	sources   map[string]struct{}
+	detect    map[string]float32
+	rateLimit time.Duration
+	lastSent  time.Time
+	changes   bool
+	alerts    bool
+
+	quiet      bool
+	quietStart time.Duration // Since midnight
+	quietEnd   time.Duration
+}
+
+func newFilter(c *sinkconfig.SinkConfig) (*filter, error) {
+
+	f := &filter{
+		detect:    c.Detect,
+		rateLimit: c.RateLimit,
+		changes:   c.Changes,
+		alerts:    c.Alerts,
+	}
+
+	if len(c.Sources) > 0 {
+		f.sources = make(map[string]struct{})
+		for _, source := range c.Sources {
+			f.sources[source] = struct{}{}
+		}
+	}
+
+	if c.QuietHours != nil {
+		var err error
+		if f.quietStart, err = conf.ParseTimeOfDay(c.QuietHours.Start); err != nil {
+			return nil, fmt.Errorf("invalid quiet hours start: %v", err)
+		}
+		if f.quietEnd, err = conf.ParseTimeOfDay(c.QuietHours.End); err != nil {
+			return nil, fmt.Errorf("invalid quiet hours end: %v", err)
+		}
+		f.quiet = true
+	}
+
+	return f, nil
+
+}
+
+// source returns true if the event source is wanted, this is checked before queueing
+func (f *filter) source(e *Event) bool {
+	if f.sources == nil {
+		return true
+	}
+	_, ok := f.sources[e.Source]
+	return ok
+}
+
+// allow returns true if the event should be sent. It's only called from the sink goroutine.
+func (f *filter) allow(e *Event) bool {
+
+	if f.alerts && e.Alert == "" {
+		return false
+	}
+
+	// Labeled events are sent to the sink named by the review queue
+	if e.Labeled {
+		return true
+	}
+
+	// Events are sent for detections or changes, alerts are already filtered by their rule
+	if e.Clip == "" && e.Alert == "" {
+		if f.changes && len(e.Changes) == 0 {
+			return false
+		} else if !f.changes && len(e.Response.Detections) == 0 {
+			return false
+		}
+	}
+
+	// Require a matching detection
+	if len(f.detect) > 0 && e.Clip == "" && e.Alert == "" && !f.match(e) {
+		return false
+	}
+
+	if f.quiet && !e.Urgent() && f.inQuietHours(e.Time) {
+		return false
+	}
+
+	if f.rateLimit > 0 && e.Clip == "" && !e.Urgent() {
+		if e.Time.Sub(f.lastSent) < f.rateLimit {
+			return false
+		}
+		f.lastSent = e.Time
+	}
+
+	return true
+
+}
+
+// match returns true if a detection meets the detect scores. Sinks for changes match on the changed labels.
+func (f *filter) match(e *Event) bool {
+	if f.changes {
+		for _, c := range e.Changes {
+			if _, ok := f.detect[c.Label]; ok {
+				return true
+			}
+			if _, ok := f.detect["*"]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	for _, d := range e.Response.Detections {
+		score, ok := f.detect[d.Label]
+		if !ok {
+			score, ok = f.detect["*"]
+		}
+		if ok && d.Confidence >= score {
+			return true
+		}
+	}
+	return false
+}
+
+// inQuietHours returns true if t is in the quiet hours, the window can wrap past midnight
+func (f *filter) inQuietHours(t time.Time) bool {
+	tod := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if f.quietStart <= f.quietEnd {
+		return tod >= f.quietStart && tod < f.quietEnd
+	}
+	return tod >= f.quietStart || tod < f.quietEnd
+}
@@ -0,0 +1,137 @@
+package sink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/snowzach/doods/sink/sinkconfig"
+	"github.com/snowzach/doods/state"
+)
+
+func TestFilterAllow(t *testing.T) {
+
+	person := testEvent("yard", 0, testDetection("person", 0.1, 80))
+	cat := testEvent("yard", 0, testDetection("cat", 0.1, 40))
+	empty := testEvent("yard", 0)
+	change := testEvent("yard", 0)
+	change.Changes = []*state.Change{{Label: "car", Type: state.Appeared, To: 1}}
+	alert := testEvent("yard", 0)
+	alert.Alert = "intruder"
+	clip := testEvent("yard", 0)
+	clip.Clip = "yard.mp4"
+	labeled := testEvent("yard", 0)
+	labeled.Labeled = true
+
+	for _, test := range []struct {
+		describe string
+		config   *sinkconfig.SinkConfig
+		event    *Event
+		allow    bool
+	}{
+		{"detections", &sinkconfig.SinkConfig{}, person, true},
+		{"no detections", &sinkconfig.SinkConfig{}, empty, false},
+		{"score met", &sinkconfig.SinkConfig{Detect: map[string]float32{"person": 70}}, person, true},
+		{"score not met", &sinkconfig.SinkConfig{Detect: map[string]float32{"person": 90}}, person, false},
+		{"other label", &sinkconfig.SinkConfig{Detect: map[string]float32{"person": 50}}, cat, false},
+		{"any label", &sinkconfig.SinkConfig{Detect: map[string]float32{"*": 30}}, cat, true},
+		{"any label score not met", &sinkconfig.SinkConfig{Detect: map[string]float32{"*": 50}}, cat, false},
+		{"changes only", &sinkconfig.SinkConfig{Changes: true}, person, false},
+		{"change", &sinkconfig.SinkConfig{Changes: true}, change, true},
+		{"change label", &sinkconfig.SinkConfig{Changes: true, Detect: map[string]float32{"car": 0}}, change, true},
+		{"change other label", &sinkconfig.SinkConfig{Changes: true, Detect: map[string]float32{"person": 0}}, change, false},
+		{"alerts only", &sinkconfig.SinkConfig{Alerts: true}, person, false},
+		{"alert", &sinkconfig.SinkConfig{Alerts: true}, alert, true},
+		{"alert skips detect", &sinkconfig.SinkConfig{Detect: map[string]float32{"person": 90}}, alert, true},
+		{"clip skips detect", &sinkconfig.SinkConfig{Detect: map[string]float32{"person": 90}}, clip, true},
+		{"labeled", &sinkconfig.SinkConfig{Detect: map[string]float32{"person": 90}}, labeled, true},
+	} {
+		f, err := newFilter(test.config)
+		if err != nil {
+			t.Fatalf("%s: %v", test.describe, err)
+		}
+		if allow := f.allow(test.event); allow != test.allow {
+			t.Errorf("%s: allow %v, expected %v", test.describe, allow, test.allow)
+		}
+	}
+
+}
+
+func TestFilterSources(t *testing.T) {
+
+	f, err := newFilter(&sinkconfig.SinkConfig{Sources: []string{"yard"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.source(testEvent("yard", 0)) || f.source(testEvent("door", 0)) {
+		t.Error("sources not filtered")
+	}
+	if f, _ = newFilter(&sinkconfig.SinkConfig{}); !f.source(testEvent("door", 0)) {
+		t.Error("no sources should allow any source")
+	}
+
+}
+
+func TestFilterRateLimit(t *testing.T) {
+
+	f, err := newFilter(&sinkconfig.SinkConfig{RateLimit: 10 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		at       int
+		priority string
+		clip     string
+		allow    bool
+	}{
+		{0, "", "", true},
+		{5, "", "", false},
+		{6, PriorityHigh, "", true}, // Urgent events are not limited
+		{7, "", "yard.mp4", true},   // Neither are clips
+		{9, "", "", false},
+		{10, "", "", true},
+	} {
+		e := testEvent("yard", test.at, testDetection("person", 0.1, 80))
+		e.Priority, e.Clip = test.priority, test.clip
+		if allow := f.allow(e); allow != test.allow {
+			t.Errorf("%d seconds: allow %v, expected %v", test.at, allow, test.allow)
+		}
+	}
+
+}
+
+func TestFilterQuietHours(t *testing.T) {
+
+	for _, test := range []struct {
+		start, end string
+		at         string
+		priority   string
+		allow      bool
+	}{
+		{"23:00", "06:00", "23:30", "", false},
+		{"23:00", "06:00", "03:00", "", false},
+		{"23:00", "06:00", "06:00", "", true},
+		{"23:00", "06:00", "12:00", "", true},
+		{"23:00", "06:00", "03:00", PriorityCritical, true},
+		{"09:00", "17:00", "12:00", "", false},
+		{"09:00", "17:00", "08:59", "", true},
+		{"22:00", "24:00", "23:59", "", false},
+		{"22:00", "24:00", "00:00", "", true},
+	} {
+		f, err := newFilter(&sinkconfig.SinkConfig{QuietHours: &sinkconfig.QuietHoursConfig{Start: test.start, End: test.end}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		at, _ := time.Parse("15:04", test.at)
+		e := testEvent("yard", 0, testDetection("person", 0.1, 80))
+		e.Time = time.Date(2020, 1, 1, at.Hour(), at.Minute(), 0, 0, time.UTC)
+		e.Priority = test.priority
+		if allow := f.allow(e); allow != test.allow {
+			t.Errorf("quiet %s-%s at %s: allow %v, expected %v", test.start, test.end, test.at, allow, test.allow)
+		}
+	}
+
+	if _, err := newFilter(&sinkconfig.SinkConfig{QuietHours: &sinkconfig.QuietHoursConfig{Start: "25:00", End: "06:00"}}); err == nil {
+		t.Error("expected an error for invalid quiet hours")
+	}
+
+}
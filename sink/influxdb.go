@@ -0,0 +1,110 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snowzach/doods/sink/sinkconfig"
+)
+
+// influxDB writes detection and label counts with the line protocol
+type influxDB struct {
+	config *sinkconfig.InfluxDBConfig
+	url    string
+	client *http.Client
+}
+
+func init() {
+	Register("influxdb", func(c *sinkconfig.SinkConfig) (Sink, error) {
+		if c.InfluxDB == nil {
+			return nil, fmt.Errorf("missing influxdb config")
+		}
+		return newInfluxDB(c.InfluxDB)
+	})
+}
+
+func newInfluxDB(c *sinkconfig.InfluxDBConfig) (*influxDB, error) {
+
+	if c.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if c.Measurement == "" {
+		c.Measurement = "doods"
+	}
+
+	// InfluxDB 2 uses the org and bucket, InfluxDB 1 the database
+	query := url.Values{"precision": []string{"ms"}}
+	var path string
+	if c.Bucket != "" {
+		path = "/api/v2/write"
+		query.Set("org", c.Org)
+		query.Set("bucket", c.Bucket)
+	} else if c.Database != "" {
+		path = "/write"
+		query.Set("db", c.Database)
+	} else {
+		return nil, fmt.Errorf("bucket or database is required")
+	}
+
+	return &influxDB{
+		config: c,
+		url:    strings.TrimRight(c.URL, "/") + path + "?" + query.Encode(),
+		client: &http.Client{Timeout: time.Minute},
+	}, nil
+
+}
+
+// Send writes <measurement>_detections with the total count and <measurement>_labels with the count of each label
+func (i *influxDB) Send(ctx context.Context, e *Event) error {
+
+	if !e.detectionEvent() {
+		return nil
+	}
+
+	ts := strconv.FormatInt(e.Time.UnixNano()/int64(time.Millisecond), 10)
+	tags := ",source=" + influxEscape(e.Source)
+	if e.Detector != "" {
+		tags += ",detector=" + influxEscape(e.Detector)
+	}
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "%s_detections%s count=%di %s\n", influxEscape(i.config.Measurement), tags, len(e.Response.Detections), ts)
+	for _, c := range e.labelCounts() {
+		fmt.Fprintf(&body, "%s_labels%s,label=%s count=%di,confidence=%g %s\n", influxEscape(i.config.Measurement), tags, influxEscape(c.Label), c.Count, c.MaxConfidence, ts)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, i.url, &body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if i.config.Token != "" {
+		req.Header.Set("Authorization", "Token "+i.config.Token)
+	} else if i.config.Username != "" {
+		req.SetBasicAuth(i.config.Username, i.config.Password)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("write failed: %s %s", resp.Status, string(msg))
+	}
+	return nil
+
+}
+
+// influxEscape escapes a measurement, tag key or tag value for the line protocol
+var influxEscape = strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`, "\n", "").Replace
@@ -0,0 +1,39 @@
+package sink
+
+import (
+	"sort"
+)
+
+// labelCount is the number of detections of a label in an event
+type labelCount struct {
+	Label         string
+	Count         int
+	MaxConfidence float32
+}
+
+// labelCounts returns the count and highest confidence of each label in the event sorted by label
+func (e *Event) labelCounts() []*labelCount {
+	counts := make(map[string]*labelCount)
+	for _, d := range e.Response.Detections {
+		c, ok := counts[d.Label]
+		if !ok {
+			c = &labelCount{Label: d.Label}
+			counts[d.Label] = c
+		}
+		c.Count++
+		if d.Confidence > c.MaxConfidence {
+			c.MaxConfidence = d.Confidence
+		}
+	}
+	ret := make([]*labelCount, 0, len(counts))
+	for _, c := range counts {
+		ret = append(ret, c)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Label < ret[j].Label })
+	return ret
+}
+
+// detectionEvent returns true if the event is a detection, not a clip, alert or labeled image
+func (e *Event) detectionEvent() bool {
+	return e.Clip == "" && e.Alert == "" && !e.Labeled && e.Response != nil
+}
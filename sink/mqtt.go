@@ -0,0 +1,202 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/snowzach/doods/sink/sinkconfig"
+)
+
+const (
+	defaultMQTTTopicTemplate       = `doods/{{.Source}}`
+	defaultMQTTAvailabilityTopic   = `doods/availability`
+	defaultMQTTHealthTopicTemplate = `doods/{{.Detector}}/health`
+	mqttTimeout                    = 30 * time.Second
+
+	mqttOnline  = "online"
+	mqttOffline = "offline"
+)
+
+// mqttSink publishes events as JSON to an MQTT broker
+type mqttSink struct {
+	config      *sinkconfig.MQTTConfig
+	topic       *template.Template
+	healthTopic *template.Template
+	client      *mqttClient
+	store       *s3
+
+	// The last published health of each detector
+	health     map[string]string
+	healthLock sync.Mutex
+	done       chan struct{}
+}
+
+func init() {
+	Register("mqtt", func(c *sinkconfig.SinkConfig) (Sink, error) {
+		if c.MQTT == nil {
+			return nil, fmt.Errorf("missing mqtt config")
+		}
+		return newMQTT(c.MQTT)
+	})
+}
+
+func newMQTT(c *sinkconfig.MQTTConfig) (*mqttSink, error) {
+
+	if c.Broker == "" {
+		return nil, fmt.Errorf("broker is required")
+	}
+	if c.Topic == "" {
+		c.Topic = defaultMQTTTopicTemplate
+	}
+	if c.ClientID == "" {
+		c.ClientID = "doods"
+	}
+
+	if c.AvailabilityTopic == "" {
+		c.AvailabilityTopic = defaultMQTTAvailabilityTopic
+	}
+	if c.HealthTopic == "" {
+		c.HealthTopic = defaultMQTTHealthTopicTemplate
+	}
+	if c.HealthInterval <= 0 {
+		c.HealthInterval = 30 * time.Second
+	}
+	if c.QoS > 2 {
+		return nil, fmt.Errorf("invalid qos: %d", c.QoS)
+	}
+
+	topic, err := template.New("topic").Parse(c.Topic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid topic template: %v", err)
+	}
+	healthTopic, err := template.New("health_topic").Parse(c.HealthTopic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid health topic template: %v", err)
+	}
+
+	m := &mqttSink{
+		config:      c,
+		topic:       topic,
+		healthTopic: healthTopic,
+		done:        make(chan struct{}),
+	}
+
+	// The broker publishes offline if the connection is lost, online is published on each connection. The client
+	// connects and reconnects in the background.
+	will := &mqttMessage{topic: c.AvailabilityTopic, qos: c.QoS, retain: true, payload: []byte(mqttOffline)}
+	if m.client, err = newMQTTClient(c.Broker, c.ClientID, c.Username, c.Password, will, m.onConnect); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+
+}
+
+// Send publishes the event and optionally the annotated image to <topic>/image
+func (m *mqttSink) Send(ctx context.Context, e *Event) error {
+
+	var topic bytes.Buffer
+	if err := m.topic.Execute(&topic, e); err != nil {
+		return fmt.Errorf("could not build topic: %v", err)
+	}
+	if !mqttTopicValid(topic.String()) {
+		return fmt.Errorf("invalid topic: %q", topic.String())
+	}
+
+	// Add the url of the stored image or clip instead of publishing the image
+	var fields map[string]interface{}
+	if m.store != nil {
+		var err error
+		if fields, err = m.store.urlFields(ctx, e); err != nil {
+			return fmt.Errorf("could not store image: %v", err)
+		}
+	}
+
+	payload, err := e.JSONWith(fields)
+	if err != nil {
+		return err
+	}
+	if err := m.publish(topic.String(), payload); err != nil {
+		return err
+	}
+
+	if m.config.Image && m.store == nil && e.Clip == "" {
+		image, err := e.Annotated()
+		if err != nil {
+			return fmt.Errorf("could not annotate image: %v", err)
+		}
+		return m.publish(topic.String()+"/image", image)
+	}
+
+	return nil
+
+}
+
+func (m *mqttSink) imageStoreName() string { return m.config.ImageStore }
+func (m *mqttSink) setImageStore(s *s3)    { m.store = s }
+
+// onConnect publishes online and the health of the detectors again after connecting
+func (m *mqttSink) onConnect(client *mqttClient) {
+	go client.publish(&mqttMessage{topic: m.config.AvailabilityTopic, qos: m.config.QoS, retain: true, payload: []byte(mqttOnline)}, mqttTimeout)
+	m.healthLock.Lock()
+	m.health = nil
+	m.healthLock.Unlock()
+}
+
+// SetHealth publishes the health of each detector when it changes
+func (m *mqttSink) SetHealth(fn HealthFunc) {
+	go func() {
+		ticker := time.NewTicker(m.config.HealthInterval)
+		defer ticker.Stop()
+		for {
+			m.publishHealth(fn())
+			select {
+			case <-m.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// publishHealth publishes the detectors with a changed health
+func (m *mqttSink) publishHealth(health map[string]string) {
+	if !m.client.connected() {
+		return
+	}
+	m.healthLock.Lock()
+	defer m.healthLock.Unlock()
+	if m.health == nil {
+		m.health = make(map[string]string)
+	}
+	for detector, h := range health {
+		if m.health[detector] == h {
+			continue
+		}
+		var topic bytes.Buffer
+		if err := m.healthTopic.Execute(&topic, struct{ Detector string }{detector}); err != nil {
+			continue
+		}
+		if m.client.publish(&mqttMessage{topic: topic.String(), qos: m.config.QoS, retain: true, payload: []byte(h)}, mqttTimeout) == nil {
+			m.health[detector] = h
+		}
+	}
+}
+
+// Close publishes offline and disconnects from the broker
+func (m *mqttSink) Close() error {
+	close(m.done)
+	if m.client.connected() {
+		m.client.publish(&mqttMessage{topic: m.config.AvailabilityTopic, qos: m.config.QoS, retain: true, payload: []byte(mqttOffline)}, time.Second)
+	}
+	m.client.close()
+	return nil
+}
+
+func (m *mqttSink) publish(topic string, payload []byte) error {
+	return m.client.publish(&mqttMessage{topic: topic, qos: m.config.QoS, retain: m.config.Retain, payload: payload}, mqttTimeout)
+}
@@ -0,0 +1,441 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// MQTT 3.1.1 packet types
+const (
+	mqttConnect    = 1
+	mqttConnAck    = 2
+	mqttPublish    = 3
+	mqttPubAck     = 4
+	mqttPubRec     = 5
+	mqttPubRel     = 6
+	mqttPubComp    = 7
+	mqttPingReq    = 12
+	mqttPingResp   = 13
+	mqttDisconnect = 14
+
+	mqttKeepAlive     = 30 * time.Second
+	mqttMaxBackoff    = time.Minute
+	mqttMaxPacketSize = 1 << 16 // Only acks are expected from the broker
+)
+
+// mqttConnAckErrors are the reasons the broker refused the connection
+var mqttConnAckErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// mqttMessage is a message to publish
+type mqttMessage struct {
+	topic   string
+	qos     byte
+	retain  bool
+	payload []byte
+}
+
+// mqttClient is a minimal MQTT 3.1.1 client that only publishes. It connects and reconnects in the background.
+type mqttClient struct {
+	broker    *url.URL
+	clientID  string
+	username  string
+	password  string
+	will      *mqttMessage
+	onConnect func(*mqttClient)
+
+	lock     sync.Mutex
+	conn     net.Conn
+	packetID uint16
+	inflight map[uint16]chan error
+	// Serializes writes to conn
+	writeLock sync.Mutex
+
+	done chan struct{}
+	stop sync.Once
+}
+
+// newMQTTClient checks the broker url (tcp://, ssl://, ws:// or wss://) and starts connecting
+func newMQTTClient(broker, clientID, username, password string, will *mqttMessage, onConnect func(*mqttClient)) (*mqttClient, error) {
+
+	u, err := url.Parse(broker)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broker: %v", err)
+	}
+	switch u.Scheme {
+	case "tcp", "mqtt", "ssl", "tls", "mqtts", "ws", "wss":
+	default:
+		return nil, fmt.Errorf("invalid broker scheme: %s", u.Scheme)
+	}
+	if will != nil && will.qos > 2 {
+		return nil, fmt.Errorf("invalid qos: %d", will.qos)
+	}
+
+	c := &mqttClient{
+		broker:    u,
+		clientID:  clientID,
+		username:  username,
+		password:  password,
+		will:      will,
+		onConnect: onConnect,
+		inflight:  make(map[uint16]chan error),
+		done:      make(chan struct{}),
+	}
+	go c.run()
+
+	return c, nil
+
+}
+
+// run connects and waits for the connection to fail, with a backoff between attempts
+func (c *mqttClient) run() {
+	backoff := time.Second
+	for {
+		conn, reader, err := c.dial()
+		if err == nil {
+			backoff = time.Second
+			c.lock.Lock()
+			c.conn = conn
+			c.lock.Unlock()
+
+			errs := make(chan error, 1)
+			go func() { errs <- c.read(conn, reader) }()
+			if c.onConnect != nil {
+				c.onConnect(c)
+			}
+			c.keepAlive(errs)
+			c.disconnected(conn)
+		}
+
+		select {
+		case <-c.done:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > mqttMaxBackoff {
+			backoff = mqttMaxBackoff
+		}
+	}
+}
+
+// dial opens the connection and sends CONNECT
+func (c *mqttClient) dial() (net.Conn, *bufio.Reader, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), mqttTimeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: mqttTimeout}
+	host := c.broker.Host
+	switch c.broker.Scheme {
+	case "tcp", "mqtt":
+		if c.broker.Port() == "" {
+			host = net.JoinHostPort(c.broker.Hostname(), "1883")
+		}
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	case "ssl", "tls", "mqtts":
+		if c.broker.Port() == "" {
+			host = net.JoinHostPort(c.broker.Hostname(), "8883")
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: c.broker.Hostname()})
+	case "ws", "wss":
+		var config *websocket.Config
+		origin := "http://" + c.broker.Host
+		if c.broker.Scheme == "wss" {
+			origin = "https://" + c.broker.Host
+		}
+		if config, err = websocket.NewConfig(c.broker.String(), origin); err != nil {
+			return nil, nil, err
+		}
+		config.Protocol = []string{"mqtt"}
+		config.Dialer = dialer
+		var ws *websocket.Conn
+		if ws, err = websocket.DialConfig(config); err == nil {
+			ws.PayloadType = websocket.BinaryFrame
+			conn = ws
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn.SetDeadline(time.Now().Add(mqttTimeout))
+	reader := bufio.NewReader(conn)
+	if err = c.connect(conn, reader); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	return conn, reader, nil
+
+}
+
+// connect sends CONNECT and waits for CONNACK
+func (c *mqttClient) connect(conn net.Conn, reader *bufio.Reader) error {
+
+	var flags byte = 0x02 // Clean session
+	body := mqttString(nil, "MQTT")
+	body = append(body, 4) // Protocol level 3.1.1
+	flagsAt := len(body)
+	body = append(body, 0)
+	keepAlive := uint16(mqttKeepAlive / time.Second)
+	body = append(body, byte(keepAlive>>8), byte(keepAlive))
+	body = mqttString(body, c.clientID)
+	if c.will != nil {
+		flags |= 0x04 | c.will.qos<<3
+		if c.will.retain {
+			flags |= 0x20
+		}
+		body = mqttString(body, c.will.topic)
+		body = mqttString(body, string(c.will.payload))
+	}
+	if c.username != "" {
+		flags |= 0x80
+		body = mqttString(body, c.username)
+		if c.password != "" {
+			flags |= 0x40
+			body = mqttString(body, c.password)
+		}
+	}
+	body[flagsAt] = flags
+
+	if _, err := conn.Write(mqttPacket(mqttConnect<<4, body)); err != nil {
+		return err
+	}
+
+	header, ack, err := readMQTTPacket(reader)
+	if err != nil {
+		return err
+	}
+	if header>>4 != mqttConnAck || len(ack) != 2 {
+		return fmt.Errorf("expected CONNACK from broker")
+	}
+	if ack[1] != 0 {
+		if reason, ok := mqttConnAckErrors[ack[1]]; ok {
+			return fmt.Errorf("broker refused connection: %s", reason)
+		}
+		return fmt.Errorf("broker refused connection: %d", ack[1])
+	}
+	return nil
+
+}
+
+// read handles the acks from the broker until the connection fails, the pings get a response before the deadline
+func (c *mqttClient) read(conn net.Conn, reader *bufio.Reader) error {
+	for {
+		conn.SetReadDeadline(time.Now().Add(mqttKeepAlive * 3 / 2))
+		header, body, err := readMQTTPacket(reader)
+		if err != nil {
+			return err
+		}
+		switch header >> 4 {
+		case mqttPubAck, mqttPubComp:
+			if len(body) < 2 {
+				return fmt.Errorf("invalid ack from broker")
+			}
+			c.acked(binary.BigEndian.Uint16(body), nil)
+		case mqttPubRec:
+			if len(body) < 2 {
+				return fmt.Errorf("invalid ack from broker")
+			}
+			if err = c.write(mqttPacket(mqttPubRel<<4|0x02, body[:2])); err != nil {
+				return err
+			}
+		case mqttPingResp:
+		default:
+			return fmt.Errorf("unexpected packet type %d from broker", header>>4)
+		}
+	}
+}
+
+// keepAlive pings the broker until the reader fails or the client is closed
+func (c *mqttClient) keepAlive(errs chan error) {
+	ticker := time.NewTicker(mqttKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			c.write(mqttPacket(mqttDisconnect<<4, nil))
+			return
+		case <-errs:
+			return
+		case <-ticker.C:
+			if err := c.write(mqttPacket(mqttPingReq<<4, nil)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// disconnected closes the connection and fails the messages waiting for an ack
+func (c *mqttClient) disconnected(conn net.Conn) {
+	conn.Close()
+	c.lock.Lock()
+	c.conn = nil
+	inflight := c.inflight
+	c.inflight = make(map[uint16]chan error)
+	c.lock.Unlock()
+	for _, ack := range inflight {
+		ack <- fmt.Errorf("connection lost")
+	}
+}
+
+// acked completes the message with the packet id
+func (c *mqttClient) acked(id uint16, err error) {
+	c.lock.Lock()
+	ack, ok := c.inflight[id]
+	delete(c.inflight, id)
+	c.lock.Unlock()
+	if ok {
+		ack <- err
+	}
+}
+
+// write sends the packet on the current connection
+func (c *mqttClient) write(packet []byte) error {
+	c.lock.Lock()
+	conn := c.conn
+	c.lock.Unlock()
+	if conn == nil {
+		return fmt.Errorf("not connected to %s", c.broker.Host)
+	}
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(mqttTimeout))
+	_, err := conn.Write(packet)
+	return err
+}
+
+// connected returns true if there is a connection to the broker
+func (c *mqttClient) connected() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.conn != nil
+}
+
+// publish sends the message and waits up to timeout for the broker to acknowledge it if qos > 0
+func (c *mqttClient) publish(m *mqttMessage, timeout time.Duration) error {
+
+	if m.qos > 2 {
+		return fmt.Errorf("invalid qos: %d", m.qos)
+	}
+	header := byte(mqttPublish<<4) | m.qos<<1
+	if m.retain {
+		header |= 0x01
+	}
+	body := mqttString(nil, m.topic)
+
+	var ack chan error
+	if m.qos > 0 {
+		ack = make(chan error, 1)
+		c.lock.Lock()
+		if c.packetID++; c.packetID == 0 {
+			c.packetID = 1
+		}
+		id := c.packetID
+		c.inflight[id] = ack
+		c.lock.Unlock()
+		body = append(body, byte(id>>8), byte(id))
+		defer c.acked(id, nil)
+	}
+	body = append(body, m.payload...)
+
+	if err := c.write(mqttPacket(header, body)); err != nil {
+		return err
+	}
+	if ack == nil {
+		return nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-ack:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("timeout publishing to %s", m.topic)
+	}
+
+}
+
+// close sends DISCONNECT, the broker doesn't publish the will
+func (c *mqttClient) close() {
+	c.stop.Do(func() { close(c.done) })
+}
+
+// mqttString appends the length prefixed string
+func mqttString(b []byte, s string) []byte {
+	b = append(b, byte(len(s)>>8), byte(len(s)))
+	return append(b, s...)
+}
+
+// mqttPacket returns the packet with the fixed header
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	n := len(body)
+	for {
+		digit := byte(n % 128)
+		if n /= 128; n > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if n == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// readMQTTPacket reads the fixed header and the body of a packet
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var n, shift int
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, fmt.Errorf("invalid packet length")
+		}
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(digit&0x7f) << shift
+		shift += 7
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+	if n > mqttMaxPacketSize {
+		return 0, nil, fmt.Errorf("packet too large: %d", n)
+	}
+	body := make([]byte, n)
+	if _, err = io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+// mqttTopicValid returns false if the topic can't be published to
+func mqttTopicValid(topic string) bool {
+	return topic != "" && len(topic) <= 65535 && !strings.ContainsAny(topic, "+#\x00")
+}
@@ -0,0 +1,147 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestMQTTPacketLength(t *testing.T) {
+
+	for _, n := range []int{0, 1, 127, 128, 16383, 16384, mqttMaxPacketSize} {
+		body := bytes.Repeat([]byte{'x'}, n)
+		header, decoded, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(mqttPacket(mqttPublish<<4, body))))
+		if err != nil {
+			t.Fatalf("length %d: %v", n, err)
+		}
+		if header != mqttPublish<<4 || len(decoded) != n {
+			t.Errorf("length %d decoded as header %x length %d", n, header, len(decoded))
+		}
+	}
+
+	// Too large and more than 4 length bytes
+	for _, packet := range [][]byte{
+		mqttPacket(mqttPublish<<4, make([]byte, mqttMaxPacketSize+1)),
+		{mqttPublish << 4, 0xff, 0xff, 0xff, 0xff, 0x01},
+	} {
+		if _, _, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(packet))); err == nil {
+			t.Errorf("expected an error for %x", packet[:5])
+		}
+	}
+
+}
+
+func TestMQTTClient(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// The broker checks CONNECT, acks a QoS 1 and a QoS 2 publish and forwards the messages
+	messages := make(chan *mqttMessage, 10)
+	connects := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			header, body, err := readMQTTPacket(reader)
+			if err != nil {
+				return
+			}
+			switch header >> 4 {
+			case mqttConnect:
+				connects <- body
+				conn.Write(mqttPacket(mqttConnAck<<4, []byte{0, 0}))
+			case mqttPublish:
+				m := &mqttMessage{qos: header >> 1 & 0x03, retain: header&0x01 != 0}
+				n := int(binary.BigEndian.Uint16(body))
+				m.topic = string(body[2 : 2+n])
+				body = body[2+n:]
+				if m.qos > 0 {
+					id := body[:2]
+					body = body[2:]
+					if m.qos == 1 {
+						conn.Write(mqttPacket(mqttPubAck<<4, id))
+					} else {
+						conn.Write(mqttPacket(mqttPubRec<<4, id))
+					}
+				}
+				m.payload = body
+				messages <- m
+			case mqttPubRel:
+				conn.Write(mqttPacket(mqttPubComp<<4, body))
+			}
+		}
+	}()
+
+	connected := make(chan struct{}, 1)
+	will := &mqttMessage{topic: "doods/availability", qos: 1, retain: true, payload: []byte(mqttOffline)}
+	c, err := newMQTTClient("tcp://"+listener.Addr().String(), "doods", "user", "secret", will, func(*mqttClient) { connected <- struct{}{} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.close()
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout connecting")
+	}
+
+	connect := <-connects
+	expected := mqttString(nil, "MQTT")
+	expected = append(expected, 4, 0x80|0x40|0x20|0x08|0x04|0x02, 0, 30)
+	for _, s := range []string{"doods", "doods/availability", mqttOffline, "user", "secret"} {
+		expected = mqttString(expected, s)
+	}
+	if !bytes.Equal(connect, expected) {
+		t.Errorf("CONNECT was %x, expected %x", connect, expected)
+	}
+
+	for _, m := range []*mqttMessage{
+		{topic: "doods/camera", qos: 0, payload: []byte("zero")},
+		{topic: "doods/camera", qos: 1, retain: true, payload: []byte("one")},
+		{topic: "doods/camera", qos: 2, payload: []byte("two")},
+	} {
+		if err := c.publish(m, 5*time.Second); err != nil {
+			t.Fatalf("qos %d: %v", m.qos, err)
+		}
+		select {
+		case received := <-messages:
+			if received.topic != m.topic || received.qos != m.qos || received.retain != m.retain || !bytes.Equal(received.payload, m.payload) {
+				t.Errorf("received %+v, expected %+v", received, m)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("qos %d: timeout receiving", m.qos)
+		}
+	}
+
+	if err := c.publish(&mqttMessage{topic: "doods/camera", qos: 3}, time.Second); err == nil {
+		t.Error("expected an error for qos 3")
+	}
+
+}
+
+func TestMQTTTopicValid(t *testing.T) {
+
+	for topic, valid := range map[string]bool{
+		"doods/camera":   true,
+		"doods/+/camera": false,
+		"doods/#":        false,
+		"":               false,
+	} {
+		if mqttTopicValid(topic) != valid {
+			t.Errorf("%q valid should be %v", topic, valid)
+		}
+	}
+
+}
@@ -0,0 +1,249 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/snowzach/doods/sink/sinkconfig"
+	"github.com/snowzach/doods/state"
+)
+
+var notifyClient = &http.Client{Timeout: time.Minute}
+
+func init() {
+	Register("telegram", func(c *sinkconfig.SinkConfig) (Sink, error) {
+		if c.Telegram == nil {
+			return nil, fmt.Errorf("missing telegram config")
+		}
+		return newTelegram(c.Telegram)
+	})
+	Register("pushover", func(c *sinkconfig.SinkConfig) (Sink, error) {
+		if c.Pushover == nil {
+			return nil, fmt.Errorf("missing pushover config")
+		}
+		return newPushover(c.Pushover)
+	})
+	Register("ntfy", func(c *sinkconfig.SinkConfig) (Sink, error) {
+		if c.Ntfy == nil {
+			return nil, fmt.Errorf("missing ntfy config")
+		}
+		return newNtfy(c.Ntfy)
+	})
+}
+
+// Summary returns a short description of the event for notifications
+func (e *Event) Summary() string {
+	var b strings.Builder
+	if e.Alert != "" {
+		fmt.Fprintf(&b, "%s: ", e.Alert)
+	}
+	if len(e.Changes) > 0 {
+		for i, c := range e.Changes {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			switch c.Type {
+			case state.Appeared, state.Left:
+				fmt.Fprintf(&b, "%s %s", c.Label, c.Type)
+			default:
+				fmt.Fprintf(&b, "%s %d → %d", c.Label, c.From, c.To)
+			}
+		}
+		fmt.Fprintf(&b, " on %s", e.Source)
+		return b.String()
+	}
+	for i, d := range e.Response.Detections {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s %.0f%%", d.Label, d.Confidence)
+	}
+	fmt.Fprintf(&b, " on %s", e.Source)
+	return b.String()
+}
+
+// telegram sends the snapshot to a Telegram chat with a bot
+type telegram struct {
+	config *sinkconfig.TelegramConfig
+}
+
+func newTelegram(c *sinkconfig.TelegramConfig) (*telegram, error) {
+	if c.Token == "" || c.ChatID == "" {
+		return nil, fmt.Errorf("token and chatId are required")
+	}
+	if c.URL == "" {
+		c.URL = "https://api.telegram.org"
+	}
+	return &telegram{config: c}, nil
+}
+
+func (t *telegram) Send(ctx context.Context, e *Event) error {
+	if e.Clip != "" {
+		return nil
+	}
+	image, err := e.Annotated()
+	if err != nil {
+		return fmt.Errorf("could not annotate image: %v", err)
+	}
+	return postMultipart(ctx, t.config.URL+"/bot"+t.config.Token+"/sendPhoto", map[string]string{
+		"chat_id": t.config.ChatID,
+		"caption": e.Summary(),
+	}, "photo", image)
+}
+
+// pushover sends the snapshot with Pushover
+type pushover struct {
+	config *sinkconfig.PushoverConfig
+}
+
+func newPushover(c *sinkconfig.PushoverConfig) (*pushover, error) {
+	if c.Token == "" || c.User == "" {
+		return nil, fmt.Errorf("token and user are required")
+	}
+	if c.URL == "" {
+		c.URL = "https://api.pushover.net/1/messages.json"
+	}
+	return &pushover{config: c}, nil
+}
+
+func (p *pushover) Send(ctx context.Context, e *Event) error {
+	if e.Clip != "" {
+		return nil
+	}
+	image, err := e.Annotated()
+	if err != nil {
+		return fmt.Errorf("could not annotate image: %v", err)
+	}
+	fields := map[string]string{
+		"token":   p.config.Token,
+		"user":    p.config.User,
+		"title":   "DOODS " + e.Source,
+		"message": e.Summary(),
+	}
+	priority := p.config.Priority
+	switch {
+	case e.Priority == PriorityCritical:
+		// Emergency priority repeats until it's acknowledged
+		priority = 2
+		fields["retry"] = "60"
+		fields["expire"] = "3600"
+	case e.Priority == PriorityHigh && priority < 1:
+		priority = 1
+	}
+	if priority != 0 {
+		fields["priority"] = fmt.Sprint(priority)
+	}
+	if p.config.Sound != "" {
+		fields["sound"] = p.config.Sound
+	}
+	return postMultipart(ctx, p.config.URL, fields, "attachment", image)
+}
+
+// ntfy sends the snapshot to an ntfy topic
+type ntfy struct {
+	config *sinkconfig.NtfyConfig
+}
+
+func newNtfy(c *sinkconfig.NtfyConfig) (*ntfy, error) {
+	if c.Topic == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+	if c.URL == "" {
+		c.URL = "https://ntfy.sh"
+	}
+	return &ntfy{config: c}, nil
+}
+
+func (n *ntfy) Send(ctx context.Context, e *Event) error {
+	if e.Clip != "" {
+		return nil
+	}
+	image, err := e.Annotated()
+	if err != nil {
+		return fmt.Errorf("could not annotate image: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, strings.TrimSuffix(n.config.URL, "/")+"/"+n.config.Topic, bytes.NewReader(image))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Filename", e.Source+".jpg")
+	req.Header.Set("Title", "DOODS "+e.Source)
+	req.Header.Set("Message", e.Summary())
+	req.Header.Set("Tags", strings.Join(e.Labels(), ","))
+	priority := n.config.Priority
+	switch e.Priority {
+	case PriorityCritical:
+		priority = "urgent"
+	case PriorityHigh:
+		if ntfyPriorities[priority] < ntfyPriorities["high"] {
+			priority = "high"
+		}
+	}
+	if priority != "" {
+		req.Header.Set("Priority", priority)
+	}
+	if n.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.config.Token)
+	}
+
+	return doNotify(req)
+}
+
+// The ntfy priorities by name and number
+var ntfyPriorities = map[string]int{
+	"min": 1, "1": 1, "low": 2, "2": 2, "default": 3, "3": 3, "high": 4, "4": 4, "max": 5, "urgent": 5, "5": 5,
+}
+
+// postMultipart posts the fields and an image as a multipart form
+func postMultipart(ctx context.Context, url string, fields map[string]string, fileField string, image []byte) error {
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			return err
+		}
+	}
+	fw, err := w.CreateFormFile(fileField, "snapshot.jpg")
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(image); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, &body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	return doNotify(req)
+
+}
+
+func doNotify(req *http.Request) error {
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("notification failed: %s %s", resp.Status, string(body))
+	}
+	return nil
+}
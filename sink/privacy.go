@@ -0,0 +1,42 @@
+package sink
+
+import (
+	"sync"
+
+	config "github.com/spf13/viper"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+var (
+	faceLabels     map[string]struct{}
+	faceLabelsOnce sync.Once
+)
+
+// Private returns true if the detections have a face (doods.privacy.face_labels) and doods.privacy.persist_faces
+// isn't set, then the image must not be stored. The detections should be the ones before the labels are translated.
+func Private(detections []*odrpc.Detection) bool {
+	faceLabelsOnce.Do(func() {
+		if config.GetBool("doods.privacy.persist_faces") {
+			return
+		}
+		faceLabels = make(map[string]struct{})
+		for _, label := range config.GetStringSlice("doods.privacy.face_labels") {
+			faceLabels[label] = struct{}{}
+		}
+	})
+	return hasFace(detections)
+}
+
+// hasFace checks the detections and the detections of the cascade stages nested in them
+func hasFace(detections []*odrpc.Detection) bool {
+	for _, d := range detections {
+		if _, ok := faceLabels[d.Label]; ok {
+			return true
+		}
+		if hasFace(d.Children) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/snowzach/doods/sink/sinkconfig"
+)
+
+const (
+	defaultS3KeyTemplate     = `{{.Source}}/{{.Time.Format "2006/01/02"}}/{{.Time.Format "150405.000"}}-{{.ID}}.jpg`
+	defaultS3ClipKeyTemplate = `{{.Source}}/{{.Time.Format "2006/01/02"}}/{{.Time.Format "150405.000"}}-{{.ID}}.mp4`
+)
+
+// s3 uploads the annotated image to S3 compatible storage
+type s3 struct {
+	config  *sinkconfig.S3Config
+	key     *template.Template
+	clipKey *template.Template
+	client  *http.Client
+}
+
+func init() {
+	Register("s3", func(c *sinkconfig.SinkConfig) (Sink, error) {
+		if c.S3 == nil {
+			return nil, fmt.Errorf("missing s3 config")
+		}
+		return newS3(c.S3)
+	})
+}
+
+func newS3(c *sinkconfig.S3Config) (*s3, error) {
+
+	if c.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if c.Region == "" {
+		c.Region = "us-east-1"
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = "s3." + c.Region + ".amazonaws.com"
+	}
+	if c.KeyTemplate == "" {
+		c.KeyTemplate = defaultS3KeyTemplate
+	}
+
+	if c.ClipKeyTemplate == "" {
+		c.ClipKeyTemplate = defaultS3ClipKeyTemplate
+	}
+	if c.PresignExpiry <= 0 {
+		c.PresignExpiry = 24 * time.Hour
+	} else if c.PresignExpiry > 7*24*time.Hour {
+		c.PresignExpiry = 7 * 24 * time.Hour
+	}
+
+	key, err := template.New("key").Parse(c.KeyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key template: %v", err)
+	}
+	clipKey, err := template.New("clip_key").Parse(c.ClipKeyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clip key template: %v", err)
+	}
+
+	return &s3{
+		config:  c,
+		key:     key,
+		clipKey: clipKey,
+		client:  &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+
+}
+
+// Send uploads the annotated image or the clip for clip events
+func (s *s3) Send(ctx context.Context, e *Event) error {
+	if s.config.StoreOnly || e.Private {
+		return nil
+	}
+	_, err := s.upload(ctx, e)
+	return err
+}
+
+// upload uploads the annotated image or the clip once per event and returns the key
+func (s *s3) upload(ctx context.Context, e *Event) (string, error) {
+
+	e.uploadLock.Lock()
+	defer e.uploadLock.Unlock()
+	if key, ok := e.uploads[s]; ok {
+		return key, nil
+	}
+
+	var key bytes.Buffer
+	if e.Clip != "" {
+		data, err := ioutil.ReadFile(e.Clip)
+		if err != nil {
+			return "", fmt.Errorf("could not read clip: %v", err)
+		}
+		if err := s.clipKey.Execute(&key, e); err != nil {
+			return "", fmt.Errorf("could not build clip key: %v", err)
+		}
+		if err = s.put(ctx, key.String(), "video/mp4", data); err != nil {
+			return "", err
+		}
+	} else {
+		data, err := e.Annotated()
+		if err != nil {
+			return "", fmt.Errorf("could not annotate image: %v", err)
+		}
+		if err := s.key.Execute(&key, e); err != nil {
+			return "", fmt.Errorf("could not build key: %v", err)
+		}
+		if err = s.put(ctx, key.String(), "image/jpeg", data); err != nil {
+			return "", err
+		}
+	}
+
+	if e.uploads == nil {
+		e.uploads = make(map[*s3]string)
+	}
+	e.uploads[s] = key.String()
+	return key.String(), nil
+
+}
+
+// urlFields uploads the image or clip and returns the pre-signed image_url or clip_url field for the event
+func (s *s3) urlFields(ctx context.Context, e *Event) (map[string]interface{}, error) {
+	if e.Private {
+		return nil, nil
+	}
+	key, err := s.upload(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	field := "image_url"
+	if e.Clip != "" {
+		field = "clip_url"
+	}
+	return map[string]interface{}{field: s.presign(key, time.Now().UTC())}, nil
+}
+
+// location returns the scheme, host and escaped path of an object
+func (s *s3) location(key string) (string, string, string) {
+	scheme := "https"
+	if s.config.Insecure {
+		scheme = "http"
+	}
+	host := s.config.Endpoint
+	path := "/" + escapePath(strings.TrimPrefix(key, "/"))
+	if s.config.PathStyle {
+		path = "/" + s.config.Bucket + path
+	} else {
+		host = s.config.Bucket + "." + host
+	}
+	return scheme, host, path
+}
+
+// presign returns a url to get the object without credentials until the expiry, AWS signature version 4 in the query
+func (s *s3) presign(key string, now time.Time) string {
+
+	scheme, host, path := s.location(key)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+
+	// The parameters sorted by name
+	query := "X-Amz-Algorithm=AWS4-HMAC-SHA256" +
+		"&X-Amz-Credential=" + escapeQuery(s.config.AccessKey+"/"+scope) +
+		"&X-Amz-Date=" + amzDate +
+		"&X-Amz-Expires=" + strconv.Itoa(int(s.config.PresignExpiry/time.Second)) +
+		"&X-Amz-SignedHeaders=host"
+
+	canonicalRequest := strings.Join([]string{http.MethodGet, path, query, "host:" + host + "\n", "host", "UNSIGNED-PAYLOAD"}, "\n")
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(date), stringToSign))
+
+	return scheme + "://" + host + path + "?" + query + "&X-Amz-Signature=" + signature
+
+}
+
+// put uploads an object using AWS signature version 4
+func (s *s3) put(ctx context.Context, key string, contentType string, data []byte) error {
+
+	scheme, host, path := s.location(key)
+
+	req, err := http.NewRequest(http.MethodPut, scheme+"://"+host+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, host, path, data, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("upload %s failed: %s %s", key, resp.Status, string(body))
+	}
+
+	return nil
+
+}
+
+// sign adds the AWS signature version 4 headers
+func (s *s3) sign(req *http.Request, host string, path string, payload []byte, now time.Time) {
+
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("Host", host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{req.Method, path, "", canonicalHeaders, signedHeaders, payloadHash}, "\n")
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signature := hex.EncodeToString(hmacSHA256(s.signingKey(date), stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.config.AccessKey+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+
+}
+
+// signingKey returns the AWS signature version 4 key for the date
+func (s *s3) signingKey(date string) []byte {
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	return hmacSHA256(key, "aws4_request")
+}
+
+// escapePath escapes each segment of the path, everything but the unreserved characters are encoded
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// escapeQuery escapes a query value, slashes too
+func escapeQuery(value string) string {
+	return strings.Replace(escapePath(value), "/", "%2F", -1)
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,346 @@
+// Package sink sends detection events to external services
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/detector/annotate"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/sink/sinkconfig"
+	"github.com/snowzach/doods/state"
+)
+
+const queueSize = 100
+
+// Priorities for alerts, they skip the quiet hours and rate limits and are sent as urgent notifications
+const (
+	PriorityHigh     = "high"
+	PriorityCritical = "critical"
+)
+
+// Event is a detection with results
+type Event struct {
+	Time time.Time
+	// The request ID
+	ID string
+	// The detector or stream name
+	Source   string
+	Detector string
+	// The original image data
+	Image    []byte
+	Response *odrpc.DetectResponse
+	// The filename of a recorded clip for clip events
+	Clip string
+	// The alert name for alert events
+	Alert string
+	// PriorityHigh or PriorityCritical for urgent alerts, blank for normal
+	Priority string
+	// Confirmed changes in the objects for the source
+	Changes []*state.Change
+	// The detections were labeled by a person for the dataset
+	Labeled bool
+	// The number of duplicate events dropped for the source since the last one
+	Duplicates int
+	// Only send to these sinks, all if empty
+	Sinks []string
+	// The image has a face and must not be saved, see Private
+	Private bool
+
+	annotateOnce sync.Once
+	annotated    []byte
+	annotateErr  error
+
+	// The keys the image or clip was stored with by each s3 sink
+	uploads    map[*s3]string
+	uploadLock sync.Mutex
+}
+
+// Annotated returns the image as a JPEG with the detections drawn. It is only rendered once per event.
+func (e *Event) Annotated() ([]byte, error) {
+	e.annotateOnce.Do(func() {
+		e.annotated, e.annotateErr = annotate.Image(e.Image, e.Response.Detections, 0)
+	})
+	return e.annotated, e.annotateErr
+}
+
+// Urgent returns true if the event has a high or critical priority
+func (e *Event) Urgent() bool {
+	return e.Priority == PriorityHigh || e.Priority == PriorityCritical
+}
+
+// Labels returns the detected labels
+func (e *Event) Labels() []string {
+	labels := make([]string, 0, len(e.Response.Detections))
+	for _, d := range e.Response.Detections {
+		labels = append(labels, d.Label)
+	}
+	return labels
+}
+
+// JSON returns the event as JSON without the image
+func (e *Event) JSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Time       time.Time          `json:"time"`
+		ID         string             `json:"id"`
+		Source     string             `json:"source"`
+		Detector   string             `json:"detector"`
+		Detections []*odrpc.Detection `json:"detections"`
+		Clip       string             `json:"clip,omitempty"`
+		Alert      string             `json:"alert,omitempty"`
+		Priority   string             `json:"priority,omitempty"`
+		Changes    []*state.Change    `json:"changes,omitempty"`
+		Labeled    bool               `json:"labeled,omitempty"`
+		Duplicates int                `json:"duplicates,omitempty"`
+		Signature  *odrpc.Signature   `json:"signature,omitempty"`
+	}{
+		Time:       e.Time,
+		ID:         e.ID,
+		Source:     e.Source,
+		Detector:   e.Detector,
+		Detections: e.Response.Detections,
+		Clip:       e.Clip,
+		Alert:      e.Alert,
+		Priority:   e.Priority,
+		Changes:    e.Changes,
+		Labeled:    e.Labeled,
+		Duplicates: e.Duplicates,
+		Signature:  e.Response.Signature,
+	})
+}
+
+// JSONWith returns the event as JSON with extra fields
+func (e *Event) JSONWith(fields map[string]interface{}) ([]byte, error) {
+	body, err := e.JSON()
+	if err != nil || len(fields) == 0 {
+		return body, err
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(body, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range fields {
+		all[k] = v
+	}
+	return json.Marshal(all)
+}
+
+// Sink receives detection events. If it also implements io.Closer it's closed on shutdown.
+type Sink interface {
+	Send(ctx context.Context, e *Event) error
+}
+
+// imageStoreUser is a sink that stores images in an s3 sink and sends urls
+type imageStoreUser interface {
+	imageStoreName() string
+	setImageStore(s *s3)
+}
+
+// HealthFunc returns the health of each detector by name
+type HealthFunc func() map[string]string
+
+// HealthReporter is a sink that reports the health of the detectors
+type HealthReporter interface {
+	SetHealth(fn HealthFunc)
+}
+
+// Factory creates a sink from the config
+type Factory func(c *sinkconfig.SinkConfig) (Sink, error)
+
+var factories = make(map[string]Factory)
+
+// Register adds a sink type. Sinks register themselves in init, other packages can add sinks by registering
+// before the sinks are created and reading their settings with SinkConfig.DecodeOptions.
+func Register(sinkType string, factory Factory) {
+	if _, ok := factories[sinkType]; ok {
+		panic("sink type registered twice: " + sinkType)
+	}
+	factories[sinkType] = factory
+}
+
+// queue runs a sink in its own goroutine so a slow sink doesn't block the others
+type queue struct {
+	name   string
+	sink   Sink
+	filter *filter
+	events chan *Event
+}
+
+// Manager sends events to the configured sinks
+type Manager struct {
+	queues []*queue
+	dedupe *dedupe
+	logger *zap.SugaredLogger
+}
+
+// New creates and starts the configured sinks
+func New(lc *conf.Lifecycle) *Manager {
+
+	m := &Manager{
+		dedupe: newDedupe(config.GetDuration("doods.dedupe.window"), float32(config.GetFloat64("doods.dedupe.iou"))),
+		logger: zap.S().With("package", "sink"),
+	}
+
+	// Get the sinks config
+	var sinkConfig []*sinkconfig.SinkConfig
+	config.UnmarshalKey("doods.sinks", &sinkConfig)
+
+	for _, c := range sinkConfig {
+		s, err := newSink(c)
+		if err != nil {
+			m.logger.Errorf("Could not configure sink %s: %v", c.Name, err)
+			continue
+		}
+
+		f, err := newFilter(c)
+		if err != nil {
+			m.logger.Errorf("Could not configure sink %s: %v", c.Name, err)
+			continue
+		}
+
+		q := &queue{
+			name:   c.Name,
+			sink:   s,
+			filter: f,
+			events: make(chan *Event, queueSize),
+		}
+		m.queues = append(m.queues, q)
+
+		lc.Go(func(ctx context.Context) { m.run(ctx, q) })
+
+		m.logger.Infow("Configured Sink", "name", c.Name, "type", c.Type)
+	}
+
+	// Link the sinks that store their images in an s3 sink
+	for _, q := range m.queues {
+		user, ok := q.sink.(imageStoreUser)
+		if !ok || user.imageStoreName() == "" {
+			continue
+		}
+		var store *s3
+		for _, sq := range m.queues {
+			if sq.name == user.imageStoreName() {
+				store, _ = sq.sink.(*s3)
+			}
+		}
+		if store == nil {
+			m.logger.Errorw("Image store is not an s3 sink, sending images", "sink", q.name, "image_store", user.imageStoreName())
+			continue
+		}
+		user.setImageStore(store)
+	}
+
+	return m
+
+}
+
+// newSink creates a sink from the config with the registered factory
+func newSink(c *sinkconfig.SinkConfig) (Sink, error) {
+	factory, ok := factories[c.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown sink type: %s", c.Type)
+	}
+	return factory(c)
+}
+
+// run sends events to the sink until stopped
+func (m *Manager) run(ctx context.Context, q *queue) {
+	for {
+		select {
+		case <-ctx.Done():
+			if closer, ok := q.sink.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					m.logger.Warnw("Could not close sink", "sink", q.name, "error", err)
+				}
+			}
+			return
+		case e := <-q.events:
+			if !q.filter.allow(e) {
+				continue
+			}
+			sendCtx, cancel := context.WithTimeout(ctx, time.Minute)
+			if err := q.sink.Send(sendCtx, e); err != nil {
+				m.logger.Errorw("Sink error", "sink", q.name, "id", e.ID, "source", e.Source, "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// SetHealth passes the detector health to the sinks that report it
+func (m *Manager) SetHealth(fn HealthFunc) {
+	if m == nil {
+		return
+	}
+	for _, q := range m.queues {
+		if reporter, ok := q.sink.(HealthReporter); ok {
+			reporter.SetHealth(fn)
+		}
+	}
+}
+
+// Send queues the event for all the sinks. If a sink is behind, the event is dropped for that sink.
+func (m *Manager) Send(e *Event) {
+	if m == nil {
+		return
+	}
+	if !m.dedupe.check(e) {
+		return
+	}
+	for _, q := range m.queues {
+		if !q.filter.source(e) || !e.sendTo(q.name) {
+			continue
+		}
+		select {
+		case q.events <- e:
+		default:
+			m.logger.Warnw("Sink queue full, dropping event", "sink", q.name, "id", e.ID, "source", e.Source)
+		}
+	}
+}
+
+// sendTo returns true if the event should go to the named sink
+func (e *Event) sendTo(name string) bool {
+	if len(e.Sinks) == 0 {
+		return true
+	}
+	for _, s := range e.Sinks {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+type sourceKey struct{}
+type sinksKey struct{}
+
+// WithSource sets the source name for events from detections using the context
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SourceFromContext returns the source name set with WithSource
+func SourceFromContext(ctx context.Context) (string, bool) {
+	source, ok := ctx.Value(sourceKey{}).(string)
+	return source, ok
+}
+
+// WithSinks limits events from detections using the context to the named sinks
+func WithSinks(ctx context.Context, sinks []string) context.Context {
+	return context.WithValue(ctx, sinksKey{}, sinks)
+}
+
+// SinksFromContext returns the sink names set with WithSinks
+func SinksFromContext(ctx context.Context) []string {
+	sinks, _ := ctx.Value(sinksKey{}).([]string)
+	return sinks
+}
@@ -0,0 +1,116 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/snowzach/doods/sink/sinkconfig"
+)
+
+// timescale inserts detection and label counts into TimescaleDB or PostgreSQL
+type timescale struct {
+	config     *sinkconfig.TimescaleConfig
+	detections string
+	labels     string
+	db         *postgres
+	created    bool
+}
+
+func init() {
+	Register("timescale", func(c *sinkconfig.SinkConfig) (Sink, error) {
+		if c.Timescale == nil {
+			return nil, fmt.Errorf("missing timescale config")
+		}
+		return newTimescale(c.Timescale)
+	})
+}
+
+func newTimescale(c *sinkconfig.TimescaleConfig) (*timescale, error) {
+	if c.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if c.Table == "" {
+		c.Table = "doods"
+	}
+	// Connect on the first event so the database doesn't have to be up first
+	return &timescale{
+		config:     c,
+		detections: postgresIdent(c.Table + "_detections"),
+		labels:     postgresIdent(c.Table + "_labels"),
+	}, nil
+}
+
+// Send inserts the total count into <table>_detections and the count of each label into <table>_labels
+func (t *timescale) Send(ctx context.Context, e *Event) error {
+
+	if !e.detectionEvent() {
+		return nil
+	}
+
+	if t.db == nil {
+		db, err := dialPostgres(ctx, t.config.URL)
+		if err != nil {
+			return fmt.Errorf("could not connect: %v", err)
+		}
+		t.db = db
+	}
+
+	if t.config.CreateTables && !t.created {
+		if err := t.db.exec(ctx, t.createTables()); err != nil {
+			t.close()
+			return fmt.Errorf("could not create tables: %v", err)
+		}
+		t.created = true
+	}
+
+	ts := postgresString(e.Time.UTC().Format(time.RFC3339Nano))
+	source, detector := postgresString(e.Source), postgresString(e.Detector)
+
+	var query strings.Builder
+	fmt.Fprintf(&query, "INSERT INTO %s (time, source, detector, count) VALUES (%s, %s, %s, %d);", t.detections, ts, source, detector, len(e.Response.Detections))
+	if counts := e.labelCounts(); len(counts) > 0 {
+		fmt.Fprintf(&query, "INSERT INTO %s (time, source, detector, label, count, confidence) VALUES ", t.labels)
+		for i, c := range counts {
+			if i > 0 {
+				query.WriteString(", ")
+			}
+			fmt.Fprintf(&query, "(%s, %s, %s, %s, %d, %g)", ts, source, detector, postgresString(c.Label), c.Count, c.MaxConfidence)
+		}
+		query.WriteString(";")
+	}
+
+	if err := t.db.exec(ctx, query.String()); err != nil {
+		// Reconnect on the next event
+		t.close()
+		return err
+	}
+	return nil
+
+}
+
+// createTables returns the statements to create the tables and hypertables if TimescaleDB is installed
+func (t *timescale) createTables() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (time TIMESTAMPTZ NOT NULL, source TEXT NOT NULL, detector TEXT NOT NULL, count INTEGER NOT NULL);
+CREATE TABLE IF NOT EXISTS %[2]s (time TIMESTAMPTZ NOT NULL, source TEXT NOT NULL, detector TEXT NOT NULL, label TEXT NOT NULL, count INTEGER NOT NULL, confidence REAL NOT NULL);
+DO $$ BEGIN
+IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb') THEN
+PERFORM create_hypertable(%[3]s, 'time', if_not_exists => TRUE);
+PERFORM create_hypertable(%[4]s, 'time', if_not_exists => TRUE);
+END IF;
+END $$;`, t.detections, t.labels, postgresString(t.detections), postgresString(t.labels))
+}
+
+func (t *timescale) close() {
+	if t.db != nil {
+		t.db.Close()
+		t.db = nil
+	}
+}
+
+// Close closes the connection
+func (t *timescale) Close() error {
+	t.close()
+	return nil
+}
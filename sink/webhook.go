@@ -0,0 +1,75 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/snowzach/doods/sink/sinkconfig"
+)
+
+// webhook posts events as JSON to a url
+type webhook struct {
+	config *sinkconfig.WebhookConfig
+	store  *s3
+}
+
+func init() {
+	Register("webhook", func(c *sinkconfig.SinkConfig) (Sink, error) {
+		if c.Webhook == nil {
+			return nil, fmt.Errorf("missing webhook config")
+		}
+		return newWebhook(c.Webhook)
+	})
+}
+
+func newWebhook(c *sinkconfig.WebhookConfig) (*webhook, error) {
+	if c.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if c.Method == "" {
+		c.Method = http.MethodPost
+	}
+	return &webhook{config: c}, nil
+}
+
+func (wh *webhook) imageStoreName() string { return wh.config.ImageStore }
+func (wh *webhook) setImageStore(s *s3)    { wh.store = s }
+
+func (wh *webhook) Send(ctx context.Context, e *Event) error {
+
+	// Add the url of the stored image or clip, or the annotated image as base64
+	var fields map[string]interface{}
+	var err error
+	if wh.store != nil {
+		if fields, err = wh.store.urlFields(ctx, e); err != nil {
+			return fmt.Errorf("could not store image: %v", err)
+		}
+	} else if wh.config.Image && e.Clip == "" {
+		image, err := e.Annotated()
+		if err != nil {
+			return fmt.Errorf("could not annotate image: %v", err)
+		}
+		fields = map[string]interface{}{"image": base64.StdEncoding.EncodeToString(image)}
+	}
+
+	body, err := e.JSONWith(fields)
+	if err != nil {
+		return fmt.Errorf("could not encode event: %v", err)
+	}
+
+	req, err := http.NewRequest(wh.config.Method, wh.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range wh.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	return doNotify(req)
+
+}
@@ -0,0 +1,158 @@
+// Package state tracks the objects seen by each source and reports when they change
+package state
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// Change types
+const (
+	Appeared = "appeared"
+	Left     = "left"
+	Changed  = "changed"
+)
+
+// Change is a confirmed change in the number of objects with a label
+type Change struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Label  string    `json:"label"`
+	Type   string    `json:"type"`
+	From   int       `json:"from"`
+	To     int       `json:"to"`
+}
+
+type labelState struct {
+	// The confirmed count
+	count int
+	// The count seen but not confirmed yet and when it was first seen
+	pending int
+	since   time.Time
+}
+
+// Tracker keeps the object counts for each source
+type Tracker struct {
+	// How long a new count must be seen before it's confirmed
+	debounce time.Duration
+	// How long objects must be gone before they have left
+	leave time.Duration
+
+	sources map[string]map[string]*labelState
+	lock    sync.Mutex
+}
+
+// NewTracker creates a tracker. Objects leaving usually need a longer window as detections flicker.
+func NewTracker(debounce time.Duration, leave time.Duration) *Tracker {
+	return &Tracker{
+		debounce: debounce,
+		leave:    leave,
+		sources:  make(map[string]map[string]*labelState),
+	}
+}
+
+// Update records the detections for the source and returns the confirmed changes
+func (t *Tracker) Update(source string, now time.Time, detections []*odrpc.Detection) []*Change {
+
+	counts := make(map[string]int)
+	for _, d := range detections {
+		counts[d.Label]++
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	labels, ok := t.sources[source]
+	if !ok {
+		labels = make(map[string]*labelState)
+		t.sources[source] = labels
+	}
+	for label := range counts {
+		if _, ok := labels[label]; !ok {
+			labels[label] = &labelState{}
+		}
+	}
+
+	var changes []*Change
+	for label, s := range labels {
+		count := counts[label]
+
+		if count == s.count {
+			s.pending = count
+			// Never confirmed
+			if count == 0 {
+				delete(labels, label)
+			}
+			continue
+		}
+		if count != s.pending {
+			s.pending = count
+			s.since = now
+		}
+
+		window := t.debounce
+		if count == 0 {
+			window = t.leave
+		}
+		if now.Sub(s.since) < window {
+			continue
+		}
+
+		change := &Change{
+			Time:   now,
+			Source: source,
+			Label:  label,
+			Type:   Changed,
+			From:   s.count,
+			To:     count,
+		}
+		switch {
+		case s.count == 0:
+			change.Type = Appeared
+		case count == 0:
+			change.Type = Left
+		}
+		changes = append(changes, change)
+
+		s.count = count
+		if count == 0 {
+			delete(labels, label)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Label < changes[j].Label })
+	return changes
+
+}
+
+// Get returns the confirmed object counts for a source
+func (t *Tracker) Get(source string) map[string]int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	counts := make(map[string]int)
+	for label, s := range t.sources[source] {
+		if s.count > 0 {
+			counts[label] = s.count
+		}
+	}
+	return counts
+}
+
+// All returns the confirmed object counts for every source
+func (t *Tracker) All() map[string]map[string]int {
+	t.lock.Lock()
+	sources := make([]string, 0, len(t.sources))
+	for source := range t.sources {
+		sources = append(sources, source)
+	}
+	t.lock.Unlock()
+
+	all := make(map[string]map[string]int)
+	for _, source := range sources {
+		all[source] = t.Get(source)
+	}
+	return all
+}
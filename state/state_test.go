@@ -0,0 +1,87 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+func frame(labels ...string) []*odrpc.Detection {
+	ret := make([]*odrpc.Detection, 0, len(labels))
+	for _, label := range labels {
+		ret = append(ret, &odrpc.Detection{Label: label})
+	}
+	return ret
+}
+
+func TestUpdate(t *testing.T) {
+
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(2*time.Second, 5*time.Second)
+
+	for _, test := range []struct {
+		at       int // Seconds from the start
+		frame    []*odrpc.Detection
+		changes  []Change
+		counts   map[string]int
+		describe string
+	}{
+		{0, frame("car"), nil, map[string]int{}, "car is new"},
+		{1, frame("car"), nil, map[string]int{}, "car is debounced"},
+		{2, frame("car"), []Change{{Label: "car", Type: Appeared, From: 0, To: 1}}, map[string]int{"car": 1}, "car appeared"},
+		{3, frame("car", "person", "person"), nil, map[string]int{"car": 1}, "people are new"},
+		{4, frame("car"), nil, map[string]int{"car": 1}, "people were never confirmed"},
+		{6, frame("car"), nil, map[string]int{"car": 1}, "people are forgotten"},
+		{7, frame("car", "car"), nil, map[string]int{"car": 1}, "second car is new"},
+		{8, frame("car"), nil, map[string]int{"car": 1}, "second car flickered"},
+		{9, frame("car", "car"), nil, map[string]int{"car": 1}, "second car is new again"},
+		{11, frame("car", "car"), []Change{{Label: "car", Type: Changed, From: 1, To: 2}}, map[string]int{"car": 2}, "second car confirmed"},
+		{12, frame(), nil, map[string]int{"car": 2}, "cars are gone"},
+		{16, frame(), nil, map[string]int{"car": 2}, "cars are gone less than leave"},
+		{17, frame("dog"), []Change{{Label: "car", Type: Left, From: 2, To: 0}}, map[string]int{}, "cars left"},
+		{19, frame("dog", "car"), []Change{{Label: "dog", Type: Appeared, From: 0, To: 1}}, map[string]int{"dog": 1}, "dog appeared"},
+	} {
+		now := start.Add(time.Duration(test.at) * time.Second)
+		changes := tracker.Update("yard", now, test.frame)
+		if len(changes) != len(test.changes) {
+			t.Fatalf("%s: changes %v, expected %v", test.describe, changes, test.changes)
+		}
+		for i, c := range changes {
+			expected := test.changes[i]
+			expected.Time, expected.Source = now, "yard"
+			if *c != expected {
+				t.Errorf("%s: change %+v, expected %+v", test.describe, *c, expected)
+			}
+		}
+		if counts := tracker.Get("yard"); !reflect.DeepEqual(counts, test.counts) {
+			t.Errorf("%s: counts %v, expected %v", test.describe, counts, test.counts)
+		}
+	}
+
+	// Sources are separate
+	if changes := tracker.Update("door", start, frame("dog")); len(changes) != 0 {
+		t.Errorf("door changes %v", changes)
+	}
+	expected := map[string]map[string]int{"yard": {"dog": 1}, "door": {}}
+	if all := tracker.All(); !reflect.DeepEqual(all, expected) {
+		t.Errorf("all counts %v, expected %v", all, expected)
+	}
+
+}
+
+func TestUpdateNoDebounce(t *testing.T) {
+
+	tracker := NewTracker(0, 0)
+	now := time.Now()
+	changes := tracker.Update("yard", now, frame("person", "cat"))
+	if len(changes) != 2 || changes[0].Label != "cat" || changes[1].Label != "person" {
+		t.Fatalf("changes %v, expected cat and person appeared", changes)
+	}
+	changes = tracker.Update("yard", now, frame("cat"))
+	if len(changes) != 1 || changes[0].Type != Left || changes[0].Label != "person" {
+		t.Errorf("changes %v, expected person left", changes)
+	}
+
+}
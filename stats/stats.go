@@ -0,0 +1,172 @@
+// Package stats keeps rolling detection statistics for each source
+package stats
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+const (
+	// The rate and latency are over the last 5 minutes
+	rateWindow = 5 * time.Minute
+	// The labels are counted over the last hour in minute buckets
+	buckets = 60
+)
+
+// Stats are the statistics for a source
+type Stats struct {
+	Source string `json:"source"`
+	// The detections per second over the last 5 minutes
+	FPS float64 `json:"fps"`
+	// The average request and detector time over the last 5 minutes
+	LatencyMS float64 `json:"latency_ms"`
+	DetectMS  float64 `json:"detect_ms"`
+	// The number of frames and detections of each label in the last hour
+	Frames      int            `json:"frames_per_hour"`
+	LabelCounts map[string]int `json:"detections_per_hour"`
+	// The last time there was a detection and the last time a frame was analyzed
+	LastDetection *time.Time `json:"last_detection,omitempty"`
+	LastFrame     time.Time  `json:"last_frame"`
+}
+
+// bucket is a minute of detections
+type bucket struct {
+	minute  int64
+	frames  int
+	latency time.Duration
+	detect  time.Duration
+	labels  map[string]int
+}
+
+type source struct {
+	buckets       [buckets]bucket
+	first         time.Time
+	lastFrame     time.Time
+	lastDetection time.Time
+}
+
+// Collector keeps the statistics of each source
+type Collector struct {
+	sources map[string]*source
+	lock    sync.Mutex
+}
+
+// New creates a collector
+func New() *Collector {
+	return &Collector{
+		sources: make(map[string]*source),
+	}
+}
+
+// Record adds a detection for the source, latency is the whole request and detect the detector
+func (c *Collector) Record(name string, now time.Time, latency time.Duration, detect time.Duration, detections []*odrpc.Detection) {
+
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	s, ok := c.sources[name]
+	if !ok {
+		s = &source{first: now}
+		c.sources[name] = s
+	}
+
+	minute := now.Unix() / 60
+	b := &s.buckets[minute%buckets]
+	if b.minute != minute {
+		*b = bucket{minute: minute, labels: make(map[string]int)}
+	}
+	b.frames++
+	b.latency += latency
+	b.detect += detect
+	for _, d := range detections {
+		b.labels[d.Label]++
+	}
+
+	s.lastFrame = now
+	if len(detections) > 0 {
+		s.lastDetection = now
+	}
+
+}
+
+// Get returns the statistics for the source, nil if it hasn't been seen
+func (c *Collector) Get(name string) *Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	s, ok := c.sources[name]
+	if !ok {
+		return nil
+	}
+	return s.stats(name, time.Now())
+}
+
+// All returns the statistics for every source sorted by name
+func (c *Collector) All() []*Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	ret := make([]*Stats, 0, len(c.sources))
+	for name, s := range c.sources {
+		ret = append(ret, s.stats(name, now))
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Source < ret[j].Source })
+	return ret
+}
+
+// stats sums the buckets, the lock must be held
+func (s *source) stats(name string, now time.Time) *Stats {
+
+	st := &Stats{
+		Source:      name,
+		LabelCounts: make(map[string]int),
+		LastFrame:   s.lastFrame,
+	}
+	if !s.lastDetection.IsZero() {
+		last := s.lastDetection
+		st.LastDetection = &last
+	}
+
+	minute := now.Unix() / 60
+	rateMinutes := int64(rateWindow / time.Minute)
+	var frames int
+	var latency, detect time.Duration
+	for _, b := range s.buckets {
+		age := minute - b.minute
+		if b.frames == 0 || age < 0 || age >= buckets {
+			continue
+		}
+		st.Frames += b.frames
+		for label, n := range b.labels {
+			st.LabelCounts[label] += n
+		}
+		if age < rateMinutes {
+			frames += b.frames
+			latency += b.latency
+			detect += b.detect
+		}
+	}
+
+	if frames > 0 {
+		// The window starts at the oldest bucket counted or when the source was first seen
+		window := now.Sub(time.Unix((minute-rateMinutes+1)*60, 0))
+		if since := now.Sub(s.first); since < window {
+			window = since
+		}
+		if window < time.Second {
+			window = time.Second
+		}
+		st.FPS = float64(frames) / window.Seconds()
+		st.LatencyMS = float64(latency/time.Duration(frames)) / float64(time.Millisecond)
+		st.DetectMS = float64(detect/time.Duration(frames)) / float64(time.Millisecond)
+	}
+
+	return st
+
+}
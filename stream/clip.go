@@ -0,0 +1,239 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+	"time"
+
+	"go.uber.org/zap"
+	"gocv.io/x/gocv"
+
+	"github.com/snowzach/doods/sink"
+	"github.com/snowzach/doods/stream/sconfig"
+)
+
+const (
+	defaultClipPath      = "clips"
+	defaultClipFPS       = 10.0
+	defaultClipPreRoll   = 5 * time.Second
+	defaultClipPostRoll  = 10 * time.Second
+	defaultClipMaxLength = time.Minute
+	defaultClipFile      = `{{.Source}}-{{.Time.Format "20060102-150405"}}.mp4`
+)
+
+type clipFrame struct {
+	time time.Time
+	data []byte // JPEG
+}
+
+// recorder buffers the stream and records clips around events
+type recorder struct {
+	name     string
+	config   *sconfig.ClipConfig
+	file     *template.Template
+	interval time.Duration
+	sinks    *sink.Manager
+	logger   *zap.SugaredLogger
+
+	// Pre-roll frames
+	buffer    []clipFrame
+	lastFrame time.Time
+
+	// Current recording
+	writer    *gocv.VideoWriter
+	filename  string
+	start     time.Time
+	until     time.Time
+	event     *sink.Event
+	triggered *sink.Event
+	lock      sync.Mutex
+}
+
+func newRecorder(name string, c *sconfig.ClipConfig, sinks *sink.Manager, logger *zap.SugaredLogger) (*recorder, error) {
+
+	if c.Path == "" {
+		c.Path = defaultClipPath
+	}
+	if c.FPS <= 0 {
+		c.FPS = defaultClipFPS
+	}
+	if c.PreRoll <= 0 {
+		c.PreRoll = defaultClipPreRoll
+	}
+	if c.PostRoll <= 0 {
+		c.PostRoll = defaultClipPostRoll
+	}
+	if c.MaxLength <= 0 {
+		c.MaxLength = defaultClipMaxLength
+	}
+	if c.FileTemplate == "" {
+		c.FileTemplate = defaultClipFile
+	}
+
+	file, err := template.New("file").Parse(c.FileTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clip file template: %v", err)
+	}
+
+	if err := os.MkdirAll(c.Path, 0755); err != nil {
+		return nil, fmt.Errorf("could not create clip path %s: %v", c.Path, err)
+	}
+
+	return &recorder{
+		name:     name,
+		config:   c,
+		file:     file,
+		interval: time.Duration(float64(time.Second) / c.FPS),
+		sinks:    sinks,
+		logger:   logger,
+	}, nil
+
+}
+
+// trigger starts or extends a recording for the event, a private event is kept until the next frame so it isn't missed
+func (r *recorder) trigger(e *sink.Event) {
+	r.lock.Lock()
+	if r.triggered == nil || !r.triggered.Private {
+		r.triggered = e
+	}
+	r.lock.Unlock()
+}
+
+// frame handles a frame from the stream
+func (r *recorder) frame(now time.Time, frame gocv.Mat) {
+
+	if now.Sub(r.lastFrame) < r.interval {
+		return
+	}
+	r.lastFrame = now
+
+	r.lock.Lock()
+	triggered := r.triggered
+	r.triggered = nil
+	r.lock.Unlock()
+
+	// Frames with a face must not be stored, the clip and the pre-roll with them are dropped
+	if triggered != nil && triggered.Private {
+		r.discardClip()
+		r.buffer = r.buffer[:0]
+		return
+	}
+
+	if triggered != nil {
+		if r.writer == nil {
+			if err := r.startClip(now, frame, triggered); err != nil {
+				r.logger.Errorw("Could not start clip", "error", err)
+			}
+		}
+		// Extend the recording up to the max length
+		r.until = now.Add(r.config.PostRoll)
+		if max := r.start.Add(r.config.MaxLength); r.until.After(max) {
+			r.until = max
+		}
+	}
+
+	if r.writer != nil {
+		if err := r.writer.Write(frame); err != nil {
+			r.logger.Errorw("Could not write clip frame", "error", err)
+		}
+		if now.After(r.until) {
+			r.finishClip()
+		}
+		return
+	}
+
+	// Buffer for the pre-roll
+	data, err := gocv.IMEncode(gocv.JPEGFileExt, frame)
+	if err != nil {
+		r.logger.Errorw("Could not encode clip frame", "error", err)
+		return
+	}
+	r.buffer = append(r.buffer, clipFrame{time: now, data: data})
+	var x int
+	for x < len(r.buffer) && now.Sub(r.buffer[x].time) > r.config.PreRoll {
+		x++
+	}
+	r.buffer = r.buffer[x:]
+
+}
+
+// startClip opens the clip file and writes the pre-roll frames
+func (r *recorder) startClip(now time.Time, frame gocv.Mat, e *sink.Event) error {
+
+	var name bytes.Buffer
+	if err := r.file.Execute(&name, e); err != nil {
+		return fmt.Errorf("could not build clip filename: %v", err)
+	}
+	filename := filepath.Join(r.config.Path, name.String())
+
+	writer, err := gocv.VideoWriterFile(filename, "mp4v", r.config.FPS, frame.Cols(), frame.Rows(), true)
+	if err != nil {
+		return fmt.Errorf("could not create clip %s: %v", filename, err)
+	}
+
+	for _, f := range r.buffer {
+		img, err := gocv.IMDecode(f.data, gocv.IMReadColor)
+		if err != nil {
+			continue
+		}
+		if img.Cols() == frame.Cols() && img.Rows() == frame.Rows() {
+			writer.Write(img)
+		}
+		img.Close()
+	}
+	r.buffer = r.buffer[:0]
+
+	r.writer = writer
+	r.filename = filename
+	r.start = now
+	r.event = e
+
+	r.logger.Infow("Recording clip", "file", filename)
+
+	return nil
+
+}
+
+// discardClip closes and deletes the current clip without sending it
+func (r *recorder) discardClip() {
+
+	if r.writer == nil {
+		return
+	}
+	r.writer.Close()
+	r.writer = nil
+
+	if err := os.Remove(r.filename); err != nil {
+		r.logger.Errorw("Could not delete private clip", "file", r.filename, "error", err)
+		return
+	}
+	r.logger.Infow("Private clip deleted", "file", r.filename)
+
+}
+
+// finishClip closes the clip and sends it to the sinks
+func (r *recorder) finishClip() {
+
+	if r.writer == nil {
+		return
+	}
+	r.writer.Close()
+	r.writer = nil
+
+	r.logger.Infow("Clip complete", "file", r.filename, "duration", time.Since(r.start))
+
+	r.sinks.Send(&sink.Event{
+		Time:     r.event.Time,
+		ID:       r.event.ID,
+		Source:   r.event.Source,
+		Detector: r.event.Detector,
+		Image:    r.event.Image,
+		Response: r.event.Response,
+		Clip:     r.filename,
+	})
+
+}
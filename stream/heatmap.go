@@ -0,0 +1,211 @@
+package stream
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/png"
+	"sync"
+	"time"
+
+	_ "golang.org/x/image/bmp"
+
+	"github.com/snowzach/doods/detector/annotate"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/stream/sconfig"
+)
+
+const (
+	// The window is split into buckets so old detections can be dropped
+	heatmapBuckets        = 24
+	defaultHeatmapWindow  = 24 * time.Hour
+	defaultHeatmapCells   = 64
+	defaultHeatmapWidth   = 640
+	maxHeatmapWidth       = 4096
+	heatmapOverlayOpacity = 0.6
+)
+
+// heatmap counts the centers of the detections in a grid over a window of time
+type heatmap struct {
+	cells  int
+	span   time.Duration
+	labels map[string]struct{}
+
+	buckets [heatmapBuckets]heatmapBucket
+	// The last frame for the overlay
+	frame []byte
+	lock  sync.Mutex
+}
+
+type heatmapBucket struct {
+	index  int64
+	counts []float32
+}
+
+func newHeatmap(c *sconfig.HeatmapConfig) *heatmap {
+	h := &heatmap{
+		cells: c.Resolution,
+		span:  c.Window / heatmapBuckets,
+	}
+	if h.cells <= 0 {
+		h.cells = defaultHeatmapCells
+	}
+	if c.Window <= 0 {
+		h.span = defaultHeatmapWindow / heatmapBuckets
+	}
+	if h.span < time.Second {
+		h.span = time.Second
+	}
+	if len(c.Labels) > 0 {
+		h.labels = make(map[string]struct{}, len(c.Labels))
+		for _, label := range c.Labels {
+			h.labels[label] = struct{}{}
+		}
+	}
+	return h
+}
+
+// add counts the centers of the detections and keeps the frame
+func (h *heatmap) add(now time.Time, frame []byte, detections []*odrpc.Detection) {
+
+	if h == nil {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.frame = frame
+
+	index := now.UnixNano() / int64(h.span)
+	b := &h.buckets[index%heatmapBuckets]
+	for _, d := range detections {
+		if h.labels != nil {
+			if _, ok := h.labels[d.Label]; !ok {
+				continue
+			}
+		}
+		if b.index != index || b.counts == nil {
+			b.index, b.counts = index, make([]float32, h.cells*h.cells)
+		}
+		x := clampCell(int((d.Left+d.Right)/2*float32(h.cells)), h.cells)
+		y := clampCell(int((d.Top+d.Bottom)/2*float32(h.cells)), h.cells)
+		b.counts[y*h.cells+x]++
+	}
+
+}
+
+// grid sums the buckets in the window and blurs it, the values are scaled 0-1
+func (h *heatmap) grid(now time.Time) []float32 {
+
+	index := now.UnixNano() / int64(h.span)
+	sum := make([]float32, h.cells*h.cells)
+	for _, b := range h.buckets {
+		if b.counts == nil || index-b.index >= heatmapBuckets || b.index > index {
+			continue
+		}
+		for i, v := range b.counts {
+			sum[i] += v
+		}
+	}
+
+	// Spread each count over the neighbouring cells
+	blurred := make([]float32, len(sum))
+	var max float32
+	for y := 0; y < h.cells; y++ {
+		for x := 0; x < h.cells; x++ {
+			var v, weight float32
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := x+dx, y+dy
+					if nx < 0 || ny < 0 || nx >= h.cells || ny >= h.cells {
+						continue
+					}
+					w := float32(1)
+					if dx == 0 && dy == 0 {
+						w = 4
+					}
+					v += sum[ny*h.cells+nx] * w
+					weight += w
+				}
+			}
+			v /= weight
+			blurred[y*h.cells+x] = v
+			if v > max {
+				max = v
+			}
+		}
+	}
+	if max > 0 {
+		for i := range blurred {
+			blurred[i] /= max
+		}
+	}
+	return blurred
+
+}
+
+// png renders the heatmap, over the last frame if overlay is set
+func (h *heatmap) png(now time.Time, width int, overlay bool) ([]byte, error) {
+
+	h.lock.Lock()
+	grid := h.grid(now)
+	frame := h.frame
+	h.lock.Unlock()
+
+	// The size of the last frame or the width with the aspect of the last frame
+	var base image.Image
+	if frame != nil {
+		if img, _, err := image.Decode(bytes.NewReader(frame)); err == nil {
+			base = img
+		}
+	}
+	if width <= 0 {
+		width = defaultHeatmapWidth
+	}
+	height := width * 3 / 4
+	if base != nil {
+		b := base.Bounds()
+		if overlay {
+			width, height = b.Dx(), b.Dy()
+		} else if b.Dx() > 0 {
+			height = width * b.Dy() / b.Dx()
+		}
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	if overlay && base != nil {
+		draw.Draw(out, out.Bounds(), base, base.Bounds().Min, draw.Src)
+	}
+
+	heat := image.NewRGBA(out.Bounds())
+	opacity := float32(1)
+	if overlay && base != nil {
+		opacity = heatmapOverlayOpacity
+	}
+	for y := 0; y < height; y++ {
+		cy := clampCell(y*h.cells/height, h.cells)
+		for x := 0; x < width; x++ {
+			cx := clampCell(x*h.cells/width, h.cells)
+			heat.SetRGBA(x, y, annotate.HeatColor(grid[cy*h.cells+cx], opacity))
+		}
+	}
+	draw.Draw(out, out.Bounds(), heat, image.Point{}, draw.Over)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+
+}
+
+func clampCell(v, cells int) int {
+	if v < 0 {
+		return 0
+	}
+	if v >= cells {
+		return cells - 1
+	}
+	return v
+}
@@ -0,0 +1,170 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/onvif"
+	"github.com/snowzach/doods/server"
+)
+
+const (
+	mjpegBoundary    = "doodsframe"
+	discoveryTimeout = 3 * time.Second
+)
+
+// RegisterHTTP registers the stream endpoints on the router
+func (m *Manager) RegisterHTTP(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(m.keys))
+		r.Get("/streams", m.handleStreams)
+		r.Get("/streams/discover", m.handleDiscover)
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(server.ImageAuth(m.keys))
+		r.Get("/stream/{name}/live", m.handleLive)
+		r.Get("/stream/{name}/heatmap.png", m.handleHeatmap)
+	})
+}
+
+type streamInfo struct {
+	Name     string                `json:"name"`
+	Detector string                `json:"detector"`
+	Response *odrpc.DetectResponse `json:"response,omitempty"`
+}
+
+// handleStreams returns the configured streams and their last detections
+func (m *Manager) handleStreams(w http.ResponseWriter, r *http.Request) {
+	streams := make([]*streamInfo, 0, len(m.streams))
+	for _, s := range m.streams {
+		streams = append(streams, &streamInfo{
+			Name:     s.config.Name,
+			Detector: s.config.Detector,
+			Response: s.Response(),
+		})
+	}
+	sort.Slice(streams, func(i, j int) bool { return streams[i].Name < streams[j].Name })
+	render.JSON(w, r, map[string]interface{}{"streams": streams})
+}
+
+// handleLive serves an MJPEG stream of the frames with the detections drawn
+func (m *Manager) handleLive(w http.ResponseWriter, r *http.Request) {
+
+	s, ok := m.streams[chi.URLParam(r, "name")]
+	if !ok {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		render.Render(w, r, server.ErrInternal(fmt.Errorf("streaming not supported")))
+		return
+	}
+
+	sub := s.Subscribe()
+	defer s.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+mjpegBoundary)
+	w.Header().Set("Cache-Control", "no-cache, no-store")
+	w.WriteHeader(http.StatusOK)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-sub:
+			if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", mjpegBoundary, len(data)); err != nil {
+				return
+			}
+			// The frame is shared with the other subscribers so the line end is written on its own
+			if _, err := w.Write(data); err != nil {
+				return
+			}
+			if _, err := io.WriteString(w, "\r\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+
+}
+
+type discoveredCamera struct {
+	*onvif.Device
+	Profiles []*onvif.Profile `json:"profiles,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// handleDiscover finds ONVIF cameras on the local network. If username and password are passed
+// the stream urls for each camera are included.
+func (m *Manager) handleDiscover(w http.ResponseWriter, r *http.Request) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), discoveryTimeout)
+	defer cancel()
+
+	devices, err := onvif.Discover(ctx)
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+
+	username := r.URL.Query().Get("username")
+	password := r.URL.Query().Get("password")
+
+	cameras := make([]*discoveredCamera, 0, len(devices))
+	for _, d := range devices {
+		camera := &discoveredCamera{Device: d}
+		if username != "" && len(d.XAddrs) > 0 {
+			camera.Profiles, err = onvif.NewClient(d.XAddrs[0], username, password).Profiles(r.Context())
+			if err != nil {
+				camera.Error = err.Error()
+			}
+		}
+		cameras = append(cameras, camera)
+	}
+	sort.Slice(cameras, func(i, j int) bool { return cameras[i].Name < cameras[j].Name })
+
+	render.JSON(w, r, map[string]interface{}{"cameras": cameras})
+
+}
+
+// handleHeatmap returns the heatmap of the stream as a PNG. Pass ?overlay=true to draw it over the last frame
+// and ?width=<pixels> for the size without the overlay.
+func (m *Manager) handleHeatmap(w http.ResponseWriter, r *http.Request) {
+
+	s, ok := m.streams[chi.URLParam(r, "name")]
+	if !ok || s.heatmap == nil {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+
+	var width int
+	if ws := r.URL.Query().Get("width"); ws != "" {
+		var err error
+		if width, err = strconv.Atoi(ws); err != nil || width <= 0 || width > maxHeatmapWidth {
+			render.Render(w, r, server.ErrInvalidRequest(fmt.Errorf("invalid width: %s", ws)))
+			return
+		}
+	}
+	overlay, _ := strconv.ParseBool(r.URL.Query().Get("overlay"))
+
+	data, err := s.heatmap.png(time.Now(), width, overlay)
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(data)
+
+}
@@ -0,0 +1,110 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/snowzach/doods/stream/sconfig"
+)
+
+const (
+	HWDecodeVAAPI   = "vaapi"
+	HWDecodeV4L2M2M = "v4l2m2m"
+	HWDecodeRKMPP   = "rkmpp"
+	HWDecodeNVDEC   = "nvdec"
+
+	defaultVAAPIDevice = "/dev/dri/renderD128"
+)
+
+// openHWDecode decodes the stream with ffmpeg using a hardware decoder. Frames are passed back as BMP
+// which is cheap to encode and decode.
+func openHWDecode(ctx context.Context, url string, c *sconfig.HWDecodeConfig) (source, error) {
+
+	args, err := hwDecodeArgs(url, c)
+	if err != nil {
+		return nil, err
+	}
+
+	command := c.Command
+	if command == "" {
+		command = "ffmpeg"
+	}
+
+	return startProcess(ctx, command, args, readBMP)
+
+}
+
+// hwDecodeArgs builds the ffmpeg arguments for the decoder type
+func hwDecodeArgs(url string, c *sconfig.HWDecodeConfig) ([]string, error) {
+
+	codec := strings.ToLower(c.Codec)
+	switch codec {
+	case "", "h264":
+		codec = "h264"
+	case "h265", "hevc":
+		codec = "hevc"
+	default:
+		return nil, fmt.Errorf("unknown codec %s", c.Codec)
+	}
+
+	args := []string{"-hide_banner", "-loglevel", "error"}
+
+	switch c.Type {
+	case HWDecodeVAAPI:
+		device := c.Device
+		if device == "" {
+			device = defaultVAAPIDevice
+		}
+		args = append(args, "-hwaccel", "vaapi", "-hwaccel_device", device)
+	case HWDecodeNVDEC:
+		args = append(args, "-hwaccel", "cuda")
+		if c.Device != "" {
+			args = append(args, "-hwaccel_device", c.Device)
+		}
+	case HWDecodeV4L2M2M:
+		args = append(args, "-c:v", codec+"_v4l2m2m")
+	case HWDecodeRKMPP:
+		args = append(args, "-c:v", codec+"_rkmpp")
+	default:
+		return nil, fmt.Errorf("unknown hardware decoder %s", c.Type)
+	}
+
+	if strings.HasPrefix(url, "rtsp://") {
+		args = append(args, "-rtsp_transport", "tcp")
+	}
+	args = append(args, c.InputArgs...)
+	args = append(args, "-i", url, "-an", "-f", "image2pipe", "-c:v", "bmp", "-")
+
+	return args, nil
+
+}
+
+// readBMP reads a single BMP image from a stream of concatenated images
+func readBMP(r *bufio.Reader) ([]byte, error) {
+
+	header := make([]byte, 14)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if header[0] != 'B' || header[1] != 'M' {
+		return nil, fmt.Errorf("invalid bmp header")
+	}
+
+	size := binary.LittleEndian.Uint32(header[2:6])
+	if size < 14 {
+		return nil, fmt.Errorf("invalid bmp size %d", size)
+	}
+
+	data := make([]byte, size)
+	copy(data, header)
+	if _, err := io.ReadFull(r, data[14:]); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+
+}
@@ -0,0 +1,110 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+
+	"github.com/snowzach/doods/stream/sconfig"
+)
+
+// The commands that can capture from the Pi camera, newer releases renamed libcamera-vid
+var libcameraCommands = []string{"rpicam-vid", "libcamera-vid"}
+
+// openLibcamera starts the capture command for the Pi camera
+func openLibcamera(ctx context.Context, c *sconfig.LibcameraConfig) (source, error) {
+
+	command := c.Command
+	if command == "" {
+		for _, name := range libcameraCommands {
+			if _, err := exec.LookPath(name); err == nil {
+				command = name
+				break
+			}
+		}
+		if command == "" {
+			return nil, fmt.Errorf("could not find %v", libcameraCommands)
+		}
+	}
+
+	return startProcess(ctx, command, libcameraArgs(c), readJPEG)
+
+}
+
+// libcameraArgs builds the capture command arguments from the config
+func libcameraArgs(c *sconfig.LibcameraConfig) []string {
+
+	args := []string{"--nopreview", "--timeout", "0", "--codec", "mjpeg", "--output", "-",
+		"--camera", strconv.Itoa(c.Camera)}
+
+	if c.Width > 0 && c.Height > 0 {
+		args = append(args, "--width", strconv.Itoa(c.Width), "--height", strconv.Itoa(c.Height))
+	}
+	if c.FPS > 0 {
+		args = append(args, "--framerate", strconv.FormatFloat(c.FPS, 'f', -1, 64))
+	}
+	if c.Mode != "" {
+		args = append(args, "--mode", c.Mode)
+	}
+	if c.Shutter > 0 {
+		args = append(args, "--shutter", strconv.FormatInt(c.Shutter.Microseconds(), 10))
+	}
+	if c.Gain > 0 {
+		args = append(args, "--gain", strconv.FormatFloat(c.Gain, 'f', -1, 64))
+	}
+	if c.EV != 0 {
+		args = append(args, "--ev", strconv.FormatFloat(c.EV, 'f', -1, 64))
+	}
+	if c.AWB != "" {
+		args = append(args, "--awb", c.AWB)
+	}
+	if c.Rotation != 0 {
+		args = append(args, "--rotation", strconv.Itoa(c.Rotation))
+	}
+
+	return append(args, c.Args...)
+
+}
+
+var (
+	jpegStart = []byte{0xff, 0xd8}
+	jpegEnd   = []byte{0xff, 0xd9}
+)
+
+// readJPEG reads a single JPEG image from a stream of concatenated images
+func readJPEG(r *bufio.Reader) ([]byte, error) {
+
+	var buf bytes.Buffer
+
+	// Skip to the start of image marker
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != jpegStart[0] {
+			continue
+		}
+		if next, err := r.Peek(1); err == nil && next[0] == jpegStart[1] {
+			r.ReadByte()
+			buf.Write(jpegStart)
+			break
+		}
+	}
+
+	// Read until the end of image marker. 0xff is always escaped in the image data.
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteByte(b)
+		if b == jpegEnd[1] && bytes.HasSuffix(buf.Bytes(), jpegEnd) {
+			return buf.Bytes(), nil
+		}
+	}
+
+}
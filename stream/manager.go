@@ -0,0 +1,51 @@
+package stream
+
+import (
+	config "github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/snowzach/doods/conf"
+	"github.com/snowzach/doods/server"
+	"github.com/snowzach/doods/sink"
+	"github.com/snowzach/doods/stream/sconfig"
+	"github.com/snowzach/doods/zone"
+)
+
+// Manager runs the configured streams
+type Manager struct {
+	streams map[string]*Stream
+	lc      *conf.Lifecycle
+	keys    *server.AuthKeys
+	logger  *zap.SugaredLogger
+}
+
+// New creates and starts the configured streams
+func New(lc *conf.Lifecycle, detector Detector, zones *zone.Store, sinks *sink.Manager) *Manager {
+
+	m := &Manager{
+		streams: make(map[string]*Stream),
+		lc:      lc,
+		keys:    server.Keys(),
+		logger:  zap.S().With("package", "stream"),
+	}
+
+	// Get the streams config
+	var streamConfig []*sconfig.StreamConfig
+	config.UnmarshalKey("doods.streams", &streamConfig)
+
+	for _, c := range streamConfig {
+		s, err := newStream(c, detector, zones, sinks)
+		if err != nil {
+			m.logger.Errorf("Could not configure stream %s: %v", c.Name, err)
+			continue
+		}
+		m.streams[c.Name] = s
+
+		lc.Go(s.Run)
+
+		m.logger.Infow("Configured Stream", "name", c.Name, "detector", c.Detector, "fps", c.FPS)
+	}
+
+	return m
+
+}
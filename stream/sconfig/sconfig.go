@@ -0,0 +1,150 @@
+package sconfig
+
+import (
+	"time"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// StreamConfig is used for parsing stream configuration from the config file
+type StreamConfig struct {
+	Name     string                `json:"name"`
+	URL      string                `json:"url"`
+	Detector string                `json:"detector"`
+	Detect   map[string]float32    `json:"detect"`
+	Regions  []*odrpc.DetectRegion `json:"regions"`
+	// Enhance the frames before detection
+	Preprocess *odrpc.Preprocess `json:"preprocess"`
+	// Rotate the frames clockwise (90, 180 or 270) and flip them (horizontal, vertical or both) for mounted cameras
+	Rotate int32  `json:"rotate"`
+	Flip   string `json:"flip"`
+	// Smooth the boxes between frames
+	Smooth *odrpc.Smooth `json:"smooth"`
+	// Only report labels seen in min_frames of the last frames
+	Confirm *odrpc.Confirm `json:"confirm"`
+	// Detections per second
+	FPS float64 `json:"fps"`
+	// Vary the detections per second with activity
+	Adaptive *AdaptiveConfig `json:"adaptive"`
+	// Max frames per second for the live output
+	LiveFPS float64 `json:"live_fps"`
+	// Accumulate a heatmap of where objects are detected
+	Heatmap *HeatmapConfig `json:"heatmap"`
+	// Record clips around events
+	Clip *ClipConfig `json:"clip"`
+	// Decode the url with a hardware decoder
+	HWDecode *HWDecodeConfig `json:"hw_decode"`
+	// Get the url and motion events from an ONVIF camera
+	ONVIF *ONVIFConfig `json:"onvif"`
+	// Capture from a local V4L2 device
+	Device *DeviceConfig `json:"device"`
+	// Capture from a Raspberry Pi camera module
+	Libcamera *LibcameraConfig `json:"libcamera"`
+	// Poll a JPEG snapshot url
+	Snapshot *SnapshotConfig `json:"snapshot"`
+}
+
+// DeviceConfig configures a local V4L2 capture device
+type DeviceConfig struct {
+	// The device path (/dev/video0) or index
+	Path   string `json:"path"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	// The capture frame rate
+	FPS float64 `json:"fps"`
+	// The pixel format FOURCC (MJPG, YUYV)
+	Format string `json:"format"`
+}
+
+// HWDecodeConfig configures hardware video decoding with ffmpeg
+type HWDecodeConfig struct {
+	// vaapi, v4l2m2m, rkmpp or nvdec
+	Type string `json:"type"`
+	// The stream codec (h264 or h265)
+	Codec string `json:"codec"`
+	// The render device for vaapi or gpu index for nvdec
+	Device string `json:"device"`
+	// The ffmpeg command
+	Command string `json:"command"`
+	// Extra ffmpeg arguments before the input
+	InputArgs []string `json:"input_args"`
+}
+
+// ONVIFConfig configures an ONVIF camera for a stream
+type ONVIFConfig struct {
+	// The device service url or host:port
+	Address  string `json:"address"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// The media profile name or token, defaults to the first profile
+	Profile string `json:"profile"`
+	// Only detect while the camera reports motion
+	Motion bool `json:"motion"`
+	// How long to keep detecting after motion stops
+	MotionHold time.Duration `json:"motion_hold"`
+}
+
+// AdaptiveConfig increases the detection rate while there are detections
+type AdaptiveConfig struct {
+	// Detections per second when idle, defaults to the stream fps
+	MinFPS float64 `json:"min_fps"`
+	// Detections per second while there are detections
+	MaxFPS float64 `json:"max_fps"`
+	// How long after the last detection before dropping back to the min
+	Cooldown time.Duration `json:"cooldown"`
+}
+
+// HeatmapConfig accumulates the centers of the detections
+type HeatmapConfig struct {
+	// How long detections count, default 24h
+	Window time.Duration `json:"window"`
+	// The number of cells across and down, default 64
+	Resolution int `json:"resolution"`
+	// Only these labels, all if empty
+	Labels []string `json:"labels"`
+}
+
+// ClipConfig configures clip recording for a stream
+type ClipConfig struct {
+	// The directory for clips
+	Path string `json:"path"`
+	// A text/template for the clip filename
+	FileTemplate string        `json:"file_template"`
+	FPS          float64       `json:"fps"`
+	PreRoll      time.Duration `json:"pre_roll"`
+	PostRoll     time.Duration `json:"post_roll"`
+	MaxLength    time.Duration `json:"max_length"`
+}
+
+// LibcameraConfig configures a Raspberry Pi camera module
+type LibcameraConfig struct {
+	// The capture command, defaults to rpicam-vid or libcamera-vid
+	Command string `json:"command"`
+	// The camera index
+	Camera int     `json:"camera"`
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+	FPS    float64 `json:"fps"`
+	// The sensor mode (width:height:bit-depth:packing)
+	Mode string `json:"mode"`
+	// Fixed exposure time, 0 is automatic
+	Shutter time.Duration `json:"shutter"`
+	// Analog gain, 0 is automatic
+	Gain float64 `json:"gain"`
+	// Exposure compensation in stops
+	EV float64 `json:"ev"`
+	// White balance mode (auto, daylight, cloudy, indoor...)
+	AWB      string `json:"awb"`
+	Rotation int    `json:"rotation"`
+	// Extra command arguments
+	Args []string `json:"args"`
+}
+
+// SnapshotConfig configures polling a camera snapshot url
+type SnapshotConfig struct {
+	URL string `json:"url"`
+	// Basic or digest auth
+	Username string        `json:"username"`
+	Password string        `json:"password"`
+	Interval time.Duration `json:"interval"`
+}
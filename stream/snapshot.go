@@ -0,0 +1,99 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"gocv.io/x/gocv"
+
+	"github.com/snowzach/doods/digest"
+	"github.com/snowzach/doods/stream/sconfig"
+)
+
+const defaultSnapshotInterval = time.Second
+
+// snapshotSource polls a JPEG snapshot url
+type snapshotSource struct {
+	ctx    context.Context
+	config *sconfig.SnapshotConfig
+	client *http.Client
+	last   time.Time
+}
+
+// openSnapshot creates a snapshot polling source
+func openSnapshot(ctx context.Context, c *sconfig.SnapshotConfig) (source, error) {
+	if c.Interval <= 0 {
+		c.Interval = defaultSnapshotInterval
+	}
+	if _, err := url.Parse(c.URL); err != nil {
+		return nil, fmt.Errorf("invalid snapshot url: %v", err)
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	if c.Username != "" {
+		client.Transport = digest.NewTransport(c.Username, c.Password)
+	}
+	return &snapshotSource{
+		ctx:    ctx,
+		config: c,
+		client: client,
+	}, nil
+}
+
+// Read waits for the next interval and fetches a snapshot
+func (s *snapshotSource) Read(m *gocv.Mat) bool {
+
+	if wait := s.config.Interval - time.Since(s.last); wait > 0 {
+		select {
+		case <-s.ctx.Done():
+			return false
+		case <-time.After(wait):
+		}
+	}
+	s.last = time.Now()
+
+	data, err := s.fetch()
+	if err != nil {
+		return false
+	}
+
+	img, err := gocv.IMDecode(data, gocv.IMReadColor)
+	if err != nil {
+		return false
+	}
+	defer img.Close()
+	img.CopyTo(m)
+
+	return true
+
+}
+
+// Close does nothing, each snapshot is a separate request
+func (s *snapshotSource) Close() error {
+	return nil
+}
+
+// fetch gets the snapshot
+func (s *snapshotSource) fetch() ([]byte, error) {
+
+	request, err := http.NewRequest(http.MethodGet, s.config.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.client.Do(request.WithContext(s.ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("snapshot request failed: %s", response.Status)
+	}
+
+	return ioutil.ReadAll(response.Body)
+
+}
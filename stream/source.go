@@ -0,0 +1,144 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"gocv.io/x/gocv"
+
+	"github.com/snowzach/doods/stream/sconfig"
+)
+
+// source is where a stream reads frames from
+type source interface {
+	Read(m *gocv.Mat) bool
+	Close() error
+}
+
+// hasSource returns true if the config has a source to read frames from
+func hasSource(c *sconfig.StreamConfig) bool {
+	return c.URL != "" ||
+		(c.ONVIF != nil && c.ONVIF.Address != "") ||
+		(c.Device != nil && c.Device.Path != "") ||
+		c.Libcamera != nil ||
+		(c.Snapshot != nil && c.Snapshot.URL != "")
+}
+
+// openSource opens the configured source for the stream
+func (s *Stream) openSource(ctx context.Context) (source, error) {
+
+	if s.config.Snapshot != nil {
+		return openSnapshot(ctx, s.config.Snapshot)
+	}
+	if s.config.Libcamera != nil {
+		return openLibcamera(ctx, s.config.Libcamera)
+	}
+	if s.config.Device != nil {
+		return openDevice(s.config.Device.Path, s.config.Device.Width, s.config.Device.Height, s.config.Device.FPS, s.config.Device.Format)
+	}
+
+	url := s.config.URL
+	if url == "" {
+		var err error
+		if url, err = s.onvif.StreamURI(ctx, s.config.ONVIF.Profile); err != nil {
+			return nil, fmt.Errorf("could not get onvif stream url: %v", err)
+		}
+	}
+
+	if s.config.HWDecode != nil {
+		return openHWDecode(ctx, url, s.config.HWDecode)
+	}
+
+	capture, err := gocv.OpenVideoCapture(url)
+	if err != nil {
+		return nil, fmt.Errorf("could not open stream: %v", err)
+	}
+	return capture, nil
+
+}
+
+// openDevice opens a local V4L2 device (/dev/video0 or just 0) and sets the capture format
+func openDevice(path string, width int, height int, fps float64, format string) (source, error) {
+
+	capture, err := gocv.OpenVideoCapture(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open device %s: %v", path, err)
+	}
+
+	// The format needs to be set before the size for most devices
+	if len(format) == 4 {
+		f := strings.ToUpper(format)
+		capture.Set(gocv.VideoCaptureFOURCC, float64(uint32(f[0])|uint32(f[1])<<8|uint32(f[2])<<16|uint32(f[3])<<24))
+	}
+	if width > 0 && height > 0 {
+		capture.Set(gocv.VideoCaptureFrameWidth, float64(width))
+		capture.Set(gocv.VideoCaptureFrameHeight, float64(height))
+	}
+	if fps > 0 {
+		capture.Set(gocv.VideoCaptureFPS, fps)
+	}
+
+	return capture, nil
+
+}
+
+// processSource decodes images read from the output of a command
+type processSource struct {
+	cmd    *exec.Cmd
+	stdout io.ReadCloser
+	reader *bufio.Reader
+	next   func(r *bufio.Reader) ([]byte, error)
+}
+
+// startProcess starts the command, next reads a single encoded image from the output
+func startProcess(ctx context.Context, command string, args []string, next func(r *bufio.Reader) ([]byte, error)) (source, error) {
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not start %s: %v", command, err)
+	}
+
+	return &processSource{
+		cmd:    cmd,
+		stdout: stdout,
+		reader: bufio.NewReaderSize(stdout, 1<<20),
+		next:   next,
+	}, nil
+
+}
+
+// Read decodes the next image from the output
+func (p *processSource) Read(m *gocv.Mat) bool {
+
+	data, err := p.next(p.reader)
+	if err != nil {
+		return false
+	}
+
+	img, err := gocv.IMDecode(data, gocv.IMReadColor)
+	if err != nil {
+		return false
+	}
+	defer img.Close()
+	img.CopyTo(m)
+
+	return true
+
+}
+
+// Close stops the command
+func (p *processSource) Close() error {
+	p.stdout.Close()
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+	return p.cmd.Wait()
+}
@@ -0,0 +1,351 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"gocv.io/x/gocv"
+
+	"github.com/snowzach/doods/detector/annotate"
+	"github.com/snowzach/doods/odrpc"
+	"github.com/snowzach/doods/onvif"
+	"github.com/snowzach/doods/sink"
+	"github.com/snowzach/doods/stream/sconfig"
+	"github.com/snowzach/doods/zone"
+)
+
+const (
+	defaultFPS      = 1.0
+	defaultLiveFPS  = 10.0
+	defaultHold     = 10 * time.Second
+	defaultCooldown = 30 * time.Second
+	reconnectDelay  = 5 * time.Second
+)
+
+// Detector runs detections for a stream
+type Detector interface {
+	Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error)
+}
+
+// Stream reads frames from a camera and runs detections on them
+type Stream struct {
+	config   *sconfig.StreamConfig
+	detector Detector
+	zones    *zone.Store
+	sinks    *sink.Manager
+	clip     *recorder
+	heatmap  *heatmap
+	onvif    *onvif.Client
+	logger   *zap.SugaredLogger
+
+	// The last time there were detections (unix nanoseconds)
+	lastActive int64
+
+	// Detect until this time (unix nanoseconds) when gated by camera motion
+	motionUntil int64
+
+	// The last detection response
+	response     *odrpc.DetectResponse
+	responseLock sync.RWMutex
+	detecting    int32
+
+	// Live output subscribers
+	subscribers map[chan []byte]struct{}
+	subLock     sync.Mutex
+}
+
+// newStream creates a stream from the config
+func newStream(c *sconfig.StreamConfig, detector Detector, zones *zone.Store, sinks *sink.Manager) (*Stream, error) {
+
+	if c.Name == "" {
+		return nil, fmt.Errorf("stream name is required")
+	}
+	if !hasSource(c) {
+		return nil, fmt.Errorf("stream url, onvif address, device, libcamera or snapshot url is required")
+	}
+	if c.FPS <= 0 {
+		c.FPS = defaultFPS
+	}
+	if c.LiveFPS <= 0 {
+		c.LiveFPS = defaultLiveFPS
+	}
+	if c.Adaptive != nil {
+		if c.Adaptive.MinFPS <= 0 {
+			c.Adaptive.MinFPS = c.FPS
+		}
+		if c.Adaptive.MaxFPS < c.Adaptive.MinFPS {
+			return nil, fmt.Errorf("adaptive max_fps must be greater than min_fps")
+		}
+		if c.Adaptive.Cooldown <= 0 {
+			c.Adaptive.Cooldown = defaultCooldown
+		}
+	}
+
+	s := &Stream{
+		config:      c,
+		detector:    detector,
+		zones:       zones,
+		sinks:       sinks,
+		logger:      zap.S().With("package", "stream", "name", c.Name),
+		subscribers: make(map[chan []byte]struct{}),
+	}
+
+	if c.HWDecode != nil {
+		if _, err := hwDecodeArgs(c.URL, c.HWDecode); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.ONVIF != nil && c.ONVIF.Address != "" {
+		s.onvif = onvif.NewClient(c.ONVIF.Address, c.ONVIF.Username, c.ONVIF.Password)
+		if c.ONVIF.MotionHold <= 0 {
+			c.ONVIF.MotionHold = defaultHold
+		}
+	}
+
+	if c.Heatmap != nil {
+		s.heatmap = newHeatmap(c.Heatmap)
+	}
+
+	if c.Clip != nil {
+		var err error
+		if s.clip, err = newRecorder(c.Name, c.Clip, sinks, s.logger); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+
+}
+
+// Name returns the stream name
+func (s *Stream) Name() string {
+	return s.config.Name
+}
+
+// Response returns the last detection response
+func (s *Stream) Response() *odrpc.DetectResponse {
+	s.responseLock.RLock()
+	defer s.responseLock.RUnlock()
+	return s.response
+}
+
+// Run reads the stream until the context is canceled, reconnecting on errors
+func (s *Stream) Run(ctx context.Context) {
+	if s.onvif != nil && s.config.ONVIF.Motion {
+		go s.onvif.WatchMotion(ctx, s.motion, func(err error) {
+			s.logger.Errorw("ONVIF event error", "error", err)
+		})
+	}
+	for ctx.Err() == nil {
+		if err := s.read(ctx); err != nil {
+			s.logger.Errorw("Stream error", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// read opens the stream and processes frames until there is an error or the context is canceled
+func (s *Stream) read(ctx context.Context) error {
+
+	capture, err := s.openSource(ctx)
+	if err != nil {
+		return err
+	}
+	defer capture.Close()
+
+	s.logger.Infow("Stream opened")
+
+	frame := gocv.NewMat()
+	defer frame.Close()
+
+	// Finish any clip in progress if the stream fails
+	if s.clip != nil {
+		defer s.clip.finishClip()
+	}
+
+	liveInterval := time.Duration(float64(time.Second) / s.config.LiveFPS)
+	var lastDetect, lastLive time.Time
+
+	for ctx.Err() == nil {
+
+		if !capture.Read(&frame) {
+			return fmt.Errorf("could not read frame")
+		}
+		if frame.Empty() {
+			continue
+		}
+
+		now := time.Now()
+
+		// Run a detection if one is due and the last one is finished
+		if now.Sub(lastDetect) >= s.detectInterval(now) && s.motionActive(now) && atomic.CompareAndSwapInt32(&s.detecting, 0, 1) {
+			lastDetect = now
+			data, err := gocv.IMEncode(gocv.BMPFileExt, frame)
+			if err != nil {
+				atomic.StoreInt32(&s.detecting, 0)
+				return fmt.Errorf("could not encode frame: %v", err)
+			}
+			go s.detect(ctx, data)
+		}
+
+		// Record clips
+		if s.clip != nil {
+			s.clip.frame(now, frame)
+		}
+
+		// Send the live output
+		if now.Sub(lastLive) >= liveInterval && s.hasSubscribers() {
+			lastLive = now
+			s.publish(frame)
+		}
+	}
+
+	return nil
+
+}
+
+// detectInterval returns the time between detections. When adaptive, the max rate is used
+// until there have been no detections for the cooldown.
+func (s *Stream) detectInterval(now time.Time) time.Duration {
+	fps := s.config.FPS
+	if a := s.config.Adaptive; a != nil {
+		if now.Sub(time.Unix(0, atomic.LoadInt64(&s.lastActive))) < a.Cooldown {
+			fps = a.MaxFPS
+		} else {
+			fps = a.MinFPS
+		}
+	}
+	return time.Duration(float64(time.Second) / fps)
+}
+
+// motion is called when the camera reports motion starting or stopping
+func (s *Stream) motion(active bool) {
+	if active {
+		// Detect until motion stops
+		atomic.StoreInt64(&s.motionUntil, math.MaxInt64)
+		s.logger.Debugw("Camera motion started")
+	} else {
+		atomic.StoreInt64(&s.motionUntil, time.Now().Add(s.config.ONVIF.MotionHold).UnixNano())
+		s.logger.Debugw("Camera motion stopped")
+	}
+}
+
+// motionActive returns true if detection is not gated by camera motion or there is motion
+func (s *Stream) motionActive(now time.Time) bool {
+	if s.onvif == nil || !s.config.ONVIF.Motion {
+		return true
+	}
+	return now.UnixNano() < atomic.LoadInt64(&s.motionUntil)
+}
+
+// detect runs a detection on the frame data and saves the response
+func (s *Stream) detect(ctx context.Context, data []byte) {
+
+	defer atomic.StoreInt32(&s.detecting, 0)
+
+	request := &odrpc.DetectRequest{
+		Id:           s.config.Name,
+		DetectorName: s.config.Detector,
+		Data:         data,
+		Detect:       s.config.Detect,
+		Regions:      append([]*odrpc.DetectRegion{}, s.config.Regions...),
+		Preprocess:   s.config.Preprocess,
+		Rotate:       s.config.Rotate,
+		Flip:         s.config.Flip,
+		Smooth:       s.config.Smooth,
+		Confirm:      s.config.Confirm,
+	}
+
+	// Add the regions for this stream, the detector drops the detections in its masks before the sinks get them
+	zones := s.zones.Get(zone.Target(zone.TargetStreams, s.config.Name))
+	zones.Apply(request)
+
+	response, err := s.detector.Detect(zone.WithZones(sink.WithSource(ctx, s.config.Name), zones), request)
+	if err != nil {
+		s.logger.Errorw("Detect error", "error", err)
+		return
+	}
+
+	if len(response.Detections) > 0 {
+		atomic.StoreInt64(&s.lastActive, time.Now().UnixNano())
+	}
+
+	s.heatmap.add(time.Now(), data, response.Detections)
+
+	// Start or extend a clip
+	if s.clip != nil && len(response.Detections) > 0 {
+		s.clip.trigger(&sink.Event{
+			Time:     time.Now(),
+			ID:       request.Id,
+			Source:   s.config.Name,
+			Detector: s.config.Detector,
+			Image:    data,
+			Response: response,
+			Private:  sink.Private(response.Detections),
+		})
+	}
+
+	s.responseLock.Lock()
+	s.response = response
+	s.responseLock.Unlock()
+
+}
+
+// publish draws the last detections on the frame and sends it to the live subscribers
+func (s *Stream) publish(frame gocv.Mat) {
+
+	img := frame.Clone()
+	defer img.Close()
+
+	if response := s.Response(); response != nil {
+		annotate.Draw(&img, response.Detections)
+	}
+
+	data, err := gocv.IMEncode(gocv.JPEGFileExt, img)
+	if err != nil {
+		s.logger.Errorw("Could not encode live frame", "error", err)
+		return
+	}
+
+	s.subLock.Lock()
+	for sub := range s.subscribers {
+		// Drop the frame if the subscriber is behind
+		select {
+		case sub <- data:
+		default:
+		}
+	}
+	s.subLock.Unlock()
+
+}
+
+// Subscribe returns a channel that receives annotated JPEG frames
+func (s *Stream) Subscribe() chan []byte {
+	sub := make(chan []byte, 1)
+	s.subLock.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.subLock.Unlock()
+	return sub
+}
+
+// Unsubscribe stops sending frames to the channel
+func (s *Stream) Unsubscribe(sub chan []byte) {
+	s.subLock.Lock()
+	delete(s.subscribers, sub)
+	s.subLock.Unlock()
+}
+
+func (s *Stream) hasSubscribers() bool {
+	s.subLock.Lock()
+	defer s.subLock.Unlock()
+	return len(s.subscribers) > 0
+}
@@ -0,0 +1,226 @@
+package ui
+
+// indexHTML is the single page web interface. It only uses the public API.
+const indexHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>DOODS</title>
+<style>
+  body { font-family: sans-serif; margin: 0; background: #f4f4f4; color: #222; }
+  header { background: #263238; color: #fff; padding: 8px 16px; display: flex; align-items: center; gap: 16px; }
+  header h1 { font-size: 20px; margin: 0; flex: 1; }
+  nav button { background: none; border: none; color: #cfd8dc; font-size: 15px; cursor: pointer; padding: 6px 10px; }
+  nav button.active { color: #fff; border-bottom: 2px solid #fff; }
+  main { padding: 16px; }
+  section { display: none; }
+  section.active { display: block; }
+  .card { background: #fff; border-radius: 4px; padding: 12px; margin-bottom: 12px; box-shadow: 0 1px 2px rgba(0,0,0,.2); }
+  .stage { position: relative; display: inline-block; max-width: 100%; }
+  .stage img, .stage canvas { max-width: 100%; display: block; }
+  .stage canvas { position: absolute; top: 0; left: 0; }
+  table { border-collapse: collapse; }
+  td, th { padding: 4px 8px; text-align: left; border-bottom: 1px solid #eee; }
+  pre { background: #263238; color: #eceff1; padding: 8px; overflow: auto; }
+  label { margin-right: 12px; }
+  .error { color: #c62828; }
+</style>
+</head>
+<body>
+<header>
+  <h1>DOODS</h1>
+  <nav>
+    <button data-tab="detectors" class="active">Detectors</button>
+    <button data-tab="test">Test</button>
+    <button data-tab="streams">Streams</button>
+    <button data-tab="zones">Zones</button>
+  </nav>
+  <input id="authKey" type="password" placeholder="Auth Key" size="12">
+</header>
+<main>
+  <div id="error" class="error"></div>
+
+  <section id="detectors" class="active"></section>
+
+  <section id="test">
+    <div class="card">
+      <label>Detector <select id="testDetector"></select></label>
+      <label>Image <input id="testFile" type="file" accept="image/*"></label>
+      <label>Min Confidence <input id="testThreshold" type="range" min="0" max="100" value="50"> <span id="testThresholdValue">50</span>%</label>
+    </div>
+    <div class="card">
+      <div class="stage"><img id="testImage"><canvas id="testCanvas"></canvas></div>
+      <table id="testResults"></table>
+    </div>
+  </section>
+
+  <section id="streams"></section>
+
+  <section id="zones">
+    <div class="card">
+      <label>Image <input id="zoneFile" type="file" accept="image/*"></label>
+      <label>Stream <select id="zoneStream"><option value="">-</option></select></label>
+      <label>Covers <input id="zoneCovers" type="checkbox"></label>
+      <button id="zoneClear">Clear</button>
+      <p>Drag on the image to draw detection regions.</p>
+    </div>
+    <div class="card">
+      <div class="stage"><img id="zoneImage"><canvas id="zoneCanvas"></canvas></div>
+      <pre id="zoneConfig"></pre>
+    </div>
+  </section>
+</main>
+<script>
+(function() {
+  var $ = function(id) { return document.getElementById(id); };
+  var authKey = $("authKey");
+  authKey.value = localStorage.getItem("doods-auth-key") || "";
+  authKey.onchange = function() { localStorage.setItem("doods-auth-key", authKey.value); load(); };
+
+  function api(method, path, body) {
+    var headers = { "Content-Type": "application/json" };
+    if (authKey.value) headers["doods-auth-key"] = authKey.value;
+    return fetch(path, { method: method, headers: headers, body: body ? JSON.stringify(body) : undefined }).then(function(r) {
+      if (!r.ok) return r.text().then(function(t) { throw new Error(r.status + " " + t); });
+      return r.json();
+    });
+  }
+  function showError(err) { $("error").textContent = err ? err.message || err : ""; }
+  function withKey(path) { return authKey.value ? path + (path.indexOf("?") < 0 ? "?" : "&") + "auth_key=" + encodeURIComponent(authKey.value) : path; }
+  function esc(s) { var d = document.createElement("div"); d.textContent = s; return d.innerHTML; }
+
+  // Tabs
+  document.querySelectorAll("nav button").forEach(function(b) {
+    b.onclick = function() {
+      document.querySelectorAll("nav button, section").forEach(function(e) { e.classList.remove("active"); });
+      b.classList.add("active");
+      $(b.dataset.tab).classList.add("active");
+    };
+  });
+
+  // Detectors
+  var detectors = [];
+  function loadDetectors() {
+    return api("GET", "/detectors").then(function(resp) {
+      detectors = resp.detectors || [];
+      var html = "", options = "";
+      detectors.forEach(function(d) {
+        html += "<div class=card><h3>" + esc(d.name) + "</h3><table>" +
+          "<tr><th>Type</th><td>" + esc(d.type) + "</td></tr>" +
+          "<tr><th>Model</th><td>" + esc(d.model) + "</td></tr>" +
+          "<tr><th>Size</th><td>" + d.width + "x" + d.height + "</td></tr>" +
+          "<tr><th>Labels</th><td>" + esc((d.labels || []).join(", ")) + "</td></tr></table>" +
+          "<h4>Last Detection</h4><img style='max-width:480px' src='" + withKey("/detectors/" + encodeURIComponent(d.name) + "/last.jpg?width=480") + "' onerror=\"this.replaceWith('None')\"></div>";
+        options += "<option>" + esc(d.name) + "</option>";
+      });
+      $("detectors").innerHTML = html;
+      $("testDetector").innerHTML = options;
+    });
+  }
+
+  // Test detection
+  var testResponse = null;
+  function drawDetections(img, canvas, detections, min) {
+    canvas.width = img.clientWidth; canvas.height = img.clientHeight;
+    var ctx = canvas.getContext("2d");
+    ctx.clearRect(0, 0, canvas.width, canvas.height);
+    ctx.lineWidth = 2; ctx.font = "14px sans-serif";
+    (detections || []).forEach(function(d) {
+      if (d.confidence < min) return;
+      var x = d.left * canvas.width, y = d.top * canvas.height;
+      ctx.strokeStyle = "#00e676"; ctx.fillStyle = "#00e676";
+      ctx.strokeRect(x, y, (d.right - d.left) * canvas.width, (d.bottom - d.top) * canvas.height);
+      var text = d.label + " " + d.confidence.toFixed(0) + "%";
+      ctx.fillRect(x, y, ctx.measureText(text).width + 6, 18);
+      ctx.fillStyle = "#000"; ctx.fillText(text, x + 3, y + 14);
+    });
+  }
+  function renderTest() {
+    var min = +$("testThreshold").value;
+    $("testThresholdValue").textContent = min;
+    if (!testResponse) return;
+    drawDetections($("testImage"), $("testCanvas"), testResponse.detections, min);
+    var rows = "<tr><th>Label</th><th>Confidence</th><th>Box</th></tr>";
+    (testResponse.detections || []).filter(function(d) { return d.confidence >= min; }).forEach(function(d) {
+      rows += "<tr><td>" + esc(d.label) + "</td><td>" + d.confidence.toFixed(1) + "</td><td>" +
+        [d.top, d.left, d.bottom, d.right].map(function(v) { return v.toFixed(3); }).join(", ") + "</td></tr>";
+    });
+    $("testResults").innerHTML = rows;
+  }
+  $("testThreshold").oninput = renderTest;
+  $("testFile").onchange = function() {
+    var file = this.files[0];
+    if (!file) return;
+    var reader = new FileReader();
+    reader.onload = function() {
+      $("testImage").src = reader.result;
+      testResponse = null;
+      api("POST", "/detect", {
+        detector_name: $("testDetector").value,
+        data: reader.result.split(",")[1],
+        detect: { "*": 0 }
+      }).then(function(resp) { testResponse = resp; showError(); renderTest(); }).catch(showError);
+    };
+    reader.readAsDataURL(file);
+  };
+
+  // Streams
+  function loadStreams() {
+    return api("GET", "/streams").then(function(resp) {
+      var html = "", options = "<option value=''>-</option>";
+      (resp.streams || []).forEach(function(s) {
+        html += "<div class=card><h3>" + esc(s.name) + " <small>(" + esc(s.detector) + ")</small></h3>" +
+          "<img style='max-width:100%' src='" + withKey("/stream/" + encodeURIComponent(s.name) + "/live") + "'></div>";
+        options += "<option>" + esc(s.name) + "</option>";
+      });
+      $("streams").innerHTML = html || "<div class=card>No streams configured</div>";
+      $("zoneStream").innerHTML = options;
+    });
+  }
+
+  // Zones
+  var regions = [], drawing = null;
+  function zonePoint(e) {
+    var r = $("zoneCanvas").getBoundingClientRect();
+    return { x: Math.min(Math.max((e.clientX - r.left) / r.width, 0), 1), y: Math.min(Math.max((e.clientY - r.top) / r.height, 0), 1) };
+  }
+  function renderZones() {
+    var img = $("zoneImage"), canvas = $("zoneCanvas");
+    canvas.width = img.clientWidth; canvas.height = img.clientHeight;
+    var ctx = canvas.getContext("2d");
+    ctx.lineWidth = 2;
+    regions.concat(drawing ? [drawing] : []).forEach(function(r) {
+      ctx.strokeStyle = "#ff9100"; ctx.fillStyle = "rgba(255,145,0,.2)";
+      var x = r.left * canvas.width, y = r.top * canvas.height, w = (r.right - r.left) * canvas.width, h = (r.bottom - r.top) * canvas.height;
+      ctx.fillRect(x, y, w, h); ctx.strokeRect(x, y, w, h);
+    });
+    $("zoneConfig").textContent = JSON.stringify({ regions: regions }, null, 2);
+  }
+  function toRegion(a, b) {
+    return { top: +Math.min(a.y, b.y).toFixed(3), left: +Math.min(a.x, b.x).toFixed(3), bottom: +Math.max(a.y, b.y).toFixed(3), right: +Math.max(a.x, b.x).toFixed(3),
+      detect: { "*": +$("testThreshold").value }, covers: $("zoneCovers").checked };
+  }
+  var start = null;
+  $("zoneCanvas").onmousedown = function(e) { start = zonePoint(e); };
+  $("zoneCanvas").onmousemove = function(e) { if (start) { drawing = toRegion(start, zonePoint(e)); renderZones(); } };
+  $("zoneCanvas").onmouseup = function(e) {
+    if (start) { var r = toRegion(start, zonePoint(e)); if (r.right > r.left && r.bottom > r.top) regions.push(r); }
+    start = null; drawing = null; renderZones();
+  };
+  $("zoneClear").onclick = function() { regions = []; renderZones(); };
+  $("zoneImage").onload = renderZones;
+  $("zoneFile").onchange = function() { if (this.files[0]) $("zoneImage").src = URL.createObjectURL(this.files[0]); };
+  $("zoneStream").onchange = function() { if (this.value) $("zoneImage").src = withKey("/stream/" + encodeURIComponent(this.value) + "/live"); };
+
+  function load() {
+    showError();
+    loadDetectors().catch(showError);
+    loadStreams().catch(function() {});
+  }
+  load();
+})();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,19 @@
+// Package ui serves the built in web interface
+package ui
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The time the binary was started is used for caching the page
+var started = time.Now()
+
+// Handler returns the handler for the web interface
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		http.ServeContent(w, r, "index.html", started, strings.NewReader(indexHTML))
+	})
+}
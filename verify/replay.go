@@ -0,0 +1,249 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The files of a request saved to doods.debug.dir and its expected result
+const (
+	RequestFile  = "request.json"
+	ResponseFile = "response.json"
+	ExpectedFile = "expected.json"
+	ManifestFile = "manifest.json"
+)
+
+// Case is a request to replay and the detections it should return
+type Case struct {
+	Name     string
+	Request  *odrpc.DetectRequest
+	Expected []*odrpc.Detection
+	// Saves the new expected detections
+	save func([]*odrpc.Detection) error
+}
+
+// ManifestEntry is an image in a manifest and its expected detections
+type ManifestEntry struct {
+	Image    string `json:"image"`
+	Detector string `json:"detector,omitempty"`
+	// Options for the detect request like rotate or preprocess
+	Request  *odrpc.DetectRequest `json:"request,omitempty"`
+	Expected []*odrpc.Detection   `json:"expected"`
+}
+
+// LoadCases loads the requests to replay from a request saved to doods.debug.dir, the debug dir itself, a directory
+// with a manifest.json or a manifest file. The expected detections are the ones in expected.json or the saved
+// response for debug requests.
+func LoadCases(path string) ([]*Case, error) {
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return loadManifest(path)
+	}
+	if _, err := os.Stat(filepath.Join(path, ManifestFile)); err == nil {
+		return loadManifest(filepath.Join(path, ManifestFile))
+	}
+	if _, err := os.Stat(filepath.Join(path, RequestFile)); err == nil {
+		c, err := loadDebugCase(path)
+		if err != nil {
+			return nil, err
+		}
+		return []*Case{c}, nil
+	}
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var cases []*Case
+	for _, f := range files {
+		dir := filepath.Join(path, f.Name())
+		if _, err := os.Stat(filepath.Join(dir, RequestFile)); !f.IsDir() || err != nil {
+			continue
+		}
+		c, err := loadDebugCase(dir)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no saved requests or %s in %s", ManifestFile, path)
+	}
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Name < cases[j].Name })
+	return cases, nil
+
+}
+
+// loadDebugCase loads a request saved to doods.debug.dir
+func loadDebugCase(dir string) (*Case, error) {
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, RequestFile))
+	if err != nil {
+		return nil, err
+	}
+	var saved struct {
+		Request *odrpc.DetectRequest `json:"request"`
+	}
+	if err = json.Unmarshal(data, &saved); err != nil || saved.Request == nil {
+		return nil, fmt.Errorf("could not parse %s: %v", filepath.Join(dir, RequestFile), err)
+	}
+
+	// The image as it was received, the request does all of the same steps again
+	originals, _ := filepath.Glob(filepath.Join(dir, "original.*"))
+	if len(originals) == 0 {
+		return nil, fmt.Errorf("no original image in %s", dir)
+	}
+	request := saved.Request
+	if request.Data, err = ioutil.ReadFile(originals[0]); err != nil {
+		return nil, err
+	}
+
+	c := &Case{
+		Name:    filepath.Base(dir),
+		Request: request,
+		save: func(detections []*odrpc.Detection) error {
+			return saveGolden(filepath.Join(dir, ExpectedFile), &Golden{Detector: request.DetectorName, Detections: detections})
+		},
+	}
+	if golden, err := loadGolden(filepath.Join(dir, ExpectedFile)); err == nil {
+		c.Expected = golden.Detections
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not load %s: %v", filepath.Join(dir, ExpectedFile), err)
+	} else if data, err := ioutil.ReadFile(filepath.Join(dir, ResponseFile)); err == nil {
+		response := new(odrpc.DetectResponse)
+		if err = json.Unmarshal(data, response); err != nil {
+			return nil, fmt.Errorf("could not parse %s: %v", filepath.Join(dir, ResponseFile), err)
+		}
+		c.Expected = response.Detections
+		if c.Expected == nil {
+			c.Expected = []*odrpc.Detection{}
+		}
+	}
+	return c, nil
+
+}
+
+// loadManifest loads the images in a manifest, the image paths are relative to the manifest
+func loadManifest(filename string) ([]*Case, error) {
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*ManifestEntry
+	if err = json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", filename, err)
+	}
+
+	// The manifest is written again with each new expected result
+	save := func() error {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filename, data, 0644)
+	}
+
+	cases := make([]*Case, 0, len(entries))
+	for i, e := range entries {
+		// A copy so the image isn't written to the manifest
+		request := new(odrpc.DetectRequest)
+		if e.Request != nil {
+			*request = *e.Request
+		}
+		if e.Detector != "" {
+			request.DetectorName = e.Detector
+		}
+		image := e.Image
+		if !filepath.IsAbs(image) {
+			image = filepath.Join(filepath.Dir(filename), image)
+		}
+		if request.Data, err = ioutil.ReadFile(image); err != nil {
+			return nil, err
+		}
+		if request.Id == "" {
+			request.Id = strings.TrimSuffix(filepath.Base(e.Image), filepath.Ext(e.Image))
+		}
+		entry := e
+		cases = append(cases, &Case{
+			Name:     fmt.Sprintf("%d:%s", i+1, e.Image),
+			Request:  request,
+			Expected: e.Expected,
+			save: func(detections []*odrpc.Detection) error {
+				entry.Expected = detections
+				return save()
+			},
+		})
+	}
+	return cases, nil
+
+}
+
+// Replay runs the cases and compares the detections with the expected detections. The detector in the options is
+// used instead of the detector of each request if it's set. Cases without expected detections are recorded like with
+// Update.
+func Replay(ctx context.Context, d Detector, opts *Options, cases []*Case) ([]*Result, error) {
+
+	results := make([]*Result, 0, len(cases))
+	for _, c := range cases {
+		results = append(results, replayCase(ctx, d, opts, c))
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+	}
+	return results, nil
+
+}
+
+// replayCase runs a case and compares it
+func replayCase(ctx context.Context, d Detector, opts *Options, c *Case) *Result {
+
+	result := &Result{Image: c.Name}
+
+	request := *c.Request
+	if opts.Detector != "" {
+		request.DetectorName = opts.Detector
+	}
+	// The image is in the request and smoothing and confirming depend on the frames before it
+	request.File, request.ImageUrl = "", ""
+	request.Smooth, request.Confirm = nil, nil
+	request.Debug = false
+
+	response, err := d.Detect(ctx, &request)
+	if err == nil && response.Error != "" {
+		err = fmt.Errorf("%s", response.Error)
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("could not detect: %v", err)
+		return result
+	}
+
+	if opts.Update || c.Expected == nil {
+		if c.save != nil {
+			if err = c.save(response.Detections); err != nil {
+				result.Error = fmt.Sprintf("could not save expected result: %v", err)
+				return result
+			}
+		}
+		result.Updated = true
+		result.Matched = len(response.Detections)
+		return result
+	}
+
+	compare(opts, result, c.Expected, response.Detections)
+
+	return result
+
+}
@@ -0,0 +1,207 @@
+// Package verify runs reference images through a detector and compares the detections with stored golden results
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// GoldenSuffix is added to the image filename for its golden result
+const GoldenSuffix = ".golden.json"
+
+// The image files that are verified
+var imageExtensions = map[string]struct{}{".jpg": {}, ".jpeg": {}, ".png": {}, ".gif": {}, ".bmp": {}, ".ppm": {}, ".webp": {}}
+
+// Detector runs the detections
+type Detector interface {
+	Detect(ctx context.Context, request *odrpc.DetectRequest) (*odrpc.DetectResponse, error)
+}
+
+// Options are the images, detector and tolerances
+type Options struct {
+	// The directory with the reference images and golden results
+	Dir      string
+	Detector string
+	// Detections below this confidence are not compared
+	MinConfidence float32
+	// The minimum overlap (intersection over union) for a detection to match the golden detection
+	IOU float32
+	// The maximum confidence difference of matched detections. Golden or new detections within this of
+	// MinConfidence are not required to match.
+	Tolerance float32
+	// Write the detections as the new golden results
+	Update bool
+}
+
+// Golden is the stored result for an image
+type Golden struct {
+	Detector   string             `json:"detector"`
+	Detections []*odrpc.Detection `json:"detections"`
+}
+
+// Change is a matched detection with a confidence outside the tolerance
+type Change struct {
+	Golden *odrpc.Detection `json:"golden"`
+	Got    *odrpc.Detection `json:"got"`
+}
+
+// Result is the comparison for an image
+type Result struct {
+	Image   string             `json:"image"`
+	Updated bool               `json:"updated,omitempty"`
+	Matched int                `json:"matched"`
+	Missing []*odrpc.Detection `json:"missing,omitempty"`
+	Extra   []*odrpc.Detection `json:"extra,omitempty"`
+	Changed []*Change          `json:"changed,omitempty"`
+	Error   string             `json:"error,omitempty"`
+}
+
+// Passed returns true if the detections match the golden result
+func (r *Result) Passed() bool {
+	return r.Error == "" && len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Changed) == 0
+}
+
+// Run verifies every image in the directory. Images without a golden result are recorded like with Update.
+func Run(ctx context.Context, d Detector, opts *Options) ([]*Result, error) {
+
+	files, err := ioutil.ReadDir(opts.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", opts.Dir, err)
+	}
+
+	var images []string
+	for _, f := range files {
+		if _, ok := imageExtensions[strings.ToLower(filepath.Ext(f.Name()))]; ok && !f.IsDir() {
+			images = append(images, f.Name())
+		}
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no images in %s", opts.Dir)
+	}
+	sort.Strings(images)
+
+	results := make([]*Result, 0, len(images))
+	for _, image := range images {
+		results = append(results, verifyImage(ctx, d, opts, image))
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+	}
+
+	return results, nil
+
+}
+
+// verifyImage detects an image and compares it with its golden result
+func verifyImage(ctx context.Context, d Detector, opts *Options, image string) *Result {
+
+	result := &Result{Image: image}
+	filename := filepath.Join(opts.Dir, image)
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		result.Error = fmt.Sprintf("could not read image: %v", err)
+		return result
+	}
+
+	response, err := d.Detect(ctx, &odrpc.DetectRequest{
+		Id:           image,
+		DetectorName: opts.Detector,
+		Data:         data,
+		Detect:       map[string]float32{"*": opts.MinConfidence},
+	})
+	if err == nil && response.Error != "" {
+		err = fmt.Errorf("%s", response.Error)
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("could not detect: %v", err)
+		return result
+	}
+
+	golden, err := loadGolden(filename + GoldenSuffix)
+	if opts.Update || os.IsNotExist(err) {
+		golden = &Golden{Detector: opts.Detector, Detections: response.Detections}
+		if err = saveGolden(filename+GoldenSuffix, golden); err != nil {
+			result.Error = fmt.Sprintf("could not save golden result: %v", err)
+			return result
+		}
+		result.Updated = true
+		result.Matched = len(golden.Detections)
+		return result
+	} else if err != nil {
+		result.Error = fmt.Sprintf("could not load golden result: %v", err)
+		return result
+	}
+
+	compare(opts, result, golden.Detections, response.Detections)
+
+	return result
+
+}
+
+// compare greedily matches each golden detection with the best overlapping detection with the same label
+func compare(opts *Options, result *Result, golden, got []*odrpc.Detection) {
+
+	// Borderline detections may or may not be over the threshold
+	borderline := func(d *odrpc.Detection) bool {
+		return d.Confidence < opts.MinConfidence+opts.Tolerance
+	}
+
+	used := make([]bool, len(got))
+	for _, g := range golden {
+		best, bestIOU := -1, opts.IOU
+		for j, d := range got {
+			if used[j] || d.Label != g.Label {
+				continue
+			}
+			if overlap := odrpc.IoU(g, d); overlap >= bestIOU {
+				best, bestIOU = j, overlap
+			}
+		}
+		if best < 0 {
+			if !borderline(g) {
+				result.Missing = append(result.Missing, g)
+			}
+			continue
+		}
+		used[best] = true
+		result.Matched++
+		if diff := got[best].Confidence - g.Confidence; diff > opts.Tolerance || diff < -opts.Tolerance {
+			result.Changed = append(result.Changed, &Change{Golden: g, Got: got[best]})
+		}
+	}
+	for j, d := range got {
+		if !used[j] && !borderline(d) {
+			result.Extra = append(result.Extra, d)
+		}
+	}
+
+}
+
+func loadGolden(filename string) (*Golden, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	golden := new(Golden)
+	if err := json.Unmarshal(data, golden); err != nil {
+		return nil, err
+	}
+	return golden, nil
+}
+
+func saveGolden(filename string, golden *Golden) error {
+	data, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
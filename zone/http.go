@@ -0,0 +1,77 @@
+package zone
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/snowzach/doods/server"
+)
+
+// Target returns the store key for a detector or stream
+func Target(targetType string, name string) string {
+	return targetType + "/" + name
+}
+
+// The target types
+const (
+	TargetDetectors = "detectors"
+	TargetStreams   = "streams"
+)
+
+// RegisterHTTP registers the zone management endpoints on the router
+func (s *Store) RegisterHTTP(r chi.Router, keys *server.AuthKeys) {
+	r.Group(func(r chi.Router) {
+		r.Use(server.Auth(keys))
+		r.Get("/zones", s.handleList)
+		r.Route("/zones/{targetType:detectors|streams}/{target}", func(r chi.Router) {
+			r.Get("/", s.handleGet)
+			r.Put("/{kind:regions|masks|lines}/{name}", s.handleSet)
+			r.Delete("/{kind:regions|masks|lines}/{name}", s.handleDelete)
+		})
+	})
+}
+
+func target(r *http.Request) string {
+	return Target(chi.URLParam(r, "targetType"), chi.URLParam(r, "target"))
+}
+
+func (s *Store) handleList(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, s.All())
+}
+
+func (s *Store) handleGet(w http.ResponseWriter, r *http.Request) {
+	z := s.Get(target(r))
+	if z == nil {
+		z = newZones()
+	}
+	render.JSON(w, r, z)
+}
+
+func (s *Store) handleSet(w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	if err := s.Set(target(r), chi.URLParam(r, "kind"), chi.URLParam(r, "name"), data); err != nil {
+		render.Render(w, r, server.ErrInvalidRequest(err))
+		return
+	}
+	render.JSON(w, r, s.Get(target(r)))
+}
+
+func (s *Store) handleDelete(w http.ResponseWriter, r *http.Request) {
+	found, err := s.Delete(target(r), chi.URLParam(r, "kind"), chi.URLParam(r, "name"))
+	if err != nil {
+		render.Render(w, r, server.ErrInternal(err))
+		return
+	}
+	if !found {
+		render.Render(w, r, server.ErrNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
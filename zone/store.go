@@ -0,0 +1,185 @@
+package zone
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// Store holds the zones for each target (detector or stream) and persists them to a file
+type Store struct {
+	filename string
+	zones    map[string]*Zones
+	sync.RWMutex
+}
+
+// NewStore creates a store and loads the zones from filename. If filename is blank, zones are not persisted.
+func NewStore(filename string) (*Store, error) {
+
+	s := &Store{
+		filename: filename,
+		zones:    make(map[string]*Zones),
+	}
+
+	if filename == "" {
+		return s, nil
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read zones file %s: %v", filename, err)
+	}
+	if err := json.Unmarshal(data, &s.zones); err != nil {
+		return nil, fmt.Errorf("could not parse zones file %s: %v", filename, err)
+	}
+	for key, z := range s.zones {
+		s.zones[key] = z.clone() // Ensures the maps are not nil
+	}
+
+	return s, nil
+
+}
+
+// Get returns the zones for a target. The returned zones must not be modified.
+func (s *Store) Get(target string) *Zones {
+	if s == nil {
+		return nil
+	}
+	s.RLock()
+	defer s.RUnlock()
+	return s.zones[target]
+}
+
+// All returns all of the zones by target
+func (s *Store) All() map[string]*Zones {
+	s.RLock()
+	defer s.RUnlock()
+	ret := make(map[string]*Zones, len(s.zones))
+	for k, v := range s.zones {
+		ret[k] = v
+	}
+	return ret
+}
+
+// Set creates or replaces a zone. The data is the JSON for the kind of zone.
+func (s *Store) Set(target string, kind string, name string, data []byte) error {
+
+	s.Lock()
+	defer s.Unlock()
+
+	z := newZones()
+	if existing, ok := s.zones[target]; ok {
+		z = existing.clone()
+	}
+
+	switch kind {
+	case KindRegions:
+		var region odrpc.DetectRegion
+		if err := json.Unmarshal(data, &region); err != nil {
+			return fmt.Errorf("invalid region: %v", err)
+		}
+		if region.Top >= region.Bottom || region.Left >= region.Right {
+			return fmt.Errorf("invalid region coordinates")
+		}
+		z.Regions[name] = &region
+	case KindMasks:
+		var mask Polygon
+		if err := json.Unmarshal(data, &mask); err != nil {
+			return fmt.Errorf("invalid mask: %v", err)
+		}
+		if len(mask.Points) < 3 {
+			return fmt.Errorf("mask requires at least 3 points")
+		}
+		z.Masks[name] = &mask
+	case KindLines:
+		var line Line
+		if err := json.Unmarshal(data, &line); err != nil {
+			return fmt.Errorf("invalid line: %v", err)
+		}
+		z.Lines[name] = &line
+	default:
+		return fmt.Errorf("unknown zone kind: %s", kind)
+	}
+
+	s.zones[target] = z
+	return s.save()
+
+}
+
+// Delete removes a zone. It returns false if the zone did not exist.
+func (s *Store) Delete(target string, kind string, name string) (bool, error) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	existing, ok := s.zones[target]
+	if !ok {
+		return false, nil
+	}
+	z := existing.clone()
+
+	var found bool
+	switch kind {
+	case KindRegions:
+		_, found = z.Regions[name]
+		delete(z.Regions, name)
+	case KindMasks:
+		_, found = z.Masks[name]
+		delete(z.Masks, name)
+	case KindLines:
+		_, found = z.Lines[name]
+		delete(z.Lines, name)
+	default:
+		return false, fmt.Errorf("unknown zone kind: %s", kind)
+	}
+	if !found {
+		return false, nil
+	}
+
+	if z.Empty() {
+		delete(s.zones, target)
+	} else {
+		s.zones[target] = z
+	}
+	return true, s.save()
+
+}
+
+// save writes the zones to the file, the lock must be held
+func (s *Store) save() error {
+
+	if s.filename == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.zones, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode zones: %v", err)
+	}
+
+	// Write to a temp file and rename so the file is never partially written
+	tmp, err := ioutil.TempFile(filepath.Dir(s.filename), ".zones")
+	if err != nil {
+		return fmt.Errorf("could not save zones: %v", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not save zones: %v", err)
+	}
+	tmp.Close()
+	if err := os.Rename(tmp.Name(), s.filename); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not save zones: %v", err)
+	}
+
+	return nil
+
+}
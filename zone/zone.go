@@ -0,0 +1,131 @@
+// Package zone manages the regions, masks and lines for detectors and streams
+package zone
+
+import (
+	"context"
+
+	"github.com/snowzach/doods/odrpc"
+)
+
+// The kinds of zones
+const (
+	KindRegions = "regions"
+	KindMasks   = "masks"
+	KindLines   = "lines"
+)
+
+// Point is a relative (0-1) coordinate
+type Point struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+// Polygon is a masked area. Detections with their center inside are dropped.
+type Polygon struct {
+	Points []Point `json:"points"`
+}
+
+// Line is a line across the frame, used to detect objects crossing it
+type Line struct {
+	Start  Point              `json:"start"`
+	End    Point              `json:"end"`
+	Detect map[string]float32 `json:"detect"`
+}
+
+// Zones are the named zones for a detector or stream
+type Zones struct {
+	Regions map[string]*odrpc.DetectRegion `json:"regions"`
+	Masks   map[string]*Polygon            `json:"masks"`
+	Lines   map[string]*Line               `json:"lines"`
+}
+
+type zonesKey struct{}
+
+// WithZones adds the zones of the caller, like a stream, to the context so the detector also applies their masks
+func WithZones(ctx context.Context, z *Zones) context.Context {
+	return context.WithValue(ctx, zonesKey{}, z)
+}
+
+// FromContext returns the zones set with WithZones or nil
+func FromContext(ctx context.Context) *Zones {
+	z, _ := ctx.Value(zonesKey{}).(*Zones)
+	return z
+}
+
+func newZones() *Zones {
+	return &Zones{
+		Regions: make(map[string]*odrpc.DetectRegion),
+		Masks:   make(map[string]*Polygon),
+		Lines:   make(map[string]*Line),
+	}
+}
+
+// clone makes a shallow copy of the zones so it can be modified without affecting readers
+func (z *Zones) clone() *Zones {
+	c := newZones()
+	for k, v := range z.Regions {
+		c.Regions[k] = v
+	}
+	for k, v := range z.Masks {
+		c.Masks[k] = v
+	}
+	for k, v := range z.Lines {
+		c.Lines[k] = v
+	}
+	return c
+}
+
+// Empty returns true if there are no zones
+func (z *Zones) Empty() bool {
+	return z == nil || (len(z.Regions) == 0 && len(z.Masks) == 0 && len(z.Lines) == 0)
+}
+
+// Apply adds the regions to the request
+func (z *Zones) Apply(request *odrpc.DetectRequest) {
+	if z == nil {
+		return
+	}
+	for _, region := range z.Regions {
+		request.Regions = append(request.Regions, region)
+	}
+}
+
+// Masked returns true if the point is in any of the masks
+func (z *Zones) Masked(x, y float32) bool {
+	if z == nil {
+		return false
+	}
+	for _, mask := range z.Masks {
+		if mask.Contains(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterResponse removes any detections with a center in a mask
+func (z *Zones) FilterResponse(response *odrpc.DetectResponse) {
+	if z == nil || len(z.Masks) == 0 {
+		return
+	}
+	temp := response.Detections[:0]
+	for _, detection := range response.Detections {
+		if z.Masked((detection.Left+detection.Right)/2, (detection.Top+detection.Bottom)/2) {
+			continue
+		}
+		temp = append(temp, detection)
+	}
+	response.Detections = temp
+}
+
+// Contains returns true if the point is inside the polygon
+func (p *Polygon) Contains(x, y float32) bool {
+	var inside bool
+	for i, j := 0, len(p.Points)-1; i < len(p.Points); j, i = i, i+1 {
+		pi, pj := p.Points[i], p.Points[j]
+		if (pi.Y > y) != (pj.Y > y) && x < (pj.X-pi.X)*(y-pi.Y)/(pj.Y-pi.Y)+pi.X {
+			inside = !inside
+		}
+	}
+	return inside
+}